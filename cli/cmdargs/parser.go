@@ -39,6 +39,14 @@ const (
    special 'filebytes' argument type for this with a filepath specified after
    the colon, e.g. 'filebytes:my_file.txt'.
 
+   Complex parameters (including maps and arrays of any depth) can be passed as
+   JSON documents using a special 'json' argument type. JSON objects with a
+   'type' field are parameters in the regular {"type": ..., "value": ...}
+   format, other objects are converted to maps with string keys, arrays are
+   converted to arrays, strings with 'hex:' or 'base64:' prefix are decoded to
+   byte arrays, other strings are strings, integer numbers are integers,
+   booleans are bools and null is a Null stackitem.
+
    Given values are type-checked against given types with the following
    restrictions applied:
     * 'signature' type values should be hex-encoded and have a (decoded)
@@ -95,7 +103,9 @@ const (
     * '[ a b c ]' is an array with strings values 'a', 'b' and 'c'
     * '[ a b [ c d ] e ]' is an array with 4 values: string 'a', string 'b',
       array of two strings 'c' and 'd', string 'e'
-    * '[ ]' is an empty array`
+    * '[ ]' is an empty array
+    * 'json:{"a": [1, "hex:01"]}' is a map with a single key 'a' and an array
+      of integer 1 and byte array '01' as a value`
 
 	// SignersParsingDoc is a documentation for signers parsing.
 	SignersParsingDoc = `   Signers represent a set of Uint160 hashes with witness scopes and are used
@@ -256,6 +266,16 @@ func GetDataFromContext(ctx *cli.Context) (int, interface{}, *cli.ExitError) {
 	return offset, data, nil
 }
 
+// ParseData parses a single 'data' parameter given in the ParseParams format
+// (like "int:42" or "hash160:<hash>") and converts it into an emitable value.
+func ParseData(s string) (interface{}, error) {
+	param, err := smartcontract.NewParameterFromString(s)
+	if err != nil {
+		return nil, err
+	}
+	return smartcontract.ExpandParameterToEmitable(*param)
+}
+
 // EnsureNone returns an error if there are any positional arguments present.
 // It can be used to check for them in commands that don't accept arguments.
 func EnsureNone(ctx *cli.Context) *cli.ExitError {
@@ -341,6 +361,9 @@ func GetSignersAccounts(senderAcc *wallet.Account, wall *wallet.Wallet, signers
 		if signerAcc == nil {
 			return nil, fmt.Errorf("no account was found in the wallet for signer #%d (%s)", i, address.Uint160ToString(s.Account))
 		}
+		if signerAcc.IsWatchOnly() {
+			return nil, fmt.Errorf("signer #%d (%s): %w", i, address.Uint160ToString(s.Account), wallet.ErrWatchOnly)
+		}
 		signersAccounts = append(signersAccounts, actor.SignerAccount{
 			Signer:  s,
 			Account: signerAcc,
@@ -2,6 +2,7 @@ package cmdargs
 
 import (
 	"encoding/hex"
+	"math/big"
 	"strings"
 	"testing"
 
@@ -378,3 +379,28 @@ func TestParseParams_CalledFromOutside(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestParseData(t *testing.T) {
+	h160 := util.Uint160{1, 2, 3}
+	h256 := util.Uint256{4, 5, 6}
+	testCases := map[string]interface{}{
+		"int:42":                     big.NewInt(42),
+		"42":                         big.NewInt(42),
+		"string:deposit":             "deposit",
+		"bytes:010203":               []byte{1, 2, 3},
+		"hash160:" + h160.StringLE(): h160,
+		"hash256:" + h256.StringLE(): h256,
+		"bool:true":                  true,
+		"any:":                       nil,
+	}
+	for str, expected := range testCases {
+		actual, err := ParseData(str)
+		require.NoError(t, err, str)
+		require.Equal(t, expected, actual, str)
+	}
+
+	for _, str := range []string{"int:abc", "bytes:xyz", "hash160:123", "map:"} {
+		_, err := ParseData(str)
+		require.Error(t, err, str)
+	}
+}
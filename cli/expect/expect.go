@@ -0,0 +1,367 @@
+/*
+Package expect implements simple invocation result assertions used by CLI
+commands (--expect flag). Each condition has the form of
+
+	subject operator value
+
+where subject is one of:
+  - state, VM state (HALT or FAULT)
+  - gasconsumed, GAS consumed by the invocation (in GAS, like 0.1)
+  - stack.len, the number of items on the resulting stack
+  - stack[N].type, the type of the N-th stack item (Integer, ByteString, ...)
+  - stack[N].int, stack[N].bool, stack[N].string or stack[N].bytes, the N-th
+    stack item converted to integer, boolean, UTF-8 string or byte slice (the
+    value is hex-encoded then)
+  - notifications.count, the number of emitted notifications
+  - notifications[Name].count, the number of emitted notifications with the
+    given name
+
+and operator is one of =, ==, !=, <, <=, >, >= (the latter four are only
+supported for numeric subjects).
+*/
+package expect
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+type (
+	// Condition is a single parsed expectation.
+	Condition struct {
+		// Raw is the original condition string.
+		Raw string
+
+		subject  subject
+		index    int
+		name     string
+		op       string
+		value    string
+		num      *big.Int
+		boolean  bool
+		bytesVal []byte
+	}
+
+	// Failure describes the condition not met by the invocation result.
+	Failure struct {
+		// Condition is the failed condition.
+		Condition *Condition
+		// Actual is the actual subject value.
+		Actual string
+	}
+
+	// Result contains invocation data conditions are checked against.
+	Result struct {
+		State         string
+		GasConsumed   int64
+		Stack         []stackitem.Item
+		Notifications []state.NotificationEvent
+	}
+
+	subject byte
+)
+
+const (
+	subjState subject = iota
+	subjGas
+	subjStackLen
+	subjStackType
+	subjStackInt
+	subjStackBool
+	subjStackString
+	subjStackBytes
+	subjNotifications
+)
+
+// stackConversions maps stack item subject suffixes to subjects.
+var stackConversions = map[string]subject{
+	"type":   subjStackType,
+	"int":    subjStackInt,
+	"bool":   subjStackBool,
+	"string": subjStackString,
+	"bytes":  subjStackBytes,
+}
+
+// operators is a list of supported operators, two-character ones go first.
+var operators = []string{"==", "!=", "<=", ">=", "=", "<", ">"}
+
+// FromInvoke creates Result from the test invocation result.
+func FromInvoke(r *result.Invoke) *Result {
+	return &Result{
+		State:         r.State,
+		GasConsumed:   r.GasConsumed,
+		Stack:         r.Stack,
+		Notifications: r.Notifications,
+	}
+}
+
+// FromExecution creates Result from the transaction execution result (taken
+// from the application log).
+func FromExecution(e *state.Execution) *Result {
+	return &Result{
+		State:         e.VMState.String(),
+		GasConsumed:   e.GasConsumed,
+		Stack:         e.Stack,
+		Notifications: e.Events,
+	}
+}
+
+// Error implements the error interface.
+func (f *Failure) Error() string {
+	return fmt.Sprintf("expectation %q is not met: expected %s %s, actual %s",
+		f.Condition.Raw, f.Condition.op, f.Condition.value, f.Actual)
+}
+
+// ParseAll parses the given list of conditions.
+func ParseAll(conds []string) ([]*Condition, error) {
+	res := make([]*Condition, 0, len(conds))
+	for _, s := range conds {
+		c, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// Parse parses a single condition.
+func Parse(s string) (*Condition, error) {
+	var (
+		c     = &Condition{Raw: s}
+		opIdx = -1
+		depth int
+	)
+	for i := 0; i < len(s) && opIdx < 0; i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '=', '!', '<', '>':
+			if depth == 0 {
+				opIdx = i
+			}
+		}
+	}
+	if opIdx < 0 {
+		return nil, fmt.Errorf("invalid condition %q: no operator", s)
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(s[opIdx:], op) {
+			c.op = op
+			break
+		}
+	}
+	if c.op == "" {
+		return nil, fmt.Errorf("invalid condition %q: unknown operator", s)
+	}
+	c.value = strings.TrimSpace(s[opIdx+len(c.op):])
+	if c.op == "==" {
+		c.op = "="
+	}
+	if err := c.parseSubject(strings.TrimSpace(s[:opIdx])); err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", s, err)
+	}
+	if err := c.parseValue(); err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", s, err)
+	}
+	return c, nil
+}
+
+func (c *Condition) parseSubject(subj string) error {
+	switch strings.ToLower(subj) {
+	case "state":
+		c.subject = subjState
+		return nil
+	case "gasconsumed":
+		c.subject = subjGas
+		return nil
+	case "stack.len":
+		c.subject = subjStackLen
+		return nil
+	case "notifications.count":
+		c.subject = subjNotifications
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(subj, "stack["):
+		end := strings.Index(subj, "]")
+		if end < 0 {
+			return errors.New("missing ']'")
+		}
+		idx, err := strconv.ParseUint(subj[len("stack["):end], 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid stack index: %w", err)
+		}
+		c.index = int(idx)
+		conv := subj[end+1:]
+		s, ok := stackConversions[strings.ToLower(strings.TrimPrefix(conv, "."))]
+		if !ok || !strings.HasPrefix(conv, ".") {
+			return fmt.Errorf("unknown stack item conversion %q (type, int, bool, string or bytes expected)", conv)
+		}
+		c.subject = s
+	case strings.HasPrefix(subj, "notifications["):
+		end := strings.LastIndex(subj, "]")
+		if end < 0 || strings.ToLower(subj[end+1:]) != ".count" {
+			return errors.New("notifications[Name].count expected")
+		}
+		c.name = subj[len("notifications["):end]
+		if c.name == "" {
+			return errors.New("empty notification name")
+		}
+		c.subject = subjNotifications
+	default:
+		return fmt.Errorf("unknown subject %q", subj)
+	}
+	return nil
+}
+
+func (c *Condition) parseValue() error {
+	var err error
+	switch c.subject {
+	case subjGas:
+		var gas fixedn.Fixed8
+		gas, err = fixedn.Fixed8FromString(c.value)
+		c.num = big.NewInt(int64(gas))
+	case subjStackLen, subjNotifications, subjStackInt:
+		var ok bool
+		c.num, ok = new(big.Int).SetString(c.value, 10)
+		if !ok {
+			err = errors.New("not an integer")
+		}
+	case subjStackBool:
+		c.boolean, err = strconv.ParseBool(c.value)
+	case subjStackBytes:
+		c.bytesVal, err = hex.DecodeString(c.value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q: %w", c.value, err)
+	}
+	if c.num == nil && c.op != "=" && c.op != "!=" {
+		return fmt.Errorf("operator %s is only supported for numeric values", c.op)
+	}
+	return nil
+}
+
+// CheckAll checks all of the given conditions against the result and returns
+// the list of failures (empty if all of them are met).
+func CheckAll(conds []*Condition, r *Result) []*Failure {
+	var res []*Failure
+	for _, c := range conds {
+		if f := c.Check(r); f != nil {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Check checks the condition against the result, it returns nil if the
+// condition is met.
+func (c *Condition) Check(r *Result) *Failure {
+	var (
+		actual string
+		ok     bool
+	)
+	switch c.subject {
+	case subjState:
+		actual = r.State
+		ok = c.equal(strings.EqualFold(actual, c.value))
+	case subjGas:
+		actual = fixedn.Fixed8(r.GasConsumed).String()
+		ok = c.compare(big.NewInt(r.GasConsumed))
+	case subjStackLen:
+		actual = strconv.Itoa(len(r.Stack))
+		ok = c.compare(big.NewInt(int64(len(r.Stack))))
+	case subjNotifications:
+		var n int
+		for i := range r.Notifications {
+			if c.name == "" || r.Notifications[i].Name == c.name {
+				n++
+			}
+		}
+		actual = strconv.Itoa(n)
+		ok = c.compare(big.NewInt(int64(n)))
+	default:
+		actual, ok = c.checkStackItem(r.Stack)
+	}
+	if ok {
+		return nil
+	}
+	return &Failure{Condition: c, Actual: actual}
+}
+
+// checkStackItem returns the actual stack item value and whether it matches
+// the condition. Missing and non-convertible items never match.
+func (c *Condition) checkStackItem(stack []stackitem.Item) (string, bool) {
+	if c.index >= len(stack) {
+		return fmt.Sprintf("no item (stack has %d items)", len(stack)), false
+	}
+	item := stack[c.index]
+	switch c.subject {
+	case subjStackType:
+		actual := item.Type().String()
+		return actual, c.equal(strings.EqualFold(actual, c.value))
+	case subjStackInt:
+		i, err := item.TryInteger()
+		if err != nil {
+			return fmt.Sprintf("%s (not an integer)", item.Type()), false
+		}
+		return i.String(), c.compare(i)
+	case subjStackBool:
+		b, err := item.TryBool()
+		if err != nil {
+			return fmt.Sprintf("%s (not a boolean)", item.Type()), false
+		}
+		return strconv.FormatBool(b), c.equal(b == c.boolean)
+	default:
+		b, err := item.TryBytes()
+		if err != nil {
+			return fmt.Sprintf("%s (not a byte string)", item.Type()), false
+		}
+		if c.subject == subjStackBytes {
+			return hex.EncodeToString(b), c.equal(bytes.Equal(b, c.bytesVal))
+		}
+		if !utf8.Valid(b) {
+			return fmt.Sprintf("%s (not a valid UTF-8 string)", hex.EncodeToString(b)), false
+		}
+		return string(b), c.equal(string(b) == c.value)
+	}
+}
+
+// equal returns the result of non-numeric condition check given the values
+// equality.
+func (c *Condition) equal(eq bool) bool {
+	return eq == (c.op == "=")
+}
+
+// compare compares the given value with the expected one using the
+// condition operator.
+func (c *Condition) compare(actual *big.Int) bool {
+	cmp := actual.Cmp(c.num)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // ">="
+		return cmp >= 0
+	}
+}
@@ -0,0 +1,259 @@
+package expect
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResult() *Result {
+	return &Result{
+		State:       "HALT",
+		GasConsumed: 1_2345_6789, // 1.23456789 GAS
+		Stack: []stackitem.Item{
+			stackitem.Make(100),
+			stackitem.Make(true),
+			stackitem.Make("hello"),
+			stackitem.Make([]byte{0xde, 0xad}),
+			stackitem.Make([]stackitem.Item{}),
+			stackitem.Null{},
+			stackitem.Make([]byte{0xff, 0xfe}),
+		},
+		Notifications: []state.NotificationEvent{
+			{Name: "Transfer", Item: stackitem.NewArray(nil)},
+			{Name: "Transfer", Item: stackitem.NewArray(nil)},
+			{Name: "Mint", Item: stackitem.NewArray(nil)},
+		},
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"state",
+		"state HALT",
+		"unknown=1",
+		"state<HALT",
+		"state>=HALT",
+		"gasconsumed=abc",
+		"gasconsumed=0.000000001",
+		"stack.len=x",
+		"stack[0]=1",
+		"stack[0].int",
+		"stack[0]int=1",
+		"stack[0].float=1",
+		"stack[x].int=1",
+		"stack[-1].int=1",
+		"stack[0.int=1",
+		"stack[0].int=1.5",
+		"stack[0].bool=maybe",
+		"stack[0].bool<true",
+		"stack[0].bytes=xyz",
+		"stack[0].string>a",
+		"stack[0].type<=Integer",
+		"notifications=1",
+		"notifications[].count=1",
+		"notifications[Transfer]=1",
+		"notifications[Transfer].len=1",
+		"notifications[Transfer].count=many",
+	} {
+		_, err := Parse(s)
+		require.Error(t, err, s)
+	}
+	_, err := ParseAll([]string{"state=HALT", "state"})
+	require.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	r := newTestResult()
+	testCases := map[string]bool{
+		"state=HALT":   true,
+		"state==halt":  true,
+		"state = HALT": true,
+		"STATE=HALT":   true,
+		"state!=HALT":  false,
+		"state=FAULT":  false,
+		"state!=FAULT": true,
+
+		"gasconsumed=1.23456789": true,
+		"gasconsumed<2":          true,
+		"gasconsumed<=1.2":       false,
+		"gasconsumed>1":          true,
+		"gasconsumed>=1.5":       false,
+		"gasconsumed!=1":         true,
+
+		"stack.len=7":  true,
+		"stack.len>7":  false,
+		"stack.len<10": true,
+		"STACK.LEN>=7": true,
+
+		"stack[0].int=100":       true,
+		"stack[0].int==100":      true,
+		"stack[0].int>=100":      true,
+		"stack[0].int>100":       false,
+		"stack[0].int<101":       true,
+		"stack[0].int<=99":       false,
+		"stack[0].int!=100":      false,
+		"stack[0].type=Integer":  true,
+		"stack[0].type=integer":  true,
+		"stack[0].type!=Boolean": true,
+		"stack[0].string=d":      true, // Integer 100 is "d" in bytes.
+		"stack[0].bytes=64":      true,
+		"stack[0].bool=true":     true,
+
+		"stack[1].bool=true":    true,
+		"stack[1].bool=1":       true,
+		"stack[1].bool!=false":  true,
+		"stack[1].bool=false":   false,
+		"stack[1].int=1":        true,
+		"stack[1].type=Boolean": true,
+
+		"stack[2].string=hello":     true,
+		"stack[2].string!=hello":    false,
+		"stack[2].string=Hello":     false,
+		"stack[2].bytes=68656c6c6f": true,
+		"stack[2].type=ByteString":  true,
+		"stack[2].bool=true":        true,
+
+		"stack[3].bytes=dead":  true,
+		"stack[3].bytes=DEAD":  true,
+		"stack[3].bytes!=dead": false,
+		"stack[3].bytes=":      false,
+		"stack[3].int=-21026":  true, // Little-endian two's complement.
+
+		"stack[4].type=Array":  true,
+		"stack[4].int=0":       false,
+		"stack[4].int!=0":      false, // Conversion failure never matches.
+		"stack[4].bytes!=00":   false,
+		"stack[4].string!=abc": false,
+		"stack[4].bool=true":   true,
+
+		"stack[5].type=Any":   true,
+		"stack[5].bool=false": true,
+		"stack[5].int=0":      false,
+
+		"stack[6].string=abc":  false, // Invalid UTF-8.
+		"stack[6].string!=abc": false,
+
+		"stack[7].int=1":     false,
+		"stack[7].int!=1":    false,
+		"stack[7].type!=Any": false,
+
+		"notifications.count=3":            true,
+		"notifications.count>3":            false,
+		"notifications[Transfer].count=2":  true,
+		"notifications[Transfer].count>=1": true,
+		"notifications[Mint].count=1":      true,
+		"notifications[mint].count=0":      true, // Names are case-sensitive.
+		"notifications[Burn].count>0":      false,
+		"notifications[a=b].count=0":       true,
+		"notifications[Transfer].COUNT=2":  true,
+	}
+	for s, expected := range testCases {
+		c, err := Parse(s)
+		require.NoError(t, err, s)
+		f := c.Check(r)
+		if expected {
+			require.Nil(t, f, s)
+		} else {
+			require.NotNil(t, f, s)
+			require.Equal(t, c, f.Condition)
+			require.Contains(t, f.Error(), s)
+		}
+	}
+}
+
+func TestFailure(t *testing.T) {
+	r := newTestResult()
+	c, err := Parse("stack[0].int>=1000")
+	require.NoError(t, err)
+	f := c.Check(r)
+	require.NotNil(t, f)
+	require.Equal(t, "100", f.Actual)
+	require.Equal(t, `expectation "stack[0].int>=1000" is not met: expected >= 1000, actual 100`, f.Error())
+
+	c, err = Parse("gasconsumed < 1")
+	require.NoError(t, err)
+	f = c.Check(r)
+	require.NotNil(t, f)
+	require.Equal(t, "1.23456789", f.Actual)
+
+	c, err = Parse("stack[10].int=1")
+	require.NoError(t, err)
+	f = c.Check(r)
+	require.NotNil(t, f)
+	require.Equal(t, "no item (stack has 7 items)", f.Actual)
+
+	c, err = Parse("stack[4].int=1")
+	require.NoError(t, err)
+	require.Equal(t, "Array (not an integer)", c.Check(r).Actual)
+	c, err = Parse("stack[4].bytes=01")
+	require.NoError(t, err)
+	require.Equal(t, "Array (not a byte string)", c.Check(r).Actual)
+	c, err = Parse("stack[6].string=a")
+	require.NoError(t, err)
+	require.Equal(t, "fffe (not a valid UTF-8 string)", c.Check(r).Actual)
+	c, err = Parse("stack[4].bool=false")
+	require.NoError(t, err)
+	require.Equal(t, "true", c.Check(r).Actual)
+}
+
+func TestCheckAllVerify(t *testing.T) {
+	r := newTestResult()
+	conds, err := ParseAll(nil)
+	require.NoError(t, err)
+	require.Empty(t, CheckAll(conds, r))
+	require.NoError(t, Verify(conds, r))
+
+	conds, err = ParseAll([]string{"state=HALT", "stack[0].int=1", "stack.len=7", "notifications.count=0"})
+	require.NoError(t, err)
+	failures := CheckAll(conds, r)
+	require.Equal(t, 2, len(failures))
+	require.Equal(t, conds[1], failures[0].Condition)
+	require.Equal(t, conds[3], failures[1].Condition)
+	err = Verify(conds, r)
+	require.Error(t, err)
+	require.Equal(t, failures[0].Error()+"\n"+failures[1].Error(), err.Error())
+}
+
+func TestFromInvoke(t *testing.T) {
+	inv := &result.Invoke{
+		State:         "FAULT",
+		GasConsumed:   42,
+		Stack:         []stackitem.Item{stackitem.Make(1)},
+		Notifications: []state.NotificationEvent{{Name: "Event"}},
+	}
+	require.Equal(t, &Result{
+		State:         "FAULT",
+		GasConsumed:   42,
+		Stack:         inv.Stack,
+		Notifications: inv.Notifications,
+	}, FromInvoke(inv))
+}
+
+func TestFromExecution(t *testing.T) {
+	e := &state.Execution{
+		Trigger:     trigger.Application,
+		VMState:     vmstate.Halt,
+		GasConsumed: 42,
+		Stack:       []stackitem.Item{stackitem.NewBigInteger(big.NewInt(1))},
+		Events:      []state.NotificationEvent{{ScriptHash: util.Uint160{1}, Name: "Event"}},
+	}
+	r := FromExecution(e)
+	require.Equal(t, &Result{
+		State:         "HALT",
+		GasConsumed:   42,
+		Stack:         e.Stack,
+		Notifications: e.Events,
+	}, r)
+	conds, err := ParseAll([]string{"state=HALT", "notifications[Event].count=1", "stack[0].int=1", "gasconsumed=0.00000042"})
+	require.NoError(t, err)
+	require.NoError(t, Verify(conds, r))
+}
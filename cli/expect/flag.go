@@ -0,0 +1,62 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+// ConditionsDoc is a documentation for --expect conditions.
+const ConditionsDoc = `   Invocation result can be checked with --expect flag (it can be repeated),
+   the command fails if any of conditions is not met printing the actual value.
+   Each condition is specified as 'subject operator value' where
+    * 'subject' is one of:
+        - 'state' - VM state (HALT or FAULT).
+        - 'gasconsumed' - GAS consumed by the invocation (like 0.1).
+        - 'stack.len' - the number of items on the resulting stack.
+        - 'stack[N].type' - the type of the N-th stack item (Integer,
+                            ByteString, Array, ...).
+        - 'stack[N].int', 'stack[N].bool', 'stack[N].string',
+          'stack[N].bytes' - the N-th stack item converted to integer,
+                             boolean, UTF-8 string or hex-encoded bytes.
+        - 'notifications.count' - the number of emitted notifications.
+        - 'notifications[Name].count' - the number of emitted notifications
+                                        with the given name.
+    * 'operator' is one of =, ==, != for any subject and <, <=, >, >= for
+      numeric ones.
+   Examples:
+    'state=HALT'
+    'stack[0].int>=100'
+    'notifications[Transfer].count=1'`
+
+// Flag is a flag used to specify invocation result expectations.
+var Flag = cli.StringSliceFlag{
+	Name: "expect",
+	Usage: "invocation result condition (like 'state=HALT' or 'stack[0].int>=100') to check, " +
+		"the command fails if it's not met (can be repeated)",
+}
+
+// FromContext parses conditions given in the --expect flag.
+func FromContext(ctx *cli.Context) ([]*Condition, error) {
+	conds, err := ParseAll(ctx.StringSlice(Flag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s flag: %w", Flag.Name, err)
+	}
+	return conds, nil
+}
+
+// Verify checks the result against the given conditions and returns an error
+// listing all unmet ones.
+func Verify(conds []*Condition, r *Result) error {
+	failures := CheckAll(conds, r)
+	if len(failures) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(failures))
+	for i := range failures {
+		msgs[i] = failures[i].Error()
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
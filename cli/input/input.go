@@ -9,6 +9,7 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"golang.org/x/term"
 )
 
@@ -69,3 +70,16 @@ func ConfirmTx(w io.Writer, tx *transaction.Transaction) error {
 	}
 	return errors.New("cancelled")
 }
+
+// ConfirmRemoteSign asks for a confirmation to sign the digest with the remote
+// signer key.
+func ConfirmRemoteSign(keyID string, digest util.Uint256) error {
+	ln, err := ReadLine(fmt.Sprintf("Sign %s with remote key %q (y|N)> ", digest.StringBE(), keyID))
+	if err != nil {
+		return err
+	}
+	if 0 < len(ln) && (ln[0] == 'y' || ln[0] == 'Y') {
+		return nil
+	}
+	return errors.New("cancelled")
+}
@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"testing"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
 )
 
@@ -117,7 +119,7 @@ func TestNEP11_ND_OwnerOf_BalanceOf_Transfer(t *testing.T) {
 	bytesRead, err := os.ReadFile(nftOwnerWallet)
 	require.NoError(t, err)
 	wall := filepath.Join(tmpDir, "my_wallet.json")
-	err = os.WriteFile(wall, bytesRead, 0755)
+	err = os.WriteFile(wall, bytesRead, wallet.FileMode)
 	require.NoError(t, err)
 
 	// transfer funds to contract owner
@@ -278,6 +280,28 @@ func TestNEP11_ND_OwnerOf_BalanceOf_Transfer(t *testing.T) {
 	require.Equal(t, hex.EncodeToString(fst), e.GetNextLine(t))
 	require.Equal(t, hex.EncodeToString(snd), e.GetNextLine(t))
 
+	// tokensOf: owner from wallet, with properties
+	e.Run(t, "neo-go", "wallet", "nep11", "tokensof",
+		"--rpc-endpoint", "http://"+e.RPC.Addr,
+		"--token", h.StringLE(), "--wallet", wall, "--properties")
+	for _, id := range [][]byte{fst, snd} {
+		e.CheckNextLine(t, "^"+hex.EncodeToString(id)+"\t"+
+			regexp.QuoteMeta(fmt.Sprintf(`{"name":"HASHY %s"}`, base64.StdEncoding.EncodeToString(id)))+"$")
+	}
+	e.CheckEOF(t)
+
+	// tokensOf: JSON output
+	e.Run(t, append(cmdTokensOf, "--json", "--properties")...)
+	var owned []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(e.GetNextLine(t)), &owned))
+	require.Equal(t, 2, len(owned))
+	for i, id := range [][]byte{fst, snd} {
+		require.Equal(t, nftOwnerAddr, owned[i]["owner"])
+		require.Equal(t, hex.EncodeToString(id), owned[i]["id"])
+		require.Equal(t, map[string]interface{}{"name": "HASHY " + base64.StdEncoding.EncodeToString(id)}, owned[i]["properties"])
+	}
+	e.CheckEOF(t)
+
 	// tokens: missing contract hash
 	cmdTokens := []string{"neo-go", "wallet", "nep11", "tokens",
 		"--rpc-endpoint", "http://" + e.RPC.Addr,
@@ -315,6 +339,10 @@ func TestNEP11_ND_OwnerOf_BalanceOf_Transfer(t *testing.T) {
 	e.In.WriteString(nftOwnerPass + "\r")
 	e.RunWithError(t, cmdTransfer...)
 
+	// transfer: amount for non-divisible token
+	e.In.WriteString(nftOwnerPass + "\r")
+	e.RunWithError(t, append(cmdTransfer, "--id", hex.EncodeToString(tokenID), "--amount", "1")...)
+
 	// transfer: good
 	e.In.WriteString(nftOwnerPass + "\r")
 	e.Run(t, append(cmdTransfer, "--id", hex.EncodeToString(tokenID))...)
@@ -388,7 +416,7 @@ func TestNEP11_D_OwnerOf_BalanceOf_Transfer(t *testing.T) {
 	bytesRead, err := os.ReadFile(testcli.ValidatorWallet)
 	require.NoError(t, err)
 	wall := filepath.Join(tmpDir, "my_wallet.json")
-	err = os.WriteFile(wall, bytesRead, 0755)
+	err = os.WriteFile(wall, bytesRead, wallet.FileMode)
 	require.NoError(t, err)
 
 	// deploy NeoFS Object contract
@@ -599,6 +627,18 @@ func TestNEP11_D_OwnerOf_BalanceOf_Transfer(t *testing.T) {
 	e.In.WriteString(testcli.ValidatorPass + "\r")
 	e.RunWithError(t, cmdTransfer...)
 
+	// transfer: too long id
+	e.In.WriteString(testcli.ValidatorPass + "\r")
+	e.RunWithError(t, append(cmdTransfer, "--id", hex.EncodeToString(make([]byte, 65)), "--amount", "0.25")...)
+
+	// transfer: amount is more precise than the token decimals
+	e.In.WriteString(testcli.ValidatorPass + "\r")
+	e.RunWithError(t, append(cmdTransfer, "--id", hex.EncodeToString(token1ID), "--amount", "0.001")...)
+
+	// transfer: negative amount
+	e.In.WriteString(testcli.ValidatorPass + "\r")
+	e.RunWithError(t, append(cmdTransfer, "--id", hex.EncodeToString(token1ID), "--amount", "-0.25")...)
+
 	// transfer: good
 	e.In.WriteString(testcli.ValidatorPass + "\r")
 	e.Run(t, append(cmdTransfer, "--id", hex.EncodeToString(token1ID))...)
@@ -1,6 +1,7 @@
 package nep_test
 
 import (
+	"encoding/json"
 	"io"
 	"math/big"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
 )
@@ -113,6 +115,63 @@ func TestNEP17Balance(t *testing.T) {
 	t.Run("Bad wallet", func(t *testing.T) {
 		e.RunWithError(t, append(cmdbalance, "--wallet", "/dev/null")...)
 	})
+	t.Run("all tokens", func(t *testing.T) {
+		addr3, err := address.StringToUint160("NfgHwwTi3wHAS8aFAN243C5vGbkYDpqLHP")
+		require.NoError(t, err)
+		neoBalance, neoIndex := e.Chain.GetGoverningTokenBalance(addr3)
+		gasBalance := e.Chain.GetUtilityTokenBalance(addr3)
+		allCmd := append(cmdbalance, "--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--address", address.Uint160ToString(addr3), "--all")
+
+		t.Run("json without all", func(t *testing.T) {
+			e.RunWithError(t, append(cmd, "--json")...)
+		})
+		t.Run("no wallet", func(t *testing.T) {
+			e.Run(t, allCmd...)
+			e.CheckNextLine(t, "^Account "+address.Uint160ToString(addr3))
+			// The order of assets is undefined.
+			for i := 0; i < 2; i++ {
+				line := e.GetNextLine(t)
+				if strings.Contains(line, "GAS") {
+					e.CheckLine(t, line, "^\\s*GAS:\\s+GasToken \\("+e.Chain.UtilityTokenHash().StringLE()+"\\)")
+					e.CheckNextLine(t, "^\\s*Amount\\s*:\\s*"+fixedn.Fixed8(gasBalance.Int64()).String()+"$")
+					e.CheckNextLine(t, "^\\s*Updated:")
+				} else {
+					e.CheckLine(t, line, "^\\s*NEO:\\s+NeoToken \\("+e.Chain.GoverningTokenHash().StringLE()+"\\)")
+					e.CheckNextLine(t, "^\\s*Amount\\s*:\\s*"+neoBalance.String()+"$")
+					e.CheckNextLine(t, "^\\s*Updated\\s*:\\s*"+strconv.FormatUint(uint64(neoIndex), 10))
+				}
+			}
+			e.CheckEOF(t)
+		})
+		t.Run("JSON", func(t *testing.T) {
+			e.Run(t, append(allCmd, "--json", "--token", "kek")...)
+			var res []struct {
+				Address  string `json:"address"`
+				Tracking bool   `json:"tracking"`
+				Balances []struct {
+					Symbol      string `json:"symbol"`
+					Amount      string `json:"amount"`
+					LastUpdated uint32 `json:"lastupdatedblock"`
+				} `json:"balances"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(e.GetNextLine(t)), &res))
+			require.Equal(t, 1, len(res))
+			require.Equal(t, address.Uint160ToString(addr3), res[0].Address)
+			require.True(t, res[0].Tracking)
+			require.Equal(t, 2, len(res[0].Balances))
+			for _, b := range res[0].Balances {
+				if b.Symbol == "NEO" {
+					require.Equal(t, neoBalance.String(), b.Amount)
+					require.Equal(t, neoIndex, b.LastUpdated)
+				} else {
+					require.Equal(t, "GAS", b.Symbol)
+					require.Equal(t, fixedn.Fixed8(gasBalance.Int64()).String(), b.Amount)
+				}
+			}
+			e.CheckEOF(t)
+		})
+	})
 }
 
 func TestNEP17Transfer(t *testing.T) {
@@ -155,6 +214,15 @@ func TestNEP17Transfer(t *testing.T) {
 		e.In.Reset()
 	})
 
+	t.Run("fees with overrides", func(t *testing.T) {
+		e.In.WriteString("one\r")
+		e.In.WriteString("n\r")
+		e.RunWithError(t, append(args, "--gas", "1", "--sysgas", "2")...)
+		e.CheckNextLine(t, `^Network fee:\s*1\.\d+`)
+		e.CheckNextLine(t, `^System fee:\s*2\.\d+`)
+		e.CheckNextLine(t, `^Total fee:\s*3\.\d+`)
+	})
+
 	e.In.WriteString("one\r")
 	e.In.WriteString("Y\r")
 	e.Run(t, args...)
@@ -205,6 +273,35 @@ func TestNEP17Transfer(t *testing.T) {
 		require.Equal(t, big.NewInt(41), b)
 	})
 
+	t.Run("idempotency key", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		args := []string{
+			"neo-go", "wallet", "nep17", "transfer",
+			"--rpc-endpoint", "http://" + e.RPC.Addr,
+			"--wallet", testcli.ValidatorWallet,
+			"--to", w.Accounts[0].Address,
+			"--token", "NEO",
+			"--amount", "1",
+			"--from", testcli.ValidatorAddr,
+			"--force",
+			"--idempotency-key", "transfer-1",
+		}
+		e.In.WriteString("one\r")
+		e.Run(t, args...)
+		tx, _ := e.CheckTxPersisted(t)
+
+		e.In.WriteString("one\r")
+		e.Run(t, args...)
+		e.CheckNextLine(t, tx.Hash().StringLE())
+		e.CheckEOF(t)
+
+		b, _ := e.Chain.GetGoverningTokenBalance(w.Accounts[0].ScriptHash())
+		require.Equal(t, big.NewInt(4), b)
+
+		e.In.WriteString("one\r")
+		e.RunWithError(t, append(args, "--out", filepath.Join(t.TempDir(), "tx.json"))...)
+	})
+
 	t.Run("with signers", func(t *testing.T) {
 		e.In.WriteString("one\r")
 		e.Run(t, "neo-go", "wallet", "nep17", "multitransfer",
@@ -236,6 +333,32 @@ func TestNEP17Transfer(t *testing.T) {
 		e.CheckTxPersisted(t)
 	})
 
+	t.Run("with data flag", func(t *testing.T) {
+		args := []string{
+			"neo-go", "wallet", "nep17", "transfer",
+			"--rpc-endpoint", "http://" + e.RPC.Addr,
+			"--wallet", testcli.ValidatorWallet,
+			"--to", w.Accounts[0].Address,
+			"--token", "GAS",
+			"--amount", "1",
+			"--from", testcli.ValidatorAddr,
+			"--force",
+		}
+		t.Run("invalid", func(t *testing.T) {
+			e.In.WriteString("one\r")
+			e.RunWithError(t, append(args, "--data", "int:notanumber")...)
+		})
+		t.Run("with argument", func(t *testing.T) {
+			e.In.WriteString("one\r")
+			e.RunWithError(t, append(args, "--data", "int:42", "string:deposit")...)
+		})
+		for _, data := range []string{"int:42", "string:deposit", "bytes:010203", "hash160:" + hVerify.StringLE()} {
+			e.In.WriteString("one\r")
+			e.Run(t, append(args, "--data", data)...)
+			e.CheckTxPersisted(t)
+		}
+	})
+
 	t.Run("with data and signers", func(t *testing.T) {
 		t.Run("invalid sender's scope", func(t *testing.T) {
 			e.In.WriteString("one\r")
@@ -252,6 +375,52 @@ func TestNEP17Transfer(t *testing.T) {
 			e.CheckTxPersisted(t)
 		})
 	})
+
+	t.Run("all", func(t *testing.T) {
+		args := []string{
+			"neo-go", "wallet", "nep17", "transfer",
+			"--rpc-endpoint", "http://" + e.RPC.Addr,
+			"--wallet", testcli.ValidatorWallet,
+			"--to", w.Accounts[0].Address,
+			"--from", testcli.ValidatorAddr,
+			"--force",
+			"--all",
+		}
+		sh := w.Accounts[0].ScriptHash()
+
+		t.Run("with amount", func(t *testing.T) {
+			e.In.WriteString("one\r")
+			e.RunWithError(t, append(args, "--token", "NEO", "--amount", "1")...)
+		})
+
+		t.Run("NEO", func(t *testing.T) {
+			neoBefore, _ := e.Chain.GetGoverningTokenBalance(testcli.ValidatorHash)
+			recvBefore, _ := e.Chain.GetGoverningTokenBalance(sh)
+			e.In.WriteString("one\r")
+			e.Run(t, append(args, "--token", "NEO")...)
+			e.CheckTxPersisted(t)
+
+			b, _ := e.Chain.GetGoverningTokenBalance(testcli.ValidatorHash)
+			require.Equal(t, 0, b.Sign())
+			b, _ = e.Chain.GetGoverningTokenBalance(sh)
+			require.Equal(t, new(big.Int).Add(recvBefore, neoBefore), b)
+
+			e.In.WriteString("one\r")
+			e.RunWithError(t, append(args, "--token", "NEO")...)
+		})
+
+		t.Run("GAS", func(t *testing.T) {
+			gasBefore := e.Chain.GetUtilityTokenBalance(testcli.ValidatorHash)
+			recvBefore := e.Chain.GetUtilityTokenBalance(sh)
+			e.In.WriteString("one\r")
+			e.Run(t, append(args, "--token", "GAS", "--gas", "0.1")...)
+			tx, _ := e.CheckTxPersisted(t)
+
+			// The validator gets block rewards, so check the amount received.
+			expected := new(big.Int).Sub(gasBefore, big.NewInt(tx.SystemFee+tx.NetworkFee))
+			require.Equal(t, new(big.Int).Add(recvBefore, expected), e.Chain.GetUtilityTokenBalance(sh))
+		})
+	})
 }
 
 func TestNEP17MultiTransfer(t *testing.T) {
@@ -386,3 +555,54 @@ func TestNEP17ImportToken(t *testing.T) {
 		})
 	})
 }
+
+func TestNEP17ImportTokenOffline(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	walletPath := filepath.Join(t.TempDir(), "walletForImport.json")
+	tokenHash := util.Uint160{1, 2, 3}
+	e.Run(t, "neo-go", "wallet", "init", "--wallet", walletPath)
+
+	t.Run("missing decimals", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "wallet", "nep17", "import",
+			"--wallet", walletPath, "--token", tokenHash.StringLE(), "--symbol", "TKN")
+	})
+	t.Run("invalid decimals", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "wallet", "nep17", "import",
+			"--wallet", walletPath, "--token", tokenHash.StringLE(), "--symbol", "TKN", "--decimals", "-1")
+	})
+	t.Run("empty symbol", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "wallet", "nep17", "import",
+			"--wallet", walletPath, "--token", tokenHash.StringLE(), "--symbol", "", "--decimals", "2")
+	})
+
+	checkInfo := func(t *testing.T) {
+		e.CheckNextLine(t, "^Name:\\s*TKN")
+		e.CheckNextLine(t, "^Symbol:\\s*TKN")
+		e.CheckNextLine(t, "^Hash:\\s*"+tokenHash.StringLE())
+		e.CheckNextLine(t, "^Decimals:\\s*2")
+		e.CheckNextLine(t, "^Address:\\s*"+address.Uint160ToString(tokenHash))
+		e.CheckNextLine(t, "^Standard:\\s*"+string(manifest.NEP17StandardName))
+	}
+	e.Run(t, "neo-go", "wallet", "nep17", "import",
+		"--wallet", walletPath, "--token", tokenHash.StringLE(), "--symbol", "TKN", "--decimals", "2")
+	checkInfo(t)
+	e.CheckEOF(t)
+
+	t.Run("duplicate", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "wallet", "nep17", "import",
+			"--wallet", walletPath, "--token", tokenHash.StringLE(), "--symbol", "TKN", "--decimals", "2")
+	})
+	t.Run("info by symbol", func(t *testing.T) {
+		e.Run(t, "neo-go", "wallet", "nep17", "info",
+			"--wallet", walletPath, "--token", "TKN")
+		checkInfo(t)
+		e.CheckEOF(t)
+	})
+	t.Run("remove by symbol", func(t *testing.T) {
+		e.Run(t, "neo-go", "wallet", "nep17", "remove",
+			"--wallet", walletPath, "--token", "TKN", "--force")
+		e.Run(t, "neo-go", "wallet", "nep17", "info",
+			"--wallet", walletPath)
+		e.CheckEOF(t)
+	})
+}
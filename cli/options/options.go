@@ -134,6 +134,17 @@ func GetInvoker(c *rpcclient.Client, ctx *cli.Context, signers []transaction.Sig
 	return nil, cli.NewExitError(errInvalidHistoric, 1)
 }
 
+// NewInvokeError wraps the given invocation error into cli.ExitCoder, for
+// historic calls with the state not available on the RPC server it adds a hint
+// with the nearest height the state is available for.
+func NewInvokeError(err error) cli.ExitCoder {
+	var stErr *invoker.HistoricStateError
+	if errors.As(err, &stErr) {
+		return cli.NewExitError(fmt.Errorf("%w\nUse --historic %d to invoke with the nearest available state", err, stErr.Nearest), 1)
+	}
+	return cli.NewExitError(err, 1)
+}
+
 // GetRPCWithInvoker combines GetRPCClient with GetInvoker for cases where it's
 // appropriate to do so.
 func GetRPCWithInvoker(gctx context.Context, ctx *cli.Context, signers []transaction.Signer) (*rpcclient.Client, *invoker.Invoker, cli.ExitCoder) {
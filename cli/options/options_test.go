@@ -1,11 +1,15 @@
 package options
 
 import (
+	"errors"
 	"flag"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
 
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli"
@@ -55,3 +59,13 @@ func TestGetTimeoutContext(t *testing.T) {
 		require.True(t, start.Before(dl) && (dl.Before(end) || dl.Equal(end)))
 	})
 }
+
+func TestNewInvokeError(t *testing.T) {
+	err := NewInvokeError(errors.New("some error"))
+	require.Equal(t, 1, err.ExitCode())
+	require.Equal(t, "some error", err.Error())
+
+	err = NewInvokeError(&invoker.HistoricStateError{Nearest: 42, Err: neorpc.NewUnsupportedStateError(5, 42)})
+	require.Equal(t, 1, err.ExitCode())
+	require.True(t, strings.HasSuffix(err.Error(), "Use --historic 42 to invoke with the nearest available state"), err.Error())
+}
@@ -1,6 +1,8 @@
 package paramcontext
 
 import (
+	stdcontext "context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,11 +15,21 @@ import (
 
 // InitAndSave creates an incompletely signed transaction which can be used
 // as an input to `multisig sign`. If a wallet.Account is given and can sign,
-// it's signed as well using it.
+// it's signed as well using it. Deployed contract-based accounts always get
+// a context item with their verification parameters (and values set for them
+// if any), so that other signers know what's expected.
 func InitAndSave(net netmode.Magic, tx *transaction.Transaction, acc *wallet.Account, filename string) error {
 	scCtx := context.NewParameterContext(context.TransactionType, net, tx)
+	if acc != nil && acc.Contract != nil && acc.Contract.Deployed {
+		if err := scCtx.AddParameters(acc.ScriptHash(), acc.Contract, acc.VerifyParameters()); err != nil {
+			return fmt.Errorf("can't add verification parameters: %w", err)
+		}
+	}
 	if acc != nil && acc.CanSign() {
-		sign := acc.SignHashable(net, tx)
+		sign, err := acc.SignHashableContext(stdcontext.Background(), net, tx)
+		if err != nil {
+			return fmt.Errorf("can't sign: %w", err)
+		}
 		if err := scCtx.AddSignature(acc.ScriptHash(), acc.Contract, acc.PublicKey(), sign); err != nil {
 			return fmt.Errorf("can't add signature: %w", err)
 		}
@@ -32,6 +44,19 @@ func Read(filename string) (*context.ParameterContext, error) {
 		return nil, fmt.Errorf("can't read input file: %w", err)
 	}
 
+	return parse(data)
+}
+
+// ReadBase64 decodes the parameter context from the base64-encoded JSON.
+func ReadBase64(s string) (*context.ParameterContext, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("can't decode base64 input: %w", err)
+	}
+	return parse(data)
+}
+
+func parse(data []byte) (*context.ParameterContext, error) {
 	c := new(context.ParameterContext)
 	if err := json.Unmarshal(data, c); err != nil {
 		return nil, fmt.Errorf("can't parse transaction: %w", err)
@@ -39,6 +64,15 @@ func Read(filename string) (*context.ParameterContext, error) {
 	return c, nil
 }
 
+// ToBase64 returns the base64-encoded JSON of the parameter context.
+func ToBase64(c *context.ParameterContext) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("can't marshal transaction: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
 // Save writes the parameter context to the file.
 func Save(c *context.ParameterContext, filename string) error {
 	if data, err := json.Marshal(c); err != nil {
@@ -1,6 +1,8 @@
 package server_test
 
 import (
+	"bufio"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -8,7 +10,9 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/testcli"
 	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
@@ -110,4 +114,87 @@ func TestDBRestoreDump(t *testing.T) {
 	d2, err := os.ReadFile(dumpPath)
 	require.NoError(t, err)
 	require.Equal(t, d1, d2, "dumps differ")
+
+	t.Run("acc", func(t *testing.T) {
+		// Full dump is the same in both formats.
+		dumpCmd := []string{"neo-go", "db", "dump", "--unittest", "--config-path", tmpDir, "--format", "acc"}
+		accPath := filepath.Join(tmpDir, "chain.acc")
+		e.Run(t, append(dumpCmd, "--out", accPath, "--progress", "7")...)
+		d3, err := os.ReadFile(accPath)
+		require.NoError(t, err)
+		require.Equal(t, d1, d3)
+
+		// Dump with offset has the start index in its header.
+		offPath := filepath.Join(tmpDir, "chain.20.acc")
+		e.Run(t, append(dumpCmd, "--out", offPath, "--start", "20")...)
+		d4, err := os.ReadFile(offPath)
+		require.NoError(t, err)
+		require.Equal(t, []byte{20, 0, 0, 0, 31, 0, 0, 0}, d4[:8])
+
+		e.RunWithError(t, append(baseCmd, "--format", "zip")...)
+
+		// Restore both parts into a new DB and check it's the same.
+		accDir := t.TempDir()
+		cfg := loadConfig(t)
+		cfg.ApplicationConfiguration.DBConfiguration.LevelDBOptions.DataDirectoryPath = filepath.Join(accDir, "chain")
+		out, err := yaml.Marshal(cfg)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(accDir, "protocol.unit_testnet.yml"), out, os.ModePerm))
+
+		restoreCmd := []string{"neo-go", "db", "restore", "--unittest", "--config-path", accDir, "--format", "acc"}
+		e.Run(t, append(restoreCmd, "--in", accPath, "--count", "20")...)
+		e.RunWithError(t, append(restoreCmd, "--in", offPath, "--format", "neo-go")...) // Start is not expected.
+		e.Run(t, append(restoreCmd, "--in", offPath, "--progress", "10")...)
+
+		resPath := filepath.Join(tmpDir, "restored.acc")
+		e.Run(t, "neo-go", "db", "dump", "--unittest", "--config-path", accDir, "--out", resPath)
+		d5, err := os.ReadFile(resPath)
+		require.NoError(t, err)
+		require.Equal(t, d1, d5)
+	})
+
+	t.Run("statediff", func(t *testing.T) {
+		diffCmd := []string{"neo-go", "db", "statediff", "--unittest", "--config-path", tmpDir}
+		diffPath := filepath.Join(tmpDir, "diff.jsonl")
+		e.RunWithError(t, diffCmd...)                                        // No heights.
+		e.RunWithError(t, append(diffCmd, "--from", "10")...)                // No --to.
+		e.RunWithError(t, append(diffCmd, "--from", "10", "--to", "100")...) // Unavailable height.
+		e.RunWithError(t, append(diffCmd, "--from", "10", "--to", "40", "--contract", util.Uint160{1, 2, 3}.StringLE())...)
+
+		e.Run(t, append(diffCmd, "--from", "10", "--to", "40", "--out", diffPath)...)
+		f, err := os.Open(diffPath)
+		require.NoError(t, err)
+		defer f.Close()
+		var (
+			items   int
+			scanner = bufio.NewScanner(f)
+		)
+		for scanner.Scan() {
+			var item map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &item))
+			require.Contains(t, []interface{}{"Added", "Changed", "Deleted"}, item["state"])
+			items++
+		}
+		require.NoError(t, scanner.Err())
+		require.NotZero(t, items)
+
+		// Same heights, no difference.
+		e.Run(t, append(diffCmd, "--from", "40", "--to", "40", "--out", diffPath)...)
+		d, err := os.ReadFile(diffPath)
+		require.NoError(t, err)
+		require.Empty(t, d)
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		unlockCmd := []string{"neo-go", "db", "unlock", "--unittest", "--config-path", tmpDir}
+		e.RunWithError(t, unlockCmd...) // Closed properly, no lock data.
+
+		store, err := storage.NewStore(loadConfig(t).ApplicationConfiguration.DBConfiguration)
+		require.NoError(t, err)
+		e.RunWithError(t, append(baseCmd, "--wait-for-lock", "200ms")...)
+		e.RunWithError(t, unlockCmd...) // Holder is running.
+		require.NoError(t, store.Close())
+
+		e.Run(t, append(baseCmd, "--wait-for-lock", "200ms")...)
+	})
 }
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/cli/options"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
@@ -27,23 +30,70 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle"
 	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
 	"github.com/nspcc-dev/neo-go/pkg/services/stateroot"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
 )
 
+// waitForLockFlag is a flag for commands opening the node DB.
+var waitForLockFlag = cli.DurationFlag{
+	Name:  "wait-for-lock",
+	Usage: "time to wait for the DB lock held by another process to be released (fail immediately by default)",
+}
+
 // NewCommands returns 'node' command.
 func NewCommands() []cli.Command {
 	cfgFlags := []cli.Flag{options.Config}
 	cfgFlags = append(cfgFlags, options.Network...)
+	var unlockFlags = make([]cli.Flag, len(cfgFlags))
+	copy(unlockFlags, cfgFlags)
+	cfgFlags = append(cfgFlags, waitForLockFlag)
 	var cfgWithCountFlags = make([]cli.Flag, len(cfgFlags))
 	copy(cfgWithCountFlags, cfgFlags)
+	var cfgPruneFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgPruneFlags, cfgFlags)
+	cfgPruneFlags = append(cfgPruneFlags,
+		cli.UintFlag{
+			Name:  "height",
+			Usage: "the lowest state height to keep",
+		},
+	)
 	cfgFlags = append(cfgFlags, options.Debug)
+	var cfgStateDiffFlags = make([]cli.Flag, len(cfgFlags))
+	copy(cfgStateDiffFlags, cfgFlags)
+	cfgStateDiffFlags = append(cfgStateDiffFlags,
+		cli.UintFlag{
+			Name:  "from",
+			Usage: "the first state height",
+		},
+		cli.UintFlag{
+			Name:  "to",
+			Usage: "the second state height",
+		},
+		flags.AddressFlag{
+			Name:  "contract",
+			Usage: "only process storage items of the contract with the given hash or address",
+		},
+		cli.StringFlag{
+			Name:  "out, o",
+			Usage: "Output file (stdout if not given)",
+		},
+	)
 
 	cfgWithCountFlags = append(cfgWithCountFlags,
 		cli.UintFlag{
 			Name:  "count, c",
 			Usage: "number of blocks to be processed (default or 0: all chain)",
 		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: chaindump.FormatNeoGo.String(),
+			Usage: "dump format: 'neo-go' or 'acc' (neo-cli chain.acc/chain.<start>.acc)",
+		},
+		cli.UintFlag{
+			Name:  "progress",
+			Usage: "log progress every N blocks (default or 0: don't log)",
+		},
 	)
 	var cfgCountOutFlags = make([]cli.Flag, len(cfgWithCountFlags))
 	copy(cfgCountOutFlags, cfgWithCountFlags)
@@ -77,7 +127,7 @@ func NewCommands() []cli.Command {
 		{
 			Name:      "node",
 			Usage:     "start a NEO node",
-			UsageText: "neo-go node [--config-path path] [-d] [-p/-m/-t]",
+			UsageText: "neo-go node [--config-path path] [-d] [-p/-m/-t] [--wait-for-lock duration]",
 			Action:    startServer,
 			Flags:     cfgFlags,
 		},
@@ -88,16 +138,64 @@ func NewCommands() []cli.Command {
 				{
 					Name:      "dump",
 					Usage:     "dump blocks (starting with block #1) to the file",
-					UsageText: "neo-go db dump -o file [-s start] [-c count] [--config-path path] [-p/-m/-t]",
-					Action:    dumpDB,
-					Flags:     cfgCountOutFlags,
+					UsageText: "neo-go db dump -o file [-s start] [-c count] [--format neo-go|acc] [--progress N] [--config-path path] [-p/-m/-t] [--wait-for-lock duration]",
+					Description: `Dumps blocks to the file (or stdout). Dumps are compatible with neo-cli
+   chain.acc files. Use '--format acc' to also store the start index for dumps
+   not starting from the genesis block like neo-cli does for chain.<start>.acc
+   files (neo-go dumps only have the number of blocks in the header).
+`,
+					Action: dumpDB,
+					Flags:  cfgCountOutFlags,
 				},
 				{
 					Name:      "restore",
 					Usage:     "restore blocks from the file",
-					UsageText: "neo-go db restore -i file [--dump] [-n] [-c count] [--config-path path] [-p/-m/-t]",
-					Action:    restoreDB,
-					Flags:     cfgCountInFlags,
+					UsageText: "neo-go db restore -i file [--dump] [-n] [-c count] [--format neo-go|acc] [--progress N] [--config-path path] [-p/-m/-t] [--wait-for-lock duration]",
+					Description: `Restores blocks from the file (or stdin). Incremental dumps (-n) have the
+   start index stored in the header. With '--format acc' files named like
+   neo-cli chain.<start>.acc dumps are treated as incremental automatically.
+`,
+					Action: restoreDB,
+					Flags:  cfgCountInFlags,
+				},
+				{
+					Name:      "prune",
+					Usage:     "remove MPT state data not needed for the latest states",
+					UsageText: "neo-go db prune --height height [--config-path path] [-p/-m/-t] [--wait-for-lock duration]",
+					Description: `Removes MPT nodes that are not reachable from state roots starting
+   from the given height up to the current one. States below this height
+   are no longer available after that (including proofs and historic
+   invocations), the operation is the same as performed by the node
+   when StatePruning is enabled.`,
+					Action: pruneDB,
+					Flags:  cfgPruneFlags,
+				},
+				{
+					Name:      "statediff",
+					Usage:     "print storage changes between two state heights",
+					UsageText: "neo-go db statediff --from A --to B [--contract hash] [-o file] [--config-path path] [-p/-m/-t] [--wait-for-lock duration]",
+					Description: `Walks MPTs for the given state heights and prints storage items that
+   differ between them in JSON Lines format, one item per line. Every item has
+   'state' (Added, Changed or Deleted), contract 'id', contract hash
+   ('contract', if it's known to the current state), base64-encoded 'key' and
+   'old'/'new' values (if any). Items are ordered by contract ID (in
+   little-endian encoding) and key. The node should not drop old states
+   (KeepOnlyLatestState must be off) and both heights must be within the range
+   of available states (see 'getstateheight' RPC).`,
+					Action: stateDiff,
+					Flags:  cfgStateDiffFlags,
+				},
+				{
+					Name:      "unlock",
+					Usage:     "remove stale DB lock data",
+					UsageText: "neo-go db unlock [--config-path path] [-p/-m/-t]",
+					Description: `Removes DB lock data left by the node that hasn't closed the DB properly.
+   The lock itself is released by OS when the process exits, but its data
+   (PID and hostname of the holder) is kept. This command checks that the
+   recorded process is not running anymore and nobody holds the DB lock
+   before removing it.`,
+					Action: unlockDB,
+					Flags:  unlockFlags,
 				},
 			},
 		},
@@ -116,8 +214,8 @@ func newGraceContext() context.Context {
 	return ctx
 }
 
-func initBCWithMetrics(cfg config.Config, log *zap.Logger) (*core.Blockchain, *metrics.Service, *metrics.Service, error) {
-	chain, err := initBlockChain(cfg, log)
+func initBCWithMetrics(cfg config.Config, log *zap.Logger, lockWait time.Duration) (*core.Blockchain, *metrics.Service, *metrics.Service, error) {
+	chain, err := initBlockChain(cfg, log, lockWait)
 	if err != nil {
 		return nil, nil, nil, cli.NewExitError(err, 1)
 	}
@@ -149,6 +247,11 @@ func dumpDB(ctx *cli.Context) error {
 	}
 	count := uint32(ctx.Uint("count"))
 	start := uint32(ctx.Uint("start"))
+	progress := uint32(ctx.Uint("progress"))
+	format, err := chaindump.ParseFormat(ctx.String("format"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
 
 	var outStream = os.Stdout
 	if out := ctx.String("out"); out != "" {
@@ -158,9 +261,10 @@ func dumpDB(ctx *cli.Context) error {
 		}
 	}
 	defer outStream.Close()
-	writer := io.NewBinWriterFromIO(outStream)
+	bufOut := bufio.NewWriter(outStream)
+	writer := io.NewBinWriterFromIO(bufOut)
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log, ctx.Duration(waitForLockFlag.Name))
 	if err != nil {
 		return err
 	}
@@ -177,14 +281,185 @@ func dumpDB(ctx *cli.Context) error {
 	if count == 0 {
 		count = chainCount - start
 	}
-	writer.WriteU32LE(count)
-	err = chaindump.Dump(chain, writer, start, count)
+	chaindump.WriteHeader(writer, format, start, count)
+	// Blocks are dumped in chunks of progress size to log the progress.
+	var step = count
+	if progress != 0 {
+		step = progress
+	}
+	for done := uint32(0); done < count; {
+		n := count - done
+		if n > step {
+			n = step
+		}
+		err = chaindump.Dump(chain, writer, start+done, n)
+		if err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		done += n
+		if progress != 0 {
+			log.Info("dumped blocks", zap.Uint32("count", done), zap.Uint32("total", count),
+				zap.Uint32("index", start+done-1))
+		}
+	}
+	if err := bufOut.Flush(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
+func pruneDB(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	if !ctx.IsSet("height") {
+		return cli.NewExitError("--height is required", 1)
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log, ctx.Duration(waitForLockFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	err = chain.PruneStates(uint32(ctx.Uint("height")))
 	if err != nil {
-		return cli.NewExitError(err.Error(), 1)
+		return cli.NewExitError(fmt.Errorf("failed to prune states: %w", err), 1)
 	}
 	return nil
 }
 
+// stateDiffItem is a single storage item change printed by db statediff.
+type stateDiffItem struct {
+	State    string        `json:"state"`
+	ID       int32         `json:"id"`
+	Contract *util.Uint160 `json:"contract,omitempty"`
+	Key      []byte        `json:"key"`
+	Old      []byte        `json:"old,omitempty"`
+	New      []byte        `json:"new,omitempty"`
+}
+
+func stateDiff(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	if !ctx.IsSet("from") || !ctx.IsSet("to") {
+		return cli.NewExitError("--from and --to are required", 1)
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	log, logCloser, err := options.HandleLoggingParams(ctx.Bool("debug"), cfg.ApplicationConfiguration)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if logCloser != nil {
+		defer func() { _ = logCloser() }()
+	}
+
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log, ctx.Duration(waitForLockFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pprof.ShutDown()
+		prometheus.ShutDown()
+		chain.Close()
+	}()
+
+	var id *int32
+	if contract := ctx.Generic("contract").(*flags.Address); contract.IsSet {
+		cs := chain.GetContractState(contract.Uint160())
+		if cs == nil {
+			return cli.NewExitError(fmt.Errorf("contract %s not found", contract.Uint160().StringLE()), 1)
+		}
+		id = &cs.ID
+	}
+
+	var outStream = os.Stdout
+	if out := ctx.String("out"); out != "" {
+		outStream, err = os.Create(out)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+	defer outStream.Close()
+	bufOut := bufio.NewWriter(outStream)
+	enc := json.NewEncoder(bufOut)
+
+	var (
+		hashes = make(map[int32]*util.Uint160)
+		encErr error
+	)
+	err = chain.GetStateDiff(uint32(ctx.Uint("from")), uint32(ctx.Uint("to")), id, func(d *core.StorageDiff) bool {
+		h, ok := hashes[d.ID]
+		if !ok {
+			if u, err := chain.GetContractScriptHash(d.ID); err == nil {
+				h = &u
+			}
+			hashes[d.ID] = h
+		}
+		item := stateDiffItem{
+			State:    "Changed",
+			ID:       d.ID,
+			Contract: h,
+			Key:      d.Key,
+			Old:      d.Old,
+			New:      d.New,
+		}
+		switch {
+		case d.Old == nil:
+			item.State = "Added"
+		case d.New == nil:
+			item.State = "Deleted"
+		}
+		encErr = enc.Encode(item)
+		return encErr == nil
+	})
+	if err == nil {
+		err = encErr
+	}
+	if err == nil {
+		err = bufOut.Flush()
+	}
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get state diff: %w", err), 1)
+	}
+	return nil
+}
+
+func unlockDB(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	cfg, err := options.GetConfigFromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	err = storage.Unlock(cfg.ApplicationConfiguration.DBConfiguration)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't unlock DB: %w", err), 1)
+	}
+	fmt.Fprintln(ctx.App.Writer, "Stale DB lock data removed.")
+	return nil
+}
+
 func restoreDB(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -201,6 +476,12 @@ func restoreDB(ctx *cli.Context) error {
 		defer func() { _ = logCloser() }()
 	}
 	count := uint32(ctx.Uint("count"))
+	progress := uint32(ctx.Uint("progress"))
+	format, err := chaindump.ParseFormat(ctx.String("format"))
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	incremental := ctx.Bool("incremental")
 
 	var inStream = os.Stdin
 	if in := ctx.String("in"); in != "" {
@@ -208,16 +489,20 @@ func restoreDB(ctx *cli.Context) error {
 		if err != nil {
 			return cli.NewExitError(err, 1)
 		}
+		// neo-cli stores the start index in chain.<start>.acc files.
+		if _, ok := chaindump.ACCFileStart(in); ok && format == chaindump.FormatACC {
+			incremental = true
+		}
 	}
 	defer inStream.Close()
-	reader := io.NewBinReaderFromIO(inStream)
+	reader := io.NewBinReaderFromIO(bufio.NewReader(inStream))
 
 	dumpDir := ctx.String("dump")
 	if dumpDir != "" {
 		cfg.ProtocolConfiguration.SaveStorageBatch = true
 	}
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log, ctx.Duration(waitForLockFlag.Name))
 	if err != nil {
 		return err
 	}
@@ -228,7 +513,7 @@ func restoreDB(ctx *cli.Context) error {
 	}()
 
 	var start uint32
-	if ctx.Bool("incremental") {
+	if incremental {
 		start = reader.ReadU32LE()
 		if chain.BlockHeight()+1 < start {
 			return cli.NewExitError(fmt.Errorf("expected height: %d, dump starts at %d",
@@ -255,7 +540,8 @@ func restoreDB(ctx *cli.Context) error {
 		zap.Uint32("start", start),
 		zap.Uint32("height", chain.BlockHeight()),
 		zap.Uint32("skip", skip),
-		zap.Uint32("count", count))
+		zap.Uint32("count", count),
+		zap.Stringer("format", format))
 
 	gctx := newGraceContext()
 	var lastIndex uint32
@@ -295,6 +581,21 @@ func restoreDB(ctx *cli.Context) error {
 		}
 	}
 
+	if progress != 0 {
+		var (
+			restored uint32
+			next     = f
+		)
+		f = func(b *block.Block) error {
+			restored++
+			if restored%progress == 0 || restored == count {
+				log.Info("restored blocks", zap.Uint32("count", restored), zap.Uint32("total", count),
+					zap.Uint32("index", b.Index))
+			}
+			return next(b)
+		}
+	}
+
 	err = chaindump.Restore(chain, reader, skip, count, f)
 	if err != nil {
 		return cli.NewExitError(err, 1)
@@ -367,7 +668,6 @@ func mkP2PNotary(config config.P2PNotary, chain *core.Blockchain, serv *network.
 		return nil, fmt.Errorf("failed to create Notary module: %w", err)
 	}
 	serv.AddService(n)
-	chain.SetNotary(n)
 	return n, nil
 }
 
@@ -393,7 +693,7 @@ func startServer(ctx *cli.Context) error {
 
 	serverConfig := network.NewServerConfig(cfg)
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log)
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, log, ctx.Duration(waitForLockFlag.Name))
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -428,6 +728,9 @@ func startServer(ctx *cli.Context) error {
 	}
 	errChan := make(chan error)
 	rpcServer := rpcsrv.New(chain, cfg.ApplicationConfiguration.RPC, serv, oracleSrv, log, errChan)
+	if p2pNotary != nil {
+		rpcServer.SetNotaryHandler(p2pNotary)
+	}
 	serv.AddService(&rpcServer)
 
 	go serv.Start(errChan)
@@ -472,6 +775,9 @@ Main:
 				serv.DelService(&rpcServer)
 				rpcServer.Shutdown()
 				rpcServer = rpcsrv.New(chain, cfgnew.ApplicationConfiguration.RPC, serv, oracleSrv, log, errChan)
+				if p2pNotary != nil {
+					rpcServer.SetNotaryHandler(p2pNotary)
+				}
 				serv.AddService(&rpcServer)
 				if !cfgnew.ApplicationConfiguration.RPC.StartWhenSynchronized || serv.IsInSync() {
 					rpcServer.Start()
@@ -502,7 +808,7 @@ Main:
 				}
 				if p2pNotary != nil {
 					serv.DelService(p2pNotary)
-					chain.SetNotary(nil)
+					rpcServer.SetNotaryHandler(nil)
 					p2pNotary.Shutdown()
 				}
 				p2pNotary, err = mkP2PNotary(cfgnew.ApplicationConfiguration.P2PNotary, chain, serv, log)
@@ -510,11 +816,13 @@ Main:
 					log.Error("failed to create notary service", zap.Error(err))
 					break // Keep going.
 				}
-				if p2pNotary != nil && serv.IsInSync() {
-					p2pNotary.Start()
+				if p2pNotary != nil {
+					rpcServer.SetNotaryHandler(p2pNotary)
+					if serv.IsInSync() {
+						p2pNotary.Start()
+					}
 				}
 				serv.DelExtensibleService(sr, stateroot.Category)
-				srMod.SetUpdateValidatorsCallback(nil)
 				sr.Shutdown()
 				sr, err = stateroot.New(cfgnew.ApplicationConfiguration.StateRoot, srMod, log, chain, serv.BroadcastExtensible)
 				if err != nil {
@@ -572,9 +880,10 @@ func configureAddresses(cfg *config.ApplicationConfiguration) {
 	}
 }
 
-// initBlockChain initializes BlockChain with preselected DB.
-func initBlockChain(cfg config.Config, log *zap.Logger) (*core.Blockchain, error) {
-	store, err := storage.NewStore(cfg.ApplicationConfiguration.DBConfiguration)
+// initBlockChain initializes BlockChain with preselected DB, waiting for the
+// DB lock to be released by another process for the given time.
+func initBlockChain(cfg config.Config, log *zap.Logger, lockWait time.Duration) (*core.Blockchain, error) {
+	store, err := storage.NewStoreWithLockWait(cfg.ApplicationConfiguration.DBConfiguration, lockWait)
 	if err != nil {
 		return nil, cli.NewExitError(fmt.Errorf("could not initialize storage: %w", err), 1)
 	}
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"go.uber.org/zap/zapcore"
@@ -107,11 +108,11 @@ func TestInitBCWithMetrics(t *testing.T) {
 	})
 
 	t.Run("bad store", func(t *testing.T) {
-		_, _, _, err = initBCWithMetrics(config.Config{}, logger)
+		_, _, _, err = initBCWithMetrics(config.Config{}, logger, 0)
 		require.Error(t, err)
 	})
 
-	chain, prometheus, pprof, err := initBCWithMetrics(cfg, logger)
+	chain, prometheus, pprof, err := initBCWithMetrics(cfg, logger, 0)
 	require.NoError(t, err)
 	t.Cleanup(func() {
 		chain.Close()
@@ -159,6 +160,34 @@ func TestDumpDB(t *testing.T) {
 	})
 }
 
+func TestPruneDB(t *testing.T) {
+	d := t.TempDir()
+	err := os.Chdir(d)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, os.Chdir(serverTestWD)) })
+	newCtx := func(height int) *cli.Context {
+		set := flag.NewFlagSet("flagSet", flag.ExitOnError)
+		set.String("config-path", filepath.Join(serverTestWD, "..", "..", "config"), "")
+		set.Bool("privnet", true, "")
+		set.Bool("debug", true, "")
+		set.Int("height", 0, "")
+		if height >= 0 {
+			require.NoError(t, set.Parse([]string{"--height", strconv.Itoa(height)}))
+		}
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+
+	t.Run("no height", func(t *testing.T) {
+		require.Error(t, pruneDB(newCtx(-1)))
+	})
+	t.Run("too high", func(t *testing.T) {
+		require.Error(t, pruneDB(newCtx(10)))
+	})
+	t.Run("positive", func(t *testing.T) {
+		require.NoError(t, pruneDB(newCtx(0)))
+	})
+}
+
 func TestRestoreDB(t *testing.T) {
 	d := t.TempDir()
 	testDump := "file1.acc"
@@ -336,7 +365,7 @@ func TestConfigureAddresses(t *testing.T) {
 
 func TestInitBlockChain(t *testing.T) {
 	t.Run("bad storage", func(t *testing.T) {
-		_, err := initBlockChain(config.Config{}, nil)
+		_, err := initBlockChain(config.Config{}, nil, 0)
 		require.Error(t, err)
 	})
 
@@ -347,7 +376,7 @@ func TestInitBlockChain(t *testing.T) {
 					Type: dbconfig.InMemoryDB,
 				},
 			},
-		}, nil)
+		}, nil, 0)
 		require.Error(t, err)
 	})
 }
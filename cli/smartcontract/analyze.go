@@ -0,0 +1,91 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/analysis"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/urfave/cli"
+)
+
+func analyzeContract(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	var threshold *analysis.Severity
+	if s := ctx.String("severity"); s != "" {
+		sev, err := analysis.ParseSeverity(s)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		threshold = &sev
+	}
+	nf, _, err := readNEFFile(ctx.String("in"))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read NEF file: %w", err), 1)
+	}
+	var m *manifest.Manifest
+	if mPath := ctx.String("manifest"); mPath != "" {
+		m, _, err = readManifest(mPath, util.Uint160{})
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't read contract manifest: %w", err), 1)
+		}
+	}
+	var di *compiler.DebugInfo
+	if dPath := ctx.String("debug"); dPath != "" {
+		data, err := os.ReadFile(dPath)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't read debug info: %w", err), 1)
+		}
+		di = new(compiler.DebugInfo)
+		if err := json.Unmarshal(data, di); err != nil {
+			return cli.NewExitError(fmt.Errorf("can't parse debug info: %w", err), 1)
+		}
+	}
+	findings, err := analysis.Analyze(nf, analysis.Options{
+		Manifest: m,
+		Syscalls: knownSyscalls(),
+	})
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("analysis failed: %w", err), 1)
+	}
+	var failed int
+	for _, f := range findings {
+		if pos := di.SourcePosition(f.Offset); pos != "" {
+			fmt.Fprintf(ctx.App.Writer, "%s (%s)\n", f, pos)
+		} else {
+			fmt.Fprintln(ctx.App.Writer, f)
+		}
+		if threshold != nil && f.Severity >= *threshold {
+			failed++
+		}
+	}
+	if failed != 0 {
+		return cli.NewExitError(fmt.Errorf("%d finding(s) with %s or higher severity", failed, *threshold), 1)
+	}
+	return nil
+}
+
+// knownSyscalls returns a function resolving syscall IDs using the system
+// interops table of this NeoGo version.
+func knownSyscalls() func(uint32) (analysis.SyscallInfo, bool) {
+	fs := core.SystemInterops()
+	syscalls := make(map[uint32]analysis.SyscallInfo, len(fs))
+	for _, f := range fs {
+		syscalls[f.ID] = analysis.SyscallInfo{
+			Name:          f.Name,
+			RequiredFlags: f.RequiredFlags,
+			ParamCount:    f.ParamCount,
+		}
+	}
+	return func(id uint32) (analysis.SyscallInfo, bool) {
+		info, ok := syscalls[id]
+		return info, ok
+	}
+}
@@ -96,6 +96,60 @@ func TestCalcHash(t *testing.T) {
 	})
 }
 
+func TestContractManifestDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := testcli.NewExecutor(t, false)
+
+	oldPath := "./testdata/verify.manifest.json"
+	manifestBytes, err := os.ReadFile(oldPath)
+	require.NoError(t, err)
+	writeManifest := func(t *testing.T, m *manifest.Manifest) string {
+		p := filepath.Join(tmpDir, "new.manifest.json")
+		data, err := json.Marshal(m)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(p, data, os.ModePerm))
+		return p
+	}
+	readManifest := func(t *testing.T) *manifest.Manifest {
+		m := new(manifest.Manifest)
+		require.NoError(t, json.Unmarshal(manifestBytes, m))
+		return m
+	}
+
+	cmd := []string{"neo-go", "contract", "manifest-diff"}
+	t.Run("missing arguments", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, oldPath)...)
+	})
+	t.Run("invalid manifest path", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, oldPath, "./testdata/verify.manifest123")...)
+	})
+	t.Run("no changes", func(t *testing.T) {
+		e.Run(t, append(cmd, oldPath, oldPath)...)
+		e.CheckNextLine(t, "No changes")
+		e.CheckEOF(t)
+	})
+	t.Run("non-breaking", func(t *testing.T) {
+		m := readManifest(t)
+		m.ABI.Events = append(m.ABI.Events, manifest.Event{Name: "Something", Parameters: []manifest.Parameter{}})
+		e.Run(t, append(cmd, oldPath, writeManifest(t, m))...)
+		e.CheckNextLine(t, "^added event Something$")
+		e.CheckEOF(t)
+	})
+	t.Run("breaking", func(t *testing.T) {
+		m := readManifest(t)
+		name := m.ABI.Methods[0].Name
+		m.ABI.Methods = m.ABI.Methods[1:]
+		newPath := writeManifest(t, m)
+		e.RunWithError(t, append(cmd, oldPath, newPath)...)
+		e.CheckNextLine(t, `^\[breaking\] removed method `+name+"/")
+		e.CheckEOF(t)
+
+		e.Run(t, append(cmd, "--allow-breaking", oldPath, newPath)...)
+		e.CheckNextLine(t, `^\[breaking\] removed method `+name+"/")
+		e.CheckEOF(t)
+	})
+}
+
 func TestContractBindings(t *testing.T) {
 	// For proper nef generation.
 	config.Version = "v0.98.1-test"
@@ -287,6 +341,12 @@ func TestContractInitAndCompile(t *testing.T) {
 	t.Run("output hex script with --verbose", func(t *testing.T) {
 		e.Run(t, append(cmd, "--verbose")...)
 		e.CheckNextLine(t, "^[0-9a-hA-H]+$")
+		e.CheckNextLine(t, `^Script size: \d+ bytes \(\d+ without optimizations, \d+ functions inlined\)$`)
+	})
+	t.Run("output hex script with --verbose and --no-optimize", func(t *testing.T) {
+		e.Run(t, append(cmd, "--verbose", "--no-optimize")...)
+		e.CheckNextLine(t, "^[0-9a-hA-H]+$")
+		e.CheckEOF(t)
 	})
 }
 
@@ -699,6 +759,43 @@ func TestComlileAndInvokeFunction(t *testing.T) {
 	}
 	checkGetValueOut("on create|sub create")
 
+	t.Run("diagnostics", func(t *testing.T) {
+		e.Run(t, "neo-go", "contract", "testinvokefunction",
+			"--rpc-endpoint", "http://"+e.RPC.Addr, "--diagnostics",
+			h.StringLE(), "getValue")
+		res := new(result.Invoke)
+		require.NoError(t, json.Unmarshal(e.Out.Bytes(), res))
+		require.Equal(t, vmstate.Halt.String(), res.State, res.FaultException)
+		require.NotNil(t, res.Diagnostics)
+		require.NotNil(t, res.Diagnostics.GasUsage)
+		require.Contains(t, res.Diagnostics.GasUsage.Syscalls, "System.Storage.Get")
+
+		e.RunWithError(t, "neo-go", "contract", "testinvokefunction",
+			"--rpc-endpoint", "http://"+e.RPC.Addr, "--diagnostics",
+			"--historic", "1", h.StringLE(), "getValue")
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		cmd := []string{"neo-go", "contract", "testinvokefunction",
+			"--rpc-endpoint", "http://" + e.RPC.Addr}
+		t.Run("invalid condition", func(t *testing.T) {
+			e.RunWithError(t, append(cmd, "--expect", "stack[0]", h.StringLE(), "getValue")...)
+		})
+		t.Run("good", func(t *testing.T) {
+			e.Run(t, append(cmd, "--expect", "state=HALT", "--expect", "stack.len=1",
+				"--expect", "stack[0].string=on create|sub create", h.StringLE(), "getValue")...)
+			checkGetValueOut("on create|sub create")
+		})
+		t.Run("not met", func(t *testing.T) {
+			e.RunWithError(t, append(cmd, "--expect", "state=HALT",
+				"--expect", "stack[0].string=something else", h.StringLE(), "getValue")...)
+		})
+		t.Run("fault expected", func(t *testing.T) {
+			e.Run(t, append(cmd, "--expect", "state=FAULT", h.StringLE(), "fail")...)
+			e.RunWithError(t, append(cmd, "--expect", "state=HALT", h.StringLE(), "fail")...)
+		})
+	})
+
 	// deploy verification contract
 	hVerify := deployVerifyContract(t, e)
 
@@ -766,6 +863,31 @@ func TestComlileAndInvokeFunction(t *testing.T) {
 			e.Run(t, append(cmd, "--force", h.StringLE(), "fail")...)
 		})
 
+		t.Run("await", func(t *testing.T) {
+			t.Run("expect without await", func(t *testing.T) {
+				e.RunWithError(t, append(cmd, "--force", "--expect", "state=HALT", h.StringLE(), "getValue")...)
+			})
+			t.Run("await with out", func(t *testing.T) {
+				e.In.WriteString("one\r")
+				e.RunWithError(t, append(cmd, "--await", "--out", filepath.Join(t.TempDir(), "tx.json"),
+					h.StringLE(), "getValue")...)
+			})
+			t.Run("good", func(t *testing.T) {
+				e.In.WriteString("one\r")
+				e.Run(t, append(cmd, "--force", "--await", "--expect", "state=HALT",
+					"--expect", "stack[0].string=on create|sub create", h.StringLE(), "getValue")...)
+				e.CheckTxPersisted(t)
+				aer := new(state.AppExecResult)
+				require.NoError(t, json.Unmarshal(e.Out.Bytes(), aer))
+				require.Equal(t, vmstate.Halt, aer.VMState)
+			})
+			t.Run("not met", func(t *testing.T) {
+				e.In.WriteString("one\r")
+				e.RunWithError(t, append(cmd, "--force", "--await",
+					"--expect", "notifications[Transfer].count=1", h.StringLE(), "getValue")...)
+			})
+		})
+
 		t.Run("cosigner is deployed contract", func(t *testing.T) {
 			e.In.WriteString("one\r")
 			e.In.WriteString("y\r")
@@ -980,6 +1102,51 @@ func TestContractInspect(t *testing.T) {
 	})
 }
 
+func TestContractAnalyze(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+
+	// For proper nef generation.
+	config.Version = "0.90.0-test"
+	const srcPath = "testdata/deploy/main.go"
+	tmpDir := t.TempDir()
+
+	nefName := filepath.Join(tmpDir, "deploy.nef")
+	manifestName := filepath.Join(tmpDir, "deploy.manifest.json")
+	debugName := filepath.Join(tmpDir, "deploy.debug.json")
+	e.Run(t, "neo-go", "contract", "compile",
+		"--in", srcPath,
+		"--config", "testdata/deploy/neo-go.yml",
+		"--out", nefName, "--manifest", manifestName, "--debug", debugName)
+
+	cmd := []string{"neo-go", "contract", "analyze"}
+	t.Run("missing input", func(t *testing.T) {
+		e.RunWithError(t, cmd...)
+		e.RunWithError(t, append(cmd, "--in", filepath.Join(tmpDir, "not.exists"))...)
+	})
+	t.Run("invalid arguments", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, "--in", nefName, "something")...)
+		e.RunWithError(t, append(cmd, "--in", nefName, "--severity", "fatal")...)
+		e.RunWithError(t, append(cmd, "--in", nefName, "--manifest", filepath.Join(tmpDir, "not.exists"))...)
+		e.RunWithError(t, append(cmd, "--in", nefName, "--debug", filepath.Join(tmpDir, "not.exists"))...)
+	})
+	t.Run("good", func(t *testing.T) {
+		e.Run(t, append(cmd, "--in", nefName, "--manifest", manifestName, "--debug", debugName, "--severity", "error")...)
+	})
+	t.Run("broken", func(t *testing.T) {
+		// RET, PUSH1, RET: the last two instructions are unreachable.
+		nf, err := nef.NewFile([]byte{0x40, 0x11, 0x40})
+		require.NoError(t, err)
+		b, err := nf.Bytes()
+		require.NoError(t, err)
+		brokenName := filepath.Join(tmpDir, "broken.nef")
+		require.NoError(t, os.WriteFile(brokenName, b, os.ModePerm))
+
+		e.Run(t, append(cmd, "--in", brokenName, "--severity", "error")...)
+		require.True(t, strings.Contains(e.Out.String(), "unreachable"))
+		e.RunWithError(t, append(cmd, "--in", brokenName, "--severity", "warning")...)
+	})
+}
+
 func TestCompileExamples(t *testing.T) {
 	tmpDir := t.TempDir()
 	const examplePath = "../../examples"
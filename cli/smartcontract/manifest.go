@@ -73,6 +73,34 @@ func manifestAddGroup(ctx *cli.Context) error {
 	return nil
 }
 
+func manifestDiff(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 2 {
+		return cli.NewExitError("expected old and new manifest files", 1)
+	}
+	oldM, _, err := readManifest(args[0], util.Uint160{})
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read old manifest: %w", err), 1)
+	}
+	newM, _, err := readManifest(args[1], util.Uint160{})
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read new manifest: %w", err), 1)
+	}
+
+	r := manifest.Diff(oldM, newM)
+	if len(r.Changes) == 0 {
+		fmt.Fprintln(ctx.App.Writer, "No changes")
+		return nil
+	}
+	for _, c := range r.Changes {
+		fmt.Fprintln(ctx.App.Writer, c.String())
+	}
+	if r.IsBreaking() && !ctx.Bool("allow-breaking") {
+		return cli.NewExitError("manifest has breaking changes", 1)
+	}
+	return nil
+}
+
 func readNEFFile(filename string) (*nef.File, []byte, error) {
 	if len(filename) == 0 {
 		return nil, nil, errors.New("no nef file was provided")
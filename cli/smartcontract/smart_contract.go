@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/expect"
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/cli/input"
 	"github.com/nspcc-dev/neo-go/cli/options"
@@ -18,6 +19,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
@@ -47,6 +49,7 @@ var (
 	errNoScriptHash           = errors.New("no smart contract hash was provided, specify one as the first argument")
 	errNoSmartContractName    = errors.New("no name was provided, specify the '--name or -n' flag")
 	errFileExist              = errors.New("A file with given smart-contract name already exists")
+	errHistoricDiagnostics    = errors.New("--diagnostics can't be used with --historic")
 
 	walletFlag = cli.StringFlag{
 		Name:  "wallet, w",
@@ -60,6 +63,10 @@ var (
 		Name:  addressFlagName,
 		Usage: "address to use as transaction signee (and gas source)",
 	}
+	diagnosticsFlag = cli.BoolFlag{
+		Name:  "diagnostics",
+		Usage: "Request diagnostic data (invocation tree, storage changes and GAS consumption breakdown)",
+	}
 )
 
 // ModVersion contains `pkg/interop` module version
@@ -94,9 +101,10 @@ func NewCommands() []cli.Command {
 			Usage: "Input location of the .nef file that needs to be invoked",
 		},
 		options.Historic,
+		diagnosticsFlag,
 	}
 	testInvokeScriptFlags = append(testInvokeScriptFlags, options.RPC...)
-	testInvokeFunctionFlags := []cli.Flag{options.Historic}
+	testInvokeFunctionFlags := []cli.Flag{options.Historic, diagnosticsFlag, expect.Flag}
 	testInvokeFunctionFlags = append(testInvokeFunctionFlags, options.RPC...)
 	invokeFunctionFlags := []cli.Flag{
 		walletFlag,
@@ -106,6 +114,7 @@ func NewCommands() []cli.Command {
 		txctx.SysGasFlag,
 		txctx.OutFlag,
 		txctx.ForceFlag,
+		txctx.VerifyParamFlag,
 	}
 	invokeFunctionFlags = append(invokeFunctionFlags, options.RPC...)
 	deployFlags := append(invokeFunctionFlags, []cli.Flag{
@@ -118,6 +127,7 @@ func NewCommands() []cli.Command {
 			Usage: "Manifest input file (*.manifest.json)",
 		},
 	}...)
+	invokeFunctionFlags = append([]cli.Flag{txctx.AwaitFlag, expect.Flag}, invokeFunctionFlags...)
 	return []cli.Command{{
 		Name:  "contract",
 		Usage: "compile - debug - deploy smart contracts",
@@ -125,7 +135,7 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "compile",
 				Usage:     "compile a smart contract to a .nef file",
-				UsageText: "neo-go contract compile -i path [-o nef] [-v] [-d] [-m manifest] [-c yaml] [--bindings file] [--no-standards] [--no-events] [--no-permissions]",
+				UsageText: "neo-go contract compile -i path [-o nef] [-v] [-d] [-m manifest] [-c yaml] [--bindings file] [--source-url url] [--build-info file] [--verify-against nef] [--no-standards] [--no-events] [--no-permissions] [--no-optimize]",
 				Action:    contractCompile,
 				Flags: []cli.Flag{
 					cli.StringFlag{
@@ -157,7 +167,7 @@ func NewCommands() []cli.Command {
 						Usage: "do not check compliance with supported standards",
 					},
 					cli.BoolFlag{
-						Name:  "no-events",
+						Name:  "no-events, no-eventcheck",
 						Usage: "do not check emitted events with the manifest",
 					},
 					cli.BoolFlag{
@@ -168,6 +178,22 @@ func NewCommands() []cli.Command {
 						Name:  "bindings",
 						Usage: "output file for smart-contract bindings configuration",
 					},
+					cli.StringFlag{
+						Name:  "source-url",
+						Usage: "source code URL to be written into NEF (overrides the one from configuration file)",
+					},
+					cli.StringFlag{
+						Name:  "build-info",
+						Usage: "emit build info (compiler and Go versions, source file hashes) into separate file",
+					},
+					cli.StringFlag{
+						Name:  "verify-against",
+						Usage: "compile the contract and compare the result with the given NEF file instead of saving it",
+					},
+					cli.BoolFlag{
+						Name:  "no-optimize",
+						Usage: "do not inline small functions",
+					},
 				},
 			},
 			{
@@ -186,13 +212,17 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "invokefunction",
 				Usage:     "invoke deployed contract on the blockchain",
-				UsageText: "neo-go contract invokefunction -r endpoint -w wallet [-a address] [-g gas] [-e sysgas] [--out file] [--force] scripthash [method] [arguments...] [--] [signers...]",
+				UsageText: "neo-go contract invokefunction -r endpoint -w wallet [-a address] [-g gas] [-e sysgas] [--out file] [--force] [--await [--expect condition...]] scripthash [method] [arguments...] [--] [signers...]",
 				Description: `Executes given (as a script hash) deployed script with the given method,
    arguments and signers. Sender is included in the list of signers by default
    with None witness scope. If you'd like to change default sender's scope, 
    specify it via signers parameter. See testinvokefunction documentation for 
    the details about parameters. It differs from testinvokefunction in that this
    command sends an invocation transaction to the network.
+
+   With --await flag the command waits for the transaction to be accepted and
+   prints its execution result (application log), --expect conditions (see
+   testinvokefunction documentation) are checked against it then.
 `,
 				Action: invokeFunction,
 				Flags:  invokeFunctionFlags,
@@ -200,7 +230,7 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "testinvokefunction",
 				Usage:     "invoke deployed contract on the blockchain (test mode)",
-				UsageText: "neo-go contract testinvokefunction -r endpoint [--historic index/hash] scripthash [method] [arguments...] [--] [signers...]",
+				UsageText: "neo-go contract testinvokefunction -r endpoint [--historic index/hash] [--diagnostics] [--expect condition...] scripthash [method] [arguments...] [--] [signers...]",
 				Description: `Executes given (as a script hash) deployed script with the given method,
    arguments and signers (sender is not included by default). If no method is given
    "" is passed to the script, if no arguments are given, an empty array is 
@@ -208,11 +238,15 @@ func NewCommands() []cli.Command {
    the first one of them is treated as a sender. All of the given arguments are 
    encapsulated into array before invoking the script. The script thus should 
    follow the regular convention of smart contract arguments (method string and 
-   an array of other arguments).
+   an array of other arguments). With --diagnostics flag the result also
+   contains invocation tree, storage changes and GAS consumption breakdown
+   by opcode price class and syscall (it can't be used with --historic).
 
 ` + cmdargs.ParamsParsingDoc + `
 
 ` + cmdargs.SignersParsingDoc + `
+
+` + expect.ConditionsDoc + `
 `,
 				Action: testInvokeFunction,
 				Flags:  testInvokeFunctionFlags,
@@ -220,10 +254,10 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "testinvokescript",
 				Usage:     "Invoke compiled AVM code in NEF format on the blockchain (test mode, not creating a transaction for it)",
-				UsageText: "neo-go contract testinvokescript -r endpoint -i input.nef [--historic index/hash] [signers...]",
+				UsageText: "neo-go contract testinvokescript -r endpoint -i input.nef [--historic index/hash] [--diagnostics] [signers...]",
 				Description: `Executes given compiled AVM instructions in NEF format with the given set of
    signers not included sender by default. See testinvokefunction documentation 
-   for the details about parameters.
+   for the details about parameters and --diagnostics flag.
 `,
 				Action: testInvokeScript,
 				Flags:  testInvokeScriptFlags,
@@ -260,6 +294,39 @@ func NewCommands() []cli.Command {
 					},
 				},
 			},
+			{
+				Name:      "analyze",
+				Usage:     "performs static analysis of the compiled contract",
+				UsageText: "neo-go contract analyze -i nef [-m manifest] [-d debug] [--severity level]",
+				Description: `Decodes the contract script and reports problems found in it: unreachable
+   code, stack imbalance along linear paths, syscalls and method tokens
+   requiring call flags not available to safe methods, CALLT instructions
+   referencing missing tokens or having not enough parameters and oversized
+   pushes. Each finding is printed with its script offset and (if debug info
+   is provided) the source position. Manifest is needed for method-specific
+   checks. If --severity is given (info, warning or error), the command fails
+   when there is at least one finding of this or higher severity.
+`,
+				Action: analyzeContract,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "in, i",
+						Usage: "path to NEF file",
+					},
+					cli.StringFlag{
+						Name:  "manifest, m",
+						Usage: "path to manifest file",
+					},
+					cli.StringFlag{
+						Name:  "debug, d",
+						Usage: "path to debug info file",
+					},
+					cli.StringFlag{
+						Name:  "severity",
+						Usage: "minimum severity of findings that makes the command fail",
+					},
+				},
+			},
 			{
 				Name:      "calc-hash",
 				Usage:     "calculates hash of a contract after deployment",
@@ -280,6 +347,25 @@ func NewCommands() []cli.Command {
 					},
 				},
 			},
+			{
+				Name:      "manifest-diff",
+				Usage:     "compare contract manifests",
+				UsageText: "neo-go contract manifest-diff [--allow-breaking] old.manifest.json new.manifest.json",
+				Description: `Prints changes in methods, events, permissions and supported standards
+   between the old and the new version of the contract manifest. Changes
+   that can break existing callers (like removed methods or changed
+   parameter types) or contract behavior (like narrowed permissions) are
+   marked as breaking, the command exits with non-zero code if there are
+   any unless --allow-breaking is given.
+`,
+				Action: manifestDiff,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name:  "allow-breaking",
+						Usage: "don't fail if there are breaking changes",
+					},
+				},
+			},
 			{
 				Name:  "manifest",
 				Usage: "manifest-related commands",
@@ -407,13 +493,17 @@ func contractCompile(ctx *cli.Context) error {
 	o := &compiler.Options{
 		Outfile: ctx.String("out"),
 
-		DebugInfo:    debugFile,
-		ManifestFile: manifestFile,
-		BindingsFile: ctx.String("bindings"),
+		DebugInfo:     debugFile,
+		ManifestFile:  manifestFile,
+		BindingsFile:  ctx.String("bindings"),
+		BuildInfoFile: ctx.String("build-info"),
 
 		NoStandardCheck:    ctx.Bool("no-standards"),
 		NoEventsCheck:      ctx.Bool("no-events"),
 		NoPermissionsCheck: ctx.Bool("no-permissions"),
+
+		Optimize: !ctx.Bool("no-optimize"),
+		Stats:    new(compiler.OptimizationStats),
 	}
 
 	if len(confFile) != 0 {
@@ -432,6 +522,13 @@ func contractCompile(ctx *cli.Context) error {
 		o.SafeMethods = conf.SafeMethods
 		o.Overloads = conf.Overloads
 	}
+	if ctx.IsSet("source-url") {
+		o.SourceURL = ctx.String("source-url")
+	}
+
+	if verifyFile := ctx.String("verify-against"); verifyFile != "" {
+		return verifyCompiled(ctx, src, o, verifyFile)
+	}
 
 	result, err := compiler.CompileAndSave(src, o)
 	if err != nil {
@@ -439,11 +536,69 @@ func contractCompile(ctx *cli.Context) error {
 	}
 	if ctx.Bool("verbose") {
 		fmt.Fprintln(ctx.App.Writer, hex.EncodeToString(result))
+		if o.Optimize {
+			fmt.Fprintf(ctx.App.Writer, "Script size: %d bytes (%d without optimizations, %d functions inlined)\n",
+				o.Stats.Size, o.Stats.UnoptimizedSize, o.Stats.Inlined)
+		}
 	}
 
 	return nil
 }
 
+// verifyCompiled compiles the contract and compares the result with the
+// given NEF file section by section reporting the first divergent one.
+func verifyCompiled(ctx *cli.Context, src string, o *compiler.Options, nefFile string) error {
+	data, err := os.ReadFile(nefFile)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't read NEF file: %w", err), 1)
+	}
+	expected, err := nef.FileFromBytes(data)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("can't parse NEF file: %w", err), 1)
+	}
+	actual, _, err := compiler.CompileWithOptions(src, nil, o)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("error while trying to compile smart contract file: %w", err), 1)
+	}
+	if diff := nefDiff(&expected, actual); diff != "" {
+		return cli.NewExitError(fmt.Errorf("NEF mismatch: %s", diff), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "NEF matches, script hash: %s, checksum: %d\n",
+		hash.Hash160(actual.Script).StringLE(), actual.Checksum)
+	return nil
+}
+
+// nefDiff returns a description of the first divergent section of two NEF
+// files or an empty string if they're the same.
+func nefDiff(expected, actual *nef.File) string {
+	if expected.Compiler != actual.Compiler {
+		return fmt.Sprintf("compiler: expected %q, got %q", expected.Compiler, actual.Compiler)
+	}
+	if expected.Source != actual.Source {
+		return fmt.Sprintf("source: expected %q, got %q", expected.Source, actual.Source)
+	}
+	if len(expected.Tokens) != len(actual.Tokens) {
+		return fmt.Sprintf("tokens: expected %d, got %d", len(expected.Tokens), len(actual.Tokens))
+	}
+	for i := range expected.Tokens {
+		if expected.Tokens[i] != actual.Tokens[i] {
+			return fmt.Sprintf("tokens: token #%d differs", i)
+		}
+	}
+	for i := 0; i < len(expected.Script) && i < len(actual.Script); i++ {
+		if expected.Script[i] != actual.Script[i] {
+			return fmt.Sprintf("script: first difference at offset %d", i)
+		}
+	}
+	if len(expected.Script) != len(actual.Script) {
+		return fmt.Sprintf("script: expected length %d, got %d", len(expected.Script), len(actual.Script))
+	}
+	if expected.Checksum != actual.Checksum {
+		return fmt.Sprintf("checksum: expected %d, got %d", expected.Checksum, actual.Checksum)
+	}
+	return ""
+}
+
 func calcHash(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -557,6 +712,16 @@ func invokeWithArgs(ctx *cli.Context, acc *wallet.Account, wall *wallet.Wallet,
 		inv             *invoker.Invoker
 		act             *actor.Actor
 	)
+	conds, err := expect.FromContext(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if ctx.Bool(diagnosticsFlag.Name) && ctx.String(options.Historic.Name) != "" {
+		return cli.NewExitError(errHistoricDiagnostics, 1)
+	}
+	if signAndPush && len(conds) != 0 && !ctx.Bool(txctx.AwaitFlag.Name) {
+		return cli.NewExitError(fmt.Errorf("--%s requires --%s", expect.Flag.Name, txctx.AwaitFlag.Name), 1)
+	}
 	if signAndPush {
 		signersAccounts, err = cmdargs.GetSignersAccounts(acc, wall, cosigners, transaction.None)
 		if err != nil {
@@ -583,11 +748,21 @@ func invokeWithArgs(ctx *cli.Context, acc *wallet.Account, wall *wallet.Wallet,
 		}
 	}
 	out := ctx.String("out")
-	resp, err = inv.Call(script, operation, params...)
+	if ctx.Bool(diagnosticsFlag.Name) {
+		var ps []smartcontract.Parameter
+		ps, err = smartcontract.NewParametersFromValues(params...)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		resp, err = c.InvokeFunctionDiagnostics(script, operation, ps, cosigners)
+	} else {
+		resp, err = inv.Call(script, operation, params...)
+	}
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return options.NewInvokeError(err)
 	}
-	if resp.State != "HALT" {
+	// Expectations (if any) are to check the state in test mode.
+	if resp.State != "HALT" && (signAndPush || len(conds) == 0) {
 		errText := fmt.Sprintf("Warning: %s VM state returned from the RPC node: %s", resp.State, resp.FaultException)
 		if !signAndPush {
 			return cli.NewExitError(errText, 1)
@@ -611,6 +786,9 @@ func invokeWithArgs(ctx *cli.Context, acc *wallet.Account, wall *wallet.Wallet,
 		}
 
 		fmt.Fprintln(ctx.App.Writer, string(b))
+		if err := expect.Verify(conds, expect.FromInvoke(resp)); err != nil {
+			return cli.NewExitError(err, 1)
+		}
 		return nil
 	}
 	if len(resp.Script) == 0 {
@@ -620,7 +798,14 @@ func invokeWithArgs(ctx *cli.Context, acc *wallet.Account, wall *wallet.Wallet,
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("failed to create tx: %w", err), 1)
 	}
-	return txctx.SignAndSend(ctx, act, acc, tx)
+	res, err := txctx.SignAndAwait(ctx, act, acc, tx)
+	if err != nil || res == nil {
+		return err
+	}
+	if err := expect.Verify(conds, expect.FromExecution(&res.Execution)); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
 }
 
 func testInvokeScript(ctx *cli.Context) error {
@@ -646,14 +831,22 @@ func testInvokeScript(ctx *cli.Context) error {
 	gctx, cancel := options.GetTimeoutContext(ctx)
 	defer cancel()
 
-	_, inv, err := options.GetRPCWithInvoker(gctx, ctx, signers)
+	if ctx.Bool(diagnosticsFlag.Name) && ctx.String(options.Historic.Name) != "" {
+		return cli.NewExitError(errHistoricDiagnostics, 1)
+	}
+	c, inv, err := options.GetRPCWithInvoker(gctx, ctx, signers)
 	if err != nil {
 		return err
 	}
 
-	resp, err := inv.Run(nefFile.Script)
+	var resp *result.Invoke
+	if ctx.Bool(diagnosticsFlag.Name) {
+		resp, err = c.InvokeScriptDiagnostics(nefFile.Script, signers)
+	} else {
+		resp, err = inv.Run(nefFile.Script)
+	}
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return options.NewInvokeError(err)
 	}
 
 	b, err = json.MarshalIndent(resp, "", "  ")
@@ -738,6 +931,7 @@ func getAccFromContext(ctx *cli.Context) (*wallet.Account, *wallet.Wallet, error
 	if err != nil {
 		return nil, nil, err
 	}
+	cliwallet.WarnPermissions(ctx, wPath)
 	addrFlag := ctx.Generic("address").(*flags.Address)
 	if addrFlag.IsSet {
 		addr = addrFlag.Uint160()
@@ -754,10 +948,19 @@ func getUnlockedAccount(wall *wallet.Wallet, addr util.Uint160, pass *string) (*
 	if acc == nil {
 		return nil, fmt.Errorf("wallet contains no account for '%s'", address.Uint160ToString(addr))
 	}
+	if acc.IsWatchOnly() {
+		return nil, fmt.Errorf("account %s: %w", address.Uint160ToString(addr), wallet.ErrWatchOnly)
+	}
 
 	if acc.CanSign() {
 		return acc, nil
 	}
+	if acc.IsRemote() {
+		if err := cliwallet.ConnectRemote(acc); err != nil {
+			return nil, err
+		}
+		return acc, nil
+	}
 
 	if pass == nil {
 		rawPass, err := input.ReadPassword(
@@ -812,7 +1015,7 @@ func contractDeploy(ctx *cli.Context) error {
 		return err
 	} else if len(cosigners) == 0 {
 		cosigners = []transaction.Signer{{
-			Account: acc.Contract.ScriptHash(),
+			Account: acc.ScriptHash(),
 			Scopes:  transaction.CalledByEntry,
 		}}
 	}
@@ -4,11 +4,13 @@ import (
 	"encoding/hex"
 	"flag"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli"
 	"gopkg.in/yaml.v3"
@@ -137,3 +139,37 @@ func TestPermissionUnmarshalInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestNEFDiff(t *testing.T) {
+	newNEF := func() *nef.File {
+		f, err := nef.NewFile([]byte{1, 2, 3})
+		require.NoError(t, err)
+		f.Tokens = []nef.MethodToken{{Method: "a", ParamCount: 1}}
+		return f
+	}
+	expected := newNEF()
+	require.Equal(t, "", nefDiff(expected, newNEF()))
+
+	testCases := map[string]func(f *nef.File){
+		"compiler": func(f *nef.File) { f.Compiler = "other" },
+		"source":   func(f *nef.File) { f.Source = "https://example.com" },
+		"tokens: expected 1, got 0": func(f *nef.File) {
+			f.Tokens = nil
+		},
+		"tokens: token #0": func(f *nef.File) { f.Tokens[0].ParamCount = 2 },
+		"script: first difference at offset 1": func(f *nef.File) {
+			f.Script = []byte{1, 3, 3}
+		},
+		"script: expected length 3, got 4": func(f *nef.File) {
+			f.Script = []byte{1, 2, 3, 4}
+		},
+		"checksum": func(f *nef.File) { f.Checksum++ },
+	}
+	for prefix, change := range testCases {
+		t.Run(prefix, func(t *testing.T) {
+			actual := newNEF()
+			change(actual)
+			require.True(t, strings.HasPrefix(nefDiff(expected, actual), prefix))
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package verifyparam
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+)
+
+// Verify checks the password given.
+func Verify(password string) bool {
+	return password == "secret"
+}
+
+func OnNEP17Payment(from interop.Hash160, amount int, data interface{}) {
+}
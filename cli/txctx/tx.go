@@ -4,14 +4,20 @@ Package txctx contains helper functions that deal with transactions in CLI conte
 package txctx
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/cli/input"
 	"github.com/nspcc-dev/neo-go/cli/paramcontext"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/urfave/cli"
 )
@@ -37,23 +43,115 @@ var (
 		Name:  "force",
 		Usage: "Do not ask for a confirmation (and ignore errors)",
 	}
+	// MaxFeeFlag is a flag used to limit the total transaction fee.
+	MaxFeeFlag = flags.Fixed8Flag{
+		Name:  "max-fee",
+		Usage: "maximum total (system + network) fee of the transaction, no transaction is signed if it's exceeded (no limit by default)",
+	}
+	// AwaitFlag is a flag used to wait for the transaction to be included in a block.
+	AwaitFlag = cli.BoolFlag{
+		Name:  "await",
+		Usage: "wait for the transaction to be included in a block and print its execution result (limited by --timeout)",
+	}
+	// VerifyParamFlag is a flag used to provide verification parameters for
+	// deployed contract-based accounts.
+	VerifyParamFlag = cli.StringSliceFlag{
+		Name: "verify-param",
+		Usage: "verification parameter value for deployed contract-based accounts in 'name=value' form " +
+			"(can be repeated), values that are not provided are requested interactively",
+	}
 )
 
+// SendHooks are optional callbacks used by SignAndSendWithHooks.
+type SendHooks struct {
+	// BeforeSend is called with the signed transaction right before it's
+	// sent to the network, the transaction is not sent if it returns an
+	// error.
+	BeforeSend func(tx *transaction.Transaction) error
+	// SendFailed is called with the transaction and the sending error if the
+	// transaction can't be sent.
+	SendFailed func(tx *transaction.Transaction, err error)
+}
+
 // SignAndSend adds network and system fees to the provided transaction and
 // either sends it to the network (with a confirmation or --force flag) or saves
-// it into a file (given in the --out flag).
+// it into a file (given in the --out flag). If the --max-fee flag is set (and
+// supported by the command) and the total fee exceeds it, an error is returned
+// instead. Verification parameters of deployed
+// contract-based signers are taken from the --verify-param flag or requested
+// interactively (the latter is not done for --out, missing values are to be
+// added by other signers then).
 func SignAndSend(ctx *cli.Context, act *actor.Actor, acc *wallet.Account, tx *transaction.Transaction) error {
+	_, err := signAndAwait(ctx, act, acc, tx, SendHooks{})
+	return err
+}
+
+// SignAndSendWithHooks is similar to SignAndSend, but calls the given hooks
+// when the transaction is sent to the network (they're not called when it's
+// saved into a file).
+func SignAndSendWithHooks(ctx *cli.Context, act *actor.Actor, acc *wallet.Account, tx *transaction.Transaction, hooks SendHooks) error {
+	_, err := signAndAwait(ctx, act, acc, tx, hooks)
+	return err
+}
+
+// SignAndAwait is similar to SignAndSend, but if the --await flag is set (and
+// supported by the command), it also waits for the transaction to be accepted
+// and returns its execution result (nil is returned otherwise).
+func SignAndAwait(ctx *cli.Context, act *actor.Actor, acc *wallet.Account, tx *transaction.Transaction) (*state.AppExecResult, error) {
+	return signAndAwait(ctx, act, acc, tx, SendHooks{})
+}
+
+func signAndAwait(ctx *cli.Context, act *actor.Actor, acc *wallet.Account, tx *transaction.Transaction, hooks SendHooks) (*state.AppExecResult, error) {
 	var (
-		err    error
-		gas    = flags.Fixed8FromContext(ctx, "gas")
-		sysgas = flags.Fixed8FromContext(ctx, "sysgas")
-		ver    = act.GetVersion()
+		err     error
+		gas     = flags.Fixed8FromContext(ctx, "gas")
+		sysgas  = flags.Fixed8FromContext(ctx, "sysgas")
+		ver     = act.GetVersion()
+		outFile = ctx.String("out")
+		await   = ctx.Bool(AwaitFlag.Name)
+		recalc  bool
 	)
+	if await && outFile != "" {
+		return nil, cli.NewExitError(errors.New("--await can't be used with --out"), 1)
+	}
+
+	for i, s := range act.GetSigners() {
+		// Values can be added by other signers to the saved context.
+		params, err := GetVerifyParameters(ctx, s.Account.Address, s.Account.Contract, outFile == "")
+		if err != nil {
+			return nil, cli.NewExitError(err, 1)
+		}
+		if params == nil {
+			continue
+		}
+		if err := s.Account.SetVerifyParameters(params); err != nil {
+			return nil, cli.NewExitError(err, 1)
+		}
+		if inv, err := s.Account.DummyInvocationScript(); err == nil && i < len(tx.Scripts) {
+			tx.Scripts[i].InvocationScript = inv
+			recalc = true
+		}
+	}
+	if recalc { // Verification cost depends on parameter values.
+		tx.NetworkFee, err = act.CalculateNetworkFee(tx)
+		if err != nil {
+			return nil, cli.NewExitError(fmt.Errorf("failed to calculate network fee: %w", err), 1)
+		}
+	}
 
 	tx.SystemFee += int64(sysgas)
 	tx.NetworkFee += int64(gas)
 
-	if outFile := ctx.String("out"); outFile != "" {
+	// The flag is not available for all commands.
+	if maxFee, ok := ctx.Generic(MaxFeeFlag.Name).(*flags.Fixed8); ok && maxFee.Value != 0 {
+		fee := fixedn.Fixed8(tx.SystemFee + tx.NetworkFee)
+		if fee > maxFee.Value {
+			return nil, cli.NewExitError(fmt.Errorf("estimated fee %s GAS exceeds the maximum allowed %s GAS (--%s)",
+				fee, maxFee.Value, MaxFeeFlag.Name), 1)
+		}
+	}
+
+	if outFile != "" {
 		// Make a long-lived transaction, it's to be signed manually.
 		tx.ValidUntilBlock += (ver.Protocol.MaxValidUntilBlockIncrement - uint32(ver.Protocol.ValidatorsCount)) - 2
 		err = paramcontext.InitAndSave(ver.Protocol.Network, tx, acc, outFile)
@@ -62,18 +160,105 @@ func SignAndSend(ctx *cli.Context, act *actor.Actor, acc *wallet.Account, tx *tr
 			promptTime := time.Now()
 			err := input.ConfirmTx(ctx.App.Writer, tx)
 			if err != nil {
-				return cli.NewExitError(err, 1)
+				return nil, cli.NewExitError(err, 1)
 			}
 			waitTime := time.Since(promptTime)
 			// Compensate for confirmation waiting.
 			tx.ValidUntilBlock += uint32((waitTime.Milliseconds() / int64(ver.Protocol.MillisecondsPerBlock))) + 1
 		}
-		_, _, err = act.SignAndSend(tx)
+		err = sendTx(act, tx, hooks)
 	}
 	if err != nil {
-		return cli.NewExitError(err, 1)
+		return nil, cli.NewExitError(err, 1)
 	}
 
 	fmt.Fprintln(ctx.App.Writer, tx.Hash().StringLE())
-	return nil
+	if !await {
+		return nil, nil
+	}
+	res, err := act.Wait(tx.Hash(), tx.ValidUntilBlock, nil)
+	if err != nil {
+		return nil, cli.NewExitError(fmt.Errorf("failed to await transaction: %w", err), 1)
+	}
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return nil, cli.NewExitError(err, 1)
+	}
+	fmt.Fprintln(ctx.App.Writer, string(b))
+	return res, nil
+}
+
+// sendTx signs the given transaction and sends it to the network calling
+// the hooks.
+func sendTx(act *actor.Actor, tx *transaction.Transaction, hooks SendHooks) error {
+	if err := act.Sign(tx); err != nil {
+		return err
+	}
+	if hooks.BeforeSend != nil {
+		if err := hooks.BeforeSend(tx); err != nil {
+			return err
+		}
+	}
+	_, _, err := act.Send(tx)
+	if err != nil && hooks.SendFailed != nil {
+		hooks.SendFailed(tx, err)
+	}
+	return err
+}
+
+// GetVerifyParameters returns values of verification parameters for the given
+// deployed contract taken from the --verify-param flag or requested from the
+// user interactively (if prompt is true, otherwise they're left empty).
+// Values are returned in the contract parameters order, Signature ones are
+// left empty (they're to be signed). It returns nil if the contract is not
+// a deployed one or has no non-signature parameters.
+func GetVerifyParameters(ctx *cli.Context, addr string, ctr *wallet.Contract, prompt bool) ([]smartcontract.Parameter, error) {
+	if ctr == nil || !ctr.Deployed {
+		return nil, nil
+	}
+	var needed bool
+	for _, p := range ctr.Parameters {
+		if p.Type != smartcontract.SignatureType {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil, nil
+	}
+	values := make(map[string]string)
+	for _, s := range ctx.StringSlice(VerifyParamFlag.Name) {
+		kv := strings.SplitN(s, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid verification parameter %q, 'name=value' expected", s)
+		}
+		values[kv[0]] = kv[1]
+	}
+	res := make([]smartcontract.Parameter, len(ctr.Parameters))
+	for i, p := range ctr.Parameters {
+		res[i].Type = p.Type
+		if p.Type == smartcontract.SignatureType {
+			continue
+		}
+		val, ok := values[p.Name]
+		if !ok && !prompt {
+			continue
+		}
+		if !ok {
+			var err error
+			val, err = input.ReadLine(fmt.Sprintf("Enter %s value for '%s' verification parameter of %s > ", p.Type, p.Name, addr))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read '%s' parameter value: %w", p.Name, err)
+			}
+		}
+		if p.Type != smartcontract.AnyType { // Any values can have explicit type.
+			val = p.Type.String() + ":" + val
+		}
+		v, err := smartcontract.NewParameterFromString(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid '%s' parameter value: %w", p.Name, err)
+		}
+		res[i] = *v
+	}
+	return res, nil
 }
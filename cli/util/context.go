@@ -0,0 +1,60 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nspcc-dev/neo-go/cli/paramcontext"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/urfave/cli"
+)
+
+func convertContext(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) == 0 {
+		return cli.NewExitError("missing input file", 1)
+	} else if len(args) > 1 {
+		return cli.NewExitError("only one input file is accepted", 1)
+	}
+	netArg := ctx.String("network")
+	if netArg == "" {
+		return cli.NewExitError(errors.New("missing target network (--network)"), 1)
+	}
+	magic, err := strconv.ParseUint(netArg, 0, 32)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid network magic: %w", err), 1)
+	}
+	net := netmode.Magic(magic)
+
+	pc, err := paramcontext.Read(args[0])
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	if _, ok := pc.Verifiable.(*transaction.Transaction); !ok {
+		return cli.NewExitError("verifiable item is not a transaction", 1)
+	}
+	if pc.Network == net {
+		return cli.NewExitError(fmt.Errorf("context already belongs to %s (%d)", net, magic), 1)
+	}
+	from := pc.Network
+	removed := pc.SetNetwork(net)
+	fmt.Fprintf(ctx.App.ErrWriter, "Context is converted from %s (%d) to %s (%d), %d signature(s) removed\n",
+		from, uint32(from), net, magic, removed)
+
+	out := ctx.String("out")
+	if out == "" {
+		txt, err := json.MarshalIndent(pc, " ", "     ")
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't display resulting context: %w", err), 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(txt))
+		return nil
+	}
+	if err := paramcontext.Save(pc, out); err != nil {
+		return cli.NewExitError(fmt.Errorf("can't save resulting context: %w", err), 1)
+	}
+	return nil
+}
@@ -37,6 +37,31 @@ func NewCommands() []cli.Command {
 					Action: sendTx,
 					Flags:  txDumpFlags,
 				},
+				{
+					Name:      "convert-context",
+					Usage:     "Convert transaction signing context to another network",
+					UsageText: "convert-context --network <magic> [--out <file.out>] <file.in>",
+					Description: `Moves the transaction signing context from the given file to another network
+   (given by its magic number, decimal or hex with 0x prefix), which is useful
+   for networks with identical contracts like testnet and a private network.
+   Transaction data is kept as is, but signatures are made for the
+   network-specific hash, so the ones made for the old network are removed and
+   should be added again with 'wallet sign'. The resulting context is printed
+   to the console or saved into the given file (which can be the same as input
+   one).
+`,
+					Action: convertContext,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "network",
+							Usage: "Target network magic",
+						},
+						cli.StringFlag{
+							Name:  "out",
+							Usage: "Output file for the resulting context",
+						},
+					},
+				},
 				{
 					Name:      "txdump",
 					Usage:     "Dump transaction stored in file",
@@ -44,6 +69,24 @@ func NewCommands() []cli.Command {
 					Action:    txDump,
 					Flags:     txDumpFlags,
 				},
+				{
+					Name:      "oracle-verify",
+					Usage:     "Check oracle response transaction against the original request",
+					UsageText: "oracle-verify -r <endpoint> --tx <hex>",
+					Description: `Fetches the request the given oracle response transaction is made for from
+   the native Oracle contract storage (so the request must not be processed
+   yet), recomputes the expected script, signers, witnesses and fees of the
+   response transaction using current Oracle nodes and Policy settings and
+   reports any mismatch found. Response data itself can't be checked.
+`,
+					Action: oracleVerify,
+					Flags: append([]cli.Flag{
+						cli.StringFlag{
+							Name:  "tx",
+							Usage: "hex-encoded oracle response transaction",
+						},
+					}, options.RPC...),
+				},
 			},
 		},
 	}
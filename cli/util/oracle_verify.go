@@ -0,0 +1,99 @@
+package util
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/options"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/oracle"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/policy"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/rolemgmt"
+	oraclesrv "github.com/nspcc-dev/neo-go/pkg/services/oracle"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/urfave/cli"
+)
+
+func oracleVerify(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	txHex := ctx.String("tx")
+	if txHex == "" {
+		return cli.NewExitError(errors.New("no transaction specified"), 1)
+	}
+	rawTx, err := hex.DecodeString(txHex)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid transaction hex: %w", err), 1)
+	}
+	tx, err := transaction.NewTransactionFromBytes(rawTx)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to decode transaction: %w", err), 1)
+	}
+	var resp *transaction.OracleResponse
+	for i := range tx.Attributes {
+		if tx.Attributes[i].Type == transaction.OracleResponseT {
+			resp = tx.Attributes[i].Value.(*transaction.OracleResponse)
+			break
+		}
+	}
+	if resp == nil {
+		return cli.NewExitError(errors.New("transaction has no oracle response attribute"), 1)
+	}
+
+	gctx, cancel := options.GetTimeoutContext(ctx)
+	defer cancel()
+
+	c, err := options.GetRPCClient(gctx, ctx)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to create RPC client: %w", err), 1)
+	}
+
+	rawReq, err := c.GetStorageByHash(oracle.Hash, native.MakeOracleRequestKey(resp.ID))
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get request %d (it may be processed already): %w", resp.ID, err), 1)
+	}
+	item, err := stackitem.Deserialize(rawReq)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to deserialize request: %w", err), 1)
+	}
+	req := new(state.OracleRequest)
+	if err := req.FromStackItem(item); err != nil {
+		return cli.NewExitError(fmt.Errorf("invalid request: %w", err), 1)
+	}
+
+	height, err := c.GetBlockCount()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get block count: %w", err), 1)
+	}
+	inv := invoker.New(c, nil)
+	nodes, err := rolemgmt.NewReader(inv).GetDesignatedByRole(noderoles.Oracle, height)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get oracle nodes: %w", err), 1)
+	}
+	pol := policy.NewReader(inv)
+	baseExecFee, err := pol.GetExecFeeFactor()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get exec fee factor: %w", err), 1)
+	}
+	feePerByte, err := pol.GetFeePerByte()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get fee per byte: %w", err), 1)
+	}
+
+	mismatches := oraclesrv.CheckResponseTx(tx, req, nodes, baseExecFee, feePerByte)
+	for _, m := range mismatches {
+		fmt.Fprintln(ctx.App.Writer, m)
+	}
+	if len(mismatches) != 0 {
+		return cli.NewExitError(fmt.Errorf("%d mismatch(es) found", len(mismatches)), 1)
+	}
+	fmt.Fprintf(ctx.App.Writer, "Response %d matches the request\n", resp.ID)
+	return nil
+}
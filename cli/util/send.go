@@ -33,6 +33,13 @@ func sendTx(ctx *cli.Context) error {
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("failed to create RPC client: %w", err), 1)
 	}
+	net, err := c.GetNetwork()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get network: %w", err), 1)
+	}
+	if err := pc.CheckNetwork(net); err != nil {
+		return cli.NewExitError(err, 1)
+	}
 	res, err := c.SendRawTransaction(tx)
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("failed to submit transaction to RPC node: %w", err), 1)
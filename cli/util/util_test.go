@@ -1,10 +1,20 @@
 package util_test
 
 import (
+	"path/filepath"
+	"strconv"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/cli/paramcontext"
 	"github.com/nspcc-dev/neo-go/internal/testcli"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUtilConvert(t *testing.T) {
@@ -24,3 +34,87 @@ func TestUtilConvert(t *testing.T) {
 	e.CheckNextLine(t, "MDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAzMDIwMQ==")                         // string to base64
 	e.CheckEOF(t)
 }
+
+func TestUtilConvertContext(t *testing.T) {
+	e := testcli.NewExecutor(t, true)
+	tmpDir := t.TempDir()
+	chainNet := e.Chain.GetConfig().Magic
+
+	t.Run("partially signed multisig", func(t *testing.T) {
+		privs, pubs := testcli.GenerateKeys(t, 3)
+		script, err := smartcontract.CreateMultiSigRedeemScript(2, pubs)
+		require.NoError(t, err)
+		ctr := &wallet.Contract{
+			Script: script,
+			Parameters: []wallet.ContractParam{
+				{Name: "parameter0", Type: smartcontract.SignatureType},
+				{Name: "parameter1", Type: smartcontract.SignatureType},
+			},
+		}
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.ValidUntilBlock = 100
+		tx.Signers = []transaction.Signer{{Account: ctr.ScriptHash()}}
+		tx.Scripts = []transaction.Witness{}
+		pc := context.NewParameterContext(context.TransactionType, netmode.TestNet, tx)
+		require.NoError(t, pc.AddSignature(ctr.ScriptHash(), ctr, pubs[0], privs[0].SignHashable(uint32(netmode.TestNet), tx)))
+		in := filepath.Join(tmpDir, "multisig.json")
+		require.NoError(t, paramcontext.Save(pc, in))
+
+		t.Run("missing network", func(t *testing.T) {
+			e.RunWithError(t, "neo-go", "util", "convert-context", in)
+		})
+		t.Run("invalid network", func(t *testing.T) {
+			e.RunWithError(t, "neo-go", "util", "convert-context", "--network", "net", in)
+		})
+		t.Run("same network", func(t *testing.T) {
+			e.RunWithError(t, "neo-go", "util", "convert-context", "--network", strconv.FormatUint(uint64(netmode.TestNet), 10), in)
+		})
+		t.Run("missing file", func(t *testing.T) {
+			e.RunWithError(t, "neo-go", "util", "convert-context", "--network", "42", filepath.Join(tmpDir, "missing.json"))
+		})
+
+		out := filepath.Join(tmpDir, "multisig.out.json")
+		e.Run(t, "neo-go", "util", "convert-context", "--network", "0x2a", "--out", out, in)
+		require.Regexp(t, "1 signature\\(s\\) removed", e.Err.String())
+		e.CheckEOF(t)
+
+		res, err := paramcontext.Read(out)
+		require.NoError(t, err)
+		require.Equal(t, netmode.UnitTestNet, res.Network)
+		require.Equal(t, tx.Hash(), res.Verifiable.Hash())
+		item := res.Items[ctr.ScriptHash()]
+		require.NotNil(t, item)
+		require.Equal(t, script, item.Script)
+		require.Equal(t, 0, len(item.Signatures))
+
+		// Signing can be continued for the new network.
+		for _, i := range []int{1, 2} {
+			require.NoError(t, res.AddSignature(ctr.ScriptHash(), ctr, pubs[i], privs[i].SignHashable(uint32(netmode.UnitTestNet), tx)))
+		}
+		_, err = res.GetCompleteTransaction()
+		require.NoError(t, err)
+	})
+
+	t.Run("sign and send", func(t *testing.T) {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1_0000_0000)
+		tx.NetworkFee = 1_0000_0000
+		tx.ValidUntilBlock = e.Chain.BlockHeight() + 10
+		tx.Signers = []transaction.Signer{{Account: testcli.ValidatorHash}}
+		tx.Scripts = []transaction.Witness{}
+		in := filepath.Join(tmpDir, "validator.json")
+		require.NoError(t, paramcontext.Save(context.NewParameterContext(context.TransactionType, netmode.TestNet, tx), in))
+
+		args := []string{"neo-go", "wallet", "sign",
+			"--rpc-endpoint", "http://" + e.RPC.Addr,
+			"--wallet", testcli.ValidatorWallet,
+			"--address", testcli.ValidatorAddr,
+			"--in", in}
+		e.In.WriteString(testcli.ValidatorPass + "\r")
+		e.RunWithError(t, args...)
+
+		e.Run(t, "neo-go", "util", "convert-context", "--network", strconv.FormatUint(uint64(chainNet), 10), "--out", in, in)
+		e.In.WriteString(testcli.ValidatorPass + "\r")
+		e.Run(t, args...)
+		e.CheckTxPersisted(t)
+	})
+}
@@ -33,6 +33,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
@@ -41,6 +43,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/trace"
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -52,6 +55,8 @@ const (
 	icKey               = "ic"
 	manifestKey         = "manifest"
 	exitFuncKey         = "exitFunc"
+	breakCondsKey       = "breakConds"
+	watchesKey          = "watches"
 	readlineInstanceKey = "readlineKey"
 	printLogoKey        = "printLogoKey"
 )
@@ -62,6 +67,7 @@ const (
 	historicFlagFullName  = "historic"
 	backwardsFlagFullName = "backwards"
 	diffFlagFullName      = "diff"
+	debugFlagFullName     = "debug"
 )
 
 var historicFlag = cli.IntFlag{
@@ -88,13 +94,32 @@ var commands = []cli.Command{
 	{
 		Name:      "break",
 		Usage:     "Place a breakpoint",
-		UsageText: `break <ip>`,
-		Description: `<ip> is mandatory parameter.
+		UsageText: `break <ip> [when top (==|!=) <value>]`,
+		Description: `<ip> is mandatory parameter. An optional condition makes the breakpoint
+conditional, execution is only stopped there if the topmost evaluation stack
+item is equal (or not equal) to the given <value>. <value> is either an
+integer or a 0x-prefixed hex-encoded byte string.
 
 Example:
-> break 12`,
+> break 12
+> break 12 when top == 42
+> break 12 when top != 0x0102`,
 		Action: handleBreak,
 	},
+	{
+		Name:      "watch",
+		Usage:     "Stop execution when the specified storage item is written",
+		UsageText: `watch [<hash-or-address-or-id> <key>]`,
+		Description: `Adds a watch for the storage item with the given hex-encoded <key> of the
+given contract, execution is stopped right after the item is written
+(put or deleted) by a contract or changed by a native contract. The contract
+can be specified by its hash, address or ID. Watches are active until a new
+program is loaded. List current watches if no arguments are given.
+
+Example:
+> watch 1 0102`,
+		Action: handleWatch,
+	},
 	{
 		Name:      "jump",
 		Usage:     "Jump to the specified instruction (absolute IP value)",
@@ -370,6 +395,27 @@ Example:
 > changes 0x0000000009070e030d0f0e020d0c06050e030c02 030e`,
 		Action: handleChanges,
 	},
+	{
+		Name:      "trace",
+		Usage:     "Print VM execution trace returned by invokescripttrace RPC call",
+		UsageText: `trace <file> [--debug <file>]`,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  debugFlagFullName + ",d",
+				Usage: "Contract debug info file used to map instructions to the source code",
+			},
+		},
+		Description: `Print VM execution trace returned by invokescripttrace RPC call.
+Can be used if no script is loaded.
+<file> is mandatory parameter, it's a JSON file containing either the whole invocation
+result or just the 'trace' field of it. Optional debug info file (see '--debug'
+option of 'contract compile' command) is used to add source code positions to the
+instructions of the corresponding contract.
+
+Example:
+> trace /path/to/result.json --debug /path/to/contract.debug.json`,
+		Action: handleTrace,
+	},
 }
 
 var completer *readline.PrefixCompleter
@@ -478,6 +524,8 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 		icKey:               ic,
 		manifestKey:         new(manifest.Manifest),
 		exitFuncKey:         exitF,
+		breakCondsKey:       make(map[int]*breakCondition),
+		watchesKey:          []storageWatch{},
 		readlineInstanceKey: l,
 		printLogoKey:        printLogotype,
 	}
@@ -517,6 +565,14 @@ func getPrintLogoFromContext(app *cli.App) bool {
 	return app.Metadata[printLogoKey].(bool)
 }
 
+func getBreakConditionsFromContext(app *cli.App) map[int]*breakCondition {
+	return app.Metadata[breakCondsKey].(map[int]*breakCondition)
+}
+
+func getWatchesFromContext(app *cli.App) []storageWatch {
+	return app.Metadata[watchesKey].([]storageWatch)
+}
+
 func setInteropContextInContext(app *cli.App, ic *interop.Context) {
 	app.Metadata[icKey] = ic
 }
@@ -525,6 +581,10 @@ func setManifestInContext(app *cli.App, m *manifest.Manifest) {
 	app.Metadata[manifestKey] = m
 }
 
+func setWatchesInContext(app *cli.App, w []storageWatch) {
+	app.Metadata[watchesKey] = w
+}
+
 func checkVMIsReady(app *cli.App) bool {
 	v := getVMFromContext(app)
 	if v == nil || !v.Ready() {
@@ -563,14 +623,56 @@ func handleBreak(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
-	n, err := getInstructionParameter(c)
+	args := c.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: <ip>", ErrMissingParameter)
+	}
+	n, err := strconv.Atoi(args[0])
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %s", ErrInvalidParameter, err)
+	}
+	var cond *breakCondition
+	if len(args) > 1 {
+		if args[1] != "when" || len(args) == 2 {
+			return fmt.Errorf("%w: condition should be specified as 'when <condition>'", ErrInvalidParameter)
+		}
+		cond, err = parseBreakCondition(strings.Join(args[2:], " "))
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidParameter, err)
+		}
 	}
 
 	v := getVMFromContext(c.App)
 	v.AddBreakPoint(n)
-	fmt.Fprintf(c.App.Writer, "breakpoint added at instruction %d\n", n)
+	conds := getBreakConditionsFromContext(c.App)
+	if cond == nil {
+		delete(conds, n)
+		fmt.Fprintf(c.App.Writer, "breakpoint added at instruction %d\n", n)
+	} else {
+		conds[n] = cond
+		fmt.Fprintf(c.App.Writer, "breakpoint added at instruction %d when %s\n", n, cond)
+	}
+	return nil
+}
+
+func handleWatch(c *cli.Context) error {
+	watches := getWatchesFromContext(c.App)
+	if !c.Args().Present() {
+		for _, w := range watches {
+			fmt.Fprintln(c.App.Writer, w)
+		}
+		return nil
+	}
+	if c.NArg() != 2 {
+		return fmt.Errorf("%w: <hash-or-address-or-id> <key>", ErrMissingParameter)
+	}
+	id, key, err := getDumpArgs(c)
+	if err != nil {
+		return err
+	}
+	w := storageWatch{id: id, key: key}
+	setWatchesInContext(c.App, append(watches, w))
+	fmt.Fprintf(c.App.Writer, "watch added for %s\n", w)
 	return nil
 }
 
@@ -927,6 +1029,8 @@ func resetState(app *cli.App, tx *transaction.Transaction, height ...uint32) err
 		return err
 	}
 	resetManifest(app)
+	app.Metadata[breakCondsKey] = make(map[int]*breakCondition)
+	setWatchesInContext(app, []storageWatch{})
 	return nil
 }
 
@@ -994,10 +1098,41 @@ func handleRun(c *cli.Context) error {
 	return nil
 }
 
+// runVM runs VM until it halts, fails, stops at a breakpoint (which condition
+// is met, if any) or a watched storage item is written. Breakpoint condition
+// or triggered watch is returned if it's the reason of the stop.
+func runVM(app *cli.App) (*breakCondition, *storageWatch, error) {
+	var (
+		v       = getVMFromContext(app)
+		conds   = getBreakConditionsFromContext(app)
+		watches = getWatchesFromContext(app)
+		tracer  *watchTracer
+	)
+	if len(watches) != 0 {
+		tracer = newWatchTracer(getInteropContextFromContext(app), watches)
+		v.SetTracer(tracer)
+		defer v.SetTracer(nil)
+	}
+	for {
+		err := v.Run()
+		if tracer != nil && tracer.hit != nil {
+			v.RemoveBreakPoint(tracer.breakPoint)
+			return nil, tracer.hit, err
+		}
+		if err != nil || !v.AtBreakpoint() {
+			return nil, nil, err
+		}
+		cond, ok := conds[v.Context().NextIP()]
+		if !ok || cond.check(v) {
+			return cond, nil, nil
+		}
+	}
+}
+
 // runVMWithHandling runs VM with handling errors and additional state messages.
 func runVMWithHandling(c *cli.Context) {
 	v := getVMFromContext(c.App)
-	err := v.Run()
+	cond, watch, err := runVM(c.App)
 	if err != nil {
 		writeErr(c.App.ErrWriter, err)
 	}
@@ -1017,9 +1152,19 @@ func runVMWithHandling(c *cli.Context) {
 		ctx := v.Context()
 		if ctx.NextIP() < ctx.LenInstr() {
 			i, op := ctx.NextInstr()
-			message = fmt.Sprintf("at breakpoint %d (%s)", i, op)
+			switch {
+			case watch != nil:
+				message = fmt.Sprintf("watch triggered for %s at %d (%s)", watch, i, op)
+			case cond != nil:
+				message = fmt.Sprintf("at breakpoint %d (%s) when %s", i, op, cond)
+			default:
+				message = fmt.Sprintf("at breakpoint %d (%s)", i, op)
+			}
 		} else {
 			message = "execution has finished"
+			if watch != nil {
+				message = fmt.Sprintf("watch triggered for %s, %s", watch, message)
+			}
 		}
 	}
 	if dumpNtf {
@@ -1217,6 +1362,96 @@ func handleChanges(c *cli.Context) error {
 	return nil
 }
 
+func handleTrace(c *cli.Context) error {
+	args := c.Args()
+	if len(args) < 1 {
+		return fmt.Errorf("%w: <file>", ErrMissingParameter)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	tr, err := parseTrace(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse trace: %w", err)
+	}
+	var di *compiler.DebugInfo
+	if path := c.String(debugFlagFullName); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read debug info: %w", err)
+		}
+		di = new(compiler.DebugInfo)
+		if err := json.Unmarshal(data, di); err != nil {
+			return fmt.Errorf("failed to parse debug info: %w", err)
+		}
+	}
+	out, err := dumpTrace(tr, di)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.App.Writer, out)
+	return nil
+}
+
+// parseTrace extracts execution trace from the invocation result or parses a
+// bare trace.
+func parseTrace(data []byte) (*trace.Trace, error) {
+	res := new(result.Invoke)
+	if err := json.Unmarshal(data, res); err == nil && res.Trace != nil {
+		return res.Trace, nil
+	}
+	tr := new(trace.Trace)
+	if err := json.Unmarshal(data, tr); err != nil {
+		return nil, err
+	}
+	if tr.Steps == nil {
+		return nil, errors.New("no trace found")
+	}
+	return tr, nil
+}
+
+// dumpTrace returns a human-readable representation of the given trace with
+// source code positions for the instructions of the contract described by di
+// (if any).
+func dumpTrace(tr *trace.Trace, di *compiler.DebugInfo) (string, error) {
+	var (
+		buf  = bytes.NewBuffer(nil)
+		w    = tabwriter.NewWriter(buf, 0, 4, 4, '\t', 0)
+		prev *util.Uint160
+	)
+	for i, s := range tr.Steps {
+		if prev == nil || !prev.Equals(s.ScriptHash) {
+			fmt.Fprintf(w, "Contract: 0x%s\n", s.ScriptHash.StringLE())
+			fmt.Fprint(w, "INDEX\tIP\tOPCODE\tDEPTH\tGAS LEFT\tSOURCE\n")
+			prev = &tr.Steps[i].ScriptHash
+		}
+		op := s.Opcode.String()
+		if s.Syscall != "" {
+			op += " " + s.Syscall
+		}
+		gas := "unlimited"
+		if s.GasLeft >= 0 {
+			gas = fixedn.Fixed8(s.GasLeft).String()
+		}
+		var pos string
+		if di != nil && di.Hash.Equals(s.ScriptHash) {
+			pos = di.SourcePosition(s.IP)
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%s\t%s\n", i, s.IP, op, s.StackDepth, gas, pos)
+		if s.Error != "" {
+			fmt.Fprintf(w, "\t\terror: %s\n", s.Error)
+		}
+	}
+	if tr.Truncated {
+		fmt.Fprintln(w, "... (trace is truncated)")
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
 // getDumpArgs is a helper function that retrieves contract ID and search prefix (if given).
 func getDumpArgs(c *cli.Context) (int32, []byte, error) {
 	id, err := getContractID(c)
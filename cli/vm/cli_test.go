@@ -25,20 +25,24 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dboper"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/trace"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
@@ -653,6 +657,72 @@ func TestBreakpoint(t *testing.T) {
 	e.checkStack(t, 9)
 }
 
+func TestConditionalBreakpoint(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH0, opcode.PUSH1, opcode.PUSH2, opcode.PUSH3, opcode.NOP)
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break 1 when",
+		"break 1 if top == 1",
+		"break 1 when bottom == 1",
+		"break 1 when top > 1",
+		"break 1 when top == abc",
+		"break 1 when top == 0xzz",
+		"break 2 when top == 5",
+		"break 3 when top != 1",
+		"break 4 when top == 0x03",
+		"run",
+		"cont",
+		"cont",
+	)
+
+	e.checkNextLine(t, "READY: loaded 5 instructions")
+	for i := 0; i < 6; i++ {
+		e.checkError(t, ErrInvalidParameter)
+	}
+	e.checkNextLine(t, "breakpoint added at instruction 2 when top == 5")
+	e.checkNextLine(t, "breakpoint added at instruction 3 when top != 1")
+	e.checkNextLine(t, "breakpoint added at instruction 4 when top == 0x03")
+	e.checkNextLine(t, "at breakpoint 3 \\(PUSH3\\) when top != 1")
+	e.checkNextLine(t, "at breakpoint 4 \\(NOP\\) when top == 0x03")
+	e.checkStack(t, 0, 1, 2, 3)
+}
+
+func TestWatch(t *testing.T) {
+	e := newTestVMCLI(t)
+	validators, err := e.cli.chain.GetValidators()
+	require.NoError(t, err)
+	multisig, err := smartcontract.CreateDefaultMultiSigRedeemScript(validators)
+	require.NoError(t, err)
+	from := hash.Hash160(multisig)
+	to := util.Uint160{1, 2, 3}
+	gasHash := state.CreateNativeContractHash(nativenames.Gas)
+
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, gasHash, "transfer", callflag.All, from, to, 1, nil)
+	require.NoError(t, w.Err)
+	key := hex.EncodeToString(append([]byte{20}, to.BytesBE()...)) // GAS account prefix.
+
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes())+" "+from.StringLE(),
+		"watch "+gasHash.StringLE(),
+		"watch "+gasHash.StringLE()+" zz",
+		"watch "+gasHash.StringLE()+" "+key,
+		"watch",
+		"run",
+		"cont",
+	)
+
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "Error: failed to decode prefix from hex")
+	e.checkNextLine(t, "watch added for contract -6 key "+key)
+	e.checkNextLine(t, "contract -6 key "+key)
+	e.checkNextLine(t, "watch triggered for contract -6 key "+key+" at \\d+")
+	e.checkStack(t, true)
+}
+
 func TestDumpSSlot(t *testing.T) {
 	w := io.NewBufBinWriter()
 	emit.Opcodes(w.BinWriter, opcode.INITSSLOT, 2, // init static slot with size=2
@@ -1199,3 +1269,68 @@ func TestJump(t *testing.T) {
 	e.checkNextLine(t, fmt.Sprintf("jumped to instruction %d", jmpTo))
 	e.checkStack(t, 9)
 }
+
+func TestTrace(t *testing.T) {
+	h1, h2 := util.Uint160{1, 2, 3}, util.Uint160{4, 5, 6}
+	tr := &trace.Trace{
+		Steps: []trace.Step{
+			{ScriptHash: h1, IP: 0, Opcode: opcode.PUSH1, StackDepth: 0, GasLeft: 1_0000_0000},
+			{ScriptHash: h1, IP: 1, Opcode: opcode.SYSCALL, StackDepth: 1, GasLeft: 5000_0000, Syscall: interopnames.SystemContractCall, Error: "bad"},
+			{ScriptHash: h2, IP: 0, Opcode: opcode.RET, StackDepth: 2, GasLeft: -1},
+		},
+		Truncated: true,
+	}
+	di := &compiler.DebugInfo{
+		Hash:      h1,
+		Documents: []string{"a.go"},
+		Methods: []compiler.MethodDebugInfo{{
+			Range:     compiler.DebugRange{Start: 0, End: 6},
+			SeqPoints: []compiler.DebugSeqPoint{{Opcode: 1, Document: 0, StartLine: 3, StartCol: 4}},
+		}},
+		Events: []compiler.EventDebugInfo{},
+	}
+	tmpDir := t.TempDir()
+	traceData, err := json.Marshal(tr)
+	require.NoError(t, err)
+	traceFile := filepath.Join(tmpDir, "trace.json")
+	require.NoError(t, os.WriteFile(traceFile, traceData, os.ModePerm))
+	resFile := filepath.Join(tmpDir, "result.json")
+	require.NoError(t, os.WriteFile(resFile, []byte(`{"state":"HALT","gasconsumed":"1","script":"","stack":[],"trace":`+string(traceData)+`}`), os.ModePerm))
+	diData, err := json.Marshal(di)
+	require.NoError(t, err)
+	diFile := filepath.Join(tmpDir, "debug.json")
+	require.NoError(t, os.WriteFile(diFile, diData, os.ModePerm))
+	badFile := filepath.Join(tmpDir, "bad.json")
+	require.NoError(t, os.WriteFile(badFile, []byte(`{"state":"HALT"}`), os.ModePerm))
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"trace",
+		"trace "+filepath.Join(tmpDir, "unknown.json"),
+		"trace "+badFile,
+		"trace "+traceFile+" --debug "+badFile+"1",
+		"trace "+resFile+" --debug "+diFile,
+		"trace "+traceFile,
+	)
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "Error: open .*unknown.json")
+	e.checkNextLine(t, "Error: failed to parse trace: no trace found")
+	e.checkNextLine(t, "Error: failed to read debug info")
+	checkTrace := func(t *testing.T, withSource bool) {
+		var pos string
+		if withSource {
+			pos = `\s+a.go:3:4`
+		}
+		e.checkNextLine(t, "^Contract: 0x"+h1.StringLE())
+		e.checkNextLine(t, `^INDEX\s+IP\s+OPCODE\s+DEPTH\s+GAS LEFT\s+SOURCE`)
+		e.checkNextLine(t, `^0\s+0\s+PUSH1\s+0\s+1\s*$`)
+		e.checkNextLine(t, `^1\s+1\s+SYSCALL System.Contract.Call\s+1\s+0.5`+pos+`\s*$`)
+		e.checkNextLine(t, `^\s+error: bad\s*$`)
+		e.checkNextLine(t, "^Contract: 0x"+h2.StringLE())
+		e.checkNextLine(t, `^INDEX\s+IP\s+OPCODE\s+DEPTH\s+GAS LEFT\s+SOURCE`)
+		e.checkNextLine(t, `^2\s+0\s+RET\s+2\s+unlimited\s*$`)
+		e.checkNextLine(t, `^\.\.\. \(trace is truncated\)\s*$`)
+	}
+	checkTrace(t, true)
+	checkTrace(t, false)
+}
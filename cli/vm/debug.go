@@ -0,0 +1,182 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	istorage "github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// breakCondition is a condition of a conditional breakpoint, it compares the
+// topmost evaluation stack item with an integer or a byte string.
+type breakCondition struct {
+	notEqual bool
+	isBytes  bool
+	intVal   *big.Int
+	bytesVal []byte
+	expr     string
+}
+
+// parseBreakCondition parses breakpoint condition in the form of
+// `top == <value>` or `top != <value>` where value is either an integer or
+// a 0x-prefixed hex-encoded byte string.
+func parseBreakCondition(s string) (*breakCondition, error) {
+	var (
+		res breakCondition
+		op  = "=="
+	)
+	i := strings.Index(s, op)
+	if i < 0 {
+		op = "!="
+		i = strings.Index(s, op)
+		if i < 0 {
+			return nil, errors.New("condition should be either 'top == <value>' or 'top != <value>'")
+		}
+		res.notEqual = true
+	}
+	if lhs := strings.TrimSpace(s[:i]); lhs != "top" {
+		return nil, fmt.Errorf("unsupported condition operand %q, only 'top' is supported", lhs)
+	}
+	rhs := strings.TrimSpace(s[i+len(op):])
+	if strings.HasPrefix(rhs, "0x") {
+		b, err := hex.DecodeString(rhs[2:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid byte string %q: %w", rhs, err)
+		}
+		res.isBytes = true
+		res.bytesVal = b
+	} else {
+		val, ok := new(big.Int).SetString(rhs, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid value %q: neither integer nor 0x-prefixed hex string", rhs)
+		}
+		res.intVal = val
+	}
+	res.expr = "top " + op + " " + rhs
+	return &res, nil
+}
+
+// check returns true if the condition is met for the given VM state. Items
+// that can't be converted to the value type are considered to be not equal
+// to it.
+func (b *breakCondition) check(v *vm.VM) bool {
+	var eq bool
+	if v.Estack().Len() != 0 {
+		item := v.Estack().Peek(0).Item()
+		if b.isBytes {
+			val, err := item.TryBytes()
+			eq = err == nil && bytes.Equal(val, b.bytesVal)
+		} else {
+			val, err := item.TryInteger()
+			eq = err == nil && val.Cmp(b.intVal) == 0
+		}
+	}
+	return eq != b.notEqual
+}
+
+// String implements the fmt.Stringer interface.
+func (b *breakCondition) String() string {
+	return b.expr
+}
+
+// storageWatch is a storage item watched for changes.
+type storageWatch struct {
+	id  int32
+	key []byte
+}
+
+// String implements the fmt.Stringer interface.
+func (w storageWatch) String() string {
+	return fmt.Sprintf("contract %d key %s", w.id, hex.EncodeToString(w.key))
+}
+
+var (
+	storagePutID    = interopnames.ToID([]byte(interopnames.SystemStoragePut))
+	storageDeleteID = interopnames.ToID([]byte(interopnames.SystemStorageDelete))
+)
+
+// watchTracer is a vm.Tracer that detects writes to the watched storage
+// items. It checks System.Storage.Put and System.Storage.Delete arguments and
+// compares item values before and after any other syscall (so that changes
+// made by native contracts are detected as well). A temporary breakpoint is
+// added right after the syscall that has written the item.
+type watchTracer struct {
+	ic      *interop.Context
+	watches []storageWatch
+	values  [][]byte
+	direct  int
+
+	// hit is the triggered watch, nil if none.
+	hit *storageWatch
+	// breakPoint is the temporary breakpoint set for the triggered watch.
+	breakPoint int
+}
+
+func newWatchTracer(ic *interop.Context, watches []storageWatch) *watchTracer {
+	return &watchTracer{
+		ic:      ic,
+		watches: watches,
+		values:  make([][]byte, len(watches)),
+		direct:  -1,
+	}
+}
+
+// OnStep implements the vm.Tracer interface.
+func (t *watchTracer) OnStep(util.Uint160, int, opcode.Opcode, int, int64) {}
+
+// OnSyscallEnter implements the vm.Tracer interface.
+func (t *watchTracer) OnSyscallEnter(id uint32) {
+	t.direct = -1
+	for i, w := range t.watches {
+		t.values[i] = t.ic.DAO.GetStorageItem(w.id, w.key)
+	}
+	if id != storagePutID && id != storageDeleteID {
+		return
+	}
+	estack := t.ic.VM.Estack()
+	if estack.Len() < 2 {
+		return
+	}
+	stc, ok := estack.Peek(0).Value().(*istorage.Context)
+	if !ok {
+		return
+	}
+	key, err := estack.Peek(1).Item().TryBytes()
+	if err != nil {
+		return
+	}
+	for i, w := range t.watches {
+		if w.id == stc.ID && bytes.Equal(w.key, key) {
+			t.direct = i
+			return
+		}
+	}
+}
+
+// OnSyscallExit implements the vm.Tracer interface.
+func (t *watchTracer) OnSyscallExit(_ uint32, err error) {
+	if err != nil || t.hit != nil {
+		return
+	}
+	for i, w := range t.watches {
+		if i != t.direct {
+			val := t.ic.DAO.GetStorageItem(w.id, w.key)
+			if (val == nil) == (t.values[i] == nil) && bytes.Equal(val, t.values[i]) {
+				continue
+			}
+		}
+		t.hit = &t.watches[i]
+		t.breakPoint = t.ic.VM.Context().NextIP()
+		t.ic.VM.AddBreakPoint(t.breakPoint)
+		return
+	}
+}
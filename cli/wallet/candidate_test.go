@@ -51,11 +51,21 @@ func TestRegisterCandidate(t *testing.T) {
 		"--address", validatorAddress,
 		"error")
 
+	// fee limit exceeded
+	e.In.WriteString("one\r")
+	e.RunWithError(t, "neo-go", "wallet", "candidate", "register",
+		"--rpc-endpoint", "http://"+e.RPC.Addr,
+		"--wallet", testcli.ValidatorWallet,
+		"--address", validatorAddress,
+		"--max-fee", "100",
+		"--force")
+
 	e.In.WriteString("one\r")
 	e.Run(t, "neo-go", "wallet", "candidate", "register",
 		"--rpc-endpoint", "http://"+e.RPC.Addr,
 		"--wallet", testcli.ValidatorWallet,
 		"--address", validatorAddress,
+		"--max-fee", "1001",
 		"--force")
 	e.CheckTxPersisted(t)
 
@@ -98,6 +108,17 @@ func TestRegisterCandidate(t *testing.T) {
 		e.CheckNextLine(t, "^\\s*"+validatorHex+"\\s*"+b.String()+"\\s*true\\s*true$")
 		e.CheckEOF(t)
 
+		e.Run(t, "neo-go", "wallet", "candidate", "list",
+			"--rpc-endpoint", "http://"+e.RPC.Addr, "--sort", "votes")
+		e.CheckNextLine(t, "^\\s*Key\\s+Votes\\s+Committee\\s+Validator$") // Header.
+		e.CheckNextLine(t, "^\\s*"+validatorHex+"\\s*"+b.String()+"\\s*true\\s*true$")
+		e.CheckEOF(t)
+
+		e.Run(t, "neo-go", "wallet", "candidate", "list",
+			"--rpc-endpoint", "http://"+e.RPC.Addr, "--json")
+		e.CheckNextLine(t, `^\[{"publickey":"`+validatorHex+`","votes":`+b.String()+`,"committee":true,"validator":true}\]$`)
+		e.CheckEOF(t)
+
 		// check state
 		e.Run(t, "neo-go", "query", "voter",
 			"--rpc-endpoint", "http://"+e.RPC.Addr,
@@ -159,4 +180,10 @@ func TestRegisterCandidate(t *testing.T) {
 	e.RunWithError(t, "neo-go", "query", "voter", "--rpc-endpoint", "http://"+e.RPC.Addr, validatorAddress, validatorAddress)
 	e.RunWithError(t, "neo-go", "query", "committee", "--rpc-endpoint", "http://"+e.RPC.Addr, "something")
 	e.RunWithError(t, "neo-go", "query", "candidates", "--rpc-endpoint", "http://"+e.RPC.Addr, "something")
+	e.RunWithError(t, "neo-go", "wallet", "candidate", "list", "--rpc-endpoint", "http://"+e.RPC.Addr, "something")
+	e.RunWithError(t, "neo-go", "wallet", "candidate", "list", "--rpc-endpoint", "http://"+e.RPC.Addr, "--sort", "name")
+
+	e.Run(t, "neo-go", "wallet", "candidate", "list", "--rpc-endpoint", "http://"+e.RPC.Addr)
+	e.CheckNextLine(t, "^\\s*Key.+$") // Header.
+	e.CheckEOF(t)
 }
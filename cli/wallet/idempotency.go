@@ -0,0 +1,162 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/urfave/cli"
+)
+
+const (
+	// idempotencyDir is the directory (relative to the user config one)
+	// idempotency keys file is stored in.
+	idempotencyDir = "neo-go"
+	// idempotencyFile is the name of the file containing idempotency keys.
+	idempotencyFile = "idempotency.json"
+)
+
+var idempotencyKeyFlag = cli.StringFlag{
+	Name:  "idempotency-key",
+	Usage: "key identifying this transfer, retries with the same key print the original transaction hash instead of sending a new one",
+}
+
+type (
+	// idempotencyRecord is a transaction sent with some idempotency key.
+	idempotencyRecord struct {
+		Hash            util.Uint256 `json:"hash"`
+		ValidUntilBlock uint32       `json:"validuntilblock"`
+	}
+
+	// idempotencyKeys contains idempotency records per network (the key is
+	// network magic) per idempotency key.
+	idempotencyKeys map[string]map[string]idempotencyRecord
+
+	// txChecker is an RPC client interface sufficient to check transaction
+	// status.
+	txChecker interface {
+		GetBlockCount() (uint32, error)
+		GetRawTransaction(hash util.Uint256) (*transaction.Transaction, error)
+	}
+)
+
+// getIdempotencyPath returns the path to idempotency keys file.
+func getIdempotencyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("can't get user config directory: %w", err)
+	}
+	return filepath.Join(dir, idempotencyDir, idempotencyFile), nil
+}
+
+func readIdempotencyKeys(path string) (idempotencyKeys, error) {
+	keys := make(idempotencyKeys)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return keys, nil
+		}
+		return nil, fmt.Errorf("can't read idempotency keys: %w", err)
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("invalid idempotency keys file %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+func writeIdempotencyKeys(path string, keys idempotencyKeys) error {
+	data, err := json.Marshal(keys)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("can't create idempotency keys directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("can't write idempotency keys: %w", err)
+	}
+	return nil
+}
+
+// checkIdempotencyKey checks whether a transaction was already sent with the
+// given key. It returns the hash of this transaction if it's known to the
+// network (mempooled or accepted). Keys of transactions that have not been
+// accepted before their ValidUntilBlock are removed, nil is returned for them
+// and a new transaction can be sent. An error is returned if the transaction
+// is not known, but can still be accepted.
+func checkIdempotencyKey(c txChecker, path string, magic netmode.Magic, key string) (*util.Uint256, error) {
+	keys, err := readIdempotencyKeys(path)
+	if err != nil {
+		return nil, err
+	}
+	net := strconv.FormatUint(uint64(magic), 10)
+	rec, ok := keys[net][key]
+	if !ok {
+		return nil, nil
+	}
+	_, err = c.GetRawTransaction(rec.Hash)
+	if err == nil {
+		return &rec.Hash, nil
+	}
+	var rpcErr *neorpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != neorpc.RPCErrorCode {
+		return nil, fmt.Errorf("can't check transaction %s: %w", rec.Hash.StringLE(), err)
+	}
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return nil, fmt.Errorf("can't get block count: %w", err)
+	}
+	if count <= rec.ValidUntilBlock { // The next block still can contain it.
+		return nil, fmt.Errorf("transaction %s sent with this key is not known, but it's valid until block %d, retry after it",
+			rec.Hash.StringLE(), rec.ValidUntilBlock)
+	}
+	delete(keys[net], key)
+	return nil, writeIdempotencyKeys(path, keys)
+}
+
+// isTxRejected checks whether the given transaction sending error is
+// a definite rejection of the transaction by the node, a transaction that is
+// not rejected (like on network failures) still can be accepted.
+func isTxRejected(err error) bool {
+	var rpcErr *neorpc.Error
+	return errors.As(err, &rpcErr) && !errors.Is(err, neorpc.ErrAlreadyExists)
+}
+
+// saveIdempotencyKey stores the given transaction for the key.
+func saveIdempotencyKey(path string, magic netmode.Magic, key string, tx *transaction.Transaction) error {
+	keys, err := readIdempotencyKeys(path)
+	if err != nil {
+		return err
+	}
+	net := strconv.FormatUint(uint64(magic), 10)
+	if keys[net] == nil {
+		keys[net] = make(map[string]idempotencyRecord)
+	}
+	keys[net][key] = idempotencyRecord{
+		Hash:            tx.Hash(),
+		ValidUntilBlock: tx.ValidUntilBlock,
+	}
+	return writeIdempotencyKeys(path, keys)
+}
+
+// forgetIdempotencyKey removes the transaction stored for the key if it's the
+// given one.
+func forgetIdempotencyKey(path string, magic netmode.Magic, key string, tx *transaction.Transaction) error {
+	keys, err := readIdempotencyKeys(path)
+	if err != nil {
+		return err
+	}
+	net := strconv.FormatUint(uint64(magic), 10)
+	if rec, ok := keys[net][key]; !ok || !rec.Hash.Equals(tx.Hash()) {
+		return nil
+	}
+	delete(keys[net], key)
+	return writeIdempotencyKeys(path, keys)
+}
@@ -0,0 +1,106 @@
+package wallet
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTxChecker struct {
+	count uint32
+	known map[util.Uint256]bool
+	err   error
+}
+
+func (c *fakeTxChecker) GetBlockCount() (uint32, error) {
+	return c.count, nil
+}
+
+func (c *fakeTxChecker) GetRawTransaction(h util.Uint256) (*transaction.Transaction, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if !c.known[h] {
+		return nil, neorpc.ErrUnknownTransaction
+	}
+	return new(transaction.Transaction), nil
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "neo-go", idempotencyFile)
+	c := &fakeTxChecker{count: 10, known: make(map[util.Uint256]bool)}
+	tx := transaction.New([]byte{1, 2, 3}, 0)
+	tx.ValidUntilBlock = 15
+
+	h, err := checkIdempotencyKey(c, path, netmode.UnitTestNet, "key")
+	require.NoError(t, err)
+	require.Nil(t, h)
+
+	require.NoError(t, saveIdempotencyKey(path, netmode.UnitTestNet, "key", tx))
+
+	t.Run("other network", func(t *testing.T) {
+		h, err := checkIdempotencyKey(c, path, netmode.TestNet, "key")
+		require.NoError(t, err)
+		require.Nil(t, h)
+	})
+	t.Run("RPC error", func(t *testing.T) {
+		c.err = errors.New("connection refused")
+		defer func() { c.err = nil }()
+		_, err := checkIdempotencyKey(c, path, netmode.UnitTestNet, "key")
+		require.Error(t, err)
+	})
+	t.Run("known", func(t *testing.T) {
+		c.known[tx.Hash()] = true
+		defer delete(c.known, tx.Hash())
+		h, err := checkIdempotencyKey(c, path, netmode.UnitTestNet, "key")
+		require.NoError(t, err)
+		require.Equal(t, tx.Hash(), *h)
+	})
+	t.Run("unknown, not expired", func(t *testing.T) {
+		c.count = tx.ValidUntilBlock
+		_, err := checkIdempotencyKey(c, path, netmode.UnitTestNet, "key")
+		require.Error(t, err)
+	})
+	t.Run("expired", func(t *testing.T) {
+		c.count = tx.ValidUntilBlock + 1
+		h, err := checkIdempotencyKey(c, path, netmode.UnitTestNet, "key")
+		require.NoError(t, err)
+		require.Nil(t, h)
+
+		keys, err := readIdempotencyKeys(path)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(keys["42"]))
+	})
+}
+
+func TestForgetIdempotencyKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "neo-go", idempotencyFile)
+	tx := transaction.New([]byte{1, 2, 3}, 0)
+	other := transaction.New([]byte{4, 5, 6}, 0)
+
+	require.NoError(t, forgetIdempotencyKey(path, netmode.UnitTestNet, "key", tx))
+	require.NoError(t, saveIdempotencyKey(path, netmode.UnitTestNet, "key", tx))
+
+	require.NoError(t, forgetIdempotencyKey(path, netmode.UnitTestNet, "key", other))
+	keys, err := readIdempotencyKeys(path)
+	require.NoError(t, err)
+	require.Equal(t, tx.Hash(), keys["42"]["key"].Hash)
+
+	require.NoError(t, forgetIdempotencyKey(path, netmode.UnitTestNet, "key", tx))
+	keys, err = readIdempotencyKeys(path)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(keys["42"]))
+}
+
+func TestIsTxRejected(t *testing.T) {
+	require.True(t, isTxRejected(neorpc.ErrValidationFailed))
+	require.True(t, isTxRejected(neorpc.WrapErrorWithData(neorpc.ErrPolicyFail, "blocked")))
+	require.False(t, isTxRejected(neorpc.ErrAlreadyExists))
+	require.False(t, isTxRejected(errors.New("connection reset")))
+}
@@ -1,25 +1,41 @@
 package wallet
 
 import (
+	stdcontext "context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/cli/options"
 	"github.com/nspcc-dev/neo-go/cli/paramcontext"
+	"github.com/nspcc-dev/neo-go/cli/txctx"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/urfave/cli"
 )
 
 func signStoredTransaction(ctx *cli.Context) error {
 	var (
-		out      = ctx.String("out")
-		rpcNode  = ctx.String(options.RPCEndpointFlag)
-		addrFlag = ctx.Generic("address").(*flags.Address)
+		out        = ctx.String("out")
+		outBase64  = ctx.Bool("out-base64")
+		rpcNode    = ctx.String(options.RPCEndpointFlag)
+		addrFlag   = ctx.Generic("address").(*flags.Address)
+		addWitness = ctx.Bool("add-witness")
+		vub        = uint32(ctx.Uint("valid-until-block"))
 	)
-	if err := cmdargs.EnsureNone(ctx); err != nil {
-		return err
+	if !addWitness {
+		if err := cmdargs.EnsureNone(ctx); err != nil {
+			return err
+		}
 	}
 	wall, pass, err := readWallet(ctx)
 	if err != nil {
@@ -27,7 +43,15 @@ func signStoredTransaction(ctx *cli.Context) error {
 	}
 	defer wall.Close()
 
-	pc, err := paramcontext.Read(ctx.String("in"))
+	var pc *context.ParameterContext
+	if ctx.IsSet("in-base64") {
+		if ctx.String("in") != "" {
+			return cli.NewExitError("--in and --in-base64 can't be used together", 1)
+		}
+		pc, err = paramcontext.ReadBase64(ctx.String("in-base64"))
+	} else {
+		pc, err = paramcontext.Read(ctx.String("in"))
+	}
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -36,10 +60,24 @@ func signStoredTransaction(ctx *cli.Context) error {
 		return cli.NewExitError("address was not provided", 1)
 	}
 
-	var ch = addrFlag.Uint160()
-	acc, err := getDecryptedAccount(wall, ch, pass)
-	if err != nil {
-		return cli.NewExitError(err, 1)
+	var (
+		ch  = addrFlag.Uint160()
+		acc *wallet.Account
+	)
+	if addWitness {
+		// No signature is added, so there is no need to decrypt anything.
+		acc = wall.GetAccount(ch)
+		if acc == nil {
+			return cli.NewExitError(fmt.Errorf("can't find account for the address: %s", address.Uint160ToString(ch)), 1)
+		}
+		if !acc.Contract.Deployed {
+			return cli.NewExitError("witness can only be added for deployed contract accounts", 1)
+		}
+	} else {
+		acc, err = getDecryptedAccount(wall, ch, pass)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
 	}
 
 	tx, ok := pc.Verifiable.(*transaction.Transaction)
@@ -58,16 +96,79 @@ func signStoredTransaction(ctx *cli.Context) error {
 		return cli.NewExitError("tx signers don't contain provided account", 1)
 	}
 
-	if acc.CanSign() {
-		sign := acc.SignHashable(pc.Network, pc.Verifiable)
+	var (
+		c      *rpcclient.Client
+		gctx   stdcontext.Context
+		cancel func()
+	)
+	if rpcNode != "" {
+		gctx, cancel = options.GetTimeoutContext(ctx)
+		defer cancel()
+
+		var err error // `GetRPCClient` returns specialized type.
+		c, err = options.GetRPCClient(gctx, ctx)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to create RPC client: %w", err), 1)
+		}
+		// Signatures for the wrong network are only rejected by the node
+		// as invalid ones, so it's checked before signing anything.
+		net, err := c.GetNetwork()
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to get network: %w", err), 1)
+		}
+		if err := pc.CheckNetwork(net); err != nil {
+			return cli.NewExitError(fmt.Errorf("%w (see 'util convert-context')", err), 1)
+		}
+	}
+	if vub != 0 {
+		if rpcNode == "" {
+			return cli.NewExitError("--valid-until-block can only be used with an RPC endpoint", 1)
+		}
+		if isContextSigned(pc) {
+			return cli.NewExitError("can't change ValidUntilBlock of a transaction that is already (partially) signed", 1)
+		}
+		if err := checkValidUntilBlock(c, vub); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		// Changing ValidUntilBlock changes the hash, so the transaction is
+		// decoded again to have it recalculated.
+		tx.ValidUntilBlock = vub
+		tx, err = transaction.NewTransactionFromBytes(tx.Bytes())
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to update transaction: %w", err), 1)
+		}
+		pc.Verifiable = tx
+	}
+
+	if addWitness {
+		if err := addContractWitness(ctx, pc, ch, acc.Contract); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	} else if acc.Contract.Deployed && !verifyParamsSet(pc.Items[ch]) {
+		params, err := txctx.GetVerifyParameters(ctx, acc.Address, acc.Contract, true)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if params != nil {
+			if err := pc.AddParameters(ch, acc.Contract, params); err != nil {
+				return cli.NewExitError(fmt.Errorf("can't add verification parameters: %w", err), 1)
+			}
+		}
+	}
+	if !addWitness && acc.CanSign() {
+		sign, err := acc.SignHashableContext(stdcontext.Background(), pc.Network, pc.Verifiable)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't sign: %w", err), 1)
+		}
 		if err := pc.AddSignature(ch, acc.Contract, acc.PublicKey(), sign); err != nil {
 			return cli.NewExitError(fmt.Errorf("can't add signature: %w", err), 1)
 		}
-	} else if rpcNode == "" {
+		printSigningProgress(ctx.App.ErrWriter, pc, ch)
+	} else if !addWitness && rpcNode == "" {
 		return cli.NewExitError(fmt.Errorf("can't sign transactions with the given account and no RPC endpoing given to send anything signed"), 1)
 	}
 	// Not saving and not sending, print.
-	if out == "" && rpcNode == "" {
+	if out == "" && !outBase64 && rpcNode == "" {
 		txt, err := json.MarshalIndent(pc, " ", "     ")
 		if err != nil {
 			return cli.NewExitError(fmt.Errorf("can't display resulting context: %w", err), 1)
@@ -80,20 +181,21 @@ func signStoredTransaction(ctx *cli.Context) error {
 			return cli.NewExitError(fmt.Errorf("can't save resulting context: %w", err), 1)
 		}
 	}
+	if outBase64 {
+		b64, err := paramcontext.ToBase64(pc)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't encode resulting context: %w", err), 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, b64)
+		if rpcNode == "" {
+			return nil
+		}
+	}
 	if rpcNode != "" {
 		tx, err = pc.GetCompleteTransaction()
 		if err != nil {
 			return cli.NewExitError(fmt.Errorf("failed to complete transaction: %w", err), 1)
 		}
-
-		gctx, cancel := options.GetTimeoutContext(ctx)
-		defer cancel()
-
-		var err error // `GetRPCClient` returns specialized type.
-		c, err := options.GetRPCClient(gctx, ctx)
-		if err != nil {
-			return cli.NewExitError(fmt.Errorf("failed to create RPC client: %w", err), 1)
-		}
 		res, err := c.SendRawTransaction(tx)
 		if err != nil {
 			return cli.NewExitError(fmt.Errorf("failed to submit transaction to RPC node: %w", err), 1)
@@ -105,3 +207,102 @@ func signStoredTransaction(ctx *cli.Context) error {
 	fmt.Fprintln(ctx.App.Writer, tx.Hash().StringLE())
 	return nil
 }
+
+// addContractWitness adds verification parameters given as command arguments
+// for the deployed contract account to the context.
+func addContractWitness(ctx *cli.Context, pc *context.ParameterContext, h util.Uint160, ctr *wallet.Contract) error {
+	args := ctx.Args()
+	n, params, err := cmdargs.ParseParams(args, true)
+	if err != nil {
+		return fmt.Errorf("invalid verification parameters: %w", err)
+	}
+	if n != len(args) {
+		return errors.New("unexpected arguments after verification parameters")
+	}
+	if len(params) != len(ctr.Parameters) {
+		return fmt.Errorf("contract expects %d verification parameters, %d given", len(ctr.Parameters), len(params))
+	}
+	if err := pc.AddParameters(h, ctr, params); err != nil {
+		return fmt.Errorf("can't add verification parameters: %w", err)
+	}
+	return nil
+}
+
+// verifyParamsSet checks whether all non-signature parameters of the given
+// context item have values.
+func verifyParamsSet(item *context.Item) bool {
+	if item == nil {
+		return false
+	}
+	for _, p := range item.Parameters {
+		if p.Value == nil && p.Type != smartcontract.SignatureType && p.Type != smartcontract.AnyType {
+			return false
+		}
+	}
+	return true
+}
+
+// isContextSigned checks whether the context already has any signatures (which
+// are bound to the current transaction hash).
+func isContextSigned(pc *context.ParameterContext) bool {
+	for _, item := range pc.Items {
+		if len(item.Signatures) != 0 {
+			return true
+		}
+		for _, p := range item.Parameters {
+			if p.Type == smartcontract.SignatureType && p.Value != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkValidUntilBlock checks that the given ValidUntilBlock value is
+// acceptable for the network at its current height.
+func checkValidUntilBlock(c *rpcclient.Client, vub uint32) error {
+	count, err := c.GetBlockCount()
+	if err != nil {
+		return fmt.Errorf("failed to get current height: %w", err)
+	}
+	v, err := c.GetVersion()
+	if err != nil {
+		return fmt.Errorf("failed to get network parameters: %w", err)
+	}
+	var height = count - 1
+	if vub <= height {
+		return fmt.Errorf("ValidUntilBlock %d is not higher than the current height %d", vub, height)
+	}
+	if vub > height+v.Protocol.MaxValidUntilBlockIncrement {
+		return fmt.Errorf("ValidUntilBlock %d is more than %d blocks away from the current height %d", vub, v.Protocol.MaxValidUntilBlockIncrement, height)
+	}
+	return nil
+}
+
+// printSigningProgress prints the number of signatures collected for the given
+// multisignature account and whether the transaction is completely signed
+// when the threshold is reached. Nothing is printed for other accounts.
+func printSigningProgress(w io.Writer, pc *context.ParameterContext, h util.Uint160) {
+	item := pc.Items[h]
+	if item == nil {
+		return
+	}
+	m, _, ok := vm.ParseMultiSigContract(item.Script)
+	if !ok {
+		return
+	}
+	n := len(item.Signatures)
+	if n < m {
+		fmt.Fprintf(w, "%d of %d signatures collected, %d more required\n", n, m, m-n)
+		return
+	}
+	tx := pc.Verifiable.(*transaction.Transaction)
+	for i := range tx.Signers {
+		if _, err := pc.GetWitness(tx.Signers[i].Account); err != nil {
+			fmt.Fprintf(w, "%d of %d signatures collected, witness for %s is still missing\n",
+				m, m, address.Uint160ToString(tx.Signers[i].Account))
+			return
+		}
+	}
+	fmt.Fprintf(w, "%d of %d signatures collected, transaction is complete\n", m, m)
+}
@@ -1,12 +1,14 @@
 package wallet_test
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/testcli"
@@ -103,6 +105,16 @@ func TestSignMultisigTx(t *testing.T) {
 			"--wallet", wallet2Path, "--address", multisigAddr,
 			"--in", txPath, "--out", t.TempDir())
 
+		// both --in and --in-base64
+		e.RunWithError(t, "neo-go", "wallet", "sign",
+			"--wallet", wallet2Path, "--address", multisigAddr,
+			"--in", txPath, "--in-base64", "e30=")
+
+		// invalid base64
+		e.RunWithError(t, "neo-go", "wallet", "sign",
+			"--wallet", wallet2Path, "--address", multisigAddr,
+			"--in-base64", "not a base64")
+
 		// invalid RPC endpoint
 		e.In.WriteString("pass\r")
 		e.RunWithError(t, "neo-go", "wallet", "sign",
@@ -164,6 +176,30 @@ func TestSignMultisigTx(t *testing.T) {
 		require.Equal(t, vmstate.Halt.String(), res.State, res.FaultException)
 	})
 
+	t.Run("signing progress", func(t *testing.T) {
+		pc := new(context.ParameterContext)
+		data, err := os.ReadFile(txPath)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, pc))
+		pc.Items[multisigHash].Signatures = make(map[string][]byte)
+		data, err = json.Marshal(pc)
+		require.NoError(t, err)
+		unsignedPath := filepath.Join(tmpDir, "unsigned.json")
+		require.NoError(t, os.WriteFile(unsignedPath, data, 0644))
+
+		e.In.WriteString("pass\r")
+		e.Run(t, "neo-go", "wallet", "sign",
+			"--wallet", wallet1Path, "--address", multisigAddr,
+			"--in", unsignedPath, "--out", unsignedPath)
+		require.Equal(t, "1 of 2 signatures collected, 1 more required\n", e.Err.String())
+
+		e.In.WriteString("pass\r")
+		e.Run(t, "neo-go", "wallet", "sign",
+			"--wallet", wallet2Path, "--address", multisigAddr,
+			"--in", unsignedPath, "--out", unsignedPath)
+		require.Equal(t, "2 of 2 signatures collected, transaction is complete\n", e.Err.String())
+	})
+
 	t.Run("console output", func(t *testing.T) {
 		oldIn, err := os.ReadFile(txPath)
 		require.NoError(t, err)
@@ -191,6 +227,25 @@ func TestSignMultisigTx(t *testing.T) {
 		require.NotEqual(t, pcOld.Items[multisigHash].Signatures, pcNew.Items[multisigHash].Signatures)
 	})
 
+	t.Run("base64 input and output", func(t *testing.T) {
+		oldIn, err := os.ReadFile(txPath)
+		require.NoError(t, err)
+		e.In.WriteString("pass\r")
+		e.Run(t, "neo-go", "wallet", "sign",
+			"--wallet", wallet2Path, "--address", multisigAddr,
+			"--in-base64", base64.StdEncoding.EncodeToString(oldIn), "--out-base64")
+
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(e.Out.String()))
+		require.NoError(t, err)
+		pcNew := new(context.ParameterContext)
+		require.NoError(t, json.Unmarshal(data, pcNew))
+
+		pcOld := new(context.ParameterContext)
+		require.NoError(t, json.Unmarshal(oldIn, pcOld))
+		require.Equal(t, pcOld.Verifiable, pcNew.Verifiable)
+		require.NotEqual(t, pcOld.Items[multisigHash].Signatures, pcNew.Items[multisigHash].Signatures)
+	})
+
 	t.Run("sign, save and send", func(t *testing.T) {
 		e.In.WriteString("pass\r")
 		e.Run(t, "neo-go", "wallet", "sign",
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep11"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -22,6 +24,9 @@ import (
 	"github.com/urfave/cli"
 )
 
+// maxNEP11TokenIDLength is the maximum NEP-11 token ID length in bytes.
+const maxNEP11TokenIDLength = 64
+
 func newNEP11Commands() []cli.Command {
 	maxIters := strconv.Itoa(config.DefaultMaxIteratorResultItems)
 	tokenAddressFlag := flags.AddressFlag{
@@ -29,7 +34,7 @@ func newNEP11Commands() []cli.Command {
 		Usage: "Token contract address or hash in LE",
 	}
 	ownerAddressFlag := flags.AddressFlag{
-		Name:  "address",
+		Name:  "address, a",
 		Usage: "NFT owner address or hash in LE",
 	}
 	tokenID := cli.StringFlag{
@@ -105,8 +110,13 @@ func newNEP11Commands() []cli.Command {
 			Action:    transferNEP11,
 			Flags:     transferFlags,
 			Description: `Transfers specified NEP-11 token with optional cosigners list attached to
-   the transfer. Amount should be specified for divisible NEP-11
-   tokens and omitted for non-divisible NEP-11 tokens. See
+   the transfer. Amount can only be specified for divisible NEP-11 tokens
+   (having non-zero decimals), it's then passed to the 5-parameter
+   transfer(from, to, amount, tokenId, data) method and can't have more
+   fractional digits than the token has decimals. Without amount the
+   3-parameter transfer(to, tokenId, data) method is used (it transfers the
+   whole token for non-divisible NEP-11 tokens). Token ID is hex-encoded and
+   can't exceed 64 bytes. See
    'contract testinvokefunction' documentation for the details
    about cosigners syntax. If no cosigners are given then the
    sender with CalledByEntry scope will be used as the only
@@ -148,12 +158,31 @@ func newNEP11Commands() []cli.Command {
 		},
 		{
 			Name:      "tokensOf",
-			Usage:     "print list of tokens IDs for the specified NFT owner (" + maxIters + " will be printed at max)",
-			UsageText: "tokensOf --rpc-endpoint <node> [--timeout <time>] --token <hash> --address <addr> [--historic <block/hash>]",
-			Action:    printNEP11TokensOf,
+			Aliases:   []string{"tokensof"},
+			Usage:     "print list of tokens IDs for the specified NFT owner",
+			UsageText: "tokensOf --rpc-endpoint <node> [--timeout <time>] --token <hash> [-w wallet [--wallet-config path]] [--address <addr>] [--properties] [--json] [--historic <block/hash>]",
+			Description: `Prints IDs of all tokens owned by the given address (or by all accounts of
+   the given wallet if no address is specified) for the given NEP-11 contract
+   (divisible or non-divisible). Tokens are retrieved page by page via iterator
+   sessions, if the RPC server has sessions disabled only the first ` + maxIters + `
+   of them are printed. With --properties each token's properties are fetched
+   and printed along with its ID. --json prints the result as a JSON array of
+   {"owner", "id", "properties"} objects.
+`,
+			Action: printNEP11TokensOf,
 			Flags: append([]cli.Flag{
 				tokenAddressFlag,
 				ownerAddressFlag,
+				walletPathFlag,
+				walletConfigFlag,
+				cli.BoolFlag{
+					Name:  "properties",
+					Usage: "Print properties of each token",
+				},
+				cli.BoolFlag{
+					Name:  "json",
+					Usage: "Output JSON",
+				},
 				options.Historic,
 			}, options.RPC...),
 		},
@@ -287,15 +316,37 @@ func printNEP11Owner(ctx *cli.Context, divisible bool) error {
 	return nil
 }
 
+// nep11OwnedToken is a JSON representation of the token printed by tokensOf.
+type nep11OwnedToken struct {
+	Owner      string          `json:"owner"`
+	ID         string          `json:"id"`
+	Properties json.RawMessage `json:"properties,omitempty"`
+}
+
 func printNEP11TokensOf(ctx *cli.Context) error {
 	var err error
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
 	tokenHash := ctx.Generic("token").(*flags.Address)
 	if !tokenHash.IsSet {
 		return cli.NewExitError("token contract hash was not set", 1)
 	}
 
+	var owners []util.Uint160
 	acc := ctx.Generic("address").(*flags.Address)
-	if !acc.IsSet {
+	if acc.IsSet {
+		owners = append(owners, acc.Uint160())
+	} else if ctx.String("wallet") != "" || ctx.String("wallet-config") != "" {
+		wall, _, err := readWallet(ctx)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("bad wallet: %w", err), 1)
+		}
+		for _, a := range wall.Accounts {
+			owners = append(owners, a.ScriptHash())
+		}
+		wall.Close()
+	} else {
 		return cli.NewExitError("owner address flag was not set", 1)
 	}
 
@@ -307,16 +358,82 @@ func printNEP11TokensOf(ctx *cli.Context) error {
 		return err
 	}
 
-	n11 := nep11.NewBaseReader(inv, tokenHash.Uint160())
-	result, err := n11.TokensOfExpanded(acc.Uint160(), config.DefaultMaxIteratorResultItems)
+	var (
+		n11      = nep11.NewBaseReader(inv, tokenHash.Uint160())
+		withProp = ctx.Bool("properties")
+		toJSON   = ctx.Bool("json")
+		res      = []nep11OwnedToken{}
+	)
+	for _, owner := range owners {
+		ids, err := getNEP11TokensOf(n11, owner)
+		if err != nil {
+			return cli.NewExitError(fmt.Sprintf("failed to call NEP-11 `tokensOf` method: %s", err.Error()), 1)
+		}
+		ownerAddr := address.Uint160ToString(owner)
+		if !toJSON && len(owners) > 1 {
+			fmt.Fprintf(ctx.App.Writer, "Account %s\n", ownerAddr)
+		}
+		for _, id := range ids {
+			tok := nep11OwnedToken{
+				Owner: ownerAddr,
+				ID:    hex.EncodeToString(id),
+			}
+			if withProp {
+				props, err := n11.Properties(id)
+				if err != nil {
+					return cli.NewExitError(fmt.Sprintf("failed to call NEP-11 `properties` method for %s: %s", tok.ID, err), 1)
+				}
+				tok.Properties, err = stackitem.ToJSON(props)
+				if err != nil {
+					return cli.NewExitError(fmt.Sprintf("failed to convert properties of %s to JSON: %s", tok.ID, err), 1)
+				}
+			}
+			if toJSON {
+				res = append(res, tok)
+				continue
+			}
+			if withProp {
+				fmt.Fprintf(ctx.App.Writer, "%s\t%s\n", tok.ID, tok.Properties)
+			} else {
+				fmt.Fprintln(ctx.App.Writer, tok.ID)
+			}
+		}
+	}
+	if toJSON {
+		data, err := json.Marshal(res)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(data))
+	}
+	return nil
+}
+
+// getNEP11TokensOf returns all tokens owned by the given account. It traverses
+// session-based iterator page by page if possible and falls back to
+// in-VM iterator expansion (that is limited to
+// config.DefaultMaxIteratorResultItems) if the server has sessions disabled.
+func getNEP11TokensOf(n11 *nep11.BaseReader, owner util.Uint160) ([][]byte, error) {
+	iter, err := n11.TokensOf(owner)
+	if errors.Is(err, unwrap.ErrNoSessionID) {
+		return n11.TokensOfExpanded(owner, config.DefaultMaxIteratorResultItems)
+	}
 	if err != nil {
-		return cli.NewExitError(fmt.Sprintf("failed to call NEP-11 `tokensOf` method: %s", err.Error()), 1)
+		return nil, err
 	}
+	defer func() { _ = iter.Terminate() }()
 
-	for i := range result {
-		fmt.Fprintln(ctx.App.Writer, hex.EncodeToString(result[i]))
+	var res [][]byte
+	for {
+		page, err := iter.Next(config.DefaultMaxIteratorResultItems)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, page...)
+		if len(page) < config.DefaultMaxIteratorResultItems {
+			return res, nil
+		}
 	}
-	return nil
 }
 
 func printNEP11Tokens(ctx *cli.Context) error {
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -15,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
@@ -52,6 +54,36 @@ var (
 			Usage: "Token contract address or hash in LE",
 		},
 	}, options.RPC...)
+	importMetadataFlags = []cli.Flag{
+		cli.StringFlag{
+			Name:  "symbol",
+			Usage: "Token symbol, token is imported without RPC requests if set (--decimals is required then)",
+		},
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "Token name (symbol is used if not set, with --symbol only)",
+		},
+		cli.IntFlag{
+			Name:  "decimals",
+			Usage: "Token decimals (with --symbol only)",
+		},
+	}
+	allBalancesFlag = cli.BoolFlag{
+		Name:  "all",
+		Usage: "Print all tokens tracked by the node for the account (--token is a comma-separated fallback list then)",
+	}
+	jsonBalancesFlag = cli.BoolFlag{
+		Name:  "json",
+		Usage: "Output JSON (with --all only)",
+	}
+	transferAllFlag = cli.BoolFlag{
+		Name:  "all",
+		Usage: "Transfer the whole token balance (transaction fees are subtracted from it for GAS)",
+	}
+	dataFlag = cli.StringFlag{
+		Name:  "data",
+		Usage: "'data' parameter passed to the receiver's onNEP17Payment method (int:42, string:deposit, bytes:<hex>, hash160:<hash> etc.)",
+	}
 	baseTransferFlags = []cli.Flag{
 		walletPathFlag,
 		walletConfigFlag,
@@ -62,6 +94,7 @@ var (
 		txctx.GasFlag,
 		txctx.SysGasFlag,
 		txctx.ForceFlag,
+		txctx.VerifyParamFlag,
 		cli.StringFlag{
 			Name:  "amount",
 			Usage: "Amount of asset to send",
@@ -75,21 +108,27 @@ var (
 		txctx.GasFlag,
 		txctx.SysGasFlag,
 		txctx.ForceFlag,
+		txctx.VerifyParamFlag,
 	}, options.RPC...)
 )
 
 func newNEP17Commands() []cli.Command {
 	balanceFlags := make([]cli.Flag, len(baseBalanceFlags))
 	copy(balanceFlags, baseBalanceFlags)
+	balanceFlags = append(balanceFlags, allBalancesFlag, jsonBalancesFlag)
 	balanceFlags = append(balanceFlags, options.RPC...)
 	transferFlags := make([]cli.Flag, len(baseTransferFlags))
 	copy(transferFlags, baseTransferFlags)
+	transferFlags = append(transferFlags, transferAllFlag, idempotencyKeyFlag, dataFlag)
 	transferFlags = append(transferFlags, options.RPC...)
+	importNEP17Flags := make([]cli.Flag, len(importFlags))
+	copy(importNEP17Flags, importFlags)
+	importNEP17Flags = append(importNEP17Flags, importMetadataFlags...)
 	return []cli.Command{
 		{
 			Name:      "balance",
 			Usage:     "get address balance",
-			UsageText: "balance -w wallet [--wallet-config path] --rpc-endpoint <node> [--timeout <time>] [--address <address>] [--token <hash-or-name>]",
+			UsageText: "balance -w wallet [--wallet-config path] --rpc-endpoint <node> [--timeout <time>] [--address <address>] [--token <hash-or-name>] [--all [--json]]",
 			Description: `Prints NEP-17 balances for address and tokens specified. By default (no
    address or token parameter) all tokens for all accounts in the specified wallet
    are listed. A single account can be chosen with the address option and/or a
@@ -102,6 +141,15 @@ func newNEP17Commands() []cli.Command {
    not found in the wallet then depending on the balances data from the server
    this command can print no data at all or print multiple tokens for one
    account (if they use the same names/symbols).
+
+   With --all every token tracked by the node (via getnep17balances) is
+   printed for the account(s) irrespective of the token option, the wallet is
+   not required then if the address is given. If the node doesn't track NEP-17
+   balances, tokens specified via --token (a comma-separated list of hashes,
+   addresses or names) are queried directly. --json prints the result as a
+   JSON array of {"address", "tracking", "balances"} objects where every
+   balance has "assethash", "symbol", "name", "decimals", "amount" (with
+   decimals applied) and "lastupdatedblock" (omitted if unknown) fields.
 `,
 			Action: getNEP17Balance,
 			Flags:  balanceFlags,
@@ -109,9 +157,15 @@ func newNEP17Commands() []cli.Command {
 		{
 			Name:      "import",
 			Usage:     "import NEP-17 token to a wallet",
-			UsageText: "import -w wallet [--wallet-config path] --rpc-endpoint <node> --timeout <time> --token <hash>",
-			Action:    importNEP17Token,
-			Flags:     importFlags,
+			UsageText: "import -w wallet [--wallet-config path] [--rpc-endpoint <node> --timeout <time>] --token <hash> [--symbol <symbol> --decimals <decimals> [--name <name>]]",
+			Description: `Imports NEP-17 token metadata (name, symbol and decimals) into the wallet,
+   so that the token can be referred to by its symbol or name in other
+   commands. Metadata is fetched from the RPC node by default, but it can be
+   specified with --symbol, --decimals and --name flags instead, then no RPC
+   requests are made.
+`,
+			Action: importNEP17Token,
+			Flags:  importNEP17Flags,
 		},
 		{
 			Name:      "info",
@@ -139,7 +193,7 @@ func newNEP17Commands() []cli.Command {
 		{
 			Name:      "transfer",
 			Usage:     "transfer NEP-17 tokens",
-			UsageText: "transfer -w wallet [--wallet-config path] --rpc-endpoint <node> --timeout <time> --from <addr> --to <addr> --token <hash-or-name> --amount string [data] [-- <cosigner1:Scope> [<cosigner2> [...]]]",
+			UsageText: "transfer -w wallet [--wallet-config path] --rpc-endpoint <node> --timeout <time> --from <addr> --to <addr> --token <hash-or-name> --amount string|--all [--data <param>] [data] [-- <cosigner1:Scope> [<cosigner2> [...]]]",
 			Action:    transferNEP17,
 			Flags:     transferFlags,
 			Description: `Transfers specified NEP-17 token amount with optional 'data' parameter and cosigners
@@ -147,6 +201,30 @@ func newNEP17Commands() []cli.Command {
    for the details about 'data' parameter and cosigners syntax. If no 'data' is
    given then default nil value will be used. If no cosigners are given then the
    sender with CalledByEntry scope will be used as the only signer.
+
+   The transfer is test-invoked to calculate its system fee, network fee is
+   calculated for the signers given. Both fees (including the --gas and
+   --sysgas additions) and the total GAS cost are printed and the transaction
+   is only sent after confirmation unless --force is used.
+
+   Non-array 'data' can also be given with --data flag using the same parameter
+   syntax (like 'int:42', 'string:deposit' or 'hash160:<hash>'), it can't be
+   combined with the positional 'data' argument.
+
+   With --all the whole token balance of the sender is transferred (--amount
+   can't be used then). For GAS the transaction fees (including the --gas and
+   --sysgas additions) are subtracted from the amount sent, so that the account
+   is emptied, for other tokens the fees are paid from the GAS balance as
+   usual. The command fails if the balance is not enough to pay the fees.
+
+   If --idempotency-key is given, the hash of the transaction to be sent is
+   stored (in the user config directory) for this key and the current network
+   before sending it, it's removed only if the node rejects the transaction. If the
+   command is repeated with the same key and this transaction is known to the
+   network (either mempooled or accepted), its hash is printed and no new
+   transaction is sent. If it's not known and its ValidUntilBlock has passed,
+   the key is forgotten and a new transaction is sent. This option can't be
+   used with --out.
 `,
 		},
 		{
@@ -167,6 +245,12 @@ func tokenMatch(curToken *wallet.Token, expToken *wallet.Token, name string) boo
 }
 
 func getNEP17Balance(ctx *cli.Context) error {
+	if ctx.Bool("all") {
+		return getAllNEP17Balances(ctx)
+	}
+	if ctx.Bool("json") {
+		return cli.NewExitError("--json can only be used with --all", 1)
+	}
 	return getNEPBalance(ctx, manifest.NEP17StandardName, func(ctx *cli.Context, c *rpcclient.Client, addrHash util.Uint160, name string, token *wallet.Token, _ string) error {
 		balances, err := c.GetNEP17Balances(addrHash)
 		if err != nil {
@@ -202,6 +286,165 @@ func getNEP17Balance(ctx *cli.Context) error {
 	})
 }
 
+// nep17AccountBalances is the account data printed by 'balance --all --json'.
+type nep17AccountBalances struct {
+	Address string `json:"address"`
+	// Tracking is false if the node doesn't track NEP-17 balances, so
+	// only explicitly specified tokens are included.
+	Tracking bool                  `json:"tracking"`
+	Balances []nep17AccountBalance `json:"balances"`
+}
+
+type nep17AccountBalance struct {
+	Asset       util.Uint160 `json:"assethash"`
+	Symbol      string       `json:"symbol"`
+	Name        string       `json:"name"`
+	Decimals    int          `json:"decimals"`
+	Amount      string       `json:"amount"`
+	LastUpdated uint32       `json:"lastupdatedblock,omitempty"`
+}
+
+func getAllNEP17Balances(ctx *cli.Context) error {
+	var (
+		accounts []util.Uint160
+		wall     *wallet.Wallet
+		addrFlag = ctx.Generic("address").(*flags.Address)
+		toJSON   = ctx.Bool("json")
+	)
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	if addrFlag.IsSet && ctx.String("wallet") == "" && ctx.String("wallet-config") == "" {
+		accounts = append(accounts, addrFlag.Uint160())
+	} else {
+		w, _, err := readWallet(ctx)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("bad wallet: %w", err), 1)
+		}
+		defer w.Close()
+		wall = w
+		if addrFlag.IsSet {
+			if w.GetAccount(addrFlag.Uint160()) == nil {
+				return cli.NewExitError(fmt.Errorf("can't find account for the address: %s", address.Uint160ToString(addrFlag.Uint160())), 1)
+			}
+			accounts = append(accounts, addrFlag.Uint160())
+		} else {
+			if len(w.Accounts) == 0 {
+				return cli.NewExitError(errors.New("no accounts in the wallet"), 1)
+			}
+			for _, acc := range w.Accounts {
+				accounts = append(accounts, acc.ScriptHash())
+			}
+		}
+	}
+
+	gctx, cancel := options.GetTimeoutContext(ctx)
+	defer cancel()
+
+	c, inv, exitErr := options.GetRPCWithInvoker(gctx, ctx, nil)
+	if exitErr != nil {
+		return exitErr
+	}
+
+	var (
+		res    = make([]nep17AccountBalances, 0, len(accounts))
+		tokens []*wallet.Token
+	)
+	for _, acc := range accounts {
+		accBalances := nep17AccountBalances{
+			Address:  address.Uint160ToString(acc),
+			Tracking: true,
+			Balances: []nep17AccountBalance{},
+		}
+		bs, err := c.GetNEP17Balances(acc)
+		var rpcErr *neorpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.Code == neorpc.MethodNotFoundCode {
+			accBalances.Tracking = false
+		} else if err != nil {
+			return cli.NewExitError(fmt.Errorf("failed to get balances of %s: %w", accBalances.Address, err), 1)
+		}
+		if accBalances.Tracking {
+			for _, b := range bs.Balances {
+				accBalances.Balances = append(accBalances.Balances, nep17AccountBalance{
+					Asset:       b.Asset,
+					Symbol:      b.Symbol,
+					Name:        b.Name,
+					Decimals:    b.Decimals,
+					Amount:      decimalAmount(b.Amount, b.Decimals),
+					LastUpdated: b.LastUpdated,
+				})
+			}
+		} else {
+			if tokens == nil {
+				fmt.Fprintln(ctx.App.ErrWriter, "NEP-17 balance tracking is disabled on the node, querying specified tokens only")
+				tokens, err = getNEP17TokenList(ctx, c, wall)
+				if err != nil {
+					return cli.NewExitError(err, 1)
+				}
+			}
+			for _, t := range tokens {
+				amount, err := nep17.NewReader(inv, t.Hash).BalanceOf(acc)
+				if err != nil {
+					return cli.NewExitError(fmt.Errorf("failed to get %s balance of %s: %w", t.Symbol, accBalances.Address, err), 1)
+				}
+				accBalances.Balances = append(accBalances.Balances, nep17AccountBalance{
+					Asset:    t.Hash,
+					Symbol:   t.Symbol,
+					Name:     t.Name,
+					Decimals: int(t.Decimals),
+					Amount:   decimalAmount(amount.String(), int(t.Decimals)),
+				})
+			}
+		}
+		res = append(res, accBalances)
+	}
+
+	if toJSON {
+		data, err := json.Marshal(res)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(data))
+		return nil
+	}
+	for i, accBalances := range res {
+		if i != 0 {
+			fmt.Fprintln(ctx.App.Writer)
+		}
+		fmt.Fprintf(ctx.App.Writer, "Account %s\n", accBalances.Address)
+		for _, b := range accBalances.Balances {
+			fmt.Fprintf(ctx.App.Writer, "%s: %s (%s)\n", b.Symbol, b.Name, b.Asset.StringLE())
+			fmt.Fprintf(ctx.App.Writer, "\tAmount : %s\n", b.Amount)
+			if accBalances.Tracking {
+				fmt.Fprintf(ctx.App.Writer, "\tUpdated: %d\n", b.LastUpdated)
+			}
+		}
+	}
+	return nil
+}
+
+// getNEP17TokenList returns tokens from the comma-separated --token list, they
+// must be known (or be given as hashes).
+func getNEP17TokenList(ctx *cli.Context, c *rpcclient.Client, wall *wallet.Wallet) ([]*wallet.Token, error) {
+	list := ctx.String("token")
+	if list == "" {
+		return nil, errors.New("no tokens specified, use --token to list them")
+	}
+	var tokens []*wallet.Token
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		t, err := findToken(ctx, c, wall, name, manifest.NEP17StandardName)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, fmt.Errorf("can't find %q token", name)
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
 func getNEPBalance(ctx *cli.Context, standard string, accHandler func(*cli.Context, *rpcclient.Client, util.Uint160, string, *wallet.Token, string) error) error {
 	var accounts []*wallet.Account
 
@@ -241,30 +484,9 @@ func getNEPBalance(ctx *cli.Context, standard string, accHandler func(*cli.Conte
 	var token *wallet.Token
 
 	if name != "" {
-		// Token was explicitly specified, let's try finding it, search in the wallet first.
-		token, err = getMatchingToken(ctx, wall, name, standard)
+		token, err = findToken(ctx, c, wall, name, standard)
 		if err != nil {
-			var h util.Uint160
-
-			// Well-known hardcoded names/symbols.
-			if standard == manifest.NEP17StandardName && (name == nativenames.Neo || name == "NEO") {
-				h = neo.Hash
-			} else if standard == manifest.NEP17StandardName && (name == nativenames.Gas || name == "GAS") {
-				h = gas.Hash
-			} else {
-				// The last resort, maybe it's a direct hash or address.
-				h, _ = flags.ParseAddress(name)
-			}
-			// If the hash is not found then it's some kind of named token, there is
-			// no way for us to find it, but it's not an error, maybe we'll find it
-			// in balances.
-			if !h.Equals(util.Uint160{}) {
-				// But if we have an exact hash, it must be correct.
-				token, err = getTokenWithStandard(c, h, standard)
-				if err != nil {
-					return cli.NewExitError(fmt.Errorf("%q is not a valid %s token: %w", name, standard, err), 1)
-				}
-			}
+			return cli.NewExitError(err, 1)
 		}
 	}
 	tokenID := ctx.String("id")
@@ -290,6 +512,41 @@ func getNEPBalance(ctx *cli.Context, standard string, accHandler func(*cli.Conte
 	return nil
 }
 
+// findToken looks for the token specified by the user in the wallet (if
+// given) first and then tries well-known names and direct hashes/addresses.
+// Nil token is returned if nothing matches the name.
+func findToken(ctx *cli.Context, c *rpcclient.Client, wall *wallet.Wallet, name string, standard string) (*wallet.Token, error) {
+	if wall != nil {
+		token, err := getMatchingToken(ctx, wall, name, standard)
+		if err == nil {
+			return token, nil
+		}
+	}
+	var h util.Uint160
+
+	// Well-known hardcoded names/symbols.
+	if standard == manifest.NEP17StandardName && (name == nativenames.Neo || name == "NEO") {
+		h = neo.Hash
+	} else if standard == manifest.NEP17StandardName && (name == nativenames.Gas || name == "GAS") {
+		h = gas.Hash
+	} else {
+		// The last resort, maybe it's a direct hash or address.
+		h, _ = flags.ParseAddress(name)
+	}
+	// If the hash is not found then it's some kind of named token, there is
+	// no way for us to find it, but it's not an error, maybe we'll find it
+	// in balances.
+	if h.Equals(util.Uint160{}) {
+		return nil, nil
+	}
+	// But if we have an exact hash, it must be correct.
+	token, err := getTokenWithStandard(c, h, standard)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid %s token: %w", name, standard, err)
+	}
+	return token, nil
+}
+
 func decimalAmount(amount string, decimals int) string {
 	if decimals != 0 {
 		b, ok := new(big.Int).SetString(amount, 10)
@@ -393,17 +650,25 @@ func importNEPToken(ctx *cli.Context, standard string) error {
 		}
 	}
 
-	gctx, cancel := options.GetTimeoutContext(ctx)
-	defer cancel()
+	var tok *wallet.Token
+	if standard == manifest.NEP17StandardName && ctx.IsSet("symbol") {
+		tok, err = tokenFromFlags(ctx, tokenHash, standard)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	} else {
+		gctx, cancel := options.GetTimeoutContext(ctx)
+		defer cancel()
 
-	c, err := options.GetRPCClient(gctx, ctx)
-	if err != nil {
-		return cli.NewExitError(err, 1)
-	}
+		c, exitErr := options.GetRPCClient(gctx, ctx)
+		if exitErr != nil {
+			return cli.NewExitError(exitErr, 1)
+		}
 
-	tok, err := getTokenWithStandard(c, tokenHash, standard)
-	if err != nil {
-		return cli.NewExitError(fmt.Errorf("can't receive token info: %w", err), 1)
+		tok, err = getTokenWithStandard(c, tokenHash, standard)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("can't receive token info: %w", err), 1)
+		}
 	}
 
 	wall.AddToken(tok)
@@ -414,6 +679,26 @@ func importNEPToken(ctx *cli.Context, standard string) error {
 	return nil
 }
 
+// tokenFromFlags creates token with metadata specified via command line flags.
+func tokenFromFlags(ctx *cli.Context, hash util.Uint160, std string) (*wallet.Token, error) {
+	symbol := ctx.String("symbol")
+	if symbol == "" {
+		return nil, errors.New("empty token symbol")
+	}
+	if !ctx.IsSet("decimals") {
+		return nil, errors.New("token decimals should be specified along with the symbol")
+	}
+	decimals := ctx.Int("decimals")
+	if decimals < 0 {
+		return nil, fmt.Errorf("invalid token decimals: %d", decimals)
+	}
+	name := ctx.String("name")
+	if name == "" {
+		name = symbol
+	}
+	return wallet.NewToken(hash, name, symbol, int64(decimals), std), nil
+}
+
 func getTokenWithStandard(c *rpcclient.Client, hash util.Uint160, std string) (*wallet.Token, error) {
 	token, err := neptoken.Info(c, hash)
 	if err != nil {
@@ -640,6 +925,15 @@ func transferNEP(ctx *cli.Context, standard string) error {
 	if extErr != nil {
 		return extErr
 	}
+	if dataFlagValue := ctx.String("data"); dataFlagValue != "" {
+		if data != nil {
+			return cli.NewExitError(errors.New("'data' can't be given both as an argument and with --data"), 1)
+		}
+		data, err = cmdargs.ParseData(dataFlagValue)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("unable to parse --data: %w", err), 1)
+		}
+	}
 
 	cosigners, extErr := cmdargs.GetSignersFromContext(ctx, cosignersOffset)
 	if extErr != nil {
@@ -654,14 +948,44 @@ func transferNEP(ctx *cli.Context, standard string) error {
 		return cli.NewExitError(fmt.Errorf("failed to create RPC actor: %w", err), 1)
 	}
 
+	var (
+		idemKey  = ctx.String(idempotencyKeyFlag.Name)
+		idemPath string
+	)
+	if idemKey != "" {
+		if ctx.String("out") != "" {
+			return cli.NewExitError(errors.New("--idempotency-key can't be used with --out"), 1)
+		}
+		idemPath, err = getIdempotencyPath()
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		h, err := checkIdempotencyKey(c, idemPath, act.GetNetwork(), idemKey)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		if h != nil {
+			fmt.Fprintln(ctx.App.Writer, h.StringLE())
+			return nil
+		}
+	}
+
 	amountArg := ctx.String("amount")
+	transferAll := standard == manifest.NEP17StandardName && ctx.Bool(transferAllFlag.Name)
+	if transferAll && amountArg != "" {
+		return cli.NewExitError(errors.New("--amount can't be used with --all"), 1)
+	}
 	amount, err := fixedn.FromString(amountArg, int(token.Decimals))
 	// It's OK for NEP-11 transfer to not have amount set.
-	if err != nil && (standard == manifest.NEP17StandardName || amountArg != "") {
-		return cli.NewExitError(fmt.Errorf("invalid amount: %w", err), 1)
+	if err != nil && !transferAll && (standard == manifest.NEP17StandardName || amountArg != "") {
+		return cli.NewExitError(fmt.Errorf("invalid amount (token has %d decimals): %w", token.Decimals, err), 1)
 	}
 	switch standard {
 	case manifest.NEP17StandardName:
+		if transferAll {
+			tx, err = makeTransferAllNEP17(ctx, act, token, to, data)
+			break
+		}
 		n17 := nep17.New(act, token.Hash)
 		tx, err = n17.TransferUnsigned(act.Sender(), to, amount, data)
 	case manifest.NEP11StandardName:
@@ -673,6 +997,17 @@ func transferNEP(ctx *cli.Context, standard string) error {
 		if terr != nil {
 			return cli.NewExitError(fmt.Errorf("invalid token ID: %w", terr), 1)
 		}
+		if len(tokenIDBytes) > maxNEP11TokenIDLength {
+			return cli.NewExitError(fmt.Errorf("invalid token ID: %d bytes long, it can't exceed %d", len(tokenIDBytes), maxNEP11TokenIDLength), 1)
+		}
+		if amountArg != "" {
+			if token.Decimals == 0 {
+				return cli.NewExitError(errors.New("amount can't be specified for non-divisible NEP-11 token"), 1)
+			}
+			if amount.Sign() < 0 {
+				return cli.NewExitError(errors.New("invalid amount: negative value"), 1)
+			}
+		}
 		if amountArg == "" {
 			n11 := nep11.NewNonDivisible(act, token.Hash)
 			tx, err = n11.TransferUnsigned(to, tokenIDBytes, data)
@@ -687,7 +1022,81 @@ func transferNEP(ctx *cli.Context, standard string) error {
 		return cli.NewExitError(fmt.Errorf("can't make transaction: %w", err), 1)
 	}
 
-	return txctx.SignAndSend(ctx, act, acc, tx)
+	if idemKey == "" {
+		return txctx.SignAndSend(ctx, act, acc, tx)
+	}
+	// The key is stored before sending, so that a retry after any failure
+	// that doesn't definitely reject the transaction (like a lost RPC
+	// response) can't lead to a double transfer.
+	return txctx.SignAndSendWithHooks(ctx, act, acc, tx, txctx.SendHooks{
+		BeforeSend: func(tx *transaction.Transaction) error {
+			if err := saveIdempotencyKey(idemPath, act.GetNetwork(), idemKey, tx); err != nil {
+				return fmt.Errorf("idempotency key can't be saved: %w", err)
+			}
+			return nil
+		},
+		SendFailed: func(tx *transaction.Transaction, err error) {
+			if !isTxRejected(err) {
+				return
+			}
+			if err := forgetIdempotencyKey(idemPath, act.GetNetwork(), idemKey, tx); err != nil {
+				fmt.Fprintf(ctx.App.ErrWriter, "transaction is rejected, but idempotency key can't be removed: %s\n", err)
+			}
+		},
+	})
+}
+
+// makeTransferAllNEP17 creates a transaction transferring the whole token
+// balance of the sender. For GAS the transaction fees are subtracted from the
+// amount, for other tokens the sender's GAS balance is checked to cover them.
+func makeTransferAllNEP17(ctx *cli.Context, act *actor.Actor, token *wallet.Token, to util.Uint160, data interface{}) (*transaction.Transaction, error) {
+	var (
+		n17      = nep17.New(act, token.Hash)
+		extraFee = int64(flags.Fixed8FromContext(ctx, "gas") + flags.Fixed8FromContext(ctx, "sysgas"))
+		fee      = func(tx *transaction.Transaction) int64 {
+			return tx.SystemFee + tx.NetworkFee + extraFee
+		}
+	)
+	balance, err := n17.BalanceOf(act.Sender())
+	if err != nil {
+		return nil, fmt.Errorf("can't get %s balance: %w", token.Symbol, err)
+	}
+	if balance.Sign() <= 0 {
+		return nil, fmt.Errorf("nothing to transfer, %s balance is zero", token.Symbol)
+	}
+	tx, err := n17.TransferUnsigned(act.Sender(), to, balance, data)
+	if err != nil {
+		return nil, err
+	}
+	if !token.Hash.Equals(gas.Hash) {
+		gasBalance, err := gas.New(act).BalanceOf(act.Sender())
+		if err != nil {
+			return nil, fmt.Errorf("can't get GAS balance: %w", err)
+		}
+		if gasBalance.Cmp(big.NewInt(fee(tx))) < 0 {
+			return nil, fmt.Errorf("insufficient GAS balance to pay fees: %s GAS needed, %s GAS available",
+				fixedn.Fixed8(fee(tx)), fixedn.ToString(gasBalance, 8))
+		}
+		return tx, nil
+	}
+	// Network fee depends on the transaction size and thus on the amount, it
+	// can only decrease along with the amount, so this converges quickly.
+	for i := 0; i < 3; i++ {
+		amount := new(big.Int).Sub(balance, big.NewInt(fee(tx)))
+		if amount.Sign() <= 0 {
+			return nil, fmt.Errorf("GAS balance %s doesn't cover transaction fees %s",
+				fixedn.ToString(balance, 8), fixedn.Fixed8(fee(tx)))
+		}
+		newTx, err := n17.TransferUnsigned(act.Sender(), to, amount, data)
+		if err != nil {
+			return nil, err
+		}
+		if fee(newTx) == fee(tx) {
+			return newTx, nil
+		}
+		tx = newTx
+	}
+	return nil, errors.New("can't calculate transaction fees for the whole GAS balance")
 }
 
 func makeMultiTransferNEP17(act *actor.Actor, recipients []rpcclient.TransferTarget) (*transaction.Transaction, error) {
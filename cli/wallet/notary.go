@@ -0,0 +1,28 @@
+package wallet
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/cli/flags"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/notary"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/urfave/cli"
+)
+
+func notaryDeposit(ctx *cli.Context) error {
+	amount := flags.Fixed8FromContext(ctx, "amount")
+	if amount <= 0 {
+		return cli.NewExitError(errors.New("positive amount is required"), 1)
+	}
+	till := ctx.Uint("till")
+	if till == 0 {
+		return cli.NewExitError(errors.New("deposit lock height is required"), 1)
+	}
+	return handleAccountAction(ctx, func(act *actor.Actor, addr util.Uint160, _ *wallet.Account) (*transaction.Transaction, error) {
+		return notary.DepositTransferUnsigned(act, addr, nil, big.NewInt(int64(amount)), uint32(till))
+	})
+}
@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/cli/cmdargs"
+	"github.com/nspcc-dev/neo-go/cli/input"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/remote"
+	"github.com/urfave/cli"
+)
+
+// remoteRetries is the number of retries for failed remote signer requests.
+const remoteRetries = 2
+
+// ConnectRemote connects the given remote account to its signer, every
+// signature made with it needs to be confirmed by user then.
+func ConnectRemote(acc *wallet.Account) error {
+	err := acc.Connect(context.Background(), remote.Options{
+		Retries: remoteRetries,
+		Confirm: input.ConfirmRemoteSign,
+	})
+	if err != nil {
+		return fmt.Errorf("can't connect to remote signer: %w", err)
+	}
+	return nil
+}
+
+func importRemote(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	signerURL := ctx.String("signer")
+	if signerURL == "" {
+		return cli.NewExitError(errors.New("remote signer URL is required"), 1)
+	}
+	keyID := ctx.String("key")
+	if keyID == "" {
+		return cli.NewExitError(errors.New("key ID is required"), 1)
+	}
+	wall, _, err := openWallet(ctx, true)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	c, err := remote.New(signerURL, remote.Options{Retries: remoteRetries})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	ks, err := c.ListKeys(context.Background())
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to list remote keys: %w", err), 1)
+	}
+	var acc *wallet.Account
+	for i := range ks {
+		if ks[i].ID == keyID {
+			acc = wallet.NewRemoteAccount(signerURL, keyID, ks[i].PublicKey)
+			break
+		}
+	}
+	if acc == nil {
+		return cli.NewExitError(fmt.Errorf("key %q is not found in the remote signer", keyID), 1)
+	}
+	acc.Label = ctx.String("name")
+	if err := addAccountAndSave(wall, acc); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	fmt.Fprintln(ctx.App.Writer, acc.Address)
+	return nil
+}
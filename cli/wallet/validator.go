@@ -1,18 +1,26 @@
 package wallet
 
 import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/cli/flags"
 	"github.com/nspcc-dev/neo-go/cli/input"
 	"github.com/nspcc-dev/neo-go/cli/options"
 	"github.com/nspcc-dev/neo-go/cli/txctx"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/neo"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/urfave/cli"
@@ -23,7 +31,7 @@ func newValidatorCommands() []cli.Command {
 		{
 			Name:      "register",
 			Usage:     "register as a new candidate",
-			UsageText: "register -w <path> -r <rpc> -a <addr> [-g gas] [-e sysgas] [--out file] [--force]",
+			UsageText: "register -w <path> -r <rpc> -a <addr> [-g gas] [-e sysgas] [--max-fee fee] [--out file] [--force]",
 			Action:    handleRegister,
 			Flags: append([]cli.Flag{
 				walletPathFlag,
@@ -32,6 +40,8 @@ func newValidatorCommands() []cli.Command {
 				txctx.SysGasFlag,
 				txctx.OutFlag,
 				txctx.ForceFlag,
+				txctx.MaxFeeFlag,
+				txctx.VerifyParamFlag,
 				flags.AddressFlag{
 					Name:  "address, a",
 					Usage: "Address to register",
@@ -41,7 +51,7 @@ func newValidatorCommands() []cli.Command {
 		{
 			Name:      "unregister",
 			Usage:     "unregister self as a candidate",
-			UsageText: "unregister -w <path> -r <rpc> -a <addr> [-g gas] [-e sysgas] [--out file] [--force]",
+			UsageText: "unregister -w <path> -r <rpc> -a <addr> [-g gas] [-e sysgas] [--max-fee fee] [--out file] [--force]",
 			Action:    handleUnregister,
 			Flags: append([]cli.Flag{
 				walletPathFlag,
@@ -50,6 +60,8 @@ func newValidatorCommands() []cli.Command {
 				txctx.SysGasFlag,
 				txctx.OutFlag,
 				txctx.ForceFlag,
+				txctx.MaxFeeFlag,
+				txctx.VerifyParamFlag,
 				flags.AddressFlag{
 					Name:  "address, a",
 					Usage: "Address to unregister",
@@ -59,7 +71,7 @@ func newValidatorCommands() []cli.Command {
 		{
 			Name:      "vote",
 			Usage:     "vote for a validator",
-			UsageText: "vote -w <path> -r <rpc> [-s <timeout>] [-g gas] [-e sysgas] -a <addr> [-c <public key>] [--out file] [--force]",
+			UsageText: "vote -w <path> -r <rpc> [-s <timeout>] [-g gas] [-e sysgas] [--max-fee fee] -a <addr> [-c <public key>] [--out file] [--force]",
 			Description: `Votes for a validator by calling "vote" method of a NEO native
    contract. Do not provide candidate argument to perform unvoting.
 `,
@@ -71,6 +83,8 @@ func newValidatorCommands() []cli.Command {
 				txctx.SysGasFlag,
 				txctx.OutFlag,
 				txctx.ForceFlag,
+				txctx.MaxFeeFlag,
+				txctx.VerifyParamFlag,
 				flags.AddressFlag{
 					Name:  "address, a",
 					Usage: "Address to vote from",
@@ -81,6 +95,125 @@ func newValidatorCommands() []cli.Command {
 				},
 			}, options.RPC...),
 		},
+		{
+			Name:      "list",
+			Usage:     "list registered candidates",
+			UsageText: "list -r <rpc> [-s <timeout>] [--sort <key|votes>] [--json]",
+			Description: `Prints all registered candidates with their votes and flags showing
+   whether they're in the current committee and whether they're validators
+   for the next block. Candidates are sorted by their keys by default, use
+   '--sort votes' to sort them by the number of votes (descending).
+`,
+			Action: listCandidates,
+			Flags: append([]cli.Flag{
+				cli.StringFlag{
+					Name:  "sort",
+					Usage: "Sort candidates by 'key' (default) or 'votes'",
+				},
+				cli.BoolFlag{
+					Name:  "json",
+					Usage: "Output JSON",
+				},
+			}, options.RPC...),
+		},
+	}
+}
+
+// candidateInfo is a candidate description printed by the list command.
+type candidateInfo struct {
+	PublicKey *keys.PublicKey `json:"publickey"`
+	Votes     int64           `json:"votes"`
+	Committee bool            `json:"committee"`
+	Validator bool            `json:"validator"`
+}
+
+func listCandidates(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	sortBy := ctx.String("sort")
+	if sortBy != "" && sortBy != "key" && sortBy != "votes" {
+		return cli.NewExitError(fmt.Errorf("invalid sort order: %s", sortBy), 1)
+	}
+
+	gctx, cancel := options.GetTimeoutContext(ctx)
+	defer cancel()
+
+	_, inv, exitErr := options.GetRPCWithInvoker(gctx, ctx, nil)
+	if exitErr != nil {
+		return exitErr
+	}
+	neoToken := neo.NewReader(inv)
+	vals, err := getAllCandidates(neoToken)
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get candidates: %w", err), 1)
+	}
+	comm, err := neoToken.GetCommittee()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get committee: %w", err), 1)
+	}
+	next, err := neoToken.GetNextBlockValidators()
+	if err != nil {
+		return cli.NewExitError(fmt.Errorf("failed to get next block validators: %w", err), 1)
+	}
+
+	res := make([]candidateInfo, len(vals))
+	for i := range vals {
+		res[i] = candidateInfo{
+			PublicKey: &vals[i].PublicKey,
+			Votes:     vals[i].Votes,
+			Committee: comm.Contains(&vals[i].PublicKey),
+			Validator: next.Contains(&vals[i].PublicKey),
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if sortBy == "votes" && res[i].Votes != res[j].Votes {
+			return res[i].Votes > res[j].Votes
+		}
+		return res[i].PublicKey.Cmp(res[j].PublicKey) == -1
+	})
+
+	if ctx.Bool("json") {
+		data, err := json.Marshal(res)
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		fmt.Fprintln(ctx.App.Writer, string(data))
+		return nil
+	}
+	tw := tabwriter.NewWriter(ctx.App.Writer, 0, 2, 2, ' ', 0)
+	_, _ = tw.Write([]byte("Key\tVotes\tCommittee\tValidator\n"))
+	for _, c := range res {
+		_, _ = tw.Write([]byte(fmt.Sprintf("%s\t%d\t%t\t%t\n", hex.EncodeToString(c.PublicKey.Bytes()), c.Votes, c.Committee, c.Validator)))
+	}
+	_ = tw.Flush()
+	return nil
+}
+
+// getAllCandidates returns all registered candidates. It traverses
+// session-based iterator page by page if possible and falls back to in-VM
+// iterator expansion (that is limited to config.DefaultMaxIteratorResultItems)
+// if the server has sessions disabled.
+func getAllCandidates(neoToken *neo.ContractReader) ([]result.Validator, error) {
+	iter, err := neoToken.GetAllCandidates()
+	if errors.Is(err, unwrap.ErrNoSessionID) {
+		return neoToken.GetAllCandidatesExpanded(config.DefaultMaxIteratorResultItems)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = iter.Terminate() }()
+
+	var res []result.Validator
+	for {
+		page, err := iter.Next(config.DefaultMaxIteratorResultItems)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, page...)
+		if len(page) < config.DefaultMaxIteratorResultItems {
+			return res, nil
+		}
 	}
 }
 
@@ -97,6 +230,14 @@ func handleUnregister(ctx *cli.Context) error {
 }
 
 func handleNeoAction(ctx *cli.Context, mkTx func(*neo.Contract, util.Uint160, *wallet.Account) (*transaction.Transaction, error)) error {
+	return handleAccountAction(ctx, func(act *actor.Actor, addr util.Uint160, acc *wallet.Account) (*transaction.Transaction, error) {
+		return mkTx(neo.New(act), addr, acc)
+	})
+}
+
+// handleAccountAction creates a transaction using mkTx for the account
+// specified by the --address flag and signs/sends it.
+func handleAccountAction(ctx *cli.Context, mkTx func(*actor.Actor, util.Uint160, *wallet.Account) (*transaction.Transaction, error)) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
 	}
@@ -128,8 +269,7 @@ func handleNeoAction(ctx *cli.Context, mkTx func(*neo.Contract, util.Uint160, *w
 		return cli.NewExitError(fmt.Errorf("RPC actor issue: %w", err), 1)
 	}
 
-	contract := neo.New(act)
-	tx, err := mkTx(contract, addr, acc)
+	tx, err := mkTx(act, addr, acc)
 	if err != nil {
 		return cli.NewExitError(err, 1)
 	}
@@ -162,6 +302,16 @@ func getDecryptedAccount(wall *wallet.Wallet, addr util.Uint160, password *strin
 	if acc == nil {
 		return nil, fmt.Errorf("can't find account for the address: %s", address.Uint160ToString(addr))
 	}
+	if acc.IsWatchOnly() {
+		return nil, fmt.Errorf("account %s: %w", address.Uint160ToString(addr), wallet.ErrWatchOnly)
+	}
+
+	if acc.IsRemote() {
+		if err := ConnectRemote(acc); err != nil {
+			return nil, err
+		}
+		return acc, nil
+	}
 
 	// No private key available, nothing to decrypt, but it's still a useful account for many purposes.
 	if acc.EncryptedWIF == "" {
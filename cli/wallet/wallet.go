@@ -9,6 +9,7 @@ import (
 	"math/big"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/cli/flags"
@@ -19,6 +20,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/gas"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient/neo"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
@@ -41,6 +45,9 @@ const (
 	ConfirmPasswordPrompt = "Confirm password > "
 )
 
+// redactedKey replaces encrypted keys in the wallet dump output.
+const redactedKey = "<redacted>"
+
 var (
 	errNoPath                 = errors.New("wallet path is mandatory and should be passed using (--wallet, -w) flags or via wallet config using --wallet-config flag")
 	errConflictingWalletFlags = errors.New("--wallet flag conflicts with --wallet-config flag, please, provide one of them to specify wallet location")
@@ -65,6 +72,10 @@ var (
 		Name:  "decrypt, d",
 		Usage: "Decrypt encrypted keys.",
 	}
+	showKeysFlag = cli.BoolFlag{
+		Name:  "show-keys",
+		Usage: "Print encrypted keys (NEP-2) instead of masking them.",
+	}
 	inFlag = cli.StringFlag{
 		Name:  "in",
 		Usage: "file with JSON transaction",
@@ -88,10 +99,15 @@ func NewCommands() []cli.Command {
 		txctx.SysGasFlag,
 		txctx.OutFlag,
 		txctx.ForceFlag,
+		txctx.VerifyParamFlag,
 		flags.AddressFlag{
 			Name:  "address, a",
 			Usage: "Address to claim GAS for",
 		},
+		flags.AddressFlag{
+			Name:  "to",
+			Usage: "Address to send claimed GAS to (the claiming address by default)",
+		},
 	}
 	claimFlags = append(claimFlags, options.RPC...)
 	signFlags := []cli.Flag{
@@ -99,10 +115,27 @@ func NewCommands() []cli.Command {
 		walletConfigFlag,
 		txctx.OutFlag,
 		inFlag,
+		cli.StringFlag{
+			Name:  "in-base64",
+			Usage: "Base64-encoded context (JSON) with a transaction to sign (instead of --in file)",
+		},
+		cli.BoolFlag{
+			Name:  "out-base64",
+			Usage: "Print the resulting context as base64-encoded JSON",
+		},
+		txctx.VerifyParamFlag,
 		flags.AddressFlag{
 			Name:  "address, a",
 			Usage: "Address to use",
 		},
+		cli.BoolFlag{
+			Name:  "add-witness",
+			Usage: "Add witness of deployed contract account using verification parameters given as arguments instead of signing",
+		},
+		cli.UintFlag{
+			Name:  "valid-until-block",
+			Usage: "Set ValidUntilBlock of the (yet unsigned) transaction before signing it, requires an RPC endpoint",
+		},
 	}
 	signFlags = append(signFlags, options.RPC...)
 	return []cli.Command{{
@@ -112,9 +145,46 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "claim",
 				Usage:     "claim GAS",
-				UsageText: "neo-go wallet claim -w wallet [--wallet-config path] [-g gas] [-e sysgas] -a address -r endpoint [-s timeout] [--out file] [--force]",
-				Action:    claimGas,
-				Flags:     claimFlags,
+				UsageText: "neo-go wallet claim -w wallet [--wallet-config path] [-g gas] [-e sysgas] -a address [--to address] -r endpoint [-s timeout] [--out file] [--force]",
+				Description: `Claims GAS generated by NEO held by the given account. If --to
+   address is specified, the amount of GAS claimable at the next block is
+   transferred to it in the same transaction. The amount of GAS expected to be
+   claimed at the next block is printed before the transaction is created.
+`,
+				Action: claimGas,
+				Flags:  claimFlags,
+			},
+			{
+				Name:      "notary-deposit",
+				Usage:     "deposit GAS to the Notary contract",
+				UsageText: "neo-go wallet notary-deposit -w wallet [--wallet-config path] -a address --amount amount --till height -r endpoint [-s timeout] [-g gas] [-e sysgas] [--out file] [--force]",
+				Description: `Transfers the specified amount of GAS from the given account to the
+   Notary native contract to be used as a deposit for notary requests. The
+   deposit is locked until the given height (it can't be lower than the
+   current lock height of an existing deposit).
+`,
+				Action: notaryDeposit,
+				Flags: append([]cli.Flag{
+					walletPathFlag,
+					walletConfigFlag,
+					txctx.GasFlag,
+					txctx.SysGasFlag,
+					txctx.OutFlag,
+					txctx.ForceFlag,
+					txctx.VerifyParamFlag,
+					flags.AddressFlag{
+						Name:  "address, a",
+						Usage: "Address to deposit GAS from",
+					},
+					flags.Fixed8Flag{
+						Name:  "amount",
+						Usage: "Amount of GAS to deposit",
+					},
+					cli.UintFlag{
+						Name:  "till",
+						Usage: "Height the deposit is locked until",
+					},
+				}, options.RPC...),
 			},
 			{
 				Name:      "init",
@@ -133,14 +203,24 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "change-password",
 				Usage:     "change password for accounts",
-				UsageText: "neo-go wallet change-password -w wallet -a address",
-				Action:    changePassword,
+				UsageText: "neo-go wallet change-password -w wallet [-a address] [--uniform]",
+				Description: `Changes passwords of all (or the specified one) wallet accounts.
+   The old password is asked for first, accounts that can't be decrypted
+   with it are asked for their own old password and then for their own
+   new password, all the other accounts get the same new password. Use
+   --uniform to require all accounts to have the same old password.
+`,
+				Action: changePassword,
 				Flags: []cli.Flag{
 					walletPathFlag,
 					flags.AddressFlag{
 						Name:  "address, a",
 						Usage: "address to change password for",
 					},
+					cli.BoolFlag{
+						Name:  "uniform",
+						Usage: "require the same old password for all accounts and set the same new one",
+					},
 				},
 			},
 			{
@@ -170,9 +250,10 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "dump",
 				Usage:     "check and dump an existing NEO wallet",
-				UsageText: "neo-go wallet dump -w wallet [--wallet-config path] [-d]",
+				UsageText: "neo-go wallet dump -w wallet [--wallet-config path] [-d] [--show-keys]",
 				Description: `Prints the given wallet (via -w option or via wallet configuration file) in JSON
-   format to the standard output. If -d is given, private keys are unencrypted and
+   format to the standard output. Encrypted (NEP-2) keys are masked unless
+   --show-keys or -d is given. If -d is given, private keys are unencrypted and
    displayed in clear text on the console! Be very careful with this option and
    don't use it unless you know what you're doing.
 `,
@@ -181,13 +262,34 @@ func NewCommands() []cli.Command {
 					walletPathFlag,
 					walletConfigFlag,
 					decryptFlag,
+					showKeysFlag,
+				},
+			},
+			{
+				Name:      "list",
+				Usage:     "list wallet accounts",
+				UsageText: "neo-go wallet list -w wallet [--wallet-config path]",
+				Description: `Prints addresses, types and labels of all wallet accounts. Watch-only
+   and remote signer accounts are marked as such, the default account is
+   marked as well.
+`,
+				Action: listAccounts,
+				Flags: []cli.Flag{
+					walletPathFlag,
+					walletConfigFlag,
 				},
 			},
 			{
 				Name:      "dump-keys",
 				Usage:     "dump public keys for account",
-				UsageText: "neo-go wallet dump-keys -w wallet [--wallet-config path] [-a address]",
-				Action:    dumpKeys,
+				UsageText: "neo-go wallet dump-keys -w wallet [--wallet-config path] [-a address] [--out file [--include-multisig]]",
+				Description: `Prints public keys of the wallet accounts (or the one specified with -a).
+   If --out is given, keys are written to the file instead, one hex-encoded
+   key per line without duplicates. Only simple signature accounts are
+   used for it by default, --include-multisig adds keys of multisignature
+   accounts as well.
+`,
+				Action: dumpKeys,
 				Flags: []cli.Flag{
 					walletPathFlag,
 					walletConfigFlag,
@@ -195,6 +297,14 @@ func NewCommands() []cli.Command {
 						Name:  "address, a",
 						Usage: "address to print public keys for",
 					},
+					cli.StringFlag{
+						Name:  "out",
+						Usage: "file to write public keys to (one per line)",
+					},
+					cli.BoolFlag{
+						Name:  "include-multisig",
+						Usage: "write keys of multisignature accounts to the --out file",
+					},
 				},
 			},
 			{
@@ -215,10 +325,38 @@ func NewCommands() []cli.Command {
 				},
 			},
 			{
-				Name:      "import",
-				Usage:     "import WIF of a standard signature contract",
-				UsageText: "import -w wallet [--wallet-config path] --wif <wif> [--name <account_name>]",
-				Action:    importWallet,
+				Name:      "export-public",
+				Usage:     "export accounts without keys to be used as a watch-only wallet",
+				UsageText: "neo-go wallet export-public -w wallet [--wallet-config path] [-a address]",
+				Description: `Prints a wallet in JSON format to the standard output that contains
+   watch-only copies of all accounts of the given wallet (or only the one
+   specified with -a). Only addresses, labels and contracts (verification
+   scripts with public keys and parameters) are exported, keys are never
+   included. The result can be saved into a file and shared with other parties
+   (like co-signers of multisignature accounts) to be used as a watch-only
+   wallet for transaction creation. The source wallet is not changed.
+`,
+				Action: exportPublic,
+				Flags: []cli.Flag{
+					walletPathFlag,
+					walletConfigFlag,
+					flags.AddressFlag{
+						Name:  "address, a",
+						Usage: "address of the account to export",
+					},
+				},
+			},
+			{
+				Name:  "import",
+				Usage: "import WIF of a standard signature contract or a watch-only address",
+				UsageText: "import -w wallet [--wallet-config path] --wif <wif> [--name <account_name>]\n" +
+					"   import -w wallet [--wallet-config path] --watch-only (--address <address> | --public-key <key>) [--name <account_name>]",
+				Description: `Imports an account into the wallet. If --watch-only is given, an account
+   without a key is created for the given address or public key, it can be used
+   for balance queries, but can't sign anything. Its signature contract is
+   left empty if only the address is known.
+`,
+				Action: importWallet,
 				Flags: []cli.Flag{
 					walletPathFlag,
 					walletConfigFlag,
@@ -231,6 +369,18 @@ func NewCommands() []cli.Command {
 						Name:  "contract",
 						Usage: "Verification script for custom contracts",
 					},
+					cli.BoolFlag{
+						Name:  "watch-only",
+						Usage: "Import watch-only account for the given address",
+					},
+					flags.AddressFlag{
+						Name:  "address, a",
+						Usage: "Address to import (watch-only accounts only)",
+					},
+					cli.StringFlag{
+						Name:  "public-key",
+						Usage: "Hex-encoded public key to import (watch-only accounts only)",
+					},
 				},
 			},
 			{
@@ -272,6 +422,33 @@ func NewCommands() []cli.Command {
 					},
 				}, options.RPC...),
 			},
+			{
+				Name:      "import-remote",
+				Usage:     "import an account which key is stored in a remote signer",
+				UsageText: "import-remote -w wallet [--wallet-config path] --signer <url> --key <id> [--name <account_name>]",
+				Description: `Adds an account referencing the key with the given ID stored in the remote
+   signer (see pkg/wallet/remote for the protocol). The signer is queried for the
+   public key, no private key is stored in the wallet. Every signature made with
+   this account by CLI commands needs to be confirmed interactively.
+`,
+				Action: importRemote,
+				Flags: []cli.Flag{
+					walletPathFlag,
+					walletConfigFlag,
+					cli.StringFlag{
+						Name:  "signer",
+						Usage: "Remote signer URL",
+					},
+					cli.StringFlag{
+						Name:  "key",
+						Usage: "Key ID in the remote signer",
+					},
+					cli.StringFlag{
+						Name:  "name, n",
+						Usage: "Optional account name",
+					},
+				},
+			},
 			{
 				Name:      "remove",
 				Usage:     "remove an account from the wallet",
@@ -290,13 +467,32 @@ func NewCommands() []cli.Command {
 			{
 				Name:      "sign",
 				Usage:     "cosign transaction with multisig/contract/additional account",
-				UsageText: "sign -w wallet [--wallet-config path] --address <address> --in <file.in> [--out <file.out>] [-r <endpoint>]",
+				UsageText: "sign -w wallet [--wallet-config path] --address <address> {--in <file.in> | --in-base64 <context>} [--out <file.out>] [--out-base64] [-r <endpoint>] [--valid-until-block N] [--add-witness [params...]]",
 				Description: `Signs the given (in file.in) context (which must be a transaction
    signing context) for the given address using the given wallet. This command can
    output the resulting JSON (with additional signature added) right to the console
    (if no file.out and no RPC endpoint specified) or into a file (which can be the
    same as input one). If an RPC endpoint is given it'll also try to construct a
    complete transaction and send it via RPC (printing its hash if everything is OK).
+   The context must belong to the network of the RPC node then, see 'util
+   convert-context' to move it to another one.
+
+   The context can also be given as a base64-encoded JSON string with
+   --in-base64 (instead of --in) and the resulting one can be printed in the
+   same format with --out-base64, which is convenient for exchanging contexts
+   via text channels.
+
+   If --add-witness flag is given, the address must belong to a deployed
+   contract account, its witness is filled with verification parameters given
+   as command arguments (see 'contract testinvokefunction' documentation for
+   parameter format) instead of a signature. The number of parameters must
+   match the one of contract's verify method.
+
+   --valid-until-block sets ValidUntilBlock of the transaction to the given
+   value (checked against the current chain height via RPC) before signing
+   it, so it can't linger in the mempool longer than intended. This changes
+   the transaction hash, so it's only possible for contexts that have no
+   signatures yet. Without it the value from the context is kept.
 `,
 				Action: signStoredTransaction,
 				Flags:  signFlags,
@@ -338,8 +534,46 @@ func NewCommands() []cli.Command {
 }
 
 func claimGas(ctx *cli.Context) error {
-	return handleNeoAction(ctx, func(contract *neo.Contract, shash util.Uint160, _ *wallet.Account) (*transaction.Transaction, error) {
-		return contract.TransferUnsigned(shash, shash, big.NewInt(0), nil)
+	toFlag := ctx.Generic("to").(*flags.Address)
+	return handleAccountAction(ctx, func(act *actor.Actor, shash util.Uint160, _ *wallet.Account) (*transaction.Transaction, error) {
+		contract := neo.New(act)
+		bal, err := contract.BalanceOf(shash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get NEO balance: %w", err)
+		}
+		count, err := act.GetBlockCount()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block count: %w", err)
+		}
+		// The transaction can't be accepted earlier than in the next
+		// block which has the index equal to the current block count.
+		amount := big.NewInt(0)
+		if bal.Sign() != 0 {
+			amount, err = contract.UnclaimedGas(shash, count)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get unclaimed GAS: %w", err)
+			}
+		}
+		fmt.Fprintf(ctx.App.ErrWriter, "Expected GAS to claim: %s\n", fixedn.ToString(amount, 8))
+		if !toFlag.IsSet || toFlag.Uint160().Equals(shash) {
+			return contract.TransferUnsigned(shash, shash, big.NewInt(0), nil)
+		}
+		// GAS is always distributed to the NEO holder, so it's claimed
+		// and then transferred with a separate call.
+		if bal.Sign() == 0 {
+			return nil, errors.New("no NEO to claim GAS for")
+		}
+		if amount.Sign() == 0 {
+			return nil, errors.New("no GAS to claim")
+		}
+		scr := smartcontract.NewBuilder()
+		scr.InvokeWithAssert(neo.Hash, "transfer", shash, shash, 0, nil)
+		scr.InvokeWithAssert(gas.Hash, "transfer", shash, toFlag.Uint160(), amount, nil)
+		script, err := scr.Script()
+		if err != nil {
+			return nil, err
+		}
+		return act.MakeUnsignedRun(script, nil)
 	})
 }
 
@@ -369,25 +603,53 @@ func changePassword(ctx *cli.Context) error {
 		return cli.NewExitError(fmt.Errorf("Error reading old password: %w", err), 1)
 	}
 
+	var (
+		uniform = ctx.Bool("uniform")
+		accs    []*wallet.Account
+		// individual contains accounts encrypted with a password different
+		// from the first one entered, they get individual new passwords.
+		individual = make(map[*wallet.Account]bool)
+	)
 	for i := range wall.Accounts {
-		if addrFlag.IsSet && wall.Accounts[i].Address != addrFlag.String() {
+		if (addrFlag.IsSet && wall.Accounts[i].Address != addrFlag.String()) || wall.Accounts[i].IsRemote() || wall.Accounts[i].IsWatchOnly() {
+			continue
+		}
+		acc := wall.Accounts[i]
+		accs = append(accs, acc)
+		err := acc.Decrypt(oldPass, wall.Scrypt)
+		if err == nil {
 			continue
 		}
-		err := wall.Accounts[i].Decrypt(oldPass, wall.Scrypt)
+		if uniform {
+			return cli.NewExitError(fmt.Errorf("unable to decrypt account %s: %w", acc.Address, err), 1)
+		}
+		accPass, err := input.ReadPassword(fmt.Sprintf("Enter old password for %s > ", acc.Address))
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("Error reading old password: %w", err), 1)
+		}
+		err = acc.Decrypt(accPass, wall.Scrypt)
 		if err != nil {
-			return cli.NewExitError(fmt.Errorf("unable to decrypt account %s: %w", wall.Accounts[i].Address, err), 1)
+			return cli.NewExitError(fmt.Errorf("unable to decrypt account %s: %w", acc.Address, err), 1)
 		}
+		individual[acc] = true
 	}
 
-	pass, err := readNewPassword()
-	if err != nil {
-		return cli.NewExitError(fmt.Errorf("Error reading new password: %w", err), 1)
+	var pass string
+	if len(individual) < len(accs) {
+		pass, err = readNewPassword()
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("Error reading new password: %w", err), 1)
+		}
 	}
-	for i := range wall.Accounts {
-		if addrFlag.IsSet && wall.Accounts[i].Address != addrFlag.String() {
-			continue
+	for _, acc := range accs {
+		accPass := pass
+		if individual[acc] {
+			accPass, err = readNewPasswordWithPrompt(fmt.Sprintf("Enter new password for %s > ", acc.Address))
+			if err != nil {
+				return cli.NewExitError(fmt.Errorf("Error reading new password: %w", err), 1)
+			}
 		}
-		err := wall.Accounts[i].Encrypt(pass, wall.Scrypt)
+		err := acc.Encrypt(accPass, wall.Scrypt)
 		if err != nil {
 			return cli.NewExitError(err, 1)
 		}
@@ -436,6 +698,7 @@ func convertWallet(ctx *cli.Context) error {
 	if err := newWallet.Save(); err != nil {
 		return cli.NewExitError(err, 1)
 	}
+	WarnPermissions(ctx, out)
 	return nil
 }
 
@@ -626,6 +889,9 @@ func importWallet(ctx *cli.Context) error {
 	}
 	defer wall.Close()
 
+	if ctx.Bool("watch-only") {
+		return importWatchOnly(ctx, wall)
+	}
 	acc, err := newAccountFromWIF(ctx.App.Writer, ctx.String("wif"), wall.Scrypt)
 	if err != nil {
 		return cli.NewExitError(err, 1)
@@ -649,6 +915,40 @@ func importWallet(ctx *cli.Context) error {
 	return nil
 }
 
+func importWatchOnly(ctx *cli.Context, wall *wallet.Wallet) error {
+	if ctx.String("wif") != "" || ctx.String("contract") != "" {
+		return cli.NewExitError("--wif and --contract can't be used with --watch-only", 1)
+	}
+	var (
+		acc    *wallet.Account
+		addr   = ctx.Generic("address").(*flags.Address)
+		pubStr = ctx.String("public-key")
+	)
+	switch {
+	case addr.IsSet && pubStr != "":
+		return cli.NewExitError("--address and --public-key can't be used together", 1)
+	case pubStr != "":
+		pub, err := keys.NewPublicKeyFromString(pubStr)
+		if err != nil {
+			return cli.NewExitError(fmt.Errorf("invalid public key: %w", err), 1)
+		}
+		acc = wallet.NewWatchOnlyAccountFromPublicKey(pub)
+	case addr.IsSet:
+		var err error
+		acc, err = wallet.NewWatchOnlyAccount(address.Uint160ToString(addr.Uint160()))
+		if err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	default:
+		return cli.NewExitError("address or public key is required for watch-only account", 1)
+	}
+	acc.Label = ctx.String("name")
+	if err := addAccountAndSave(wall, acc); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	return nil
+}
+
 func removeAccount(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -714,17 +1014,68 @@ func dumpWallet(ctx *cli.Context) error {
 			pass = &password
 		}
 		for i := range wall.Accounts {
+			if wall.Accounts[i].IsWatchOnly() {
+				continue
+			}
 			// Just testing the decryption here.
 			err := wall.Accounts[i].Decrypt(*pass, wall.Scrypt)
 			if err != nil {
 				return cli.NewExitError(err, 1)
 			}
 		}
+	} else if !ctx.Bool("show-keys") {
+		wall = redactedWallet(wall)
 	}
 	fmtPrintWallet(ctx.App.Writer, wall)
 	return nil
 }
 
+// redactedWallet returns a copy of the wallet with encrypted keys masked.
+func redactedWallet(wall *wallet.Wallet) *wallet.Wallet {
+	res := *wall
+	res.Accounts = make([]*wallet.Account, len(wall.Accounts))
+	for i, acc := range wall.Accounts {
+		a := *acc
+		if a.EncryptedWIF != "" {
+			a.EncryptedWIF = redactedKey
+		}
+		res.Accounts[i] = &a
+	}
+	return &res
+}
+
+func exportPublic(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	wall, _, err := readWallet(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+	accounts := wall.Accounts
+
+	addrFlag := ctx.Generic("address").(*flags.Address)
+	if addrFlag.IsSet {
+		acc := wall.GetAccount(addrFlag.Uint160())
+		if acc == nil {
+			return cli.NewExitError("account is missing", 1)
+		}
+		accounts = []*wallet.Account{acc}
+	}
+	res := &wallet.Wallet{
+		Version:  wall.Version,
+		Accounts: make([]*wallet.Account, 0, len(accounts)),
+		Scrypt:   wall.Scrypt,
+		Extra:    wall.Extra,
+	}
+	for _, acc := range accounts {
+		res.Accounts = append(res.Accounts, acc.WatchOnlyCopy())
+	}
+	fmtPrintWallet(ctx.App.Writer, res)
+	return nil
+}
+
 func dumpKeys(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -745,9 +1096,32 @@ func dumpKeys(ctx *cli.Context) error {
 		accounts = []*wallet.Account{acc}
 	}
 
+	if out := ctx.String("out"); out != "" {
+		return dumpKeysToFile(accounts, out, ctx.Bool("include-multisig"))
+	}
+	if ctx.Bool("include-multisig") {
+		return cli.NewExitError("--include-multisig can only be used with --out", 1)
+	}
+
 	hasPrinted := false
 	for _, acc := range accounts {
+		if acc.Contract == nil {
+			continue
+		}
 		pub, ok := vm.ParseSignatureContract(acc.Contract.Script)
+		if acc.IsWatchOnly() {
+			if hasPrinted {
+				fmt.Fprintln(ctx.App.Writer)
+			}
+			if !ok {
+				fmt.Fprintf(ctx.App.Writer, "%s (watch-only, no keys)\n", acc.Address)
+			} else {
+				fmt.Fprintf(ctx.App.Writer, "%s (watch-only simple signature contract):\n", acc.Address)
+				fmt.Fprintln(ctx.App.Writer, hex.EncodeToString(pub))
+			}
+			hasPrinted = true
+			continue
+		}
 		if ok {
 			if hasPrinted {
 				fmt.Fprintln(ctx.App.Writer)
@@ -776,6 +1150,88 @@ func dumpKeys(ctx *cli.Context) error {
 	return nil
 }
 
+// dumpKeysToFile writes public keys of the given accounts to the file, one
+// hex-encoded key per line. Keys of multisignature accounts are only written
+// if includeMultisig is set, deployed contract accounts are skipped.
+func dumpKeysToFile(accounts []*wallet.Account, path string, includeMultisig bool) error {
+	var (
+		buf  strings.Builder
+		seen = make(map[string]bool)
+	)
+	add := func(pub []byte) {
+		s := hex.EncodeToString(pub)
+		if !seen[s] {
+			seen[s] = true
+			buf.WriteString(s)
+			buf.WriteByte('\n')
+		}
+	}
+	for _, acc := range accounts {
+		if acc.Contract == nil {
+			continue
+		}
+		if pub, ok := vm.ParseSignatureContract(acc.Contract.Script); ok {
+			add(pub)
+			continue
+		}
+		if !includeMultisig {
+			continue
+		}
+		if _, bs, ok := vm.ParseMultiSigContract(acc.Contract.Script); ok {
+			for i := range bs {
+				add(bs[i])
+			}
+		}
+	}
+	if err := os.WriteFile(path, []byte(buf.String()), 0644); err != nil {
+		return cli.NewExitError(fmt.Errorf("can't write public keys: %w", err), 1)
+	}
+	return nil
+}
+
+func listAccounts(ctx *cli.Context) error {
+	if err := cmdargs.EnsureNone(ctx); err != nil {
+		return err
+	}
+	wall, _, err := readWallet(ctx)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	defer wall.Close()
+
+	tw := tabwriter.NewWriter(ctx.App.Writer, 0, 2, 2, ' ', 0)
+	_, _ = tw.Write([]byte("Address\tType\tLabel\n"))
+	for _, acc := range wall.Accounts {
+		typ := accountType(acc)
+		if acc.Default {
+			typ += " (default)"
+		}
+		_, _ = tw.Write([]byte(acc.Address + "\t" + typ + "\t" + acc.Label + "\n"))
+	}
+	_ = tw.Flush()
+	return nil
+}
+
+// accountType returns a short description of the account kind.
+func accountType(acc *wallet.Account) string {
+	switch {
+	case acc.IsWatchOnly():
+		return "watch-only"
+	case acc.IsRemote():
+		return "remote"
+	case acc.Contract == nil:
+		return "no contract"
+	case acc.Contract.Deployed:
+		return "deployed"
+	case vm.IsSignatureContract(acc.Contract.Script):
+		return "standard"
+	case vm.IsMultiSigContract(acc.Contract.Script):
+		return "multisig"
+	default:
+		return "contract"
+	}
+}
+
 func stripKeys(ctx *cli.Context) error {
 	if err := cmdargs.EnsureNone(ctx); err != nil {
 		return err
@@ -829,6 +1285,7 @@ func createWallet(ctx *cli.Context) error {
 	if err := wall.Save(); err != nil {
 		return cli.NewExitError(err, 1)
 	}
+	WarnPermissions(ctx, path)
 
 	if ctx.Bool("account") {
 		if err := createAccount(wall, pass); err != nil {
@@ -855,7 +1312,11 @@ func readAccountInfo() (string, string, error) {
 }
 
 func readNewPassword() (string, error) {
-	phrase, err := input.ReadPassword(EnterNewPasswordPrompt)
+	return readNewPasswordWithPrompt(EnterNewPasswordPrompt)
+}
+
+func readNewPasswordWithPrompt(prompt string) (string, error) {
+	phrase, err := input.ReadPassword(prompt)
 	if err != nil {
 		return "", fmt.Errorf("Error reading password: %w", err)
 	}
@@ -898,6 +1359,7 @@ func openWallet(ctx *cli.Context, canUseWalletConfig bool) (*wallet.Wallet, *str
 	if err != nil {
 		return nil, nil, err
 	}
+	WarnPermissions(ctx, path)
 	return w, pass, nil
 }
 
@@ -917,6 +1379,7 @@ func readWallet(ctx *cli.Context) (*wallet.Wallet, *string, error) {
 	if err != nil {
 		return nil, nil, err
 	}
+	WarnPermissions(ctx, path)
 	return w, pass, nil
 }
 
@@ -945,6 +1408,15 @@ func getWalletPathAndPass(ctx *cli.Context, canUseWalletConfig bool) (string, *s
 	return path, pass, nil
 }
 
+// WarnPermissions prints a warning to the application error writer if the
+// wallet file at the given path (or its directory) has loose permissions, see
+// wallet.CheckPermissions.
+func WarnPermissions(ctx *cli.Context, path string) {
+	if err := wallet.CheckPermissions(path); err != nil {
+		fmt.Fprintf(ctx.App.ErrWriter, "Warning: %s\n", err)
+	}
+}
+
 func ReadWalletConfig(configPath string) (*config.Wallet, error) {
 	file, err := os.Open(configPath)
 	if err != nil {
@@ -6,15 +6,19 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/chzyer/readline"
+	"github.com/nspcc-dev/neo-go/cli/paramcontext"
+	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/internal/testcli"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
@@ -140,6 +144,58 @@ func TestWalletChangePassword(t *testing.T) {
 		e.In.WriteString("pass\r")
 		e.Run(t, "neo-go", "wallet", "change-password", "--wallet", walletPath)
 	})
+	t.Run("different passwords", func(t *testing.T) {
+		e.In.WriteString("pass\r")
+		e.In.WriteString("other\r")
+		e.In.WriteString("other\r")
+		e.Run(t, "neo-go", "wallet", "change-password", "--wallet", walletPath, "--address", addr2)
+
+		t.Run("uniform", func(t *testing.T) {
+			e.In.WriteString("pass\r")
+			e.In.WriteString("aaa\r")
+			e.In.WriteString("aaa\r")
+			e.RunWithError(t, "neo-go", "wallet", "change-password", "--wallet", walletPath, "--uniform")
+		})
+		t.Run("bad individual old password", func(t *testing.T) {
+			e.In.WriteString("pass\r")
+			e.In.WriteString("ssap\r")
+			e.RunWithError(t, "neo-go", "wallet", "change-password", "--wallet", walletPath)
+		})
+		t.Run("bad individual new password", func(t *testing.T) {
+			e.In.WriteString("pass\r")
+			e.In.WriteString("other\r")
+			e.In.WriteString("new1\r")
+			e.In.WriteString("new1\r")
+			e.In.WriteString("new2\r")
+			e.In.WriteString("new3\r")
+			e.RunWithError(t, "neo-go", "wallet", "change-password", "--wallet", walletPath)
+		})
+		t.Run("good", func(t *testing.T) {
+			e.In.WriteString("pass\r")
+			e.In.WriteString("other\r")
+			e.In.WriteString("new1\r")
+			e.In.WriteString("new1\r")
+			e.In.WriteString("new2\r")
+			e.In.WriteString("new2\r")
+			e.Run(t, "neo-go", "wallet", "change-password", "--wallet", walletPath)
+
+			w, err := wallet.NewWalletFromFile(walletPath)
+			require.NoError(t, err)
+			require.NoError(t, w.Accounts[0].Decrypt("new1", w.Scrypt))
+			require.NoError(t, w.Accounts[1].Decrypt("new2", w.Scrypt))
+		})
+		t.Run("only individual passwords", func(t *testing.T) {
+			e.In.WriteString("new2\r") // Only decrypts the second account.
+			e.In.WriteString("new1\r")
+			e.In.WriteString("new3\r")
+			e.In.WriteString("new3\r")
+			e.Run(t, "neo-go", "wallet", "change-password", "--wallet", walletPath, "--address", addr1)
+
+			w, err := wallet.NewWalletFromFile(walletPath)
+			require.NoError(t, err)
+			require.NoError(t, w.Accounts[0].Decrypt("new3", w.Scrypt))
+		})
+	})
 }
 
 func TestWalletInit(t *testing.T) {
@@ -355,6 +411,75 @@ func TestWalletInit(t *testing.T) {
 			require.NotNil(t, actual)
 			require.NoError(t, actual.Decrypt("somepass", w.Scrypt))
 		})
+		t.Run("WatchOnly", func(t *testing.T) {
+			woPath := filepath.Join(t.TempDir(), "watch-only.json")
+			w, err := wallet.NewWallet(woPath)
+			require.NoError(t, err)
+			require.NoError(t, w.Save())
+			w.Close()
+
+			priv, err := keys.NewPrivateKey()
+			require.NoError(t, err)
+			cmd := []string{"neo-go", "wallet", "import", "--wallet", woPath, "--watch-only"}
+			t.Run("missing address", func(t *testing.T) {
+				e.RunWithError(t, cmd...)
+			})
+			t.Run("with WIF", func(t *testing.T) {
+				e.RunWithError(t, append(cmd, "--address", priv.Address(), "--wif", priv.WIF())...)
+			})
+			e.Run(t, append(cmd, "--address", priv.Address(), "--name", "watched")...)
+
+			w, err = wallet.NewWalletFromFile(woPath)
+			require.NoError(t, err)
+			actual := w.GetAccount(priv.GetScriptHash())
+			require.NotNil(t, actual)
+			require.Equal(t, "watched", actual.Label)
+			require.True(t, actual.IsWatchOnly())
+			require.Empty(t, actual.EncryptedWIF)
+			require.NotNil(t, actual.Contract)
+			require.Empty(t, actual.Contract.Script)
+
+			t.Run("double-import", func(t *testing.T) {
+				e.RunWithError(t, append(cmd, "--address", priv.Address())...)
+			})
+			t.Run("address and public key", func(t *testing.T) {
+				e.RunWithError(t, append(cmd, "--address", priv.Address(), "--public-key", hex.EncodeToString(priv.PublicKey().Bytes()))...)
+			})
+			t.Run("bad public key", func(t *testing.T) {
+				e.RunWithError(t, append(cmd, "--public-key", "01020304")...)
+			})
+			priv2, err := keys.NewPrivateKey()
+			require.NoError(t, err)
+			e.Run(t, append(cmd, "--public-key", hex.EncodeToString(priv2.PublicKey().Bytes()))...)
+			w, err = wallet.NewWalletFromFile(woPath)
+			require.NoError(t, err)
+			actual = w.GetAccount(priv2.GetScriptHash())
+			require.NotNil(t, actual)
+			require.True(t, actual.IsWatchOnly())
+			require.Equal(t, priv2.PublicKey().GetVerificationScript(), actual.Contract.Script)
+
+			t.Run("dump-keys", func(t *testing.T) {
+				e.Run(t, "neo-go", "wallet", "dump-keys", "--wallet", woPath)
+				e.CheckNextLine(t, priv.Address()+" \\(watch-only, no keys\\)")
+				e.CheckNextLine(t, "^$")
+				e.CheckNextLine(t, priv2.Address()+" \\(watch-only simple signature contract\\):")
+				e.CheckNextLine(t, hex.EncodeToString(priv2.PublicKey().Bytes()))
+				e.CheckEOF(t)
+			})
+			t.Run("list", func(t *testing.T) {
+				e.Run(t, "neo-go", "wallet", "list", "--wallet", woPath)
+				e.CheckNextLine(t, "^Address +Type +Label$")
+				e.CheckNextLine(t, "^"+priv.Address()+" +watch-only +watched$")
+				e.CheckNextLine(t, "^"+priv2.Address()+" +watch-only +$")
+				e.CheckEOF(t)
+			})
+			t.Run("sign", func(t *testing.T) {
+				e.RunWithError(t, "neo-go", "wallet", "nep17", "transfer",
+					"--rpc-endpoint", "http://127.0.0.1:0",
+					"--wallet", woPath, "--from", priv.Address(),
+					"--to", priv.Address(), "--token", "GAS", "--amount", "1")
+			})
+		})
 		t.Run("Multisig", func(t *testing.T) {
 			t.Run("missing wallet", func(t *testing.T) {
 				e.RunWithError(t, "neo-go", "wallet", "import-multisig")
@@ -539,6 +664,75 @@ func TestWalletClaimGas(t *testing.T) {
 	}
 }
 
+func TestWalletClaimGasTo(t *testing.T) {
+	e := testcli.NewExecutor(t, true)
+
+	args := []string{
+		"neo-go", "wallet", "claim",
+		"--rpc-endpoint", "http://" + e.RPC.Addr,
+		"--wallet", testcli.TestWalletPath,
+		"--address", testcli.TestWalletAccount,
+		"--force",
+	}
+	t.Run("no NEO", func(t *testing.T) {
+		e.In.WriteString("testpass\r")
+		e.RunWithError(t, append(args, "--to", testcli.ValidatorAddr)...)
+	})
+
+	e.In.WriteString("one\r")
+	e.Run(t, "neo-go", "wallet", "nep17", "multitransfer",
+		"--rpc-endpoint", "http://"+e.RPC.Addr,
+		"--wallet", testcli.ValidatorWallet,
+		"--from", testcli.ValidatorAddr,
+		"--force",
+		"NEO:"+testcli.TestWalletAccount+":1000",
+		"GAS:"+testcli.TestWalletAccount+":1000") // for tx send
+	e.CheckTxPersisted(t)
+
+	h, err := address.StringToUint160(testcli.TestWalletAccount)
+	require.NoError(t, err)
+	to := random.Uint160()
+
+	cl, err := e.Chain.CalculateClaimable(h, e.Chain.BlockHeight()+1)
+	require.NoError(t, err)
+	require.True(t, cl.Sign() > 0)
+
+	e.In.WriteString("testpass\r")
+	e.Run(t, append(args, "--to", address.Uint160ToString(to))...)
+	require.Equal(t, "Expected GAS to claim: "+fixedn.ToString(cl, 8)+"\n", e.Err.String())
+	e.CheckTxPersisted(t)
+	require.Equal(t, cl, e.Chain.GetUtilityTokenBalance(to))
+}
+
+func TestWalletNotaryDeposit(t *testing.T) {
+	e := testcli.NewExecutor(t, true)
+
+	args := []string{"neo-go", "wallet", "notary-deposit",
+		"--rpc-endpoint", "http://" + e.RPC.Addr,
+		"--wallet", testcli.ValidatorWallet,
+		"--address", testcli.ValidatorAddr,
+		"--force"}
+	t.Run("missing amount", func(t *testing.T) {
+		e.RunWithError(t, append(args, "--till", "1000")...)
+	})
+	t.Run("missing till", func(t *testing.T) {
+		e.RunWithError(t, append(args, "--amount", "10")...)
+	})
+	t.Run("till is too low", func(t *testing.T) {
+		e.In.WriteString("one\r")
+		e.RunWithError(t, append(args, "--amount", "10", "--till", "1")...)
+	})
+
+	till := e.Chain.BlockHeight() + 1000
+	e.In.WriteString("one\r")
+	e.Run(t, append(args, "--amount", "10", "--till", strconv.Itoa(int(till)))...)
+	e.CheckTxPersisted(t)
+
+	h := testcli.ValidatorHash
+	require.Equal(t, big.NewInt(10_0000_0000), e.Chain.GetNotaryBalance(h))
+	require.Equal(t, till, e.Chain.GetNotaryDepositExpiration(h))
+}
+
 func TestWalletImportDeployed(t *testing.T) {
 	tmpDir := t.TempDir()
 	e := testcli.NewExecutor(t, true)
@@ -632,6 +826,108 @@ func TestWalletImportDeployed(t *testing.T) {
 	})
 }
 
+func TestWalletImportDeployedVerifyParams(t *testing.T) {
+	tmpDir := t.TempDir()
+	e := testcli.NewExecutor(t, true)
+	h := testcli.DeployContract(t, e, "../smartcontract/testdata/verifyparam/verify.go", "../smartcontract/testdata/verifyparam/verify.yml", testcli.ValidatorWallet, testcli.ValidatorAddr, testcli.ValidatorPass)
+	walletPath := filepath.Join(tmpDir, "wallet.json")
+	txPath := filepath.Join(tmpDir, "tx.json")
+	contractAddr := address.Uint160ToString(h)
+
+	e.Run(t, "neo-go", "wallet", "init", "--wallet", walletPath)
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	e.In.WriteString("acc\rpass\rpass\r")
+	e.Run(t, "neo-go", "wallet", "import-deployed",
+		"--rpc-endpoint", "http://"+e.RPC.Addr,
+		"--wallet", walletPath, "--wif", priv.WIF(),
+		"--contract", h.StringLE())
+
+	w, err := wallet.NewWalletFromFile(walletPath)
+	require.NoError(t, err)
+	require.Equal(t, []wallet.ContractParam{{Name: "password", Type: smartcontract.StringType}}, w.Accounts[0].Contract.Parameters)
+	w.Close()
+
+	e.In.WriteString("one\r")
+	e.Run(t, "neo-go", "wallet", "nep17", "transfer",
+		"--rpc-endpoint", "http://"+e.RPC.Addr,
+		"--wallet", testcli.ValidatorWallet, "--from", testcli.ValidatorAddr,
+		"--to", contractAddr, "--token", "NEO", "--amount", "10",
+		"--force")
+	e.CheckTxPersisted(t)
+
+	privTo, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	transferArgs := []string{"neo-go", "wallet", "nep17", "transfer",
+		"--rpc-endpoint", "http://" + e.RPC.Addr,
+		"--wallet", walletPath, "--from", contractAddr,
+		"--to", privTo.Address(), "--token", "NEO", "--amount", "1",
+		"--force"}
+	checkBalance := func(t *testing.T, expected int64) {
+		b, _ := e.Chain.GetGoverningTokenBalance(privTo.GetScriptHash())
+		require.Equal(t, big.NewInt(expected), b)
+	}
+
+	t.Run("bad flag", func(t *testing.T) {
+		e.In.WriteString("pass\r")
+		e.RunWithError(t, append(transferArgs, "--verify-param", "password")...)
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		e.In.WriteString("pass\r")
+		e.RunWithError(t, append(transferArgs, "--verify-param", "password=public")...)
+	})
+	t.Run("flag", func(t *testing.T) {
+		e.In.WriteString("pass\r")
+		e.Run(t, append(transferArgs, "--verify-param", "password=secret")...)
+		e.CheckTxPersisted(t)
+		checkBalance(t, 1)
+	})
+	t.Run("interactive", func(t *testing.T) {
+		e.In.WriteString("pass\rsecret\r")
+		e.Run(t, transferArgs...)
+		e.CheckTxPersisted(t)
+		checkBalance(t, 2)
+	})
+	t.Run("out", func(t *testing.T) {
+		e.In.WriteString("pass\r")
+		e.Run(t, append(transferArgs, "--out", txPath)...)
+
+		pc, err := paramcontext.Read(txPath)
+		require.NoError(t, err)
+		item := pc.Items[h]
+		require.NotNil(t, item)
+		require.Equal(t, []smartcontract.Parameter{{Type: smartcontract.StringType}}, item.Parameters)
+
+		e.In.WriteString("pass\rsecret\r")
+		e.Run(t, "neo-go", "wallet", "sign",
+			"--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--wallet", walletPath, "--address", contractAddr,
+			"--in", txPath, "--out", txPath)
+		e.CheckTxPersisted(t)
+		checkBalance(t, 3)
+	})
+	t.Run("add witness", func(t *testing.T) {
+		e.In.WriteString("pass\r")
+		e.Run(t, append(transferArgs, "--out", txPath)...)
+
+		signArgs := []string{"neo-go", "wallet", "sign",
+			"--rpc-endpoint", "http://" + e.RPC.Addr,
+			"--wallet", walletPath, "--address", contractAddr,
+			"--in", txPath, "--out", txPath, "--add-witness"}
+		e.RunWithError(t, signArgs...)
+		e.RunWithError(t, append(signArgs, "string:secret", "int:1")...)
+		e.RunWithError(t, append(signArgs, "int:1")...)
+		e.RunWithError(t, "neo-go", "wallet", "sign",
+			"--wallet", testcli.ValidatorWallet, "--address", testcli.ValidatorAddr,
+			"--in", txPath, "--add-witness", "string:secret")
+
+		// No password is needed.
+		e.Run(t, append(signArgs, "string:secret")...)
+		e.CheckTxPersisted(t)
+		checkBalance(t, 4)
+	})
+}
+
 func TestStripKeys(t *testing.T) {
 	e := testcli.NewExecutor(t, true)
 	tmpDir := t.TempDir()
@@ -673,7 +969,7 @@ func TestOfflineSigning(t *testing.T) {
 	require.NoError(t, err)
 	jOut, err := w.JSON()
 	require.NoError(t, err)
-	require.NoError(t, os.WriteFile(walletPath, jOut, 0644))
+	require.NoError(t, os.WriteFile(walletPath, jOut, wallet.FileMode))
 
 	// And remove keys from it.
 	e.Run(t, "neo-go", "wallet", "strip-keys", "--wallet", walletPath, "--force")
@@ -752,6 +1048,60 @@ func TestOfflineSigning(t *testing.T) {
 			"--rpc-endpoint", "http://"+e.RPC.Addr,
 			txPath)
 	})
+	e.CheckTxPersisted(t)
+	t.Run("valid until block", func(t *testing.T) {
+		simpleAddr := w.Accounts[0].Address
+		e.Run(t, "neo-go", "wallet", "nep17", "transfer",
+			"--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--wallet", walletPath,
+			"--from", simpleAddr,
+			"--to", testcli.ValidatorAddr,
+			"--token", "NEO",
+			"--amount", "1",
+			"--force",
+			"--out", txPath)
+		signArgs := []string{"neo-go", "wallet", "sign",
+			"--wallet", testcli.ValidatorWallet, "--address", simpleAddr,
+			"--in", txPath}
+		height := e.Chain.BlockHeight()
+		vub := height + 5
+		// RPC endpoint is required.
+		e.In.WriteString("one\r")
+		e.RunWithError(t, append(signArgs, "--valid-until-block", strconv.FormatUint(uint64(vub), 10))...)
+		// Too low.
+		e.In.WriteString("one\r")
+		e.RunWithError(t, append(signArgs, "--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--valid-until-block", strconv.FormatUint(uint64(height), 10))...)
+		// Too high.
+		e.In.WriteString("one\r")
+		e.RunWithError(t, append(signArgs, "--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--valid-until-block", strconv.FormatUint(uint64(height+e.Chain.GetConfig().MaxValidUntilBlockIncrement+1), 10))...)
+
+		e.In.WriteString("one\r")
+		e.Run(t, append(signArgs, "--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--valid-until-block", strconv.FormatUint(uint64(vub), 10))...)
+		tx, _ := e.CheckTxPersisted(t)
+		require.Equal(t, vub, tx.ValidUntilBlock)
+	})
+	t.Run("valid until block, signed", func(t *testing.T) {
+		e.In.WriteString("one\r")
+		e.Run(t, "neo-go", "wallet", "nep17", "transfer",
+			"--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--wallet", testcli.ValidatorWallet,
+			"--from", testcli.ValidatorAddr,
+			"--to", w.Accounts[0].Address,
+			"--token", "NEO",
+			"--amount", "1",
+			"--force",
+			"--out", txPath)
+		// Signatures are bound to the hash which depends on ValidUntilBlock.
+		e.In.WriteString("one\r")
+		e.RunWithError(t, "neo-go", "wallet", "sign",
+			"--rpc-endpoint", "http://"+e.RPC.Addr,
+			"--wallet", testcli.ValidatorWallet, "--address", testcli.ValidatorAddr,
+			"--in", txPath,
+			"--valid-until-block", strconv.FormatUint(uint64(e.Chain.BlockHeight()+5), 10))
+	})
 }
 
 func TestWalletDump(t *testing.T) {
@@ -767,7 +1117,17 @@ func TestWalletDump(t *testing.T) {
 	require.NoError(t, json.Unmarshal([]byte(rawStr), w))
 	require.Equal(t, 1, len(w.Accounts))
 	require.Equal(t, testcli.TestWalletAccount, w.Accounts[0].Address)
+	require.Equal(t, "<redacted>", w.Accounts[0].EncryptedWIF)
 
+	t.Run("show keys", func(t *testing.T) {
+		e.Run(t, append(cmd, "--show-keys")...)
+		rawStr := strings.TrimSpace(e.Out.String())
+		w := new(wallet.Wallet)
+		require.NoError(t, json.Unmarshal([]byte(rawStr), w))
+		require.Equal(t, 1, len(w.Accounts))
+		require.NotEqual(t, "<redacted>", w.Accounts[0].EncryptedWIF)
+		require.NotEmpty(t, w.Accounts[0].EncryptedWIF)
+	})
 	t.Run("with decrypt", func(t *testing.T) {
 		cmd = append(cmd, "--decrypt")
 		t.Run("EOF reading password", func(t *testing.T) {
@@ -854,6 +1214,103 @@ func TestWalletDumpKeys(t *testing.T) {
 		e.CheckNextLine(t, pubRegex)
 		e.CheckEOF(t)
 	})
+	t.Run("to file", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "keys")
+		readKeys := func(t *testing.T) []string {
+			data, err := os.ReadFile(out)
+			require.NoError(t, err)
+			keys := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			for _, k := range keys {
+				require.Regexp(t, pubRegex, k)
+			}
+			return keys
+		}
+		t.Run("include-multisig without out", func(t *testing.T) {
+			e.RunWithError(t, append(cmd, "--include-multisig")...)
+		})
+		t.Run("simple signature only", func(t *testing.T) {
+			e.Run(t, append(cmd, "--out", out)...)
+			e.CheckEOF(t)
+			keys := readKeys(t)
+			require.Equal(t, 1, len(keys))
+		})
+		t.Run("with multisig", func(t *testing.T) {
+			e.Run(t, append(cmd, "--out", out, "--include-multisig")...)
+			e.CheckEOF(t)
+			keys := readKeys(t)
+			// Multisig keys include simple signature ones, no duplicates.
+			require.Equal(t, 4, len(keys))
+		})
+		t.Run("address", func(t *testing.T) {
+			e.Run(t, append(cmd, "--out", out, "-a", "NVTiAjNgagDkTr5HTzDmQP9kPwPHN5BgVq")...)
+			data, err := os.ReadFile(out)
+			require.NoError(t, err)
+			require.Equal(t, 0, len(data))
+		})
+		t.Run("bad path", func(t *testing.T) {
+			e.RunWithError(t, append(cmd, "--out", filepath.Join(out, "missing", "keys"))...)
+		})
+	})
+}
+
+func TestWalletExportPublic(t *testing.T) {
+	e := testcli.NewExecutor(t, false)
+	t.Run("missing wallet", func(t *testing.T) {
+		e.RunWithError(t, "neo-go", "wallet", "export-public")
+	})
+	src, err := os.ReadFile(testcli.ValidatorWallet)
+	require.NoError(t, err)
+	w, err := wallet.NewWalletFromFile(testcli.ValidatorWallet)
+	require.NoError(t, err)
+	defer w.Close()
+
+	cmd := []string{"neo-go", "wallet", "export-public", "--wallet", testcli.ValidatorWallet}
+	export := func(t *testing.T, args ...string) *wallet.Wallet {
+		e.Run(t, append(cmd, args...)...)
+		res := new(wallet.Wallet)
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(e.Out.String())), res))
+		e.Out.Reset()
+		for _, acc := range res.Accounts {
+			require.True(t, acc.IsWatchOnly())
+			require.Equal(t, "", acc.EncryptedWIF)
+		}
+		return res
+	}
+	t.Run("all", func(t *testing.T) {
+		res := export(t)
+		require.Equal(t, len(w.Accounts), len(res.Accounts))
+		for i, acc := range w.Accounts {
+			require.Equal(t, acc.Address, res.Accounts[i].Address)
+			require.Equal(t, acc.Label, res.Accounts[i].Label)
+			require.Equal(t, acc.Contract, res.Accounts[i].Contract)
+		}
+
+		// Exported wallet is usable as a watch-only one.
+		woPath := filepath.Join(t.TempDir(), "wallet.json")
+		data, err := res.JSON()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(woPath, data, wallet.FileMode))
+		e.Run(t, "neo-go", "wallet", "dump-keys", "--wallet", woPath, "-a", "Nhfg3TbpwogLvDGVvAvqyThbsHgoSUKwtn")
+		e.CheckNextLine(t, "watch-only simple signature contract")
+		e.CheckNextLine(t, "^0[23][a-hA-H0-9]{64}$")
+		e.CheckEOF(t)
+	})
+	t.Run("unknown address", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, "--address", util.Uint160{}.StringLE())...)
+	})
+	t.Run("extra arguments", func(t *testing.T) {
+		e.RunWithError(t, append(cmd, "something")...)
+	})
+	t.Run("single", func(t *testing.T) {
+		res := export(t, "-a", "NVTiAjNgagDkTr5HTzDmQP9kPwPHN5BgVq")
+		require.Equal(t, 1, len(res.Accounts))
+		require.Equal(t, "NVTiAjNgagDkTr5HTzDmQP9kPwPHN5BgVq", res.Accounts[0].Address)
+		require.Equal(t, w.GetAccount(res.Accounts[0].ScriptHash()).Contract, res.Accounts[0].Contract)
+	})
+
+	actual, err := os.ReadFile(testcli.ValidatorWallet)
+	require.NoError(t, err)
+	require.Equal(t, src, actual) // Not changed.
 }
 
 // Testcase is the wallet of privnet validator.
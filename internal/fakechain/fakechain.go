@@ -13,6 +13,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
@@ -141,6 +142,11 @@ func (chain *FakeChain) GetStoragePrice() int64 {
 	return native.DefaultStoragePrice
 }
 
+// GetDesignatedByRole implements the Blockchainer interface.
+func (chain *FakeChain) GetDesignatedByRole(r noderoles.Role, index uint32) (keys.PublicKeys, uint32, error) {
+	panic("TODO")
+}
+
 // GetMaxVerificationGAS implements the Policer interface.
 func (chain *FakeChain) GetMaxVerificationGAS() int64 {
 	if chain.MaxVerificationGAS != 0 {
@@ -365,6 +371,11 @@ func (chain *FakeChain) SubscribeForNotifications(ch chan *state.ContainedNotifi
 	panic("TODO")
 }
 
+// SubscribeForRoleChanges implements the Blockchainer interface.
+func (chain *FakeChain) SubscribeForRoleChanges(ch chan *state.RoleChangeEvent) {
+	panic("TODO")
+}
+
 // SubscribeForTransactions implements the Blockchainer interface.
 func (chain *FakeChain) SubscribeForTransactions(ch chan *transaction.Transaction) {
 	panic("TODO")
@@ -405,6 +416,11 @@ func (chain *FakeChain) UnsubscribeFromNotifications(ch chan *state.ContainedNot
 	panic("TODO")
 }
 
+// UnsubscribeFromRoleChanges implements the Blockchainer interface.
+func (chain *FakeChain) UnsubscribeFromRoleChanges(ch chan *state.RoleChangeEvent) {
+	panic("TODO")
+}
+
 // UnsubscribeFromTransactions implements the Blockchainer interface.
 func (chain *FakeChain) UnsubscribeFromTransactions(ch chan *transaction.Transaction) {
 	panic("TODO")
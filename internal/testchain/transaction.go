@@ -6,7 +6,6 @@ import (
 	gio "io"
 	"strings"
 
-	clisc "github.com/nspcc-dev/neo-go/cli/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
@@ -17,7 +16,6 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
@@ -59,7 +57,7 @@ func NewTransferFromOwner(bc Ledger, contractHash, to util.Uint160, amount int64
 
 // NewDeployTx returns a new deployment transaction for a contract with the source from r and a name equal to
 // the filename without '.go' suffix.
-func NewDeployTx(bc Ledger, name string, sender util.Uint160, r gio.Reader, confFile *string) (*transaction.Transaction, util.Uint160, []byte, error) {
+func NewDeployTx(bc Ledger, name string, sender util.Uint160, r gio.Reader) (*transaction.Transaction, util.Uint160, []byte, error) {
 	// nef.NewFile() cares about version a lot.
 	config.Version = "0.90.0-test"
 
@@ -68,23 +66,6 @@ func NewDeployTx(bc Ledger, name string, sender util.Uint160, r gio.Reader, conf
 		NoStandardCheck: true,
 		NoEventsCheck:   true,
 	}
-	if confFile != nil {
-		conf, err := clisc.ParseContractConfig(*confFile)
-		if err != nil {
-			return nil, util.Uint160{}, nil, fmt.Errorf("failed to parse configuration: %w", err)
-		}
-		o.Name = conf.Name
-		o.SourceURL = conf.SourceURL
-		o.ContractEvents = conf.Events
-		o.ContractSupportedStandards = conf.SupportedStandards
-		o.Permissions = make([]manifest.Permission, len(conf.Permissions))
-		for i := range conf.Permissions {
-			o.Permissions[i] = manifest.Permission(conf.Permissions[i])
-		}
-		o.SafeMethods = conf.SafeMethods
-		o.Overloads = conf.Overloads
-	}
-
 	ne, di, err := compiler.CompileWithOptions(name, r, o)
 	if err != nil {
 		return nil, util.Uint160{}, nil, err
@@ -26,7 +26,7 @@ var (
 	goBuiltins = []string{"len", "append", "panic", "make", "copy", "recover", "delete"}
 	// Custom builtin utility functions.
 	customBuiltins = []string{
-		"FromAddress",
+		"FromAddress", "Assert",
 	}
 	// Custom builtin utility functions that contain some meaningful code inside and
 	// require code generation using standard rules, but sometimes (depending on
@@ -707,6 +707,6 @@ func canInline(s string, name string, isBuiltin bool) bool {
 		return false
 	}
 	return !strings.HasPrefix(s[len(interopPrefix):], "/neogointernal") &&
-		!(strings.HasPrefix(s[len(interopPrefix):], "/util") && name == "FromAddress") &&
+		!(strings.HasPrefix(s[len(interopPrefix):], "/util") && (name == "FromAddress" || name == "Assert")) &&
 		!(strings.HasPrefix(s[len(interopPrefix):], "/lib/address") && name == "ToHash160" && isBuiltin)
 }
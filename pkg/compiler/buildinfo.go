@@ -0,0 +1,96 @@
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// BuildInfo contains data needed to reproduce contract compilation and to
+// check that some NEF file is built from the given sources.
+type BuildInfo struct {
+	// Compiler is the compiler name and version (the same as in NEF).
+	Compiler string `json:"compiler"`
+	// GoVersion is the version of Go toolchain the compiler was built with.
+	GoVersion string `json:"goversion"`
+	// Source is the source code URL (the same as in NEF).
+	Source string `json:"source,omitempty"`
+	// ScriptHash is the hash of the contract script.
+	ScriptHash util.Uint160 `json:"scripthash"`
+	// Checksum is the NEF checksum.
+	Checksum uint32 `json:"checksum"`
+	// Files contains hashes of all contract source files along with
+	// go.mod and go.sum (if present).
+	Files []FileHash `json:"files"`
+}
+
+// FileHash is a source file hash.
+type FileHash struct {
+	// Path is the file path relative to the contract directory (absolute
+	// for files outside of it).
+	Path string `json:"path"`
+	// SHA256 is the hex-encoded SHA256 hash of the file contents.
+	SHA256 string `json:"sha256"`
+}
+
+// NewBuildInfo creates build info for the NEF compiled from the sources in
+// the given directory. Source files are taken from the debug info.
+func NewBuildInfo(f *nef.File, di *DebugInfo, dir string) (*BuildInfo, error) {
+	var files = make([]string, 0, len(di.Documents)+2)
+	files = append(files, di.Documents...)
+	for _, name := range []string{"go.mod", "go.sum"} {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			files = append(files, p)
+		}
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res = &BuildInfo{
+			Compiler:   f.Compiler,
+			GoVersion:  runtime.Version(),
+			Source:     f.Source,
+			ScriptHash: hash.Hash160(f.Script),
+			Checksum:   f.Checksum,
+			Files:      make([]FileHash, 0, len(files)),
+		}
+		seen = make(map[string]bool, len(files))
+	)
+	for _, name := range files {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[abs] {
+			continue
+		}
+		seen[abs] = true
+		data, err := os.ReadFile(abs)
+		if err != nil {
+			return nil, fmt.Errorf("can't read source file: %w", err)
+		}
+		path := abs
+		if rel, err := filepath.Rel(absDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.ToSlash(rel)
+		}
+		h := sha256.Sum256(data)
+		res.Files = append(res.Files, FileHash{Path: path, SHA256: hex.EncodeToString(h[:])})
+	}
+	sort.Slice(res.Files, func(i, j int) bool {
+		return res.Files[i].Path < res.Files[j].Path
+	})
+	return res, nil
+}
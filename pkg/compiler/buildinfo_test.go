@@ -0,0 +1,57 @@
+package compiler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBuildInfo(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":       "package main",
+		"sub/helper.go": "package sub",
+		"go.mod":        "module main",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), os.ModePerm))
+		require.NoError(t, os.WriteFile(p, []byte(content), os.ModePerm))
+	}
+	f, err := nef.NewFile([]byte{1, 2, 3})
+	require.NoError(t, err)
+	f.Source = "https://example.com"
+
+	di := &DebugInfo{Documents: []string{
+		filepath.Join(dir, "sub", "helper.go"),
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "main.go"),
+	}}
+	bi, err := NewBuildInfo(f, di, dir)
+	require.NoError(t, err)
+	require.Equal(t, f.Compiler, bi.Compiler)
+	require.Equal(t, runtime.Version(), bi.GoVersion)
+	require.Equal(t, f.Source, bi.Source)
+	require.Equal(t, hash.Hash160(f.Script), bi.ScriptHash)
+	require.Equal(t, f.Checksum, bi.Checksum)
+
+	var expected []FileHash
+	for _, name := range []string{"go.mod", "main.go", "sub/helper.go"} {
+		h := sha256.Sum256([]byte(files[name]))
+		expected = append(expected, FileHash{Path: name, SHA256: hex.EncodeToString(h[:])})
+	}
+	require.Equal(t, expected, bi.Files)
+
+	t.Run("missing file", func(t *testing.T) {
+		di := &DebugInfo{Documents: []string{filepath.Join(dir, "unknown.go")}}
+		_, err := NewBuildInfo(f, di, dir)
+		require.Error(t, err)
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/bitfield"
@@ -111,6 +112,12 @@ type codegen struct {
 
 	// emittedEvents contains all events emitted by the contract.
 	emittedEvents map[string][][]string
+	// events contains events from the compiler options along with the ones
+	// declared in the code.
+	events []manifest.Event
+	// declaredEvents contains events declared in the code only (excluding
+	// the ones also present in the compiler options).
+	declaredEvents []manifest.Event
 
 	// invokedContracts contains invoked methods of other contracts.
 	invokedContracts map[util.Uint160][]string
@@ -120,6 +127,9 @@ type codegen struct {
 
 	// Tokens for CALLT instruction
 	callTokens []nef.MethodToken
+
+	// autoInline contains names of functions inlined by the optimizer.
+	autoInline map[string]bool
 }
 
 type labelOffsetType byte
@@ -968,7 +978,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			if fun.Obj != nil && fun.Obj.Kind == ast.Var {
 				isFunc = true
 			}
-			if ok && canInline(f.pkg.Path(), f.decl.Name.Name, false) {
+			if ok && (canInline(f.pkg.Path(), f.decl.Name.Name, false) || f.autoInline) {
 				c.inlineCall(f, n)
 				return nil
 			}
@@ -979,7 +989,7 @@ func (c *codegen) Visit(node ast.Node) ast.Visitor {
 			if ok {
 				f.selector = fun.X
 				isBuiltin = isCustomBuiltin(f) || isPotentialCustomBuiltin(f, n)
-				if canInline(f.pkg.Path(), f.decl.Name.Name, isBuiltin) {
+				if canInline(f.pkg.Path(), f.decl.Name.Name, isBuiltin) || f.autoInline {
 					c.inlineCall(f, n)
 					return nil
 				}
@@ -1913,6 +1923,14 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 		bytes := uint160.BytesBE()
 		emit.Bytes(c.prog.BinWriter, bytes)
 		c.emitConvert(stackitem.BufferT)
+	case "Assert":
+		// There are no ASSERTMSG/ABORTMSG opcodes, so THROW is used to
+		// get the message into the FAULT exception.
+		end := c.newLabel()
+		c.emitBoolExpr(expr.Args[0], true, true, end)
+		ast.Walk(c, expr.Args[1])
+		emit.Opcodes(c.prog.BinWriter, opcode.THROW)
+		c.setLabel(end)
 	}
 }
 
@@ -1924,12 +1942,17 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 //     push parameters on stack and perform an actual call
 //  2. With panic, the generated code depends on the fact if an argument was nil or a string;
 //     so, it should be handled accordingly.
+//  3. With Assert, the condition is compiled into a jump and the message is
+//     only evaluated if the condition is false.
 func transformArgs(fs *funcScope, fun ast.Expr, isBuiltin bool, args []ast.Expr) []ast.Expr {
 	switch f := fun.(type) {
 	case *ast.SelectorExpr:
 		if f.Sel.Name == "FromAddress" || (isBuiltin && f.Sel.Name == "ToHash160") {
 			return args[1:]
 		}
+		if isBuiltin && f.Sel.Name == "Assert" {
+			return nil
+		}
 		if fs != nil && isSyscall(fs) {
 			return nil
 		}
@@ -2172,6 +2195,9 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 	c.mainPkg = pkg
 	c.analyzePkgOrder()
 	c.fillDocumentInfo()
+	if err := c.collectEvents(); err != nil {
+		return err
+	}
 	funUsage := c.analyzeFuncAndGlobalVarUsage()
 	if c.prog.Err != nil {
 		return c.prog.Err
@@ -2179,6 +2205,9 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 
 	// Bring all imported functions into scope.
 	c.ForEachFile(c.resolveFuncDecls)
+	for name, f := range c.funcs {
+		f.autoInline = c.autoInline[name]
+	}
 
 	hasDeploy := c.traverseGlobals()
 
@@ -2213,7 +2242,7 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 					pkgPath = pkg.Path()
 				}
 				name := c.getFuncNameFromDecl(pkgPath, n)
-				if !isInitFunc(n) && !isDeployFunc(n) && funUsage.funcUsed(name) &&
+				if !isInitFunc(n) && !isDeployFunc(n) && funUsage.funcUsed(name) && !c.autoInline[name] &&
 					(!isInteropPath(pkg.Path()) && !canInline(pkg.Path(), n.Name.Name, false)) {
 					c.convertFuncDecl(f, n, pkg)
 				}
@@ -2253,16 +2282,27 @@ func codeGen(info *buildInfo) (*nef.File, *DebugInfo, error) {
 	if len(info.program) == 0 {
 		return nil, nil, errors.New("empty package")
 	}
+	f, di, c, err := codeGenPass(info, nil)
+	if err != nil || info.options == nil || !info.options.Optimize {
+		return f, di, err
+	}
+	return c.optimize(f, di)
+}
+
+// codeGenPass compiles the program inlining the given functions in addition
+// to the ones that are always inlined.
+func codeGenPass(info *buildInfo, autoInline map[string]bool) (*nef.File, *DebugInfo, *codegen, error) {
 	pkg := info.program[0]
 	c := newCodegen(info, pkg)
+	c.autoInline = autoInline
 
 	if err := c.compile(info, pkg); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	buf, err := c.writeJumps(c.prog.Bytes())
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	methods := bitfield.New(len(buf))
@@ -2272,13 +2312,13 @@ func codeGen(info *buildInfo) (*nef.File, *DebugInfo, error) {
 	}
 	f, err := nef.NewFile(buf)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error while trying to create .nef file: %w", err)
+		return nil, nil, nil, fmt.Errorf("error while trying to create .nef file: %w", err)
 	}
 	if c.callTokens != nil {
 		f.Tokens = c.callTokens
 	}
 	f.Checksum = f.CalculateChecksum()
-	return f, di, vm.IsScriptCorrect(buf, methods)
+	return f, di, c, vm.IsScriptCorrect(buf, methods)
 }
 
 func (c *codegen) resolveFuncDecls(f *ast.File, pkg *types.Package) {
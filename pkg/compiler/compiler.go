@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
@@ -39,6 +40,9 @@ type Options struct {
 	// The name of the output for contract manifest file.
 	ManifestFile string
 
+	// The name of the output for build info (see BuildInfo).
+	BuildInfoFile string
+
 	// NoEventsCheck specifies if events emitted by contract needs to be present in manifest.
 	// This setting has effect only if manifest is emitted.
 	NoEventsCheck bool
@@ -55,7 +59,7 @@ type Options struct {
 	// Name is a contract's name to be written to manifest.
 	Name string
 
-	// SourceURL is a contract's source URL to be written to manifest.
+	// SourceURL is a contract's source URL to be written to NEF.
 	SourceURL string
 
 	// Runtime notifications.
@@ -76,6 +80,19 @@ type Options struct {
 
 	// BindingsFile contains configuration for smart-contract bindings generator.
 	BindingsFile string
+
+	// Optimize enables optimizations: small functions without side effects
+	// are inlined (and not emitted if they're not used otherwise). The
+	// optimized script is used only if it's smaller than the unoptimized one.
+	Optimize bool
+
+	// InlineThreshold is the maximum number of instructions in a function
+	// to be inlined if Optimize is set, DefaultInlineThreshold is used if
+	// it's zero.
+	InlineThreshold int
+
+	// Stats is filled with optimization results if it's not nil.
+	Stats *OptimizationStats
 }
 
 type buildInfo struct {
@@ -179,7 +196,8 @@ func getBuildInfo(name string, src interface{}) (*buildInfo, error) {
 		if singleFile && filepath.Dir(filename) == filepath.Dir(absName) && filename != absName {
 			return nil, nil
 		}
-		const mode = parser.AllErrors
+		// Comments are needed for event declarations.
+		const mode = parser.AllErrors | parser.ParseComments
 		return parser.ParseFile(fset, filename, src, mode)
 	}
 	prog, err := packages.Load(conf, names...)
@@ -216,7 +234,18 @@ func CompileWithOptions(name string, r io.Reader, o *Options) (*nef.File, *Debug
 		return nil, nil, err
 	}
 	ctx.options = o
-	return codeGen(ctx)
+	f, di, err := codeGen(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o != nil && o.SourceURL != "" {
+		if len(o.SourceURL) > nef.MaxSourceURLLength {
+			return nil, nil, errors.New("too long source URL")
+		}
+		f.Source = o.SourceURL
+		f.Checksum = f.CalculateChecksum()
+	}
+	return f, di, nil
 }
 
 // CompileAndSave will compile and save the file to disk in the NEF format.
@@ -236,13 +265,6 @@ func CompileAndSave(src string, o *Options) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error while trying to compile smart contract file: %w", err)
 	}
-	if o.SourceURL != "" {
-		if len(o.SourceURL) > nef.MaxSourceURLLength {
-			return nil, errors.New("too long source URL")
-		}
-		f.Source = o.SourceURL
-		f.Checksum = f.CalculateChecksum()
-	}
 	bytes, err := f.Bytes()
 	if err != nil {
 		return nil, fmt.Errorf("error while serializing .nef file: %w", err)
@@ -252,13 +274,32 @@ func CompileAndSave(src string, o *Options) ([]byte, error) {
 	if err != nil {
 		return f.Script, err
 	}
-	if o.DebugInfo == "" && o.ManifestFile == "" && o.BindingsFile == "" {
+	if o.DebugInfo == "" && o.ManifestFile == "" && o.BindingsFile == "" && o.BuildInfoFile == "" {
 		return f.Script, nil
 	}
 
+	if o.BuildInfoFile != "" {
+		dir := src
+		if strings.HasSuffix(src, ".go") {
+			dir = filepath.Dir(src)
+		}
+		bi, err := NewBuildInfo(f, di, dir)
+		if err != nil {
+			return f.Script, fmt.Errorf("can't create build info: %w", err)
+		}
+		data, err := json.MarshalIndent(bi, "", "  ")
+		if err != nil {
+			return f.Script, err
+		}
+		if err := os.WriteFile(o.BuildInfoFile, data, os.ModePerm); err != nil {
+			return f.Script, err
+		}
+	}
+
 	if o.DebugInfo != "" {
-		di.Events = make([]EventDebugInfo, len(o.ContractEvents))
-		for i, e := range o.ContractEvents {
+		events := append(o.ContractEvents[:len(o.ContractEvents):len(o.ContractEvents)], di.DeclaredEvents...)
+		di.Events = make([]EventDebugInfo, len(events))
+		for i, e := range events {
 			params := make([]DebugParam, len(e.Parameters))
 			for j, p := range e.Parameters {
 				params[j] = DebugParam{
@@ -352,7 +393,12 @@ func CreateManifest(di *DebugInfo, o *Options) (*manifest.Manifest, error) {
 		}
 	}
 	if !o.NoEventsCheck {
+		names := make([]string, 0, len(di.EmittedEvents))
 		for name := range di.EmittedEvents {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
 			ev := m.ABI.GetEvent(name)
 			if ev == nil {
 				return nil, fmt.Errorf("event '%s' is emitted but not specified in manifest", name)
@@ -383,7 +429,13 @@ func CreateManifest(di *DebugInfo, o *Options) (*manifest.Manifest, error) {
 		// 2. Permission may be specified for a group of contracts by public key.
 		// Thus only basic checks are performed.
 
-		for h, methods := range di.InvokedContracts {
+		hashes := make([]util.Uint160, 0, len(di.InvokedContracts))
+		for h := range di.InvokedContracts {
+			hashes = append(hashes, h)
+		}
+		sort.Slice(hashes, func(i, j int) bool { return hashes[i].Less(hashes[j]) })
+		for _, h := range hashes {
+			methods := di.InvokedContracts[h]
 			knownHash := !h.Equals(util.Uint160{})
 
 		methodLoop:
@@ -272,6 +272,96 @@ func TestNotifyInVerify(t *testing.T) {
 	}
 }
 
+func TestEventDeclarations(t *testing.T) {
+	const decl = `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop"
+		import "github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		//go:neo-event Transfer(from Hash160, to Hash160, amount Integer)
+		//go:neo-event Paused()
+		`
+	compile := func(t *testing.T, src string, o *compiler.Options) (*compiler.DebugInfo, error) {
+		if o == nil {
+			o = &compiler.Options{Name: "eventTest"}
+		}
+		_, di, err := compiler.CompileWithOptions("eventTest.go", strings.NewReader(src), o)
+		return di, err
+	}
+
+	t.Run("good", func(t *testing.T) {
+		src := decl + `func Main(from, to interop.Hash160, amount int, paused bool) {
+			if paused {
+				runtime.Notify("Paused")
+			}
+			runtime.Notify("Transfer", from, to, amount)
+			runtime.Notify("Transfer", []byte(from), to, paused)
+		}`
+		di, err := compile(t, src, nil)
+		require.NoError(t, err)
+
+		m, err := compiler.CreateManifest(di, &compiler.Options{Name: "eventTest"})
+		require.NoError(t, err)
+		require.Equal(t, []manifest.Event{
+			{
+				Name: "Transfer",
+				Parameters: []manifest.Parameter{
+					manifest.NewParameter("from", smartcontract.Hash160Type),
+					manifest.NewParameter("to", smartcontract.Hash160Type),
+					manifest.NewParameter("amount", smartcontract.IntegerType),
+				},
+			},
+			{Name: "Paused", Parameters: []manifest.Parameter{}},
+		}, m.ABI.Events)
+	})
+	t.Run("the same event in config", func(t *testing.T) {
+		src := decl + `func Main() { runtime.Notify("Paused") }`
+		o := &compiler.Options{
+			Name:           "eventTest",
+			ContractEvents: []manifest.Event{{Name: "Paused", Parameters: []manifest.Parameter{}}},
+		}
+		di, err := compile(t, src, o)
+		require.NoError(t, err)
+		m, err := compiler.CreateManifest(di, o)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(m.ABI.Events))
+
+		o.ContractEvents[0].Parameters = []manifest.Parameter{manifest.NewParameter("a", smartcontract.IntegerType)}
+		_, err = compile(t, src, o)
+		require.Error(t, err)
+	})
+	t.Run("invalid declaration", func(t *testing.T) {
+		src := `package foo
+		//go:neo-event Transfer(from Unknown)
+		func Main() {}`
+		_, err := compile(t, src, nil)
+		require.Error(t, err)
+	})
+	t.Run("undeclared event", func(t *testing.T) {
+		src := decl + `func Main() { runtime.Notify("Unknown", 1) }`
+		_, err := compile(t, src, nil)
+		require.Error(t, err)
+
+		t.Run("suppress", func(t *testing.T) {
+			_, err := compile(t, src, &compiler.Options{Name: "eventTest", NoEventsCheck: true})
+			require.NoError(t, err)
+		})
+	})
+	t.Run("wrong parameter number", func(t *testing.T) {
+		src := decl + `func Main(from interop.Hash160) { runtime.Notify("Transfer", from, from) }`
+		_, err := compile(t, src, nil)
+		require.Error(t, err)
+	})
+	t.Run("wrong parameter type", func(t *testing.T) {
+		src := decl + `func Main(from interop.Hash160) { runtime.Notify("Transfer", from, from, "1") }`
+		_, err := compile(t, src, nil)
+		require.Error(t, err)
+	})
+	t.Run("variadic call is not checked", func(t *testing.T) {
+		src := decl + `func Main(args []interface{}) { runtime.Notify("Transfer", args...) }`
+		_, err := compile(t, src, nil)
+		require.NoError(t, err)
+	})
+}
+
 func TestInvokedContractsPermissons(t *testing.T) {
 	testCompile := func(t *testing.T, di *compiler.DebugInfo, disable bool, ps ...manifest.Permission) error {
 		o := &compiler.Options{
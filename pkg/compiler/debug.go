@@ -29,6 +29,9 @@ type DebugInfo struct {
 	Events    []EventDebugInfo  `json:"events"`
 	// EmittedEvents contains events occurring in code.
 	EmittedEvents map[string][][]string `json:"-"`
+	// DeclaredEvents contains events declared in the code via
+	// `//go:neo-event` directives (and not present in Options).
+	DeclaredEvents []manifest.Event `json:"-"`
 	// InvokedContracts contains foreign contract invocations.
 	InvokedContracts map[util.Uint160][]string `json:"-"`
 	// StaticVariables contains a list of static variable names and types.
@@ -192,10 +195,21 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 		}
 		d.Methods = append(d.Methods, *m)
 	}
+	// Methods with the same name can come from different packages, so
+	// namespace is also compared to make the order (and thus manifest and
+	// debug info) deterministic.
 	sort.Slice(d.Methods[start:], func(i, j int) bool {
-		return d.Methods[start+i].Name.Name < d.Methods[start+j].Name.Name
+		mi, mj := d.Methods[start+i], d.Methods[start+j]
+		if mi.Name.Name != mj.Name.Name {
+			return mi.Name.Name < mj.Name.Name
+		}
+		if mi.Name.Namespace != mj.Name.Namespace {
+			return mi.Name.Namespace < mj.Name.Namespace
+		}
+		return mi.Range.Start < mj.Range.Start
 	})
 	d.EmittedEvents = c.emittedEvents
+	d.DeclaredEvents = c.declaredEvents
 	d.InvokedContracts = c.invokedContracts
 	return d
 }
@@ -475,6 +489,31 @@ func parsePairJSON(data []byte, sep string) (string, string, error) {
 	return ss[0], ss[1], nil
 }
 
+// SourcePosition returns a source file position ("file:line:column") of the
+// instruction at the given offset or an empty string if it can't be
+// determined. It can be called on nil DebugInfo.
+func (di *DebugInfo) SourcePosition(offset int) string {
+	if di == nil {
+		return ""
+	}
+	for _, m := range di.Methods {
+		if offset < int(m.Range.Start) || offset > int(m.Range.End) {
+			continue
+		}
+		var sp *DebugSeqPoint
+		for i := range m.SeqPoints {
+			if m.SeqPoints[i].Opcode <= offset && (sp == nil || sp.Opcode < m.SeqPoints[i].Opcode) {
+				sp = &m.SeqPoints[i]
+			}
+		}
+		if sp == nil || sp.Document < 0 || sp.Document >= len(di.Documents) {
+			return ""
+		}
+		return fmt.Sprintf("%s:%d:%d", di.Documents[sp.Document], sp.StartLine, sp.StartCol)
+	}
+	return ""
+}
+
 // ConvertToManifest converts a contract to the manifest.Manifest struct for debugger.
 // Note: manifest is taken from the external source, however it can be generated ad-hoc. See #1038.
 func (di *DebugInfo) ConvertToManifest(o *Options) (*manifest.Manifest, error) {
@@ -498,7 +537,7 @@ func (di *DebugInfo) ConvertToManifest(o *Options) (*manifest.Manifest, error) {
 	}
 	result.ABI = manifest.ABI{
 		Methods: methods,
-		Events:  o.ContractEvents,
+		Events:  append(o.ContractEvents[:len(o.ContractEvents):len(o.ContractEvents)], di.DeclaredEvents...),
 	}
 	if result.ABI.Events == nil {
 		result.ABI.Events = make([]manifest.Event, 0)
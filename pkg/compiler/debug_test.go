@@ -364,6 +364,29 @@ func TestDebugInfo_MarshalJSON(t *testing.T) {
 	testserdes.MarshalUnmarshalJSON(t, d, new(DebugInfo))
 }
 
+func TestDebugInfo_SourcePosition(t *testing.T) {
+	d := &DebugInfo{
+		Documents: []string{"a.go", "b.go"},
+		Methods: []MethodDebugInfo{
+			{
+				Range: DebugRange{Start: 0, End: 9},
+				SeqPoints: []DebugSeqPoint{
+					{Opcode: 2, Document: 0, StartLine: 3, StartCol: 4},
+					{Opcode: 5, Document: 1, StartLine: 7, StartCol: 1},
+					{Opcode: 8, Document: 2, StartLine: 9, StartCol: 1},
+				},
+			},
+		},
+	}
+	require.Equal(t, "", d.SourcePosition(1))
+	require.Equal(t, "a.go:3:4", d.SourcePosition(2))
+	require.Equal(t, "a.go:3:4", d.SourcePosition(4))
+	require.Equal(t, "b.go:7:1", d.SourcePosition(6))
+	require.Equal(t, "", d.SourcePosition(8)) // Invalid document.
+	require.Equal(t, "", d.SourcePosition(10))
+	require.Equal(t, "", (*DebugInfo)(nil).SourcePosition(2))
+}
+
 func TestManifestOverload(t *testing.T) {
 	src := `package foo
 	func Main() int {
@@ -0,0 +1,140 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// eventDirective is a comment prefix used to declare contract events in the
+// code, the format is
+//
+//	//go:neo-event Name(param1 Type1, param2 Type2)
+//
+// where types are smartcontract.ParamType names (as accepted by
+// smartcontract.ParseParamType).
+const eventDirective = "//go:neo-event"
+
+// parseEventDirective parses event declaration from the comment text.
+func parseEventDirective(text string) (manifest.Event, error) {
+	var ev manifest.Event
+
+	decl := strings.TrimSpace(strings.TrimPrefix(text, eventDirective))
+	open := strings.IndexByte(decl, '(')
+	if open < 0 || !strings.HasSuffix(decl, ")") {
+		return ev, errors.New("expected Name(param Type, ...) declaration")
+	}
+	ev.Name = strings.TrimSpace(decl[:open])
+	if ev.Name == "" {
+		return ev, errors.New("empty event name")
+	}
+	params := strings.TrimSpace(decl[open+1 : len(decl)-1])
+	ev.Parameters = make([]manifest.Parameter, 0)
+	if params == "" {
+		return ev, nil
+	}
+	for i, p := range strings.Split(params, ",") {
+		fields := strings.Fields(p)
+		if len(fields) != 2 {
+			return ev, fmt.Errorf("parameter #%d: expected name and type", i+1)
+		}
+		typ, err := smartcontract.ParseParamType(fields[1])
+		if err != nil || typ == smartcontract.VoidType {
+			return ev, fmt.Errorf("parameter #%d: bad type %s", i+1, fields[1])
+		}
+		ev.Parameters = append(ev.Parameters, manifest.NewParameter(fields[0], typ))
+	}
+	return ev, nil
+}
+
+// collectEvents parses event declarations from the main package and merges
+// them with events from the compiler options. Declarations must match
+// events from the options if they're present in both.
+func (c *codegen) collectEvents() error {
+	if c.buildInfo.options != nil {
+		c.events = c.buildInfo.options.ContractEvents
+	}
+	fset := c.buildInfo.config.Fset
+	for _, f := range c.mainPkg.Syntax {
+		for _, cg := range f.Comments {
+			for _, cm := range cg.List {
+				if !strings.HasPrefix(cm.Text, eventDirective+" ") {
+					continue
+				}
+				ev, err := parseEventDirective(cm.Text)
+				if err != nil {
+					return fmt.Errorf("%s: invalid event declaration: %w", fset.Position(cm.Pos()), err)
+				}
+				if err := ev.IsValid(); err != nil {
+					return fmt.Errorf("%s: invalid event declaration: %w", fset.Position(cm.Pos()), err)
+				}
+				if known := c.getEvent(ev.Name); known != nil {
+					if !reflect.DeepEqual(known.Parameters, ev.Parameters) {
+						return fmt.Errorf("%s: event '%s' is already declared with different parameters",
+							fset.Position(cm.Pos()), ev.Name)
+					}
+					continue
+				}
+				// Don't modify the options slice.
+				c.events = append(c.events[:len(c.events):len(c.events)], ev)
+				c.declaredEvents = append(c.declaredEvents, ev)
+			}
+		}
+	}
+	return nil
+}
+
+// getEvent returns the event with the given name or nil if it's unknown.
+func (c *codegen) getEvent(name string) *manifest.Event {
+	for i := range c.events {
+		if c.events[i].Name == name {
+			return &c.events[i]
+		}
+	}
+	return nil
+}
+
+// checkEventCall checks runtime.Notify call against the declared event.
+func (c *codegen) checkEventCall(name string, params []smartcontract.ParamType, vParams []*stackitem.Type) error {
+	ev := c.getEvent(name)
+	if ev == nil {
+		return fmt.Errorf("event '%s' is not declared", name)
+	}
+	if len(ev.Parameters) != len(params) {
+		return fmt.Errorf("event '%s' should have %d parameters but has %d",
+			name, len(ev.Parameters), len(params))
+	}
+	for i, p := range ev.Parameters {
+		if !isConvertibleEventParam(params[i], *vParams[i], p.Type) {
+			return fmt.Errorf("event '%s' should have '%s' as type of %d parameter, got: %s",
+				name, p.Type, i+1, params[i])
+		}
+	}
+	return nil
+}
+
+// isConvertibleEventParam checks whether an argument of the given type can be
+// passed as an event parameter of the expected type (probably with conversion).
+func isConvertibleEventParam(actual smartcontract.ParamType, actualVM stackitem.Type, expected smartcontract.ParamType) bool {
+	if expected == smartcontract.AnyType || actual == smartcontract.AnyType || actual == expected {
+		return true
+	}
+	expectedVM := expected.ConvertToStackitemType()
+	switch expectedVM {
+	case stackitem.IntegerT, stackitem.BooleanT:
+		return actualVM == stackitem.IntegerT || actualVM == stackitem.BooleanT
+	case stackitem.ByteArrayT:
+		// String, Hash160, PublicKey and other byte types.
+		return actualVM == stackitem.ByteArrayT || actualVM == stackitem.BufferT
+	case stackitem.ArrayT:
+		return actualVM == stackitem.ArrayT || actualVM == stackitem.StructT
+	case stackitem.InteropT:
+		return actualVM == stackitem.InteropT || actualVM == stackitem.AnyT
+	}
+	return actualVM == expectedVM
+}
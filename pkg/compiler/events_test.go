@@ -0,0 +1,67 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventDirective(t *testing.T) {
+	t.Run("good", func(t *testing.T) {
+		ev, err := parseEventDirective("//go:neo-event Transfer(from Hash160, to Hash160, amount Integer)")
+		require.NoError(t, err)
+		require.Equal(t, manifest.Event{
+			Name: "Transfer",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("from", smartcontract.Hash160Type),
+				manifest.NewParameter("to", smartcontract.Hash160Type),
+				manifest.NewParameter("amount", smartcontract.IntegerType),
+			},
+		}, ev)
+	})
+	t.Run("no parameters", func(t *testing.T) {
+		ev, err := parseEventDirective("//go:neo-event Paused( )")
+		require.NoError(t, err)
+		require.Equal(t, manifest.Event{Name: "Paused", Parameters: []manifest.Parameter{}}, ev)
+	})
+	for _, text := range []string{
+		"//go:neo-event Transfer",
+		"//go:neo-event (a Integer)",
+		"//go:neo-event Transfer(a Integer",
+		"//go:neo-event Transfer(Integer)",
+		"//go:neo-event Transfer(a Integer,)",
+		"//go:neo-event Transfer(a Unknown)",
+		"//go:neo-event Transfer(a Void)",
+	} {
+		t.Run(text, func(t *testing.T) {
+			_, err := parseEventDirective(text)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestIsConvertibleEventParam(t *testing.T) {
+	testCases := []struct {
+		actual   smartcontract.ParamType
+		actualVM stackitem.Type
+		expected smartcontract.ParamType
+		ok       bool
+	}{
+		{smartcontract.IntegerType, stackitem.IntegerT, smartcontract.AnyType, true},
+		{smartcontract.AnyType, stackitem.AnyT, smartcontract.Hash160Type, true},
+		{smartcontract.BoolType, stackitem.BooleanT, smartcontract.IntegerType, true},
+		{smartcontract.ByteArrayType, stackitem.ByteArrayT, smartcontract.Hash160Type, true},
+		{smartcontract.ByteArrayType, stackitem.BufferT, smartcontract.StringType, true},
+		{smartcontract.ArrayType, stackitem.StructT, smartcontract.ArrayType, true},
+		{smartcontract.StringType, stackitem.ByteArrayT, smartcontract.IntegerType, false},
+		{smartcontract.IntegerType, stackitem.IntegerT, smartcontract.Hash160Type, false},
+		{smartcontract.MapType, stackitem.MapT, smartcontract.ArrayType, false},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.ok, isConvertibleEventParam(tc.actual, tc.actualVM, tc.expected),
+			"%s -> %s", tc.actual, tc.expected)
+	}
+}
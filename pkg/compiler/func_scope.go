@@ -47,6 +47,9 @@ type funcScope struct {
 
 	// Local variable counter.
 	i int
+
+	// autoInline is true if the function is inlined by the optimizer.
+	autoInline bool
 }
 
 type deferInfo struct {
@@ -7,6 +7,7 @@ import (
 	"go/types"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/runtime"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -173,10 +174,12 @@ func (c *codegen) processNotify(f *funcScope, args []ast.Expr, hasEllipsis bool)
 	}
 
 	params := make([]string, 0, len(args[1:]))
+	scParams := make([]smartcontract.ParamType, 0, len(args[1:]))
 	vParams := make([]*stackitem.Type, 0, len(args[1:]))
 	for _, p := range args[1:] {
 		st, vt, _ := c.scAndVMTypeFromExpr(p)
 		params = append(params, st.String())
+		scParams = append(scParams, st)
 		vParams = append(vParams, &vt)
 	}
 
@@ -186,9 +189,19 @@ func (c *codegen) processNotify(f *funcScope, args []ast.Expr, hasEllipsis bool)
 			name, runtime.MaxEventNameLen)
 		return nil
 	}
+	noEventsCheck := c.buildInfo.options != nil && c.buildInfo.options.NoEventsCheck
+	// Contracts declaring events in the code are checked strictly, all
+	// notifications must match declarations.
+	strict := len(c.declaredEvents) != 0 && !noEventsCheck
+	if strict {
+		if err := c.checkEventCall(name, scParams, vParams); err != nil {
+			c.prog.Err = err
+			return nil
+		}
+	}
 	var eventFound bool
-	if c.buildInfo.options != nil && c.buildInfo.options.ContractEvents != nil && !c.buildInfo.options.NoEventsCheck {
-		for _, e := range c.buildInfo.options.ContractEvents {
+	if c.events != nil && !noEventsCheck {
+		for _, e := range c.events {
 			if e.Name == name && len(e.Parameters) == len(vParams) {
 				eventFound = true
 				for i, scParam := range e.Parameters {
@@ -213,6 +226,12 @@ func (c *codegen) processNotify(f *funcScope, args []ast.Expr, hasEllipsis bool)
 			}
 		}
 	}
+	if strict {
+		// Parameters are already checked to be convertible.
+		for i, p := range c.getEvent(name).Parameters {
+			params[i] = p.Type.String()
+		}
+	}
 	c.emittedEvents[name] = append(c.emittedEvents[name], params)
 	// Do not enforce perfect expected/actual events match on this step, the final
 	// check wil be performed after compilation if --no-events option is off.
@@ -1,6 +1,7 @@
 package compiler_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
@@ -18,6 +19,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/base58"
 	cinterop "github.com/nspcc-dev/neo-go/pkg/interop"
@@ -229,6 +231,64 @@ func TestAbort(t *testing.T) {
 	require.True(t, v.HasFailed())
 }
 
+func TestAssert(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/util"
+	func Main() int {
+		return check(%d)
+	}
+	func check(a int) int {
+		util.Assert(a > 1, "too small")
+		return a
+	}`
+	t.Run("opcodes", func(t *testing.T) {
+		b, err := compiler.Compile("foo.go", strings.NewReader(fmt.Sprintf(src, 2)))
+		require.NoError(t, err)
+
+		// Comparison is fused with the jump, the message is pushed only if
+		// the condition is false.
+		msg := "too small"
+		expected := []byte{byte(opcode.LDARG0), byte(opcode.PUSH1), byte(opcode.JMPGT), byte(2 + 2 + len(msg) + 1),
+			byte(opcode.PUSHDATA1), byte(len(msg))}
+		expected = append(expected, msg...)
+		expected = append(expected, byte(opcode.THROW))
+		require.True(t, bytes.Contains(b, expected), "%x", b)
+	})
+	t.Run("success", func(t *testing.T) {
+		eval(t, fmt.Sprintf(src, 2), big.NewInt(2))
+	})
+	t.Run("fail", func(t *testing.T) {
+		evalWithError(t, fmt.Sprintf(src, 1), `unhandled exception: "too small"`)
+	})
+	t.Run("lazy message", func(t *testing.T) {
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/util"
+		func Main() int {
+			util.Assert(true, msg())
+			return 1
+		}
+		func msg() string {
+			panic("evaluated")
+		}`
+		eval(t, src, big.NewInt(1))
+	})
+	t.Run("fault exception", func(t *testing.T) {
+		bc, acc := chain.NewSingle(t)
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/util"
+		func Transfer(amount int) bool {
+			util.Assert(amount > 0, "amount must be positive")
+			return true
+		}`
+		ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+		e.DeployContract(t, ctr, nil)
+		c := e.CommitteeInvoker(ctr.Hash)
+		c.Invoke(t, true, "transfer", 1)
+		c.InvokeFail(t, "amount must be positive", "transfer", 0)
+	})
+}
+
 func spawnVM(t *testing.T, ic *interop.Context, src string) *vm.VM {
 	b, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
 	require.NoError(t, err)
@@ -692,3 +752,229 @@ func TestForcedNotifyArgumentsConversion(t *testing.T) {
 		checkSingleType(t, methodWithoutEllipsis, smartcontract.PublicKeyType, stackitem.IntegerT, true)
 	})
 }
+
+func TestInvokeVerifyMultisig(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import (
+			"github.com/nspcc-dev/neo-go/pkg/interop"
+			"github.com/nspcc-dev/neo-go/pkg/interop/native/crypto"
+		)
+		func Verify(msg []byte, pubs []interop.PublicKey, sigs []interop.Signature, curve int) bool {
+			return crypto.VerifyMultisig(msg, pubs, sigs, crypto.NamedCurve(curve))
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+
+	// The helper is inlined, so the only call emitted is CryptoLib's verifyWithECDsa.
+	require.Equal(t, 1, len(ctr.NEF.Tokens))
+	tok := ctr.NEF.Tokens[0]
+	require.Equal(t, e.NativeHash(t, nativenames.CryptoLib), tok.Hash)
+	require.Equal(t, "verifyWithECDsa", tok.Method)
+	require.Equal(t, uint16(4), tok.ParamCount)
+	require.True(t, tok.HasReturn)
+	require.Equal(t, callflag.NoneFlag, tok.CallFlag)
+
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	msg := []byte("message")
+	for name, curve := range map[string]struct {
+		id     int
+		newKey func() (*keys.PrivateKey, error)
+	}{
+		"secp256r1": {int(native.Secp256r1), keys.NewPrivateKey},
+		"secp256k1": {int(native.Secp256k1), keys.NewSecp256k1PrivateKey},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var pubs, sigs []interface{}
+			privs := make([]*keys.PrivateKey, 3)
+			for i := range privs {
+				var err error
+				privs[i], err = curve.newKey()
+				require.NoError(t, err)
+				pubs = append(pubs, privs[i].PublicKey().Bytes())
+				sigs = append(sigs, privs[i].Sign(msg))
+			}
+			check := func(t *testing.T, expected bool, msg []byte, pubs, sigs []interface{}) {
+				c.Invoke(t, stackitem.NewBool(expected), "verify", msg, pubs, sigs, curve.id)
+			}
+
+			t.Run("all", func(t *testing.T) { check(t, true, msg, pubs, sigs) })
+			t.Run("2 of 3", func(t *testing.T) {
+				check(t, true, msg, pubs, []interface{}{sigs[0], sigs[2]})
+				check(t, true, msg, pubs, []interface{}{sigs[1], sigs[2]})
+			})
+			t.Run("1 of 3", func(t *testing.T) { check(t, true, msg, pubs, sigs[2:]) })
+			t.Run("wrong order", func(t *testing.T) {
+				check(t, false, msg, pubs, []interface{}{sigs[2], sigs[0]})
+			})
+			t.Run("duplicate signature", func(t *testing.T) {
+				check(t, false, msg, pubs, []interface{}{sigs[0], sigs[0]})
+			})
+			t.Run("wrong message", func(t *testing.T) { check(t, false, []byte("other"), pubs, sigs) })
+			t.Run("no signatures", func(t *testing.T) { check(t, false, msg, pubs, []interface{}{}) })
+			t.Run("more signatures than keys", func(t *testing.T) {
+				check(t, false, msg, pubs[:2], sigs)
+			})
+		})
+	}
+	t.Run("wrong curve", func(t *testing.T) {
+		priv, err := keys.NewSecp256k1PrivateKey()
+		require.NoError(t, err)
+		c.InvokeFail(t, "failed to decode pubkey", "verify", msg,
+			[]interface{}{priv.PublicKey().Bytes()}, []interface{}{priv.Sign(msg)}, int(native.Secp256r1))
+	})
+}
+
+func TestBase64URL(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/native/std"
+		func Encode(b []byte) string {
+			return std.Base64URLEncode(b)
+		}
+		func Decode(s string) []byte {
+			return std.Base64URLDecode(s)
+		}
+		func RoundTrip(b []byte) []byte {
+			return std.Base64URLDecode(std.Base64URLEncode(b))
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	t.Run("encode", func(t *testing.T) {
+		for _, tc := range []struct {
+			data     []byte
+			expected string
+		}{
+			{[]byte{}, ""},
+			{[]byte("A"), "QQ"},
+			{[]byte("AB"), "QUI"},
+			{[]byte("ABC"), "QUJD"},
+			{[]byte{0xfb, 0xff, 0xbf}, "-_-_"}, // "+/+/" in the standard alphabet.
+		} {
+			c.Invoke(t, stackitem.Make(tc.expected), "encode", tc.data)
+		}
+	})
+	t.Run("decode", func(t *testing.T) {
+		for _, tc := range []struct {
+			s        string
+			expected []byte
+		}{
+			{"", []byte{}},
+			{"QQ", []byte("A")},
+			{"QQ==", []byte("A")},
+			{"QUI", []byte("AB")},
+			{"QUI=", []byte("AB")},
+			{"QUJD", []byte("ABC")},
+			{"-_-_", []byte{0xfb, 0xff, 0xbf}},
+		} {
+			c.Invoke(t, stackitem.Make(tc.expected), "decode", tc.s)
+		}
+	})
+	t.Run("decode, bad input", func(t *testing.T) {
+		for _, s := range []string{"QQ=", "Q===", "QUJD=", "Q"} {
+			c.InvokeFail(t, "illegal base64 data", "decode", s)
+		}
+		c.InvokeFail(t, "invalid base64url character", "decode", "+/+/")
+	})
+	t.Run("round-trip", func(t *testing.T) {
+		header := []byte(`{"alg":"ES256","typ":"JWT"}`)
+		for i := 0; i <= len(header); i++ {
+			c.Invoke(t, stackitem.Make(header[:i]), "roundTrip", header[:i])
+		}
+	})
+}
+
+func TestMemorySearchCompare(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/native/std"
+		func Compare(a, b []byte) int {
+			return std.MemoryCompare(a, b)
+		}
+		func Search(mem, pattern []byte) int {
+			return std.MemorySearch(mem, pattern)
+		}
+		func SearchIndex(mem, pattern []byte, start int) int {
+			return std.MemorySearchIndex(mem, pattern, start)
+		}
+		func SearchLastIndex(mem, pattern []byte, start int) int {
+			return std.MemorySearchLastIndex(mem, pattern, start)
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	t.Run("compare", func(t *testing.T) {
+		for _, tc := range []struct {
+			a, b     []byte
+			expected int64
+		}{
+			{[]byte{}, []byte{}, 0},
+			{[]byte("abc"), []byte("abc"), 0},
+			{[]byte("abc"), []byte("abd"), -1},
+			{[]byte("abd"), []byte("abc"), 1},
+			{[]byte("ab"), []byte("abc"), -1},
+			{[]byte("abc"), []byte("ab"), 1},
+			{[]byte{}, []byte{0}, -1},
+		} {
+			c.Invoke(t, tc.expected, "compare", tc.a, tc.b)
+		}
+	})
+
+	mem := []byte("abcabcabc")
+	t.Run("search", func(t *testing.T) {
+		c.Invoke(t, 0, "search", mem, []byte("abc"))
+		c.Invoke(t, 2, "search", mem, []byte("ca"))
+		c.Invoke(t, -1, "search", mem, []byte("abd"))
+		c.Invoke(t, 0, "search", mem, []byte{})
+	})
+	t.Run("search from index", func(t *testing.T) {
+		c.Invoke(t, 3, "searchIndex", mem, []byte("abc"), 1)
+		c.Invoke(t, 6, "searchIndex", mem, []byte("abc"), 6)
+		c.Invoke(t, -1, "searchIndex", mem, []byte("abc"), 7)
+		c.InvokeFail(t, "slice bounds out of range", "searchIndex", mem, []byte("abc"), len(mem)+1)
+	})
+	t.Run("search backwards", func(t *testing.T) {
+		c.Invoke(t, 6, "searchLastIndex", mem, []byte("abc"), len(mem))
+		c.Invoke(t, 3, "searchLastIndex", mem, []byte("abc"), 8)
+		c.Invoke(t, -1, "searchLastIndex", mem, []byte("abc"), 2)
+	})
+}
+
+func TestGetBlockHash(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import (
+			"github.com/nspcc-dev/neo-go/pkg/interop"
+			"github.com/nspcc-dev/neo-go/pkg/interop/native/ledger"
+		)
+		func GetHash(index int) interop.Hash256 {
+			return ledger.GetBlockHash(index)
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+
+	// The helper is inlined, so the only call emitted is Ledger's getBlock.
+	require.Equal(t, 1, len(ctr.NEF.Tokens))
+	tok := ctr.NEF.Tokens[0]
+	require.Equal(t, e.NativeHash(t, nativenames.Ledger), tok.Hash)
+	require.Equal(t, "getBlock", tok.Method)
+	require.Equal(t, uint16(1), tok.ParamCount)
+	require.True(t, tok.HasReturn)
+	require.Equal(t, callflag.ReadStates, tok.CallFlag)
+
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	for _, i := range []uint32{0, 1, bc.BlockHeight()} {
+		c.Invoke(t, stackitem.Make(bc.GetHeaderHash(int(i)).BytesBE()), "getHash", int64(i))
+	}
+	c.Invoke(t, stackitem.Make([]byte{}), "getHash", int64(bc.BlockHeight()+100))
+	c.Invoke(t, stackitem.Make([]byte{}), "getHash", -1)
+}
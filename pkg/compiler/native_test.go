@@ -2,6 +2,7 @@ package compiler_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
@@ -25,6 +26,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/interop/native/policy"
 	"github.com/nspcc-dev/neo-go/pkg/interop/native/roles"
 	"github.com/nspcc-dev/neo-go/pkg/interop/native/std"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -76,6 +79,24 @@ func TestCryptoLibNamedCurve(t *testing.T) {
 	require.EqualValues(t, native.Secp256r1, crypto.Secp256r1)
 }
 
+func TestCryptoLibMurmur32(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/native/crypto"
+		func Hash(b []byte, seed int) []byte {
+			return crypto.Murmur32(b, seed)
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	// Example from the C# node, see native CryptoLib tests.
+	expected := make([]byte, 4)
+	binary.LittleEndian.PutUint32(expected, 378574820)
+	c.Invoke(t, expected, "hash", []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 1}, 10)
+}
+
 func TestOracleContractValues(t *testing.T) {
 	require.EqualValues(t, oracle.Success, transaction.Success)
 	require.EqualValues(t, oracle.ProtocolNotSupported, transaction.ProtocolNotSupported)
@@ -0,0 +1,200 @@
+package compiler
+
+import (
+	"go/ast"
+	"go/types"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+)
+
+// DefaultInlineThreshold is the default maximum number of instructions in a
+// function to be inlined by the optimizer.
+const DefaultInlineThreshold = 16
+
+// OptimizationStats contains optimization results.
+type OptimizationStats struct {
+	// UnoptimizedSize is the size of the script compiled without
+	// optimizations.
+	UnoptimizedSize int
+	// Size is the size of the resulting script.
+	Size int
+	// Inlined is the number of functions inlined by the optimizer.
+	Inlined int
+}
+
+// optimize recompiles the program inlining small functions found by the
+// previous compilation pass (done by c). The result of the previous pass is
+// returned if it's smaller or if there is nothing to optimize.
+func (c *codegen) optimize(f *nef.File, di *DebugInfo) (*nef.File, *DebugInfo, error) {
+	o := c.buildInfo.options
+	stats := OptimizationStats{
+		UnoptimizedSize: len(f.Script),
+		Size:            len(f.Script),
+	}
+	defer func() {
+		if o.Stats != nil {
+			*o.Stats = stats
+		}
+	}()
+
+	threshold := o.InlineThreshold
+	if threshold <= 0 {
+		threshold = DefaultInlineThreshold
+	}
+	funcs := c.inlineCandidates(f.Script, threshold)
+	if len(funcs) == 0 {
+		return f, di, nil
+	}
+	optF, optDI, _, err := codeGenPass(c.buildInfo, funcs)
+	if err != nil || len(optF.Script) >= len(f.Script) {
+		// Optimization is best-effort, the unoptimized program is correct.
+		return f, di, nil
+	}
+	stats.Size = len(optF.Script)
+	stats.Inlined = len(funcs)
+	return optF, optDI, nil
+}
+
+// inlineCandidates returns names of functions that can be inlined: they're
+// compiled into at most threshold instructions, consist of a single return
+// (or call) statement without side effects except for interop calls and are
+// only used in calls. Contract methods and methods of types are never
+// inlined.
+func (c *codegen) inlineCandidates(script []byte, threshold int) map[string]bool {
+	var (
+		res     = make(map[string]bool)
+		objects = make(map[types.Object]string)
+	)
+	for name, f := range c.funcs {
+		if f.decl == nil || f.rng.End == 0 || isInteropPath(f.pkg.Path()) ||
+			f.pkg == c.mainPkg.Types && f.decl.Name.IsExported() ||
+			isInitFunc(f.decl) || isDeployFunc(f.decl) {
+			continue
+		}
+		pkg := c.packageCache[f.pkg.Path()]
+		if pkg == nil || !c.isInlineable(f.decl, pkg.TypesInfo) ||
+			countInstructions(script[f.rng.Start:int(f.rng.End)+1]) > threshold {
+			continue
+		}
+		if obj := pkg.TypesInfo.Defs[f.decl.Name]; obj != nil {
+			objects[obj] = name
+			res[name] = true
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	// Functions used as values can't be removed from the program, so they're
+	// not inlined at all.
+	for _, pkg := range c.packageCache {
+		for _, file := range pkg.Syntax {
+			called := make(map[*ast.Ident]bool)
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch n := n.(type) {
+				case *ast.CallExpr:
+					switch fun := n.Fun.(type) {
+					case *ast.Ident:
+						called[fun] = true
+					case *ast.SelectorExpr:
+						called[fun.Sel] = true
+					}
+				case *ast.Ident:
+					if name, ok := objects[pkg.TypesInfo.Uses[n]]; ok && !called[n] {
+						delete(res, name)
+					}
+				}
+				return true
+			})
+		}
+	}
+	return res
+}
+
+// isInlineable checks whether the function declaration is simple enough to be
+// inlined without changing program behavior. Arguments of inlined functions
+// without calls are evaluated lazily, so the function body must have no side
+// effects except for interop calls.
+func (c *codegen) isInlineable(decl *ast.FuncDecl, info *types.Info) bool {
+	if decl.Recv != nil || decl.Body == nil || len(decl.Body.List) != 1 {
+		return false
+	}
+	results := decl.Type.Results
+	if results.NumFields() > 1 || results.NumFields() == 1 && results.List[0].Names != nil {
+		return false
+	}
+	for _, p := range decl.Type.Params.List {
+		if _, ok := p.Type.(*ast.Ellipsis); ok || len(p.Names) == 0 {
+			return false
+		}
+		for _, name := range p.Names {
+			if name.Name == "_" {
+				return false
+			}
+		}
+	}
+	switch stmt := decl.Body.List[0].(type) {
+	case *ast.ReturnStmt:
+		if len(stmt.Results) != results.NumFields() {
+			return false
+		}
+	case *ast.ExprStmt:
+		if _, ok := stmt.X.(*ast.CallExpr); !ok || results.NumFields() != 0 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	ok := true
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			ok = false
+		case *ast.CallExpr:
+			var id *ast.Ident
+			switch fun := n.Fun.(type) {
+			case *ast.Ident:
+				id = fun
+			case *ast.SelectorExpr:
+				id = fun.Sel
+			default:
+				// Conversions to composite types are fine.
+				if info.Types[n.Fun].IsType() {
+					return ok
+				}
+				ok = false
+				return false
+			}
+			switch obj := info.Uses[id].(type) {
+			case *types.TypeName:
+			case *types.Builtin:
+				ok = ok && obj.Name() != "recover"
+			case *types.Func:
+				// Notifications are checked against the function they're
+				// emitted from, so they're not inlined.
+				ok = ok && obj.Pkg() != nil && isInteropPath(obj.Pkg().Path()) &&
+					!(obj.Pkg().Path() == interopPrefix+"/runtime" && (obj.Name() == "Notify" || obj.Name() == "Log"))
+			default:
+				ok = false
+			}
+		}
+		return ok
+	})
+	return ok
+}
+
+// countInstructions returns the number of instructions in the script.
+func countInstructions(script []byte) int {
+	var (
+		n   int
+		ctx = vm.NewContext(script)
+	)
+	for ctx.NextIP() < len(script) {
+		if _, _, err := ctx.Next(); err != nil {
+			break
+		}
+		n++
+	}
+	return n
+}
@@ -0,0 +1,92 @@
+package compiler_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimizeInline(t *testing.T) {
+	src := `package foo
+	func Main() int {
+		return add(1, 2) + add(3, 4) + twice(5)
+	}
+	func add(a, b int) int {
+		return a + b
+	}
+	func twice(a int) int {
+		return add(a, a)
+	}`
+
+	check := func(t *testing.T, o *compiler.Options, inlined int) {
+		var stats compiler.OptimizationStats
+		o.Stats = &stats
+		b, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), o)
+		require.NoError(t, err)
+		require.Equal(t, inlined, stats.Inlined)
+		require.Equal(t, len(b.Script), stats.Size)
+		if inlined != 0 {
+			require.Less(t, stats.Size, stats.UnoptimizedSize)
+		} else {
+			require.Equal(t, stats.UnoptimizedSize, stats.Size)
+		}
+	}
+	t.Run("default threshold", func(t *testing.T) {
+		// `twice` calls non-interop function and is not inlined.
+		check(t, &compiler.Options{Optimize: true}, 1)
+	})
+	t.Run("small threshold", func(t *testing.T) {
+		check(t, &compiler.Options{Optimize: true, InlineThreshold: 2}, 0)
+	})
+	eval(t, src, big.NewInt(30))
+}
+
+func TestOptimizeNoInline(t *testing.T) {
+	testCases := map[string]string{
+		"used as value": `func Main() int {
+			f := add
+			return f(1, 2) + add(3, 4)
+		}
+		func add(a, b int) int { return a + b }`,
+		"exported": `func Main() int {
+			return Add(1, 2)
+		}
+		func Add(a, b int) int { return a + b }`,
+		"multiple statements": `func Main() int {
+			return add(1, 2)
+		}
+		func add(a, b int) int {
+			c := a + b
+			return c
+		}`,
+		"named result": `func Main() int {
+			return add(1, 2)
+		}
+		func add(a, b int) (c int) { return a + b }`,
+		"method": `type pair struct { a, b int }
+		func Main() int {
+			p := pair{1, 2}
+			return p.sum()
+		}
+		func (p pair) sum() int { return p.a + p.b }`,
+		"notification": `import "github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		func Main() int {
+			notify(1)
+			return 3
+		}
+		func notify(a int) { runtime.Notify("event", a) }`,
+	}
+	for name, body := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var stats compiler.OptimizationStats
+			_, _, err := compiler.CompileWithOptions("foo.go", strings.NewReader("package foo\n"+body),
+				&compiler.Options{Optimize: true, NoEventsCheck: true, Stats: &stats})
+			require.NoError(t, err)
+			require.Equal(t, 0, stats.Inlined)
+			require.Equal(t, stats.UnoptimizedSize, stats.Size)
+		})
+	}
+}
@@ -15,6 +15,8 @@ import (
 	istorage "github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
 	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
 	"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -72,6 +74,7 @@ func TestSyscallExecution(t *testing.T) {
 		"iterator.Value":                   {interopnames.SystemIteratorValue, []string{"iterator.Iterator{}"}, false},
 		"runtime.BurnGas":                  {interopnames.SystemRuntimeBurnGas, []string{"1"}, true},
 		"runtime.CheckWitness":             {interopnames.SystemRuntimeCheckWitness, []string{b}, false},
+		"runtime.CurrentSigners":           {interopnames.SystemRuntimeCurrentSigners, nil, false},
 		"runtime.GasLeft":                  {interopnames.SystemRuntimeGasLeft, nil, false},
 		"runtime.GetAddressVersion":        {interopnames.SystemRuntimeGetAddressVersion, nil, false},
 		"runtime.GetCallingScriptHash":     {interopnames.SystemRuntimeGetCallingScriptHash, nil, false},
@@ -84,6 +87,7 @@ func TestSyscallExecution(t *testing.T) {
 		"runtime.GetScriptContainer":       {interopnames.SystemRuntimeGetScriptContainer, nil, false},
 		"runtime.GetTime":                  {interopnames.SystemRuntimeGetTime, nil, false},
 		"runtime.GetTrigger":               {interopnames.SystemRuntimeGetTrigger, nil, false},
+		"runtime.LoadScript":               {interopnames.SystemRuntimeLoadScript, []string{b, "contract.All", "1"}, false},
 		"runtime.Log":                      {interopnames.SystemRuntimeLog, []string{`"msg"`}, true},
 		"runtime.Notify":                   {interopnames.SystemRuntimeNotify, []string{`"ev"`, "1"}, true},
 		"runtime.Platform":                 {interopnames.SystemRuntimePlatform, nil, false},
@@ -169,6 +173,37 @@ func runSyscallTestCase(t *testing.T, ic *interop.Context, realName string,
 	}
 }
 
+func TestGetCallFlagsBranching(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	func Main() int {
+		f := contract.GetCallFlags()
+		if f&contract.WriteStates == 0 {
+			return 1
+		}
+		if f&contract.AllowNotify == 0 {
+			return 2
+		}
+		return 3
+	}`
+	for f, expected := range map[callflag.CallFlag]int{
+		callflag.ReadOnly: 1,
+		callflag.States:   2,
+		callflag.All:      3,
+	} {
+		t.Run(f.String(), func(t *testing.T) {
+			v, s, _ := vmAndCompileInterop(t, src)
+			s.interops[interopnames.ToID([]byte(interopnames.SystemContractGetCallFlags))] = func(v *vm.VM) error {
+				v.Estack().PushVal(int64(f))
+				return nil
+			}
+			require.NoError(t, v.Run())
+			require.Equal(t, 1, v.Estack().Len())
+			require.Equal(t, big.NewInt(int64(expected)), v.Estack().Pop().Value())
+		})
+	}
+}
+
 func TestStoragePutGet(t *testing.T) {
 	src := `
 		package foo
@@ -186,6 +221,110 @@ func TestStoragePutGet(t *testing.T) {
 	eval(t, src, []byte("foo"))
 }
 
+func TestStorageMap(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	func Main() []interface{} {
+		ctx := storage.GetContext()
+		m := storage.NewMap(ctx, "pre")
+		m.Put("a", []byte{1})
+		m.Put([]byte("b"), []byte{2})
+		storage.Put(ctx, "c", []byte{3})
+		m.Delete("b")
+		return []interface{}{m.Get("a"), m.Get("b"), m.Get("c"), storage.Get(ctx, "prea")}
+	}`
+	eval(t, src, []stackitem.Item{
+		stackitem.NewByteArray([]byte{1}),
+		stackitem.Null{},
+		stackitem.Null{},
+		stackitem.NewByteArray([]byte{1}),
+	})
+}
+
+func TestStorageStruct(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		type inner struct {
+			c []byte
+		}
+		type point struct {
+			x, y int
+			s    string
+			in   inner
+		}
+		func Put(x, y int, s string) {
+			storage.PutStruct(storage.GetContext(), "p", point{x: x, y: y, s: s, in: inner{c: []byte{7}}})
+		}
+		func Get() []interface{} {
+			p := &point{}
+			if !storage.GetStruct(storage.GetReadOnlyContext(), "p", p) {
+				return nil
+			}
+			return []interface{}{p.x, p.y, p.s, p.in.c}
+		}
+		func GetMissing() bool {
+			p := &point{}
+			return storage.GetStruct(storage.GetReadOnlyContext(), "missing", p)
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	c.Invoke(t, stackitem.Null{}, "get")
+	c.Invoke(t, stackitem.Null{}, "put", 1, 2, "str")
+	c.Invoke(t, []stackitem.Item{
+		stackitem.Make(1),
+		stackitem.Make(2),
+		stackitem.Make("str"),
+		stackitem.NewBuffer([]byte{7}),
+	}, "get")
+	c.Invoke(t, false, "getMissing")
+}
+
+func TestStorageSerialized(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		type point struct {
+			x, y int
+			s    string
+		}
+		func Put(x, y int, s string) {
+			ctx := storage.GetContext()
+			storage.PutSerialized(ctx, "p", point{x: x, y: y, s: s})
+			storage.PutSerialized(ctx, "m", map[string]int{s: x + y})
+		}
+		func Get() []interface{} {
+			ctx := storage.GetReadOnlyContext()
+			v := storage.GetSerialized(ctx, "p")
+			if v == nil {
+				return nil
+			}
+			p := v.(point)
+			m := storage.GetSerialized(ctx, "m").(map[string]int)
+			return []interface{}{p.x, p.y, p.s, m[p.s]}
+		}
+		func GetMissing() bool {
+			return storage.GetSerialized(storage.GetReadOnlyContext(), "missing") == nil
+		}`
+	ctr := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Helper"})
+	e.DeployContract(t, ctr, nil)
+	c := e.CommitteeInvoker(ctr.Hash)
+
+	c.Invoke(t, stackitem.Null{}, "get")
+	c.Invoke(t, stackitem.Null{}, "put", 1, 2, "str")
+	c.Invoke(t, []stackitem.Item{
+		stackitem.Make(1),
+		stackitem.Make(2),
+		stackitem.Make("str"),
+		stackitem.Make(3),
+	}, "get")
+	c.Invoke(t, true, "getMissing")
+}
+
 func TestNotify(t *testing.T) {
 	src := `package foo
 	import "github.com/nspcc-dev/neo-go/pkg/interop/runtime"
@@ -53,6 +53,10 @@ func eval(t *testing.T, src string, result interface{}, expectedOps ...interface
 		require.Equal(t, expected.Bytes(), script)
 	}
 	runAndCheck(t, vm, result)
+
+	// Optimized program must behave exactly the same way.
+	vm, _, _ = vmAndCompileInteropWithOptions(t, src, &compiler.Options{Optimize: true})
+	runAndCheck(t, vm, result)
 	return script
 }
 
@@ -61,6 +65,11 @@ func evalWithError(t *testing.T, src string, e string) []byte {
 	err := vm.Run()
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), e), err)
+
+	vm, _, _ = vmAndCompileInteropWithOptions(t, src, &compiler.Options{Optimize: true})
+	err = vm.Run()
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), e), err)
 	return prog
 }
 
@@ -93,13 +102,17 @@ func vmAndCompile(t *testing.T, src string) *vm.VM {
 }
 
 func vmAndCompileInterop(t *testing.T, src string) (*vm.VM, *storagePlugin, []byte) {
+	return vmAndCompileInteropWithOptions(t, src, nil)
+}
+
+func vmAndCompileInteropWithOptions(t *testing.T, src string, o *compiler.Options) (*vm.VM, *storagePlugin, []byte) {
 	vm := vm.New()
 
 	storePlugin := newStoragePlugin()
 	vm.GasLimit = -1
 	vm.SyscallHandler = storePlugin.syscallHandler
 
-	b, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	b, di, err := compiler.CompileWithOptions("foo.go", strings.NewReader(src), o)
 	require.NoError(t, err)
 
 	storePlugin.info = di
@@ -140,6 +153,7 @@ func newStoragePlugin() *storagePlugin {
 	}
 	s.interops[interopnames.ToID([]byte(interopnames.SystemStorageGet))] = s.Get
 	s.interops[interopnames.ToID([]byte(interopnames.SystemStoragePut))] = s.Put
+	s.interops[interopnames.ToID([]byte(interopnames.SystemStorageDelete))] = s.Delete
 	s.interops[interopnames.ToID([]byte(interopnames.SystemStorageGetContext))] = s.GetContext
 	s.interops[interopnames.ToID([]byte(interopnames.SystemRuntimeNotify))] = s.Notify
 	s.interops[interopnames.ToID([]byte(interopnames.SystemRuntimeGetTime))] = s.GetTime
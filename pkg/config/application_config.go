@@ -10,24 +10,34 @@ type ApplicationConfiguration struct {
 	AnnouncedNodePort uint16 `yaml:"AnnouncedPort"`
 	AttemptConnPeers  int    `yaml:"AttemptConnPeers"`
 	// BroadcastFactor is the factor (0-100) controlling gossip fan-out number optimization.
-	BroadcastFactor   int                      `yaml:"BroadcastFactor"`
-	DBConfiguration   dbconfig.DBConfiguration `yaml:"DBConfiguration"`
-	DialTimeout       int64                    `yaml:"DialTimeout"`
-	LogPath           string                   `yaml:"LogPath"`
-	MaxPeers          int                      `yaml:"MaxPeers"`
-	MinPeers          int                      `yaml:"MinPeers"`
-	NodePort          uint16                   `yaml:"NodePort"`
-	PingInterval      int64                    `yaml:"PingInterval"`
-	PingTimeout       int64                    `yaml:"PingTimeout"`
-	Pprof             BasicService             `yaml:"Pprof"`
-	Prometheus        BasicService             `yaml:"Prometheus"`
-	ProtoTickInterval int64                    `yaml:"ProtoTickInterval"`
-	Relay             bool                     `yaml:"Relay"`
-	RPC               RPC                      `yaml:"RPC"`
-	UnlockWallet      Wallet                   `yaml:"UnlockWallet"`
-	Oracle            OracleConfiguration      `yaml:"Oracle"`
-	P2PNotary         P2PNotary                `yaml:"P2PNotary"`
-	StateRoot         StateRoot                `yaml:"StateRoot"`
+	BroadcastFactor    int                      `yaml:"BroadcastFactor"`
+	DBConfiguration    dbconfig.DBConfiguration `yaml:"DBConfiguration"`
+	DialTimeout        int64                    `yaml:"DialTimeout"`
+	DisableInbound     bool                     `yaml:"DisableInbound"`
+	LogPath            string                   `yaml:"LogPath"`
+	MaxPeers           int                      `yaml:"MaxPeers"`
+	MemPoolPersistence MemPoolPersistence       `yaml:"MemPoolPersistence"`
+	MinPeers           int                      `yaml:"MinPeers"`
+	NAT                NAT                      `yaml:"NAT"`
+	NodePort           uint16                   `yaml:"NodePort"`
+	P2PCompression     bool                     `yaml:"P2PCompression"`
+	PeerDiversity      PeerDiversity            `yaml:"PeerDiversity"`
+	PeerPersistence    PeerPersistence          `yaml:"PeerPersistence"`
+	PeerScoring        PeerScoring              `yaml:"PeerScoring"`
+	PingInterval       int64                    `yaml:"PingInterval"`
+	PingTimeout        int64                    `yaml:"PingTimeout"`
+	Pprof              BasicService             `yaml:"Pprof"`
+	Prometheus         BasicService             `yaml:"Prometheus"`
+	ProtoTickInterval  int64                    `yaml:"ProtoTickInterval"`
+	ProxyAddress       string                   `yaml:"ProxyAddress"`
+	Relay              bool                     `yaml:"Relay"`
+	RPC                RPC                      `yaml:"RPC"`
+	SecureP2P          SecureP2P                `yaml:"SecureP2P"`
+	ShutdownTimeout    int64                    `yaml:"ShutdownTimeout"`
+	UnlockWallet       Wallet                   `yaml:"UnlockWallet"`
+	Oracle             OracleConfiguration      `yaml:"Oracle"`
+	P2PNotary          P2PNotary                `yaml:"P2PNotary"`
+	StateRoot          StateRoot                `yaml:"StateRoot"`
 	// ExtensiblePoolSize is the maximum amount of the extensible payloads from a single sender.
 	ExtensiblePoolSize int `yaml:"ExtensiblePoolSize"`
 }
@@ -41,15 +51,25 @@ func (a *ApplicationConfiguration) EqualsButServices(o *ApplicationConfiguration
 		a.BroadcastFactor != o.BroadcastFactor ||
 		a.DBConfiguration != o.DBConfiguration ||
 		a.DialTimeout != o.DialTimeout ||
+		a.DisableInbound != o.DisableInbound ||
 		a.ExtensiblePoolSize != o.ExtensiblePoolSize ||
 		a.LogPath != o.LogPath ||
 		a.MaxPeers != o.MaxPeers ||
+		a.MemPoolPersistence != o.MemPoolPersistence ||
 		a.MinPeers != o.MinPeers ||
+		a.NAT != o.NAT ||
 		a.NodePort != o.NodePort ||
+		a.P2PCompression != o.P2PCompression ||
+		!a.PeerDiversity.Equals(&o.PeerDiversity) ||
+		a.PeerPersistence != o.PeerPersistence ||
+		a.PeerScoring != o.PeerScoring ||
 		a.PingInterval != o.PingInterval ||
 		a.PingTimeout != o.PingTimeout ||
 		a.ProtoTickInterval != o.ProtoTickInterval ||
-		a.Relay != o.Relay {
+		a.ProxyAddress != o.ProxyAddress ||
+		a.Relay != o.Relay ||
+		!a.SecureP2P.Equals(&o.SecureP2P) ||
+		a.ShutdownTimeout != o.ShutdownTimeout {
 		return false
 	}
 	return true
@@ -1,5 +1,7 @@
 package config
 
+import "fmt"
+
 //go:generate stringer -type=Hardfork -linecomment
 
 // Hardfork represents the application hard-fork identifier.
@@ -10,14 +12,22 @@ const (
 	// https://github.com/neo-project/neo/pull/2712) and #2519 (ported from
 	// https://github.com/neo-project/neo/pull/2749).
 	HFAspidochelone Hardfork = 1 << iota // Aspidochelone
+	// HFBasilisk represents hard-fork enabling System.Runtime.LoadScript
+	// syscall.
+	HFBasilisk // Basilisk
 )
 
-// hardforks holds a map of Hardfork string representation to its type.
-var hardforks map[string]Hardfork
+var (
+	// hardforks holds a map of Hardfork string representation to its type.
+	hardforks map[string]Hardfork
+	// orderedHardforks contains all known hard-forks in the order they're
+	// applied in.
+	orderedHardforks = []Hardfork{HFAspidochelone, HFBasilisk}
+)
 
 func init() {
 	hardforks = make(map[string]Hardfork)
-	for _, hf := range []Hardfork{HFAspidochelone} {
+	for _, hf := range orderedHardforks {
 		hardforks[hf.String()] = hf
 	}
 }
@@ -28,3 +38,58 @@ func IsHardforkValid(s string) bool {
 	_, ok := hardforks[s]
 	return ok
 }
+
+// HardforkFromString returns the Hardfork with the given name, false is
+// returned for unknown names.
+func HardforkFromString(s string) (Hardfork, bool) {
+	hf, ok := hardforks[s]
+	return hf, ok
+}
+
+// OrderedHardforks returns all known hard-forks in the order they're applied
+// in.
+func OrderedHardforks() []Hardfork {
+	res := make([]Hardfork, len(orderedHardforks))
+	copy(res, orderedHardforks)
+	return res
+}
+
+// ValidateHardforks checks the given Hardforks configuration: all hard-forks
+// are to be known and enabled in order.
+func ValidateHardforks(heights map[string]uint32) error {
+	for name := range heights {
+		if !IsHardforkValid(name) {
+			return fmt.Errorf("Hardforks configuration section contains unexpected hardfork: %s", name)
+		}
+	}
+	if err := validateHardforkOrder(heights, orderedHardforks); err != nil {
+		return fmt.Errorf("invalid Hardforks configuration: %w", err)
+	}
+	return nil
+}
+
+// validateHardforkOrder checks that the given hard-fork heights don't
+// decrease in the given hard-fork order and that there are no gaps (a hard-fork
+// can't be enabled without all the previous ones).
+func validateHardforkOrder(heights map[string]uint32, order []Hardfork) error {
+	var (
+		prev    Hardfork
+		prevSet bool
+	)
+	for i, hf := range order {
+		h, ok := heights[hf.String()]
+		if !ok {
+			prevSet = false
+			prev = hf
+			continue
+		}
+		if i > 0 && !prevSet {
+			return fmt.Errorf("hardfork %s is enabled, but the previous %s is not", hf, prev)
+		}
+		if i > 0 && h < heights[prev.String()] {
+			return fmt.Errorf("hardfork %s is enabled at %d, before the previous %s", hf, h, prev)
+		}
+		prev, prevSet = hf, true
+	}
+	return nil
+}
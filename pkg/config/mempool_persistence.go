@@ -0,0 +1,12 @@
+package config
+
+// MemPoolPersistence contains configuration of memory pool contents storage,
+// pooled transactions (and P2P notary requests) are saved into the node's DB
+// on shutdown and are verified and added back to the pool on the next start.
+type MemPoolPersistence struct {
+	Enabled bool `yaml:"Enabled"`
+	// MaxTransactions is the maximum number of transactions (and separately
+	// notary requests) stored, the most prioritized ones are preferred.
+	// 10000 is used if it's not set.
+	MaxTransactions int `yaml:"MaxTransactions"`
+}
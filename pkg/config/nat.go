@@ -0,0 +1,13 @@
+package config
+
+// NAT contains configuration of the automatic P2P port mapping done via UPnP
+// IGD or NAT-PMP for nodes running behind home routers.
+type NAT struct {
+	Enabled bool `yaml:"Enabled"`
+	// Gateway is the NAT-PMP gateway address, the default one is used if
+	// it's not set.
+	Gateway string `yaml:"Gateway"`
+	// LeaseDuration is the port mapping lifetime in seconds, the mapping is
+	// refreshed in a half of it.
+	LeaseDuration int64 `yaml:"LeaseDuration"`
+}
@@ -12,9 +12,32 @@ type OracleConfiguration struct {
 	MaxTaskTimeout        time.Duration      `yaml:"MaxTaskTimeout"`
 	RefreshInterval       time.Duration      `yaml:"RefreshInterval"`
 	MaxConcurrentRequests int                `yaml:"MaxConcurrentRequests"`
+	MaxRequestsPerSecond  float64            `yaml:"MaxRequestsPerSecond"`
 	RequestTimeout        time.Duration      `yaml:"RequestTimeout"`
 	ResponseTimeout       time.Duration      `yaml:"ResponseTimeout"`
+	CachePath             string             `yaml:"CachePath"`
 	UnlockWallet          Wallet             `yaml:"UnlockWallet"`
+	TLS                   OracleTLS          `yaml:"TLS"`
+	// Resolver is the address (host:port) of DNS server used to resolve
+	// request hosts, system resolver is used if not set.
+	Resolver string `yaml:"Resolver"`
+}
+
+// OracleTLS is a TLS configuration for oracle HTTPS requests, Go defaults
+// are used for everything that is not set.
+type OracleTLS struct {
+	// MinVersion is the minimum TLS version allowed ("1.0"-"1.3").
+	MinVersion string `yaml:"MinVersion"`
+	// RootCAs is a PEM file with CA certificates used instead of the
+	// system ones to verify servers.
+	RootCAs string `yaml:"RootCAs"`
+	// CertFile and KeyFile are PEM-encoded client certificate and key
+	// presented to servers requesting them.
+	CertFile string `yaml:"CertFile"`
+	KeyFile  string `yaml:"KeyFile"`
+	// InsecureSkipVerify disables server certificate verification, it
+	// should only be used for testing.
+	InsecureSkipVerify bool `yaml:"InsecureSkipVerify"`
 }
 
 // NeoFSConfiguration is a config for the NeoFS service.
@@ -0,0 +1,29 @@
+package config
+
+// PeerDiversity contains P2P connection diversity configuration, it limits the
+// number of connections to nodes from the same autonomous system (if ASN map
+// is provided) or the same network (/16 prefix for IPv4 and /32 for IPv6).
+type PeerDiversity struct {
+	// MaxPerPrefix is the maximum number of connections to nodes sharing the
+	// same network prefix, zero (default) disables the limit.
+	MaxPerPrefix int `yaml:"MaxPerPrefix"`
+	// Trusted is a list of IP addresses and networks (in CIDR notation) that
+	// are not subject to the limit.
+	Trusted []string `yaml:"Trusted"`
+	// ASNMap is an optional path to the file mapping networks to AS
+	// numbers, addresses not found there are grouped by network prefix.
+	ASNMap string `yaml:"ASNMap"`
+}
+
+// Equals checks whether two configurations are the same.
+func (p *PeerDiversity) Equals(o *PeerDiversity) bool {
+	if p.MaxPerPrefix != o.MaxPerPrefix || p.ASNMap != o.ASNMap || len(p.Trusted) != len(o.Trusted) {
+		return false
+	}
+	for i := range p.Trusted {
+		if p.Trusted[i] != o.Trusted[i] {
+			return false
+		}
+	}
+	return true
+}
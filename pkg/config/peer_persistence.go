@@ -0,0 +1,17 @@
+package config
+
+// PeerPersistence contains configuration of known good peer addresses storage,
+// these addresses are saved into a file and used on the next start to connect
+// to the network without going through the seed list first.
+type PeerPersistence struct {
+	Enabled bool `yaml:"Enabled"`
+	// File is the path to the file addresses are stored in, it's required if
+	// persistence is enabled.
+	File string `yaml:"File"`
+	// MaxAge is the time (in seconds) after which an address that wasn't
+	// seen is no longer stored, 7 days are used if it's not set.
+	MaxAge int64 `yaml:"MaxAge"`
+	// MaxAddresses is the maximum number of addresses stored, 1000 is used
+	// if it's not set.
+	MaxAddresses int `yaml:"MaxAddresses"`
+}
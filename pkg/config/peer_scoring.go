@@ -0,0 +1,13 @@
+package config
+
+// PeerScoring contains P2P peer quality scoring configuration.
+type PeerScoring struct {
+	// HalfLife is the time (in seconds) it takes for peer's misbehavior and
+	// usefulness counters to decrease twice. Default (used when it's zero)
+	// is 600 seconds.
+	HalfLife int64 `yaml:"HalfLife"`
+	// DisconnectScore is the score threshold, peers scoring lower than that
+	// are disconnected and not accepted until their score decays above it.
+	// It's a negative number, zero disables this check.
+	DisconnectScore float64 `yaml:"DisconnectScore"`
+}
@@ -22,10 +22,15 @@ type (
 
 		Magic       netmode.Magic `yaml:"Magic"`
 		MemPoolSize int           `yaml:"MemPoolSize"`
+		// MemPoolReplaceByFee contains memory pool transaction replacement settings.
+		MemPoolReplaceByFee ReplaceByFee `yaml:"MemPoolReplaceByFee"`
 
 		// Hardforks is a map of hardfork names that enables version-specific application
 		// logic dependent on the specified height.
 		Hardforks map[string]uint32 `yaml:"Hardforks"`
+		// HardforkQueryExtension enables System.Runtime.IsHardforkEnabled
+		// interop allowing contracts to check for active hard-forks.
+		HardforkQueryExtension bool `yaml:"HardforkQueryExtension"`
 		// InitialGASSupply is the amount of GAS generated in the genesis block.
 		InitialGASSupply fixedn.Fixed8 `yaml:"InitialGASSupply"`
 		// P2PNotaryRequestPayloadPoolSize specifies the memory pool size for P2PNotaryRequestPayloads.
@@ -64,6 +69,8 @@ type (
 		StandbyCommittee []string `yaml:"StandbyCommittee"`
 		// StateRooInHeader enables storing state root in block header.
 		StateRootInHeader bool `yaml:"StateRootInHeader"`
+		// StatePruning contains background MPT state pruning settings.
+		StatePruning StatePruning `yaml:"StatePruning"`
 		// StateSyncInterval is the number of blocks between state heights available for MPT state data synchronization.
 		// It is valid only if P2PStateExchangeExtensions are enabled.
 		StateSyncInterval int `yaml:"StateSyncInterval"`
@@ -92,15 +99,21 @@ func (p *ProtocolConfiguration) Validate() error {
 	if p.P2PStateExchangeExtensions && p.KeepOnlyLatestState && !p.RemoveUntraceableBlocks {
 		return fmt.Errorf("P2PStateExchangeExtensions can be enabled either on MPT-complete node (KeepOnlyLatestState=false) or on light GC-enabled node (RemoveUntraceableBlocks=true)")
 	}
+	if p.StatePruning.Enabled {
+		if p.P2PStateExchangeExtensions {
+			return errors.New("StatePruning can't be enabled along with P2PStateExchangeExtensions")
+		}
+		if p.StatePruning.RetainRoots == 0 {
+			return errors.New("StatePruning requires positive RetainRoots")
+		}
+	}
 	for name := range p.NativeUpdateHistories {
 		if !nativenames.IsValid(name) {
 			return fmt.Errorf("NativeActivations configuration section contains unexpected native contract name: %s", name)
 		}
 	}
-	for name := range p.Hardforks {
-		if !IsHardforkValid(name) {
-			return fmt.Errorf("Hardforks configuration section contains unexpected hardfork: %s", name)
-		}
+	if err := ValidateHardforks(p.Hardforks); err != nil {
+		return err
 	}
 	if p.ValidatorsCount != 0 && len(p.ValidatorsHistory) != 0 {
 		return errors.New("configuration should either have ValidatorsCount or ValidatorsHistory, not both")
@@ -201,6 +214,7 @@ func (p *ProtocolConfiguration) ShouldUpdateCommitteeAt(height uint32) bool {
 // they're equal.
 func (p *ProtocolConfiguration) Equals(o *ProtocolConfiguration) bool {
 	if p.GarbageCollectionPeriod != o.GarbageCollectionPeriod ||
+		p.HardforkQueryExtension != o.HardforkQueryExtension ||
 		p.InitialGASSupply != o.InitialGASSupply ||
 		p.KeepOnlyLatestState != o.KeepOnlyLatestState ||
 		p.Magic != o.Magic ||
@@ -210,6 +224,7 @@ func (p *ProtocolConfiguration) Equals(o *ProtocolConfiguration) bool {
 		p.MaxTransactionsPerBlock != o.MaxTransactionsPerBlock ||
 		p.MaxValidUntilBlockIncrement != o.MaxValidUntilBlockIncrement ||
 		p.MemPoolSize != o.MemPoolSize ||
+		p.MemPoolReplaceByFee != o.MemPoolReplaceByFee ||
 		p.P2PNotaryRequestPayloadPoolSize != o.P2PNotaryRequestPayloadPoolSize ||
 		p.P2PSigExtensions != o.P2PSigExtensions ||
 		p.P2PStateExchangeExtensions != o.P2PStateExchangeExtensions ||
@@ -217,6 +232,7 @@ func (p *ProtocolConfiguration) Equals(o *ProtocolConfiguration) bool {
 		p.ReservedAttributes != o.ReservedAttributes ||
 		p.SaveStorageBatch != o.SaveStorageBatch ||
 		p.SecondsPerBlock != o.SecondsPerBlock ||
+		p.StatePruning != o.StatePruning ||
 		p.StateRootInHeader != o.StateRootInHeader ||
 		p.StateSyncInterval != o.StateSyncInterval ||
 		p.ValidatorsCount != o.ValidatorsCount ||
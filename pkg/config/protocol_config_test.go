@@ -107,6 +107,26 @@ func TestProtocolConfigurationValidation(t *testing.T) {
 		},
 	}
 	require.Error(t, p.Validate())
+	p = &ProtocolConfiguration{
+		Hardforks: map[string]uint32{
+			"Aspidochelone": 123, // Proper single hard-fork.
+		},
+	}
+	require.NoError(t, p.Validate())
+	p = &ProtocolConfiguration{
+		StatePruning: StatePruning{
+			Enabled: true, // No RetainRoots.
+		},
+	}
+	require.Error(t, p.Validate())
+	p = &ProtocolConfiguration{
+		P2PStateExchangeExtensions: true,
+		StatePruning: StatePruning{
+			Enabled:     true,
+			RetainRoots: 100,
+		},
+	}
+	require.Error(t, p.Validate())
 	p = &ProtocolConfiguration{
 		StandbyCommittee: []string{
 			"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
@@ -217,3 +237,27 @@ func TestProtocolConfigurationEquals(t *testing.T) {
 	p.ValidatorsHistory = map[uint32]int{112: 0}
 	require.False(t, p.Equals(o))
 }
+
+func TestValidateHardforkOrder(t *testing.T) {
+	var (
+		next  = HFBasilisk
+		order = orderedHardforks
+	)
+	require.NoError(t, validateHardforkOrder(nil, order))
+	require.NoError(t, validateHardforkOrder(map[string]uint32{HFAspidochelone.String(): 5}, order))
+	require.NoError(t, validateHardforkOrder(map[string]uint32{
+		HFAspidochelone.String(): 5,
+		next.String():            5,
+	}, order))
+	require.NoError(t, validateHardforkOrder(map[string]uint32{
+		HFAspidochelone.String(): 5,
+		next.String():            10,
+	}, order))
+	require.Error(t, validateHardforkOrder(map[string]uint32{
+		next.String(): 10, // No Aspidochelone.
+	}, order))
+	require.Error(t, validateHardforkOrder(map[string]uint32{
+		HFAspidochelone.String(): 10,
+		next.String():            5, // Before Aspidochelone.
+	}, order))
+}
@@ -0,0 +1,17 @@
+package config
+
+// ReplaceByFee contains memory pool transaction replacement settings.
+type ReplaceByFee struct {
+	// Enabled allows a transaction to replace the pooled one having the
+	// same sender and nonce if it pays enough network fee.
+	Enabled bool `yaml:"Enabled"`
+	// MinFeeIncrease is the minimum network fee increase (in percents)
+	// required for a transaction to replace the pooled one of the same
+	// sender (either via Conflicts attribute or via the same nonce). It's
+	// only applied if Enabled is set.
+	MinFeeIncrease uint32 `yaml:"MinFeeIncrease"`
+	// MaxReplacements is the maximum number of times a transaction can be
+	// replaced in a row, 10 is used if it's not set. It's only applied if
+	// Enabled is set.
+	MaxReplacements uint32 `yaml:"MaxReplacements"`
+}
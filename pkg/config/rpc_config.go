@@ -16,13 +16,20 @@ type (
 		MaxIteratorResultItems int           `yaml:"MaxIteratorResultItems"`
 		MaxFindResultItems     int           `yaml:"MaxFindResultItems"`
 		MaxNEP11Tokens         int           `yaml:"MaxNEP11Tokens"`
-		Port                   uint16        `yaml:"Port"`
-		SessionEnabled         bool          `yaml:"SessionEnabled"`
-		SessionExpirationTime  int           `yaml:"SessionExpirationTime"`
-		SessionBackedByMPT     bool          `yaml:"SessionBackedByMPT"`
-		SessionPoolSize        int           `yaml:"SessionPoolSize"`
-		StartWhenSynchronized  bool          `yaml:"StartWhenSynchronized"`
-		TLSConfig              TLS           `yaml:"TLSConfig"`
+		// NotaryStateEnabled enables getnotaryservicestate method exposing
+		// Notary service internals, it's disabled by default.
+		NotaryStateEnabled    bool   `yaml:"NotaryStateEnabled"`
+		Port                  uint16 `yaml:"Port"`
+		SessionEnabled        bool   `yaml:"SessionEnabled"`
+		SessionExpirationTime int    `yaml:"SessionExpirationTime"`
+		SessionBackedByMPT    bool   `yaml:"SessionBackedByMPT"`
+		SessionPoolSize       int    `yaml:"SessionPoolSize"`
+		StartWhenSynchronized bool   `yaml:"StartWhenSynchronized"`
+		TLSConfig             TLS    `yaml:"TLSConfig"`
+		// TraceEnabled enables invokescripttrace method, it's disabled by
+		// default because tracing is costly.
+		TraceEnabled  bool `yaml:"TraceEnabled"`
+		TraceMaxSteps int  `yaml:"TraceMaxSteps"`
 	}
 
 	// TLS describes SSL/TLS configuration.
@@ -0,0 +1,40 @@
+package config
+
+// SecureP2P contains configuration of the encrypted P2P transport used for
+// connections between trusted nodes. It's a separate TLS listener with mutual
+// authentication, the standard P2P protocol is used inside of the tunnel.
+type SecureP2P struct {
+	Enabled bool   `yaml:"Enabled"`
+	Address string `yaml:"Address"`
+	Port    uint16 `yaml:"Port"`
+	// CertFile and KeyFile are PEM-encoded certificate and key of the node.
+	CertFile string `yaml:"CertFile"`
+	KeyFile  string `yaml:"KeyFile"`
+	// TrustedCertificates is a list of PEM files with certificates (either
+	// the nodes' own or CA ones) that remote nodes are allowed to use.
+	TrustedCertificates []string `yaml:"TrustedCertificates"`
+	// Peers is a list of secure listener addresses of trusted nodes, they're
+	// always dialed via the encrypted transport.
+	Peers []string `yaml:"Peers"`
+}
+
+// Equals checks whether two configurations are the same.
+func (s *SecureP2P) Equals(o *SecureP2P) bool {
+	if s.Enabled != o.Enabled || s.Address != o.Address || s.Port != o.Port ||
+		s.CertFile != o.CertFile || s.KeyFile != o.KeyFile ||
+		len(s.TrustedCertificates) != len(o.TrustedCertificates) ||
+		len(s.Peers) != len(o.Peers) {
+		return false
+	}
+	for i := range s.TrustedCertificates {
+		if s.TrustedCertificates[i] != o.TrustedCertificates[i] {
+			return false
+		}
+	}
+	for i := range s.Peers {
+		if s.Peers[i] != o.Peers[i] {
+			return false
+		}
+	}
+	return true
+}
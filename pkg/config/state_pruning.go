@@ -0,0 +1,16 @@
+package config
+
+// StatePruning contains configuration of background MPT state pruning that
+// removes nodes not reachable from the latest state roots.
+type StatePruning struct {
+	Enabled bool `yaml:"Enabled"`
+	// RetainRoots is the number of the latest state roots whose data is
+	// kept, it must be positive if pruning is enabled.
+	RetainRoots uint32 `yaml:"RetainRoots"`
+	// Period is the number of blocks between pruning cycles starts, 10000
+	// is used if it's not set.
+	Period uint32 `yaml:"Period"`
+	// MaxStepTime is the maximum time (in milliseconds) spent on pruning
+	// after every persist, 100 is used if it's not set.
+	MaxStepTime int64 `yaml:"MaxStepTime"`
+}
@@ -2,6 +2,9 @@ package config
 
 // StateRoot contains state root service configuration.
 type StateRoot struct {
-	Enabled      bool   `yaml:"Enabled"`
-	UnlockWallet Wallet `yaml:"UnlockWallet"`
+	Enabled bool `yaml:"Enabled"`
+	// HaltOnMismatch stops block processing when local state root doesn't
+	// match the one validated by state validators.
+	HaltOnMismatch bool   `yaml:"HaltOnMismatch"`
+	UnlockWallet   Wallet `yaml:"UnlockWallet"`
 }
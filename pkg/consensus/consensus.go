@@ -157,10 +157,18 @@ func NewService(cfg Config) (Service, error) {
 	if srv.wallet, err = wallet.NewWalletFromFile(cfg.Wallet.Path); err != nil {
 		return nil, err
 	}
+	if err := wallet.CheckPermissions(cfg.Wallet.Path); err != nil {
+		srv.log.Warn("insecure wallet permissions", zap.Error(err))
+	}
 
 	// Check that the wallet password is correct for at least one account.
+	// Remote signer accounts are not supported, signing is synchronous in
+	// dBFT and can't wait for an external service.
 	var ok bool
 	for _, acc := range srv.wallet.Accounts {
+		if acc.IsRemote() {
+			continue
+		}
 		err := acc.Decrypt(srv.Config.Wallet.Password, srv.wallet.Scrypt)
 		if err == nil {
 			ok = true
@@ -383,6 +391,10 @@ func (s *service) getKeyPair(pubs []crypto.PublicKey) (int, crypto.PrivateKey, c
 		if acc == nil {
 			continue
 		}
+		if acc.IsRemote() {
+			s.log.Error("remote signer accounts can't be used for consensus", zap.String("address", address.Uint160ToString(sh)))
+			continue
+		}
 
 		if !acc.CanSign() {
 			err := acc.Decrypt(s.Config.Wallet.Password, s.wallet.Scrypt)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math/big"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/random"
@@ -17,6 +18,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
 )
@@ -33,6 +35,35 @@ func BenchmarkBlockchain_VerifyWitness(t *testing.B) {
 	}
 }
 
+func BenchmarkBlockchain_AddBlock(t *testing.B) {
+	const txCount = 500
+	for _, procs := range []int{1, 2, 4} {
+		t.Run(fmt.Sprintf("GOMAXPROCS=%d", procs), func(t *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			benchmarkAddBlock(t, txCount)
+		})
+	}
+}
+
+func benchmarkAddBlock(t *testing.B, txCount int) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+
+	t.ResetTimer()
+	for n := 0; n < t.N; n++ {
+		t.StopTimer()
+		txs := make([]*transaction.Transaction, txCount)
+		for i := range txs {
+			txs[i] = e.NewTx(t, []neotest.Signer{acc}, gasHash, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+		}
+		b := e.NewUnsignedBlock(t, txs...)
+		e.SignBlock(b)
+		t.StartTimer()
+		require.NoError(t, bc.AddBlock(b))
+	}
+}
+
 func BenchmarkBlockchain_ForEachNEP17Transfer(t *testing.B) {
 	var stores = map[string]func(testing.TB) storage.Store{
 		"MemPS": func(t testing.TB) storage.Store {
@@ -14,6 +14,7 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/limits"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/dao"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
@@ -56,6 +57,9 @@ const (
 	defaultMaxTraceableBlocks              = 2102400 // 1 year of 15s blocks
 	defaultMaxTransactionsPerBlock         = 512
 	defaultSecondsPerBlock                 = 15
+	defaultStatePruningPeriod              = 10000
+	defaultStatePruningMaxStepTime         = 100 // ms
+	defaultMemPoolMaxReplacements          = 10
 	// HeaderVerificationGasLimit is the maximum amount of GAS for block header verification.
 	HeaderVerificationGasLimit = 3_00000000 // 3 GAS
 	defaultStateSyncInterval   = 40000
@@ -105,6 +109,11 @@ var (
 // the state of the ledger that can be accessed in various ways and changed by
 // adding new blocks or headers.
 type Blockchain struct {
+	// droppedEvents is the number of events not delivered to non-blocking
+	// subscribers, it's the first field to be 64-bit aligned for atomic
+	// operations.
+	droppedEvents uint64
+
 	config config.ProtocolConfiguration
 
 	// The only way chain state changes is by adding blocks, so we can't
@@ -166,6 +175,13 @@ type Blockchain struct {
 
 	extensible atomic.Value
 
+	// onExecHook is the vm.OnExecHook set for all VMs created by the chain.
+	onExecHook atomic.Value
+
+	// hardforks contains hard-fork activation heights (map[string]uint32)
+	// used instead of the configured ones, see SetHardforks.
+	hardforks atomic.Value
+
 	// knownValidatorsCount is the latest known validators count used
 	// for defaultBlockWitness.
 	knownValidatorsCount atomic.Value
@@ -175,10 +191,23 @@ type Blockchain struct {
 
 	stateRoot *stateroot.Module
 
+	// pruneLock protects state pruning data below.
+	pruneLock sync.Mutex
+	// pruner is the current state pruning cycle (if any).
+	pruner *stateroot.Pruner
+	// pruneStartedAt is the state height the last pruning cycle was started at.
+	pruneStartedAt uint32
+	// prunedHeight is the lowest height state is retained for after pruning,
+	// it's accessed atomically.
+	prunedHeight uint32
+
 	// Notification subsystem.
 	events  chan bcEvent
 	subCh   chan interface{}
 	unsubCh chan interface{}
+	// roleChanges contains role changes made by the block being stored,
+	// it's only accessed from storeBlock().
+	roleChanges []*state.RoleChangeEvent
 }
 
 // StateRoot represents local state root module.
@@ -200,6 +229,7 @@ type StateRoot interface {
 type bcEvent struct {
 	block          *block.Block
 	appExecResults []*state.AppExecResult
+	roleChanges    []*state.RoleChangeEvent
 }
 
 // transferData is used for transfer caching during storeBlock.
@@ -272,6 +302,21 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 		cfg.GarbageCollectionPeriod = defaultGCPeriod
 		log.Info("GarbageCollectionPeriod is not set or wrong, using default value", zap.Uint32("GarbageCollectionPeriod", cfg.GarbageCollectionPeriod))
 	}
+	if cfg.StatePruning.Enabled {
+		if cfg.StatePruning.Period == 0 {
+			cfg.StatePruning.Period = defaultStatePruningPeriod
+			log.Info("StatePruning.Period is not set or wrong, using default value", zap.Uint32("Period", cfg.StatePruning.Period))
+		}
+		if cfg.StatePruning.MaxStepTime <= 0 {
+			cfg.StatePruning.MaxStepTime = defaultStatePruningMaxStepTime
+			log.Info("StatePruning.MaxStepTime is not set or wrong, using default value", zap.Int64("MaxStepTime", cfg.StatePruning.MaxStepTime))
+		}
+	}
+	if cfg.MemPoolReplaceByFee.Enabled && cfg.MemPoolReplaceByFee.MaxReplacements == 0 {
+		cfg.MemPoolReplaceByFee.MaxReplacements = defaultMemPoolMaxReplacements
+		log.Info("MemPoolReplaceByFee.MaxReplacements is not set or wrong, using default value",
+			zap.Uint32("MaxReplacements", cfg.MemPoolReplaceByFee.MaxReplacements))
+	}
 	if len(cfg.NativeUpdateHistories) == 0 {
 		cfg.NativeUpdateHistories = map[string][]uint32{}
 		log.Info("NativeActivations are not set, using default values")
@@ -294,9 +339,11 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 		unsubCh:     make(chan interface{}),
 		contracts:   *native.NewContracts(cfg),
 	}
+	bc.hardforks.Store(cfg.Hardforks)
 
+	bc.memPool.SetReplaceByFee(cfg.MemPoolReplaceByFee)
 	bc.stateRoot = stateroot.NewModule(bc.GetConfig(), bc.VerifyWitness, bc.log, bc.dao.Store)
-	bc.contracts.Designate.StateRootService = bc.stateRoot
+	bc.contracts.Designate.OnRoleChange = bc.onRoleChange
 
 	if err := bc.init(); err != nil {
 		return nil, err
@@ -316,12 +363,6 @@ func (bc *Blockchain) SetOracle(mod native.OracleService) {
 		}
 		mod.UpdateNativeContract(orc.NEF.Script, orc.GetOracleResponseScript(),
 			orc.Hash, md.MD.Offset)
-		keys, _, err := bc.contracts.Designate.GetDesignatedByRole(bc.dao, noderoles.Oracle, bc.BlockHeight())
-		if err != nil {
-			bc.log.Error("failed to get oracle key list")
-			return
-		}
-		mod.UpdateOracleNodes(keys)
 		reqs, err := bc.contracts.Oracle.GetRequests(bc.dao)
 		if err != nil {
 			bc.log.Error("failed to get current oracle request list")
@@ -330,21 +371,17 @@ func (bc *Blockchain) SetOracle(mod native.OracleService) {
 		mod.AddRequests(reqs)
 	}
 	orc.Module.Store(&mod)
-	bc.contracts.Designate.OracleService.Store(&mod)
 }
 
-// SetNotary sets notary module. It doesn't protected by mutex and
-// must be called before `bc.Run()` to avoid data race.
-func (bc *Blockchain) SetNotary(mod native.NotaryService) {
-	if mod != nil {
-		keys, _, err := bc.contracts.Designate.GetDesignatedByRole(bc.dao, noderoles.P2PNotary, bc.BlockHeight())
-		if err != nil {
-			bc.log.Error("failed to get notary key list")
-			return
-		}
-		mod.UpdateNotaryNodes(keys)
+// onRoleChange is called by the native Designation contract for every role
+// changed by the block being stored. State validators are updated
+// immediately, other parties get the event via SubscribeForRoleChanges after
+// the block is stored.
+func (bc *Blockchain) onRoleChange(ev *state.RoleChangeEvent) {
+	if ev.Role == noderoles.StateValidator {
+		bc.stateRoot.UpdateStateValidators(ev.Height, ev.Nodes.Copy())
 	}
-	bc.contracts.Designate.NotaryService.Store(&mod)
+	bc.roleChanges = append(bc.roleChanges, ev)
 }
 
 func (bc *Blockchain) init() error {
@@ -475,6 +512,9 @@ func (bc *Blockchain) init() error {
 	if err = bc.stateRoot.Init(bHeight); err != nil {
 		return fmt.Errorf("can't init MPT at height %d: %w", bHeight, err)
 	}
+	if err = bc.initPrunedHeight(); err != nil {
+		return fmt.Errorf("can't retrieve pruned state height: %w", err)
+	}
 
 	err = bc.initializeNativeCache(bc.blockHeight, bc.dao)
 	if err != nil {
@@ -689,6 +729,9 @@ func (bc *Blockchain) Run() {
 			if bc.config.RemoveUntraceableBlocks {
 				gcDur = bc.tryRunGC(oldPersisted)
 			}
+			if bc.config.StatePruning.Enabled {
+				gcDur += bc.tryRunPruning()
+			}
 			nextSync = dur > persistInterval*2
 			interval := persistInterval - dur - gcDur
 			if interval <= 0 {
@@ -729,6 +772,59 @@ func (bc *Blockchain) tryRunGC(oldHeight uint32) time.Duration {
 	return dur
 }
 
+// tryRunPruning starts a new state pruning cycle if it's time to or performs
+// the next step of the current one.
+func (bc *Blockchain) tryRunPruning() time.Duration {
+	bc.pruneLock.Lock()
+	defer bc.pruneLock.Unlock()
+
+	start := time.Now()
+	if bc.pruner == nil {
+		var (
+			cfg = bc.config.StatePruning
+			h   = bc.stateRoot.CurrentLocalHeight()
+		)
+		if h < bc.pruneStartedAt+cfg.Period || h < cfg.RetainRoots {
+			return 0
+		}
+		bc.pruneStartedAt = h
+		bc.setPrunedHeight(h - cfg.RetainRoots + 1)
+		bc.pruner = bc.stateRoot.NewPruner(h-cfg.RetainRoots+1, bc.store)
+	}
+	done, err := bc.pruner.Step(time.Duration(bc.config.StatePruning.MaxStepTime) * time.Millisecond)
+	if done || err != nil {
+		// Failed cycle is restarted after the next Period.
+		bc.pruner = nil
+	}
+	return time.Since(start)
+}
+
+// PruneStates removes MPT nodes that are not reachable from state roots
+// starting from the given height up to the current one. Block processing is
+// blocked until it's finished. It can be used irrespective of StatePruning
+// settings, but old states removed are no longer available (including for
+// proofs and historic invocations).
+func (bc *Blockchain) PruneStates(height uint32) error {
+	if bc.config.P2PStateExchangeExtensions {
+		return errors.New("state pruning is not supported with P2PStateExchangeExtensions")
+	}
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+	bc.pruneLock.Lock()
+	defer bc.pruneLock.Unlock()
+
+	h := bc.stateRoot.CurrentLocalHeight()
+	if height > h {
+		return fmt.Errorf("height %d is higher than the current state height %d", height, h)
+	}
+	// Current pruning cycle (if any) is superseded by this one.
+	bc.pruner = nil
+	bc.pruneStartedAt = h
+	bc.setPrunedHeight(height)
+	_, err := bc.stateRoot.NewPruner(height, bc.store).Step(0)
+	return err
+}
+
 func (bc *Blockchain) removeOldTransfers(index uint32) time.Duration {
 	bc.log.Info("starting transfer data garbage collection", zap.Uint32("index", index))
 	start := time.Now()
@@ -784,31 +880,77 @@ func (bc *Blockchain) removeOldTransfers(index uint32) time.Duration {
 	return dur
 }
 
+// nonBlockingSub wraps subscription channels that events are dropped for if
+// they're not ready to receive them.
+type nonBlockingSub struct {
+	ch interface{}
+}
+
 // notificationDispatcher manages subscription to events and broadcasts new events.
 func (bc *Blockchain) notificationDispatcher() {
 	var (
 		// These are just sets of subscribers, though modelled as maps
 		// for ease of management (not a lot of subscriptions is really
 		// expected, but maps are convenient for adding/deleting elements).
+		// Values denote whether sends to the channel are blocking.
 		blockFeed        = make(map[chan *block.Block]bool)
 		txFeed           = make(map[chan *transaction.Transaction]bool)
 		notificationFeed = make(map[chan *state.ContainedNotificationEvent]bool)
 		executionFeed    = make(map[chan *state.AppExecResult]bool)
+		roleChangeFeed   = make(map[chan *state.RoleChangeEvent]bool)
 	)
+	sendExecution := func(aer *state.AppExecResult) {
+		for ch, blocking := range executionFeed {
+			if blocking {
+				ch <- aer
+				continue
+			}
+			select {
+			case ch <- aer:
+			default:
+				atomic.AddUint64(&bc.droppedEvents, 1)
+			}
+		}
+	}
+	sendNotifications := func(aer *state.AppExecResult) {
+		for i := range aer.Events {
+			for ch, blocking := range notificationFeed {
+				ev := &state.ContainedNotificationEvent{
+					Container:         aer.Container,
+					NotificationEvent: aer.Events[i],
+				}
+				if blocking {
+					ch <- ev
+					continue
+				}
+				select {
+				case ch <- ev:
+				default:
+					atomic.AddUint64(&bc.droppedEvents, 1)
+				}
+			}
+		}
+	}
 	for {
 		select {
 		case <-bc.stopCh:
 			return
 		case sub := <-bc.subCh:
+			blocking := true
+			if nb, ok := sub.(nonBlockingSub); ok {
+				sub, blocking = nb.ch, false
+			}
 			switch ch := sub.(type) {
 			case chan *block.Block:
-				blockFeed[ch] = true
+				blockFeed[ch] = blocking
 			case chan *transaction.Transaction:
-				txFeed[ch] = true
+				txFeed[ch] = blocking
 			case chan *state.ContainedNotificationEvent:
-				notificationFeed[ch] = true
+				notificationFeed[ch] = blocking
 			case chan *state.AppExecResult:
-				executionFeed[ch] = true
+				executionFeed[ch] = blocking
+			case chan *state.RoleChangeEvent:
+				roleChangeFeed[ch] = blocking
 			default:
 				panic(fmt.Sprintf("bad subscription: %T", sub))
 			}
@@ -822,10 +964,19 @@ func (bc *Blockchain) notificationDispatcher() {
 				delete(notificationFeed, ch)
 			case chan *state.AppExecResult:
 				delete(executionFeed, ch)
+			case chan *state.RoleChangeEvent:
+				delete(roleChangeFeed, ch)
 			default:
 				panic(fmt.Sprintf("bad unsubscription: %T", unsub))
 			}
 		case event := <-bc.events:
+			// Role changes are sent before anything else, so that
+			// subscribers process them before the next block.
+			for _, rc := range event.roleChanges {
+				for ch := range roleChangeFeed {
+					ch <- rc
+				}
+			}
 			// We don't want to waste time looping through transactions when there are no
 			// subscribers.
 			if len(txFeed) != 0 || len(notificationFeed) != 0 || len(executionFeed) != 0 {
@@ -833,17 +984,8 @@ func (bc *Blockchain) notificationDispatcher() {
 				if !aer.Container.Equals(event.block.Hash()) {
 					panic("inconsistent application execution results")
 				}
-				for ch := range executionFeed {
-					ch <- aer
-				}
-				for i := range aer.Events {
-					for ch := range notificationFeed {
-						ch <- &state.ContainedNotificationEvent{
-							Container:         aer.Container,
-							NotificationEvent: aer.Events[i],
-						}
-					}
-				}
+				sendExecution(aer)
+				sendNotifications(aer)
 
 				aerIdx := 1
 				for _, tx := range event.block.Transactions {
@@ -852,21 +994,20 @@ func (bc *Blockchain) notificationDispatcher() {
 						panic("inconsistent application execution results")
 					}
 					aerIdx++
-					for ch := range executionFeed {
-						ch <- aer
-					}
+					sendExecution(aer)
 					if aer.VMState == vmstate.Halt {
-						for i := range aer.Events {
-							for ch := range notificationFeed {
-								ch <- &state.ContainedNotificationEvent{
-									Container:         aer.Container,
-									NotificationEvent: aer.Events[i],
-								}
-							}
-						}
+						sendNotifications(aer)
 					}
-					for ch := range txFeed {
-						ch <- tx
+					for ch, blocking := range txFeed {
+						if blocking {
+							ch <- tx
+							continue
+						}
+						select {
+						case ch <- tx:
+						default:
+							atomic.AddUint64(&bc.droppedEvents, 1)
+						}
 					}
 				}
 
@@ -874,21 +1015,20 @@ func (bc *Blockchain) notificationDispatcher() {
 				if !aer.Container.Equals(event.block.Hash()) {
 					panic("inconsistent application execution results")
 				}
-				for ch := range executionFeed {
-					ch <- aer
+				sendExecution(aer)
+				sendNotifications(aer)
+			}
+			for ch, blocking := range blockFeed {
+				if blocking {
+					ch <- event.block
+					continue
 				}
-				for i := range aer.Events {
-					for ch := range notificationFeed {
-						ch <- &state.ContainedNotificationEvent{
-							Container:         aer.Container,
-							NotificationEvent: aer.Events[i],
-						}
-					}
+				select {
+				case ch <- event.block:
+				default:
+					atomic.AddUint64(&bc.droppedEvents, 1)
 				}
 			}
-			for ch := range blockFeed {
-				ch <- event.block
-			}
 		}
 	}
 }
@@ -910,6 +1050,9 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 	bc.addLock.Lock()
 	defer bc.addLock.Unlock()
 
+	if m := bc.stateRoot.Halted(); m != nil {
+		return fmt.Errorf("%w at block %d, block processing is halted", stateroot.ErrStateMismatch, m.Height)
+	}
 	var mp *mempool.Pool
 	expectedHeight := bc.BlockHeight() + 1
 	if expectedHeight != block.Index {
@@ -932,7 +1075,8 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 			return errors.New("invalid block: MerkleRoot mismatch")
 		}
 		mp = mempool.New(len(block.Transactions), 0, false)
-		for _, tx := range block.Transactions {
+		witnesses := bc.preverifyTxWitnesses(block.Transactions)
+		for i, tx := range block.Transactions {
 			var err error
 			// Transactions are verified before adding them
 			// into the pool, so there is no point in doing
@@ -943,7 +1087,7 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 					continue
 				}
 			} else {
-				err = bc.verifyAndPoolTx(tx, mp, bc)
+				err = bc.verifyAndPoolTxInternal(tx, mp, bc, witnesses[i])
 			}
 			if err != nil && bc.config.VerifyTransactions {
 				return fmt.Errorf("transaction %s failed to verify: %w", tx.Hash().StringLE(), err)
@@ -1044,6 +1188,22 @@ func (bc *Blockchain) GetStateModule() StateRoot {
 	return bc.stateRoot
 }
 
+// GetMemPoolData returns serialized memory pool contents stored by the network
+// server, nil is returned if there are none.
+func (bc *Blockchain) GetMemPoolData() ([]byte, error) {
+	data, err := bc.dao.Store.Get([]byte{byte(storage.SYSMemPool)})
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// PutMemPoolData stores serialized memory pool contents, they're persisted
+// along with the chain data.
+func (bc *Blockchain) PutMemPoolData(data []byte) {
+	bc.dao.Store.Put([]byte{byte(storage.SYSMemPool)}, data)
+}
+
 // GetStateSyncModule returns new state sync service instance.
 func (bc *Blockchain) GetStateSyncModule() *statesync.Module {
 	return statesync.NewModule(bc, bc.stateRoot, bc.log, bc.dao, bc.jumpToState)
@@ -1060,6 +1220,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		aerchan        = make(chan *state.AppExecResult, len(block.Transactions)/8) // Tested 8 and 4 with no practical difference, but feel free to test more and tune.
 		aerdone        = make(chan error)
 	)
+	bc.roleChanges = nil // Leftovers from a failed block (if any).
 	go func() {
 		var (
 			kvcache      = aerCache
@@ -1264,11 +1425,13 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	bc.lock.Unlock()
 
 	updateBlockHeightMetric(block.Index)
+	roleChanges := bc.roleChanges
+	bc.roleChanges = nil
 	// Genesis block is stored when Blockchain is not yet running, so there
 	// is no one to read this event. And it doesn't make much sense as event
 	// anyway.
 	if block.Index != 0 {
-		bc.events <- bcEvent{block, appExecResults}
+		bc.events <- bcEvent{block, appExecResults, roleChanges}
 	}
 	return nil
 }
@@ -1578,6 +1741,12 @@ func (bc *Blockchain) GetNotaryServiceFeePerKey() int64 {
 	return bc.contracts.Notary.GetNotaryServiceFeePerKey(bc.dao)
 }
 
+// GetDesignatedByRole returns the list of nodes designated for the given role
+// at the given height along with the height this list was designated at.
+func (bc *Blockchain) GetDesignatedByRole(r noderoles.Role, index uint32) (keys.PublicKeys, uint32, error) {
+	return bc.contracts.Designate.GetDesignatedByRole(bc.dao, r, index)
+}
+
 // GetNotaryContractScriptHash returns Notary native contract hash.
 func (bc *Blockchain) GetNotaryContractScriptHash() util.Uint160 {
 	if bc.P2PSigExtensionsEnabled() {
@@ -1808,21 +1977,76 @@ func (bc *Blockchain) GetNatives() []state.NativeContract {
 
 // GetConfig returns the config stored in the blockchain.
 func (bc *Blockchain) GetConfig() config.ProtocolConfiguration {
-	return bc.config
+	cfg := bc.config
+	cfg.Hardforks = bc.hardforks.Load().(map[string]uint32)
+	return cfg
+}
+
+// SetHardforks overrides hard-fork activation heights of the running chain
+// with the given ones (in the same format as Hardforks protocol configuration
+// setting), it allows to test hard-fork dependent behaviour on private networks
+// without chain recreation. It's not allowed for MainNet and TestNet and the
+// override can't change the state of any hard-fork at the current or lower
+// heights. The override is not persisted, configured heights are used after
+// node restart.
+func (bc *Blockchain) SetHardforks(hfs map[string]uint32) error {
+	if bc.config.Magic == netmode.MainNet || bc.config.Magic == netmode.TestNet {
+		return errors.New("hard-forks can't be overridden for public networks")
+	}
+	if err := config.ValidateHardforks(hfs); err != nil {
+		return err
+	}
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+
+	var (
+		old    = bc.hardforks.Load().(map[string]uint32)
+		height = bc.BlockHeight()
+	)
+	for _, hf := range config.OrderedHardforks() {
+		// Hard-fork state can only change at heights higher than the current one.
+		oldH, newH := hardforkHeight(old, hf), hardforkHeight(hfs, hf)
+		if oldH != newH && (oldH <= height || newH <= height) {
+			return fmt.Errorf("%s state at the current height %d can't be changed", hf, height)
+		}
+	}
+	res := make(map[string]uint32, len(hfs))
+	for name, h := range hfs {
+		res[name] = h
+	}
+	bc.hardforks.Store(res)
+	bc.log.Info("hard-fork heights are overridden", zap.Any("Hardforks", res))
+	return nil
+}
+
+// hardforkHeight returns the height the hard-fork is enabled at with the
+// given Hardforks configuration, math.MaxUint32 is returned if it's not enabled.
+func hardforkHeight(hfs map[string]uint32, hf config.Hardfork) uint32 {
+	h, ok := hfs[hf.String()]
+	if ok {
+		return h
+	}
+	if len(hfs) == 0 {
+		return 0 // Each hard-fork is enabled by default.
+	}
+	return math.MaxUint32
 }
 
 // SubscribeForBlocks adds given channel to new block event broadcasting, so when
 // there is a new block added to the chain you'll receive it via this channel.
-// Make sure it's read from regularly as not reading these events might affect
-// other Blockchain functions.
+// Sends to this channel are blocking, so make sure it's read from regularly as
+// not reading these events might affect other Blockchain functions (use
+// SubscribeForBlocksNonBlocking if missing some events is acceptable).
 func (bc *Blockchain) SubscribeForBlocks(ch chan *block.Block) {
 	bc.subCh <- ch
 }
 
 // SubscribeForTransactions adds given channel to new transaction event
 // broadcasting, so when there is a new transaction added to the chain (in a
-// block) you'll receive it via this channel. Make sure it's read from regularly
-// as not reading these events might affect other Blockchain functions.
+// block) you'll receive it via this channel. Sends to this channel are
+// blocking, so make sure it's read from regularly as not reading these events
+// might affect other Blockchain functions (use
+// SubscribeForTransactionsNonBlocking if missing some events is acceptable).
 func (bc *Blockchain) SubscribeForTransactions(ch chan *transaction.Transaction) {
 	bc.subCh <- ch
 }
@@ -1831,21 +2055,69 @@ func (bc *Blockchain) SubscribeForTransactions(ch chan *transaction.Transaction)
 // broadcasting, so when an in-block transaction execution generates a
 // notification you'll receive it via this channel. Only notifications from
 // successful transactions are broadcasted, if you're interested in failed
-// transactions use SubscribeForExecutions instead. Make sure this channel is
-// read from regularly as not reading these events might affect other Blockchain
-// functions.
+// transactions use SubscribeForExecutions instead. Sends to this channel are
+// blocking, so make sure it's read from regularly as not reading these events
+// might affect other Blockchain functions (use
+// SubscribeForNotificationsNonBlocking if missing some events is acceptable).
 func (bc *Blockchain) SubscribeForNotifications(ch chan *state.ContainedNotificationEvent) {
 	bc.subCh <- ch
 }
 
 // SubscribeForExecutions adds given channel to new transaction execution event
 // broadcasting, so when an in-block transaction execution happens you'll receive
-// the result of it via this channel. Make sure it's read from regularly as not
-// reading these events might affect other Blockchain functions.
+// the result of it via this channel. Sends to this channel are blocking, so
+// make sure it's read from regularly as not reading these events might affect
+// other Blockchain functions (use SubscribeForExecutionsNonBlocking if missing
+// some events is acceptable).
 func (bc *Blockchain) SubscribeForExecutions(ch chan *state.AppExecResult) {
 	bc.subCh <- ch
 }
 
+// SubscribeForBlocksNonBlocking is similar to SubscribeForBlocks, but events
+// are dropped if the channel is not ready to receive them (it has no free
+// buffer space and no reader waiting), so slow readers can't affect Blockchain.
+// Dropped events are counted, see DroppedEvents.
+func (bc *Blockchain) SubscribeForBlocksNonBlocking(ch chan *block.Block) {
+	bc.subCh <- nonBlockingSub{ch}
+}
+
+// SubscribeForTransactionsNonBlocking is similar to SubscribeForTransactions,
+// but events are dropped if the channel is not ready to receive them, see
+// SubscribeForBlocksNonBlocking.
+func (bc *Blockchain) SubscribeForTransactionsNonBlocking(ch chan *transaction.Transaction) {
+	bc.subCh <- nonBlockingSub{ch}
+}
+
+// SubscribeForNotificationsNonBlocking is similar to SubscribeForNotifications,
+// but events are dropped if the channel is not ready to receive them, see
+// SubscribeForBlocksNonBlocking.
+func (bc *Blockchain) SubscribeForNotificationsNonBlocking(ch chan *state.ContainedNotificationEvent) {
+	bc.subCh <- nonBlockingSub{ch}
+}
+
+// SubscribeForExecutionsNonBlocking is similar to SubscribeForExecutions, but
+// events are dropped if the channel is not ready to receive them, see
+// SubscribeForBlocksNonBlocking.
+func (bc *Blockchain) SubscribeForExecutionsNonBlocking(ch chan *state.AppExecResult) {
+	bc.subCh <- nonBlockingSub{ch}
+}
+
+// SubscribeForRoleChanges adds given channel to role change event
+// broadcasting, so that when nodes designated for some role are changed by the
+// RoleManagement native contract, the new list is sent to this channel. Events
+// are sent after the block containing the designation is stored, but before
+// this block is broadcasted to block subscribers. Sends to this channel are
+// blocking, so make sure the channel is read from.
+func (bc *Blockchain) SubscribeForRoleChanges(ch chan *state.RoleChangeEvent) {
+	bc.subCh <- ch
+}
+
+// DroppedEvents returns the total number of events dropped for non-blocking
+// subscribers since the Blockchain start.
+func (bc *Blockchain) DroppedEvents() uint64 {
+	return atomic.LoadUint64(&bc.droppedEvents)
+}
+
 // UnsubscribeFromBlocks unsubscribes given channel from new block notifications,
 // you can close it afterwards. Passing non-subscribed channel is a no-op, but
 // the method can read from this channel (discarding any read data).
@@ -1903,12 +2175,34 @@ unsubloop:
 	}
 }
 
+// UnsubscribeFromRoleChanges unsubscribes given channel from role change
+// notifications, you can close it afterwards. Passing non-subscribed channel
+// is a no-op, but the method can read from this channel (discarding any read
+// data).
+func (bc *Blockchain) UnsubscribeFromRoleChanges(ch chan *state.RoleChangeEvent) {
+unsubloop:
+	for {
+		select {
+		case <-ch:
+		case bc.unsubCh <- ch:
+			break unsubloop
+		}
+	}
+}
+
 // CalculateClaimable calculates the amount of GAS generated by owning specified
 // amount of NEO between specified blocks.
 func (bc *Blockchain) CalculateClaimable(acc util.Uint160, endHeight uint32) (*big.Int, error) {
 	return bc.contracts.NEO.CalculateBonus(bc.dao, acc, endHeight)
 }
 
+// CalculateClaimableDetails is similar to CalculateClaimable, but it also
+// returns a breakdown of the amount into the NEO holder reward for every range
+// of blocks with the same GAS per block value and the voter reward.
+func (bc *Blockchain) CalculateClaimableDetails(acc util.Uint160, endHeight uint32) (*state.GASBonus, error) {
+	return bc.contracts.NEO.CalculateBonusDetails(bc.dao, acc, endHeight)
+}
+
 // FeePerByte returns transaction network fee per byte.
 func (bc *Blockchain) FeePerByte() int64 {
 	return bc.contracts.Policy.GetFeePerByteInternal(bc.dao)
@@ -2001,6 +2295,13 @@ var (
 // verifyAndPoolTx verifies whether a transaction is bonafide or not and tries
 // to add it to the mempool given.
 func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.Pool, feer mempool.Feer, data ...interface{}) error {
+	return bc.verifyAndPoolTxInternal(t, pool, feer, nil, data...)
+}
+
+// verifyAndPoolTxInternal is an internal implementation of verifyAndPoolTx
+// that can use the result of witnesses verification performed in advance
+// (witnesses are verified if it's nil).
+func (bc *Blockchain) verifyAndPoolTxInternal(t *transaction.Transaction, pool *mempool.Pool, feer mempool.Feer, witnesses *witnessesResult, data ...interface{}) error {
 	// This code can technically be moved out of here, because it doesn't
 	// really require a chain lock.
 	err := vm.IsScriptCorrect(t.Script, nil)
@@ -2045,7 +2346,11 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 			return err
 		}
 	}
-	err = bc.verifyTxWitnesses(t, nil, isPartialTx)
+	if witnesses != nil {
+		err = witnesses.err
+	} else {
+		err = bc.verifyTxWitnesses(t, nil, isPartialTx)
+	}
 	if err != nil {
 		return err
 	}
@@ -2063,7 +2368,7 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 			return ErrInsufficientFunds
 		case errors.Is(err, mempool.ErrOOM):
 			return ErrOOM
-		case errors.Is(err, mempool.ErrConflictsAttribute):
+		case errors.Is(err, mempool.ErrConflictsAttribute), errors.Is(err, mempool.ErrReplacement):
 			return fmt.Errorf("mempool: %w: %s", ErrHasConflicts, err)
 		default:
 			return err
@@ -2074,6 +2379,7 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 }
 
 func (bc *Blockchain) verifyTxAttributes(d *dao.Simple, tx *transaction.Transaction, isPartialTx bool) error {
+	var conflicts []util.Uint256
 	for i := range tx.Attributes {
 		switch attrType := tx.Attributes[i].Type; attrType {
 		case transaction.HighPriority:
@@ -2132,10 +2438,7 @@ func (bc *Blockchain) verifyTxAttributes(d *dao.Simple, tx *transaction.Transact
 			if !bc.config.P2PSigExtensions {
 				return fmt.Errorf("%w: Conflicts attribute was found, but P2PSigExtensions are disabled", ErrInvalidAttribute)
 			}
-			conflicts := tx.Attributes[i].Value.(*transaction.Conflicts)
-			if err := bc.dao.HasTransaction(conflicts.Hash); errors.Is(err, dao.ErrAlreadyExists) {
-				return fmt.Errorf("%w: conflicting transaction %s is already on chain", ErrInvalidAttribute, conflicts.Hash.StringLE())
-			}
+			conflicts = append(conflicts, tx.Attributes[i].Value.(*transaction.Conflicts).Hash)
 		case transaction.NotaryAssistedT:
 			if !bc.config.P2PSigExtensions {
 				return fmt.Errorf("%w: NotaryAssisted attribute was found, but P2PSigExtensions are disabled", ErrInvalidAttribute)
@@ -2149,6 +2452,13 @@ func (bc *Blockchain) verifyTxAttributes(d *dao.Simple, tx *transaction.Transact
 			}
 		}
 	}
+	if len(conflicts) != 0 {
+		for i, err := range bc.dao.HasTransactions(conflicts) {
+			if errors.Is(err, dao.ErrAlreadyExists) {
+				return fmt.Errorf("%w: conflicting transaction %s is already on chain", ErrInvalidAttribute, conflicts[i].StringLE())
+			}
+		}
+	}
 	return nil
 }
 
@@ -2265,26 +2575,56 @@ func (bc *Blockchain) GetTestVM(t trigger.Type, tx *transaction.Transaction, b *
 	return systemInterop, nil
 }
 
+// GetTestVMSnapshot returns an interop context with VM set up for a test run
+// over a consistent snapshot of the latest chain state. Unlike GetTestVM, the
+// state it works with (including iterators created by the invoked script) is
+// not affected by new blocks until the context is finalized, which releases
+// the snapshot. storage.ErrSnapshotsNotSupported is returned if the DB can't
+// provide snapshots.
+func (bc *Blockchain) GetTestVMSnapshot(t trigger.Type, tx *transaction.Transaction) (*interop.Context, error) {
+	d, release, err := bc.dao.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	h, err := d.GetCurrentBlockHeight()
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to get snapshot height: %w", err)
+	}
+	b, err := bc.getFakeNextBlock(h + 1)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to create fake block for height %d: %w", h+1, err)
+	}
+	systemInterop := bc.newInteropContext(t, d, b, tx)
+	systemInterop.RegisterCancelFunc(release)
+	_ = systemInterop.SpawnVM() // All the other code suppose that the VM is ready.
+	return systemInterop, nil
+}
+
 // GetTestHistoricVM returns an interop context with VM set up for a test run.
+// *StateUnavailableError is returned if the state for the requested height is
+// not available (see GetStateArchive).
 func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, nextBlockHeight uint32) (*interop.Context, error) {
-	if bc.config.KeepOnlyLatestState {
+	archive, ok := bc.GetStateArchive()
+	if !ok {
 		return nil, errors.New("only latest state is supported")
 	}
+	if nextBlockHeight < 1 {
+		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d", nextBlockHeight)
+	}
+	// Assuming that block N-th is processing during historic call, the historic invocation should be based on the storage state of height N-1.
+	if !archive.Contains(nextBlockHeight - 1) {
+		return nil, &StateUnavailableError{Height: nextBlockHeight - 1, Nearest: archive.Nearest(nextBlockHeight - 1)}
+	}
 	b, err := bc.getFakeNextBlock(nextBlockHeight)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create fake block for height %d: %w", nextBlockHeight, err)
 	}
 	var mode = mpt.ModeAll
 	if bc.config.RemoveUntraceableBlocks {
-		if b.Index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
-			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", b.Index)
-		}
 		mode |= mpt.ModeGCFlag
 	}
-	if b.Index < 1 || b.Index > bc.BlockHeight()+1 {
-		return nil, fmt.Errorf("unsupported historic chain's height: requested state for %d, chain height %d", b.Index, bc.blockHeight)
-	}
-	// Assuming that block N-th is processing during historic call, the historic invocation should be based on the storage state of height N-1.
 	sr, err := bc.stateRoot.GetStateRoot(b.Index - 1)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", b.Index, err)
@@ -2485,17 +2825,28 @@ func (bc *Blockchain) newInteropContext(trigger trigger.Type, d *dao.Simple, blo
 		baseStorageFee = bc.contracts.Policy.GetStoragePriceInternal(d)
 	}
 	ic := interop.NewContext(trigger, bc, d, baseExecFee, baseStorageFee, bc.contracts.Management.GetContract, bc.contracts.Contracts, contract.LoadToken, block, tx, bc.log)
-	ic.Functions = systemInterops
+	ic.Functions = interopsFor(bc.config)
 	switch {
 	case tx != nil:
 		ic.Container = tx
 	case block != nil:
 		ic.Container = block
 	}
+	if h, ok := bc.onExecHook.Load().(vm.OnExecHook); ok {
+		ic.SetOnExecHook(h)
+	}
 	ic.InitNonceData()
 	return ic
 }
 
+// SetOnExecHook sets the hook to be called before every instruction executed
+// by the chain (including test invocations and verification scripts), nil
+// disables it. It's intended for testing (contract code coverage collection),
+// hook slows execution down.
+func (bc *Blockchain) SetOnExecHook(h vm.OnExecHook) {
+	bc.onExecHook.Store(h)
+}
+
 // P2PSigExtensionsEnabled defines whether P2P signature extensions are enabled.
 func (bc *Blockchain) P2PSigExtensionsEnabled() bool {
 	return bc.config.P2PSigExtensions
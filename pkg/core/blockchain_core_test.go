@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -11,13 +12,19 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/testchain"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -340,3 +347,197 @@ func TestBlockchain_BaseExecFeeBaseStoragePrice_Compat(t *testing.T) {
 		check(t)
 	})
 }
+
+func TestBlockchain_GetTestVMSnapshot(t *testing.T) {
+	getIndex := func(t *testing.T, ic *interop.Context) int64 {
+		ic.VM.LoadScriptWithFlags(ic.Tx.Script, callflag.All)
+		require.NoError(t, ic.VM.Run())
+		return ic.VM.Estack().Pop().BigInt().Int64()
+	}
+	check := func(t *testing.T, st storage.Store) {
+		bc := newTestChainWithCustomCfgAndStore(t, st, nil)
+		_, err := bc.genBlocks(2)
+		require.NoError(t, err)
+		w := io.NewBufBinWriter()
+		emit.AppCall(w.BinWriter, bc.contracts.Ledger.Hash, "currentIndex", callflag.ReadStates)
+		require.NoError(t, w.Err)
+		tx := transaction.New(w.Bytes(), 0)
+
+		ic, err := bc.GetTestVMSnapshot(trigger.Application, tx)
+		require.NoError(t, err)
+		require.Equal(t, uint32(3), ic.Block.Index)
+
+		// New blocks are persisted while the snapshot is in use.
+		_, err = bc.genBlocks(3)
+		require.NoError(t, err)
+		_, err = bc.persist(false)
+		require.NoError(t, err)
+
+		require.EqualValues(t, 2, getIndex(t, ic))
+		ic.Finalize()
+
+		ic, err = bc.GetTestVM(trigger.Application, tx, nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, getIndex(t, ic))
+		ic.Finalize()
+	}
+	t.Run("MemoryStore", func(t *testing.T) {
+		check(t, storage.NewMemoryStore())
+	})
+	t.Run("LevelDBStore", func(t *testing.T) {
+		st, err := storage.NewLevelDBStore(dbconfig.LevelDBOptions{DataDirectoryPath: t.TempDir()})
+		require.NoError(t, err)
+		check(t, st)
+	})
+	t.Run("BoltDBStore", func(t *testing.T) {
+		st, err := storage.NewBoltDBStore(dbconfig.BoltDBOptions{FilePath: filepath.Join(t.TempDir(), "bolt")})
+		require.NoError(t, err)
+		bc := newTestChainWithCustomCfgAndStore(t, st, nil)
+		_, err = bc.GetTestVMSnapshot(trigger.Application, nil)
+		require.ErrorIs(t, err, storage.ErrSnapshotsNotSupported)
+	})
+}
+
+func TestBlockchain_StateArchive(t *testing.T) {
+	checkUnavailable := func(t *testing.T, bc *Blockchain, h uint32, nearest uint32) {
+		var stErr *StateUnavailableError
+		_, err := bc.GetTestHistoricVM(trigger.Application, nil, h+1)
+		require.ErrorAs(t, err, &stErr)
+		require.Equal(t, h, stErr.Height)
+		require.Equal(t, nearest, stErr.Nearest)
+	}
+
+	t.Run("pruning", func(t *testing.T) {
+		bc := newTestChain(t)
+		_, err := bc.genBlocks(10)
+		require.NoError(t, err)
+
+		a, ok := bc.GetStateArchive()
+		require.True(t, ok)
+		require.Equal(t, StateArchive{Start: 0, End: 10}, a)
+		checkUnavailable(t, bc, 11, 10)
+		ic, err := bc.GetTestHistoricVM(trigger.Application, nil, 1)
+		require.NoError(t, err)
+		ic.Finalize()
+
+		require.NoError(t, bc.PruneStates(7))
+		a, ok = bc.GetStateArchive()
+		require.True(t, ok)
+		require.Equal(t, StateArchive{Start: 7, End: 10}, a)
+		checkUnavailable(t, bc, 6, 7)
+		ic, err = bc.GetTestHistoricVM(trigger.Application, nil, 8)
+		require.NoError(t, err)
+		ic.Finalize()
+
+		// Lower height doesn't make pruned states available.
+		require.NoError(t, bc.PruneStates(5))
+		a, _ = bc.GetStateArchive()
+		require.Equal(t, uint32(7), a.Start)
+
+		// Pruned height is restored from the storage.
+		bc.prunedHeight = 0
+		require.NoError(t, bc.initPrunedHeight())
+		a, _ = bc.GetStateArchive()
+		require.Equal(t, StateArchive{Start: 7, End: 10}, a)
+	})
+	t.Run("untraceable blocks", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+			c.ProtocolConfiguration.RemoveUntraceableBlocks = true
+			c.ProtocolConfiguration.MaxTraceableBlocks = 5
+		})
+		_, err := bc.genBlocks(3)
+		require.NoError(t, err)
+		a, ok := bc.GetStateArchive()
+		require.True(t, ok)
+		require.Equal(t, StateArchive{Start: 0, End: 3}, a)
+
+		_, err = bc.genBlocks(7)
+		require.NoError(t, err)
+		a, ok = bc.GetStateArchive()
+		require.True(t, ok)
+		require.Equal(t, StateArchive{Start: 4, End: 10}, a)
+		checkUnavailable(t, bc, 3, 4)
+	})
+	t.Run("latest state only", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+			c.ProtocolConfiguration.KeepOnlyLatestState = true
+		})
+		_, ok := bc.GetStateArchive()
+		require.False(t, ok)
+		_, err := bc.GetTestHistoricVM(trigger.Application, nil, 1)
+		require.Error(t, err)
+	})
+}
+
+func TestBlockchain_PruneStates(t *testing.T) {
+	// checkStates checks that all states are available for the given height
+	// and that their proofs are valid.
+	checkStates := func(t *testing.T, bc *Blockchain, h uint32) {
+		sr, err := bc.stateRoot.GetStateRoot(h)
+		require.NoError(t, err)
+		kvs, err := bc.stateRoot.FindStates(sr.Root, nil, nil, 1000)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(kvs))
+		for _, kv := range kvs {
+			proof, err := bc.stateRoot.GetStateProof(sr.Root, kv.Key)
+			require.NoError(t, err)
+			v, ok := mpt.VerifyProof(sr.Root, kv.Key, proof)
+			require.True(t, ok, "height %d", h)
+			require.Equal(t, kv.Value, v)
+		}
+	}
+	checkPruned := func(t *testing.T, bc *Blockchain, h uint32) {
+		sr, err := bc.stateRoot.GetStateRoot(h)
+		require.NoError(t, err)
+		_, err = bc.stateRoot.FindStates(sr.Root, nil, nil, 1000)
+		require.Error(t, err)
+	}
+
+	t.Run("manual", func(t *testing.T) {
+		bc := newTestChain(t)
+		_, err := bc.genBlocks(10)
+		require.NoError(t, err)
+		_, err = bc.persist(false)
+		require.NoError(t, err)
+
+		require.Error(t, bc.PruneStates(11))
+		require.NoError(t, bc.PruneStates(7))
+		for h := uint32(7); h <= 10; h++ {
+			checkStates(t, bc, h)
+		}
+		checkPruned(t, bc, 6)
+
+		// The chain is still operational.
+		_, err = bc.genBlocks(2)
+		require.NoError(t, err)
+		checkStates(t, bc, 12)
+	})
+	t.Run("background", func(t *testing.T) {
+		bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+			c.ProtocolConfiguration.StatePruning = config.StatePruning{
+				Enabled:     true,
+				RetainRoots: 3,
+				Period:      5,
+			}
+		})
+		_, err := bc.genBlocks(10)
+		require.NoError(t, err)
+
+		// Pruning is performed by the Run loop after persist.
+		require.Eventually(t, func() bool {
+			bc.pruneLock.Lock()
+			defer bc.pruneLock.Unlock()
+			return bc.pruneStartedAt == 10 && bc.pruner == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		for h := uint32(8); h <= 10; h++ {
+			checkStates(t, bc, h)
+		}
+		checkPruned(t, bc, 7)
+
+		// Not the time for the next cycle yet.
+		_, err = bc.genBlocks(2)
+		require.NoError(t, err)
+		require.Zero(t, bc.tryRunPruning())
+		checkStates(t, bc, 8)
+	})
+}
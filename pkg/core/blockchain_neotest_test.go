@@ -1,11 +1,13 @@
 package core_test
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"math/big"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -33,6 +35,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
@@ -439,6 +442,155 @@ func TestBlockchain_AddBadBlock(t *testing.T) {
 	})
 }
 
+func TestBlockchain_AddBlockParallelWitnesses(t *testing.T) {
+	// Witnesses are verified in parallel irrespective of the number of CPUs.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+
+	// Non-standard verification scripts are verified serially.
+	var (
+		goodScript = []byte{byte(opcode.PUSHT)}
+		badScript  = []byte{byte(opcode.PUSHF)}
+		goodHash   = hash.Hash160(goodScript)
+		badHash    = hash.Hash160(badScript)
+	)
+	e.ValidatorInvoker(gasHash).Invoke(t, true, "transfer", acc.ScriptHash(), goodHash, 10_0000_0000, nil)
+	e.ValidatorInvoker(gasHash).Invoke(t, true, "transfer", acc.ScriptHash(), badHash, 10_0000_0000, nil)
+
+	newStandardTx := func(t *testing.T) *transaction.Transaction {
+		return e.NewTx(t, []neotest.Signer{acc}, gasHash, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	}
+	newCustomTx := func(t *testing.T, script []byte) *transaction.Transaction {
+		h := hash.Hash160(script)
+		tx := e.NewUnsignedTx(t, gasHash, "transfer", h, util.Uint160{1, 2, 3}, 1, nil)
+		tx.Signers = []transaction.Signer{{Account: h, Scopes: transaction.CalledByEntry}}
+		tx.Scripts = []transaction.Witness{{InvocationScript: []byte{}, VerificationScript: script}}
+		tx.NetworkFee = 1000_0000
+		neotest.AddSystemFee(bc, tx, -1)
+		return tx
+	}
+	newBlock := func(t *testing.T, txs []*transaction.Transaction) *block.Block {
+		b := e.NewUnsignedBlock(t, txs...)
+		e.SignBlock(b)
+		return b
+	}
+
+	t.Run("good", func(t *testing.T) {
+		txs := make([]*transaction.Transaction, 20)
+		for i := range txs {
+			if i%5 == 3 {
+				txs[i] = newCustomTx(t, goodScript)
+			} else {
+				txs[i] = newStandardTx(t)
+			}
+		}
+		require.NoError(t, bc.AddBlock(newBlock(t, txs)))
+		for _, tx := range txs {
+			e.CheckHalt(t, tx.Hash())
+		}
+	})
+	t.Run("first failing is reported", func(t *testing.T) {
+		txs := make([]*transaction.Transaction, 20)
+		for i := range txs {
+			txs[i] = newStandardTx(t)
+		}
+		for _, i := range []int{7, 12, 19} {
+			txs[i].Scripts[0].InvocationScript[10] ^= 0xff
+		}
+		b := newBlock(t, txs)
+		for i := 0; i < 10; i++ {
+			err := bc.AddBlock(b)
+			require.ErrorIs(t, err, core.ErrInvalidSignature)
+			require.True(t, strings.Contains(err.Error(), txs[7].Hash().StringLE()), err)
+		}
+
+		// Serially verified transaction goes first.
+		txs[5] = newCustomTx(t, badScript)
+		b = newBlock(t, txs)
+		err := bc.AddBlock(b)
+		require.ErrorIs(t, err, core.ErrInvalidSignature)
+		require.True(t, strings.Contains(err.Error(), txs[5].Hash().StringLE()), err)
+	})
+}
+
+func TestBlockchain_GetStateDiff(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	policyID := e.NativeID(t, nativenames.Policy)
+	gasID := e.NativeID(t, nativenames.Gas)
+	collect := func(t *testing.T, from, to uint32, id *int32) []core.StorageDiff {
+		var res []core.StorageDiff
+		require.NoError(t, bc.GetStateDiff(from, to, id, func(d *core.StorageDiff) bool {
+			res = append(res, *d)
+			return true
+		}))
+		return res
+	}
+
+	from := bc.BlockHeight()
+	policy := e.CommitteeInvoker(e.NativeHash(t, nativenames.Policy))
+	policy.Invoke(t, stackitem.Null{}, "setFeePerByte", 1234)
+	receiver := random.Uint160()
+	gas := e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+	gas.Invoke(t, true, "transfer", e.Committee.ScriptHash(), receiver, 1_0000_0000, nil)
+	to := bc.BlockHeight()
+
+	t.Run("contract", func(t *testing.T) {
+		diff := collect(t, from, to, &policyID)
+		require.Equal(t, 1, len(diff))
+		require.Equal(t, policyID, diff[0].ID)
+		require.Equal(t, []byte{10}, diff[0].Key) // Fee per byte.
+		require.Equal(t, big.NewInt(1234), bigint.FromBytes(diff[0].New))
+		require.Equal(t, big.NewInt(1000), bigint.FromBytes(diff[0].Old))
+
+		reverse := collect(t, to, from, &policyID)
+		require.Equal(t, 1, len(reverse))
+		require.Equal(t, diff[0].Old, reverse[0].New)
+		require.Equal(t, diff[0].New, reverse[0].Old)
+
+		require.Equal(t, 0, len(collect(t, from, from, &policyID)))
+		require.Equal(t, 0, len(collect(t, to, to, nil)))
+	})
+	t.Run("all", func(t *testing.T) {
+		diff := collect(t, from, to, nil)
+		var policyChanged, receiverAdded bool
+		for _, d := range diff {
+			switch {
+			case d.ID == policyID:
+				policyChanged = true
+			case d.ID == gasID && bytes.Equal(d.Key, append([]byte{20}, receiver.BytesBE()...)):
+				require.Nil(t, d.Old)
+				require.NotNil(t, d.New)
+				receiverAdded = true
+			}
+		}
+		require.True(t, policyChanged)
+		require.True(t, receiverAdded)
+
+		var n int
+		require.NoError(t, bc.GetStateDiff(from, to, nil, func(*core.StorageDiff) bool {
+			n++
+			return false
+		}))
+		require.Equal(t, 1, n)
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		var stErr *core.StateUnavailableError
+		err := bc.GetStateDiff(from, to+1, nil, func(*core.StorageDiff) bool { return true })
+		require.ErrorAs(t, err, &stErr)
+		require.Equal(t, to+1, stErr.Height)
+		require.Equal(t, to, stErr.Nearest)
+
+		bcLatest, _ := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.KeepOnlyLatestState = true
+		})
+		require.Error(t, bcLatest.GetStateDiff(0, 0, nil, func(*core.StorageDiff) bool { return true }))
+	})
+}
+
 func TestBlockchain_GetHeader(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 	e := neotest.NewExecutor(t, bc, acc, acc)
@@ -866,6 +1018,63 @@ func TestBlockchain_Subscriptions(t *testing.T) {
 	e.GenerateNewBlocks(t, 2*chBufSize)
 }
 
+func TestBlockchain_SubscriptionsNonBlocking(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	// blockCh has room for a single block and nobody reads from other
+	// (unbuffered) channels, so all events for them are dropped.
+	blockCh := make(chan *block.Block, 1)
+	droppedBlockCh := make(chan *block.Block)
+	notificationCh := make(chan *state.ContainedNotificationEvent)
+	executionCh := make(chan *state.AppExecResult)
+	bc.SubscribeForBlocksNonBlocking(blockCh)
+	bc.SubscribeForBlocksNonBlocking(droppedBlockCh)
+	bc.SubscribeForNotificationsNonBlocking(notificationCh)
+	bc.SubscribeForExecutionsNonBlocking(executionCh)
+	require.Zero(t, bc.DroppedEvents())
+
+	b := e.AddNewBlock(t)
+	require.Eventually(t, func() bool { return len(blockCh) != 0 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, b, <-blockCh)
+	// Block, one notification (validator bounty) and two executions.
+	require.Eventually(t, func() bool { return bc.DroppedEvents() == 4 }, time.Second, 10*time.Millisecond)
+
+	// Buffer overflow doesn't block block processing.
+	e.AddNewBlock(t)
+	e.AddNewBlock(t)
+	require.Eventually(t, func() bool { return bc.DroppedEvents() == 4+2*4+1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, 1, len(blockCh))
+
+	bc.UnsubscribeFromBlocks(blockCh)
+	bc.UnsubscribeFromBlocks(droppedBlockCh)
+	bc.UnsubscribeFromNotifications(notificationCh)
+	bc.UnsubscribeFromExecutions(executionCh)
+	e.AddNewBlock(t)
+	require.Equal(t, uint64(4+2*4+1), bc.DroppedEvents())
+}
+
+func TestBlockchain_SetOnExecHook(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	script := []byte{byte(opcode.PUSH1), byte(opcode.DROP), byte(opcode.RET)}
+	scriptHash := hash.Hash160(script)
+	var offsets []int
+	bc.SetOnExecHook(func(h util.Uint160, offset int, _ opcode.Opcode) {
+		if h == scriptHash {
+			offsets = append(offsets, offset)
+		}
+	})
+	// The script is executed twice: to calculate system fee and in block.
+	e.InvokeScriptCheckHALT(t, script, []neotest.Signer{acc})
+	require.Equal(t, []int{0, 1, 2, 0, 1, 2}, offsets)
+
+	bc.SetOnExecHook(nil)
+	e.InvokeScriptCheckHALT(t, script, []neotest.Signer{acc})
+	require.Equal(t, 6, len(offsets))
+}
+
 func TestBlockchain_RemoveUntraceable(t *testing.T) {
 	neoCommitteeKey := []byte{0xfb, 0xff, 0xff, 0xff, 0x0e}
 	check := func(t *testing.T, bc *core.Blockchain, tHash, bHash, sHash util.Uint256, errorExpected bool) {
@@ -1897,3 +2106,44 @@ func TestBlockchain_Bug1728(t *testing.T) {
 	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "TestContract"})
 	managementInvoker.DeployContract(t, c, nil)
 }
+
+func TestBlockchain_SetHardforks(t *testing.T) {
+	t.Run("public network", func(t *testing.T) {
+		bc, _ := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.Magic = netmode.MainNet
+		})
+		require.Error(t, bc.SetHardforks(map[string]uint32{config.HFAspidochelone.String(): 0}))
+	})
+
+	bc, acc := chain.NewSingleWithHardfork(t, config.HFBasilisk, 10)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	e.GenerateNewBlocks(t, 2)
+
+	w := io.NewBufBinWriter()
+	emit.String(w.BinWriter, config.HFBasilisk.String())
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeIsHardforkEnabled)
+	require.NoError(t, w.Err)
+	script := w.Bytes()
+
+	for name, hfs := range map[string]map[string]uint32{
+		"unknown hard-fork": {"Unknown": 5},
+		"invalid order":     {config.HFAspidochelone.String(): 5, config.HFBasilisk.String(): 4},
+		"active hard-fork":  {config.HFAspidochelone.String(): 3, config.HFBasilisk.String(): 10},
+		"enable at current": {config.HFAspidochelone.String(): 0, config.HFBasilisk.String(): 2},
+		"enable all":        {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, bc.SetHardforks(hfs))
+		})
+	}
+
+	hfs := map[string]uint32{config.HFAspidochelone.String(): 0, config.HFBasilisk.String(): 4}
+	require.NoError(t, bc.SetHardforks(hfs))
+	require.Equal(t, hfs, bc.GetConfig().Hardforks)
+
+	// Hard-fork is active for blocks following the one at the specified height.
+	e.InvokeScriptCheckHALT(t, script, []neotest.Signer{acc}, stackitem.NewBool(false))
+	e.InvokeScriptCheckHALT(t, script, []neotest.Signer{acc}, stackitem.NewBool(false))
+	require.Equal(t, uint32(4), bc.BlockHeight())
+	e.InvokeScriptCheckHALT(t, script, []neotest.Signer{acc}, stackitem.NewBool(true))
+}
@@ -0,0 +1,70 @@
+package chaindump
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// Format is a chain dump format. Both formats share the same framing (block
+// count followed by length-prefixed blocks), they only differ in how the
+// index of the first block is stored.
+type Format byte
+
+const (
+	// FormatNeoGo is the default neo-go format, the index of the first
+	// block is stored (before the count) for incremental dumps only and
+	// the reader has to know whether the dump is incremental.
+	FormatNeoGo Format = iota
+	// FormatACC is the neo-cli chain.acc format, dumps not starting from
+	// the genesis block have the index of the first block stored (before the
+	// count) and are named chain.<start>.acc.
+	FormatACC
+)
+
+// ParseFormat parses the format name ("neo-go" or "acc").
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "neo-go", "neogo":
+		return FormatNeoGo, nil
+	case "acc":
+		return FormatACC, nil
+	default:
+		return 0, fmt.Errorf("unknown dump format %q", s)
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (f Format) String() string {
+	if f == FormatACC {
+		return "acc"
+	}
+	return "neo-go"
+}
+
+// WriteHeader writes the dump header for count blocks starting from start. The
+// start index is only written for ACC dumps not starting from the genesis
+// block.
+func WriteHeader(w *io.BinWriter, f Format, start, count uint32) {
+	if f == FormatACC && start != 0 {
+		w.WriteU32LE(start)
+	}
+	w.WriteU32LE(count)
+}
+
+// ACCFileStart checks whether the given path has the neo-cli name of the dump
+// starting from some block (chain.<start>.acc) and returns this start index.
+func ACCFileStart(path string) (uint32, bool) {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, "chain.") || !strings.HasSuffix(name, ".acc") {
+		return 0, false
+	}
+	start, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, "chain."), ".acc"), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(start), true
+}
@@ -0,0 +1,53 @@
+package chaindump_test
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	for s, f := range map[string]chaindump.Format{
+		"":       chaindump.FormatNeoGo,
+		"neo-go": chaindump.FormatNeoGo,
+		"acc":    chaindump.FormatACC,
+		"ACC":    chaindump.FormatACC,
+	} {
+		actual, err := chaindump.ParseFormat(s)
+		require.NoError(t, err, s)
+		require.Equal(t, f, actual, s)
+	}
+	_, err := chaindump.ParseFormat("zip")
+	require.Error(t, err)
+}
+
+func TestWriteHeader(t *testing.T) {
+	check := func(t *testing.T, f chaindump.Format, start, count uint32, expected []byte) {
+		w := io.NewBufBinWriter()
+		chaindump.WriteHeader(w.BinWriter, f, start, count)
+		require.NoError(t, w.Err)
+		require.Equal(t, expected, w.Bytes())
+	}
+	check(t, chaindump.FormatNeoGo, 0, 5, []byte{5, 0, 0, 0})
+	check(t, chaindump.FormatNeoGo, 7, 5, []byte{5, 0, 0, 0})
+	check(t, chaindump.FormatACC, 0, 5, []byte{5, 0, 0, 0})
+	check(t, chaindump.FormatACC, 7, 5, []byte{7, 0, 0, 0, 5, 0, 0, 0})
+}
+
+func TestACCFileStart(t *testing.T) {
+	for p, start := range map[string]uint32{
+		"chain.0.acc":           0,
+		"chain.123.acc":         123,
+		"/some/dir/chain.7.acc": 7,
+	} {
+		actual, ok := chaindump.ACCFileStart(p)
+		require.True(t, ok, p)
+		require.Equal(t, start, actual, p)
+	}
+	for _, p := range []string{"chain.acc", "chain.-1.acc", "chain.x.acc", "chain.1.acc.zip", "dump.1.acc", "chain.99999999999.acc"} {
+		_, ok := chaindump.ACCFileStart(p)
+		require.False(t, ok, p)
+	}
+}
@@ -115,6 +115,29 @@ func (dao *Simple) GetPrivate() *Simple {
 	return d
 }
 
+// GetSnapshot returns a new DAO instance backed by a consistent snapshot of
+// the current DAO state (both storage and native contract cache). It's not
+// affected by subsequent changes and Persist calls made to the original DAO.
+// The function returned must be called to release the snapshot once it's no
+// longer needed. storage.ErrSnapshotsNotSupported is returned if the underlying
+// DB can't provide snapshots.
+func (dao *Simple) GetSnapshot() (*Simple, func(), error) {
+	// Native cache and storage changes are persisted under this lock.
+	dao.nativeCacheLock.RLock()
+	defer dao.nativeCacheLock.RUnlock()
+
+	st, err := dao.Store.Snapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	d := newSimple(st, dao.Version.StateRootInHeader, dao.Version.P2PSigExtensions)
+	d.Version = dao.Version
+	for id, nativeCache := range dao.nativeCache {
+		d.nativeCache[id] = nativeCache
+	}
+	return d, func() { _ = st.Close() }, nil
+}
+
 // GetAndDecode performs get operation and decoding with serializable structures.
 func (dao *Simple) GetAndDecode(entity io.Serializable, key []byte) error {
 	entityBytes, err := dao.Store.Get(key)
@@ -704,7 +727,12 @@ func (dao *Simple) HasTransaction(hash util.Uint256) error {
 	if err != nil {
 		return nil
 	}
+	return executableTxStatus(bytes)
+}
 
+// executableTxStatus returns HasTransaction result for the given executable
+// record.
+func executableTxStatus(bytes []byte) error {
 	if len(bytes) < 6 {
 		return nil
 	}
@@ -714,6 +742,36 @@ func (dao *Simple) HasTransaction(hash util.Uint256) error {
 	return ErrAlreadyExists
 }
 
+// HasTransactions is a batched version of HasTransaction, it returns a slice
+// of HasTransaction results for the given hashes (in the same order). Keys are
+// looked up in the ascending order which is more friendly to the underlying
+// persistent store than random access.
+func (dao *Simple) HasTransactions(hashes []util.Uint256) []error {
+	var (
+		res   = make([]error, len(hashes))
+		order = make([]int, len(hashes))
+		key   = make([]byte, 1+util.Uint256Size)
+	)
+	// Insertion sort, batches are small (limited by the number of
+	// transaction attributes) and it doesn't allocate.
+	for i := range order {
+		j := i
+		for ; j > 0 && hashes[order[j-1]].CompareTo(hashes[i]) > 0; j-- {
+			order[j] = order[j-1]
+		}
+		order[j] = i
+	}
+	key[0] = byte(storage.DataExecutable)
+	for _, i := range order {
+		copy(key[1:], hashes[i].BytesBE())
+		bytes, err := dao.Store.Get(key)
+		if err == nil {
+			res[i] = executableTxStatus(bytes)
+		}
+	}
+	return res
+}
+
 // StoreAsBlock stores given block as DataBlock. It can reuse given buffer for
 // the purpose of value serialization.
 func (dao *Simple) StoreAsBlock(block *block.Block, aer1 *state.AppExecResult, aer2 *state.AppExecResult) error {
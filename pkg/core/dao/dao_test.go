@@ -9,6 +9,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
@@ -272,6 +273,73 @@ func BenchmarkStoreAsTransaction(b *testing.B) {
 	}
 }
 
+func TestHasTransactions(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false, true)
+	conflictsH := util.Uint256{1, 2, 3}
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1)
+	tx.Signers = append(tx.Signers, transaction.Signer{})
+	tx.Scripts = append(tx.Scripts, transaction.Witness{})
+	tx.Attributes = []transaction.Attribute{
+		{
+			Type:  transaction.ConflictsT,
+			Value: &transaction.Conflicts{Hash: conflictsH},
+		},
+	}
+	require.NoError(t, dao.StoreAsTransaction(tx, 0, nil))
+
+	require.Equal(t, 0, len(dao.HasTransactions(nil)))
+	hashes := []util.Uint256{random.Uint256(), tx.Hash(), {}, conflictsH, tx.Hash(), random.Uint256()}
+	res := dao.HasTransactions(hashes)
+	require.Equal(t, len(hashes), len(res))
+	for i, h := range hashes {
+		require.Equal(t, dao.HasTransaction(h), res[i], i)
+	}
+	require.ErrorIs(t, res[1], ErrAlreadyExists)
+	require.ErrorIs(t, res[3], ErrHasConflicts)
+}
+
+func BenchmarkHasTransactions(b *testing.B) {
+	const storedCount = 100000
+	ldb, err := storage.NewLevelDBStore(dbconfig.LevelDBOptions{DataDirectoryPath: b.TempDir()})
+	require.NoError(b, err)
+	b.Cleanup(func() { require.NoError(b, ldb.Close()) })
+	dao := NewSimple(ldb, false, true)
+	hashes := make([]util.Uint256, transaction.MaxAttributes-1)
+	for i := 0; i < storedCount; i++ {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, int64(i))
+		tx.Signers = append(tx.Signers, transaction.Signer{})
+		tx.Scripts = append(tx.Scripts, transaction.Witness{})
+		require.NoError(b, dao.StoreAsTransaction(tx, 0, nil))
+		if i < len(hashes)/2 {
+			hashes[i] = tx.Hash()
+		}
+		if i%10000 == 0 {
+			_, err = dao.Persist()
+			require.NoError(b, err)
+		}
+	}
+	for i := len(hashes) / 2; i < len(hashes); i++ {
+		hashes[i] = random.Uint256()
+	}
+	_, err = dao.Persist()
+	require.NoError(b, err)
+
+	b.Run("one by one", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			for _, h := range hashes {
+				_ = dao.HasTransaction(h)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			_ = dao.HasTransactions(hashes)
+		}
+	})
+}
+
 func TestMakeStorageItemKey(t *testing.T) {
 	var id int32 = 5
 
@@ -67,6 +67,7 @@ type Context struct {
 	loadToken        func(ic *Context, id int32) error
 	GetRandomCounter uint32
 	signers          []transaction.Signer
+	onExecHook       vm.OnExecHook
 }
 
 // NewContext returns new interop context.
@@ -135,6 +136,10 @@ type Function struct {
 	// RequiredFlags is a set of flags which must be set during script invocations.
 	// Default value is NoneFlag i.e. no flags are required.
 	RequiredFlags callflag.CallFlag
+	// ActiveFrom is the hard-fork the function is available since, nil means
+	// it's always available. Before the hard-fork the function behaves as
+	// if it doesn't exist.
+	ActiveFrom *config.Hardfork
 }
 
 // Method is a signature for a native method.
@@ -310,7 +315,7 @@ func (ic *Context) LoadToken(id int32) error {
 // SyscallHandler handles syscall with id.
 func (ic *Context) SyscallHandler(_ *vm.VM, id uint32) error {
 	f := ic.GetFunction(id)
-	if f == nil {
+	if f == nil || (f.ActiveFrom != nil && !ic.IsHardforkEnabled(*f.ActiveFrom)) {
 		return errors.New("syscall not found")
 	}
 	cf := ic.VM.Context().GetCallFlags()
@@ -323,6 +328,12 @@ func (ic *Context) SyscallHandler(_ *vm.VM, id uint32) error {
 	return f.Func(ic)
 }
 
+// SetOnExecHook sets the hook to be set for VMs spawned (or reused) by this
+// context, see vm.OnExecHook.
+func (ic *Context) SetOnExecHook(h vm.OnExecHook) {
+	ic.onExecHook = h
+}
+
 // SpawnVM spawns a new VM with the specified gas limit and set context.VM field.
 func (ic *Context) SpawnVM() *vm.VM {
 	v := vm.NewWithTrigger(ic.Trigger)
@@ -335,6 +346,7 @@ func (ic *Context) initVM(v *vm.VM) {
 	v.GasLimit = -1
 	v.SyscallHandler = ic.SyscallHandler
 	v.SetPriceGetter(ic.GetPrice)
+	v.SetOnExecHook(ic.onExecHook)
 	ic.VM = v
 }
 
@@ -352,10 +364,11 @@ func (ic *Context) RegisterCancelFunc(f context.CancelFunc) {
 	}
 }
 
-// Finalize calls all registered cancel functions to release the occupied resources.
+// Finalize calls all registered cancel functions (in the reverse order of
+// their registration) to release the occupied resources.
 func (ic *Context) Finalize() {
-	for _, f := range ic.cancelFuncs {
-		f()
+	for i := len(ic.cancelFuncs) - 1; i >= 0; i-- {
+		ic.cancelFuncs[i]()
 	}
 	ic.cancelFuncs = nil
 }
@@ -17,12 +17,14 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/require"
@@ -40,6 +42,48 @@ func TestGetCallFlags(t *testing.T) {
 	require.Equal(t, int64(callflag.All), ic.VM.Estack().Pop().Value().(*big.Int).Int64())
 }
 
+func TestGetCallFlagsContract(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	// The contract behaves differently depending on the flags it was called with.
+	src := `package flags
+		import (
+			"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+			"github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		)
+		func Update(v int) bool {
+			if contract.GetCallFlags()&contract.WriteStates == 0 {
+				return false
+			}
+			storage.Put(storage.GetContext(), "v", v)
+			return true
+		}
+		func MustWrite() {
+			if contract.GetCallFlags()&contract.States != contract.States {
+				panic("states access is required")
+			}
+		}`
+	ctr := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{
+		NoEventsCheck:      true,
+		NoPermissionsCheck: true,
+		Name:               "flags",
+	})
+	e.DeployContract(t, ctr, nil)
+
+	callScript := func(t *testing.T, method string, f callflag.CallFlag, args ...interface{}) []byte {
+		w := io.NewBufBinWriter()
+		emit.AppCall(w.BinWriter, ctr.Hash, method, f, args...)
+		require.NoError(t, w.Err)
+		return w.Bytes()
+	}
+	signers := []neotest.Signer{acc}
+	e.InvokeScriptCheckHALT(t, callScript(t, "update", callflag.ReadOnly, 1), signers, stackitem.Make(false))
+	e.InvokeScriptCheckHALT(t, callScript(t, "update", callflag.All, 1), signers, stackitem.Make(true))
+	e.InvokeScriptCheckFAULT(t, callScript(t, "mustWrite", callflag.ReadOnly), signers, "states access is required")
+	e.InvokeScriptCheckHALT(t, callScript(t, "mustWrite", callflag.All), signers, stackitem.Null{})
+}
+
 func TestCall(t *testing.T) {
 	bc, _ := chain.NewSingle(t)
 	ic, err := bc.GetTestVM(trigger.Application, &transaction.Transaction{}, &block.Block{})
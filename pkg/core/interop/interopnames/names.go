@@ -15,6 +15,7 @@ const (
 	SystemIteratorValue                 = "System.Iterator.Value"
 	SystemRuntimeBurnGas                = "System.Runtime.BurnGas"
 	SystemRuntimeCheckWitness           = "System.Runtime.CheckWitness"
+	SystemRuntimeCurrentSigners         = "System.Runtime.CurrentSigners"
 	SystemRuntimeGasLeft                = "System.Runtime.GasLeft"
 	SystemRuntimeGetAddressVersion      = "System.Runtime.GetAddressVersion"
 	SystemRuntimeGetCallingScriptHash   = "System.Runtime.GetCallingScriptHash"
@@ -27,6 +28,8 @@ const (
 	SystemRuntimeGetScriptContainer     = "System.Runtime.GetScriptContainer"
 	SystemRuntimeGetTime                = "System.Runtime.GetTime"
 	SystemRuntimeGetTrigger             = "System.Runtime.GetTrigger"
+	SystemRuntimeIsHardforkEnabled      = "System.Runtime.IsHardforkEnabled"
+	SystemRuntimeLoadScript             = "System.Runtime.LoadScript"
 	SystemRuntimeLog                    = "System.Runtime.Log"
 	SystemRuntimeNotify                 = "System.Runtime.Notify"
 	SystemRuntimePlatform               = "System.Runtime.Platform"
@@ -51,6 +54,7 @@ var names = []string{
 	SystemIteratorValue,
 	SystemRuntimeBurnGas,
 	SystemRuntimeCheckWitness,
+	SystemRuntimeCurrentSigners,
 	SystemRuntimeGasLeft,
 	SystemRuntimeGetAddressVersion,
 	SystemRuntimeGetCallingScriptHash,
@@ -63,6 +67,8 @@ var names = []string{
 	SystemRuntimeGetScriptContainer,
 	SystemRuntimeGetTime,
 	SystemRuntimeGetTrigger,
+	SystemRuntimeIsHardforkEnabled,
+	SystemRuntimeLoadScript,
 	SystemRuntimeLog,
 	SystemRuntimeNotify,
 	SystemRuntimePlatform,
@@ -6,6 +6,9 @@ import (
 	"math/big"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"go.uber.org/zap"
 )
@@ -55,6 +58,18 @@ func GetScriptContainer(ic *interop.Context) error {
 	return nil
 }
 
+// CurrentSigners returns signers of the transaction being run or Null if the
+// script container is not a transaction.
+func CurrentSigners(ic *interop.Context) error {
+	tx, ok := ic.Container.(*transaction.Transaction)
+	if ok {
+		ic.VM.Estack().PushItem(transaction.SignersToStackItem(tx.Signers))
+	} else {
+		ic.VM.Estack().PushItem(stackitem.Null{})
+	}
+	return nil
+}
+
 // Platform returns the name of the platform.
 func Platform(ic *interop.Context) error {
 	ic.VM.Estack().PushItem(stackitem.NewByteArray([]byte("NEO")))
@@ -67,6 +82,28 @@ func GetTrigger(ic *interop.Context) error {
 	return nil
 }
 
+// LoadScript takes a script, call flags and arguments from the stack and loads
+// the script into the VM. The script is executed with call flags limited to
+// the read-only set (and to the flags of the current context).
+func LoadScript(ic *interop.Context) error {
+	script := ic.VM.Estack().Pop().Bytes()
+	fs := callflag.CallFlag(int32(ic.VM.Estack().Pop().BigInt().Int64()))
+	if fs&^callflag.All != 0 {
+		return errors.New("call flags out of range")
+	}
+	args := ic.VM.Estack().Pop().Array()
+	if err := vm.IsScriptCorrect(script, nil); err != nil {
+		return fmt.Errorf("invalid script: %w", err)
+	}
+	fs = ic.VM.Context().GetCallFlags() & callflag.ReadOnly & fs
+	ic.VM.LoadDynamicScript(script, fs)
+
+	for e, i := ic.VM.Estack(), len(args)-1; i >= 0; i-- {
+		e.PushItem(args[i])
+	}
+	return nil
+}
+
 // Notify should pass stack item to the notify plugin to handle it, but
 // in neo-go the only meaningful thing to do here is to log.
 func Notify(ic *interop.Context) error {
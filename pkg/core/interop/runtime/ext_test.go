@@ -447,7 +447,37 @@ func TestGetNetwork(t *testing.T) {
 
 	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGetNetwork)
 	require.NoError(t, w.Err)
-	e.InvokeScriptCheckHALT(t, w.Bytes(), []neotest.Signer{acc}, stackitem.NewBigInteger(big.NewInt(int64(bc.GetConfig().Magic))))
+	require.Equal(t, netmode.UnitTestNet, bc.GetConfig().Magic)
+	e.InvokeScriptCheckHALT(t, w.Bytes(), []neotest.Signer{acc}, stackitem.NewBigInteger(big.NewInt(int64(netmode.UnitTestNet))))
+}
+
+func TestIsHardforkEnabled(t *testing.T) {
+	script := func(t *testing.T, name string) []byte {
+		w := io.NewBufBinWriter()
+		emit.String(w.BinWriter, name)
+		emit.Syscall(w.BinWriter, interopnames.SystemRuntimeIsHardforkEnabled)
+		require.NoError(t, w.Err)
+		return w.Bytes()
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		bc, acc := chain.NewSingle(t)
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		e.InvokeScriptCheckFAULT(t, script(t, config.HFAspidochelone.String()), []neotest.Signer{acc}, "syscall not found")
+	})
+	t.Run("enabled", func(t *testing.T) {
+		const hfHeight = 3
+		bc, acc := chain.NewSingleWithHardfork(t, config.HFAspidochelone, hfHeight)
+		e := neotest.NewExecutor(t, bc, acc, acc)
+
+		// Hard-fork is active for blocks following the one at hfHeight.
+		e.InvokeScriptCheckHALT(t, script(t, config.HFAspidochelone.String()), []neotest.Signer{acc}, stackitem.NewBool(false))
+		e.GenerateNewBlocks(t, hfHeight-2)
+		e.InvokeScriptCheckHALT(t, script(t, config.HFAspidochelone.String()), []neotest.Signer{acc}, stackitem.NewBool(false))
+		require.Equal(t, uint32(hfHeight), bc.BlockHeight())
+		e.InvokeScriptCheckHALT(t, script(t, config.HFAspidochelone.String()), []neotest.Signer{acc}, stackitem.NewBool(true))
+		e.InvokeScriptCheckHALT(t, script(t, "Unknown"), []neotest.Signer{acc}, stackitem.NewBool(false))
+	})
 }
 
 func TestGetNotifications(t *testing.T) {
@@ -598,3 +628,140 @@ func TestNotify(t *testing.T) {
 		require.NotEqual(t, arr, ev.Item)
 	})
 }
+
+func TestLoadScript(t *testing.T) {
+	_, ic, _ := createVM(t)
+	run := func(t *testing.T, script []byte, f callflag.CallFlag, args ...interface{}) error {
+		w := io.NewBufBinWriter()
+		emit.Array(w.BinWriter, args...)
+		emit.Int(w.BinWriter, int64(f))
+		emit.Bytes(w.BinWriter, script)
+		emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLoadScript)
+		require.NoError(t, w.Err)
+		loadScriptWithHashAndFlags(ic, w.Bytes(), random.Uint160(), callflag.All)
+		return ic.VM.Run()
+	}
+
+	t.Run("good", func(t *testing.T) {
+		require.NoError(t, run(t, []byte{byte(opcode.ADD)}, callflag.ReadOnly, 2, 3))
+		require.Equal(t, 1, ic.VM.Estack().Len())
+		require.Equal(t, big.NewInt(5), ic.VM.Estack().Pop().Value())
+	})
+	t.Run("no return value", func(t *testing.T) {
+		require.NoError(t, run(t, []byte{byte(opcode.DROP)}, callflag.ReadOnly, 1))
+		require.Equal(t, 1, ic.VM.Estack().Len())
+		require.Equal(t, stackitem.Null{}, ic.VM.Estack().Pop().Item())
+	})
+	t.Run("restricted flags", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Syscall(w.BinWriter, interopnames.SystemContractGetCallFlags)
+		script := w.Bytes()
+		require.NoError(t, run(t, script, callflag.All))
+		require.Equal(t, big.NewInt(int64(callflag.ReadOnly)), ic.VM.Estack().Pop().Value())
+
+		require.NoError(t, run(t, script, callflag.ReadStates|callflag.AllowNotify))
+		require.Equal(t, big.NewInt(int64(callflag.ReadStates)), ic.VM.Estack().Pop().Value())
+	})
+	t.Run("notification", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Array(w.BinWriter)
+		emit.String(w.BinWriter, "event")
+		emit.Syscall(w.BinWriter, interopnames.SystemRuntimeNotify)
+		require.Error(t, run(t, w.Bytes(), callflag.All))
+	})
+	t.Run("multiple return values", func(t *testing.T) {
+		require.Error(t, run(t, []byte{byte(opcode.DUP)}, callflag.ReadOnly, 1))
+	})
+	t.Run("invalid flags", func(t *testing.T) {
+		require.Error(t, run(t, []byte{byte(opcode.PUSH1)}, callflag.All+1))
+	})
+	t.Run("invalid script", func(t *testing.T) {
+		require.Error(t, run(t, []byte{byte(opcode.JMP), 0x7f}, callflag.ReadOnly))
+	})
+}
+
+func TestLoadScriptHardfork(t *testing.T) {
+	const hfHeight = 3
+	bc, acc := chain.NewSingleWithHardfork(t, config.HFBasilisk, hfHeight)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	w := io.NewBufBinWriter()
+	emit.Array(w.BinWriter)
+	emit.Int(w.BinWriter, int64(callflag.ReadOnly))
+	emit.Bytes(w.BinWriter, []byte{byte(opcode.PUSH1)})
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLoadScript)
+	require.NoError(t, w.Err)
+	script := w.Bytes()
+
+	// Interop is available for blocks following the one at hfHeight.
+	e.InvokeScriptCheckFAULT(t, script, []neotest.Signer{acc}, "syscall not found")
+	e.GenerateNewBlocks(t, hfHeight-2)
+	e.InvokeScriptCheckFAULT(t, script, []neotest.Signer{acc}, "syscall not found")
+	require.Equal(t, uint32(hfHeight), bc.BlockHeight())
+	e.InvokeScriptCheckHALT(t, script, []neotest.Signer{acc}, stackitem.Make(1))
+}
+
+func TestCurrentSigners(t *testing.T) {
+	t.Run("container is not a transaction", func(t *testing.T) {
+		_, ic, _ := createVM(t)
+		ic.Container = &block.Block{}
+		require.NoError(t, runtime.CurrentSigners(ic))
+		require.Equal(t, stackitem.Null{}, ic.VM.Estack().Pop().Item())
+	})
+
+	const hfHeight = 3
+	bc, acc := chain.NewSingleWithHardfork(t, config.HFBasilisk, hfHeight)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	w := io.NewBufBinWriter()
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeCurrentSigners)
+	require.NoError(t, w.Err)
+	script := w.Bytes()
+
+	// Interop is available for blocks following the one at hfHeight.
+	e.InvokeScriptCheckFAULT(t, script, []neotest.Signer{acc}, "syscall not found")
+	other := e.NewAccount(t)
+	e.InvokeScriptCheckFAULT(t, script, []neotest.Signer{acc}, "syscall not found")
+	require.Equal(t, uint32(hfHeight), bc.BlockHeight())
+
+	allowed := random.Uint160()
+	tx := transaction.New(script, 0)
+	tx.Nonce = neotest.Nonce()
+	tx.ValidUntilBlock = bc.BlockHeight() + 1
+	tx.Signers = []transaction.Signer{{
+		Account: acc.ScriptHash(),
+		Scopes:  transaction.CalledByEntry,
+	}, {
+		Account:          other.ScriptHash(),
+		Scopes:           transaction.CustomContracts | transaction.Rules,
+		AllowedContracts: []util.Uint160{allowed},
+		Rules: []transaction.WitnessRule{{
+			Action:    transaction.WitnessAllow,
+			Condition: transaction.ConditionCalledByEntry{},
+		}},
+	}}
+	neotest.AddNetworkFee(bc, tx, acc, other)
+	neotest.AddSystemFee(bc, tx, -1)
+	require.NoError(t, acc.SignTx(netmode.UnitTestNet, tx))
+	require.NoError(t, other.SignTx(netmode.UnitTestNet, tx))
+	e.AddNewBlock(t, tx)
+	e.CheckHalt(t, tx.Hash(), stackitem.NewArray([]stackitem.Item{
+		stackitem.NewArray([]stackitem.Item{
+			stackitem.NewByteArray(acc.ScriptHash().BytesBE()),
+			stackitem.Make(int64(transaction.CalledByEntry)),
+			stackitem.NewArray([]stackitem.Item{}),
+			stackitem.NewArray([]stackitem.Item{}),
+			stackitem.NewArray([]stackitem.Item{}),
+		}),
+		stackitem.NewArray([]stackitem.Item{
+			stackitem.NewByteArray(other.ScriptHash().BytesBE()),
+			stackitem.Make(int64(transaction.CustomContracts | transaction.Rules)),
+			stackitem.NewArray([]stackitem.Item{stackitem.NewByteArray(allowed.BytesBE())}),
+			stackitem.NewArray([]stackitem.Item{}),
+			stackitem.NewArray([]stackitem.Item{stackitem.NewArray([]stackitem.Item{
+				stackitem.Make(int64(transaction.WitnessAllow)),
+				stackitem.NewArray([]stackitem.Item{stackitem.Make(int64(transaction.WitnessCalledByEntry))}),
+			})}),
+		}),
+	}))
+}
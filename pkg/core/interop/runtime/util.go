@@ -87,6 +87,19 @@ func GetNetwork(ic *interop.Context) error {
 	return nil
 }
 
+// IsHardforkEnabled checks whether the hard-fork with the given name is enabled
+// at the current height, false is returned for unknown hard-forks. It's a NeoGo
+// extension that is only available with HardforkQueryExtension enabled.
+func IsHardforkEnabled(ic *interop.Context) error {
+	name, err := stackitem.ToString(ic.VM.Estack().Pop().Item())
+	if err != nil {
+		return err
+	}
+	hf, ok := config.HardforkFromString(name)
+	ic.VM.Estack().PushItem(stackitem.NewBool(ok && ic.IsHardforkEnabled(hf)))
+	return nil
+}
+
 // GetRandom returns pseudo-random number which depends on block nonce and transaction hash.
 func GetRandom(ic *interop.Context) error {
 	var (
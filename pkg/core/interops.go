@@ -8,6 +8,7 @@ package core
 */
 
 import (
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/contract"
@@ -26,9 +27,15 @@ import (
 func SpawnVM(ic *interop.Context) *vm.VM {
 	vm := ic.SpawnVM()
 	ic.Functions = systemInterops
+	if ic.Chain != nil {
+		ic.Functions = interopsFor(ic.Chain.GetConfig())
+	}
 	return vm
 }
 
+// hfBasilisk is referenced by interops available since the Basilisk hard-fork.
+var hfBasilisk = config.HFBasilisk
+
 // All lists are sorted, keep 'em this way, please.
 var systemInterops = []interop.Function{
 	{Name: interopnames.SystemContractCall, Func: contract.Call, Price: 1 << 15,
@@ -46,6 +53,8 @@ var systemInterops = []interop.Function{
 	{Name: interopnames.SystemRuntimeBurnGas, Func: runtime.BurnGas, Price: 1 << 4, ParamCount: 1},
 	{Name: interopnames.SystemRuntimeCheckWitness, Func: runtime.CheckWitness, Price: 1 << 10,
 		RequiredFlags: callflag.NoneFlag, ParamCount: 1},
+	{Name: interopnames.SystemRuntimeCurrentSigners, Func: runtime.CurrentSigners, Price: 1 << 4,
+		ActiveFrom: &hfBasilisk},
 	{Name: interopnames.SystemRuntimeGasLeft, Func: runtime.GasLeft, Price: 1 << 4},
 	{Name: interopnames.SystemRuntimeGetAddressVersion, Func: runtime.GetAddressVersion, Price: 1 << 3},
 	{Name: interopnames.SystemRuntimeGetCallingScriptHash, Func: runtime.GetCallingScriptHash, Price: 1 << 4},
@@ -58,6 +67,8 @@ var systemInterops = []interop.Function{
 	{Name: interopnames.SystemRuntimeGetScriptContainer, Func: runtime.GetScriptContainer, Price: 1 << 3},
 	{Name: interopnames.SystemRuntimeGetTime, Func: runtime.GetTime, Price: 1 << 3, RequiredFlags: callflag.ReadStates},
 	{Name: interopnames.SystemRuntimeGetTrigger, Func: runtime.GetTrigger, Price: 1 << 3},
+	{Name: interopnames.SystemRuntimeLoadScript, Func: runtime.LoadScript, Price: 1 << 15, RequiredFlags: callflag.AllowCall,
+		ParamCount: 3, ActiveFrom: &hfBasilisk},
 	{Name: interopnames.SystemRuntimeLog, Func: runtime.Log, Price: 1 << 15, RequiredFlags: callflag.AllowNotify,
 		ParamCount: 1},
 	{Name: interopnames.SystemRuntimeNotify, Func: runtime.Notify, Price: 1 << 15, RequiredFlags: callflag.AllowNotify,
@@ -79,10 +90,44 @@ var systemInterops = []interop.Function{
 		RequiredFlags: callflag.ReadStates, ParamCount: 1},
 }
 
+// extensionInterops are NeoGo-specific interops that are not supported by the
+// reference node, they're only available if enabled in the protocol
+// configuration.
+var extensionInterops = []interop.Function{
+	{Name: interopnames.SystemRuntimeIsHardforkEnabled, Func: runtime.IsHardforkEnabled, Price: 1 << 3, ParamCount: 1},
+}
+
+// systemInteropsWithExtensions contains both systemInterops and
+// extensionInterops.
+var systemInteropsWithExtensions []interop.Function
+
 // init initializes IDs in the global interop slices.
 func init() {
-	for i := range systemInterops {
-		systemInterops[i].ID = interopnames.ToID([]byte(systemInterops[i].Name))
+	for _, fs := range [][]interop.Function{systemInterops, extensionInterops} {
+		for i := range fs {
+			fs[i].ID = interopnames.ToID([]byte(fs[i].Name))
+		}
 	}
 	interop.Sort(systemInterops)
+	systemInteropsWithExtensions = append(systemInteropsWithExtensions, systemInterops...)
+	systemInteropsWithExtensions = append(systemInteropsWithExtensions, extensionInterops...)
+	interop.Sort(systemInteropsWithExtensions)
+}
+
+// SystemInterops returns a copy of the system interop functions list (without
+// NeoGo extensions) sorted by ID, it can be used to get interop properties
+// like prices or required call flags.
+func SystemInterops() []interop.Function {
+	res := make([]interop.Function, len(systemInterops))
+	copy(res, systemInterops)
+	return res
+}
+
+// interopsFor returns the list of interops available for the chain with the
+// given configuration.
+func interopsFor(cfg config.ProtocolConfiguration) []interop.Function {
+	if cfg.HardforkQueryExtension {
+		return systemInteropsWithExtensions
+	}
+	return systemInterops
 }
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/holiman/uint256"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -35,6 +36,9 @@ var (
 	// ErrOracleResponse is returned when the mempool already contains a transaction
 	// with the same oracle response ID and higher network fee.
 	ErrOracleResponse = errors.New("conflicts with memory pool due to OracleResponse attribute")
+	// ErrReplacement is returned when the transaction can't replace the pooled
+	// one with the same sender and nonce.
+	ErrReplacement = errors.New("can't replace memory pool transaction")
 )
 
 // item represents a transaction in the the Memory pool.
@@ -47,6 +51,13 @@ type item struct {
 // items is a slice of an item.
 type items []item
 
+// senderNonce identifies transactions that can replace each other if
+// same-sender replacement is enabled.
+type senderNonce struct {
+	sender util.Uint160
+	nonce  uint32
+}
+
 // utilityBalanceAndFees stores the sender's balance and overall fees of
 // the sender's transactions which are currently in the mempool.
 type utilityBalanceAndFees struct {
@@ -64,6 +75,15 @@ type Pool struct {
 	conflicts map[util.Uint256][]util.Uint256
 	// oracleResp contains the ids of oracle responses for the tx in the pool.
 	oracleResp map[uint64]util.Uint256
+	// senderNonces contains the hashes of the transactions in the pool by
+	// their sender and nonce, it's only filled if same-sender replacement
+	// is enabled.
+	senderNonces map[senderNonce]util.Uint256
+	// replacements contains the number of replacements in a row that led to
+	// the pooled transactions (only non-zero values are stored), it's only
+	// filled if same-sender replacement is enabled.
+	replacements map[util.Uint256]uint32
+	rbf          config.ReplaceByFee
 
 	capacity   int
 	feePerByte int64
@@ -210,7 +230,7 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 		mp.lock.Unlock()
 		return ErrDup
 	}
-	conflictsToBeRemoved, err := mp.checkTxConflicts(t, fee)
+	conflictsToBeRemoved, replacements, err := mp.checkTxConflicts(t, fee)
 	if err != nil {
 		mp.lock.Unlock()
 		return err
@@ -228,11 +248,9 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 		mp.oracleResp[id] = t.Hash()
 	}
 
-	if fee.P2PSigExtensionsEnabled() {
-		// Remove conflicting transactions.
-		for _, conflictingTx := range conflictsToBeRemoved {
-			mp.removeInternal(conflictingTx.Hash(), fee)
-		}
+	// Remove conflicting transactions.
+	for _, conflictingTx := range conflictsToBeRemoved {
+		mp.removeInternal(conflictingTx.Hash(), fee)
 	}
 	// Insert into a sorted array (from max to min, that could also be done
 	// using sort.Sort(sort.Reverse()), but it incurs more overhead. Notice
@@ -260,6 +278,7 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 		if attrs := unlucky.txn.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 			delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
 		}
+		mp.removeReplacementInfo(unlucky.txn)
 		mp.verifiedTxes[len(mp.verifiedTxes)-1] = pItem
 		if mp.subscriptionsOn.Load() {
 			mp.events <- mempoolevent.Event{
@@ -283,6 +302,12 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 			mp.conflicts[hash] = append(mp.conflicts[hash], t.Hash())
 		}
 	}
+	if mp.rbf.Enabled {
+		mp.senderNonces[senderNonce{t.Signers[mp.payerIndex].Account, t.Nonce}] = t.Hash()
+		if replacements != 0 {
+			mp.replacements[t.Hash()] = replacements
+		}
+	}
 	// we already checked balance in checkTxConflicts, so don't need to check again
 	mp.tryAddSendersFee(pItem.txn, fee, false)
 
@@ -334,6 +359,7 @@ func (mp *Pool) removeInternal(hash util.Uint256, feer Feer) {
 		if attrs := tx.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 			delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
 		}
+		mp.removeReplacementInfo(tx)
 		if mp.subscriptionsOn.Load() {
 			mp.events <- mempoolevent.Event{
 				Type: mempoolevent.TransactionRemoved,
@@ -384,6 +410,7 @@ func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer)
 			if attrs := itm.txn.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
 				delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
 			}
+			mp.removeReplacementInfo(itm.txn)
 			if mp.subscriptionsOn.Load() {
 				mp.events <- mempoolevent.Event{
 					Type: mempoolevent.TransactionRemoved,
@@ -429,6 +456,8 @@ func New(capacity int, payerIndex int, enableSubscriptions bool) *Pool {
 		fees:                 make(map[util.Uint160]utilityBalanceAndFees),
 		conflicts:            make(map[util.Uint256][]util.Uint256),
 		oracleResp:           make(map[uint64]util.Uint256),
+		senderNonces:         make(map[senderNonce]util.Uint256),
+		replacements:         make(map[util.Uint256]uint32),
 		subscriptionsEnabled: enableSubscriptions,
 		stopCh:               make(chan struct{}),
 		events:               make(chan mempoolevent.Event),
@@ -448,6 +477,14 @@ func (mp *Pool) SetResendThreshold(h uint32, f func(*transaction.Transaction, in
 	mp.resendFunc = f
 }
 
+// SetReplaceByFee sets transaction replacement settings, it must be called
+// before any transaction is added to the pool.
+func (mp *Pool) SetReplaceByFee(cfg config.ReplaceByFee) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	mp.rbf = cfg
+}
+
 func (mp *Pool) resendStaleItems(items []item) {
 	for i := range items {
 		mp.resendFunc(items[i].txn, items[i].data)
@@ -503,16 +540,32 @@ func (mp *Pool) GetVerifiedTransactions() []*transaction.Transaction {
 	return t
 }
 
+// IterateVerifiedTransactions iterates over verified transactions in the Pool
+// (in priority order) and calls cont for each of them with the data they were
+// added with. It stops when cont returns false. The Pool is locked while
+// iterating, so cont must not call other Pool methods.
+func (mp *Pool) IterateVerifiedTransactions(cont func(tx *transaction.Transaction, data interface{}) bool) {
+	mp.lock.RLock()
+	defer mp.lock.RUnlock()
+
+	for i := range mp.verifiedTxes {
+		if !cont(mp.verifiedTxes[i].txn, mp.verifiedTxes[i].data) {
+			return
+		}
+	}
+}
+
 // checkTxConflicts is an internal unprotected version of Verify. It takes into
-// consideration conflicting transactions which are about to be removed from mempool.
-func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*transaction.Transaction, error) {
+// consideration conflicting transactions which are about to be removed from
+// mempool and returns them along with the number of replacements in a row
+// that lead to tx.
+func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*transaction.Transaction, uint32, error) {
 	payer := tx.Signers[mp.payerIndex].Account
 	actualSenderFee, ok := mp.fees[payer]
 	if !ok {
 		actualSenderFee.balance.SetFromBig(fee.GetUtilityTokenBalance(payer))
 	}
 
-	var expectedSenderFee utilityBalanceAndFees
 	// Check Conflicts attributes.
 	var conflictsToBeRemoved []*transaction.Transaction
 	if fee.P2PSigExtensionsEnabled() {
@@ -521,7 +574,10 @@ func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*tran
 			for _, hash := range conflictingHashes {
 				existingTx := mp.verifiedMap[hash]
 				if existingTx.HasSigner(payer) && existingTx.NetworkFee > tx.NetworkFee {
-					return nil, fmt.Errorf("%w: conflicting transaction %s has bigger network fee", ErrConflictsAttribute, existingTx.Hash().StringBE())
+					return nil, 0, fmt.Errorf("%w: conflicting transaction %s has bigger network fee", ErrConflictsAttribute, existingTx.Hash().StringBE())
+				}
+				if !mp.canReplace(existingTx, tx) {
+					return nil, 0, fmt.Errorf("%w: network fee increase over conflicting transaction %s is less than %d%%", ErrConflictsAttribute, existingTx.Hash().StringBE(), mp.rbf.MinFeeIncrease)
 				}
 				conflictsToBeRemoved = append(conflictsToBeRemoved, existingTx)
 			}
@@ -534,25 +590,67 @@ func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*tran
 				continue
 			}
 			if !tx.HasSigner(existingTx.Signers[mp.payerIndex].Account) {
-				return nil, fmt.Errorf("%w: not signed by the sender of conflicting transaction %s", ErrConflictsAttribute, existingTx.Hash().StringBE())
+				return nil, 0, fmt.Errorf("%w: not signed by the sender of conflicting transaction %s", ErrConflictsAttribute, existingTx.Hash().StringBE())
 			}
 			if existingTx.NetworkFee >= tx.NetworkFee {
-				return nil, fmt.Errorf("%w: conflicting transaction %s has bigger or equal network fee", ErrConflictsAttribute, existingTx.Hash().StringBE())
+				return nil, 0, fmt.Errorf("%w: conflicting transaction %s has bigger or equal network fee", ErrConflictsAttribute, existingTx.Hash().StringBE())
+			}
+			if !mp.canReplace(existingTx, tx) {
+				return nil, 0, fmt.Errorf("%w: network fee increase over conflicting transaction %s is less than %d%%", ErrConflictsAttribute, existingTx.Hash().StringBE(), mp.rbf.MinFeeIncrease)
 			}
 			conflictsToBeRemoved = append(conflictsToBeRemoved, existingTx)
 		}
-		// Step 3: take into account sender's conflicting transactions before balance check.
-		expectedSenderFee = actualSenderFee
-		for _, conflictingTx := range conflictsToBeRemoved {
-			if conflictingTx.Signers[mp.payerIndex].Account.Equals(payer) {
-				expectedSenderFee.feeSum.SubUint64(&expectedSenderFee.feeSum, uint64(conflictingTx.SystemFee+conflictingTx.NetworkFee))
+	}
+	// Check the pooled transaction with the same sender and nonce.
+	if mp.rbf.Enabled {
+		hash, ok := mp.senderNonces[senderNonce{payer, tx.Nonce}]
+		for i := 0; ok && i < len(conflictsToBeRemoved); i++ {
+			ok = conflictsToBeRemoved[i].Hash() != hash // Already replaced via Conflicts.
+		}
+		if ok {
+			existingTx := mp.verifiedMap[hash]
+			if existingTx.NetworkFee >= tx.NetworkFee {
+				return nil, 0, fmt.Errorf("%w: transaction %s with the same sender and nonce has bigger or equal network fee", ErrReplacement, hash.StringBE())
+			}
+			if !mp.canReplace(existingTx, tx) {
+				return nil, 0, fmt.Errorf("%w: network fee increase over transaction %s with the same sender and nonce is less than %d%%", ErrReplacement, hash.StringBE(), mp.rbf.MinFeeIncrease)
 			}
+			conflictsToBeRemoved = append(conflictsToBeRemoved, existingTx)
 		}
-	} else {
+	}
+	// Take into account sender's replaced transactions before balance check.
+	var (
 		expectedSenderFee = actualSenderFee
+		replacements      uint32
+	)
+	for _, conflictingTx := range conflictsToBeRemoved {
+		if conflictingTx.Signers[mp.payerIndex].Account.Equals(payer) {
+			expectedSenderFee.feeSum.SubUint64(&expectedSenderFee.feeSum, uint64(conflictingTx.SystemFee+conflictingTx.NetworkFee))
+			if n := mp.replacements[conflictingTx.Hash()] + 1; n > replacements {
+				replacements = n
+			}
+		}
+	}
+	if mp.rbf.Enabled && mp.rbf.MaxReplacements != 0 && replacements > mp.rbf.MaxReplacements {
+		return nil, 0, fmt.Errorf("%w: transaction was replaced %d times already", ErrReplacement, mp.rbf.MaxReplacements)
 	}
 	_, err := checkBalance(tx, expectedSenderFee)
-	return conflictsToBeRemoved, err
+	return conflictsToBeRemoved, replacements, err
+}
+
+// canReplace checks whether the network fee of tx is big enough to replace
+// the pooled transaction of the same sender, the check is only performed if
+// same-sender replacement is enabled.
+func (mp *Pool) canReplace(pooled, tx *transaction.Transaction) bool {
+	if !mp.rbf.Enabled || mp.rbf.MinFeeIncrease == 0 || !pooled.Signers[mp.payerIndex].Account.Equals(tx.Signers[mp.payerIndex].Account) {
+		return true
+	}
+	var need, have uint256.Int
+	need.SetUint64(uint64(pooled.NetworkFee))
+	need.Mul(&need, uint256.NewInt(100+uint64(mp.rbf.MinFeeIncrease)))
+	have.SetUint64(uint64(tx.NetworkFee))
+	have.Mul(&have, uint256.NewInt(100))
+	return have.Cmp(&need) >= 0
 }
 
 // Verify checks if the Sender of the tx is able to pay for it (and all the other
@@ -562,7 +660,7 @@ func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*tran
 func (mp *Pool) Verify(tx *transaction.Transaction, feer Feer) bool {
 	mp.lock.RLock()
 	defer mp.lock.RUnlock()
-	_, err := mp.checkTxConflicts(tx, feer)
+	_, _, err := mp.checkTxConflicts(tx, feer)
 	return err == nil
 }
 
@@ -585,3 +683,16 @@ func (mp *Pool) removeConflictsOf(tx *transaction.Transaction) {
 		}
 	}
 }
+
+// removeReplacementInfo removes the given transaction from the sender and
+// nonce index and drops its replacements counter.
+func (mp *Pool) removeReplacementInfo(tx *transaction.Transaction) {
+	if !mp.rbf.Enabled {
+		return
+	}
+	key := senderNonce{tx.Signers[mp.payerIndex].Account, tx.Nonce}
+	if h, ok := mp.senderNonces[key]; ok && h == tx.Hash() {
+		delete(mp.senderNonces, key)
+	}
+	delete(mp.replacements, tx.Hash())
+}
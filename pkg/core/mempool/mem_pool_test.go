@@ -9,6 +9,7 @@ import (
 
 	"github.com/holiman/uint256"
 	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -639,6 +640,27 @@ func TestMempoolAddWithDataGetData(t *testing.T) {
 	require.True(t, ok)
 	require.Nil(t, data)
 
+	// iterate over all items along with their data
+	var (
+		iterTxes []*transaction.Transaction
+		iterData []interface{}
+	)
+	mp.IterateVerifiedTransactions(func(tx *transaction.Transaction, data interface{}) bool {
+		iterTxes = append(iterTxes, tx)
+		iterData = append(iterData, data)
+		return true
+	})
+	require.Equal(t, []*transaction.Transaction{r3.FallbackTransaction, r2.FallbackTransaction,
+		r4.FallbackTransaction, r5.FallbackTransaction, r6}, iterTxes)
+	require.Equal(t, []interface{}{r3, r2, r4, r5, nil}, iterData)
+
+	iterData = iterData[:0]
+	mp.IterateVerifiedTransactions(func(_ *transaction.Transaction, data interface{}) bool {
+		iterData = append(iterData, data)
+		return len(iterData) < 2
+	})
+	require.Equal(t, []interface{}{r3, r2}, iterData)
+
 	// getting data: item is in verifiedMap, but not in verifiedTxes
 	r7 := &payload.P2PNotaryRequest{
 		MainTransaction:     newTx(t, 0),
@@ -656,3 +678,129 @@ func TestMempoolAddWithDataGetData(t *testing.T) {
 	_, ok = mp.TryGetData(r7.FallbackTransaction.Hash())
 	require.False(t, ok)
 }
+
+func TestMempoolReplaceByFee(t *testing.T) {
+	var (
+		sender  = util.Uint160{1, 2, 3}
+		another = util.Uint160{4, 5, 6}
+		fs      = &FeerStub{p2pSigExt: true, balance: 300}
+	)
+	newTx := func(from util.Uint160, nonce uint32, netFee, sysFee int64) *transaction.Transaction {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = nonce
+		tx.NetworkFee = netFee
+		tx.SystemFee = sysFee
+		tx.Signers = []transaction.Signer{{Account: from}}
+		return tx
+	}
+	feeSum := func(mp *Pool, acc util.Uint160) uint64 {
+		f := mp.fees[acc]
+		return f.feeSum.Uint64()
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		mp := New(10, 0, false)
+		require.NoError(t, mp.Add(newTx(sender, 1, 100, 0), fs))
+		require.NoError(t, mp.Add(newTx(sender, 1, 110, 0), fs))
+		require.Equal(t, 2, mp.Count())
+		require.Equal(t, 0, len(mp.senderNonces))
+	})
+
+	t.Run("same sender and nonce", func(t *testing.T) {
+		mp := New(10, 0, false)
+		mp.SetReplaceByFee(config.ReplaceByFee{Enabled: true, MinFeeIncrease: 10, MaxReplacements: 2})
+
+		tx1 := newTx(sender, 1, 100, 50)
+		require.NoError(t, mp.Add(tx1, fs))
+		require.Equal(t, uint64(150), feeSum(mp, sender))
+
+		// Other senders are not affected.
+		txOther := newTx(another, 1, 10, 0)
+		require.NoError(t, mp.Add(txOther, fs))
+
+		// Not enough fee.
+		require.ErrorIs(t, mp.Add(newTx(sender, 1, 100, 0), fs), ErrReplacement)
+		require.ErrorIs(t, mp.Add(newTx(sender, 1, 109, 0), fs), ErrReplacement)
+		require.Equal(t, 2, mp.Count())
+
+		tx2 := newTx(sender, 1, 110, 60)
+		require.NoError(t, mp.Add(tx2, fs))
+		require.Equal(t, 2, mp.Count())
+		require.False(t, mp.ContainsKey(tx1.Hash()))
+		require.True(t, mp.ContainsKey(txOther.Hash()))
+		require.Equal(t, uint64(170), feeSum(mp, sender))
+		require.Equal(t, uint64(10), feeSum(mp, another))
+		require.Equal(t, tx2.Hash(), mp.senderNonces[senderNonce{sender, 1}])
+
+		// Replaced transaction fees are not taken into account when checking
+		// the balance.
+		tx3 := newTx(sender, 1, 290, 0)
+		require.ErrorIs(t, mp.Add(newTx(sender, 2, 290, 0), fs), ErrConflict)
+		require.True(t, mp.Verify(tx3, fs))
+		require.NoError(t, mp.Add(tx3, fs))
+		require.Equal(t, uint64(290), feeSum(mp, sender))
+		require.Equal(t, uint32(2), mp.replacements[tx3.Hash()])
+
+		// Replacement limit is reached.
+		tx4 := newTx(sender, 1, 299, 0)
+		require.ErrorIs(t, mp.Add(tx4, fs), ErrReplacement)
+		require.False(t, mp.Verify(tx4, fs))
+
+		// The index is cleaned up on removal and the counter is reset.
+		mp.Remove(tx3.Hash(), fs)
+		require.Equal(t, uint64(0), feeSum(mp, sender))
+		require.Equal(t, 1, len(mp.senderNonces))
+		require.NoError(t, mp.Add(tx4, fs))
+		require.Equal(t, uint32(0), mp.replacements[tx4.Hash()])
+
+		mp.RemoveStale(func(*transaction.Transaction) bool { return false }, fs)
+		require.Equal(t, 0, len(mp.senderNonces))
+		require.Equal(t, 0, len(mp.fees))
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		mp := New(10, 0, false)
+		mp.SetReplaceByFee(config.ReplaceByFee{Enabled: true, MinFeeIncrease: 10})
+
+		tx1 := newTx(sender, 1, 100, 0)
+		require.NoError(t, mp.Add(tx1, fs))
+
+		newConflictsTx := func(nonce uint32, netFee int64) *transaction.Transaction {
+			tx := newTx(sender, nonce, netFee, 0)
+			tx.Attributes = []transaction.Attribute{{
+				Type:  transaction.ConflictsT,
+				Value: &transaction.Conflicts{Hash: tx1.Hash()},
+			}}
+			return tx
+		}
+		require.ErrorIs(t, mp.Add(newConflictsTx(2, 105), fs), ErrConflictsAttribute)
+
+		// Conflicting transaction with the same nonce is replaced once.
+		tx2 := newConflictsTx(1, 110)
+		require.NoError(t, mp.Add(tx2, fs))
+		require.Equal(t, 1, mp.Count())
+		require.Equal(t, uint64(110), feeSum(mp, sender))
+		require.Equal(t, uint32(1), mp.replacements[tx2.Hash()])
+	})
+
+	t.Run("conflicts, disabled", func(t *testing.T) {
+		// Limits are not applied to Conflicts-based replacement if
+		// same-sender replacement is disabled.
+		mp := New(10, 0, false)
+		mp.SetReplaceByFee(config.ReplaceByFee{MinFeeIncrease: 10, MaxReplacements: 1})
+
+		prev := newTx(sender, 1, 100, 0)
+		require.NoError(t, mp.Add(prev, fs))
+		for i := 0; i < 3; i++ {
+			tx := newTx(sender, 1, prev.NetworkFee+1, 0)
+			tx.Attributes = []transaction.Attribute{{
+				Type:  transaction.ConflictsT,
+				Value: &transaction.Conflicts{Hash: prev.Hash()},
+			}}
+			require.NoError(t, mp.Add(tx, fs))
+			require.Equal(t, 1, mp.Count())
+			prev = tx
+		}
+		require.Equal(t, 0, len(mp.replacements))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -92,4 +93,28 @@ func TestSubscriptions(t *testing.T) {
 		require.Equal(t, 0, len(subChan1))
 		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionAdded, Tx: txs[3]}, event2)
 	})
+
+	t.Run("replaced transaction", func(t *testing.T) {
+		fs := &FeerStub{balance: 100}
+		mp := New(2, 0, true)
+		mp.SetReplaceByFee(config.ReplaceByFee{Enabled: true})
+		mp.RunSubscriptions()
+		subChan := make(chan mempoolevent.Event, 3)
+		mp.SubscribeForTransactions(subChan)
+		t.Cleanup(mp.StopSubscriptions)
+
+		txs := make([]*transaction.Transaction, 2)
+		for i := range txs {
+			txs[i] = transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+			txs[i].Nonce = 1
+			txs[i].Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+			txs[i].NetworkFee = int64(i + 1)
+		}
+		require.NoError(t, mp.Add(txs[0], fs))
+		require.NoError(t, mp.Add(txs[1], fs))
+		require.Eventually(t, func() bool { return len(subChan) == 3 }, time.Second, time.Millisecond*100)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionAdded, Tx: txs[0]}, <-subChan)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionRemoved, Tx: txs[0]}, <-subChan)
+		require.Equal(t, mempoolevent.Event{Type: mempoolevent.TransactionAdded, Tx: txs[1]}, <-subChan)
+	})
 }
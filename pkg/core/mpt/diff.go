@@ -0,0 +1,138 @@
+package mpt
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// DiffFunc is called by Diff for every key that has different values in two
+// tries. oldValue is nil for added keys and newValue is nil for deleted ones.
+// Returning false stops the walk.
+type DiffFunc func(key, oldValue, newValue []byte) bool
+
+// differ is a state of Diff walk.
+type differ struct {
+	t      *Trie
+	prefix []byte
+	f      DiffFunc
+}
+
+// Diff walks two tries with the given roots (stored in the same store using
+// the given mode) in parallel and calls f for every key with the given prefix
+// that has different values in them. Keys are processed in ascending order.
+// Subtries with the same hash are identical, so they're skipped without being
+// read from the storage which makes the walk proportional to the size of the
+// difference rather than to the size of the tries.
+func Diff(store *storage.MemCachedStore, mode TrieMode, oldRoot, newRoot util.Uint256, prefix []byte, f DiffFunc) error {
+	if len(prefix) > MaxKeyLength {
+		return errors.New("invalid prefix length")
+	}
+	d := &differ{
+		t:      NewTrie(nil, mode, store),
+		prefix: toNibbles(prefix),
+		f:      f,
+	}
+	err := d.diff(rootNode(oldRoot), rootNode(newRoot), []byte{})
+	if errors.Is(err, errStop) {
+		return nil
+	}
+	return err
+}
+
+// rootNode returns a trie root node for the given state root hash.
+func rootNode(h util.Uint256) Node {
+	if h.Equals(util.Uint256{}) {
+		return EmptyNode{}
+	}
+	return NewHashNode(h)
+}
+
+// diff compares subtries located at the given path.
+func (d *differ) diff(oldNode, newNode Node, path []byte) error {
+	if isEmpty(oldNode) && isEmpty(newNode) {
+		return nil
+	}
+	if !isEmpty(oldNode) && !isEmpty(newNode) && oldNode.Hash().Equals(newNode.Hash()) {
+		return nil
+	}
+	oldValue, oldChildren, err := d.expand(oldNode)
+	if err != nil {
+		return err
+	}
+	newValue, newChildren, err := d.expand(newNode)
+	if err != nil {
+		return err
+	}
+	// Values can only be stored at full byte paths.
+	if len(path) >= len(d.prefix) && len(path)%2 == 0 &&
+		((oldValue == nil) != (newValue == nil) || !bytes.Equal(oldValue, newValue)) {
+		if !d.f(fromNibbles(path), oldValue, newValue) {
+			return errStop
+		}
+	}
+	for i := 0; i < lastChild; i++ {
+		if len(path) < len(d.prefix) && d.prefix[len(path)] != byte(i) {
+			continue
+		}
+		err = d.diff(oldChildren[i], newChildren[i], append(path, byte(i)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expand returns the value stored at the node path (nil if there is none,
+// non-nil empty slice for empty values) and children nodes located one nibble
+// deeper. Extension nodes are split into a single child
+// with the rest of the extension key, so that both tries can be walked nibble
+// by nibble irrespective of their shape.
+func (d *differ) expand(n Node) ([]byte, [lastChild]Node, error) {
+	var children [lastChild]Node
+	for i := range children {
+		children[i] = EmptyNode{}
+	}
+	if hn, ok := n.(*HashNode); ok {
+		var err error
+		n, err = d.t.getFromStore(hn.Hash())
+		if err != nil {
+			return nil, children, err
+		}
+	}
+	switch n := n.(type) {
+	case *LeafNode:
+		return leafValue(n), children, nil
+	case *ExtensionNode:
+		if len(n.key) == 1 {
+			children[n.key[0]] = n.next
+		} else {
+			children[n.key[0]] = NewExtensionNode(n.key[1:], n.next)
+		}
+		return nil, children, nil
+	case *BranchNode:
+		copy(children[:], n.Children[:lastChild])
+		var value []byte
+		if leaf, ok := n.Children[lastChild].(*LeafNode); ok {
+			value = leafValue(leaf)
+		} else if hn, ok := n.Children[lastChild].(*HashNode); ok {
+			v, _, err := d.expand(hn)
+			if err != nil {
+				return nil, children, err
+			}
+			value = v
+		}
+		return value, children, nil
+	}
+	return nil, children, nil
+}
+
+// leafValue returns the leaf value, it's never nil.
+func leafValue(n *LeafNode) []byte {
+	if n.value == nil {
+		return []byte{}
+	}
+	return n.value
+}
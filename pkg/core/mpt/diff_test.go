@@ -0,0 +1,116 @@
+package mpt
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+type diffItem struct {
+	key, oldValue, newValue []byte
+}
+
+func TestDiff(t *testing.T) {
+	st := newTestStore()
+	tr := NewTrie(nil, ModeAll, st)
+	oldKV := map[string][]byte{
+		"\x01":             []byte("short"),
+		"\x01\x02":         []byte("long"),
+		"\x01\x02\x03":     []byte("longer"),
+		"\x10\x00":         []byte("deleted"),
+		"\x10\x01":         []byte("modified"),
+		"\x20\xab\xcd\xef": []byte("same"),
+		"\x21":             []byte("same too"),
+		"\x30":             {},
+	}
+	for k, v := range oldKV {
+		require.NoError(t, tr.Put([]byte(k), v))
+	}
+	tr.Flush(1)
+	oldRoot := tr.StateRoot()
+
+	newKV := make(map[string][]byte)
+	for k, v := range oldKV {
+		newKV[k] = v
+	}
+	for k, v := range map[string][]byte{
+		"\x01\x02":     []byte("LONG"),
+		"\x10\x01":     []byte("MODIFIED"),
+		"\x10\x02":     []byte("added"),
+		"\x01\x02\x04": []byte("added too"),
+		"\xff":         []byte("last"),
+		"\x31":         {},
+	} {
+		require.NoError(t, tr.Put([]byte(k), v))
+		newKV[k] = v
+	}
+	for _, k := range []string{"\x10\x00", "\x01\x02\x03", "\x30"} {
+		require.NoError(t, tr.Delete([]byte(k)))
+		delete(newKV, k)
+	}
+	tr.Flush(2)
+	newRoot := tr.StateRoot()
+
+	expected := func(prefix []byte, a, b map[string][]byte) []diffItem {
+		var res []diffItem
+		for k, v := range a {
+			if nv, ok := b[k]; bytes.HasPrefix([]byte(k), prefix) && (!ok || !bytes.Equal(v, nv)) {
+				res = append(res, diffItem{[]byte(k), v, nv})
+			}
+		}
+		for k, v := range b {
+			if _, ok := a[k]; !ok && bytes.HasPrefix([]byte(k), prefix) {
+				res = append(res, diffItem{[]byte(k), nil, v})
+			}
+		}
+		sort.Slice(res, func(i, j int) bool { return bytes.Compare(res[i].key, res[j].key) < 0 })
+		return res
+	}
+	check := func(t *testing.T, oldRoot, newRoot util.Uint256, prefix []byte, expected []diffItem) {
+		var actual []diffItem
+		require.NoError(t, Diff(st, ModeAll, oldRoot, newRoot, prefix, func(key, oldValue, newValue []byte) bool {
+			actual = append(actual, diffItem{key, oldValue, newValue})
+			return true
+		}))
+		require.Equal(t, expected, actual)
+	}
+
+	t.Run("full", func(t *testing.T) {
+		check(t, oldRoot, newRoot, nil, expected(nil, oldKV, newKV))
+	})
+	t.Run("reverse", func(t *testing.T) {
+		check(t, newRoot, oldRoot, nil, expected(nil, newKV, oldKV))
+	})
+	t.Run("same", func(t *testing.T) {
+		check(t, oldRoot, oldRoot, nil, nil)
+	})
+	t.Run("empty", func(t *testing.T) {
+		check(t, util.Uint256{}, oldRoot, nil, expected(nil, nil, oldKV))
+		check(t, newRoot, util.Uint256{}, nil, expected(nil, newKV, nil))
+		check(t, util.Uint256{}, util.Uint256{}, nil, nil)
+	})
+	t.Run("prefix", func(t *testing.T) {
+		for _, p := range [][]byte{{0x01}, {0x01, 0x02}, {0x10}, {0x20}, {0xff}, {0x33}} {
+			check(t, oldRoot, newRoot, p, expected(p, oldKV, newKV))
+		}
+	})
+	t.Run("stop", func(t *testing.T) {
+		var n int
+		require.NoError(t, Diff(st, ModeAll, oldRoot, newRoot, nil, func(_, _, _ []byte) bool {
+			n++
+			return n < 2
+		}))
+		require.Equal(t, 2, n)
+	})
+	t.Run("missing node", func(t *testing.T) {
+		require.Error(t, Diff(st, ModeAll, oldRoot, util.Uint256{1, 2, 3}, nil, func(_, _, _ []byte) bool {
+			return true
+		}))
+	})
+	t.Run("invalid prefix", func(t *testing.T) {
+		require.Error(t, Diff(st, ModeAll, oldRoot, newRoot, make([]byte, MaxKeyLength+1), nil))
+	})
+}
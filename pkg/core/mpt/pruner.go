@@ -0,0 +1,209 @@
+package mpt
+
+import (
+	"errors"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+)
+
+// pruneBatchSize is the maximum number of nodes checked (and thus the maximum
+// number of deletions flushed to the storage at once) by Pruner between time
+// budget checks.
+const pruneBatchSize = 1024
+
+// Pruner removes MPT nodes that are not reachable from the set of retained
+// state roots. It works in two phases: marking (traversing retained tries and
+// collecting hashes of all nodes reachable from them) and sweeping (removing
+// all unmarked nodes from the storage). Both phases can be performed in small
+// steps interleaved with other storage operations, but new roots (if any)
+// must be added via AddRoot before each step. Pruner is not thread-safe.
+type Pruner struct {
+	// reader is used to get nodes, it may contain changes that are not yet
+	// persisted to the store.
+	reader *storage.MemCachedStore
+	// store is the persistent store nodes are removed from.
+	store storage.Store
+
+	marked  map[util.Uint256]struct{}
+	pending []util.Uint256
+	// sweepFrom is the key suffix sweeping continues from.
+	sweepFrom []byte
+	done      bool
+
+	removed int
+	kept    int
+}
+
+// NewPruner returns a new Pruner reading nodes from the given MemCachedStore
+// and removing them from the given Store (which is usually the one the
+// MemCachedStore is persisted to).
+func NewPruner(reader *storage.MemCachedStore, store storage.Store) *Pruner {
+	return &Pruner{
+		reader: reader,
+		store:  store,
+		marked: make(map[util.Uint256]struct{}),
+	}
+}
+
+// AddRoot adds the given state root to the set of retained ones, all nodes
+// reachable from it are kept. It can be called at any stage of pruning.
+func (p *Pruner) AddRoot(root util.Uint256) {
+	if root.Equals(util.Uint256{}) { // Empty trie.
+		return
+	}
+	p.mark(root)
+}
+
+// Marked returns the number of nodes marked as reachable so far.
+func (p *Pruner) Marked() int {
+	return len(p.marked)
+}
+
+// Removed returns the number of nodes removed so far.
+func (p *Pruner) Removed() int {
+	return p.removed
+}
+
+// Kept returns the number of nodes kept during sweeping so far.
+func (p *Pruner) Kept() int {
+	return p.kept
+}
+
+// Step performs pruning for at most the given time (zero means no limit),
+// it returns true when pruning is completed. Time checks are performed
+// after node reads and deletion batches, so the actual step duration may
+// exceed the budget by the time needed to process one node or one batch of
+// nodes.
+func (p *Pruner) Step(budget time.Duration) (bool, error) {
+	if p.done {
+		return true, nil
+	}
+	var deadline time.Time
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+	expired := func() bool {
+		return !deadline.IsZero() && time.Now().After(deadline)
+	}
+
+	// At least one node or batch is processed on every step, so that some
+	// progress is made even with very small budget.
+	for len(p.pending) != 0 {
+		if err := p.markNext(); err != nil {
+			return false, err
+		}
+		if expired() {
+			return false, nil
+		}
+	}
+	for {
+		finished, err := p.sweepBatch()
+		if err != nil {
+			return false, err
+		}
+		if finished {
+			p.done = true
+			return true, nil
+		}
+		if expired() {
+			return false, nil
+		}
+	}
+}
+
+// mark marks the node with the given hash and schedules its children
+// traversal if it's not marked yet.
+func (p *Pruner) mark(h util.Uint256) {
+	if _, ok := p.marked[h]; ok {
+		return
+	}
+	p.marked[h] = struct{}{}
+	p.pending = append(p.pending, h)
+}
+
+// markNext marks children of the next pending node.
+func (p *Pruner) markNext() error {
+	h := p.pending[len(p.pending)-1]
+	p.pending = p.pending[:len(p.pending)-1]
+
+	data, err := p.reader.Get(makeStorageKey(h))
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			// Nodes of old roots can be missing for tries with reference
+			// counting, there is nothing to keep then.
+			return nil
+		}
+		return err
+	}
+	// Values of tries with reference counting have additional data
+	// after the node which is just ignored here.
+	var n NodeObject
+	r := io.NewBinReaderFromBuf(data)
+	n.DecodeBinary(r)
+	if r.Err != nil {
+		return r.Err
+	}
+	switch node := n.Node.(type) {
+	case *BranchNode:
+		for i := range node.Children {
+			p.markChild(node.Children[i])
+		}
+	case *ExtensionNode:
+		p.markChild(node.next)
+	}
+	return nil
+}
+
+func (p *Pruner) markChild(n Node) {
+	if hn, ok := n.(*HashNode); ok {
+		p.mark(hn.Hash())
+	}
+}
+
+// sweepBatch checks up to pruneBatchSize nodes starting from the current
+// sweeping position and removes unmarked ones, it returns true if there are no more nodes left.
+func (p *Pruner) sweepBatch() (bool, error) {
+	var (
+		dels     = make(map[string][]byte)
+		checked  int
+		finished = true
+		last     []byte
+	)
+	p.store.Seek(storage.SeekRange{
+		Prefix: []byte{byte(storage.DataMPT)},
+		Start:  p.sweepFrom,
+	}, func(k, v []byte) bool {
+		if checked >= pruneBatchSize {
+			finished = false
+			last = slice.Copy(k[1:])
+			return false
+		}
+		checked++
+		var h util.Uint256
+		if len(k) != 1+util.Uint256Size {
+			p.kept++
+			return true
+		}
+		copy(h[:], k[1:])
+		if _, ok := p.marked[h]; ok {
+			p.kept++
+			return true
+		}
+		dels[string(k)] = nil
+		return true
+	})
+	if len(dels) != 0 {
+		if err := p.store.PutChangeSet(dels, nil); err != nil {
+			return false, err
+		}
+		p.removed += len(dels)
+	}
+	if !finished {
+		p.sweepFrom = last
+	}
+	return finished, nil
+}
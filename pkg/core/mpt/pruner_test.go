@@ -0,0 +1,99 @@
+package mpt
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruner(t *testing.T) {
+	const (
+		heights = 20
+		retain  = 5
+	)
+	var keys [][]byte
+	for i := 0; i < 50; i++ {
+		keys = append(keys, []byte("key"+strconv.Itoa(i)))
+	}
+	// value returns the value of the i-th key at the given height, only a
+	// part of keys is changed at every height.
+	value := func(h, i int) []byte {
+		if i < h {
+			return []byte(strconv.Itoa(h))
+		}
+		return []byte("initial")
+	}
+	countNodes := func(s storage.Store) int {
+		var n int
+		s.Seek(storage.SeekRange{Prefix: []byte{byte(storage.DataMPT)}}, func(k, v []byte) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	// checkProofs checks that proofs for all keys of the given trie state
+	// are available and valid.
+	checkProofs := func(t *testing.T, mode TrieMode, st *storage.MemCachedStore, root util.Uint256, h int) {
+		tr := NewTrie(NewHashNode(root), mode&^ModeGCFlag, st)
+		for i, k := range keys {
+			proof, err := tr.GetProof(k)
+			require.NoError(t, err, "height %d, key %d", h, i)
+			v, ok := VerifyProof(root, k, proof)
+			require.True(t, ok, "height %d, key %d", h, i)
+			require.Equal(t, value(h, i), v)
+		}
+	}
+
+	for _, mode := range []TrieMode{ModeAll, ModeLatest, ModeGC} {
+		t.Run(strconv.Itoa(int(mode)), func(t *testing.T) {
+			ps := storage.NewMemoryStore()
+			st := storage.NewMemCachedStore(ps)
+			tr := NewTrie(nil, mode, st)
+			roots := make([]util.Uint256, heights)
+			for h := 0; h < heights; h++ {
+				for i, k := range keys {
+					require.NoError(t, tr.Put(k, value(h, i)))
+				}
+				tr.Flush(uint32(h))
+				roots[h] = tr.StateRoot()
+				_, err := st.Persist()
+				require.NoError(t, err)
+			}
+			before := countNodes(ps)
+
+			p := NewPruner(st, ps)
+			for h := heights - retain; h < heights-1; h++ {
+				p.AddRoot(roots[h])
+			}
+			var done bool
+			for i := 0; !done; i++ {
+				var err error
+				if i == 1 { // Roots can be added during pruning.
+					p.AddRoot(roots[heights-1])
+				}
+				done, err = p.Step(1) // One node or batch per step.
+				require.NoError(t, err)
+			}
+			require.Equal(t, countNodes(ps), p.Kept())
+			require.Equal(t, before, p.Kept()+p.Removed())
+			done, err := p.Step(0)
+			require.NoError(t, err)
+			require.True(t, done)
+
+			if mode.RC() && !mode.GC() {
+				// Only the latest state is stored, old nodes are already removed.
+				checkProofs(t, mode, st, roots[heights-1], heights-1)
+				return
+			}
+			require.NotZero(t, p.Removed())
+			for h := heights - retain; h < heights; h++ {
+				checkProofs(t, mode, st, roots[h], h)
+			}
+			_, err = NewTrie(NewHashNode(roots[0]), mode&^ModeGCFlag, st).GetProof(keys[0])
+			require.Error(t, err)
+		})
+	}
+}
@@ -7,14 +7,13 @@ import (
 	"math"
 	"math/big"
 	"sort"
-	"sync/atomic"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/dao"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/runtime"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
-	"github.com/nspcc-dev/neo-go/pkg/core/stateroot"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -33,22 +32,22 @@ type Designate struct {
 	// p2pSigExtensionsEnabled defines whether the P2P signature extensions logic is relevant.
 	p2pSigExtensionsEnabled bool
 
-	OracleService atomic.Value
-	// NotaryService represents a Notary node module.
-	NotaryService atomic.Value
-	// StateRootService represents a StateRoot node module.
-	StateRootService *stateroot.Module
+	// OnRoleChange is called during PostPersist for every role designated
+	// within the persisted block.
+	OnRoleChange func(*state.RoleChangeEvent)
 }
 
 type roleData struct {
 	nodes  keys.PublicKeys
 	addr   util.Uint160
 	height uint32
+	// changed shows whether the role was designated within the current block.
+	changed bool
 }
 
 type DesignationCache struct {
 	// rolesChangedFlag shows whether any of designated nodes were changed within the current block.
-	// It is used to notify dependant services about updated node roles during PostPersist.
+	// It is used to emit role change events during PostPersist.
 	rolesChangedFlag bool
 	oracles          roleData
 	stateVals        roleData
@@ -231,24 +230,22 @@ func (s *Designate) updateCachedRoleData(cache *DesignationCache, d *dao.Simple,
 	v.nodes = nodeKeys
 	v.addr = s.hashFromNodes(r, nodeKeys)
 	v.height = height
+	v.changed = true
 	cache.rolesChangedFlag = true
 	return nil
 }
 
 func (s *Designate) notifyRoleChanged(v *roleData, r noderoles.Role) {
-	switch r {
-	case noderoles.Oracle:
-		if orc, _ := s.OracleService.Load().(*OracleService); orc != nil && *orc != nil {
-			(*orc).UpdateOracleNodes(v.nodes.Copy())
-		}
-	case noderoles.P2PNotary:
-		if ntr, _ := s.NotaryService.Load().(*NotaryService); ntr != nil && *ntr != nil {
-			(*ntr).UpdateNotaryNodes(v.nodes.Copy())
-		}
-	case noderoles.StateValidator:
-		if s.StateRootService != nil {
-			s.StateRootService.UpdateStateValidators(v.height, v.nodes.Copy())
-		}
+	if !v.changed {
+		return
+	}
+	v.changed = false
+	if s.OnRoleChange != nil {
+		s.OnRoleChange(&state.RoleChangeEvent{
+			Role:   r,
+			Nodes:  v.nodes.Copy(),
+			Height: v.height,
+		})
 	}
 }
 
@@ -683,6 +683,67 @@ func (n *NEO) CalculateBonus(d *dao.Simple, acc util.Uint160, end uint32) (*big.
 	return n.calculateBonus(d, st.VoteTo, &st.Balance, st.BalanceHeight, end)
 }
 
+// CalculateBonusDetails is similar to CalculateBonus, but it also returns
+// NEO holder reward for every range of blocks with the same GAS per block
+// value and the voter reward.
+func (n *NEO) CalculateBonusDetails(d *dao.Simple, acc util.Uint160, end uint32) (*state.GASBonus, error) {
+	key := makeAccountKey(acc)
+	si := d.GetStorageItem(n.ID, key)
+	if si == nil {
+		return nil, storage.ErrKeyNotFound
+	}
+	st, err := state.NEOBalanceFromBytes(si)
+	if err != nil {
+		return nil, err
+	}
+	total, err := n.calculateBonus(d, st.VoteTo, &st.Balance, st.BalanceHeight, end)
+	if err != nil {
+		return nil, err
+	}
+	holder, err := n.CalculateNEOHolderReward(d, &st.Balance, st.BalanceHeight, end)
+	if err != nil {
+		return nil, err
+	}
+	res := &state.GASBonus{
+		Total:  *total,
+		VoteTo: st.VoteTo,
+	}
+	res.VoterReward.Sub(total, holder)
+	if st.Balance.Sign() == 0 || st.BalanceHeight >= end {
+		return res, nil
+	}
+	var (
+		gr    = d.GetROCache(n.ID).(*NeoCache).gasPerBlock
+		start = st.BalanceHeight
+		denom = big.NewInt(100 * NEOTotalSupply)
+	)
+	for i := len(gr) - 1; i >= 0 && start < end; i-- {
+		if gr[i].Index >= end {
+			continue
+		}
+		p := state.GASBonusPeriod{
+			Start: gr[i].Index,
+			End:   end,
+		}
+		if p.Start < start {
+			p.Start = start
+		}
+		p.GASPerBlock.Set(&gr[i].GASPerBlock)
+		p.Amount.SetInt64(int64(p.End - p.Start))
+		p.Amount.Mul(&p.Amount, &p.GASPerBlock)
+		p.Amount.Mul(&p.Amount, &st.Balance)
+		p.Amount.Mul(&p.Amount, big.NewInt(neoHolderRewardRatio))
+		p.Amount.Div(&p.Amount, denom)
+		res.Periods = append(res.Periods, p)
+		end = p.Start
+	}
+	// Periods are collected from the newest to the oldest one.
+	for i, j := 0, len(res.Periods)-1; i < j; i, j = i+1, j-1 {
+		res.Periods[i], res.Periods[j] = res.Periods[j], res.Periods[i]
+	}
+	return res, nil
+}
+
 func (n *NEO) calculateBonus(d *dao.Simple, vote *keys.PublicKey, value *big.Int, start, end uint32) (*big.Int, error) {
 	r, err := n.CalculateNEOHolderReward(d, value, start, end)
 	if err != nil || vote == nil {
@@ -1,16 +1,19 @@
 package native_test
 
 import (
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/stateroot"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
-	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -53,57 +56,33 @@ func TestDesignate_DesignateAsRole(t *testing.T) {
 	})
 }
 
-type dummyOracle struct {
-	updateNodes func(k keys.PublicKeys)
-}
-
-// AddRequests processes new requests.
-func (o *dummyOracle) AddRequests(map[uint64]*state.OracleRequest) {
-}
-
-// RemoveRequests removes already processed requests.
-func (o *dummyOracle) RemoveRequests([]uint64) {
-	panic("TODO")
-}
-
-// UpdateOracleNodes updates oracle nodes.
-func (o *dummyOracle) UpdateOracleNodes(k keys.PublicKeys) {
-	if o.updateNodes != nil {
-		o.updateNodes(k)
-		return
-	}
-	panic("TODO")
-}
-
-// UpdateNativeContract updates oracle contract native script and hash.
-func (o *dummyOracle) UpdateNativeContract([]byte, []byte, util.Uint160, int) {
-}
-
-// Start runs oracle module.
-func (o *dummyOracle) Start() {
-	panic("TODO")
-}
-
-// Shutdown shutdowns oracle module.
-func (o *dummyOracle) Shutdown() {
-	panic("TODO")
-}
-
 func TestDesignate_Cache(t *testing.T) {
 	c := newDesignateClient(t)
 	e := c.Executor
 	designateInvoker := c.WithSigners(c.Committee)
 	r := int64(noderoles.Oracle)
-	var (
-		updatedNodes keys.PublicKeys
-		updateCalled bool
-	)
-	oracleServ := &dummyOracle{
-		updateNodes: func(k keys.PublicKeys) {
-			updatedNodes = k
-			updateCalled = true
-		},
+
+	// Role changes are sent before the block, so there can't be any
+	// unread role change once the block is received.
+	roleCh := make(chan *state.RoleChangeEvent, 4)
+	blockCh := make(chan *block.Block, 4)
+	e.Chain.SubscribeForRoleChanges(roleCh)
+	e.Chain.SubscribeForBlocks(blockCh)
+	t.Cleanup(func() {
+		e.Chain.UnsubscribeFromRoleChanges(roleCh)
+		e.Chain.UnsubscribeFromBlocks(blockCh)
+	})
+	waitBlock := func(t *testing.T, index uint32) {
+		require.Eventually(t, func() bool {
+			for len(blockCh) != 0 {
+				if (<-blockCh).Index == index {
+					return true
+				}
+			}
+			return false
+		}, time.Second, 10*time.Millisecond)
 	}
+
 	privGood, err := keys.NewPrivateKey()
 	require.NoError(t, err)
 	pubsGood := []interface{}{privGood.PublicKey().Bytes()}
@@ -112,15 +91,17 @@ func TestDesignate_Cache(t *testing.T) {
 	require.NoError(t, err)
 	pubsBad := []interface{}{privBad.PublicKey().Bytes()}
 
-	// Firstly, designate good Oracle node and check that OracleService callback was called during PostPersist.
-	e.Chain.SetOracle(oracleServ)
+	// Firstly, designate good Oracle node and check that role change event is emitted.
 	txDesignateGood := designateInvoker.PrepareInvoke(t, "designateAsRole", r, pubsGood)
-	e.AddNewBlock(t, txDesignateGood)
+	b := e.AddNewBlock(t, txDesignateGood)
 	e.CheckHalt(t, txDesignateGood.Hash(), stackitem.Null{})
-	require.True(t, updateCalled)
-	require.Equal(t, keys.PublicKeys{privGood.PublicKey()}, updatedNodes)
-	updatedNodes = nil
-	updateCalled = false
+	waitBlock(t, b.Index)
+	require.Equal(t, 1, len(roleCh))
+	require.Equal(t, &state.RoleChangeEvent{
+		Role:   noderoles.Oracle,
+		Nodes:  keys.PublicKeys{privGood.PublicKey()},
+		Height: b.Index + 1,
+	}, <-roleCh)
 
 	// Check designated node in a separate block.
 	checkNodeRoles(t, designateInvoker, true, noderoles.Oracle, e.Chain.BlockHeight()+1, keys.PublicKeys{privGood.PublicKey()})
@@ -134,6 +115,58 @@ func TestDesignate_Cache(t *testing.T) {
 	script := w.Bytes()
 
 	designateInvoker.InvokeScriptCheckFAULT(t, script, designateInvoker.Signers, "ABORT")
-	require.Nil(t, updatedNodes)
-	require.False(t, updateCalled)
+	waitBlock(t, e.Chain.BlockHeight())
+	require.Equal(t, 0, len(roleCh))
+}
+
+func TestDesignate_RoleChangeHeight(t *testing.T) {
+	c := newDesignateClient(t)
+	e := c.Executor
+	designateInvoker := c.WithSigners(c.Committee)
+
+	roleCh := make(chan *state.RoleChangeEvent, 4)
+	e.Chain.SubscribeForRoleChanges(roleCh)
+	t.Cleanup(func() { e.Chain.UnsubscribeFromRoleChanges(roleCh) })
+
+	oracles := make(keys.PublicKeys, 2)
+	for i := range oracles {
+		priv, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		oracles[i] = priv.PublicKey()
+	}
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	stateVals := keys.PublicKeys{priv.PublicKey()}
+
+	// Two roles designated by the same block produce two events.
+	txOracle := designateInvoker.PrepareInvoke(t, "designateAsRole", int64(noderoles.Oracle),
+		[]interface{}{oracles[0].Bytes(), oracles[1].Bytes()})
+	txStateVals := designateInvoker.PrepareInvoke(t, "designateAsRole", int64(noderoles.StateValidator),
+		[]interface{}{stateVals[0].Bytes()})
+	b := e.AddNewBlock(t, txOracle, txStateVals)
+	e.CheckHalt(t, txOracle.Hash(), stackitem.Null{})
+	e.CheckHalt(t, txStateVals.Hash(), stackitem.Null{})
+
+	require.Eventually(t, func() bool { return len(roleCh) == 2 }, time.Second, 10*time.Millisecond)
+	events := map[noderoles.Role]*state.RoleChangeEvent{}
+	for i := 0; i < 2; i++ {
+		ev := <-roleCh
+		events[ev.Role] = ev
+	}
+	sort.Sort(oracles)
+	require.Equal(t, &state.RoleChangeEvent{Role: noderoles.Oracle, Nodes: oracles, Height: b.Index + 1}, events[noderoles.Oracle])
+	require.Equal(t, &state.RoleChangeEvent{Role: noderoles.StateValidator, Nodes: stateVals, Height: b.Index + 1}, events[noderoles.StateValidator])
+
+	// New nodes take effect exactly at the height specified in the event.
+	for _, ev := range events {
+		pubs, _, err := e.Chain.GetDesignatedByRole(ev.Role, ev.Height-1)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(pubs))
+		pubs, h, err := e.Chain.GetDesignatedByRole(ev.Role, ev.Height)
+		require.NoError(t, err)
+		require.Equal(t, ev.Nodes, pubs)
+		require.Equal(t, ev.Height, h)
+	}
+	require.Equal(t, stateVals, e.Chain.GetStateModule().(*stateroot.Module).GetStateValidators(b.Index+1))
+	require.Equal(t, 0, len(e.Chain.GetStateModule().(*stateroot.Module).GetStateValidators(b.Index)))
 }
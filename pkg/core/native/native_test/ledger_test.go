@@ -296,3 +296,40 @@ func TestLedger_GetTransactionSignersInteropAPI(t *testing.T) {
 	ctrInvoker.Invoke(t, 1, "callLedger", false)                                                                    // Firstly, don't access CalledByEnrty Condition value => the call should be successful.
 	ctrInvoker.InvokeFail(t, `(PICKITEM): unhandled exception: "The value 1 is out of range."`, "callLedger", true) // Then, access the value to ensure it will panic.
 }
+
+func TestLedger_GetTransactionFromBlockInteropAPI(t *testing.T) {
+	c := newLedgerClient(t)
+	e := c.Executor
+
+	src := `package callledger
+		import (
+			"github.com/nspcc-dev/neo-go/pkg/interop/native/ledger"
+		)
+		func TxInfo(index, txIndex int) []interface{} {
+			tx := ledger.GetTransactionFromBlock(index, txIndex)
+			if tx == nil {
+				return nil
+			}
+			signers := ledger.GetTransactionSigners(tx.Hash)
+			return []interface{}{tx.Hash, tx.Sender, tx.ValidUntilBlock, len(signers), signers[0].Account, signers[0].Scopes}
+		}`
+	ctr := neotest.CompileSource(t, c.Committee.ScriptHash(), strings.NewReader(src), &compiler.Options{
+		Name: "calledger_contract",
+	})
+	e.DeployContract(t, ctr, nil)
+
+	// Contract reads the block it was deployed in.
+	b := e.GetBlockByIndex(t, int(e.Chain.BlockHeight()))
+	tx := b.Transactions[0]
+	ctrInvoker := e.NewInvoker(ctr.Hash, e.Committee)
+	ctrInvoker.Invoke(t, []stackitem.Item{
+		stackitem.Make(tx.Hash().BytesBE()),
+		stackitem.Make(tx.Sender().BytesBE()),
+		stackitem.Make(tx.ValidUntilBlock),
+		stackitem.Make(len(tx.Signers)),
+		stackitem.Make(tx.Signers[0].Account.BytesBE()),
+		stackitem.Make(int64(tx.Signers[0].Scopes)),
+	}, "txInfo", int64(b.Index), 0)
+	ctrInvoker.InvokeFail(t, "wrong transaction index", "txInfo", int64(b.Index), len(b.Transactions))
+	ctrInvoker.InvokeFail(t, "no block with index", "txInfo", int64(b.Index)+100, 0)
+}
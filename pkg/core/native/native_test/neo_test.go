@@ -533,12 +533,28 @@ func TestNEO_CalculateBonus(t *testing.T) {
 			e.AddNewBlock(t)
 		}
 
+		firstPart := int64(amount*rewardDistance/2*defaultGASParBlock) / int64(rewardDistance)
+		secondPart := int64(amount*rewardDistance/2*newGASPerBlock) / int64(rewardDistance)
+
+		claimHeight := e.Chain.BlockHeight() + 1
+		bonus, err := e.Chain.CalculateClaimableDetails(accH, claimHeight)
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(firstPart+secondPart), &bonus.Total)
+		require.Nil(t, bonus.VoteTo)
+		require.Equal(t, 0, bonus.VoterReward.Sign())
+		require.Equal(t, 2, len(bonus.Periods))
+		require.Equal(t, claimHeight-uint32(rewardDistance), bonus.Periods[0].Start)
+		require.Equal(t, bonus.Periods[0].End, bonus.Periods[1].Start)
+		require.Equal(t, claimHeight, bonus.Periods[1].End)
+		require.Equal(t, big.NewInt(int64(defaultGASParBlock*native.GASFactor)), &bonus.Periods[0].GASPerBlock)
+		require.Equal(t, big.NewInt(int64(newGASPerBlock*native.GASFactor)), &bonus.Periods[1].GASPerBlock)
+		require.Equal(t, big.NewInt(firstPart), &bonus.Periods[0].Amount)
+		require.Equal(t, big.NewInt(secondPart), &bonus.Periods[1].Amount)
+
 		// GAS claim for the last 10 blocks of NEO owning.
 		h := acc.Invoke(t, true, "transfer", accH, accH, amount, nil)
 		claimTx, _ := e.GetTransaction(t, h)
 
-		firstPart := int64(amount*rewardDistance/2*defaultGASParBlock) / int64(rewardDistance)
-		secondPart := int64(amount*rewardDistance/2*newGASPerBlock) / int64(rewardDistance)
 		e.CheckGASBalance(t, accH, big.NewInt(initialGASBalance.Int64()-
 			claimTx.SystemFee-claimTx.NetworkFee + +firstPart + secondPart))
 	})
@@ -53,8 +53,6 @@ type OracleService interface {
 	AddRequests(map[uint64]*state.OracleRequest)
 	// RemoveRequests removes already processed requests.
 	RemoveRequests([]uint64)
-	// UpdateOracleNodes updates oracle nodes.
-	UpdateOracleNodes(keys.PublicKeys)
 	// UpdateNativeContract updates oracle contract native script and hash.
 	UpdateNativeContract([]byte, []byte, util.Uint160, int)
 	// Start runs oracle module.
@@ -182,7 +180,7 @@ func (o *Oracle) PostPersist(ic *interop.Context) error {
 		if resp == nil {
 			continue
 		}
-		reqKey := makeRequestKey(resp.ID)
+		reqKey := MakeOracleRequestKey(resp.ID)
 		req := new(state.OracleRequest)
 		if err := o.getConvertibleFromDAO(ic.DAO, reqKey, req); err != nil {
 			continue
@@ -416,7 +414,7 @@ func (o *Oracle) RequestInternal(ic *interop.Context, url string, filter *string
 
 // PutRequestInternal puts the oracle request with the specified id to d.
 func (o *Oracle) PutRequestInternal(id uint64, req *state.OracleRequest, d *dao.Simple) error {
-	reqKey := makeRequestKey(id)
+	reqKey := MakeOracleRequestKey(id)
 	if err := putConvertibleToDAO(o.ID, d, reqKey, req); err != nil {
 		return err
 	}
@@ -451,7 +449,7 @@ func (o *Oracle) GetOracleNodes(d *dao.Simple) (keys.PublicKeys, error) {
 
 // GetRequestInternal returns the request by ID and key under which it is stored.
 func (o *Oracle) GetRequestInternal(d *dao.Simple, id uint64) (*state.OracleRequest, error) {
-	key := makeRequestKey(id)
+	key := MakeOracleRequestKey(id)
 	req := new(state.OracleRequest)
 	return req, o.getConvertibleFromDAO(d, key, req)
 }
@@ -525,7 +523,9 @@ func (o *Oracle) GetRequests(d *dao.Simple) (map[uint64]*state.OracleRequest, er
 	return reqs, nil
 }
 
-func makeRequestKey(id uint64) []byte {
+// MakeOracleRequestKey creates a native Oracle contract storage key for the
+// request with the given ID.
+func MakeOracleRequestKey(id uint64) []byte {
 	k := make([]byte, 9)
 	k[0] = prefixRequest[0]
 	binary.BigEndian.PutUint64(k[1:], id)
@@ -550,7 +550,7 @@ func (o *Oracle) updateCache(d *dao.Simple) error {
 	reqs := o.newRequests
 	o.newRequests = make(map[uint64]*state.OracleRequest)
 	for id := range reqs {
-		key := makeRequestKey(id)
+		key := MakeOracleRequestKey(id)
 		if si := d.GetStorageItem(o.ID, key); si == nil { // tx has failed
 			delete(reqs, id)
 		}
@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
@@ -165,8 +167,8 @@ func (s *Std) serialize(ic *interop.Context, args []stackitem.Item) stackitem.It
 	if err != nil {
 		panic(err)
 	}
-	if len(data) > stackitem.MaxSize {
-		panic(errors.New("too big item"))
+	if len(data) > ic.VM.Limits().MaxItemSize {
+		panic(fmt.Errorf("%w: %d bytes", vm.ErrItemTooBig, len(data)))
 	}
 
 	return stackitem.NewByteArray(slice.Copy(data)) // Serialization context can be reused.
@@ -186,25 +188,22 @@ func (s *Std) deserialize(_ *interop.Context, args []stackitem.Item) stackitem.I
 	return item
 }
 
-func (s *Std) jsonSerialize(_ *interop.Context, args []stackitem.Item) stackitem.Item {
-	data, err := stackitem.ToJSON(args[0])
+func (s *Std) jsonSerialize(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	data, err := stackitem.ToJSONWithLimit(args[0], ic.VM.Limits().MaxItemSize)
 	if err != nil {
 		panic(err)
 	}
-	if len(data) > stackitem.MaxSize {
-		panic(errors.New("too big item"))
-	}
 
 	return stackitem.NewByteArray(data)
 }
 
-func (s *Std) jsonDeserialize(_ *interop.Context, args []stackitem.Item) stackitem.Item {
+func (s *Std) jsonDeserialize(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	data, err := args[0].TryBytes()
 	if err != nil {
 		panic(err)
 	}
 
-	item, err := stackitem.FromJSON(data, stackitem.MaxDeserialized)
+	item, err := stackitem.FromJSONWithLimits(data, stackitem.MaxDeserialized, ic.VM.Limits().MaxNestingDepth)
 	if err != nil {
 		panic(err)
 	}
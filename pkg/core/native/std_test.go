@@ -388,6 +388,62 @@ func TestStdLibSerializeDeserialize(t *testing.T) {
 	})
 }
 
+func TestStdLibLimits(t *testing.T) {
+	s := newStd()
+	ic := &interop.Context{VM: vm.New(), DAO: &dao.Simple{}}
+	ic.VM.SetLimits(vm.Limits{MaxItemSize: 16, MaxNestingDepth: 2})
+
+	call := func(f func(*interop.Context, []stackitem.Item) stackitem.Item, arg stackitem.Item) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = r.(error)
+			}
+		}()
+		f(ic, []stackitem.Item{arg})
+		return nil
+	}
+	nested := func(depth int) stackitem.Item {
+		return stackitem.Make(strings.Repeat("[", depth) + "1" + strings.Repeat("]", depth))
+	}
+	testCases := []struct {
+		name    string
+		method  func(*interop.Context, []stackitem.Item) stackitem.Item
+		atLimit stackitem.Item
+		over    stackitem.Item
+		err     error
+	}{
+		{
+			// Type, length and data.
+			name:    "serialize",
+			method:  s.serialize,
+			atLimit: stackitem.NewByteArray(make([]byte, 14)),
+			over:    stackitem.NewByteArray(make([]byte, 15)),
+			err:     vm.ErrItemTooBig,
+		},
+		{
+			// Quoted string.
+			name:    "jsonSerialize",
+			method:  s.jsonSerialize,
+			atLimit: stackitem.Make(strings.Repeat("a", 14)),
+			over:    stackitem.Make(strings.Repeat("a", 15)),
+			err:     vm.ErrItemTooBig,
+		},
+		{
+			name:    "jsonDeserialize",
+			method:  s.jsonDeserialize,
+			atLimit: nested(2),
+			over:    nested(3),
+			err:     vm.ErrTooDeep,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, call(tc.method, tc.atLimit))
+			require.ErrorIs(t, call(tc.method, tc.over), tc.err)
+		})
+	}
+}
+
 func TestMemoryCompare(t *testing.T) {
 	s := newStd()
 	ic := &interop.Context{VM: vm.New(), DAO: &dao.Simple{}}
@@ -23,6 +23,36 @@ type NEOBalance struct {
 	VoteTo        *keys.PublicKey
 }
 
+// GASBonus is a breakdown of GAS generated for some NEO account (but not yet
+// claimed by it).
+type GASBonus struct {
+	// Total is the amount of GAS that will be distributed to the account.
+	Total big.Int
+	// Periods contain NEO holder reward for every range of blocks with the
+	// same amount of GAS generated per block.
+	Periods []GASBonusPeriod
+	// VoteTo is the candidate the account votes for (nil if there is none).
+	// Votes can't change without claiming GAS, so the account votes for it
+	// during all of the periods.
+	VoteTo *keys.PublicKey
+	// VoterReward is the part of Total generated for voting.
+	VoterReward big.Int
+}
+
+// GASBonusPeriod is the NEO holder reward for some range of blocks. Notice that
+// every period amount is rounded down separately, so their sum can be
+// a bit less than the holder reward included into GASBonus.Total.
+type GASBonusPeriod struct {
+	// Start is the first block of the period.
+	Start uint32
+	// End is the block following the last block of the period.
+	End uint32
+	// GASPerBlock is the amount of GAS generated per block during the period.
+	GASPerBlock big.Int
+	// Amount is the reward for holding NEO during the period.
+	Amount big.Int
+}
+
 // NEP17BalanceFromBytes converts the serialized NEP17Balance to a structure.
 func NEP17BalanceFromBytes(b []byte) (*NEP17Balance, error) {
 	if len(b) < 4 {
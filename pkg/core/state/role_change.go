@@ -0,0 +1,17 @@
+package state
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// RoleChangeEvent represents a change of the node list designated for some
+// role by the RoleManagement native contract.
+type RoleChangeEvent struct {
+	Role noderoles.Role
+	// Nodes is the new list of nodes designated for the role.
+	Nodes keys.PublicKeys
+	// Height is the height the new node list is effective from, it's
+	// the next one after the block containing the designation.
+	Height uint32
+}
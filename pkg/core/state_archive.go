@@ -0,0 +1,104 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+)
+
+// StateArchive is a contiguous range of heights full state (MPT) is
+// available for, historic calls can only be made against these states.
+type StateArchive struct {
+	// Start is the lowest height state is available for.
+	Start uint32
+	// End is the highest height state is available for, it's the current
+	// local state height.
+	End uint32
+}
+
+// StateUnavailableError is returned for historic calls when the requested state
+// is not available (it's either pruned or not yet known).
+type StateUnavailableError struct {
+	// Height is the requested state height.
+	Height uint32
+	// Nearest is the nearest height state is available for.
+	Nearest uint32
+}
+
+// Error implements the error interface.
+func (e *StateUnavailableError) Error() string {
+	return fmt.Sprintf("state for height %d is not available, nearest available height is %d", e.Height, e.Nearest)
+}
+
+// Contains checks whether state for the given height is available.
+func (a StateArchive) Contains(height uint32) bool {
+	return a.Start <= height && height <= a.End
+}
+
+// Nearest returns the height from the range that is the nearest to the given
+// one.
+func (a StateArchive) Nearest(height uint32) uint32 {
+	if height < a.Start {
+		return a.Start
+	}
+	if height > a.End {
+		return a.End
+	}
+	return height
+}
+
+// GetStateArchive returns the range of heights full state is available for. It
+// takes into account RemoveUntraceableBlocks and state pruning (both automatic
+// and manual) settings, false is returned if old states are not kept at all
+// (KeepOnlyLatestState).
+func (bc *Blockchain) GetStateArchive() (StateArchive, bool) {
+	if bc.config.KeepOnlyLatestState {
+		return StateArchive{}, false
+	}
+	var a = StateArchive{
+		Start: atomic.LoadUint32(&bc.prunedHeight),
+		End:   bc.stateRoot.CurrentLocalHeight(),
+	}
+	if bc.config.RemoveUntraceableBlocks {
+		if h := bc.BlockHeight(); h > bc.config.MaxTraceableBlocks && h-bc.config.MaxTraceableBlocks-1 > a.Start {
+			a.Start = h - bc.config.MaxTraceableBlocks - 1
+		}
+	}
+	if a.Start > a.End {
+		a.Start = a.End
+	}
+	return a, true
+}
+
+// setPrunedHeight stores the lowest height state is retained for after
+// pruning, it's called before pruning is started. It's not reverted if pruning
+// fails, some of the older states may be removed already.
+func (bc *Blockchain) setPrunedHeight(height uint32) {
+	if height <= atomic.LoadUint32(&bc.prunedHeight) {
+		return
+	}
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], height)
+	bc.dao.Store.Put([]byte{byte(storage.SYSPrunedHeight)}, buf[:])
+	atomic.StoreUint32(&bc.prunedHeight, height)
+}
+
+// initPrunedHeight restores the lowest height state is retained for after
+// pruning from the storage.
+func (bc *Blockchain) initPrunedHeight() error {
+	data, err := bc.dao.Store.Get([]byte{byte(storage.SYSPrunedHeight)})
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+	if len(data) != 4 {
+		return fmt.Errorf("invalid pruned height length %d", len(data))
+	}
+	atomic.StoreUint32(&bc.prunedHeight, binary.LittleEndian.Uint32(data))
+	return nil
+}
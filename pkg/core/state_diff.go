@@ -0,0 +1,63 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// StorageDiff is a change of a single contract storage item between two
+// state heights.
+type StorageDiff struct {
+	// ID is the contract ID.
+	ID int32
+	// Key is the storage item key.
+	Key []byte
+	// Old is the item value at the first height, it's nil for added items.
+	Old []byte
+	// New is the item value at the second height, it's nil for deleted items.
+	New []byte
+}
+
+// GetStateDiff calls f for every contract storage item that differs between
+// states at the given heights, only items of the contract with the given ID are
+// processed if id is not nil. Items are processed in MPT key order (contract
+// ID in little-endian encoding followed by item key), so the result is
+// deterministic. Both heights must be within the state archive (see
+// GetStateArchive), *StateUnavailableError is returned otherwise. The walk is
+// stopped if f returns false.
+func (bc *Blockchain) GetStateDiff(from, to uint32, id *int32, f func(*StorageDiff) bool) error {
+	archive, ok := bc.GetStateArchive()
+	if !ok {
+		return errors.New("old states are not kept (KeepOnlyLatestState is on)")
+	}
+	var roots = make([]util.Uint256, 0, 2)
+	for _, h := range []uint32{from, to} {
+		if !archive.Contains(h) {
+			return &StateUnavailableError{Height: h, Nearest: archive.Nearest(h)}
+		}
+		sr, err := bc.stateRoot.GetStateRoot(h)
+		if err != nil {
+			return fmt.Errorf("failed to get state root for height %d: %w", h, err)
+		}
+		roots = append(roots, sr.Root)
+	}
+	var prefix []byte
+	if id != nil {
+		prefix = make([]byte, 4)
+		binary.LittleEndian.PutUint32(prefix, uint32(*id))
+	}
+	return bc.stateRoot.DiffStates(roots[0], roots[1], prefix, func(key, oldValue, newValue []byte) bool {
+		if len(key) < 4 {
+			return true // Not a storage item.
+		}
+		return f(&StorageDiff{
+			ID:  int32(binary.LittleEndian.Uint32(key)),
+			Key: key[4:],
+			Old: oldValue,
+			New: newValue,
+		})
+	})
+}
@@ -39,10 +39,10 @@ type (
 		localHeight     atomic.Uint32
 		validatedHeight atomic.Uint32
 
+		mismatch atomic.Value
+
 		mtx  sync.RWMutex
 		keys []keyCache
-
-		updateValidatorsCb func(height uint32, publicKeys keys.PublicKeys)
 	}
 
 	keyCache struct {
@@ -90,6 +90,13 @@ func (s *Module) FindStates(root util.Uint256, prefix, start []byte, max int) ([
 	return tr.Find(prefix, start, max)
 }
 
+// DiffStates calls f for every key with the given prefix that has different
+// values in MPTs with the specified roots, see mpt.Diff for details.
+func (s *Module) DiffStates(oldRoot, newRoot util.Uint256, prefix []byte, f mpt.DiffFunc) error {
+	// Allow accessing old values, it's RO thing.
+	return mpt.Diff(storage.NewMemCachedStore(s.Store), s.mode&^mpt.ModeGCFlag, oldRoot, newRoot, prefix, f)
+}
+
 // GetStateProof returns proof of having key in the MPT with the specified root.
 func (s *Module) GetStateProof(root util.Uint256, key []byte) ([][]byte, error) {
 	// Allow accessing old values, it's RO thing.
@@ -2,19 +2,68 @@ package stateroot
 
 import "github.com/prometheus/client_golang/prometheus"
 
-// stateHeight prometheus metric.
-var stateHeight = prometheus.NewGauge(
-	prometheus.GaugeOpts{
-		Help:      "Current verified state height",
-		Name:      "current_state_height",
-		Namespace: "neogo",
-	},
+// Metrics used in monitoring service.
+var (
+	stateHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Current verified state height",
+			Name:      "current_state_height",
+			Namespace: "neogo",
+		},
+	)
+	mismatchHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Height of local and validated state roots mismatch block processing was halted at (0 if not halted)",
+			Name:      "stateroot_mismatch_height",
+			Namespace: "neogo",
+		},
+	)
+	pruningMarkedNodes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of MPT nodes marked as reachable by the current state pruning cycle",
+			Name:      "state_pruning_marked_nodes",
+			Namespace: "neogo",
+		},
+	)
+	pruningRemovedNodes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of MPT nodes removed by state pruning",
+			Name:      "state_pruning_removed_nodes_total",
+			Namespace: "neogo",
+		},
+	)
+	prunedHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "The lowest state height retained by the last completed state pruning cycle",
+			Name:      "state_pruning_height",
+			Namespace: "neogo",
+		},
+	)
 )
 
 func init() {
-	prometheus.MustRegister(stateHeight)
+	prometheus.MustRegister(
+		stateHeight,
+		mismatchHeight,
+		pruningMarkedNodes,
+		pruningRemovedNodes,
+		prunedHeight,
+	)
 }
 
 func updateStateHeightMetric(sHeight uint32) {
 	stateHeight.Set(float64(sHeight))
 }
+
+func updateMismatchHeightMetric(height uint32) {
+	mismatchHeight.Set(float64(height))
+}
+
+func updatePruningMetrics(marked int, removed int) {
+	pruningMarkedNodes.Set(float64(marked))
+	pruningRemovedNodes.Add(float64(removed))
+}
+
+func updatePrunedHeightMetric(height uint32) {
+	prunedHeight.Set(float64(height))
+}
@@ -0,0 +1,65 @@
+package stateroot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"go.uber.org/zap"
+)
+
+// Pruner removes MPT nodes that are not reachable from state roots starting
+// from some height, it's a wrapper over mpt.Pruner retaining all state roots
+// added since the pruning start. It's not thread-safe.
+type Pruner struct {
+	module  *Module
+	p       *mpt.Pruner
+	from    uint32
+	next    uint32
+	start   time.Time
+	removed int
+}
+
+// NewPruner returns a new Pruner retaining state roots starting from the
+// given height. Nodes are removed from the given store which is supposed to
+// be the one the Module's Store is persisted to.
+func (s *Module) NewPruner(from uint32, store storage.Store) *Pruner {
+	s.log.Info("starting MPT pruning", zap.Uint32("from", from))
+	return &Pruner{
+		module: s,
+		p:      mpt.NewPruner(s.Store, store),
+		from:   from,
+		next:   from,
+		start:  time.Now(),
+	}
+}
+
+// Step marks state roots added since the previous step as retained and
+// performs pruning for at most the given time (zero means no limit), it
+// returns true when pruning is completed.
+func (p *Pruner) Step(budget time.Duration) (bool, error) {
+	for h := p.module.CurrentLocalHeight(); p.next <= h; p.next++ {
+		sr, err := p.module.GetStateRoot(p.next)
+		if err != nil {
+			return false, fmt.Errorf("failed to get state root %d: %w", p.next, err)
+		}
+		p.p.AddRoot(sr.Root)
+	}
+	done, err := p.p.Step(budget)
+	if err != nil {
+		p.module.log.Error("MPT pruning failed", zap.Error(err))
+		return false, err
+	}
+	updatePruningMetrics(p.p.Marked(), p.p.Removed()-p.removed)
+	p.removed = p.p.Removed()
+	if done {
+		updatePrunedHeightMetric(p.from)
+		p.module.log.Info("finished MPT pruning",
+			zap.Uint32("from", p.from),
+			zap.Int("removed", p.p.Removed()),
+			zap.Int("kept", p.p.Kept()),
+			zap.Duration("time", time.Since(p.start)))
+	}
+	return done, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
 var (
@@ -16,6 +17,14 @@ var (
 	ErrStateMismatch = errors.New("stateroot mismatch")
 )
 
+// Mismatch describes the difference between local state root and the one
+// signed by state validators.
+type Mismatch struct {
+	Height    uint32
+	Local     util.Uint256
+	Validated util.Uint256
+}
+
 const (
 	prefixLocal     = 0x02
 	prefixValidated = 0x03
@@ -82,3 +91,20 @@ func (s *Module) AddStateRoot(sr *state.MPTRoot) error {
 	}
 	return nil
 }
+
+// Halt marks the module as halted because of the given state root mismatch,
+// Blockchain refuses to accept new blocks after that. Only the first mismatch
+// is stored, subsequent calls are no-op.
+func (s *Module) Halt(m *Mismatch) {
+	if !s.mismatch.CompareAndSwap(nil, m) {
+		return
+	}
+	updateMismatchHeightMetric(m.Height)
+}
+
+// Halted returns the state root mismatch the module was halted with or nil if
+// it's not halted.
+func (s *Module) Halted() *Mismatch {
+	m, _ := s.mismatch.Load().(*Mismatch)
+	return m
+}
@@ -12,9 +12,6 @@ func (s *Module) UpdateStateValidators(height uint32, pubs keys.PublicKeys) {
 	h := hash.Hash160(script)
 
 	s.mtx.Lock()
-	if s.updateValidatorsCb != nil {
-		s.updateValidatorsCb(height, pubs)
-	}
 	kc := s.getKeyCacheForHeight(height)
 	if kc.validatorsHash != h {
 		s.keys = append(s.keys, keyCache{
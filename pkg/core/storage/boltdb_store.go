@@ -19,6 +19,8 @@ var Bucket = []byte("DB")
 // blockchain data.
 type BoltDBStore struct {
 	db *bbolt.DB
+	// lockInfo is the path to the lock data file (if it's written).
+	lockInfo string
 }
 
 // NewBoltDBStore returns a new ready to use BoltDB storage with created bucket.
@@ -27,6 +29,8 @@ func NewBoltDBStore(cfg dbconfig.BoltDBOptions) (*BoltDBStore, error) {
 	opts := &cp
 	fileMode := os.FileMode(0600) // should be exposed via BoltDBOptions if anything needed
 	fileName := cfg.FilePath
+	lockInfo := fileName + boltDBLockInfoSuffix
+	opts.Timeout = boltDBLockTimeout // Don't wait for the lock forever.
 	if cfg.ReadOnly {
 		opts.ReadOnly = true
 	} else {
@@ -36,6 +40,9 @@ func NewBoltDBStore(cfg dbconfig.BoltDBOptions) (*BoltDBStore, error) {
 	}
 	db, err := bbolt.Open(fileName, fileMode, opts)
 	if err != nil {
+		if isLockHeld(err) {
+			err = newLockError(fileName, lockInfo, err)
+		}
 		return nil, fmt.Errorf("failed to open BoltDB instance: %w", err)
 	}
 	if opts.ReadOnly {
@@ -64,7 +71,14 @@ func NewBoltDBStore(cfg dbconfig.BoltDBOptions) (*BoltDBStore, error) {
 		return nil, err
 	}
 
-	return &BoltDBStore{db: db}, nil
+	if opts.ReadOnly {
+		lockInfo = ""
+	} else if err = writeLockInfo(lockInfo); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &BoltDBStore{db: db, lockInfo: lockInfo}, nil
 }
 
 // Get implements the Store interface.
@@ -167,5 +181,11 @@ func boltSeek(txopener func(func(*bbolt.Tx) error) error, rng SeekRange, f func(
 
 // Close releases all db resources.
 func (s *BoltDBStore) Close() error {
-	return s.db.Close()
+	// Lock data is removed while the lock is still held.
+	err := removeLockInfo(s.lockInfo)
+	closeErr := s.db.Close()
+	if closeErr != nil {
+		return closeErr
+	}
+	return err
 }
@@ -4,11 +4,12 @@ Package dbconfig is a micropackage that contains storage DB configuration option
 package dbconfig
 
 type (
-	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'boltDB'.
+	// DBConfiguration describes configuration for DB. Supported: 'levelDB', 'boltDB', 'pebbleDB'.
 	DBConfiguration struct {
-		Type           string         `yaml:"Type"`
-		LevelDBOptions LevelDBOptions `yaml:"LevelDBOptions"`
-		BoltDBOptions  BoltDBOptions  `yaml:"BoltDBOptions"`
+		Type            string          `yaml:"Type"`
+		LevelDBOptions  LevelDBOptions  `yaml:"LevelDBOptions"`
+		BoltDBOptions   BoltDBOptions   `yaml:"BoltDBOptions"`
+		PebbleDBOptions PebbleDBOptions `yaml:"PebbleDBOptions"`
 	}
 	// LevelDBOptions configuration for LevelDB.
 	LevelDBOptions struct {
@@ -20,4 +21,18 @@ type (
 		FilePath string `yaml:"FilePath"`
 		ReadOnly bool   `yaml:"ReadOnly"`
 	}
+	// PebbleDBOptions configuration for PebbleDB.
+	PebbleDBOptions struct {
+		DataDirectoryPath string `yaml:"DataDirectoryPath"`
+		ReadOnly          bool   `yaml:"ReadOnly"`
+		// CacheSize is the block cache size in bytes, Pebble default is
+		// used if it's zero.
+		CacheSize int64 `yaml:"CacheSize"`
+		// WALDir is the directory to store write-ahead log in, it's
+		// stored in DataDirectoryPath if empty.
+		WALDir string `yaml:"WALDir"`
+		// DisableWAL disables write-ahead log, changes are still written
+		// atomically, but the latest of them can be lost on crash.
+		DisableWAL bool `yaml:"DisableWAL"`
+	}
 )
@@ -8,4 +8,6 @@ const (
 	LevelDB = "leveldb"
 	// InMemoryDB represents in-memory storage name.
 	InMemoryDB = "inmemory"
+	// PebbleDB represents Pebble DB storage name.
+	PebbleDB = "pebbledb"
 )
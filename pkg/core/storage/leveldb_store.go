@@ -3,6 +3,7 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/syndtr/goleveldb/leveldb"
@@ -16,6 +17,8 @@ import (
 type LevelDBStore struct {
 	db   *leveldb.DB
 	path string
+	// lockInfo is the path to the lock data file (if it's written).
+	lockInfo string
 }
 
 // NewLevelDBStore returns a new LevelDBStore object that will
@@ -27,14 +30,25 @@ func NewLevelDBStore(cfg dbconfig.LevelDBOptions) (*LevelDBStore, error) {
 		opts.ErrorIfMissing = true
 	}
 	opts.Filter = filter.NewBloomFilter(10)
+	lockInfo := filepath.Join(cfg.DataDirectoryPath, levelDBLockInfoFile)
 	db, err := leveldb.OpenFile(cfg.DataDirectoryPath, opts)
 	if err != nil {
+		if isLockHeld(err) {
+			err = newLockError(cfg.DataDirectoryPath, lockInfo, err)
+		}
 		return nil, fmt.Errorf("failed to open LevelDB instance: %w", err)
 	}
+	if cfg.ReadOnly {
+		lockInfo = ""
+	} else if err = writeLockInfo(lockInfo); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
 
 	return &LevelDBStore{
-		path: cfg.DataDirectoryPath,
-		db:   db,
+		path:     cfg.DataDirectoryPath,
+		db:       db,
+		lockInfo: lockInfo,
 	}, nil
 }
 
@@ -72,7 +86,7 @@ func (s *LevelDBStore) PutChangeSet(puts map[string][]byte, stores map[string][]
 // Seek implements the Store interface.
 func (s *LevelDBStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
 	iter := s.db.NewIterator(seekRangeToPrefixes(rng), nil)
-	s.seek(iter, rng.Backwards, f)
+	seekLevelDB(iter, rng.Backwards, f)
 }
 
 // SeekGC implements the Store interface.
@@ -82,7 +96,7 @@ func (s *LevelDBStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error
 		return err
 	}
 	iter := tx.NewIterator(seekRangeToPrefixes(rng), nil)
-	s.seek(iter, rng.Backwards, func(k, v []byte) bool {
+	seekLevelDB(iter, rng.Backwards, func(k, v []byte) bool {
 		if !keep(k, v) {
 			err = tx.Delete(k, nil)
 			if err != nil {
@@ -97,7 +111,7 @@ func (s *LevelDBStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error
 	return tx.Commit()
 }
 
-func (s *LevelDBStore) seek(iter iterator.Iterator, backwards bool, f func(k, v []byte) bool) {
+func seekLevelDB(iter iterator.Iterator, backwards bool, f func(k, v []byte) bool) {
 	var (
 		next func() bool
 		ok   bool
@@ -119,7 +133,59 @@ func (s *LevelDBStore) seek(iter iterator.Iterator, backwards bool, f func(k, v
 	iter.Release()
 }
 
+// Snapshot implements the Snapshotter interface.
+func (s *LevelDBStore) Snapshot() (Store, error) {
+	snap, err := s.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &levelDBSnapshot{snap: snap}, nil
+}
+
 // Close implements the Store interface.
 func (s *LevelDBStore) Close() error {
-	return s.db.Close()
+	// Lock data is removed while the lock is still held.
+	err := removeLockInfo(s.lockInfo)
+	closeErr := s.db.Close()
+	if closeErr != nil {
+		return closeErr
+	}
+	return err
+}
+
+// levelDBSnapshot is a read-only Store backed by LevelDB snapshot.
+type levelDBSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+// Get implements the Store interface.
+func (s *levelDBSnapshot) Get(key []byte) ([]byte, error) {
+	value, err := s.snap.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		err = ErrKeyNotFound
+	}
+	return value, err
+}
+
+// PutChangeSet implements the Store interface. Always returns an error.
+func (s *levelDBSnapshot) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
+	return errReadOnlySnapshot
+}
+
+// Seek implements the Store interface.
+func (s *levelDBSnapshot) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	iter := s.snap.NewIterator(seekRangeToPrefixes(rng), nil)
+	seekLevelDB(iter, rng.Backwards, f)
+}
+
+// SeekGC implements the Store interface. Always returns an error.
+func (s *levelDBSnapshot) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	return errReadOnlySnapshot
+}
+
+// Close implements the Store interface, it releases the snapshot. Never
+// returns an error.
+func (s *levelDBSnapshot) Close() error {
+	s.snap.Release()
+	return nil
 }
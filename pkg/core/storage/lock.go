@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// levelDBLockInfoFile is the name of the lock data file stored in the
+	// LevelDB directory.
+	levelDBLockInfoFile = "LOCK.info"
+	// pebbleDBLockInfoFile is the name of the lock data file stored in the
+	// PebbleDB directory.
+	pebbleDBLockInfoFile = "LOCK.info"
+	// boltDBLockInfoSuffix is the suffix added to the BoltDB file name to
+	// get the lock data file name.
+	boltDBLockInfoSuffix = ".lock"
+	// boltDBLockTimeout is the time BoltDB tries to get the file lock for
+	// before returning an error.
+	boltDBLockTimeout = 100 * time.Millisecond
+	// lockRetryInterval is the interval between attempts to open a locked DB.
+	lockRetryInterval = 100 * time.Millisecond
+)
+
+// ErrLocked is returned when the DB is locked by another process (or another
+// handle in the same process).
+var ErrLocked = errors.New("database is locked by another process")
+
+type (
+	// LockInfo describes the process holding the DB lock, it's stored
+	// alongside the DB when it's opened in read-write mode.
+	LockInfo struct {
+		PID      int       `json:"pid"`
+		Hostname string    `json:"hostname"`
+		Since    time.Time `json:"since"`
+	}
+
+	// LockError is returned when the DB can't be opened because of the lock
+	// held by someone else, it matches ErrLocked with errors.Is.
+	LockError struct {
+		// Path is the DB path.
+		Path string
+		// Holder is the lock holder data if it's known.
+		Holder *LockInfo
+		// Err is the original error returned by the DB.
+		Err error
+	}
+)
+
+// Error implements the error interface.
+func (e *LockError) Error() string {
+	var holder string
+	if e.Holder != nil {
+		holder = fmt.Sprintf(" (PID %d on %s since %s)", e.Holder.PID, e.Holder.Hostname, e.Holder.Since.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("database %s is locked by another process%s, make sure no other instance uses it "+
+		"or use --wait-for-lock to wait for it, if the holder is not running anymore use 'db unlock' command: %v",
+		e.Path, holder, e.Err)
+}
+
+// Unwrap returns the original error.
+func (e *LockError) Unwrap() error {
+	return e.Err
+}
+
+// Is allows to match LockError with ErrLocked.
+func (e *LockError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// isLockHeld checks whether the error returned from the DB open function
+// means that the lock is held by someone else.
+func isLockHeld(err error) bool {
+	return isOSLockError(err) || errors.Is(err, bbolt.ErrTimeout)
+}
+
+// newLockError creates LockError for the DB with the given lock data file.
+func newLockError(path string, lockInfoPath string, err error) error {
+	holder, _ := ReadLockInfo(lockInfoPath)
+	return &LockError{
+		Path:   path,
+		Holder: holder,
+		Err:    err,
+	}
+}
+
+// LockInfoPath returns the path to the lock data file of the given DB, it's
+// empty for in-memory DB.
+func LockInfoPath(cfg dbconfig.DBConfiguration) string {
+	switch cfg.Type {
+	case dbconfig.LevelDB:
+		return filepath.Join(cfg.LevelDBOptions.DataDirectoryPath, levelDBLockInfoFile)
+	case dbconfig.BoltDB:
+		return cfg.BoltDBOptions.FilePath + boltDBLockInfoSuffix
+	case dbconfig.PebbleDB:
+		return filepath.Join(cfg.PebbleDBOptions.DataDirectoryPath, pebbleDBLockInfoFile)
+	default:
+		return ""
+	}
+}
+
+// ReadLockInfo reads the lock data from the given file.
+func ReadLockInfo(path string) (*LockInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info := new(LockInfo)
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("invalid lock data in %s: %w", path, err)
+	}
+	return info, nil
+}
+
+// writeLockInfo stores the current process data in the given file, it must
+// only be called with the DB lock held.
+func writeLockInfo(path string) error {
+	host, _ := os.Hostname()
+	data, err := json.Marshal(LockInfo{
+		PID:      os.Getpid(),
+		Hostname: host,
+		Since:    time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write lock data: %w", err)
+	}
+	return nil
+}
+
+// removeLockInfo removes the lock data file if it exists.
+func removeLockInfo(path string) error {
+	if path == "" {
+		return nil
+	}
+	err := os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove lock data: %w", err)
+	}
+	return nil
+}
+
+// NewStoreWithLockWait is similar to NewStore, but if the DB is locked by
+// another process it retries to open it until the lock is released or the
+// specified time passes.
+func NewStoreWithLockWait(cfg dbconfig.DBConfiguration, wait time.Duration) (Store, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		store, err := NewStore(cfg)
+		if err == nil || !errors.Is(err, ErrLocked) || !time.Now().Before(deadline) {
+			return store, err
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// Unlock removes stale lock data left by the process that hasn't closed the
+// DB properly (the lock itself is managed by OS and released when the
+// process exits). It refuses to do so if the recorded process is still
+// running, if it runs on another host (so that it can't be checked) or if the
+// DB lock is held by anyone.
+func Unlock(cfg dbconfig.DBConfiguration) error {
+	path := LockInfoPath(cfg)
+	if path == "" {
+		return fmt.Errorf("%s DB can't be locked", cfg.Type)
+	}
+	info, err := ReadLockInfo(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no lock data found at %s", path)
+	}
+	// Invalid lock data can't be checked, but it's still removed if nobody
+	// holds the DB lock.
+	if info != nil {
+		host, _ := os.Hostname()
+		if info.Hostname != host {
+			return fmt.Errorf("lock is held by PID %d on %s, it can't be checked from %s", info.PID, info.Hostname, host)
+		}
+		if processExists(info.PID) {
+			return fmt.Errorf("lock holder (PID %d) is still running", info.PID)
+		}
+	}
+	// Opening the DB ensures nobody holds the lock, closing it removes
+	// the lock data.
+	store, err := NewStore(cfg)
+	if err != nil {
+		return err
+	}
+	return store.Close()
+}
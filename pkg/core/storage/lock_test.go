@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func newLockTestConfigs(t *testing.T) map[string]dbconfig.DBConfiguration {
+	return map[string]dbconfig.DBConfiguration{
+		dbconfig.LevelDB: {
+			Type:           dbconfig.LevelDB,
+			LevelDBOptions: dbconfig.LevelDBOptions{DataDirectoryPath: t.TempDir()},
+		},
+		dbconfig.BoltDB: {
+			Type:          dbconfig.BoltDB,
+			BoltDBOptions: dbconfig.BoltDBOptions{FilePath: filepath.Join(t.TempDir(), "bolt.db")},
+		},
+	}
+}
+
+// deadPID returns the PID of the process that has already exited.
+func deadPID(t *testing.T) int {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	return cmd.ProcessState.Pid()
+}
+
+func writeTestLockInfo(t *testing.T, path string, info LockInfo) {
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestDBLock(t *testing.T) {
+	for typ, cfg := range newLockTestConfigs(t) {
+		cfg := cfg
+		t.Run(typ, func(t *testing.T) {
+			lockInfo := LockInfoPath(cfg)
+			store, err := NewStore(cfg)
+			require.NoError(t, err)
+
+			info, err := ReadLockInfo(lockInfo)
+			require.NoError(t, err)
+			require.Equal(t, os.Getpid(), info.PID)
+
+			_, err = NewStore(cfg)
+			require.True(t, errors.Is(err, ErrLocked))
+			var lockErr *LockError
+			require.True(t, errors.As(err, &lockErr))
+			require.Equal(t, info, lockErr.Holder)
+
+			require.Error(t, Unlock(cfg)) // Holder is alive.
+
+			go func() {
+				time.Sleep(3 * lockRetryInterval)
+				_ = store.Close()
+			}()
+			_, err = NewStoreWithLockWait(cfg, 0)
+			require.True(t, errors.Is(err, ErrLocked))
+			waited, err := NewStoreWithLockWait(cfg, 5*time.Second)
+			require.NoError(t, err)
+			require.NoError(t, waited.Close())
+
+			_, err = os.Stat(lockInfo)
+			require.True(t, errors.Is(err, os.ErrNotExist))
+		})
+	}
+}
+
+func TestDBUnlock(t *testing.T) {
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	for typ, cfg := range newLockTestConfigs(t) {
+		cfg := cfg
+		t.Run(typ, func(t *testing.T) {
+			lockInfo := LockInfoPath(cfg)
+			require.Error(t, Unlock(cfg)) // No lock data.
+
+			store, err := NewStore(cfg)
+			require.NoError(t, err)
+			require.NoError(t, store.Close())
+
+			t.Run("another host", func(t *testing.T) {
+				writeTestLockInfo(t, lockInfo, LockInfo{PID: deadPID(t), Hostname: host + ".other"})
+				require.Error(t, Unlock(cfg))
+				_, err := os.Stat(lockInfo)
+				require.NoError(t, err)
+			})
+			t.Run("stale", func(t *testing.T) {
+				writeTestLockInfo(t, lockInfo, LockInfo{PID: deadPID(t), Hostname: host})
+				require.NoError(t, Unlock(cfg))
+				_, err := os.Stat(lockInfo)
+				require.True(t, errors.Is(err, os.ErrNotExist))
+			})
+		})
+	}
+	require.Error(t, Unlock(dbconfig.DBConfiguration{Type: dbconfig.InMemoryDB}))
+}
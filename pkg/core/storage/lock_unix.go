@@ -0,0 +1,20 @@
+//go:build !windows
+
+package storage
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isOSLockError checks whether the error is returned by non-blocking flock
+// for the file locked by someone else.
+func isOSLockError(err error) bool {
+	return errors.Is(err, syscall.EWOULDBLOCK) || errors.Is(err, syscall.EAGAIN)
+}
+
+// processExists checks whether the process with the given PID is running.
+func processExists(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// errSharingViolation is ERROR_SHARING_VIOLATION returned when the file is
+// opened exclusively by someone else.
+const errSharingViolation = syscall.Errno(32)
+
+// isOSLockError checks whether the error is returned for the file locked by
+// someone else.
+func isOSLockError(err error) bool {
+	return errors.Is(err, errSharingViolation)
+}
+
+// processExists checks whether the process with the given PID is running.
+func processExists(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
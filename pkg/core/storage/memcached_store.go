@@ -409,6 +409,36 @@ func (s *MemCachedStore) persist(isSync bool) (int, error) {
 	return keys, err
 }
 
+// Snapshot returns a new MemCachedStore with a consistent view of the current
+// store contents (including all the lower layers). This view is not affected
+// by subsequent changes and Persist calls, so it's suitable for long-running
+// reads. The lower Store must either be a MemCachedStore or implement the
+// Snapshotter interface, ErrSnapshotsNotSupported is returned otherwise.
+// The result must be closed after use, it doesn't close the original store.
+func (s *MemCachedStore) Snapshot() (*MemCachedStore, error) {
+	var (
+		lower Store
+		err   error
+	)
+	s.rlock()
+	defer s.runlock()
+	switch ps := s.ps.(type) {
+	case *MemCachedStore:
+		lower, err = ps.Snapshot()
+	case Snapshotter:
+		lower, err = ps.Snapshot()
+	default:
+		err = ErrSnapshotsNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &MemCachedStore{
+		MemoryStore: *s.MemoryStore.snapshot(),
+		ps:          lower,
+	}, nil
+}
+
 // Close implements Store interface, clears up memory and closes the lower layer
 // Store.
 func (s *MemCachedStore) Close() error {
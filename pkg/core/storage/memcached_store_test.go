@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"sort"
 	"testing"
@@ -431,3 +432,95 @@ func TestCachedSeekSorting(t *testing.T) {
 		require.Equal(t, expected, foundKVs)
 	}
 }
+
+func TestMemCachedSnapshot(t *testing.T) {
+	var (
+		prefix = []byte{byte(STStorage)}
+		kvs    []KeyValue
+	)
+	for i := 0; i < 10; i++ {
+		kvs = append(kvs, KeyValue{
+			Key:   append(slice.Copy(prefix), byte(i)),
+			Value: []byte{byte(i)},
+		})
+	}
+	seekAll := func(t *testing.T, s *MemCachedStore) []KeyValue {
+		var res []KeyValue
+		s.Seek(SeekRange{Prefix: prefix}, func(k, v []byte) bool {
+			res = append(res, KeyValue{Key: slice.Copy(k), Value: slice.Copy(v)})
+			return true
+		})
+		return res
+	}
+	check := func(t *testing.T, ps Store) {
+		ts := NewMemCachedStore(ps)
+		// Half of the items is persisted, half is cached.
+		for _, kv := range kvs[:5] {
+			ts.Put(kv.Key, kv.Value)
+		}
+		_, err := ts.Persist()
+		require.NoError(t, err)
+		for _, kv := range kvs[5:] {
+			ts.Put(kv.Key, kv.Value)
+		}
+
+		snap, err := ts.Snapshot()
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, snap.Close()) })
+		require.Equal(t, kvs, seekAll(t, snap))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch := snap.SeekAsync(ctx, SeekRange{Prefix: prefix}, false)
+		var got []KeyValue
+		for i := 0; i < 3; i++ {
+			kv := <-ch
+			got = append(got, KeyValue{Key: slice.Copy(kv.Key), Value: slice.Copy(kv.Value)})
+		}
+
+		// Change everything and persist it in the middle of the traversal.
+		for i, kv := range kvs {
+			if i%2 == 0 {
+				ts.Delete(kv.Key)
+			} else {
+				ts.Put(kv.Key, []byte("new"))
+			}
+		}
+		ts.Put(append(slice.Copy(prefix), 0xff), []byte("new"))
+		_, err = ts.Persist()
+		require.NoError(t, err)
+		_, err = ts.Get(kvs[0].Key)
+		require.ErrorIs(t, err, ErrKeyNotFound)
+
+		for kv := range ch {
+			got = append(got, KeyValue{Key: slice.Copy(kv.Key), Value: slice.Copy(kv.Value)})
+		}
+		require.Equal(t, kvs, got)
+		require.Equal(t, kvs, seekAll(t, snap))
+		for _, kv := range kvs {
+			v, err := snap.Get(kv.Key)
+			require.NoError(t, err)
+			require.Equal(t, kv.Value, v)
+		}
+		_, err = snap.Get(append(slice.Copy(prefix), 0xff))
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	}
+
+	t.Run("MemoryStore", func(t *testing.T) {
+		check(t, NewMemoryStore())
+	})
+	t.Run("MemCachedStore", func(t *testing.T) {
+		check(t, NewMemCachedStore(NewMemoryStore()))
+	})
+	t.Run("LevelDBStore", func(t *testing.T) {
+		ps := newLevelDBForTesting(t)
+		t.Cleanup(func() { require.NoError(t, ps.Close()) })
+		check(t, ps)
+	})
+	t.Run("BoltDBStore", func(t *testing.T) {
+		ps := newBoltStoreForTesting(t)
+		t.Cleanup(func() { require.NoError(t, ps.Close()) })
+		_, err := NewMemCachedStore(ps).Snapshot()
+		require.ErrorIs(t, err, ErrSnapshotsNotSupported)
+	})
+}
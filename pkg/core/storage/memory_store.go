@@ -133,6 +133,29 @@ func (s *MemoryStore) seek(rng SeekRange, f func(k, v []byte) bool, lock func(),
 	}
 }
 
+// Snapshot implements the Snapshotter interface, it returns a copy of the
+// store. Never returns an error.
+func (s *MemoryStore) Snapshot() (Store, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.snapshot(), nil
+}
+
+// snapshot is an internal unlocked implementation of Snapshot.
+func (s *MemoryStore) snapshot() *MemoryStore {
+	res := &MemoryStore{
+		mem:  make(map[string][]byte, len(s.mem)),
+		stor: make(map[string][]byte, len(s.stor)),
+	}
+	for k, v := range s.mem {
+		res.mem[k] = v
+	}
+	for k, v := range s.stor {
+		res.stor[k] = v
+	}
+	return res
+}
+
 // Close implements Store interface and clears up memory. Never returns an
 // error.
 func (s *MemoryStore) Close() error {
@@ -0,0 +1,15 @@
+//go:build !pebble
+
+package storage
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+)
+
+// newPebbleDBStore returns an error, Pebble support is only available in
+// binaries built with "pebble" tag.
+func newPebbleDBStore(cfg dbconfig.PebbleDBOptions) (Store, error) {
+	return nil, errors.New("PebbleDB support is not compiled in, rebuild with \"pebble\" tag")
+}
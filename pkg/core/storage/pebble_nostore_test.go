@@ -0,0 +1,18 @@
+//go:build !pebble
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPebbleDBNotCompiled(t *testing.T) {
+	_, err := NewStore(dbconfig.DBConfiguration{
+		Type:            dbconfig.PebbleDB,
+		PebbleDBOptions: dbconfig.PebbleDBOptions{DataDirectoryPath: t.TempDir()},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,221 @@
+//go:build pebble
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+)
+
+// PebbleDBStore is the Store implementation based on Pebble, a LevelDB-like
+// key-value store with better compaction behaviour for large DBs.
+type PebbleDBStore struct {
+	db   *pebble.DB
+	path string
+	// lockInfo is the path to the lock data file (if it's written).
+	lockInfo string
+}
+
+// pebbleReader is the common part of pebble.DB and pebble.Snapshot used for
+// reads.
+type pebbleReader interface {
+	NewIter(o *pebble.IterOptions) (*pebble.Iterator, error)
+}
+
+// NewPebbleDBStore returns a new PebbleDBStore object that will initialize
+// the database found at the given path.
+func NewPebbleDBStore(cfg dbconfig.PebbleDBOptions) (*PebbleDBStore, error) {
+	var opts = &pebble.Options{
+		ReadOnly:   cfg.ReadOnly,
+		WALDir:     cfg.WALDir,
+		DisableWAL: cfg.DisableWAL,
+	}
+	if cfg.ReadOnly {
+		opts.ErrorIfNotExists = true
+	}
+	if cfg.CacheSize > 0 {
+		cache := pebble.NewCache(cfg.CacheSize)
+		// DB holds its own reference to the cache.
+		defer cache.Unref()
+		opts.Cache = cache
+	}
+	lockInfo := filepath.Join(cfg.DataDirectoryPath, pebbleDBLockInfoFile)
+	db, err := pebble.Open(cfg.DataDirectoryPath, opts)
+	if err != nil {
+		if isLockHeld(err) {
+			err = newLockError(cfg.DataDirectoryPath, lockInfo, err)
+		}
+		return nil, fmt.Errorf("failed to open PebbleDB instance: %w", err)
+	}
+	if cfg.ReadOnly {
+		lockInfo = ""
+	} else if err = writeLockInfo(lockInfo); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &PebbleDBStore{
+		path:     cfg.DataDirectoryPath,
+		db:       db,
+		lockInfo: lockInfo,
+	}, nil
+}
+
+// Get implements the Store interface.
+func (s *PebbleDBStore) Get(key []byte) ([]byte, error) {
+	return getPebble(s.db.Get(key))
+}
+
+// getPebble copies the value returned by Pebble Get (it's only valid until
+// closer is closed) and converts the error.
+func getPebble(value []byte, closer io.Closer, err error) ([]byte, error) {
+	if err != nil {
+		if errors.Is(err, pebble.ErrNotFound) {
+			err = ErrKeyNotFound
+		}
+		return nil, err
+	}
+	res := slice.Copy(value)
+	return res, closer.Close()
+}
+
+// PutChangeSet implements the Store interface.
+func (s *PebbleDBStore) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
+	var err error
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	for _, m := range []map[string][]byte{puts, stores} {
+		for k := range m {
+			if m[k] != nil {
+				err = batch.Set([]byte(k), m[k], nil)
+			} else {
+				err = batch.Delete([]byte(k), nil)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// Seek implements the Store interface.
+func (s *PebbleDBStore) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	seekPebble(s.db, rng, f)
+}
+
+// SeekGC implements the Store interface.
+func (s *PebbleDBStore) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	var err error
+
+	// Changes are accumulated in the batch, so iteration goes over the
+	// original data.
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	seekPebble(s.db, rng, func(k, v []byte) bool {
+		if !keep(k, v) {
+			err = batch.Delete(k, nil)
+			if err != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// seekPebble iterates over the data available via the given reader.
+func seekPebble(r pebbleReader, rng SeekRange, f func(k, v []byte) bool) {
+	var (
+		next func() bool
+		ok   bool
+		bnds = seekRangeToPrefixes(rng)
+	)
+	iter, err := r.NewIter(&pebble.IterOptions{
+		LowerBound: bnds.Start,
+		UpperBound: bnds.Limit,
+	})
+	if err != nil {
+		return
+	}
+
+	if !rng.Backwards {
+		ok = iter.First()
+		next = iter.Next
+	} else {
+		ok = iter.Last()
+		next = iter.Prev
+	}
+
+	for ; ok; ok = next() {
+		if !f(iter.Key(), iter.Value()) {
+			break
+		}
+	}
+	_ = iter.Close()
+}
+
+// Snapshot implements the Snapshotter interface.
+func (s *PebbleDBStore) Snapshot() (Store, error) {
+	return &pebbleDBSnapshot{snap: s.db.NewSnapshot()}, nil
+}
+
+// Close implements the Store interface.
+func (s *PebbleDBStore) Close() error {
+	// Lock data is removed while the lock is still held.
+	err := removeLockInfo(s.lockInfo)
+	closeErr := s.db.Close()
+	if closeErr != nil {
+		return closeErr
+	}
+	return err
+}
+
+// pebbleDBSnapshot is a read-only Store backed by Pebble snapshot.
+type pebbleDBSnapshot struct {
+	snap *pebble.Snapshot
+}
+
+// Get implements the Store interface.
+func (s *pebbleDBSnapshot) Get(key []byte) ([]byte, error) {
+	return getPebble(s.snap.Get(key))
+}
+
+// PutChangeSet implements the Store interface. Always returns an error.
+func (s *pebbleDBSnapshot) PutChangeSet(puts map[string][]byte, stores map[string][]byte) error {
+	return errReadOnlySnapshot
+}
+
+// Seek implements the Store interface.
+func (s *pebbleDBSnapshot) Seek(rng SeekRange, f func(k, v []byte) bool) {
+	seekPebble(s.snap, rng, f)
+}
+
+// SeekGC implements the Store interface. Always returns an error.
+func (s *pebbleDBSnapshot) SeekGC(rng SeekRange, keep func(k, v []byte) bool) error {
+	return errReadOnlySnapshot
+}
+
+// Close implements the Store interface, it releases the snapshot.
+func (s *pebbleDBSnapshot) Close() error {
+	return s.snap.Close()
+}
+
+// newPebbleDBStore is a NewPebbleDBStore wrapper used by NewStore.
+func newPebbleDBStore(cfg dbconfig.PebbleDBOptions) (Store, error) {
+	s, err := NewPebbleDBStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
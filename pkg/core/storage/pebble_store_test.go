@@ -0,0 +1,71 @@
+//go:build pebble
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	testDBs = append(testDBs, dbSetup{"PebbleDB", newPebbleDBForTesting})
+}
+
+func newPebbleDBForTesting(t testing.TB) Store {
+	opts := dbconfig.PebbleDBOptions{
+		DataDirectoryPath: t.TempDir(),
+	}
+	store, err := NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	return store
+}
+
+func TestROPebbleDB(t *testing.T) {
+	opts := dbconfig.PebbleDBOptions{
+		DataDirectoryPath: t.TempDir(),
+		ReadOnly:          true,
+	}
+
+	// If DB doesn't exist, then error should be returned.
+	_, err := NewPebbleDBStore(opts)
+	require.Error(t, err)
+
+	// Create the DB and try to open it in RO mode.
+	opts.ReadOnly = false
+	store, err := NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	require.NoError(t, store.PutChangeSet(map[string][]byte{"one": []byte("one")}, nil))
+	require.NoError(t, store.Close())
+	opts.ReadOnly = true
+
+	store, err = NewPebbleDBStore(opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+	v, err := store.Get([]byte("one"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), v)
+	// Changes must be prohibited.
+	putErr := store.PutChangeSet(map[string][]byte{"two": []byte("two")}, nil)
+	require.ErrorIs(t, putErr, pebble.ErrReadOnly)
+}
+
+func TestPebbleDBSnapshot(t *testing.T) {
+	store := newPebbleDBForTesting(t)
+	t.Cleanup(func() { require.NoError(t, store.Close()) })
+	key := []byte{byte(STStorage), 1}
+	require.NoError(t, store.PutChangeSet(nil, map[string][]byte{string(key): {1}}))
+
+	snap, err := store.(Snapshotter).Snapshot()
+	require.NoError(t, err)
+	require.NoError(t, store.PutChangeSet(nil, map[string][]byte{string(key): nil}))
+
+	v, err := snap.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, v)
+	_, err = store.Get(key)
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	require.NoError(t, snap.Close())
+}
@@ -33,7 +33,13 @@ const (
 	SYSStateSyncCurrentBlockHeight KeyPrefix = 0xc2
 	SYSStateSyncPoint              KeyPrefix = 0xc3
 	SYSStateJumpStage              KeyPrefix = 0xc4
-	SYSVersion                     KeyPrefix = 0xf0
+	// SYSMemPool is used to store memory pool contents between node restarts,
+	// it's not a part of the chain state.
+	SYSMemPool KeyPrefix = 0xc6
+	// SYSPrunedHeight is used to store the lowest height state is retained
+	// for after state pruning.
+	SYSPrunedHeight KeyPrefix = 0xc7
+	SYSVersion      KeyPrefix = 0xf0
 )
 
 // Executable subtypes.
@@ -70,6 +76,13 @@ type SeekRange struct {
 // when a certain key is not found.
 var ErrKeyNotFound = errors.New("key not found")
 
+// ErrSnapshotsNotSupported is returned when a snapshot is requested from the
+// Store that can't provide it.
+var ErrSnapshotsNotSupported = errors.New("snapshots are not supported by the store")
+
+// errReadOnlySnapshot is returned on attempts to change snapshot contents.
+var errReadOnlySnapshot = errors.New("snapshot is read-only")
+
 type (
 	// Store is the underlying KV backend for the blockchain data, it's
 	// not intended to be used directly, you wrap it with some memory cache
@@ -91,6 +104,14 @@ type (
 		Close() error
 	}
 
+	// Snapshotter is implemented by Store backends that are able to provide
+	// a consistent read-only view of their current contents. This view is
+	// not affected by any subsequent changes made to the original Store and
+	// must be released with Close (which doesn't close the original Store).
+	Snapshotter interface {
+		Snapshot() (Store, error)
+	}
+
 	// KeyPrefix is a constant byte added as a prefix for each key
 	// stored.
 	KeyPrefix uint8
@@ -125,6 +146,8 @@ func NewStore(cfg dbconfig.DBConfiguration) (Store, error) {
 		store = NewMemoryStore()
 	case dbconfig.BoltDB:
 		store, err = NewBoltDBStore(cfg.BoltDBOptions)
+	case dbconfig.PebbleDB:
+		store, err = newPebbleDBStore(cfg.PebbleDBOptions)
 	default:
 		return nil, fmt.Errorf("unknown storage: %s", cfg.Type)
 	}
@@ -229,16 +229,19 @@ func testStoreSeekGC(t *testing.T, s Store) {
 	}
 }
 
+// testDBs are the DBs tested by TestAllDBs, optional ones are added to
+// this list when they're compiled in.
+var testDBs = []dbSetup{
+	{"BoltDB", newBoltStoreForTesting},
+	{"LevelDB", newLevelDBForTesting},
+	{"MemCached", newMemCachedStoreForTesting},
+	{"Memory", newMemoryStoreForTesting},
+}
+
 func TestAllDBs(t *testing.T) {
-	var DBs = []dbSetup{
-		{"BoltDB", newBoltStoreForTesting},
-		{"LevelDB", newLevelDBForTesting},
-		{"MemCached", newMemCachedStoreForTesting},
-		{"Memory", newMemoryStoreForTesting},
-	}
 	var tests = []dbTestFunction{testStoreGetNonExistent, testStoreSeek,
 		testStoreSeekGC}
-	for _, db := range DBs {
+	for _, db := range testDBs {
 		for _, test := range tests {
 			s := db.create(t)
 			twrapper := func(t *testing.T) {
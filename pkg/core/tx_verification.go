@@ -0,0 +1,95 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// witnessesResult is the result of transaction witnesses verification
+// performed in advance.
+type witnessesResult struct {
+	err error
+}
+
+// preverifyTxWitnesses verifies witnesses of the given block transactions in
+// parallel using a worker pool bounded by GOMAXPROCS. Only transactions that
+// are not in the memory pool and have all witnesses using standard (signature
+// or multisignature) verification scripts with push-only invocation scripts
+// are verified, these don't depend on the storage (except for policy settings
+// that don't change within a block). The result for every transaction is
+// either nil (it's to be verified serially) or the result of its witnesses
+// verification to be used instead of running it again. The order of
+// transactions is irrelevant here, the caller is expected to check results in
+// transaction order, so the first failing transaction is always the same.
+func (bc *Blockchain) preverifyTxWitnesses(txs []*transaction.Transaction) []*witnessesResult {
+	var (
+		res     = make([]*witnessesResult, len(txs))
+		idx     = make([]int, 0, len(txs))
+		workers = runtime.GOMAXPROCS(0)
+	)
+	for i, tx := range txs {
+		if bc.memPool.ContainsKey(tx.Hash()) || !hasStandardWitnesses(tx) {
+			continue
+		}
+		idx = append(idx, i)
+	}
+	if workers > len(idx) {
+		workers = len(idx)
+	}
+	if workers < 2 {
+		return res // Nothing to parallelize, verify serially.
+	}
+
+	var (
+		wg   sync.WaitGroup
+		next = int32(-1)
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				n := int(atomic.AddInt32(&next, 1))
+				if n >= len(idx) {
+					return
+				}
+				i := idx[n]
+				res[i] = &witnessesResult{err: bc.verifyTxWitnesses(txs[i], nil, false)}
+			}
+		}()
+	}
+	wg.Wait()
+	return res
+}
+
+// hasStandardWitnesses checks whether all transaction witnesses use standard
+// verification scripts and push-only invocation scripts.
+func hasStandardWitnesses(tx *transaction.Transaction) bool {
+	if len(tx.Scripts) != len(tx.Signers) {
+		return false
+	}
+	for i := range tx.Scripts {
+		if !vm.IsStandardContract(tx.Scripts[i].VerificationScript) ||
+			!isPushOnly(tx.Scripts[i].InvocationScript) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPushOnly checks whether the script only contains push instructions.
+func isPushOnly(script []byte) bool {
+	ctx := vm.NewContext(script)
+	for ctx.NextIP() < len(script) {
+		op, _, err := ctx.Next()
+		if err != nil || op > opcode.PUSH16 {
+			return false
+		}
+	}
+	return true
+}
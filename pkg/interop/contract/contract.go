@@ -38,7 +38,9 @@ func CreateStandardAccount(pub interop.PublicKey) []byte {
 }
 
 // GetCallFlags returns the calling flags which execution context was created with.
-// This function uses `System.Contract.GetCallFlags` syscall.
+// It can be used to check that the contract is called with the flags it needs
+// (like WriteStates) and fail early otherwise or to behave differently for
+// read-only calls. This function uses `System.Contract.GetCallFlags` syscall.
 func GetCallFlags() CallFlag {
 	return neogointernal.Syscall0("System.Contract.GetCallFlags").(CallFlag)
 }
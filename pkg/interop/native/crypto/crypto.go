@@ -1,6 +1,10 @@
 /*
 Package crypto provides interface to CryptoLib native contract.
 It implements some cryptographic functions.
+
+Only the methods implemented by CryptoLib of this NeoGo version are available
+here, keccak256 and bls12381* methods of newer protocol versions are not
+supported by the node yet, so there are no wrappers for them.
 */
 package crypto
 
@@ -43,3 +47,31 @@ func Murmur32(b []byte, seed int) []byte {
 func VerifyWithECDsa(msg []byte, pub interop.PublicKey, sig interop.Signature, curve NamedCurve) bool {
 	return neogointernal.CallWithToken(Hash, "verifyWithECDsa", int(contract.NoneFlag), msg, pub, sig, curve).(bool)
 }
+
+// VerifyMultisig checks that sigs are correct msg's signatures made by some
+// subset of pubs (serialized public keys on the given curve) using
+// `verifyWithECDsa` method of native CryptoLib contract. It follows the
+// CheckMultisig rules: signatures must be ordered the same way as the public
+// keys they correspond to and each key can be used only once. It returns false
+// if there are no signatures or if there are more signatures than keys.
+//
+// Notice that every key checked costs one `verifyWithECDsa` call (1 << 15
+// execution fee units plus contract call overhead), so the GAS consumed grows
+// linearly with the number of keys and can reach len(pubs) calls in the worst
+// case. Ordering keys to match the expected signers first reduces the cost.
+func VerifyMultisig(msg []byte, pubs []interop.PublicKey, sigs []interop.Signature, curve NamedCurve) bool {
+	if len(sigs) == 0 || len(sigs) > len(pubs) {
+		return false
+	}
+	var i, j int
+	for i < len(sigs) && j < len(pubs) {
+		if len(pubs)-j < len(sigs)-i {
+			return false
+		}
+		if VerifyWithECDsa(msg, pubs[j], sigs[i], curve) {
+			i++
+		}
+		j++
+	}
+	return i == len(sigs)
+}
@@ -43,6 +43,22 @@ func GetBlock(indexOrHash interface{}) *Block {
 	return neogointernal.CallWithToken(Hash, "getBlock", int(contract.ReadStates), indexOrHash).(*Block)
 }
 
+// GetBlockHash returns the hash (256 bit BE value in a 32 byte slice) of the
+// block with the given index. An empty slice is returned if there is no such
+// block or it's not available to contracts (see MaxTraceableBlocks setting).
+// It uses `getBlock` method of Ledger native contract, so it's just a shortcut
+// for GetBlock(index).Hash that handles missing blocks.
+func GetBlockHash(index int) interop.Hash256 {
+	if index < 0 {
+		return interop.Hash256{}
+	}
+	b := GetBlock(index)
+	if b == nil {
+		return interop.Hash256{}
+	}
+	return b.Hash
+}
+
 // GetTransaction represents `getTransaction` method of Ledger native contract.
 func GetTransaction(hash interop.Hash256) *Transaction {
 	return neogointernal.CallWithToken(Hash, "getTransaction", int(contract.ReadStates), hash).(*Transaction)
@@ -1,6 +1,11 @@
 /*
 Package std provides an interface to StdLib native contract.
 It implements various useful conversion functions.
+
+MemoryCompare and MemorySearch* functions have a fixed price that doesn't
+depend on the length of their arguments (which can't exceed 1024 bytes), so
+they're much cheaper than comparing or searching byte slices with loops in
+contract code where every iteration costs several VM instructions.
 */
 package std
 
@@ -72,6 +77,51 @@ func Base64Decode(b []byte) []byte {
 		b).([]byte)
 }
 
+// Base64URLEncode encodes the given byte slice into a URL-safe base64 string
+// without padding (as used in JWT). It calls `base64Encode` method of StdLib
+// native contract and converts the result to the URL-safe alphabet.
+func Base64URLEncode(b []byte) string {
+	s := []byte(Base64Encode(b))
+	n := len(s)
+	for n > 0 && s[n-1] == '=' {
+		n--
+	}
+	for i := 0; i < n; i++ {
+		if s[i] == '+' {
+			s[i] = '-'
+		} else if s[i] == '/' {
+			s[i] = '_'
+		}
+	}
+	return string(s[:n])
+}
+
+// Base64URLDecode decodes the given URL-safe base64 string (padded or not)
+// into byte slice. It converts the string to the standard base64 alphabet and
+// calls `base64Decode` method of StdLib native contract, panic happens on
+// invalid input.
+func Base64URLDecode(s string) []byte {
+	b := []byte(s)
+	for i := 0; i < len(b); i++ {
+		if b[i] == '+' || b[i] == '/' {
+			panic("invalid base64url character")
+		}
+		if b[i] == '-' {
+			b[i] = '+'
+		} else if b[i] == '_' {
+			b[i] = '/'
+		}
+	}
+	if len(b) > 0 && b[len(b)-1] != '=' {
+		if len(b)%4 == 2 {
+			b = append(b, '=', '=')
+		} else if len(b)%4 == 3 {
+			b = append(b, '=')
+		}
+	}
+	return Base64Decode(b)
+}
+
 // Base58Encode calls `base58Encode` method of StdLib native contract and encodes
 // the given byte slice into a base58 string and returns byte representation of this
 // string.
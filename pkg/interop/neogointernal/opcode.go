@@ -22,3 +22,7 @@ func Opcode2NoReturn(op string, arg1, arg2 interface{}) {
 func Opcode3(op string, arg1, arg2, arg3 interface{}) interface{} {
 	return nil
 }
+
+// Opcode3NoReturn emits opcode with 3 arguments.
+func Opcode3NoReturn(op string, arg1, arg2, arg3 interface{}) {
+}
@@ -14,6 +14,16 @@ func GetScriptContainer() *ledger.Transaction {
 	return neogointernal.Syscall0("System.Runtime.GetScriptContainer").(*ledger.Transaction)
 }
 
+// CurrentSigners returns signers of the transaction that initially triggered
+// current execution context (the one returned by GetScriptContainer), nil is
+// returned if the script container is not a transaction (for example, it's
+// run by the system at the block persisting stage). It allows to implement
+// custom authorization policies. This function uses
+// `System.Runtime.CurrentSigners` syscall available since Basilisk hard-fork.
+func CurrentSigners() []ledger.TransactionSigner {
+	return neogointernal.Syscall0("System.Runtime.CurrentSigners").([]ledger.TransactionSigner)
+}
+
 // GetExecutingScriptHash returns script hash (160 bit in BE form represented
 // as 20-byte slice) of the contract that is currently being executed. Any
 // AppCall can change the value returned by this function if it calls a
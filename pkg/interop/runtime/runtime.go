@@ -6,6 +6,7 @@ package runtime
 
 import (
 	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
 	"github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"
 )
 
@@ -99,6 +100,16 @@ func GetInvocationCounter() int {
 	return neogointernal.Syscall0("System.Runtime.GetInvocationCounter").(int)
 }
 
+// IsHardforkEnabled checks whether the hard-fork with the given name (like
+// "Aspidochelone") is enabled at the current height, it returns false for
+// hard-forks unknown to the node. This function uses
+// `System.Runtime.IsHardforkEnabled` syscall which is a NeoGo extension not
+// supported by the C# node, it's only available on networks with
+// HardforkQueryExtension enabled.
+func IsHardforkEnabled(name string) bool {
+	return neogointernal.Syscall1("System.Runtime.IsHardforkEnabled", name).(bool)
+}
+
 // Platform returns the platform name, which is set to be `NEO`. This function uses
 // `System.Runtime.Platform` syscall.
 func Platform() []byte {
@@ -111,3 +122,18 @@ func Platform() []byte {
 func GetRandom() int {
 	return neogointernal.Syscall0("System.Runtime.GetRandom").(int)
 }
+
+// LoadScript loads the given bytecode into the VM and executes it with the
+// given call flags and arguments, returning whatever it returns (nil if it
+// returns nothing). This bytecode is executed as is from byte 0, it's not
+// a deployed contract that can have multiple methods. The script is
+// executed in a restricted read-only context: irrespective of the flags
+// given, only contract.ReadStates and contract.AllowCall (if available to
+// the calling contract) are passed to it, so it can't change storage or
+// issue notifications. Still, the script is executed on behalf of the calling
+// contract and contracts called from it see this script as the caller, so
+// never load scripts from untrusted sources without checking them first.
+// This function uses `System.Runtime.LoadScript` syscall.
+func LoadScript(script []byte, f contract.CallFlag, args ...interface{}) interface{} {
+	return neogointernal.Syscall3("System.Runtime.LoadScript", script, f, args)
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"
+)
+
+// Map is a part of the contract storage with keys sharing the same prefix.
+// Keys passed to Map methods are prepended with this prefix, they can be of
+// the same types as for Put. Map is created with NewMap.
+type Map struct {
+	ctx    Context
+	prefix interface{}
+}
+
+// NewMap returns Map for the given prefix using given Context (a read-only
+// one can be used as well, but Put and Delete will fail in this case).
+func NewMap(ctx Context, prefix interface{}) Map {
+	return Map{ctx: ctx, prefix: prefix}
+}
+
+// Put saves given value with the given key prepended with the Map prefix.
+// See Put documentation for details.
+func (m Map) Put(key interface{}, value interface{}) {
+	Put(m.ctx, neogointernal.Opcode2("CAT", m.prefix, key), value)
+}
+
+// Get retrieves value stored for the given key prepended with the Map prefix.
+// See Get documentation for details.
+func (m Map) Get(key interface{}) interface{} {
+	return Get(m.ctx, neogointernal.Opcode2("CAT", m.prefix, key))
+}
+
+// Delete removes key-value pair for the given key prepended with the Map
+// prefix. See Delete documentation for details.
+func (m Map) Delete(key interface{}) {
+	Delete(m.ctx, neogointernal.Opcode2("CAT", m.prefix, key))
+}
+
+// Find returns an iterator.Iterator over key-value pairs with keys starting
+// with the Map prefix followed by the given key. Notice that RemovePrefix
+// option removes both the Map prefix and the key. See Find documentation
+// for details.
+func (m Map) Find(key interface{}, options FindFlags) iterator.Iterator {
+	return Find(m.ctx, neogointernal.Opcode2("CAT", m.prefix, key), options)
+}
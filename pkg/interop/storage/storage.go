@@ -7,7 +7,9 @@ contract.
 package storage
 
 import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
 	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/std"
 	"github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"
 )
 
@@ -64,7 +66,8 @@ func GetReadOnlyContext() Context {
 // Even though it accepts interface{} for both, you can only pass simple types
 // there like string, []byte, int or bool (not structures or slices of more
 // complex types). To put more complex types there serialize them first using
-// runtime.Serialize. This function uses `System.Storage.Put` syscall.
+// std.Serialize (or use PutStruct for structures). This function uses
+// `System.Storage.Put` syscall.
 func Put(ctx Context, key interface{}, value interface{}) {
 	neogointernal.Syscall3NoReturn("System.Storage.Put", ctx, key, value)
 }
@@ -90,3 +93,64 @@ func Delete(ctx Context, key interface{}) {
 func Find(ctx Context, key interface{}, options FindFlags) iterator.Iterator {
 	return neogointernal.Syscall3("System.Storage.Find", ctx, key, options).(iterator.Iterator)
 }
+
+// PutStruct serializes the given structure (or a pointer to it) using
+// std.Serialize and saves it with the given key in the storage using given
+// Context. See Put documentation on possible key types. It's the same as
+// PutSerialized and is provided as a counterpart for GetStruct, use
+// PutSerialized for values of other types.
+func PutStruct(ctx Context, key interface{}, val interface{}) {
+	PutSerialized(ctx, key, val)
+}
+
+// PutSerialized serializes the given value of any type supported by
+// std.Serialize (structures, slices, maps and simple types) and saves it with
+// the given key in the storage using given Context. See Put documentation on
+// possible key types. Serialized value is limited to 65535 bytes (as any other
+// storage value) and serialization itself fails for values containing
+// interop items (like iterators) or exceeding the VM stack item limits.
+func PutSerialized(ctx Context, key interface{}, value interface{}) {
+	Put(ctx, key, std.Serialize(value))
+}
+
+// GetSerialized retrieves the value stored with PutSerialized for the given
+// key using given Context and deserializes it with std.Deserialize. It returns
+// nil if the value is not present in the database. Structures are returned
+// as slices of their fields, so they need to be converted back to the
+// original type (or GetStruct can be used to fill an existing structure):
+//
+//	p := storage.GetSerialized(ctx, key).(Point)
+func GetSerialized(ctx Context, key interface{}) interface{} {
+	data := Get(ctx, key)
+	if data == nil {
+		return nil
+	}
+	return std.Deserialize(data.([]byte))
+}
+
+// GetStruct retrieves the value stored for the given key using given Context,
+// deserializes it using std.Deserialize and copies its fields in order into
+// the structure val points to. It returns false (leaving val untouched) if
+// the value is not present in the database. The structure must have the same
+// set of fields as the one saved with PutStruct (fields are matched by
+// their order, not by name), so pass a pointer to a structure of the same
+// type:
+//
+//	var p = &Point{}
+//	if storage.GetStruct(ctx, key, p) {
+//		...
+//	}
+func GetStruct(ctx Context, key interface{}, val interface{}) bool {
+	data := Get(ctx, key)
+	if data == nil {
+		return false
+	}
+	// Direct calls are used to avoid type conversions, structures are
+	// arrays of fields for the VM.
+	fields := neogointernal.CallWithToken(std.Hash, "deserialize", int(contract.NoneFlag), data)
+	n := neogointernal.Opcode1("SIZE", fields).(int)
+	for i := 0; i < n; i++ {
+		neogointernal.Opcode3NoReturn("SETITEM", val, i, neogointernal.Opcode2("PICKITEM", fields, i))
+	}
+	return true
+}
@@ -14,6 +14,24 @@ func Abort() {
 	neogointernal.Opcode0NoReturn("ABORT")
 }
 
+// Assert checks the condition and throws an exception with the given message
+// if it's false, so that the message ends up in the FAULT exception of the
+// transaction (if not recovered from). It's a compiler intrinsic, the condition
+// is compiled into a conditional jump and msg is only evaluated if the
+// condition is false. It's equivalent to
+//
+//	if !cond {
+//		panic(msg)
+//	}
+//
+// but is cheaper to execute, since there is no NOT instruction for
+// comparisons and the jump is folded into them where possible.
+func Assert(cond bool, msg string) {
+	if !cond {
+		panic(msg)
+	}
+}
+
 // FromAddress is an utility function that converts a Neo address to its hash
 // (160 bit BE value in a 20 byte slice). It can only be used for strings known
 // at compilation time, because the conversion is actually being done by the
@@ -32,6 +32,16 @@ const (
 	RPCErrorCode = -100
 )
 
+// RPC error codes defined by NeoGo extensions.
+const (
+	// UnsupportedStateCode is returned when the state requested for a historic
+	// call is not available.
+	UnsupportedStateCode = -606
+)
+
+// unsupportedStateData is the format of UnsupportedStateCode error data.
+const unsupportedStateData = "state for height %d is not available, nearest available height is %d"
+
 var (
 	// ErrInvalidParams represents a generic 'invalid parameters' error.
 	ErrInvalidParams = NewInvalidParamsError("invalid params")
@@ -45,6 +55,9 @@ var (
 	ErrUnknownScriptContainer = NewError(RPCErrorCode, "Unknown script container", "")
 	// ErrUnknownStateRoot is returned when requested state root is not found.
 	ErrUnknownStateRoot = NewError(RPCErrorCode, "Unknown state root", "")
+	// ErrUnsupportedState is returned when the state requested for a historic
+	// call is not available.
+	ErrUnsupportedState = NewError(UnsupportedStateCode, "Unsupported state", "")
 	// ErrAlreadyExists represents SubmitError with code -501.
 	ErrAlreadyExists = NewSubmitError(-501, "Block or transaction already exists and cannot be sent repeatedly.")
 	// ErrOutOfMemory represents SubmitError with code -502.
@@ -110,6 +123,30 @@ func NewSubmitError(code int64, message string) *Error {
 	return NewError(code, message, "")
 }
 
+// NewUnsupportedStateError creates a new ErrUnsupportedState-based error
+// containing the requested state height and the nearest available one.
+func NewUnsupportedStateError(height uint32, nearest uint32) *Error {
+	return WrapErrorWithData(ErrUnsupportedState, fmt.Sprintf(unsupportedStateData, height, nearest))
+}
+
+// NearestStateHeight returns the nearest available state height from the
+// error created by NewUnsupportedStateError, false is returned for any
+// other error.
+func NearestStateHeight(err error) (uint32, bool) {
+	var (
+		e       *Error
+		height  uint32
+		nearest uint32
+	)
+	if !errors.As(err, &e) || e.Code != UnsupportedStateCode {
+		return 0, false
+	}
+	if _, err := fmt.Sscanf(e.Data, unsupportedStateData, &height, &nearest); err != nil {
+		return 0, false
+	}
+	return nearest, true
+}
+
 // WrapErrorWithData returns copy of the given error with the specified data and cause.
 // It does not modify the source error.
 func WrapErrorWithData(e *Error, data string) *Error {
@@ -10,6 +10,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/vm/invocations"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/trace"
 )
 
 // Invoke represents a code invocation result and is used by several RPC calls
@@ -24,12 +25,24 @@ type Invoke struct {
 	Transaction    *transaction.Transaction
 	Diagnostics    *InvokeDiag
 	Session        uuid.UUID
+	Trace          *trace.Trace
 }
 
 // InvokeDiag is an additional diagnostic data for invocation.
 type InvokeDiag struct {
 	Changes     []dboper.Operation  `json:"storagechanges"`
 	Invocations []*invocations.Tree `json:"invokedcontracts"`
+	GasUsage    *GasUsage           `json:"gasconsumption,omitempty"`
+}
+
+// GasUsage is a breakdown of GAS consumed by invocation.
+type GasUsage struct {
+	// Opcodes contains GAS consumed by opcodes bucketed by opcode price
+	// class (opcode price before ExecFeeFactor multiplication).
+	Opcodes map[int64]int64 `json:"opcodes"`
+	// Syscalls contains GAS consumed by syscalls by syscall name, it
+	// includes native contract method prices and storage fees.
+	Syscalls map[string]int64 `json:"syscalls"`
 }
 
 type invokeAux struct {
@@ -42,6 +55,7 @@ type invokeAux struct {
 	Transaction    []byte                    `json:"tx,omitempty"`
 	Diagnostics    *InvokeDiag               `json:"diagnostics,omitempty"`
 	Session        string                    `json:"session,omitempty"`
+	Trace          *trace.Trace              `json:"trace,omitempty"`
 }
 
 // iteratorInterfaceName is a string used to mark Iterator inside the InteropInterface.
@@ -172,6 +186,7 @@ func (r Invoke) MarshalJSON() ([]byte, error) {
 		Transaction:   txbytes,
 		Diagnostics:   r.Diagnostics,
 		Session:       sessionID,
+		Trace:         r.Trace,
 	}
 	if len(r.FaultException) != 0 {
 		aux.FaultException = &r.FaultException
@@ -230,6 +245,7 @@ func (r *Invoke) UnmarshalJSON(data []byte) error {
 	r.Notifications = aux.Notifications
 	r.Transaction = tx
 	r.Diagnostics = aux.Diagnostics
+	r.Trace = aux.Trace
 	return nil
 }
 
@@ -5,13 +5,25 @@ import (
 	"encoding/base64"
 	"encoding/json"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
 // StateHeight is a result of getstateheight RPC.
 type StateHeight struct {
 	Local     uint32 `json:"localrootindex"`
 	Validated uint32 `json:"validatedrootindex"`
+	// Archive is a NeoGo extension containing the range of heights full
+	// state is available for (historic calls can be made against), it's
+	// nil if the node keeps only the latest state.
+	Archive *StateArchive `json:"archive,omitempty"`
+}
+
+// StateArchive is a range of state heights (both inclusive).
+type StateArchive struct {
+	Start uint32 `json:"start"`
+	End   uint32 `json:"end"`
 }
 
 // ProofWithKey represens a key-proof pair.
@@ -81,6 +93,15 @@ func (p *ProofWithKey) FromString(s string) error {
 	return r.Err
 }
 
+// Verify checks the proof against the given state root and returns the value
+// of the storage item it proves, false is returned if the proof is invalid.
+// Notice that the proof contains the complete storage item key (with contract
+// ID), so it should be checked against the expected one if the proof comes from
+// an untrusted source.
+func (p *ProofWithKey) Verify(root util.Uint256) ([]byte, bool) {
+	return mpt.VerifyProof(root, p.Key, p.Proof)
+}
+
 // MarshalJSON implements the json.Marshaler.
 func (p *VerifyProof) MarshalJSON() ([]byte, error) {
 	if p.Value == nil {
@@ -7,7 +7,9 @@ import (
 	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/internal/testserdes"
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,6 +52,26 @@ func TestProofWithKey_EncodeString(t *testing.T) {
 	require.Equal(t, expected, &actual)
 }
 
+func TestProofWithKey_Verify(t *testing.T) {
+	tr := mpt.NewTrie(nil, mpt.ModeAll, storage.NewMemCachedStore(storage.NewMemoryStore()))
+	require.NoError(t, tr.Put([]byte{0x12, 0x34}, []byte("value")))
+	require.NoError(t, tr.Put([]byte{0x12, 0x35}, []byte("another")))
+	proof, err := tr.GetProof([]byte{0x12, 0x34})
+	require.NoError(t, err)
+
+	p := &ProofWithKey{Key: []byte{0x12, 0x34}, Proof: proof}
+	v, ok := p.Verify(tr.StateRoot())
+	require.True(t, ok)
+	require.Equal(t, []byte("value"), v)
+
+	_, ok = p.Verify(util.Uint256{1, 2, 3})
+	require.False(t, ok)
+
+	p.Key = []byte{0x12, 0x35}
+	_, ok = p.Verify(tr.StateRoot())
+	require.False(t, ok)
+}
+
 func TestVerifyProof_MarshalJSON(t *testing.T) {
 	t.Run("Good", func(t *testing.T) {
 		vp := &VerifyProof{random.Bytes(100)}
@@ -0,0 +1,30 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// NotaryPoolRequest represents a main transaction with all P2PNotaryRequests
+// for it pooled by the node, it's an element of getnotarypool RPC call
+// result.
+type NotaryPoolRequest struct {
+	// Hash is the main transaction hash.
+	Hash util.Uint256 `json:"hash"`
+	// NKeys is the number of keys the main transaction needs signatures
+	// from (taken from its NotaryAssisted attribute).
+	NKeys uint8 `json:"nkeys"`
+	// Signatures is the number of signatures collected, it's the number of
+	// pooled requests (each of them carries one signature).
+	Signatures int `json:"signatures"`
+	// Fallbacks contains hashes of fallback transactions of pooled requests.
+	Fallbacks []util.Uint256 `json:"fallbacks"`
+
+	// MainTransaction is the main transaction (without witnesses of
+	// other parties), it's only returned in verbose mode.
+	MainTransaction *transaction.Transaction `json:"maintx,omitempty"`
+	// FallbackTransactions are fallback transactions of pooled requests
+	// (in the same order as Fallbacks), they're only returned in verbose
+	// mode.
+	FallbackTransactions []*transaction.Transaction `json:"fallbacktxs,omitempty"`
+}
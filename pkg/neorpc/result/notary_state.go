@@ -0,0 +1,28 @@
+package result
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// NotaryState is a snapshot of the Notary service state, it's a result of
+// getnotaryservicestate RPC call.
+type NotaryState struct {
+	Started bool `json:"started"`
+	// Account is the address of the account used to sign transactions,
+	// it's empty if the node is not a designated notary node.
+	Account       string               `json:"account,omitempty"`
+	Completed     uint64               `json:"completed"`
+	FallbacksSent uint64               `json:"fallbackssent"`
+	Expired       uint64               `json:"expired"`
+	Requests      []NotaryRequestState `json:"requests"`
+}
+
+// NotaryRequestState is the state of a single request tracked by the Notary
+// service.
+type NotaryRequestState struct {
+	Main              util.Uint256 `json:"main"`
+	Fallbacks         int          `json:"fallbacks"`
+	MinNotValidBefore uint32       `json:"minnotvalidbefore"`
+	Completed         bool         `json:"completed"`
+	Sent              bool         `json:"sent"`
+}
@@ -10,6 +10,18 @@ type (
 		Unconnected Peers `json:"unconnected"`
 		Connected   Peers `json:"connected"`
 		Bad         Peers `json:"bad"`
+		// NAT is only returned by NeoGo nodes with port mapping enabled.
+		NAT *NATStatus `json:"nat,omitempty"`
+	}
+
+	// NATStatus is the state of the automatic P2P port mapping.
+	NATStatus struct {
+		// Method is either "upnp" or "natpmp", it's empty if no gateway
+		// is found.
+		Method string `json:"method"`
+		// ExternalAddress is the address the node is reachable at.
+		ExternalAddress string `json:"externaladdress,omitempty"`
+		Mapped          bool   `json:"mapped"`
 	}
 
 	// Peers represents a slice of peers.
@@ -19,6 +31,31 @@ type (
 	Peer struct {
 		Address string `json:"address"`
 		Port    string `json:"port"`
+		// Score is only returned for connected peers in verbose mode.
+		Score *PeerScore `json:"score,omitempty"`
+		// Traffic is only returned for connected peers in verbose mode.
+		Traffic *PeerTraffic `json:"traffic,omitempty"`
+	}
+
+	// PeerScore contains peer quality data tracked by the node, counters
+	// decay over time.
+	PeerScore struct {
+		Score             float64 `json:"score"`
+		InvalidPayloads   float64 `json:"invalidpayloads"`
+		MalformedMessages float64 `json:"malformedmessages"`
+		Timeouts          float64 `json:"timeouts"`
+		UsefulBlocks      float64 `json:"usefulblocks"`
+		// Latency is the average ping round-trip time in milliseconds.
+		Latency int64 `json:"latency"`
+	}
+
+	// PeerTraffic contains the amount of data exchanged with the peer since
+	// the connection was established.
+	PeerTraffic struct {
+		BytesSent     uint64 `json:"bytessent"`
+		BytesReceived uint64 `json:"bytesreceived"`
+		// SendQueue is the number of packets waiting to be sent.
+		SendQueue int `json:"sendqueue"`
 	}
 )
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"math/big"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
@@ -13,12 +14,41 @@ import (
 type UnclaimedGas struct {
 	Address   util.Uint160
 	Unclaimed big.Int
+
+	// Periods, VoteTo and VoterReward are a NeoGo extension returned for
+	// verbose requests only. Periods contain the NEO holder reward for every
+	// range of blocks with the same GAS per block value, VoterReward is
+	// the part of Unclaimed generated for voting for VoteTo candidate.
+	Periods     []UnclaimedGasPeriod
+	VoteTo      *keys.PublicKey
+	VoterReward *big.Int
+}
+
+// UnclaimedGasPeriod is the NEO holder reward for blocks from Start to End
+// (not including End). Every period amount is rounded down separately, so
+// their sum can be a bit less than the holder reward included into Unclaimed.
+type UnclaimedGasPeriod struct {
+	Start       uint32
+	End         uint32
+	GASPerBlock big.Int
+	Amount      big.Int
 }
 
 // unclaimedGas is an auxiliary struct for JSON marshalling.
 type unclaimedGas struct {
-	Address   string `json:"address"`
-	Unclaimed string `json:"unclaimed"`
+	Address     string               `json:"address"`
+	Unclaimed   string               `json:"unclaimed"`
+	Periods     []unclaimedGasPeriod `json:"periods,omitempty"`
+	VoteTo      *keys.PublicKey      `json:"voteto,omitempty"`
+	VoterReward string               `json:"voterreward,omitempty"`
+}
+
+// unclaimedGasPeriod is an auxiliary struct for JSON marshalling.
+type unclaimedGasPeriod struct {
+	Start       uint32 `json:"start"`
+	End         uint32 `json:"end"`
+	GASPerBlock string `json:"gasperblock"`
+	Amount      string `json:"amount"`
 }
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -26,6 +56,18 @@ func (g UnclaimedGas) MarshalJSON() ([]byte, error) {
 	gas := &unclaimedGas{
 		Address:   address.Uint160ToString(g.Address),
 		Unclaimed: g.Unclaimed.String(),
+		VoteTo:    g.VoteTo,
+	}
+	if g.VoterReward != nil {
+		gas.VoterReward = g.VoterReward.String()
+	}
+	for i := range g.Periods {
+		gas.Periods = append(gas.Periods, unclaimedGasPeriod{
+			Start:       g.Periods[i].Start,
+			End:         g.Periods[i].End,
+			GASPerBlock: g.Periods[i].GASPerBlock.String(),
+			Amount:      g.Periods[i].Amount.String(),
+		})
 	}
 	return json.Marshal(gas)
 }
@@ -46,5 +88,27 @@ func (g *UnclaimedGas) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	g.Address = addr
+	g.VoteTo = gas.VoteTo
+	g.VoterReward = nil
+	if gas.VoterReward != "" {
+		g.VoterReward, ok = new(big.Int).SetString(gas.VoterReward, 10)
+		if !ok {
+			return errors.New("failed to convert voter reward")
+		}
+	}
+	g.Periods = nil
+	for _, p := range gas.Periods {
+		var period = UnclaimedGasPeriod{
+			Start: p.Start,
+			End:   p.End,
+		}
+		if _, ok = period.GASPerBlock.SetString(p.GASPerBlock, 10); !ok {
+			return errors.New("failed to convert GAS per block")
+		}
+		if _, ok = period.Amount.SetString(p.Amount, 10); !ok {
+			return errors.New("failed to convert period amount")
+		}
+		g.Periods = append(g.Periods, period)
+	}
 	return nil
 }
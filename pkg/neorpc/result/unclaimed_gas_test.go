@@ -0,0 +1,49 @@
+package result
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnclaimedGas_MarshalUnmarshalJSON(t *testing.T) {
+	g := &UnclaimedGas{
+		Address:   util.Uint160{1, 2, 3},
+		Unclaimed: *big.NewInt(1234),
+	}
+	testserdes.MarshalUnmarshalJSON(t, g, new(UnclaimedGas))
+
+	data, err := json.Marshal(g)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "periods")
+	require.NotContains(t, string(data), "voteto")
+	require.NotContains(t, string(data), "voterreward")
+
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	g.VoteTo = priv.PublicKey()
+	g.VoterReward = big.NewInt(34)
+	g.Periods = []UnclaimedGasPeriod{
+		{Start: 10, End: 20, GASPerBlock: *big.NewInt(500000000), Amount: *big.NewInt(1000)},
+		{Start: 20, End: 25, GASPerBlock: *big.NewInt(100000000), Amount: *big.NewInt(200)},
+	}
+	testserdes.MarshalUnmarshalJSON(t, g, new(UnclaimedGas))
+
+	t.Run("invalid", func(t *testing.T) {
+		addr := `"address":"` + address.Uint160ToString(g.Address) + `"`
+		require.NoError(t, json.Unmarshal([]byte(`{`+addr+`,"unclaimed":"1"}`), new(UnclaimedGas)))
+		for _, s := range []string{
+			`{` + addr + `,"unclaimed":"1","voterreward":"x"}`,
+			`{` + addr + `,"unclaimed":"1","periods":[{"start":1,"end":2,"gasperblock":"x","amount":"1"}]}`,
+			`{` + addr + `,"unclaimed":"1","periods":[{"start":1,"end":2,"gasperblock":"1","amount":"x"}]}`,
+		} {
+			require.Error(t, json.Unmarshal([]byte(s), new(UnclaimedGas)), s)
+		}
+	})
+}
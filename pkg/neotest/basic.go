@@ -34,6 +34,9 @@ type Executor struct {
 	Committee     Signer
 	CommitteeHash util.Uint160
 	Contracts     map[string]*Contract
+
+	// collectCoverage is true if contract code coverage is collected.
+	collectCoverage bool
 }
 
 // NewExecutor creates a new executor instance from the provided blockchain and committee.
@@ -146,6 +149,9 @@ func (e *Executor) DeployContract(t testing.TB, c *Contract, data interface{}) u
 // data is an optional argument to `_deploy`.
 // It returns the hash of the deploy transaction.
 func (e *Executor) DeployContractBy(t testing.TB, signer Signer, c *Contract, data interface{}) util.Uint256 {
+	if e.collectCoverage {
+		addCoverageContract(c)
+	}
 	tx := NewDeployTxBy(t, e.Chain, signer, c, data)
 	e.AddNewBlock(t, tx)
 	e.CheckHalt(t, tx.Hash())
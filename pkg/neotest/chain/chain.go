@@ -131,6 +131,36 @@ func NewSingleWithCustomConfig(t testing.TB, f func(*config.ProtocolConfiguratio
 	return NewSingleWithCustomConfigAndStore(t, f, nil, true)
 }
 
+// NewSingleWithHardfork is similar to NewSingle, but enables all hard-forks
+// preceding the given one from the genesis block and the given hard-fork with
+// all the following ones at the specified height. It also enables
+// HardforkQueryExtension, so contracts can check hard-fork state via
+// System.Runtime.IsHardforkEnabled. It's useful for testing behaviour just
+// before and just after the hard-fork.
+func NewSingleWithHardfork(t testing.TB, hf config.Hardfork, height uint32) (*core.Blockchain, neotest.Signer) {
+	return NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.Hardforks = HardforkHeights(hf, height)
+		c.HardforkQueryExtension = true
+	})
+}
+
+// HardforkHeights returns Hardforks configuration with all hard-forks preceding
+// the given one enabled from the genesis block and the given hard-fork with all
+// the following ones enabled at the specified height.
+func HardforkHeights(hf config.Hardfork, height uint32) map[string]uint32 {
+	var (
+		res = make(map[string]uint32)
+		h   uint32
+	)
+	for _, cur := range config.OrderedHardforks() {
+		if cur == hf {
+			h = height
+		}
+		res[cur.String()] = h
+	}
+	return res
+}
+
 // NewSingleWithCustomConfigAndStore is similar to NewSingleWithCustomConfig, but
 // also allows to override backend Store being used. The last parameter controls if
 // Run method is called on the Blockchain instance. If not, it is its caller's
@@ -3,6 +3,7 @@ package chain
 import (
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/stretchr/testify/require"
 )
@@ -19,3 +20,14 @@ func TestNewMulti(t *testing.T) {
 	c := e.CommitteeInvoker(bc.UtilityTokenHash()).WithSigners(vAcc)
 	c.Invoke(t, true, "transfer", e.Validator.ScriptHash(), e.Committee.ScriptHash(), amount, nil)
 }
+
+func TestHardforkHeights(t *testing.T) {
+	require.Equal(t, map[string]uint32{
+		config.HFAspidochelone.String(): 5,
+		config.HFBasilisk.String():      5,
+	}, HardforkHeights(config.HFAspidochelone, 5))
+	require.Equal(t, map[string]uint32{
+		config.HFAspidochelone.String(): 0,
+		config.HFBasilisk.String():      5,
+	}, HardforkHeights(config.HFBasilisk, 5))
+}
@@ -19,6 +19,9 @@ type Contract struct {
 	Hash     util.Uint160
 	NEF      *nef.File
 	Manifest *manifest.Manifest
+	// DebugInfo is the contract debug info, it's used for coverage
+	// collection (if available).
+	DebugInfo *compiler.DebugInfo
 }
 
 // contracts caches the compiled contracts from FS across multiple tests.
@@ -36,9 +39,10 @@ func CompileSource(t testing.TB, sender util.Uint160, src io.Reader, opts *compi
 	require.NoError(t, err)
 
 	return &Contract{
-		Hash:     state.CreateContractHash(sender, ne.Checksum, m.Name),
-		NEF:      ne,
-		Manifest: m,
+		Hash:      state.CreateContractHash(sender, ne.Checksum, m.Name),
+		NEF:       ne,
+		Manifest:  m,
+		DebugInfo: di,
 	}
 }
 
@@ -72,9 +76,10 @@ func CompileFile(t testing.TB, sender util.Uint160, srcPath string, configPath s
 	require.NoError(t, err)
 
 	c := &Contract{
-		Hash:     state.CreateContractHash(sender, ne.Checksum, m.Name),
-		NEF:      ne,
-		Manifest: m,
+		Hash:      state.CreateContractHash(sender, ne.Checksum, m.Name),
+		NEF:       ne,
+		Manifest:  m,
+		DebugInfo: di,
 	}
 	contracts[srcPath] = c
 	return c
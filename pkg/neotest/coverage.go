@@ -0,0 +1,155 @@
+package neotest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// coverage contains contract execution data collected by all executors with
+// coverage enabled. It's global because coverage report is usually produced
+// once for the whole test run.
+var coverage = struct {
+	lock sync.Mutex
+	// debugInfo contains debug info of the deployed contracts.
+	debugInfo map[util.Uint160]*compiler.DebugInfo
+	// hits contains the number of executions of every visited instruction
+	// offset per contract.
+	hits map[util.Uint160]map[int]int
+}{
+	debugInfo: make(map[util.Uint160]*compiler.DebugInfo),
+	hits:      make(map[util.Uint160]map[int]int),
+}
+
+// EnableCoverage enables contract code coverage collection for all
+// invocations made via this executor's chain (including test invocations).
+// Only contracts deployed with DeployContract* methods after coverage is
+// enabled and compiled with debug info are included into the report produced
+// by WriteCoverage.
+func (e *Executor) EnableCoverage() {
+	e.collectCoverage = true
+	e.Chain.SetOnExecHook(coverageHook)
+}
+
+// DisableCoverage disables contract code coverage collection, data collected
+// so far is kept.
+func (e *Executor) DisableCoverage() {
+	e.collectCoverage = false
+	e.Chain.SetOnExecHook(nil)
+}
+
+// addCoverageContract registers the contract to be included into the coverage
+// report.
+func addCoverageContract(c *Contract) {
+	if c.DebugInfo == nil {
+		return
+	}
+	coverage.lock.Lock()
+	coverage.debugInfo[c.Hash] = c.DebugInfo
+	coverage.lock.Unlock()
+}
+
+// coverageHook is a vm.OnExecHook collecting visited instruction offsets.
+func coverageHook(scriptHash util.Uint160, offset int, _ opcode.Opcode) {
+	coverage.lock.Lock()
+	defer coverage.lock.Unlock()
+	if _, ok := coverage.debugInfo[scriptHash]; !ok {
+		return
+	}
+	hits := coverage.hits[scriptHash]
+	if hits == nil {
+		hits = make(map[int]int)
+		coverage.hits[scriptHash] = hits
+	}
+	hits[offset]++
+}
+
+// coverageBlock is a single Go coverage profile block.
+type coverageBlock struct {
+	file                string
+	startLine, startCol int
+	endLine, endCol     int
+	count               int
+}
+
+// WriteCoverage writes coverage profile of all contracts registered by
+// executors with coverage enabled to w. The profile is in the Go toolchain
+// format ("count" mode) with every sequence point being a separate statement,
+// so it can be processed with `go tool cover`. Source file paths are made
+// absolute.
+func WriteCoverage(w io.Writer) error {
+	coverage.lock.Lock()
+	blocks := make(map[coverageBlock]int)
+	for h, di := range coverage.debugInfo {
+		hits := coverage.hits[h]
+		for _, m := range di.Methods {
+			for _, sp := range m.SeqPoints {
+				if sp.Document < 0 || sp.Document >= len(di.Documents) {
+					continue
+				}
+				b := coverageBlock{
+					file:      di.Documents[sp.Document],
+					startLine: sp.StartLine,
+					startCol:  sp.StartCol,
+					endLine:   sp.EndLine,
+					endCol:    sp.EndCol,
+				}
+				// The same statement can have several sequence points
+				// (or be a part of different contracts), so the hits
+				// are summed up.
+				blocks[b] += hits[sp.Opcode]
+			}
+		}
+	}
+	coverage.lock.Unlock()
+
+	res := make([]coverageBlock, 0, len(blocks))
+	for b, count := range blocks {
+		if abs, err := filepath.Abs(b.file); err == nil {
+			b.file = abs
+		}
+		b.count = count
+		res = append(res, b)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].file != res[j].file {
+			return res[i].file < res[j].file
+		}
+		if res[i].startLine != res[j].startLine {
+			return res[i].startLine < res[j].startLine
+		}
+		return res[i].startCol < res[j].startCol
+	})
+
+	if _, err := fmt.Fprintln(w, "mode: count"); err != nil {
+		return err
+	}
+	for _, b := range res {
+		_, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d 1 %d\n", b.file, b.startLine, b.startCol, b.endLine, b.endCol, b.count)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCoverageFile is the same as WriteCoverage, but writes the profile to
+// the file with the given name.
+func WriteCoverageFile(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = WriteCoverage(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package neotest
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCoverage(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "contract.go")
+	c := &Contract{
+		Hash: util.Uint160{1, 2, 3},
+		DebugInfo: &compiler.DebugInfo{
+			Documents: []string{file},
+			Methods: []compiler.MethodDebugInfo{{
+				SeqPoints: []compiler.DebugSeqPoint{
+					{Opcode: 0, Document: 0, StartLine: 5, StartCol: 2, EndLine: 5, EndCol: 10},
+					{Opcode: 3, Document: 0, StartLine: 6, StartCol: 2, EndLine: 6, EndCol: 12},
+					{Opcode: 7, Document: 0, StartLine: 8, StartCol: 3, EndLine: 8, EndCol: 9},
+					{Opcode: 9, Document: 1, StartLine: 1, StartCol: 1, EndLine: 1, EndCol: 2},
+				},
+			}},
+		},
+	}
+	addCoverageContract(c)
+	addCoverageContract(&Contract{Hash: util.Uint160{4, 5, 6}}) // No debug info.
+
+	coverageHook(c.Hash, 0, opcode.PUSH1)
+	coverageHook(c.Hash, 1, opcode.PUSH2)
+	coverageHook(c.Hash, 3, opcode.ADD)
+	coverageHook(c.Hash, 3, opcode.ADD)
+	coverageHook(util.Uint160{4, 5, 6}, 0, opcode.RET)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, WriteCoverage(buf))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, "mode: count", lines[0])
+
+	var own []string
+	for _, l := range lines[1:] {
+		if strings.HasPrefix(l, file+":") {
+			own = append(own, l)
+		}
+	}
+	require.Equal(t, []string{
+		file + ":5.2,5.10 1 1",
+		file + ":6.2,6.12 1 2",
+		file + ":8.3,8.9 1 0",
+	}, own)
+
+	out := filepath.Join(dir, "cover.out")
+	require.NoError(t, WriteCoverageFile(out))
+	require.FileExists(t, out)
+}
@@ -15,5 +15,9 @@ Usually it's used like this:
 Higher-order methods provided in Executor and ContractInvoker hide the details
 of transaction creation for the most part, but there are lower-level methods as
 well that can be used for specific tasks.
+
+Contract code coverage can be collected by enabling it with
+Executor.EnableCoverage and written in Go coverage profile format with
+WriteCoverage (or WriteCoverageFile) after tests are done.
 */
 package neotest
@@ -0,0 +1,98 @@
+package network
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ASNMap maps IP networks to autonomous system numbers. It's used to group
+// peers by the AS they belong to for connection diversity purposes.
+type ASNMap struct {
+	// lengths contains network prefix lengths present in the map in
+	// descending order, so that the most specific match is found first.
+	lengths []int
+	// nets contains ASNs by prefix length and masked network address.
+	nets map[int]map[string]uint32
+}
+
+// LoadASNMap reads the ASN map from the given file, see ParseASNMap for the
+// format.
+func LoadASNMap(path string) (*ASNMap, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseASNMap(f)
+}
+
+// ParseASNMap parses the ASN map, it's a text with a network in CIDR notation
+// and an AS number (optionally prefixed with "AS") per line. Empty lines and
+// lines starting with '#' are ignored.
+func ParseASNMap(r io.Reader) (*ASNMap, error) {
+	var (
+		m = &ASNMap{nets: make(map[int]map[string]uint32)}
+		s = bufio.NewScanner(r)
+		n int
+	)
+	for s.Scan() {
+		n++
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected network and AS number", n)
+		}
+		_, ipNet, err := net.ParseCIDR(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(fields[1]), "AS"), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid AS number: %w", n, err)
+		}
+		ones, _ := ipNet.Mask.Size()
+		if ip4 := ipNet.IP.To4(); ip4 == nil {
+			ones += 8 * net.IPv6len // Keep IPv4 and IPv6 lengths apart.
+		}
+		if m.nets[ones] == nil {
+			m.nets[ones] = make(map[string]uint32)
+			m.lengths = append(m.lengths, ones)
+		}
+		m.nets[ones][ipNet.String()] = uint32(asn)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(m.lengths)))
+	return m, nil
+}
+
+// Lookup returns the AS number of the most specific network containing the
+// given IP.
+func (m *ASNMap) Lookup(ip net.IP) (uint32, bool) {
+	bits := 8 * net.IPv6len
+	offset := bits
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits, offset = ip4, 8*net.IPv4len, 0
+	}
+	for _, l := range m.lengths {
+		ones := l - offset
+		if ones < 0 || ones > bits {
+			continue
+		}
+		mask := net.CIDRMask(ones, bits)
+		if asn, ok := m.nets[l][(&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()]; ok {
+			return asn, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,54 @@
+package network
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestASNMap(t *testing.T) {
+	m, err := ParseASNMap(strings.NewReader(`# Comment.
+1.2.0.0/16   AS100
+
+1.2.3.0/24   as200
+2001:db8::/32 300
+0.0.0.0/0    400
+`))
+	require.NoError(t, err)
+
+	for ip, asn := range map[string]uint32{
+		"1.2.4.5":          100,
+		"1.2.3.4":          200,
+		"::ffff:1.2.3.4":   200,
+		"2001:db8:1::1":    300,
+		"8.8.8.8":          400,
+		"2001:db9:1::1":    0,
+		"::ffff:10.20.1.1": 400,
+	} {
+		actual, ok := m.Lookup(net.ParseIP(ip))
+		require.Equal(t, asn != 0, ok, ip)
+		require.Equal(t, asn, actual, ip)
+	}
+
+	for _, bad := range []string{"1.2.0.0/16", "1.2.0.0/16 AS1 AS2", "1.2.0.0 AS1", "1.2.0.0/16 ASX"} {
+		_, err := ParseASNMap(strings.NewReader(bad))
+		require.Error(t, err, bad)
+	}
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "asn.txt")
+		require.NoError(t, os.WriteFile(path, []byte("1.2.0.0/16 1\n"), 0644))
+		m, err := LoadASNMap(path)
+		require.NoError(t, err)
+		asn, ok := m.Lookup(net.ParseIP("1.2.3.4"))
+		require.True(t, ok)
+		require.Equal(t, uint32(1), asn)
+
+		_, err = LoadASNMap(filepath.Join(t.TempDir(), "unknown"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,204 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+)
+
+// defaultBlockChunkTimeout is the time a peer has to deliver the requested
+// chunk of blocks before the chunk is reassigned to some other peer.
+const defaultBlockChunkTimeout = 15 * time.Second
+
+type (
+	// blockChunk is a contiguous range of blocks requested from a single peer.
+	blockChunk struct {
+		start, end uint32
+		peer       Peer
+		deadline   time.Time
+		received   []bool
+		left       int
+	}
+
+	// blockFetcher partitions the range of missing blocks into chunks of
+	// payload.MaxHashesCount and assigns them to distinct peers, so that
+	// blocks are fetched from all of them in parallel. Every peer has at most
+	// one chunk in flight, chunks that are not delivered in time are
+	// reassigned to other peers. Blocks are still applied strictly in order
+	// by the blockQueue.
+	blockFetcher struct {
+		lock    sync.Mutex
+		timeout time.Duration
+		now     func() time.Time
+		// chunks are sorted by start and don't intersect.
+		chunks   []*blockChunk
+		inflight map[Peer]*blockChunk
+		// stalled contains peers that failed to deliver their chunk in time
+		// and the time they can be given a new one.
+		stalled map[Peer]time.Time
+	}
+)
+
+func newBlockFetcher(timeout time.Duration) *blockFetcher {
+	if timeout <= 0 {
+		timeout = defaultBlockChunkTimeout
+	}
+	return &blockFetcher{
+		timeout:  timeout,
+		now:      time.Now,
+		inflight: make(map[Peer]*blockChunk),
+		stalled:  make(map[Peer]time.Time),
+	}
+}
+
+// assign gives the chunk to the given peer returning the request for its
+// blocks above the current height.
+func (f *blockFetcher) assign(c *blockChunk, p Peer, height uint32, now time.Time) *payload.GetBlockByIndex {
+	if c.peer != nil {
+		f.release(c.peer)
+	}
+	c.peer = p
+	c.deadline = now.Add(f.timeout)
+	f.inflight[p] = c
+	delete(f.stalled, p)
+	updateInflightBlocksMetric(p.PeerAddr().String(), c.left)
+
+	start := c.start
+	if start <= height {
+		start = height + 1
+	}
+	return payload.NewGetBlockByIndex(start, int16(c.end-start+1))
+}
+
+// release removes the chunk assignment of the given peer.
+func (f *blockFetcher) release(p Peer) {
+	c, ok := f.inflight[p]
+	if !ok {
+		return
+	}
+	delete(f.inflight, p)
+	if c.peer == p {
+		c.peer = nil
+	}
+	removeInflightBlocksMetric(p.PeerAddr().String())
+}
+
+// cleanup drops chunks that are completely below the given height.
+func (f *blockFetcher) cleanup(height uint32) {
+	var i int
+	for i = 0; i < len(f.chunks) && f.chunks[i].end <= height; i++ {
+		if p := f.chunks[i].peer; p != nil {
+			f.release(p)
+		}
+	}
+	f.chunks = f.chunks[i:]
+}
+
+// next returns the next request to be sent to the given peer (nil if there
+// is nothing to request from it now) given the current height of the block
+// queue. Expired chunks are reassigned first, new chunks are created after
+// the last known one.
+func (f *blockFetcher) next(p Peer, height uint32) *payload.GetBlockByIndex {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var (
+		now        = f.now()
+		peerHeight = p.LastBlockIndex()
+	)
+	f.cleanup(height)
+	if c, ok := f.inflight[p]; ok {
+		if now.Before(c.deadline) {
+			return nil
+		}
+		f.release(p)
+		f.stalled[p] = now.Add(f.timeout)
+		return nil
+	}
+	if until, ok := f.stalled[p]; ok && now.Before(until) {
+		return nil
+	}
+	for _, c := range f.chunks {
+		if c.left == 0 || c.end > peerHeight {
+			continue
+		}
+		if c.peer == nil || !now.Before(c.deadline) {
+			if c.peer != nil {
+				f.stalled[c.peer] = now.Add(f.timeout)
+			}
+			return f.assign(c, p, height, now)
+		}
+	}
+
+	start := height + 1
+	if len(f.chunks) != 0 {
+		start = f.chunks[len(f.chunks)-1].end + 1
+	}
+	if start > peerHeight || start > height+blockCacheSize {
+		return nil
+	}
+	end := start + payload.MaxHashesCount - 1
+	if end > peerHeight {
+		end = peerHeight
+	}
+	if end > height+blockCacheSize {
+		end = height + blockCacheSize
+	}
+	c := &blockChunk{
+		start:    start,
+		end:      end,
+		received: make([]bool, end-start+1),
+		left:     int(end - start + 1),
+	}
+	f.chunks = append(f.chunks, c)
+	return f.assign(c, p, height, now)
+}
+
+// received marks the block with the given index as received. It returns true
+// if this block completes the chunk assigned to the given peer, so that it can
+// be given a new one.
+func (f *blockFetcher) received(p Peer, index uint32) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, c := range f.chunks {
+		if index < c.start || index > c.end {
+			continue
+		}
+		if i := index - c.start; !c.received[i] {
+			c.received[i] = true
+			c.left--
+		}
+		if c.peer == nil {
+			return false
+		}
+		if c.left != 0 {
+			updateInflightBlocksMetric(c.peer.PeerAddr().String(), c.left)
+			return false
+		}
+		completed := c.peer == p
+		f.release(c.peer)
+		return completed
+	}
+	return false
+}
+
+// peerDropped releases the chunk assigned to the disconnected peer.
+func (f *blockFetcher) peerDropped(p Peer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.release(p)
+	delete(f.stalled, p)
+}
+
+// reset drops all chunks and assignments.
+func (f *blockFetcher) reset() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for p := range f.inflight {
+		f.release(p)
+	}
+	f.chunks = nil
+	f.stalled = make(map[Peer]time.Time)
+}
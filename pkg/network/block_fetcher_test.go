@@ -0,0 +1,160 @@
+package network
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockFetcher(t *testing.T) {
+	var (
+		now = time.Now()
+		f   = newBlockFetcher(time.Second)
+	)
+	f.now = func() time.Time { return now }
+	newPeer := func(height uint32) *localPeer {
+		p := newLocalPeer(t, nil)
+		p.lastBlockIndex = height
+		return p
+	}
+	checkReq := func(t *testing.T, pl *payload.GetBlockByIndex, start uint32, count int16) {
+		require.NotNil(t, pl)
+		require.Equal(t, start, pl.IndexStart)
+		require.Equal(t, count, pl.Count)
+	}
+	p1, p2, p3 := newPeer(1200), newPeer(1200), newPeer(300)
+
+	// Distinct chunks for distinct peers.
+	checkReq(t, f.next(p1, 0), 1, payload.MaxHashesCount)
+	require.Nil(t, f.next(p1, 0)) // Busy.
+	checkReq(t, f.next(p2, 0), 501, payload.MaxHashesCount)
+	require.Nil(t, f.next(p3, 0)) // Can't provide anything.
+
+	// The next chunk is available right after the previous one is received.
+	for i := uint32(501); i < 1000; i++ {
+		require.False(t, f.received(p2, i))
+	}
+	require.True(t, f.received(p2, 1000))
+	checkReq(t, f.next(p2, 0), 1001, 200)
+
+	// Stalled chunks are reassigned to other peers.
+	now = now.Add(time.Second)
+	p3.lastBlockIndex = 1200
+	checkReq(t, f.next(p3, 0), 1, payload.MaxHashesCount)
+	require.Nil(t, f.next(p1, 0)) // Stalled one has to wait.
+	require.Nil(t, f.next(p2, 0)) // Its chunk is expired as well.
+	require.False(t, f.received(p1, 1))
+	for i := uint32(2); i <= 500; i++ {
+		require.Equal(t, i == 500, f.received(p3, i))
+	}
+
+	// Only missing blocks are requested.
+	checkReq(t, f.next(p3, 1050), 1051, 150)
+
+	// Chunks are released on disconnection.
+	f.peerDropped(p3)
+	now = now.Add(time.Second)
+	checkReq(t, f.next(p1, 1050), 1051, 150)
+
+	// Chunks below the height are dropped.
+	require.Nil(t, f.next(p2, 1200))
+	require.Equal(t, 0, len(f.chunks))
+	require.Equal(t, 0, len(f.inflight))
+
+	// The number of blocks requested is limited by the block queue size.
+	p1.lastBlockIndex = 5000
+	for i := 0; i < blockCacheSize/payload.MaxHashesCount; i++ {
+		checkReq(t, f.next(newPeer(5000), 1200), 1201+uint32(i*payload.MaxHashesCount), payload.MaxHashesCount)
+	}
+	require.Nil(t, f.next(p1, 1200))
+
+	f.reset()
+	checkReq(t, f.next(p1, 1200), 1201, payload.MaxHashesCount)
+}
+
+// slowPeer is a localPeer that answers block requests with the given delay
+// (or doesn't answer at all if it's zero).
+type slowPeer struct {
+	*localPeer
+	delay     time.Duration
+	lock      sync.Mutex
+	requested int
+}
+
+func newSlowPeer(t *testing.T, s *Server, blocks []*block.Block, delay time.Duration) *slowPeer {
+	p := &slowPeer{localPeer: newLocalPeer(t, s), delay: delay}
+	p.handshaked = 1
+	p.lastBlockIndex = blocks[len(blocks)-1].Index
+	p.messageHandler = func(t *testing.T, msg *Message) {
+		if msg.Command != CMDGetBlockByIndex {
+			return
+		}
+		p.lock.Lock()
+		p.requested++
+		p.lock.Unlock()
+		if p.delay == 0 {
+			return
+		}
+		pl := msg.Payload.(*payload.GetBlockByIndex)
+		go func() {
+			time.Sleep(p.delay)
+			for i := pl.IndexStart; i < pl.IndexStart+uint32(pl.Count); i++ {
+				_ = s.handleBlockCmd(p, blocks[i-1])
+			}
+		}()
+	}
+	return p
+}
+
+func (p *slowPeer) requests() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.requested
+}
+
+func TestParallelBlockFetching(t *testing.T) {
+	const delay = 200 * time.Millisecond
+
+	blocks := make([]*block.Block, 4*payload.MaxHashesCount)
+	for i := range blocks {
+		blocks[i] = newDummyBlock(uint32(i+1), 0)
+	}
+	fetch := func(t *testing.T, delays ...time.Duration) (time.Duration, []*slowPeer) {
+		s := newTestServer(t, ServerConfig{})
+		s.bFetcher.timeout = delay * 2
+		go s.bQueue.run()
+		t.Cleanup(s.bQueue.discard)
+
+		peers := make([]*slowPeer, len(delays))
+		for i := range delays {
+			peers[i] = newSlowPeer(t, s, blocks, delays[i])
+		}
+		start := time.Now()
+		require.Eventually(t, func() bool {
+			// Proto ticks.
+			for _, p := range peers {
+				assert.NoError(t, s.requestBlocksOrHeaders(p))
+			}
+			return s.chain.BlockHeight() == uint32(len(blocks))
+		}, 10*time.Second, delay/10)
+		return time.Since(start), peers
+	}
+
+	single, _ := fetch(t, delay)
+	parallel, peers := fetch(t, delay, delay, delay, delay)
+	for _, p := range peers {
+		require.Equal(t, 1, p.requests())
+	}
+	require.Less(t, int64(parallel), int64(single))
+
+	t.Run("stalled peer", func(t *testing.T) {
+		// Its chunk is reassigned, so the sync still succeeds.
+		_, peers := fetch(t, delay, delay, 0, delay)
+		require.NotZero(t, peers[2].requests())
+	})
+}
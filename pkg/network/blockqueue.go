@@ -2,6 +2,7 @@ package network
 
 import (
 	"sync"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"go.uber.org/atomic"
@@ -24,6 +25,8 @@ type blockQueue struct {
 	chain       Blockqueuer
 	relayF      func(*block.Block)
 	discarded   *atomic.Bool
+	draining    *atomic.Bool
+	drained     chan struct{}
 	len         int
 }
 
@@ -49,6 +52,8 @@ func newBlockQueue(capacity int, bc Blockqueuer, log *zap.Logger, relayer func(*
 		chain:       bc,
 		relayF:      relayer,
 		discarded:   atomic.NewBool(false),
+		draining:    atomic.NewBool(false),
+		drained:     make(chan struct{}, 1),
 	}
 }
 
@@ -75,20 +80,33 @@ func (bq *blockQueue) run() {
 			bq.queueLock.Unlock()
 			lastHeight = h
 			if b == nil {
+				if bq.draining.Load() {
+					select {
+					case bq.drained <- struct{}{}:
+					default:
+					}
+				}
 				break
 			}
 
+			start := time.Now()
 			err := bq.chain.AddBlock(b)
 			if err != nil {
 				// The block might already be added by the consensus.
-				if bq.chain.BlockHeight() < b.Index {
+				if bq.chain.BlockHeight() >= b.Index {
+					addBlockRejectedMetric(rejectAlreadyKnown)
+				} else {
+					addBlockRejectedMetric(rejectReason(err))
 					bq.log.Warn("blockQueue: failed adding block into the blockchain",
 						zap.String("error", err.Error()),
 						zap.Uint32("blockHeight", bq.chain.BlockHeight()),
 						zap.Uint32("nextIndex", b.Index))
 				}
-			} else if bq.relayF != nil {
-				bq.relayF(b)
+			} else {
+				observeBlockProcessTimeMetric(time.Since(start))
+				if bq.relayF != nil {
+					bq.relayF(b)
+				}
 			}
 			bq.queueLock.Lock()
 			bq.len--
@@ -106,7 +124,7 @@ func (bq *blockQueue) putBlock(block *block.Block) error {
 	h := bq.chain.BlockHeight()
 	bq.queueLock.Lock()
 	defer bq.queueLock.Unlock()
-	if bq.discarded.Load() {
+	if bq.discarded.Load() || bq.draining.Load() {
 		return nil
 	}
 	if block.Index <= h || h+blockCacheSize < block.Index {
@@ -155,3 +173,37 @@ func (bq *blockQueue) discard() {
 		bq.queueLock.Unlock()
 	}
 }
+
+// drain stops accepting new blocks and waits for the queued ones that can be
+// added to the chain (that is, the ones following the current height without
+// gaps) to be processed, but no longer than the given timeout. The queue is
+// discarded afterwards, so the blocks left there are lost.
+func (bq *blockQueue) drain(timeout time.Duration) {
+	if bq.discarded.Load() || !bq.draining.CAS(false, true) {
+		return
+	}
+	bq.queueLock.RLock()
+	empty := bq.len == 0
+	bq.queueLock.RUnlock()
+	if empty {
+		bq.discard()
+		return
+	}
+	select {
+	case bq.checkBlocks <- struct{}{}:
+	default:
+	}
+	t := time.NewTimer(timeout)
+	select {
+	case <-bq.drained:
+	case <-t.C:
+		bq.queueLock.RLock()
+		l := bq.len
+		bq.queueLock.RUnlock()
+		bq.log.Warn("blockQueue: failed to drain in time",
+			zap.Int("left", l),
+			zap.Uint32("blockHeight", bq.chain.BlockHeight()))
+	}
+	t.Stop()
+	bq.discard()
+}
@@ -6,7 +6,9 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/fakechain"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 )
 
@@ -72,6 +74,71 @@ func TestBlockQueue(t *testing.T) {
 	assert.Equal(t, 0, bq.length())
 }
 
+func TestBlockQueueDrain(t *testing.T) {
+	blocks := make([]*block.Block, 8)
+	for i := 1; i < 8; i++ {
+		blocks[i] = &block.Block{Header: block.Header{Index: uint32(i)}}
+	}
+	t.Run("running", func(t *testing.T) {
+		chain := fakechain.NewFakeChain()
+		bq := newBlockQueue(0, chain, zaptest.NewLogger(t), nil)
+		for _, i := range []int{1, 2, 3, 4, 5, 7} {
+			require.NoError(t, bq.putBlock(blocks[i]))
+		}
+		go bq.run()
+		bq.drain(4 * time.Second)
+		// Block 7 can't be added without block 6.
+		require.Equal(t, uint32(5), chain.BlockHeight())
+		require.True(t, bq.discarded.Load())
+		require.Equal(t, 0, bq.length())
+
+		require.NoError(t, bq.putBlock(blocks[6]))
+		require.Equal(t, 0, bq.length())
+		bq.drain(time.Second) // No-op.
+	})
+	t.Run("empty", func(t *testing.T) {
+		bq := newBlockQueue(0, fakechain.NewFakeChain(), zaptest.NewLogger(t), nil)
+		bq.drain(time.Hour) // Not even running, but returns immediately.
+		require.True(t, bq.discarded.Load())
+	})
+	t.Run("timeout", func(t *testing.T) {
+		chain := fakechain.NewFakeChain()
+		bq := newBlockQueue(0, chain, zaptest.NewLogger(t), nil)
+		require.NoError(t, bq.putBlock(blocks[1]))
+		bq.drain(10 * time.Millisecond) // Not running.
+		require.Equal(t, uint32(0), chain.BlockHeight())
+		require.True(t, bq.discarded.Load())
+		require.Equal(t, 0, bq.length())
+	})
+}
+
+// blockProcessTimeCount returns the number of block processing time
+// observations made.
+func blockProcessTimeCount(t *testing.T) uint64 {
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, mf := range mfs {
+		if mf.GetName() == "neogo_block_process_time" {
+			return mf.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+	t.Fatal("no block processing time metric")
+	return 0
+}
+
+func TestBlockQueueProcessTimeMetric(t *testing.T) {
+	chain := fakechain.NewFakeChain()
+	bq := newBlockQueue(0, chain, zaptest.NewLogger(t), nil)
+	before := blockProcessTimeCount(t)
+	for i := 1; i < 4; i++ {
+		require.NoError(t, bq.putBlock(&block.Block{Header: block.Header{Index: uint32(i)}}))
+	}
+	go bq.run()
+	t.Cleanup(bq.discard)
+	require.Eventually(t, func() bool { return chain.BlockHeight() == 3 }, 4*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return blockProcessTimeCount(t)-before == 3 }, time.Second, 10*time.Millisecond)
+}
+
 // length wraps len access for tests to make them thread-safe.
 func (bq *blockQueue) length() int {
 	bq.queueLock.Lock()
@@ -15,6 +15,9 @@ type Capabilities []Capability
 // DecodeBinary implements io.Serializable.
 func (cs *Capabilities) DecodeBinary(br *io.BinReader) {
 	br.ReadArray(cs, MaxCapabilities)
+	if br.Err != nil {
+		return
+	}
 	br.Err = cs.checkUniqueCapabilities()
 }
 
@@ -26,7 +29,7 @@ func (cs *Capabilities) EncodeBinary(br *io.BinWriter) {
 // checkUniqueCapabilities checks whether payload capabilities have a unique type.
 func (cs Capabilities) checkUniqueCapabilities() error {
 	err := errors.New("capabilities with the same type are not allowed")
-	var isFullNode, isTCP, isWS bool
+	var isFullNode, isTCP, isWS, isCompression, isOnion bool
 	for _, cap := range cs {
 		switch cap.Type {
 		case FullNode:
@@ -44,6 +47,16 @@ func (cs Capabilities) checkUniqueCapabilities() error {
 				return err
 			}
 			isWS = true
+		case PayloadCompression:
+			if isCompression {
+				return err
+			}
+			isCompression = true
+		case OnionService:
+			if isOnion {
+				return err
+			}
+			isOnion = true
 		}
 	}
 	return nil
@@ -63,6 +76,10 @@ func (c *Capability) DecodeBinary(br *io.BinReader) {
 		c.Data = &Node{}
 	case TCPServer, WSServer:
 		c.Data = &Server{}
+	case PayloadCompression:
+		c.Data = &Compression{}
+	case OnionService:
+		c.Data = &Onion{}
 	default:
 		br.Err = errors.New("unknown node capability type")
 		return
@@ -110,3 +127,37 @@ func (s *Server) DecodeBinary(br *io.BinReader) {
 func (s *Server) EncodeBinary(bw *io.BinWriter) {
 	bw.WriteU16LE(s.Port)
 }
+
+// CompressionAlgorithm is a bit mask of payload compression algorithms.
+type CompressionAlgorithm byte
+
+// Supported payload compression algorithms.
+const (
+	// Deflate is DEFLATE (RFC 1951) compression.
+	Deflate CompressionAlgorithm = 1 << iota
+)
+
+// Compression represents payload compression capability with a set of
+// algorithms supported by the node.
+type Compression struct {
+	Algorithms CompressionAlgorithm
+}
+
+// DecodeBinary implements io.Serializable.
+func (c *Compression) DecodeBinary(br *io.BinReader) {
+	c.Algorithms = CompressionAlgorithm(br.ReadB())
+}
+
+// EncodeBinary implements io.Serializable.
+func (c *Compression) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteB(byte(c.Algorithms))
+}
+
+// Onion represents onion service marker, it has no data.
+type Onion struct{}
+
+// DecodeBinary implements io.Serializable.
+func (o *Onion) DecodeBinary(br *io.BinReader) {}
+
+// EncodeBinary implements io.Serializable.
+func (o *Onion) EncodeBinary(bw *io.BinWriter) {}
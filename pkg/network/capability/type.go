@@ -10,4 +10,13 @@ const (
 	WSServer Type = 0x02
 	// FullNode represents full node capability type.
 	FullNode Type = 0x10
+	// PayloadCompression represents payload compression capability type.
+	// It's a NeoGo extension that is not a part of the Neo protocol (other
+	// nodes reject version messages containing it), so it's only advertised
+	// when enabled in the configuration.
+	PayloadCompression Type = 0x20
+	// OnionService marks Tor onion service addresses in the address pool.
+	// It's a NeoGo extension that is never sent to other nodes, onion
+	// addresses can only be used when the proxy is configured.
+	OnionService Type = 0xf0
 )
@@ -1,8 +1,11 @@
 package network
 
 import (
+	"bytes"
+	"compress/flate"
 	"encoding/binary"
 	"errors"
+	gio "io"
 
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/pierrec/lz4"
@@ -21,12 +24,9 @@ func compress(source []byte) ([]byte, error) {
 
 // decompress decompresses bytes using lz4.
 func decompress(source []byte) ([]byte, error) {
-	if len(source) < 4 {
-		return nil, errors.New("invalid compressed payload")
-	}
-	length := binary.LittleEndian.Uint32(source[:4])
-	if length > payload.MaxSize {
-		return nil, errors.New("invalid uncompressed payload length")
+	length, err := uncompressedLength(source)
+	if err != nil {
+		return nil, err
 	}
 	dest := make([]byte, length)
 	size, err := lz4.UncompressBlock(source[4:], dest)
@@ -38,3 +38,64 @@ func decompress(source []byte) ([]byte, error) {
 	}
 	return dest, nil
 }
+
+// compressDeflate compresses bytes using DEFLATE. The result has the same
+// layout as the lz4 one: 4-byte LE uncompressed length followed by data.
+func compressDeflate(source []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(4 + len(source)/2)
+	buf.Write(make([]byte, 4))
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(source); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	dest := buf.Bytes()
+	binary.LittleEndian.PutUint32(dest[:4], uint32(len(source)))
+	return dest, nil
+}
+
+// decompressDeflate decompresses bytes using DEFLATE.
+func decompressDeflate(source []byte) ([]byte, error) {
+	length, err := uncompressedLength(source)
+	if err != nil {
+		return nil, err
+	}
+	src := bytes.NewReader(source[4:])
+	r := flate.NewReader(src)
+	defer r.Close()
+	dest := make([]byte, length)
+	if _, err = gio.ReadFull(r, dest); err != nil {
+		if errors.Is(err, gio.EOF) || errors.Is(err, gio.ErrUnexpectedEOF) {
+			return nil, errors.New("decompressed payload size doesn't match header")
+		}
+		return nil, err
+	}
+	// There must be nothing left in the stream. bytes.Reader is an
+	// io.ByteReader, so the decompressor doesn't read ahead.
+	if n, err := r.Read(make([]byte, 1)); n != 0 || !errors.Is(err, gio.EOF) {
+		return nil, errors.New("decompressed payload size doesn't match header")
+	}
+	if src.Len() != 0 {
+		return nil, errors.New("trailing data after compressed payload")
+	}
+	return dest, nil
+}
+
+// uncompressedLength returns the uncompressed payload length from the
+// compressed payload header.
+func uncompressedLength(source []byte) (uint32, error) {
+	if len(source) < 4 {
+		return 0, errors.New("invalid compressed payload")
+	}
+	length := binary.LittleEndian.Uint32(source[:4])
+	if length > payload.MaxSize {
+		return 0, errors.New("invalid uncompressed payload length")
+	}
+	return length, nil
+}
@@ -1,7 +1,11 @@
 package network
 
 import (
+	"fmt"
 	"math"
+	"net"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,48 +33,217 @@ type Discoverer interface {
 	UnconnectedPeers() []string
 	BadPeers() []string
 	GoodPeers() []AddressWithCapabilities
+	LoadGoodPeers([]AddressWithCapabilities)
 }
 
 // AddressWithCapabilities represents a node address with its capabilities.
 type AddressWithCapabilities struct {
 	Address      string
 	Capabilities capability.Capabilities
+	// LastSeen is the last time the node was connected to.
+	LastSeen time.Time
+}
+
+// DiversityConfig restricts the number of connections to nodes sharing the
+// same autonomous system (if known from ASN map) or the same network prefix
+// (/16 for IPv4 and /32 for IPv6).
+type DiversityConfig struct {
+	// MaxPerPrefix is the maximum number of connections per prefix, zero
+	// means no limit.
+	MaxPerPrefix int
+	// Trusted contains networks not subject to the limit.
+	Trusted []*net.IPNet
+	// ASN is an optional ASN map used to group addresses by AS.
+	ASN *ASNMap
 }
 
 // DefaultDiscovery default implementation of the Discoverer interface.
 type DefaultDiscovery struct {
-	seeds            []string
-	transport        Transporter
-	lock             sync.RWMutex
-	dialTimeout      time.Duration
-	badAddrs         map[string]bool
-	connectedAddrs   map[string]bool
-	goodAddrs        map[string]capability.Capabilities
+	seeds          []string
+	transport      Transporter
+	lock           sync.RWMutex
+	dialTimeout    time.Duration
+	diversity      DiversityConfig
+	badAddrs       map[string]bool
+	connectedAddrs map[string]bool
+	goodAddrs      map[string]capability.Capabilities
+	// lastSeen contains the last connection time for good addresses that
+	// are not connected at the moment.
+	lastSeen         map[string]time.Time
 	unconnectedAddrs map[string]int
 	attempted        map[string]bool
-	optimalFanOut    int32
-	networkSize      int32
-	requestCh        chan int
+	// prefixes contains network prefixes of good addresses.
+	prefixes map[string]string
+	// prefixConns counts connected and attempted addresses per prefix.
+	prefixConns map[string]int
+	// poolPrefixes counts unconnected addresses per prefix.
+	poolPrefixes  map[string]int
+	optimalFanOut int32
+	networkSize   int32
+	requestCh     chan int
 }
 
 // NewDefaultDiscovery returns a new DefaultDiscovery.
-func NewDefaultDiscovery(addrs []string, dt time.Duration, ts Transporter) *DefaultDiscovery {
+func NewDefaultDiscovery(addrs []string, dt time.Duration, div DiversityConfig, ts Transporter) *DefaultDiscovery {
 	d := &DefaultDiscovery{
 		seeds:            addrs,
 		transport:        ts,
 		dialTimeout:      dt,
+		diversity:        div,
 		badAddrs:         make(map[string]bool),
 		connectedAddrs:   make(map[string]bool),
 		goodAddrs:        make(map[string]capability.Capabilities),
+		lastSeen:         make(map[string]time.Time),
 		unconnectedAddrs: make(map[string]int),
 		attempted:        make(map[string]bool),
+		prefixes:         make(map[string]string),
+		prefixConns:      make(map[string]int),
+		poolPrefixes:     make(map[string]int),
 		requestCh:        make(chan int),
 	}
 	return d
 }
 
-func newDefaultDiscovery(addrs []string, dt time.Duration, ts Transporter) Discoverer {
-	return NewDefaultDiscovery(addrs, dt, ts)
+func newDefaultDiscovery(addrs []string, dt time.Duration, div DiversityConfig, ts Transporter) Discoverer {
+	return NewDefaultDiscovery(addrs, dt, div, ts)
+}
+
+// ParseTrustedNets parses the list of IP addresses and CIDR networks.
+func ParseTrustedNets(addrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(addrs))
+	for _, addr := range addrs {
+		if strings.Contains(addr, "/") {
+			_, n, err := net.ParseCIDR(addr)
+			if err != nil {
+				return nil, err
+			}
+			nets = append(nets, n)
+			continue
+		}
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address %q", addr)
+		}
+		bits := 8 * net.IPv6len
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 8*net.IPv4len
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
+
+// netPrefix returns the network prefix of the given address (/16 for IPv4 and
+// /32 for IPv6), for non-IP addresses it returns the host itself.
+func netPrefix(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+	mask := net.CIDRMask(32, 8*net.IPv6len)
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, mask = ip4, net.CIDRMask(16, 8*net.IPv4len)
+	}
+	return (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+}
+
+// prefix returns the network prefix of the given address, it uses the recorded
+// one for good addresses.
+func (d *DefaultDiscovery) prefix(addr string) string {
+	if p, ok := d.prefixes[addr]; ok {
+		return p
+	}
+	return d.netGroup(addr)
+}
+
+// netGroup returns the group the given address belongs to for diversity
+// purposes, it's either the AS number (if it's known from the ASN map) or the
+// network prefix.
+func (d *DefaultDiscovery) netGroup(addr string) string {
+	if d.diversity.ASN != nil {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			if asn, ok := d.diversity.ASN.Lookup(ip); ok {
+				return "AS" + strconv.FormatUint(uint64(asn), 10)
+			}
+		}
+	}
+	return netPrefix(addr)
+}
+
+// isTrusted checks whether the given address is not subject to the diversity
+// limits.
+func (d *DefaultDiscovery) isTrusted(addr string) bool {
+	for _, seed := range d.seeds {
+		if addr == seed {
+			return true
+		}
+	}
+	if len(d.diversity.Trusted) == 0 {
+		return false
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range d.diversity.Trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// addPrefixConn accounts for the new connection (or connection attempt) to
+// the given address.
+func (d *DefaultDiscovery) addPrefixConn(addr string) {
+	d.prefixConns[d.prefix(addr)]++
+}
+
+// removePrefixConn accounts for the closed connection (or finished connection
+// attempt) to the given address.
+func (d *DefaultDiscovery) removePrefixConn(addr string) {
+	p := d.prefix(addr)
+	d.prefixConns[p]--
+	if d.prefixConns[p] <= 0 {
+		delete(d.prefixConns, p)
+	}
+}
+
+// addToPool adds the address to the unconnected set with the given number of
+// connection retries.
+func (d *DefaultDiscovery) addToPool(addr string, retries int) {
+	if _, ok := d.unconnectedAddrs[addr]; !ok {
+		p := d.prefix(addr)
+		d.poolPrefixes[p]++
+		updatePoolPrefixCountMetric(p, d.poolPrefixes[p])
+	}
+	d.unconnectedAddrs[addr] = retries
+}
+
+// removeFromPool removes the address from the unconnected set.
+func (d *DefaultDiscovery) removeFromPool(addr string) {
+	if _, ok := d.unconnectedAddrs[addr]; !ok {
+		return
+	}
+	delete(d.unconnectedAddrs, addr)
+	p := d.prefix(addr)
+	d.poolPrefixes[p]--
+	updatePoolPrefixCountMetric(p, d.poolPrefixes[p])
+	if d.poolPrefixes[p] <= 0 {
+		delete(d.poolPrefixes, p)
+	}
 }
 
 // BackFill implements the Discoverer interface and will backfill
@@ -107,22 +280,15 @@ func (d *DefaultDiscovery) poolCount() int {
 // is already full, it just drops it.
 func (d *DefaultDiscovery) pushToPoolOrDrop(addr string) {
 	if len(d.unconnectedAddrs) < maxPoolSize {
-		d.unconnectedAddrs[addr] = connRetries
+		d.addToPool(addr, connRetries)
 	}
 }
 
 // RequestRemote tries to establish a connection with n nodes.
 func (d *DefaultDiscovery) RequestRemote(requested int) {
 	for ; requested > 0; requested-- {
-		var nextAddr string
 		d.lock.Lock()
-		for addr := range d.unconnectedAddrs {
-			if !d.connectedAddrs[addr] && !d.attempted[addr] {
-				nextAddr = addr
-				break
-			}
-		}
-
+		nextAddr := d.pickUnconnected()
 		if nextAddr == "" {
 			// Empty pool, try seeds.
 			for _, addr := range d.seeds {
@@ -139,11 +305,44 @@ func (d *DefaultDiscovery) RequestRemote(requested int) {
 			break
 		}
 		d.attempted[nextAddr] = true
+		d.addPrefixConn(nextAddr)
 		d.lock.Unlock()
 		go d.tryAddress(nextAddr)
 	}
 }
 
+// pickUnconnected returns the unconnected address to dial preferring networks
+// we have the least number of connections to. Addresses from networks that
+// have reached the limit are skipped unless they're trusted. It must be called
+// with the lock held.
+func (d *DefaultDiscovery) pickUnconnected() string {
+	var (
+		best  string
+		bestN = -1
+	)
+	for addr := range d.unconnectedAddrs {
+		if d.connectedAddrs[addr] || d.attempted[addr] {
+			continue
+		}
+		if d.diversity.MaxPerPrefix == 0 {
+			return addr
+		}
+		n := d.prefixConns[d.prefix(addr)]
+		if d.isTrusted(addr) {
+			n = 0
+		} else if n >= d.diversity.MaxPerPrefix {
+			continue
+		}
+		if bestN < 0 || n < bestN {
+			best, bestN = addr, n
+			if n == 0 {
+				break
+			}
+		}
+	}
+	return best
+}
+
 // RegisterBadAddr registers the given address as a bad address.
 func (d *DefaultDiscovery) RegisterBadAddr(addr string) {
 	var isSeed bool
@@ -155,11 +354,14 @@ func (d *DefaultDiscovery) RegisterBadAddr(addr string) {
 		}
 	}
 	if !isSeed {
-		d.unconnectedAddrs[addr]--
-		if d.unconnectedAddrs[addr] <= 0 {
+		if retries := d.unconnectedAddrs[addr] - 1; retries > 0 {
+			d.unconnectedAddrs[addr] = retries
+		} else {
 			d.badAddrs[addr] = true
-			delete(d.unconnectedAddrs, addr)
+			d.removeFromPool(addr)
 			delete(d.goodAddrs, addr)
+			delete(d.lastSeen, addr)
+			delete(d.prefixes, addr)
 		}
 	}
 	d.updateNetSize()
@@ -189,26 +391,64 @@ func (d *DefaultDiscovery) BadPeers() []string {
 }
 
 // GoodPeers returns all addresses of known good peers (that at least once
-// succeeded handshaking with us).
+// succeeded handshaking with us). Connected peers have the current time as
+// their LastSeen.
 func (d *DefaultDiscovery) GoodPeers() []AddressWithCapabilities {
+	var now = time.Now()
+
 	d.lock.RLock()
 	addrs := make([]AddressWithCapabilities, 0, len(d.goodAddrs))
 	for addr, cap := range d.goodAddrs {
+		seen := now
+		if !d.connectedAddrs[addr] {
+			seen = d.lastSeen[addr]
+		}
 		addrs = append(addrs, AddressWithCapabilities{
 			Address:      addr,
 			Capabilities: cap,
+			LastSeen:     seen,
 		})
 	}
 	d.lock.RUnlock()
 	return addrs
 }
 
+// LoadGoodPeers adds previously known good addresses (usually restored from
+// the DB on startup) to the good set and to the pool of addresses to connect
+// to. Addresses that are currently registered as bad are ignored.
+func (d *DefaultDiscovery) LoadGoodPeers(addrs []AddressWithCapabilities) {
+	d.lock.Lock()
+	for _, a := range addrs {
+		if d.badAddrs[a.Address] {
+			continue
+		}
+		if _, ok := d.goodAddrs[a.Address]; !ok {
+			caps := a.Capabilities
+			if isOnion(a.Address) {
+				caps = withOnionMarker(caps)
+			}
+			d.goodAddrs[a.Address] = caps
+			d.prefixes[a.Address] = d.netGroup(a.Address)
+		}
+		if !d.connectedAddrs[a.Address] && d.lastSeen[a.Address].Before(a.LastSeen) {
+			d.lastSeen[a.Address] = a.LastSeen
+		}
+		d.backfill(a.Address)
+	}
+	d.lock.Unlock()
+}
+
 // RegisterGoodAddr registers a known good connected address that has passed
 // handshake successfully.
 func (d *DefaultDiscovery) RegisterGoodAddr(s string, c capability.Capabilities) {
+	if isOnion(s) {
+		c = withOnionMarker(c)
+	}
 	d.lock.Lock()
 	d.goodAddrs[s] = c
+	d.prefixes[s] = d.netGroup(s)
 	delete(d.badAddrs, s)
+	delete(d.lastSeen, s)
 	d.lock.Unlock()
 }
 
@@ -216,7 +456,13 @@ func (d *DefaultDiscovery) RegisterGoodAddr(s string, c capability.Capabilities)
 // connected, but it is still considered a good one.
 func (d *DefaultDiscovery) UnregisterConnectedAddr(s string) {
 	d.lock.Lock()
-	delete(d.connectedAddrs, s)
+	if d.connectedAddrs[s] {
+		delete(d.connectedAddrs, s)
+		d.removePrefixConn(s)
+		if _, ok := d.goodAddrs[s]; ok {
+			d.lastSeen[s] = time.Now()
+		}
+	}
 	d.backfill(s)
 	d.lock.Unlock()
 }
@@ -224,8 +470,11 @@ func (d *DefaultDiscovery) UnregisterConnectedAddr(s string) {
 // RegisterConnectedAddr tells discoverer that the given address is now connected.
 func (d *DefaultDiscovery) RegisterConnectedAddr(addr string) {
 	d.lock.Lock()
-	delete(d.unconnectedAddrs, addr)
-	d.connectedAddrs[addr] = true
+	d.removeFromPool(addr)
+	if !d.connectedAddrs[addr] {
+		d.connectedAddrs[addr] = true
+		d.addPrefixConn(addr)
+	}
 	d.updateNetSize()
 	d.lock.Unlock()
 }
@@ -258,6 +507,7 @@ func (d *DefaultDiscovery) tryAddress(addr string) {
 	err := d.transport.Dial(addr, d.dialTimeout)
 	d.lock.Lock()
 	delete(d.attempted, addr)
+	d.removePrefixConn(addr)
 	d.lock.Unlock()
 	if err != nil {
 		d.RegisterBadAddr(addr)
@@ -265,3 +515,19 @@ func (d *DefaultDiscovery) tryAddress(addr string) {
 		d.RequestRemote(1)
 	}
 }
+
+// withOnionMarker returns the given capabilities with OnionService marker
+// added (if it's not there yet).
+func withOnionMarker(caps capability.Capabilities) capability.Capabilities {
+	for _, c := range caps {
+		if c.Type == capability.OnionService {
+			return caps
+		}
+	}
+	res := make(capability.Capabilities, len(caps), len(caps)+1)
+	copy(res, caps)
+	return append(res, capability.Capability{
+		Type: capability.OnionService,
+		Data: &capability.Onion{},
+	})
+}
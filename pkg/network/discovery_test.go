@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net"
 	"sort"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -57,7 +58,7 @@ func (ft *fakeTransp) Close() {
 func TestDefaultDiscoverer(t *testing.T) {
 	ts := &fakeTransp{}
 	ts.dialCh = make(chan string)
-	d := NewDefaultDiscovery(nil, time.Second/16, ts)
+	d := NewDefaultDiscovery(nil, time.Second/16, DiversityConfig{}, ts)
 
 	var set1 = []string{"1.1.1.1:10333", "2.2.2.2:10333"}
 	sort.Strings(set1)
@@ -183,7 +184,7 @@ func TestSeedDiscovery(t *testing.T) {
 	atomic.StoreInt32(&ts.retFalse, 1) // Fail all dial requests.
 	sort.Strings(seeds)
 
-	d := NewDefaultDiscovery(seeds, time.Second/10, ts)
+	d := NewDefaultDiscovery(seeds, time.Second/10, DiversityConfig{}, ts)
 
 	d.RequestRemote(len(seeds))
 	for i := 0; i < connRetries*2; i++ {
@@ -196,3 +197,94 @@ func TestSeedDiscovery(t *testing.T) {
 		}
 	}
 }
+
+func TestDiscoveryDiversity(t *testing.T) {
+	dialN := func(t *testing.T, ts *fakeTransp, n int) []string {
+		dialled := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			select {
+			case a := <-ts.dialCh:
+				dialled = append(dialled, a)
+			case <-time.After(time.Second):
+				t.Fatalf("timeout expecting for transport dial")
+			}
+		}
+		select {
+		case a := <-ts.dialCh:
+			t.Fatalf("unexpected dial to %s", a)
+		case <-time.After(100 * time.Millisecond):
+		}
+		sort.Strings(dialled)
+		return dialled
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		ts := &fakeTransp{dialCh: make(chan string)}
+		trusted, err := ParseTrustedNets([]string{"10.0.0.0/8"})
+		require.NoError(t, err)
+		d := NewDefaultDiscovery(nil, time.Second/16, DiversityConfig{MaxPerPrefix: 1, Trusted: trusted}, ts)
+		d.BackFill("1.1.1.1:10333", "1.1.2.2:10333", "2.2.2.2:10333", "10.1.1.1:10333", "10.1.2.2:10333")
+
+		d.RequestRemote(5)
+		dialled := dialN(t, ts, 4) // Only one of 1.1.0.0/16 addresses.
+		require.Equal(t, "1.1.0.0/16", netPrefix(dialled[0]))
+		require.Equal(t, []string{"10.1.1.1:10333", "10.1.2.2:10333", "2.2.2.2:10333"}, dialled[1:])
+		for _, a := range dialled {
+			d.RegisterConnectedAddr(a)
+		}
+		require.Equal(t, 1, d.PoolCount())
+
+		// The limit is still reached.
+		d.RequestRemote(1)
+		dialN(t, ts, 0)
+
+		// Disconnection frees a slot.
+		d.UnregisterConnectedAddr(dialled[0])
+		require.Equal(t, 2, d.PoolCount())
+		d.RequestRemote(1)
+		require.Equal(t, "1.1.0.0/16", netPrefix(dialN(t, ts, 1)[0]))
+	})
+	t.Run("prefer diverse", func(t *testing.T) {
+		ts := &fakeTransp{dialCh: make(chan string)}
+		d := NewDefaultDiscovery(nil, time.Second/16, DiversityConfig{MaxPerPrefix: 2}, ts)
+		d.BackFill("1.1.1.1:10333", "1.1.2.2:10333", "2.2.2.2:10333")
+
+		d.RequestRemote(2)
+		dialled := dialN(t, ts, 2)
+		require.NotEqual(t, netPrefix(dialled[0]), netPrefix(dialled[1]))
+	})
+	t.Run("ASN", func(t *testing.T) {
+		asn, err := ParseASNMap(strings.NewReader("1.1.0.0/16 AS1\n2.2.0.0/16 AS1\n"))
+		require.NoError(t, err)
+		ts := &fakeTransp{dialCh: make(chan string)}
+		d := NewDefaultDiscovery(nil, time.Second/16, DiversityConfig{MaxPerPrefix: 1, ASN: asn}, ts)
+		d.BackFill("1.1.1.1:10333", "2.2.2.2:10333", "3.3.3.3:10333")
+
+		d.RequestRemote(3)
+		dialled := dialN(t, ts, 2) // Only one of AS1 addresses.
+		require.Equal(t, "AS1", d.netGroup(dialled[0]))
+		require.Equal(t, "3.3.3.3:10333", dialled[1])
+		require.Equal(t, "3.3.0.0/16", d.netGroup(dialled[1]))
+	})
+}
+
+func TestNetPrefix(t *testing.T) {
+	require.Equal(t, "1.2.0.0/16", netPrefix("1.2.3.4:10333"))
+	require.Equal(t, "1.2.0.0/16", netPrefix("::ffff:1.2.3.4"))
+	require.Equal(t, "2001:db8::/32", netPrefix("[2001:db8:1:2::1]:10333"))
+	require.Equal(t, "seed1.neo.org", netPrefix("seed1.neo.org:10333"))
+}
+
+func TestParseTrustedNets(t *testing.T) {
+	nets, err := ParseTrustedNets([]string{"1.2.3.4", "10.0.0.0/8", "2001:db8::1"})
+	require.NoError(t, err)
+	require.Equal(t, 3, len(nets))
+	require.Equal(t, "1.2.3.4/32", nets[0].String())
+	require.Equal(t, "10.0.0.0/8", nets[1].String())
+	require.Equal(t, "2001:db8::1/128", nets[2].String())
+
+	_, err = ParseTrustedNets([]string{"1.2.3"})
+	require.Error(t, err)
+	_, err = ParseTrustedNets([]string{"1.2.3.4/33"})
+	require.Error(t, err)
+}
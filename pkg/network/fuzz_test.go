@@ -3,10 +3,19 @@
 package network
 
 import (
+	"encoding/binary"
 	"math/rand"
+	"net"
 	"testing"
+	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/capability"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,3 +32,116 @@ func FuzzMessageDecode(f *testing.F) {
 		require.NotPanics(t, func() { _ = m.Decode(r) })
 	})
 }
+
+func FuzzDecompressDeflate(f *testing.F) {
+	for i := 0; i < 10; i++ {
+		src := make([]byte, rand.Uint32()%(2*CompressionMinSize))
+		rand.Read(src[:len(src)/2])
+		seed, err := compressDeflate(src)
+		require.NoError(f, err)
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value []byte) {
+		require.NotPanics(t, func() {
+			d, err := decompressDeflate(value)
+			if err == nil {
+				require.Equal(t, int(binary.LittleEndian.Uint32(value)), len(d))
+			}
+		})
+	})
+}
+
+// fuzzPayloadSeeds returns valid payloads for every known command, commands
+// without payload or not supported by the node get an empty one.
+func fuzzPayloadSeeds(t testing.TB) map[CommandType][]byte {
+	var (
+		hashes  = []util.Uint256{{1, 2, 3}, {4, 5, 6}}
+		witness = transaction.Witness{InvocationScript: []byte{0x61}, VerificationScript: []byte{0x51}}
+		caps    = capability.Capabilities{
+			{Type: capability.TCPServer, Data: &capability.Server{Port: 10333}},
+			{Type: capability.FullNode, Data: &capability.Node{StartHeight: 123}},
+		}
+		addrs = payload.NewAddressList(1)
+		hdr   = &block.Header{Index: 1, Timestamp: 100500, Script: witness}
+		blk   = block.New(false)
+		tx    = transaction.New([]byte{0x51}, 1)
+		ext   = payload.NewExtensible()
+	)
+	addrs.Addrs[0] = payload.NewAddressAndTime(&net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 10333}, time.Unix(100500, 0), caps)
+	blk.Script = witness
+	tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+	tx.Scripts = []transaction.Witness{witness}
+	blk.Transactions = []*transaction.Transaction{tx}
+	blk.RebuildMerkleRoot()
+	ext.Category = "dBFT"
+	ext.ValidBlockEnd = 10
+	ext.Data = []byte{1, 2, 3}
+	ext.Witness = witness
+
+	ps := map[CommandType]payload.Payload{
+		CMDVersion:         payload.NewVersion(netmode.MainNet, 1, "/NEO-GO:0.100.0/", caps),
+		CMDAddr:            addrs,
+		CMDPing:            payload.NewPing(1, 2),
+		CMDPong:            payload.NewPing(3, 4),
+		CMDGetHeaders:      payload.NewGetBlockByIndex(1, 100),
+		CMDHeaders:         &payload.Headers{Hdrs: []*block.Header{hdr}},
+		CMDGetBlocks:       payload.NewGetBlocks(hashes[0], 100),
+		CMDInv:             payload.NewInventory(payload.TXType, hashes),
+		CMDGetData:         payload.NewInventory(payload.BlockType, hashes),
+		CMDGetBlockByIndex: payload.NewGetBlockByIndex(1, -1),
+		CMDNotFound:        payload.NewInventory(payload.ExtensibleType, hashes),
+		CMDTX:              tx,
+		CMDBlock:           blk,
+		CMDExtensible:      ext,
+		CMDGetMPTData:      payload.NewMPTInventory(hashes),
+		CMDMPTData:         &payload.MPTData{Nodes: [][]byte{{1, 2, 3}}},
+		CMDMerkleBlock:     &payload.MerkleBlock{Header: hdr, TxCount: 2, Hashes: hashes, Flags: []byte{1}},
+	}
+	res := make(map[CommandType][]byte)
+	for _, cmd := range []CommandType{CMDVersion, CMDVerack, CMDGetAddr, CMDAddr, CMDPing,
+		CMDPong, CMDGetHeaders, CMDHeaders, CMDGetBlocks, CMDMempool, CMDInv, CMDGetData,
+		CMDGetBlockByIndex, CMDNotFound, CMDTX, CMDBlock, CMDExtensible, CMDP2PNotaryRequest,
+		CMDGetMPTData, CMDMPTData, CMDReject, CMDFilterLoad, CMDFilterAdd, CMDFilterClear,
+		CMDMerkleBlock, CMDAlert} {
+		var b []byte
+		if p, ok := ps[cmd]; ok {
+			w := io.NewBufBinWriter()
+			p.EncodeBinary(w.BinWriter)
+			require.NoError(t, w.Err)
+			b = w.Bytes()
+		}
+		res[cmd] = b
+	}
+	return res
+}
+
+func TestFuzzPayloadSeeds(t *testing.T) {
+	for cmd, b := range fuzzPayloadSeeds(t) {
+		if len(b) == 0 {
+			continue
+		}
+		m := &Message{Command: cmd, compressedPayload: b}
+		require.NoError(t, m.decodePayload(), cmd.String())
+	}
+}
+
+// FuzzMessagePayloadDecode fuzzes payload decoders of every command, seeds
+// are valid payloads.
+func FuzzMessagePayloadDecode(f *testing.F) {
+	for cmd, b := range fuzzPayloadSeeds(f) {
+		f.Add(byte(cmd), b)
+	}
+
+	f.Fuzz(func(t *testing.T, cmd byte, value []byte) {
+		w := io.NewBufBinWriter()
+		w.WriteB(byte(None))
+		w.WriteB(cmd)
+		w.WriteVarBytes(value)
+		require.NoError(t, w.Err)
+
+		m := new(Message)
+		r := io.NewBinReaderFromBuf(w.Bytes())
+		require.NotPanics(t, func() { _ = m.Decode(r) })
+	})
+}
@@ -27,7 +27,9 @@ type testDiscovery struct {
 	backfill     []string
 }
 
-func newTestDiscovery([]string, time.Duration, Transporter) Discoverer { return new(testDiscovery) }
+func newTestDiscovery([]string, time.Duration, DiversityConfig, Transporter) Discoverer {
+	return new(testDiscovery)
+}
 
 func (d *testDiscovery) BackFill(addrs ...string) {
 	d.Lock()
@@ -72,7 +74,8 @@ func (d *testDiscovery) BadPeers() []string {
 	defer d.Unlock()
 	return d.bad
 }
-func (d *testDiscovery) GoodPeers() []AddressWithCapabilities { return []AddressWithCapabilities{} }
+func (d *testDiscovery) GoodPeers() []AddressWithCapabilities    { return []AddressWithCapabilities{} }
+func (d *testDiscovery) LoadGoodPeers([]AddressWithCapabilities) {}
 
 var defaultMessageHandler = func(t *testing.T, msg *Message) {}
 
@@ -196,6 +199,7 @@ func (p *localPeer) IsFullNode() bool {
 func (p *localPeer) AddGetAddrSent() {
 	p.getAddrSent++
 }
+func (p *localPeer) Traffic() PeerTraffic { return PeerTraffic{} }
 func (p *localPeer) CanProcessAddr() bool {
 	p.getAddrSent--
 	return p.getAddrSent >= 0
@@ -0,0 +1,183 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"go.uber.org/zap"
+)
+
+const (
+	// memPoolStoreVersion is the version of the stored memory pool format.
+	memPoolStoreVersion = 1
+	// defaultMaxStoredTransactions is the default number of transactions
+	// (and notary requests) stored.
+	defaultMaxStoredTransactions = 10000
+	// maxStoredMemPoolItems is the sanity limit for the number of stored
+	// transactions (and notary requests) on decoding.
+	maxStoredMemPoolItems = 1 << 20
+)
+
+// MemPoolStore is an optional Ledger extension allowing to persist memory
+// pool contents between node restarts.
+type MemPoolStore interface {
+	GetMemPoolData() ([]byte, error)
+	PutMemPoolData([]byte)
+}
+
+// encodeMemPool serializes the given transactions and notary requests. The
+// format is a version byte followed by an array of transactions and an array
+// of notary requests, every element is wrapped into a byte array.
+func encodeMemPool(txs []*transaction.Transaction, reqs []*payload.P2PNotaryRequest) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	w.WriteB(memPoolStoreVersion)
+	w.WriteVarUint(uint64(len(txs)))
+	for _, tx := range txs {
+		w.WriteVarBytes(tx.Bytes())
+	}
+	w.WriteVarUint(uint64(len(reqs)))
+	for _, r := range reqs {
+		b, err := r.Bytes()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode notary request %s: %w", r.FallbackTransaction.Hash().StringLE(), err)
+		}
+		w.WriteVarBytes(b)
+	}
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+
+// decodeMemPool deserializes transactions and notary requests stored by
+// encodeMemPool.
+func decodeMemPool(data []byte) ([]*transaction.Transaction, []*payload.P2PNotaryRequest, error) {
+	r := io.NewBinReaderFromBuf(data)
+	ver := r.ReadB()
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	if ver != memPoolStoreVersion {
+		return nil, nil, fmt.Errorf("unsupported memory pool version %d", ver)
+	}
+	n := r.ReadVarUint()
+	if n > maxStoredMemPoolItems {
+		return nil, nil, fmt.Errorf("too many transactions: %d", n)
+	}
+	txs := make([]*transaction.Transaction, 0, n)
+	for i := uint64(0); i < n; i++ {
+		b := r.ReadVarBytes(transaction.MaxTransactionSize)
+		if r.Err != nil {
+			return nil, nil, r.Err
+		}
+		tx, err := transaction.NewTransactionFromBytes(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid transaction #%d: %w", i, err)
+		}
+		txs = append(txs, tx)
+	}
+	n = r.ReadVarUint()
+	if n > maxStoredMemPoolItems {
+		return nil, nil, fmt.Errorf("too many notary requests: %d", n)
+	}
+	reqs := make([]*payload.P2PNotaryRequest, 0, n)
+	for i := uint64(0); i < n; i++ {
+		b := r.ReadVarBytes(payload.MaxSize)
+		if r.Err != nil {
+			return nil, nil, r.Err
+		}
+		req, err := payload.NewP2PNotaryRequestFromBytes(b)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid notary request #%d: %w", i, err)
+		}
+		reqs = append(reqs, req)
+	}
+	if r.Err != nil {
+		return nil, nil, r.Err
+	}
+	if r.Len() != 0 {
+		return nil, nil, errors.New("unexpected data after memory pool contents")
+	}
+	return txs, reqs, nil
+}
+
+// maxStoredTransactions returns the configured number of stored transactions.
+func (s *Server) maxStoredTransactions() int {
+	if s.MemPoolPersistence.MaxTransactions <= 0 {
+		return defaultMaxStoredTransactions
+	}
+	return s.MemPoolPersistence.MaxTransactions
+}
+
+// loadMemPool restores stored memory pool contents, every transaction (and
+// notary request) is verified against the current chain state and the ones
+// that are no longer valid are dropped. Failures are not fatal, the node just
+// starts with an empty pool.
+func (s *Server) loadMemPool() {
+	data, err := s.memPoolStore.GetMemPoolData()
+	if err != nil {
+		s.log.Warn("failed to load stored memory pool", zap.Error(err))
+		return
+	}
+	if len(data) == 0 {
+		return
+	}
+	// Stored contents are only valid once.
+	s.memPoolStore.PutMemPoolData([]byte{})
+	txs, reqs, err := decodeMemPool(data)
+	if err != nil {
+		s.log.Warn("ignoring stored memory pool", zap.Error(err))
+		return
+	}
+	var txDropped, reqDropped int
+	for _, tx := range txs {
+		if err := s.verifyAndPoolTX(tx); err != nil {
+			s.log.Debug("stored transaction dropped", zap.String("hash", tx.Hash().StringLE()), zap.Error(err))
+			txDropped++
+		}
+	}
+	for _, r := range reqs {
+		if s.notaryRequestPool == nil {
+			reqDropped = len(reqs)
+			break
+		}
+		if err := s.verifyAndPoolNotaryRequest(r); err != nil {
+			s.log.Debug("stored notary request dropped", zap.String("hash", r.FallbackTransaction.Hash().StringLE()), zap.Error(err))
+			reqDropped++
+		}
+	}
+	s.log.Info("loaded stored memory pool",
+		zap.Int("transactions", len(txs)-txDropped),
+		zap.Int("dropped transactions", txDropped),
+		zap.Int("notary requests", len(reqs)-reqDropped),
+		zap.Int("dropped notary requests", reqDropped))
+}
+
+// saveMemPool stores the most prioritized pooled transactions and notary
+// requests.
+func (s *Server) saveMemPool() {
+	var (
+		max  = s.maxStoredTransactions()
+		txs  = s.mempool.GetVerifiedTransactions()
+		reqs []*payload.P2PNotaryRequest
+	)
+	if len(txs) > max {
+		txs = txs[:max]
+	}
+	if s.notaryRequestPool != nil {
+		s.notaryRequestPool.IterateVerifiedTransactions(func(_ *transaction.Transaction, data interface{}) bool {
+			reqs = append(reqs, data.(*payload.P2PNotaryRequest))
+			return len(reqs) < max
+		})
+	}
+	data, err := encodeMemPool(txs, reqs)
+	if err != nil {
+		s.log.Warn("failed to save memory pool", zap.Error(err))
+		return
+	}
+	s.memPoolStore.PutMemPoolData(data)
+	s.log.Info("memory pool saved", zap.Int("transactions", len(txs)), zap.Int("notary requests", len(reqs)))
+}
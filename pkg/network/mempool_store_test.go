@@ -0,0 +1,181 @@
+package network
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/fakechain"
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+// memPoolStoreChain is a FakeChain implementing MemPoolStore.
+type memPoolStoreChain struct {
+	*fakechain.FakeChain
+	data     []byte
+	err      error
+	requests []util.Uint256
+}
+
+func (c *memPoolStoreChain) GetMemPoolData() ([]byte, error) { return c.data, c.err }
+func (c *memPoolStoreChain) PutMemPoolData(data []byte)      { c.data = data }
+
+func (c *memPoolStoreChain) PoolTxWithData(t *transaction.Transaction, data interface{}, mp *mempool.Pool, feer mempool.Feer, verificationFunction func(t *transaction.Transaction, data interface{}) error) error {
+	c.requests = append(c.requests, t.Hash())
+	return nil
+}
+
+func newMemPoolStoreChain() *memPoolStoreChain {
+	c := &memPoolStoreChain{FakeChain: fakechain.NewFakeChain()}
+	c.UtilityTokenBalance = big.NewInt(1_0000_0000)
+	return c
+}
+
+// newTestNotaryRequest returns a notary request passing payload validity
+// checks.
+func newTestNotaryRequest() *payload.P2PNotaryRequest {
+	mainTx := transaction.New(random.Bytes(100), 123)
+	mainTx.ValidUntilBlock = 123
+	mainTx.Signers = []transaction.Signer{{Account: random.Uint160()}}
+	mainTx.Attributes = []transaction.Attribute{{Type: transaction.NotaryAssistedT, Value: &transaction.NotaryAssisted{NKeys: 1}}}
+	mainTx.Scripts = []transaction.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{}}}
+	fallbackTx := transaction.New(random.Bytes(100), 123)
+	fallbackTx.ValidUntilBlock = 123
+	fallbackTx.Signers = []transaction.Signer{{Account: random.Uint160()}, {Account: random.Uint160()}}
+	fallbackTx.Attributes = []transaction.Attribute{
+		{Type: transaction.NotValidBeforeT, Value: &transaction.NotValidBefore{Height: 100}},
+		{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: mainTx.Hash()}},
+		{Type: transaction.NotaryAssistedT, Value: &transaction.NotaryAssisted{NKeys: 0}},
+	}
+	fallbackTx.Scripts = []transaction.Witness{
+		{InvocationScript: append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, make([]byte, keys.SignatureLen)...), VerificationScript: []byte{}},
+		{InvocationScript: []byte{}, VerificationScript: []byte{}},
+	}
+	return &payload.P2PNotaryRequest{
+		MainTransaction:     mainTx,
+		FallbackTransaction: fallbackTx,
+		Witness:             transaction.Witness{InvocationScript: []byte{}, VerificationScript: []byte{}},
+	}
+}
+
+func TestMemPoolSerialization(t *testing.T) {
+	txs := []*transaction.Transaction{newDummyTx(), newDummyTx()}
+	reqs := []*payload.P2PNotaryRequest{newTestNotaryRequest()}
+	data, err := encodeMemPool(txs, reqs)
+	require.NoError(t, err)
+	actualTxs, actualReqs, err := decodeMemPool(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(actualTxs))
+	for i := range txs {
+		require.Equal(t, txs[i].Hash(), actualTxs[i].Hash())
+	}
+	require.Equal(t, 1, len(actualReqs))
+	require.Equal(t, reqs[0].FallbackTransaction.Hash(), actualReqs[0].FallbackTransaction.Hash())
+	require.Equal(t, reqs[0].MainTransaction.Hash(), actualReqs[0].MainTransaction.Hash())
+
+	t.Run("empty", func(t *testing.T) {
+		data, err := encodeMemPool(nil, nil)
+		require.NoError(t, err)
+		txs, reqs, err := decodeMemPool(data)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(txs))
+		require.Equal(t, 0, len(reqs))
+	})
+	t.Run("unknown version", func(t *testing.T) {
+		bad := append([]byte{memPoolStoreVersion + 1}, data[1:]...)
+		_, _, err := decodeMemPool(bad)
+		require.Error(t, err)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		_, _, err := decodeMemPool(data[:len(data)-1])
+		require.Error(t, err)
+		_, _, err = decodeMemPool(nil)
+		require.Error(t, err)
+	})
+	t.Run("trailing data", func(t *testing.T) {
+		_, _, err := decodeMemPool(append(data, 0))
+		require.Error(t, err)
+	})
+	t.Run("invalid transaction", func(t *testing.T) {
+		_, _, err := decodeMemPool([]byte{memPoolStoreVersion, 1, 2, 0xff, 0xff, 0})
+		require.Error(t, err)
+	})
+}
+
+func TestServerMemPoolPersistence(t *testing.T) {
+	cfg := ServerConfig{MemPoolPersistence: config.MemPoolPersistence{Enabled: true, MaxTransactions: 2}}
+
+	t.Run("unsupported ledger", func(t *testing.T) {
+		s := newPeerStoreTestServer(t, cfg, fakechain.NewFakeChain())
+		require.Nil(t, s.memPoolStore)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		s := newPeerStoreTestServer(t, ServerConfig{}, newMemPoolStoreChain())
+		require.Nil(t, s.memPoolStore)
+	})
+
+	chain := newMemPoolStoreChain()
+	s := newPeerStoreTestServer(t, cfg, chain)
+	require.NotNil(t, s.memPoolStore)
+
+	// Nothing is stored yet.
+	s.loadMemPool()
+	require.Equal(t, 0, s.mempool.Count())
+
+	txs := make([]*transaction.Transaction, 3)
+	for i := range txs {
+		txs[i] = transaction.New(random.Bytes(100), 123)
+		txs[i].NetworkFee = int64(3-i) * 1000 // The last one is the least prioritized.
+		txs[i].Signers = []transaction.Signer{{Account: random.Uint160()}}
+		txs[i].Scripts = []transaction.Witness{{InvocationScript: []byte{}, VerificationScript: []byte{}}}
+		require.NoError(t, s.mempool.Add(txs[i], chain))
+	}
+	req := newTestNotaryRequest()
+	require.NoError(t, s.notaryRequestPool.Add(req.FallbackTransaction, chain, req))
+	s.saveMemPool()
+	require.NotNil(t, chain.data)
+
+	// Restart with an empty pool, one of the stored transactions is no
+	// longer valid.
+	chain2 := newMemPoolStoreChain()
+	chain2.data = chain.data
+	chain2.PoolTxF = func(tx *transaction.Transaction) error {
+		if tx.Hash().Equals(txs[0].Hash()) {
+			return errors.New("invalid")
+		}
+		return chain2.Pool.Add(tx, chain2)
+	}
+	s2 := newPeerStoreTestServer(t, cfg, chain2)
+	s2.loadMemPool()
+	require.Equal(t, 1, s2.mempool.Count())
+	require.True(t, s2.mempool.ContainsKey(txs[1].Hash()))
+	require.False(t, s2.mempool.ContainsKey(txs[0].Hash())) // Invalid.
+	require.False(t, s2.mempool.ContainsKey(txs[2].Hash())) // Not stored.
+	require.Equal(t, []util.Uint256{req.FallbackTransaction.Hash()}, chain2.requests)
+
+	// Stored contents are loaded only once.
+	require.Equal(t, 0, len(chain2.data))
+	s2.loadMemPool()
+	require.Equal(t, 1, len(chain2.requests))
+
+	t.Run("bad data", func(t *testing.T) {
+		chain := newMemPoolStoreChain()
+		chain.data = []byte{0xff, 1, 2}
+		chain.PoolTxF = func(tx *transaction.Transaction) error { return chain.Pool.Add(tx, chain) }
+		s := newPeerStoreTestServer(t, cfg, chain)
+		s.loadMemPool()
+		require.Equal(t, 0, s.mempool.Count())
+
+		chain.data, chain.err = nil, errors.New("some error")
+		s.loadMemPool()
+		require.Equal(t, 0, s.mempool.Count())
+	})
+}
@@ -18,7 +18,7 @@ const CompressionMinSize = 1024
 // Message is a complete message sent between nodes.
 type Message struct {
 	// Flags that represents whether a message is compressed.
-	// 0 for None, 1 for Compressed.
+	// 0 for None, 1 for Compressed (lz4), 2 for Deflated (DEFLATE).
 	Flags MessageFlag
 	// Command is a byte command code.
 	Command CommandType
@@ -32,6 +32,10 @@ type Message struct {
 	// StateRootInHeader specifies if the state root is included in the block header.
 	// This is needed for correct decoding.
 	StateRootInHeader bool
+
+	// deflateAllowed specifies whether Deflated payloads can be decoded, it's
+	// only set for peers that have negotiated DEFLATE compression.
+	deflateAllowed bool
 }
 
 // MessageFlag represents compression level of a message payload.
@@ -39,8 +43,14 @@ type MessageFlag byte
 
 // Possible message flags.
 const (
+	// Compressed is set for lz4-compressed payloads.
 	Compressed MessageFlag = 1 << iota
-	None       MessageFlag = 0
+	// Deflated (0x02) is set for DEFLATE-compressed payloads. It's a NeoGo
+	// extension that is not a part of the Neo protocol (other nodes treat
+	// it as an invalid flag), so it's only used for peers advertising
+	// PayloadCompression capability.
+	Deflated
+	None MessageFlag = 0
 )
 
 // CommandType represents the type of a message command.
@@ -124,13 +134,26 @@ func (m *Message) Decode(br *io.BinReader) error {
 func (m *Message) decodePayload() error {
 	buf := m.compressedPayload
 	// try decompression
-	if m.Flags&Compressed != 0 {
+	switch m.Flags & (Compressed | Deflated) {
+	case Compressed:
 		d, err := decompress(m.compressedPayload)
 		if err != nil {
 			return err
 		}
 		buf = d
+	case Deflated:
+		if !m.deflateAllowed {
+			return errors.New("unexpected deflated payload")
+		}
+		d, err := decompressDeflate(m.compressedPayload)
+		if err != nil {
+			return err
+		}
+		buf = d
+	case Compressed | Deflated:
+		return errors.New("conflicting compression flags")
 	}
+	updatePayloadSizeMetrics(false, len(m.compressedPayload), len(buf))
 
 	var p payload.Payload
 	switch m.Command {
@@ -183,9 +206,38 @@ func (m *Message) decodePayload() error {
 	return r.Err
 }
 
+// wireSize returns the size of the received message as it was transferred.
+func (m *Message) wireSize() int {
+	return 2 + io.GetVarSize(len(m.compressedPayload)) + len(m.compressedPayload)
+}
+
+// splitPacket calls f for every message in the serialized packet (that can
+// contain several messages) with the message command and size.
+func splitPacket(b []byte, f func(cmd CommandType, size int)) {
+	for len(b) > 2 {
+		r := io.NewBinReaderFromBuf(b[2:])
+		l := r.ReadVarUint()
+		if r.Err != nil {
+			return
+		}
+		size := 2 + io.GetVarSize(int(l)) + int(l)
+		if l > uint64(len(b)) || size > len(b) {
+			size = len(b)
+		}
+		f(CommandType(b[1]), size)
+		b = b[size:]
+	}
+}
+
 // Encode encodes a Message to any given BinWriter.
 func (m *Message) Encode(br *io.BinWriter) error {
-	if err := m.tryCompressPayload(); err != nil {
+	return m.encode(br, false)
+}
+
+// encode encodes a Message to the given BinWriter, the payload is compressed
+// with DEFLATE (instead of lz4) if deflate is true.
+func (m *Message) encode(br *io.BinWriter, deflate bool) error {
+	if err := m.tryCompressPayload(deflate); err != nil {
 		return err
 	}
 	growSize := 2 + 1 // header + empty payload
@@ -205,8 +257,14 @@ func (m *Message) Encode(br *io.BinWriter) error {
 
 // Bytes serializes a Message into the new allocated buffer and returns it.
 func (m *Message) Bytes() ([]byte, error) {
+	return m.bytes(false)
+}
+
+// bytes serializes a Message into the new allocated buffer using DEFLATE
+// payload compression if deflate is true.
+func (m *Message) bytes(deflate bool) ([]byte, error) {
 	w := io.NewBufBinWriter()
-	if err := m.Encode(w.BinWriter); err != nil {
+	if err := m.encode(w.BinWriter, deflate); err != nil {
 		return nil, err
 	}
 	return w.Bytes(), nil
@@ -214,7 +272,10 @@ func (m *Message) Bytes() ([]byte, error) {
 
 // tryCompressPayload sets the message's compressed payload to a serialized
 // payload and compresses it in case its size exceeds CompressionMinSize.
-func (m *Message) tryCompressPayload() error {
+// DEFLATE is used if deflate is true, it's applied to any non-empty payload
+// (if the result is smaller than the original) while lz4 is only used for
+// payloads that are compressed by the protocol.
+func (m *Message) tryCompressPayload(deflate bool) error {
 	if m.Payload == nil {
 		return nil
 	}
@@ -224,7 +285,19 @@ func (m *Message) tryCompressPayload() error {
 		return buf.Err
 	}
 	compressedPayload := buf.Bytes()
-	if m.Flags&Compressed == 0 {
+	rawSize := len(compressedPayload)
+	_, isNull := m.Payload.(payload.NullPayload)
+	if deflate && m.Flags&(Compressed|Deflated) == 0 && !isNull && rawSize > CompressionMinSize {
+		c, err := compressDeflate(compressedPayload)
+		if err != nil {
+			return err
+		}
+		// Incompressible data is sent as is.
+		if len(c) < rawSize {
+			compressedPayload = c
+			m.Flags |= Deflated
+		}
+	} else if m.Flags&(Compressed|Deflated) == 0 {
 		switch m.Payload.(type) {
 		case *payload.Headers, *payload.MerkleBlock, payload.NullPayload,
 			*payload.Inventory, *payload.MPTInventory:
@@ -244,5 +317,6 @@ func (m *Message) tryCompressPayload() error {
 		}
 	}
 	m.compressedPayload = compressedPayload
+	updatePayloadSizeMetrics(true, len(compressedPayload), rawSize)
 	return nil
 }
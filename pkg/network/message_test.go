@@ -1,6 +1,7 @@
 package network
 
 import (
+	"encoding/binary"
 	"errors"
 	"math/rand"
 	"testing"
@@ -15,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/network/capability"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,6 +61,144 @@ func TestEncodeDecodeVersion(t *testing.T) {
 	require.NotEqual(t, len(expected.compressedPayload), len(uncompressed))
 }
 
+func TestEncodeDecodeDeflate(t *testing.T) {
+	encodeDecode := func(t *testing.T, expected *Message) *Message {
+		data, err := expected.bytes(true)
+		require.NoError(t, err)
+		actual := &Message{deflateAllowed: true}
+		require.NoError(t, testserdes.Decode(data, actual))
+		require.Equal(t, expected.Payload, actual.Payload)
+		require.Equal(t, expected.Flags, actual.Flags)
+		return actual
+	}
+
+	// Small payloads are not compressed.
+	m := NewMessage(CMDPing, payload.NewPing(123, 456))
+	encodeDecode(t, m)
+	require.Equal(t, None, m.Flags)
+
+	// Large ones are deflated instead of being compressed with lz4.
+	largeArray := make([]byte, CompressionMinSize)
+	for i := range largeArray {
+		largeArray[i] = byte(i)
+	}
+	m = NewMessage(CMDVersion, &payload.Version{
+		Magic:     1,
+		UserAgent: largeArray,
+		Capabilities: capability.Capabilities{{
+			Type: capability.FullNode,
+			Data: &capability.Node{StartHeight: 123},
+		}},
+	})
+	encodeDecode(t, m)
+	require.Equal(t, Deflated, m.Flags)
+	uncompressed, err := testserdes.EncodeBinary(m.Payload)
+	require.NoError(t, err)
+	require.Less(t, len(m.compressedPayload), len(uncompressed))
+
+	// Headers are not compressed with lz4, but can be deflated.
+	headers := &payload.Headers{Hdrs: make([]*block.Header, 16)}
+	for i := range headers.Hdrs {
+		h := &block.Header{
+			Index: uint32(i + 1),
+			Script: transaction.Witness{
+				InvocationScript:   make([]byte, 66),
+				VerificationScript: make([]byte, 40),
+			},
+		}
+		h.Hash()
+		headers.Hdrs[i] = h
+	}
+	m = NewMessage(CMDHeaders, headers)
+	encodeDecode(t, m)
+	require.Equal(t, Deflated, m.Flags)
+
+	// Incompressible payloads are sent as is.
+	random := make([]byte, 2*CompressionMinSize)
+	rand.Read(random)
+	m = NewMessage(CMDMPTData, &payload.MPTData{Nodes: [][]byte{random}})
+	encodeDecode(t, m)
+	require.Equal(t, None, m.Flags)
+	uncompressed, err = testserdes.EncodeBinary(m.Payload)
+	require.NoError(t, err)
+	require.Equal(t, uncompressed, m.compressedPayload)
+
+	// Regular encoding is not affected.
+	m = NewMessage(CMDHeaders, headers)
+	testserdes.EncodeDecode(t, m, &Message{})
+	require.Equal(t, None, m.Flags)
+}
+
+func TestDecodeCorruptedDeflate(t *testing.T) {
+	largeArray := make([]byte, 2*CompressionMinSize)
+	m := NewMessage(CMDMPTData, &payload.MPTData{Nodes: [][]byte{largeArray}})
+	data, err := m.bytes(true)
+	require.NoError(t, err)
+	require.Equal(t, Deflated, m.Flags)
+
+	// Message header is flags, command and varint payload length (3 bytes).
+	const hdrLen = 2 + 1
+	require.Less(t, len(m.compressedPayload), 0xfd)
+	payloadStart := hdrLen + 4 // Uncompressed length goes first.
+
+	check := func(t *testing.T, data []byte) {
+		require.Error(t, testserdes.Decode(data, &Message{deflateAllowed: true}))
+	}
+	t.Run("not negotiated", func(t *testing.T) {
+		require.Error(t, testserdes.Decode(data, &Message{}))
+	})
+	t.Run("conflicting flags", func(t *testing.T) {
+		bad := slice.Copy(data)
+		bad[0] |= byte(Compressed)
+		check(t, bad)
+	})
+	t.Run("lz4 flag", func(t *testing.T) {
+		bad := slice.Copy(data)
+		bad[0] = byte(Compressed)
+		check(t, bad)
+	})
+	t.Run("short length", func(t *testing.T) {
+		bad := slice.Copy(data)
+		bad[hdrLen]--
+		check(t, bad)
+	})
+	t.Run("long length", func(t *testing.T) {
+		bad := slice.Copy(data)
+		bad[hdrLen]++
+		check(t, bad)
+	})
+	t.Run("length exceeds MaxSize", func(t *testing.T) {
+		bad := slice.Copy(data)
+		binary.LittleEndian.PutUint32(bad[hdrLen:], payload.MaxSize+1)
+		check(t, bad)
+	})
+	t.Run("no length", func(t *testing.T) {
+		check(t, []byte{byte(Deflated), byte(CMDMPTData), 3, 1, 2, 3})
+	})
+	t.Run("truncated stream", func(t *testing.T) {
+		bad := slice.Copy(data[:len(data)-2])
+		bad[2] -= 2
+		check(t, bad)
+	})
+	t.Run("garbage stream", func(t *testing.T) {
+		bad := slice.Copy(data)
+		for i := payloadStart; i < len(bad); i++ {
+			bad[i] = 0xff
+		}
+		check(t, bad)
+	})
+	t.Run("trailing data", func(t *testing.T) {
+		bad := append(slice.Copy(data), 0)
+		bad[2]++
+		check(t, bad)
+	})
+	t.Run("good", func(t *testing.T) {
+		actual := &Message{deflateAllowed: true}
+		require.NoError(t, testserdes.Decode(data, actual))
+		require.Equal(t, m.Payload, actual.Payload)
+	})
+}
+
 func BenchmarkMessageBytes(b *testing.B) {
 	// shouldn't try to compress headers payload
 	ep := &payload.Extensible{
@@ -373,3 +513,45 @@ func testEncodeDecodeFail(t *testing.T, cmd CommandType, p payload.Payload) *Mes
 	require.Error(t, testserdes.Decode(data, actual))
 	return actual
 }
+
+func TestSplitPacket(t *testing.T) {
+	var (
+		msgs = []*Message{
+			NewMessage(CMDPing, payload.NewPing(1, 2)),
+			NewMessage(CMDGetAddr, payload.NewNullPayload()),
+			NewMessage(CMDBlock, newDummyBlock(1, 3)),
+		}
+		pkt   []byte
+		sizes []int
+	)
+	for _, m := range msgs {
+		b, err := m.Bytes()
+		require.NoError(t, err)
+		pkt = append(pkt, b...)
+		sizes = append(sizes, len(b))
+
+		// Received messages are accounted for with the same size.
+		actual := &Message{}
+		require.NoError(t, testserdes.Decode(b, actual))
+		require.Equal(t, len(b), actual.wireSize())
+	}
+
+	var (
+		cmds []CommandType
+		got  []int
+	)
+	splitPacket(pkt, func(cmd CommandType, size int) {
+		cmds = append(cmds, cmd)
+		got = append(got, size)
+	})
+	require.Equal(t, []CommandType{CMDPing, CMDGetAddr, CMDBlock}, cmds)
+	require.Equal(t, sizes, got)
+
+	t.Run("truncated", func(t *testing.T) {
+		got = got[:0]
+		splitPacket(pkt[:len(pkt)-1], func(_ CommandType, size int) {
+			got = append(got, size)
+		})
+		require.Equal(t, []int{sizes[0], sizes[1], sizes[2] - 1}, got)
+	})
+}
@@ -0,0 +1,225 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// defaultNATLease is the default port mapping lifetime.
+const defaultNATLease = time.Hour
+
+type (
+	// natMapper is a port mapping protocol client.
+	natMapper interface {
+		fmt.Stringer
+		// ExternalIP returns the external address of the gateway.
+		ExternalIP() (net.IP, error)
+		// AddMapping maps (or refreshes the mapping of) the external TCP
+		// port to the internal one returning the external port actually
+		// mapped.
+		AddMapping(internal, external uint16, lifetime time.Duration) (uint16, error)
+		// DeleteMapping removes the mapping.
+		DeleteMapping(internal, external uint16) error
+	}
+
+	// NATStatus describes the state of the automatic port mapping.
+	NATStatus struct {
+		// Method is the protocol used for mapping ("upnp" or "natpmp"),
+		// it's empty if no gateway is found.
+		Method string
+		// ExternalIP is the external address of the gateway.
+		ExternalIP net.IP
+		// ExternalPort is the external port mapped to the node's one.
+		ExternalPort uint16
+		// Mapped is true if the port is currently mapped.
+		Mapped bool
+	}
+
+	// natService maps the node's P2P port on the gateway, refreshes the
+	// mapping and removes it on shutdown.
+	natService struct {
+		log   *zap.Logger
+		lease time.Duration
+		// discover returns a mapper for the gateway found.
+		discover func() (natMapper, error)
+
+		lock   sync.RWMutex
+		status NATStatus
+
+		// runLock protects started and stopped, so that the service can't
+		// be started after shutdown and shutdown always waits for it.
+		runLock sync.Mutex
+		started bool
+		stopped bool
+		quit    chan struct{}
+		done    chan struct{}
+	}
+)
+
+func newNATService(cfg config.NAT, log *zap.Logger) *natService {
+	lease := time.Duration(cfg.LeaseDuration) * time.Second
+	if lease <= 0 {
+		lease = defaultNATLease
+	}
+	return &natService{
+		log:   log,
+		lease: lease,
+		discover: func() (natMapper, error) {
+			return discoverNATMapper(cfg.Gateway)
+		},
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+// discoverNATMapper tries UPnP IGD first and then NAT-PMP.
+func discoverNATMapper(gateway string) (natMapper, error) {
+	igd, upnpErr := discoverUPnP()
+	if upnpErr == nil {
+		return igd, nil
+	}
+	pmp, err := newNATPMP(gateway)
+	if err == nil {
+		if _, err = pmp.ExternalIP(); err == nil {
+			return pmp, nil
+		}
+	}
+	return nil, fmt.Errorf("UPnP: %v, NAT-PMP: %w", upnpErr, err)
+}
+
+// Status returns the current port mapping state.
+func (n *natService) Status() NATStatus {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.status
+}
+
+// externalPort returns the mapped external port if there is any.
+func (n *natService) externalPort() (uint16, bool) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+	return n.status.ExternalPort, n.status.Mapped
+}
+
+func (n *natService) setStatus(st NATStatus) {
+	n.lock.Lock()
+	n.status = st
+	n.lock.Unlock()
+	updateNATMappedMetric(st.Mapped)
+}
+
+// start starts the service in a separate goroutine, port returns the port to
+// be mapped, external is the preferred external port (the same as internal
+// one if zero). It does nothing if the service is already stopped.
+func (n *natService) start(port func() (uint16, error), external uint16) {
+	n.runLock.Lock()
+	defer n.runLock.Unlock()
+	if n.started || n.stopped {
+		return
+	}
+	n.started = true
+	go n.run(port, external)
+}
+
+// run maps the given port and keeps the mapping until the service is
+// stopped. Failures are not fatal for the node, they're just logged.
+func (n *natService) run(port func() (uint16, error), external uint16) {
+	defer close(n.done)
+
+	updateNATMappedMetric(false)
+	m, err := n.discover()
+	if err != nil {
+		n.log.Warn("NAT port mapping is not available", zap.Error(err))
+		return
+	}
+	internal, err := port()
+	if err != nil {
+		n.log.Warn("NAT port mapping failed", zap.Error(err))
+		return
+	}
+	if external == 0 {
+		external = internal
+	}
+	// Discovery takes some time, the service can be stopped meanwhile.
+	select {
+	case <-n.quit:
+		return
+	default:
+	}
+	var (
+		st    = NATStatus{Method: m.String(), ExternalPort: external}
+		added bool
+	)
+	st, added = n.mapPort(m, internal, st)
+
+	t := time.NewTicker(n.lease / 2)
+	defer t.Stop()
+	for {
+		select {
+		case <-n.quit:
+			// The mapping is removed even if the last refresh has
+			// failed, the gateway can still keep it. But it's not
+			// touched if it has never been added, the port can be
+			// mapped to some other host then.
+			if added {
+				if err := m.DeleteMapping(internal, st.ExternalPort); err != nil {
+					n.log.Warn("failed to remove NAT port mapping", zap.Error(err))
+				}
+			}
+			st.Mapped = false
+			n.setStatus(st)
+			return
+		case <-t.C:
+			var ok bool
+			st, ok = n.mapPort(m, internal, st)
+			added = added || ok
+		}
+	}
+}
+
+// mapPort creates or refreshes the mapping and updates the status. It also
+// returns true if the gateway has accepted the mapping (even if the status
+// can't be updated).
+func (n *natService) mapPort(m natMapper, internal uint16, st NATStatus) (NATStatus, bool) {
+	ext, err := m.AddMapping(internal, st.ExternalPort, n.lease)
+	added := err == nil
+	if err == nil {
+		var ip net.IP
+		ip, err = m.ExternalIP()
+		if err == nil {
+			if !st.Mapped || ext != st.ExternalPort || !ip.Equal(st.ExternalIP) {
+				n.log.Info("NAT port mapping established",
+					zap.String("method", st.Method),
+					zap.String("external", net.JoinHostPort(ip.String(), fmt.Sprint(ext))),
+					zap.Uint16("internal port", internal))
+			}
+			st.ExternalIP, st.ExternalPort, st.Mapped = ip, ext, true
+		}
+	}
+	if err != nil {
+		n.log.Warn("NAT port mapping failed", zap.String("method", st.Method), zap.Error(err))
+		st.Mapped = false
+	}
+	n.setStatus(st)
+	return st, added
+}
+
+// shutdown stops the service removing the mapping, it returns after the
+// mapping is removed.
+func (n *natService) shutdown() {
+	n.runLock.Lock()
+	defer n.runLock.Unlock()
+	if n.stopped {
+		return
+	}
+	n.stopped = true
+	close(n.quit)
+	if n.started {
+		<-n.done
+	}
+}
@@ -0,0 +1,565 @@
+package network
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// newFakeNATPMPGateway starts a NAT-PMP gateway answering with the given
+// result code and external port (the requested one if zero). Requests
+// received are sent to the returned channel.
+func newFakeNATPMPGateway(t *testing.T, code uint16, extPort uint16) (string, chan []byte) {
+	reqs := make(chan []byte, 10)
+	addr := newNATPMPResponder(t, func(req []byte) [][]byte {
+		reqs <- req
+		return [][]byte{natPMPResponse(req, code, extPort)}
+	})
+	return addr, reqs
+}
+
+// newNATPMPResponder starts a UDP server sending responses returned by the
+// given function to every request received.
+func newNATPMPResponder(t *testing.T, respond func(req []byte) [][]byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			for _, resp := range respond(append([]byte{}, buf[:n]...)) {
+				_, _ = conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// natPMPResponse creates a valid response to the given request.
+func natPMPResponse(req []byte, code uint16, extPort uint16) []byte {
+	var resp []byte
+	switch req[1] {
+	case natPMPOpExternalAddress:
+		resp = make([]byte, 12)
+		copy(resp[8:], net.IPv4(1, 2, 3, 4).To4())
+	case natPMPOpMapTCP:
+		resp = make([]byte, 16)
+		copy(resp[8:], req[4:6])
+		if extPort != 0 && binary.BigEndian.Uint32(req[8:]) != 0 {
+			binary.BigEndian.PutUint16(resp[10:], extPort)
+		} else {
+			copy(resp[10:], req[6:8])
+		}
+		copy(resp[12:], req[8:12])
+	}
+	resp[1] = req[1] | natPMPResponseFlag
+	binary.BigEndian.PutUint16(resp[2:], code)
+	return resp
+}
+
+func TestNATPMP(t *testing.T) {
+	gw, reqs := newFakeNATPMPGateway(t, 0, 30333)
+	n, err := newNATPMP(gw)
+	require.NoError(t, err)
+	require.Equal(t, "natpmp", n.String())
+
+	ip, err := n.ExternalIP()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4", ip.String())
+	require.Equal(t, []byte{0, natPMPOpExternalAddress}, <-reqs)
+
+	ext, err := n.AddMapping(20333, 20333, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, uint16(30333), ext)
+	require.Equal(t, natPMPMapRequest(20333, 20333, 3600), <-reqs)
+
+	require.NoError(t, n.DeleteMapping(20333, ext))
+	require.Equal(t, natPMPMapRequest(20333, 0, 0), <-reqs)
+
+	t.Run("error code", func(t *testing.T) {
+		gw, _ := newFakeNATPMPGateway(t, 3, 0)
+		n, err := newNATPMP(gw)
+		require.NoError(t, err)
+		_, err = n.ExternalIP()
+		require.Error(t, err)
+	})
+	t.Run("no response", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+		n, err := newNATPMP(conn.LocalAddr().String())
+		require.NoError(t, err)
+		n.timeout = 10 * time.Millisecond
+		_, err = n.AddMapping(20333, 20333, time.Hour)
+		require.Error(t, err)
+	})
+	t.Run("default port", func(t *testing.T) {
+		n, err := newNATPMP("192.168.0.1")
+		require.NoError(t, err)
+		require.Equal(t, "192.168.0.1:5351", n.gateway)
+	})
+	t.Run("bogus responses", func(t *testing.T) {
+		newClient := func(t *testing.T, mangle func(resp []byte) [][]byte) *natPMP {
+			gw := newNATPMPResponder(t, func(req []byte) [][]byte {
+				return mangle(natPMPResponse(req, 0, 0))
+			})
+			n, err := newNATPMP(gw)
+			require.NoError(t, err)
+			n.timeout = 10 * time.Millisecond
+			return n
+		}
+		zero := func(off, l int) func(resp []byte) [][]byte {
+			return func(resp []byte) [][]byte {
+				copy(resp[off:], make([]byte, l))
+				return [][]byte{resp}
+			}
+		}
+		for name, tc := range map[string]struct {
+			mangle func(resp []byte) [][]byte
+			// ipOK and mapOK are set if the field mangled is not used
+			// in the response.
+			ipOK, mapOK bool
+		}{
+			"empty":         {mangle: func(resp []byte) [][]byte { return [][]byte{{}} }},
+			"short":         {mangle: func(resp []byte) [][]byte { return [][]byte{resp[:8]} }},
+			"bad version":   {mangle: func(resp []byte) [][]byte { resp[0] = 1; return [][]byte{resp} }},
+			"bad opcode":    {mangle: func(resp []byte) [][]byte { resp[1] ^= 1; return [][]byte{resp} }},
+			"no flag":       {mangle: func(resp []byte) [][]byte { resp[1] &^= natPMPResponseFlag; return [][]byte{resp} }},
+			"zero address":  {mangle: zero(8, 4), mapOK: true},
+			"bad internal":  {mangle: func(resp []byte) [][]byte { resp[8]++; return [][]byte{resp} }, ipOK: true},
+			"zero external": {mangle: zero(10, 2), ipOK: true},
+			"zero lifetime": {mangle: zero(12, 4), ipOK: true},
+		} {
+			t.Run(name, func(t *testing.T) {
+				n := newClient(t, tc.mangle)
+				if !tc.ipOK {
+					_, err := n.ExternalIP()
+					require.Error(t, err)
+				}
+				if !tc.mapOK {
+					_, err := n.AddMapping(20333, 20333, time.Hour)
+					require.Error(t, err)
+				}
+			})
+		}
+		t.Run("garbage before response", func(t *testing.T) {
+			n := newClient(t, func(resp []byte) [][]byte {
+				bad := append([]byte{}, resp...)
+				bad[1] ^= 1
+				return [][]byte{{0xff}, bad, resp}
+			})
+			ext, err := n.AddMapping(20333, 20333, time.Hour)
+			require.NoError(t, err)
+			require.Equal(t, uint16(20333), ext)
+		})
+	})
+}
+
+func TestGatewayFromRoutes(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "route")
+	require.NoError(t, os.WriteFile(file, []byte(
+		"Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"+
+			"eth0\t0000A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n"+
+			"eth0\t00000000\t0100A8C0\t0003\t0\t0\t0\t00000000\t0\t0\t0\n"), 0600))
+	gw, err := gatewayFromRoutes(file)
+	require.NoError(t, err)
+	require.Equal(t, "192.168.0.1", gw.String())
+
+	require.NoError(t, os.WriteFile(file, []byte("Iface\tDestination\tGateway\n"), 0600))
+	_, err = gatewayFromRoutes(file)
+	require.Error(t, err)
+
+	_, err = gatewayFromRoutes(filepath.Join(t.TempDir(), "unknown"))
+	require.Error(t, err)
+}
+
+func TestUPnPIGD(t *testing.T) {
+	const (
+		svcType = "urn:schemas-upnp-org:service:WANIPConnection:1"
+		desc    = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <serviceList>
+      <service><serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType><controlURL>/l3f</controlURL></service>
+    </serviceList>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service><serviceType>` + svcType + `</serviceType><controlURL>/ctl/IPConn</controlURL></service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+	)
+	var (
+		lock    sync.Mutex
+		actions []string
+		bodies  []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/desc.xml":
+			_, _ = w.Write([]byte(desc))
+		case "/ctl/IPConn":
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			action := req.Header.Get("SOAPAction")
+			lock.Lock()
+			actions = append(actions, action)
+			bodies = append(bodies, string(body))
+			lock.Unlock()
+			switch action {
+			case `"` + svcType + `#GetExternalIPAddress"`:
+				_, _ = w.Write([]byte(`<?xml version="1.0"?><s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>` +
+					`<u:GetExternalIPAddressResponse xmlns:u="` + svcType + `"><NewExternalIPAddress>1.2.3.4</NewExternalIPAddress>` +
+					`</u:GetExternalIPAddressResponse></s:Body></s:Envelope>`))
+			case `"` + svcType + `#AddPortMapping"`, `"` + svcType + `#DeletePortMapping"`:
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := newUPnPIGD(srv.URL + "/desc.xml")
+	require.NoError(t, err)
+	require.Equal(t, "upnp", u.String())
+	require.Equal(t, srv.URL+"/ctl/IPConn", u.controlURL)
+	require.Equal(t, "127.0.0.1", u.localIP.String())
+
+	ip, err := u.ExternalIP()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4", ip.String())
+
+	ext, err := u.AddMapping(20333, 30333, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, uint16(30333), ext)
+	require.NoError(t, u.DeleteMapping(20333, 30333))
+
+	lock.Lock()
+	require.Equal(t, 3, len(actions))
+	require.True(t, strings.Contains(bodies[1], "<NewExternalPort>30333</NewExternalPort>"))
+	require.True(t, strings.Contains(bodies[1], "<NewInternalPort>20333</NewInternalPort>"))
+	require.True(t, strings.Contains(bodies[1], "<NewInternalClient>127.0.0.1</NewInternalClient>"))
+	require.True(t, strings.Contains(bodies[1], "<NewLeaseDuration>3600</NewLeaseDuration>"))
+	require.True(t, strings.Contains(bodies[2], "<NewExternalPort>30333</NewExternalPort>"))
+	lock.Unlock()
+
+	t.Run("no WAN connection", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte(`<root><device><deviceType>urn:schemas-upnp-org:device:MediaServer:1</deviceType></device></root>`))
+		}))
+		defer srv.Close()
+		_, err := newUPnPIGD(srv.URL)
+		require.Error(t, err)
+	})
+	t.Run("not found", func(t *testing.T) {
+		_, err := newUPnPIGD(srv.URL + "/unknown.xml")
+		require.Error(t, err)
+	})
+}
+
+func TestParseSSDPResponse(t *testing.T) {
+	var (
+		from = &net.UDPAddr{IP: net.IPv4(192, 168, 0, 1), Port: 1900}
+		resp = func(status, location string) []byte {
+			r := "HTTP/1.1 " + status + "\r\nST: urn:schemas-upnp-org:device:InternetGatewayDevice:1\r\n"
+			if location != "" {
+				r += "LOCATION: " + location + "\r\n"
+			}
+			return []byte(r + "\r\n")
+		}
+	)
+	loc, err := parseSSDPResponse(resp("200 OK", "http://192.168.0.1:5000/desc.xml"), from)
+	require.NoError(t, err)
+	require.Equal(t, "http://192.168.0.1:5000/desc.xml", loc)
+
+	for name, data := range map[string][]byte{
+		"empty":          {},
+		"garbage":        []byte("\x00\xff garbage\r\n\r\n"),
+		"request":        []byte("M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\n\r\n"),
+		"bad status":     resp("404 Not Found", "http://192.168.0.1:5000/desc.xml"),
+		"no location":    resp("200 OK", ""),
+		"bad location":   resp("200 OK", "http://[::1"),
+		"bad scheme":     resp("200 OK", "file:///etc/passwd"),
+		"other host":     resp("200 OK", "http://192.168.0.2:5000/desc.xml"),
+		"hostname":       resp("200 OK", "http://router.local:5000/desc.xml"),
+		"truncated":      []byte("HTTP/1.1 200 OK\r\nLOCATION: http://192.168.0.1"),
+		"external":       resp("200 OK", "http://1.2.3.4/desc.xml"),
+		"relative":       resp("200 OK", "/desc.xml"),
+		"no status code": []byte("HTTP/1.1\r\nLOCATION: http://192.168.0.1:5000/desc.xml\r\n\r\n"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseSSDPResponse(data, from)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestUPnPBogusResponses(t *testing.T) {
+	const svcType = "urn:schemas-upnp-org:service:WANPPPConnection:1"
+	var (
+		lock     sync.Mutex
+		descResp string
+		soapResp string
+		soapCode int
+	)
+	set := func(desc, soap string, code int) {
+		lock.Lock()
+		descResp, soapResp, soapCode = desc, soap, code
+		lock.Unlock()
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		if req.URL.Path == "/desc.xml" {
+			_, _ = w.Write([]byte(descResp))
+			return
+		}
+		w.WriteHeader(soapCode)
+		_, _ = w.Write([]byte(soapResp))
+	}))
+	t.Cleanup(srv.Close)
+	const goodDesc = `<root><device><serviceList><service><serviceType>` + svcType +
+		`</serviceType><controlURL>/ctl</controlURL></service></serviceList></device></root>`
+
+	t.Run("description", func(t *testing.T) {
+		for name, desc := range map[string]string{
+			"empty":       "",
+			"garbage":     "\x00\xff garbage",
+			"truncated":   goodDesc[:len(goodDesc)/2],
+			"no services": `<root><device></device></root>`,
+			"bad URLBase": `<root><URLBase>http://[::1</URLBase>` + goodDesc[len("<root>"):],
+			"too big":     "<root>" + strings.Repeat(" ", upnpMaxResponseSize) + goodDesc[len("<root>"):],
+		} {
+			t.Run(name, func(t *testing.T) {
+				set(desc, "", http.StatusOK)
+				_, err := newUPnPIGD(srv.URL + "/desc.xml")
+				require.Error(t, err)
+			})
+		}
+	})
+
+	set(goodDesc, "", http.StatusOK)
+	u, err := newUPnPIGD(srv.URL + "/desc.xml")
+	require.NoError(t, err)
+	ipResp := func(ip string) string {
+		return `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><u:GetExternalIPAddressResponse xmlns:u="` +
+			svcType + `"><NewExternalIPAddress>` + ip + `</NewExternalIPAddress></u:GetExternalIPAddressResponse></s:Body></s:Envelope>`
+	}
+	for name, tc := range map[string]struct {
+		resp string
+		code int
+	}{
+		"empty":       {"", http.StatusOK},
+		"garbage":     {"\x00\xff garbage", http.StatusOK},
+		"other tag":   {strings.ReplaceAll(ipResp("1.2.3.4"), "NewExternalIPAddress", "Other"), http.StatusOK},
+		"bad address": {ipResp("1.2.3"), http.StatusOK},
+		"zero":        {ipResp("0.0.0.0"), http.StatusOK},
+		"fault":       {ipResp("1.2.3.4"), http.StatusInternalServerError},
+		"too big":     {ipResp("1.2.3.4") + strings.Repeat(" ", upnpMaxResponseSize), http.StatusOK},
+	} {
+		t.Run("external IP, "+name, func(t *testing.T) {
+			set(goodDesc, tc.resp, tc.code)
+			_, err := u.ExternalIP()
+			require.Error(t, err)
+		})
+	}
+	t.Run("mapping fault", func(t *testing.T) {
+		set(goodDesc, "", http.StatusInternalServerError)
+		_, err := u.AddMapping(20333, 20333, time.Hour)
+		require.Error(t, err)
+		require.Error(t, u.DeleteMapping(20333, 20333))
+	})
+}
+
+// fakeNATMapper is a natMapper keeping the mapping state.
+type fakeNATMapper struct {
+	lock     sync.Mutex
+	fail     bool
+	mapped   map[uint16]uint16
+	requests int
+	deletes  int
+}
+
+func (f *fakeNATMapper) String() string { return "fake" }
+
+func (f *fakeNATMapper) ExternalIP() (net.IP, error) {
+	return net.IPv4(1, 2, 3, 4), nil
+}
+
+func (f *fakeNATMapper) AddMapping(internal, external uint16, _ time.Duration) (uint16, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests++
+	if f.fail {
+		return 0, errors.New("failed")
+	}
+	f.mapped[external] = internal
+	return external, nil
+}
+
+func (f *fakeNATMapper) DeleteMapping(_, external uint16) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.deletes++
+	delete(f.mapped, external)
+	return nil
+}
+
+func (f *fakeNATMapper) setFail(fail bool) {
+	f.lock.Lock()
+	f.fail = fail
+	f.lock.Unlock()
+}
+
+func (f *fakeNATMapper) state() (int, int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.mapped), f.requests
+}
+
+func TestNATService(t *testing.T) {
+	port := func() (uint16, error) { return 20333, nil }
+
+	t.Run("disabled", func(t *testing.T) {
+		s := newTestServer(t, ServerConfig{})
+		_, ok := s.NATStatus()
+		require.False(t, ok)
+	})
+	t.Run("mapped", func(t *testing.T) {
+		m := &fakeNATMapper{mapped: make(map[uint16]uint16)}
+		s := newTestServer(t, ServerConfig{AnnouncedPort: 30333, NAT: config.NAT{Enabled: true}})
+		s.nat.lease = 100 * time.Millisecond
+		s.nat.discover = func() (natMapper, error) { return m, nil }
+		s.nat.start(port, s.AnnouncedPort)
+		require.Eventually(t, func() bool {
+			st, _ := s.NATStatus()
+			return st.Mapped
+		}, time.Second, 10*time.Millisecond)
+
+		st, ok := s.NATStatus()
+		require.True(t, ok)
+		require.Equal(t, NATStatus{Method: "fake", ExternalIP: net.IPv4(1, 2, 3, 4), ExternalPort: 30333, Mapped: true}, st)
+		p, err := s.Port()
+		require.NoError(t, err)
+		require.Equal(t, uint16(30333), p)
+
+		// The lease is refreshed.
+		require.Eventually(t, func() bool {
+			_, reqs := m.state()
+			return reqs > 2
+		}, time.Second, 10*time.Millisecond)
+
+		s.nat.shutdown()
+		mapped, _ := m.state()
+		require.Equal(t, 0, mapped)
+		st, _ = s.NATStatus()
+		require.False(t, st.Mapped)
+	})
+	t.Run("mapping failed", func(t *testing.T) {
+		m := &fakeNATMapper{mapped: make(map[uint16]uint16), fail: true}
+		n := newNATService(config.NAT{Enabled: true}, zaptest.NewLogger(t))
+		n.discover = func() (natMapper, error) { return m, nil }
+		n.start(port, 0)
+		require.Eventually(t, func() bool {
+			_, reqs := m.state()
+			return reqs == 1
+		}, time.Second, 10*time.Millisecond)
+		n.shutdown()
+		require.Equal(t, NATStatus{Method: "fake", ExternalPort: 20333}, n.Status())
+		// The port can be mapped to some other host, so it's not touched.
+		require.Equal(t, 0, m.deletes)
+	})
+	t.Run("refresh failed", func(t *testing.T) {
+		m := &fakeNATMapper{mapped: make(map[uint16]uint16)}
+		n := newNATService(config.NAT{Enabled: true}, zaptest.NewLogger(t))
+		n.lease = 20 * time.Millisecond
+		n.discover = func() (natMapper, error) { return m, nil }
+		n.start(port, 0)
+		require.Eventually(t, func() bool { return n.Status().Mapped }, time.Second, 10*time.Millisecond)
+		m.setFail(true)
+		require.Eventually(t, func() bool { return !n.Status().Mapped }, time.Second, 10*time.Millisecond)
+		// Still removed, the gateway keeps it until the lease expires.
+		n.shutdown()
+		mapped, _ := m.state()
+		require.Equal(t, 0, mapped)
+		require.Equal(t, 1, m.deletes)
+	})
+	t.Run("stopped during discovery", func(t *testing.T) {
+		var (
+			m           = &fakeNATMapper{mapped: make(map[uint16]uint16)}
+			n           = newNATService(config.NAT{Enabled: true}, zaptest.NewLogger(t))
+			discovering = make(chan struct{})
+			stopped     = make(chan struct{})
+		)
+		n.discover = func() (natMapper, error) {
+			close(discovering)
+			<-stopped
+			return m, nil
+		}
+		n.start(port, 0)
+		<-discovering
+		go func() {
+			// Discovery is finished after shutdown is requested.
+			<-n.quit
+			close(stopped)
+		}()
+		n.shutdown()
+		mapped, reqs := m.state()
+		require.Equal(t, 0, mapped)
+		require.Equal(t, 0, reqs)
+		require.False(t, n.Status().Mapped)
+	})
+	t.Run("started after shutdown", func(t *testing.T) {
+		n := newNATService(config.NAT{Enabled: true}, zaptest.NewLogger(t))
+		n.discover = func() (natMapper, error) {
+			t.Fatal("service is started")
+			return nil, nil
+		}
+		n.shutdown()
+		n.start(port, 0)
+		n.shutdown()
+	})
+	t.Run("no gateway", func(t *testing.T) {
+		n := newNATService(config.NAT{Enabled: true}, zaptest.NewLogger(t))
+		n.discover = func() (natMapper, error) { return nil, errors.New("not found") }
+		n.start(port, 0)
+		<-n.done
+		require.Equal(t, NATStatus{}, n.Status())
+		n.shutdown()
+	})
+	t.Run("not started", func(t *testing.T) {
+		n := newNATService(config.NAT{Enabled: true}, zaptest.NewLogger(t))
+		require.Equal(t, defaultNATLease, n.lease)
+		n.shutdown()
+	})
+}
@@ -0,0 +1,197 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// natPMPPort is the NAT-PMP gateway port.
+	natPMPPort = 5351
+	// natPMPInitialTimeout is the initial retransmission interval, it's
+	// doubled on every try as recommended by RFC 6886.
+	natPMPInitialTimeout = 250 * time.Millisecond
+	// natPMPTries is the number of requests sent before giving up.
+	natPMPTries = 4
+
+	natPMPOpExternalAddress = 0
+	natPMPOpMapTCP          = 2
+	natPMPResponseFlag      = 128
+)
+
+// natPMP is a NAT-PMP (RFC 6886) client.
+type natPMP struct {
+	// gateway is the gateway address (with a port).
+	gateway string
+	timeout time.Duration
+}
+
+func newNATPMP(gateway string) (*natPMP, error) {
+	if gateway == "" {
+		gw, err := defaultGateway()
+		if err != nil {
+			return nil, err
+		}
+		gateway = gw.String()
+	}
+	if _, _, err := net.SplitHostPort(gateway); err != nil {
+		gateway = net.JoinHostPort(gateway, fmt.Sprint(natPMPPort))
+	}
+	return &natPMP{gateway: gateway, timeout: natPMPInitialTimeout}, nil
+}
+
+// String implements the natMapper interface.
+func (n *natPMP) String() string {
+	return "natpmp"
+}
+
+// request sends the request to the gateway retransmitting it if needed and
+// returns the response of the expected length.
+func (n *natPMP) request(req []byte, respLen int) ([]byte, error) {
+	conn, err := net.Dial("udp", n.gateway)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var (
+		resp    = make([]byte, 16)
+		timeout = n.timeout
+	)
+	for i := 0; i < natPMPTries; i++ {
+		if _, err = conn.Write(req); err != nil {
+			return nil, err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		timeout *= 2
+		for {
+			var l int
+			l, err = conn.Read(resp)
+			if err != nil {
+				break
+			}
+			// Responses to the previous requests can be received as well.
+			if l < respLen || resp[0] != 0 || resp[1] != req[1]|natPMPResponseFlag {
+				continue
+			}
+			if code := binary.BigEndian.Uint16(resp[2:]); code != 0 {
+				return nil, fmt.Errorf("NAT-PMP gateway returned result code %d", code)
+			}
+			return resp[:respLen], nil
+		}
+		var ne net.Error
+		if !errors.As(err, &ne) || !ne.Timeout() {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("no response from NAT-PMP gateway %s", n.gateway)
+}
+
+// ExternalIP implements the natMapper interface.
+func (n *natPMP) ExternalIP() (net.IP, error) {
+	resp, err := n.request([]byte{0, natPMPOpExternalAddress}, 12)
+	if err != nil {
+		return nil, err
+	}
+	// Gateways return zero address if they don't have an external one.
+	ip := net.IP(resp[8:12])
+	if ip.IsUnspecified() {
+		return nil, errors.New("NAT-PMP gateway has no external address")
+	}
+	return ip, nil
+}
+
+// AddMapping implements the natMapper interface.
+func (n *natPMP) AddMapping(internal, external uint16, lifetime time.Duration) (uint16, error) {
+	resp, err := n.request(natPMPMapRequest(internal, external, uint32(lifetime/time.Second)), 16)
+	if err != nil {
+		return 0, err
+	}
+	var (
+		respInternal = binary.BigEndian.Uint16(resp[8:])
+		respExternal = binary.BigEndian.Uint16(resp[10:])
+		respLifetime = binary.BigEndian.Uint32(resp[12:])
+	)
+	if respInternal != internal || respExternal == 0 || respLifetime == 0 {
+		return 0, fmt.Errorf("bad NAT-PMP mapping response (internal port %d, external port %d, lifetime %d)",
+			respInternal, respExternal, respLifetime)
+	}
+	return respExternal, nil
+}
+
+// DeleteMapping implements the natMapper interface.
+func (n *natPMP) DeleteMapping(internal, _ uint16) error {
+	_, err := n.request(natPMPMapRequest(internal, 0, 0), 16)
+	return err
+}
+
+func natPMPMapRequest(internal, external uint16, lifetime uint32) []byte {
+	req := make([]byte, 12)
+	req[1] = natPMPOpMapTCP
+	binary.BigEndian.PutUint16(req[4:], internal)
+	binary.BigEndian.PutUint16(req[6:], external)
+	binary.BigEndian.PutUint32(req[8:], lifetime)
+	return req
+}
+
+// defaultGateway returns the default IPv4 gateway address. It's taken from
+// the routing table where available (Linux), otherwise it's guessed to be
+// the first address of the network used for outgoing connections.
+func defaultGateway() (net.IP, error) {
+	if gw, err := gatewayFromRoutes("/proc/net/route"); err == nil {
+		return gw, nil
+	}
+	ip, err := outboundIP(net.JoinHostPort("1.1.1.1", "80"))
+	if err != nil {
+		return nil, fmt.Errorf("can't detect the gateway: %w", err)
+	}
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return nil, errors.New("can't detect the gateway: no IPv4 address")
+	}
+	return net.IPv4(ip4[0], ip4[1], ip4[2], 1), nil
+}
+
+// gatewayFromRoutes parses the Linux routing table file and returns the
+// gateway of the default route.
+func gatewayFromRoutes(file string) (net.IP, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	sc.Scan() // Header.
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != 4 {
+			continue
+		}
+		// Little-endian.
+		return net.IPv4(b[3], b[2], b[1], b[0]), nil
+	}
+	return nil, errors.New("no default route")
+}
+
+// outboundIP returns the local address used for connections to the given
+// one. No packets are sent.
+func outboundIP(addr string) (net.IP, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
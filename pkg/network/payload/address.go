@@ -14,6 +14,10 @@ import (
 // one payload.
 const MaxAddrsCount = 200
 
+// minAddrSize is the minimum size of the serialized AddressAndTime (timestamp,
+// IP and an empty capabilities list).
+const minAddrSize = 4 + 16 + 1
+
 // AddressAndTime payload.
 type AddressAndTime struct {
 	Timestamp    uint32
@@ -79,8 +83,16 @@ func NewAddressList(n int) *AddressList {
 
 // DecodeBinary implements the Serializable interface.
 func (p *AddressList) DecodeBinary(br *io.BinReader) {
-	br.ReadArray(&p.Addrs, MaxAddrsCount)
-	if len(p.Addrs) == 0 {
+	n := readArrayLen(br, MaxAddrsCount, minAddrSize)
+	if br.Err != nil {
+		return
+	}
+	p.Addrs = make([]*AddressAndTime, n)
+	for i := range p.Addrs {
+		p.Addrs[i] = new(AddressAndTime)
+		p.Addrs[i].DecodeBinary(br)
+	}
+	if br.Err == nil && len(p.Addrs) == 0 {
 		br.Err = errors.New("no addresses listed")
 	}
 }
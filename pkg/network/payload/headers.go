@@ -20,6 +20,10 @@ const (
 	MaxHeadersAllowed = 2000
 )
 
+// minHeaderSize is the minimum size of the serialized header (hashable fields
+// without a state root, witness count and an empty witness).
+const minHeaderSize = 4 + 32 + 32 + 8 + 8 + 4 + 1 + 20 + 1 + 2
+
 // ErrTooManyHeaders is an error returned when too many headers have been received.
 var ErrTooManyHeaders = fmt.Errorf("too many headers were received (max: %d)", MaxHeadersAllowed)
 
@@ -41,6 +45,10 @@ func (p *Headers) DecodeBinary(br *io.BinReader) {
 	if limitExceeded = lenHeaders > MaxHeadersAllowed; limitExceeded {
 		lenHeaders = MaxHeadersAllowed
 	}
+	if left := br.Len(); br.Err == nil && left >= 0 && lenHeaders*minHeaderSize > uint64(left) {
+		br.Err = fmt.Errorf("%d headers don't fit into %d bytes left", lenHeaders, left)
+		return
+	}
 
 	p.Hdrs = make([]*block.Header, lenHeaders)
 
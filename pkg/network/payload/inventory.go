@@ -60,7 +60,14 @@ func NewInventory(typ InventoryType, hashes []util.Uint256) *Inventory {
 // DecodeBinary implements the Serializable interface.
 func (p *Inventory) DecodeBinary(br *io.BinReader) {
 	p.Type = InventoryType(br.ReadB())
-	br.ReadArray(&p.Hashes, MaxHashesCount)
+	n := readArrayLen(br, MaxHashesCount, util.Uint256Size)
+	if br.Err != nil {
+		return
+	}
+	p.Hashes = make([]util.Uint256, n)
+	for i := range p.Hashes {
+		p.Hashes[i].DecodeBinary(br)
+	}
 }
 
 // EncodeBinary implements the Serializable interface.
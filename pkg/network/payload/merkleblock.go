@@ -27,9 +27,17 @@ func (m *MerkleBlock) DecodeBinary(br *io.BinReader) {
 		return
 	}
 	m.TxCount = txCount
-	br.ReadArray(&m.Hashes, m.TxCount)
-	if txCount != len(m.Hashes) {
+	n := readArrayLen(br, m.TxCount, util.Uint256Size)
+	if br.Err != nil {
+		return
+	}
+	if txCount != n {
 		br.Err = errors.New("invalid tx count")
+		return
+	}
+	m.Hashes = make([]util.Uint256, n)
+	for i := range m.Hashes {
+		m.Hashes[i].DecodeBinary(br)
 	}
 	m.Flags = br.ReadVarBytes((txCount + 7) / 8)
 }
@@ -1,6 +1,10 @@
 package payload
 
-import "github.com/nspcc-dev/neo-go/pkg/io"
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
 
 // MaxSize is the maximum payload size in decompressed form.
 const MaxSize = 0x02000000
@@ -10,6 +14,26 @@ type Payload interface {
 	io.Serializable
 }
 
+// readArrayLen reads the array length and checks it against the given limit
+// and against the amount of data left in the reader (if it's known) given
+// the minimum element size, so that no memory is allocated for elements that
+// can't be there.
+func readArrayLen(br *io.BinReader, max int, minElemSize int) int {
+	l := br.ReadVarUint()
+	if br.Err != nil {
+		return 0
+	}
+	if l > uint64(max) {
+		br.Err = fmt.Errorf("array is too big (%d)", l)
+		return 0
+	}
+	if left := br.Len(); left >= 0 && l*uint64(minElemSize) > uint64(left) {
+		br.Err = fmt.Errorf("array of %d elements doesn't fit into %d bytes left", l, left)
+		return 0
+	}
+	return int(l)
+}
+
 // NullPayload is a dummy payload with no fields.
 type NullPayload struct {
 }
@@ -0,0 +1,63 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadArrayLen(t *testing.T) {
+	w := io.NewBufBinWriter()
+	w.WriteVarUint(3)
+	w.WriteBytes(make([]byte, 6))
+	data := w.Bytes()
+
+	r := io.NewBinReaderFromBuf(data)
+	require.Equal(t, 3, readArrayLen(r, 3, 2))
+	require.NoError(t, r.Err)
+
+	r = io.NewBinReaderFromBuf(data)
+	require.Equal(t, 0, readArrayLen(r, 2, 2))
+	require.Error(t, r.Err)
+
+	r = io.NewBinReaderFromBuf(data)
+	require.Equal(t, 0, readArrayLen(r, 3, 3))
+	require.Error(t, r.Err)
+
+	r = io.NewBinReaderFromBuf(nil)
+	require.Equal(t, 0, readArrayLen(r, 3, 1))
+	require.Error(t, r.Err)
+}
+
+// TestBogusArrayLength checks that payloads with lengths not backed by data
+// are rejected.
+func TestBogusArrayLength(t *testing.T) {
+	bogus := func(prefix []byte, n uint64) []byte {
+		w := io.NewBufBinWriter()
+		w.WriteBytes(prefix)
+		w.WriteVarUint(n)
+		w.WriteBytes(make([]byte, 64))
+		return w.Bytes()
+	}
+	t.Run("inventory", func(t *testing.T) {
+		require.Error(t, testserdes.DecodeBinary(bogus([]byte{byte(BlockType)}, 3), new(Inventory)))
+		require.Error(t, testserdes.DecodeBinary(bogus([]byte{byte(BlockType)}, MaxHashesCount+1), new(Inventory)))
+	})
+	t.Run("addresses", func(t *testing.T) {
+		require.Error(t, testserdes.DecodeBinary(bogus(nil, 4), new(AddressList)))
+		require.Error(t, testserdes.DecodeBinary(bogus(nil, MaxAddrsCount+1), new(AddressList)))
+	})
+	t.Run("headers", func(t *testing.T) {
+		require.Error(t, testserdes.DecodeBinary(bogus(nil, 1), new(Headers)))
+		require.Error(t, testserdes.DecodeBinary(bogus(nil, MaxHeadersAllowed+1), new(Headers)))
+	})
+	t.Run("merkle block", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		newDumbBlock().EncodeBinary(w.BinWriter)
+		w.WriteVarUint(1000) // TxCount.
+		require.NoError(t, w.Err)
+		require.Error(t, testserdes.DecodeBinary(bogus(w.Bytes(), 1000), new(MerkleBlock)))
+	})
+}
@@ -35,6 +35,12 @@ func TestVersionEncodeDecode(t *testing.T) {
 				StartHeight: height,
 			},
 		},
+		{
+			Type: capability.PayloadCompression,
+			Data: &capability.Compression{
+				Algorithms: capability.Deflate,
+			},
+		},
 	}
 
 	version := NewVersion(magic, id, useragent, capabilities)
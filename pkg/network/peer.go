@@ -81,4 +81,17 @@ type Peer interface {
 	// CanProcessAddr checks whether an addr command is expected to come from
 	// this peer and can be processed.
 	CanProcessAddr() bool
+
+	// Traffic returns the amount of data exchanged with the peer.
+	Traffic() PeerTraffic
+}
+
+// PeerTraffic contains the data exchange statistics of the connected peer.
+type PeerTraffic struct {
+	// BytesSent is the number of bytes written to the connection.
+	BytesSent uint64
+	// BytesReceived is the number of bytes in messages received.
+	BytesReceived uint64
+	// SendQueueLen is the number of packets waiting to be sent.
+	SendQueueLen int
 }
@@ -0,0 +1,202 @@
+package network
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Peer score is calculated as
+//
+//	blocks*scoreBlockReward - invalid*scoreInvalidPenalty - malformed*scoreMalformedPenalty -
+//	timeouts*scoreTimeoutPenalty - latency*scoreLatencyPenalty
+//
+// where blocks, invalid, malformed and timeouts are exponentially decaying
+// counters and latency is a moving average of ping round-trip time (in
+// seconds).
+const (
+	defaultScoreHalfLife = 10 * time.Minute
+	scoreBlockReward     = 0.01
+	scoreInvalidPenalty  = 10
+	// scoreMalformedPenalty is higher than scoreInvalidPenalty, since a
+	// message that can't even be decoded is never sent by honest nodes.
+	scoreMalformedPenalty = 20
+	scoreTimeoutPenalty   = 5
+	scoreLatencyPenalty   = 1
+	// scoreLatencyWeight is the weight of the new latency measurement in
+	// the moving average.
+	scoreLatencyWeight = 0.25
+	// scoreForgetLimit is the counter value below which it's considered to
+	// be completely decayed.
+	scoreForgetLimit = 0.01
+)
+
+type (
+	// PeerScore contains peer quality counters and the score derived from
+	// them.
+	PeerScore struct {
+		// Score is the resulting peer score, the higher it is the better.
+		Score float64
+		// InvalidPayloads is the (decaying) number of payloads from this
+		// peer that failed to be processed.
+		InvalidPayloads float64
+		// MalformedMessages is the (decaying) number of messages from this
+		// peer that failed to be decoded.
+		MalformedMessages float64
+		// Timeouts is the (decaying) number of ping timeouts.
+		Timeouts float64
+		// UsefulBlocks is the (decaying) number of new blocks received from
+		// this peer.
+		UsefulBlocks float64
+		// Latency is the average ping round-trip time.
+		Latency time.Duration
+	}
+
+	// peerScoreState is the PeerScore with decay and ping accounting data.
+	peerScoreState struct {
+		PeerScore
+		updated  time.Time
+		pingSent time.Time
+	}
+
+	// peerScores tracks peer scores by peer address, so that the history
+	// is kept for some time after the peer disconnects.
+	peerScores struct {
+		lock     sync.Mutex
+		halfLife time.Duration
+		now      func() time.Time
+		peers    map[string]*peerScoreState
+	}
+)
+
+func newPeerScores(halfLife time.Duration) *peerScores {
+	if halfLife <= 0 {
+		halfLife = defaultScoreHalfLife
+	}
+	return &peerScores{
+		halfLife: halfLife,
+		now:      time.Now,
+		peers:    make(map[string]*peerScoreState),
+	}
+}
+
+// decay applies the counter decay for the time passed since the last update
+// and recalculates the score.
+func (st *peerScoreState) decay(now time.Time, halfLife time.Duration) {
+	if dt := now.Sub(st.updated); dt > 0 {
+		k := math.Pow(0.5, float64(dt)/float64(halfLife))
+		st.InvalidPayloads *= k
+		st.MalformedMessages *= k
+		st.Timeouts *= k
+		st.UsefulBlocks *= k
+		st.updated = now
+	}
+	st.Score = st.UsefulBlocks*scoreBlockReward -
+		st.InvalidPayloads*scoreInvalidPenalty -
+		st.MalformedMessages*scoreMalformedPenalty -
+		st.Timeouts*scoreTimeoutPenalty -
+		st.Latency.Seconds()*scoreLatencyPenalty
+}
+
+// negligible returns true if the state has no data worth keeping.
+func (st *peerScoreState) negligible() bool {
+	return st.InvalidPayloads < scoreForgetLimit &&
+		st.MalformedMessages < scoreForgetLimit &&
+		st.Timeouts < scoreForgetLimit &&
+		st.UsefulBlocks < scoreForgetLimit &&
+		st.pingSent.IsZero()
+}
+
+// update calls f for the decayed state of the given peer (creating it if
+// needed).
+func (ps *peerScores) update(addr string, f func(st *peerScoreState, now time.Time)) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+	now := ps.now()
+	st, ok := ps.peers[addr]
+	if !ok {
+		st = &peerScoreState{updated: now}
+		ps.peers[addr] = st
+	}
+	st.decay(now, ps.halfLife)
+	f(st, now)
+	st.decay(now, ps.halfLife)
+}
+
+// invalidPayload accounts for a payload that failed to be processed.
+func (ps *peerScores) invalidPayload(addr string) {
+	ps.update(addr, func(st *peerScoreState, _ time.Time) { st.InvalidPayloads++ })
+}
+
+// malformedMessage accounts for a message that can't be decoded.
+func (ps *peerScores) malformedMessage(addr string) {
+	ps.update(addr, func(st *peerScoreState, _ time.Time) { st.MalformedMessages++ })
+}
+
+// timeout accounts for a ping timeout.
+func (ps *peerScores) timeout(addr string) {
+	ps.update(addr, func(st *peerScoreState, _ time.Time) {
+		st.Timeouts++
+		st.pingSent = time.Time{}
+	})
+}
+
+// usefulBlock accounts for a new block received from the peer.
+func (ps *peerScores) usefulBlock(addr string) {
+	ps.update(addr, func(st *peerScoreState, _ time.Time) { st.UsefulBlocks++ })
+}
+
+// pingSent remembers the time of the ping sent to the peer, only the first
+// of outstanding pings is accounted for.
+func (ps *peerScores) pingSent(addr string) {
+	ps.update(addr, func(st *peerScoreState, now time.Time) {
+		if st.pingSent.IsZero() {
+			st.pingSent = now
+		}
+	})
+}
+
+// pongReceived updates the average peer latency.
+func (ps *peerScores) pongReceived(addr string) {
+	ps.update(addr, func(st *peerScoreState, now time.Time) {
+		if st.pingSent.IsZero() {
+			return
+		}
+		rtt := now.Sub(st.pingSent)
+		st.pingSent = time.Time{}
+		if st.Latency == 0 {
+			st.Latency = rtt
+			return
+		}
+		st.Latency = time.Duration(scoreLatencyWeight*float64(rtt) + (1-scoreLatencyWeight)*float64(st.Latency))
+	})
+}
+
+// get returns the current score of the given peer, zero value is returned
+// for unknown peers.
+func (ps *peerScores) get(addr string) PeerScore {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+	st, ok := ps.peers[addr]
+	if !ok {
+		return PeerScore{}
+	}
+	st.decay(ps.now(), ps.halfLife)
+	return st.PeerScore
+}
+
+// prune removes completely decayed states of disconnected peers.
+func (ps *peerScores) prune(connected map[string]bool) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+	now := ps.now()
+	for addr, st := range ps.peers {
+		if connected[addr] {
+			continue
+		}
+		st.decay(now, ps.halfLife)
+		if st.negligible() {
+			delete(ps.peers, addr)
+		}
+	}
+}
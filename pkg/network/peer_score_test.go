@@ -0,0 +1,105 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPeerScores(t *testing.T) (*peerScores, *time.Time) {
+	var now = time.Unix(1000000, 0)
+	ps := newPeerScores(time.Minute)
+	ps.now = func() time.Time { return now }
+	return ps, &now
+}
+
+func TestPeerScores(t *testing.T) {
+	const addr = "127.0.0.1:20333"
+
+	t.Run("default half-life", func(t *testing.T) {
+		require.Equal(t, defaultScoreHalfLife, newPeerScores(0).halfLife)
+	})
+	t.Run("unknown", func(t *testing.T) {
+		ps, _ := newTestPeerScores(t)
+		require.Equal(t, PeerScore{}, ps.get(addr))
+	})
+	t.Run("counters", func(t *testing.T) {
+		ps, _ := newTestPeerScores(t)
+		for i := 0; i < 100; i++ {
+			ps.usefulBlock(addr)
+		}
+		require.InDelta(t, 100*scoreBlockReward, ps.get(addr).Score, 1e-9)
+
+		ps.invalidPayload(addr)
+		ps.malformedMessage(addr)
+		ps.timeout(addr)
+		sc := ps.get(addr)
+		require.Equal(t, float64(1), sc.InvalidPayloads)
+		require.Equal(t, float64(1), sc.MalformedMessages)
+		require.Equal(t, float64(1), sc.Timeouts)
+		require.Equal(t, float64(100), sc.UsefulBlocks)
+		require.InDelta(t, 100*scoreBlockReward-scoreInvalidPenalty-scoreMalformedPenalty-scoreTimeoutPenalty, sc.Score, 1e-9)
+	})
+	t.Run("decay", func(t *testing.T) {
+		ps, now := newTestPeerScores(t)
+		ps.invalidPayload(addr)
+		ps.invalidPayload(addr)
+
+		*now = now.Add(time.Minute)
+		sc := ps.get(addr)
+		require.InDelta(t, 1, sc.InvalidPayloads, 1e-9)
+		require.InDelta(t, -scoreInvalidPenalty, sc.Score, 1e-9)
+
+		*now = now.Add(2 * time.Minute)
+		require.InDelta(t, 0.25, ps.get(addr).InvalidPayloads, 1e-9)
+	})
+	t.Run("latency", func(t *testing.T) {
+		ps, now := newTestPeerScores(t)
+		ps.pongReceived(addr) // Unexpected, ignored.
+		require.Equal(t, time.Duration(0), ps.get(addr).Latency)
+
+		ps.pingSent(addr)
+		*now = now.Add(time.Second)
+		ps.pingSent(addr) // Outstanding ping, the first one is used.
+		*now = now.Add(time.Second)
+		ps.pongReceived(addr)
+		sc := ps.get(addr)
+		require.Equal(t, 2*time.Second, sc.Latency)
+		require.InDelta(t, -2*scoreLatencyPenalty, sc.Score, 1e-9)
+
+		ps.pingSent(addr)
+		*now = now.Add(6 * time.Second)
+		ps.pongReceived(addr)
+		require.Equal(t, 3*time.Second, ps.get(addr).Latency)
+
+		// Latency doesn't decay.
+		*now = now.Add(time.Hour)
+		require.Equal(t, 3*time.Second, ps.get(addr).Latency)
+
+		t.Run("timeout", func(t *testing.T) {
+			ps.pingSent(addr)
+			ps.timeout(addr)
+			*now = now.Add(time.Second)
+			ps.pongReceived(addr)
+			sc := ps.get(addr)
+			require.Equal(t, 3*time.Second, sc.Latency)
+			require.InDelta(t, 1, sc.Timeouts, 0.02)
+		})
+	})
+	t.Run("prune", func(t *testing.T) {
+		ps, now := newTestPeerScores(t)
+		ps.invalidPayload(addr)
+		ps.invalidPayload("127.0.0.2:20333")
+		ps.pingSent("127.0.0.3:20333")
+
+		ps.prune(nil)
+		require.Equal(t, 3, len(ps.peers))
+
+		*now = now.Add(time.Hour)
+		ps.prune(map[string]bool{addr: true})
+		require.Equal(t, 2, len(ps.peers))
+		require.Contains(t, ps.peers, addr)
+		require.Contains(t, ps.peers, "127.0.0.3:20333") // Pending ping.
+	})
+}
@@ -0,0 +1,199 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"go.uber.org/zap"
+)
+
+const (
+	// peerStoreVersion is the version of the stored peer addresses format.
+	peerStoreVersion = 1
+	// defaultPeerMaxAge is the default time after which an address that
+	// wasn't seen is dropped from the store.
+	defaultPeerMaxAge = 7 * 24 * time.Hour
+	// defaultMaxStoredPeers is the default number of addresses stored.
+	defaultMaxStoredPeers = 1000
+	// peerSaveInterval is the interval of periodic addresses saving.
+	peerSaveInterval = 5 * time.Minute
+
+	// maxStoredAddrLen is the maximum length of the stored address.
+	maxStoredAddrLen = 256
+	// maxStoredCapsLen is the maximum length of the serialized capabilities.
+	maxStoredCapsLen = 1024
+)
+
+// peerFile stores known good peer addresses in a file owned by the network
+// server, they're node-local data and don't belong to the chain DB.
+type peerFile struct {
+	lock sync.Mutex
+	path string
+}
+
+// get returns the file contents, nil is returned if there is no file yet.
+func (f *peerFile) get() ([]byte, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// put replaces the file contents with the given data. It's written into a
+// temporary file first, so that the file is never left partially written.
+func (f *peerFile) put(data []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// encodePeerAddresses serializes the given addresses. The format is a version
+// byte followed by an array of (address, last seen unix time, capabilities)
+// records, capabilities are wrapped into a byte array, so that records with
+// unknown capabilities can be skipped on decoding.
+func encodePeerAddresses(addrs []AddressWithCapabilities) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	w.WriteB(peerStoreVersion)
+	w.WriteVarUint(uint64(len(addrs)))
+	for i := range addrs {
+		cw := io.NewBufBinWriter()
+		addrs[i].Capabilities.EncodeBinary(cw.BinWriter)
+		if cw.Err != nil {
+			return nil, fmt.Errorf("failed to encode %s capabilities: %w", addrs[i].Address, cw.Err)
+		}
+		w.WriteString(addrs[i].Address)
+		w.WriteU64LE(uint64(addrs[i].LastSeen.Unix()))
+		w.WriteVarBytes(cw.Bytes())
+	}
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+
+// decodePeerAddresses deserializes addresses stored by encodePeerAddresses.
+// Records with capabilities that can't be decoded are skipped.
+func decodePeerAddresses(data []byte) ([]AddressWithCapabilities, error) {
+	r := io.NewBinReaderFromBuf(data)
+	ver := r.ReadB()
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if ver != peerStoreVersion {
+		return nil, fmt.Errorf("unsupported peer addresses version %d", ver)
+	}
+	n := r.ReadVarUint()
+	if n > maxPoolSize {
+		return nil, fmt.Errorf("too many peer addresses: %d", n)
+	}
+	addrs := make([]AddressWithCapabilities, 0, n)
+	for i := uint64(0); i < n; i++ {
+		var a AddressWithCapabilities
+
+		a.Address = r.ReadString(maxStoredAddrLen)
+		a.LastSeen = time.Unix(int64(r.ReadU64LE()), 0)
+		caps := r.ReadVarBytes(maxStoredCapsLen)
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		cr := io.NewBinReaderFromBuf(caps)
+		a.Capabilities.DecodeBinary(cr)
+		if cr.Err != nil {
+			continue
+		}
+		addrs = append(addrs, a)
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("unexpected data after peer addresses")
+	}
+	return addrs, nil
+}
+
+// selectPeerAddresses returns at most max addresses seen after the given time,
+// the most recently seen ones are preferred.
+func selectPeerAddresses(addrs []AddressWithCapabilities, after time.Time, max int) []AddressWithCapabilities {
+	res := make([]AddressWithCapabilities, 0, len(addrs))
+	for _, a := range addrs {
+		if a.LastSeen.After(after) {
+			res = append(res, a)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].LastSeen.After(res[j].LastSeen)
+	})
+	if len(res) > max {
+		res = res[:max]
+	}
+	return res
+}
+
+// peerMaxAge returns the configured stored address lifetime.
+func (s *Server) peerMaxAge() time.Duration {
+	if s.PeerPersistence.MaxAge <= 0 {
+		return defaultPeerMaxAge
+	}
+	return time.Duration(s.PeerPersistence.MaxAge) * time.Second
+}
+
+// maxStoredPeers returns the configured number of stored addresses.
+func (s *Server) maxStoredPeers() int {
+	if s.PeerPersistence.MaxAddresses <= 0 {
+		return defaultMaxStoredPeers
+	}
+	return s.PeerPersistence.MaxAddresses
+}
+
+// loadPeers restores known good addresses from the store and adds them to the
+// discoverer, stale addresses are dropped. Failures are not fatal, the node
+// just falls back to seeds.
+func (s *Server) loadPeers() {
+	data, err := s.peerStore.get()
+	if err != nil {
+		s.log.Warn("failed to load stored peer addresses", zap.Error(err))
+		return
+	}
+	if len(data) == 0 {
+		return
+	}
+	addrs, err := decodePeerAddresses(data)
+	if err != nil {
+		s.log.Warn("ignoring stored peer addresses", zap.Error(err))
+		return
+	}
+	addrs = selectPeerAddresses(addrs, time.Now().Add(-s.peerMaxAge()), s.maxStoredPeers())
+	if s.proxyDial == nil {
+		filtered := addrs[:0]
+		for _, a := range addrs {
+			if !isOnion(a.Address) {
+				filtered = append(filtered, a)
+			}
+		}
+		addrs = filtered
+	}
+	s.discovery.LoadGoodPeers(addrs)
+	s.log.Info("loaded stored peer addresses", zap.Int("count", len(addrs)))
+}
+
+// savePeers stores known good addresses.
+func (s *Server) savePeers() {
+	addrs := selectPeerAddresses(s.discovery.GoodPeers(), time.Now().Add(-s.peerMaxAge()), s.maxStoredPeers())
+	data, err := encodePeerAddresses(addrs)
+	if err != nil {
+		s.log.Warn("failed to save peer addresses", zap.Error(err))
+		return
+	}
+	if err = s.peerStore.put(data); err != nil {
+		s.log.Warn("failed to save peer addresses", zap.Error(err))
+	}
+}
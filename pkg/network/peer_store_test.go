@@ -0,0 +1,199 @@
+package network
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/fakechain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/capability"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func testPeerCaps(height uint32) capability.Capabilities {
+	return capability.Capabilities{
+		{Type: capability.TCPServer, Data: &capability.Server{Port: 10333}},
+		{Type: capability.FullNode, Data: &capability.Node{StartHeight: height}},
+	}
+}
+
+func TestPeerAddressesSerialization(t *testing.T) {
+	now := time.Unix(time.Now().Unix(), 0)
+	addrs := []AddressWithCapabilities{
+		{Address: "1.1.1.1:10333", Capabilities: testPeerCaps(1), LastSeen: now},
+		{Address: "2.2.2.2:10333", Capabilities: capability.Capabilities{}, LastSeen: now.Add(-time.Hour)},
+	}
+	data, err := encodePeerAddresses(addrs)
+	require.NoError(t, err)
+	actual, err := decodePeerAddresses(data)
+	require.NoError(t, err)
+	require.Equal(t, addrs, actual)
+
+	t.Run("empty", func(t *testing.T) {
+		data, err := encodePeerAddresses(nil)
+		require.NoError(t, err)
+		actual, err := decodePeerAddresses(data)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(actual))
+	})
+	t.Run("unknown version", func(t *testing.T) {
+		bad := append([]byte{peerStoreVersion + 1}, data[1:]...)
+		_, err := decodePeerAddresses(bad)
+		require.Error(t, err)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		_, err := decodePeerAddresses(data[:len(data)-1])
+		require.Error(t, err)
+		_, err = decodePeerAddresses(nil)
+		require.Error(t, err)
+	})
+	t.Run("trailing data", func(t *testing.T) {
+		_, err := decodePeerAddresses(append(data, 0))
+		require.Error(t, err)
+	})
+	t.Run("unknown capability", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		w.WriteB(peerStoreVersion)
+		w.WriteVarUint(2)
+		w.WriteString("3.3.3.3:10333")
+		w.WriteU64LE(uint64(now.Unix()))
+		w.WriteVarBytes([]byte{1, 0xff, 0}) // One capability of unknown type.
+		w.WriteString(addrs[0].Address)
+		w.WriteU64LE(uint64(now.Unix()))
+		cw := io.NewBufBinWriter()
+		addrs[0].Capabilities.EncodeBinary(cw.BinWriter)
+		w.WriteVarBytes(cw.Bytes())
+		require.NoError(t, w.Err)
+
+		actual, err := decodePeerAddresses(w.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, addrs[:1], actual)
+	})
+}
+
+func TestSelectPeerAddresses(t *testing.T) {
+	now := time.Now()
+	addrs := []AddressWithCapabilities{
+		{Address: "1.1.1.1:10333", LastSeen: now.Add(-3 * time.Hour)},
+		{Address: "2.2.2.2:10333", LastSeen: now},
+		{Address: "3.3.3.3:10333", LastSeen: now.Add(-48 * time.Hour)},
+		{Address: "4.4.4.4:10333", LastSeen: now.Add(-time.Hour)},
+	}
+	res := selectPeerAddresses(addrs, now.Add(-24*time.Hour), 10)
+	require.Equal(t, []AddressWithCapabilities{addrs[1], addrs[3], addrs[0]}, res)
+
+	res = selectPeerAddresses(addrs, now.Add(-24*time.Hour), 2)
+	require.Equal(t, []AddressWithCapabilities{addrs[1], addrs[3]}, res)
+}
+
+func newPeerStoreTestServer(t *testing.T, cfg ServerConfig, chain Ledger) *Server {
+	s, err := newServerFromConstructors(cfg, chain, new(fakechain.FakeStateSync), zaptest.NewLogger(t),
+		newFakeTransp, newDefaultDiscovery)
+	require.NoError(t, err)
+	return s
+}
+
+func TestServerPeerPersistence(t *testing.T) {
+	var (
+		file = filepath.Join(t.TempDir(), "peers")
+		cfg  = ServerConfig{PeerPersistence: config.PeerPersistence{Enabled: true, File: file, MaxAge: 3600, MaxAddresses: 2}}
+	)
+
+	t.Run("no file", func(t *testing.T) {
+		cfg := ServerConfig{PeerPersistence: config.PeerPersistence{Enabled: true}}
+		_, err := newServerFromConstructors(cfg, fakechain.NewFakeChain(), new(fakechain.FakeStateSync), zaptest.NewLogger(t),
+			newFakeTransp, newDefaultDiscovery)
+		require.Error(t, err)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		s := newPeerStoreTestServer(t, ServerConfig{}, fakechain.NewFakeChain())
+		require.Nil(t, s.peerStore)
+	})
+
+	s := newPeerStoreTestServer(t, cfg, fakechain.NewFakeChain())
+	require.NotNil(t, s.peerStore)
+
+	// Nothing is stored yet.
+	s.loadPeers()
+	require.Equal(t, 0, len(s.discovery.GoodPeers()))
+
+	d := s.discovery.(*DefaultDiscovery)
+	for i, a := range []string{"1.1.1.1:10333", "2.2.2.2:10333", "3.3.3.3:10333"} {
+		d.RegisterConnectedAddr(a)
+		d.RegisterGoodAddr(a, testPeerCaps(uint32(i)))
+	}
+	d.UnregisterConnectedAddr("1.1.1.1:10333")
+	d.lock.Lock()
+	d.lastSeen["1.1.1.1:10333"] = time.Now().Add(-2 * time.Hour) // Stale.
+	d.lock.Unlock()
+	d.BackFill("4.4.4.4:10333") // Not a good one.
+	s.savePeers()
+	require.FileExists(t, file)
+	require.NoFileExists(t, file+".tmp")
+
+	s2 := newPeerStoreTestServer(t, cfg, fakechain.NewFakeChain())
+	s2.discovery.RegisterBadAddr("3.3.3.3:10333") // Unconnected, so it's marked bad right away.
+	s2.loadPeers()
+	good := s2.discovery.GoodPeers()
+	require.Equal(t, 1, len(good))
+	require.Equal(t, "2.2.2.2:10333", good[0].Address)
+	require.Equal(t, testPeerCaps(1), good[0].Capabilities)
+	require.Equal(t, []string{"2.2.2.2:10333"}, s2.discovery.UnconnectedPeers())
+	require.Equal(t, []string{"3.3.3.3:10333"}, s2.discovery.BadPeers())
+
+	t.Run("bad data", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(file, []byte{0xff, 1, 2}, 0o644))
+		s := newPeerStoreTestServer(t, cfg, fakechain.NewFakeChain())
+		s.loadPeers()
+		require.Equal(t, 0, len(s.discovery.GoodPeers()))
+		require.Equal(t, 0, s.discovery.PoolCount())
+
+		// Can't be read.
+		cfg := cfg
+		cfg.PeerPersistence.File = t.TempDir()
+		s = newPeerStoreTestServer(t, cfg, fakechain.NewFakeChain())
+		s.loadPeers()
+		require.Equal(t, 0, s.discovery.PoolCount())
+	})
+}
+
+func TestDiscoveryLoadGoodPeers(t *testing.T) {
+	var (
+		ts   = &fakeTransp{}
+		d    = NewDefaultDiscovery(nil, time.Second, DiversityConfig{}, ts)
+		now  = time.Now()
+		caps = testPeerCaps(1)
+	)
+	d.RegisterBadAddr("1.1.1.1:10333")
+	d.RegisterConnectedAddr("2.2.2.2:10333")
+	d.RegisterGoodAddr("2.2.2.2:10333", caps)
+
+	d.LoadGoodPeers([]AddressWithCapabilities{
+		{Address: "1.1.1.1:10333", Capabilities: caps, LastSeen: now},
+		{Address: "2.2.2.2:10333", Capabilities: caps, LastSeen: now.Add(-time.Hour)},
+		{Address: "3.3.3.3:10333", Capabilities: caps, LastSeen: now.Add(-time.Hour)},
+	})
+	require.Equal(t, []string{"1.1.1.1:10333"}, d.BadPeers())
+	require.Equal(t, []string{"3.3.3.3:10333"}, d.UnconnectedPeers())
+
+	good := d.GoodPeers()
+	sort.Slice(good, func(i, j int) bool { return good[i].Address < good[j].Address })
+	require.Equal(t, 2, len(good))
+	require.Equal(t, "2.2.2.2:10333", good[0].Address)
+	require.False(t, good[0].LastSeen.Before(now)) // Connected.
+	require.Equal(t, "3.3.3.3:10333", good[1].Address)
+	require.True(t, good[1].LastSeen.Equal(now.Add(-time.Hour)))
+
+	// Loaded addresses are subject to the usual bookkeeping.
+	for i := 0; i < connRetries; i++ {
+		d.RegisterBadAddr("3.3.3.3:10333")
+	}
+	require.Equal(t, 0, len(d.UnconnectedPeers()))
+	require.ElementsMatch(t, []string{"1.1.1.1:10333", "3.3.3.3:10333"}, d.BadPeers())
+	require.Equal(t, 1, len(d.GoodPeers()))
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -41,6 +42,14 @@ var (
 			Namespace: "neogo",
 		},
 	)
+	poolPrefixCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Number of available node addresses per network prefix",
+			Name:      "pool_prefix_count",
+			Namespace: "neogo",
+		},
+		[]string{"prefix"},
+	)
 
 	blockQueueLength = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -49,7 +58,132 @@ var (
 			Namespace: "neogo",
 		},
 	)
+	secondsSinceLastBlock = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of seconds since the last block was accepted",
+			Name:      "seconds_since_last_block",
+			Namespace: "neogo",
+		},
+	)
+	inflightBlocks = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Number of blocks requested from the peer and not yet received",
+			Name:      "inflight_blocks",
+			Namespace: "neogo",
+		},
+		[]string{"address"},
+	)
+	peerScore = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Help:      "Connected peer quality score",
+			Name:      "peer_score",
+			Namespace: "neogo",
+		},
+		[]string{"address"},
+	)
+	p2pRawBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Uncompressed size of P2P message payloads",
+			Name:      "p2p_raw_bytes",
+			Namespace: "neogo",
+		},
+		[]string{"direction"},
+	)
+	p2pWireBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "On-the-wire (possibly compressed) size of P2P message payloads",
+			Name:      "p2p_wire_bytes",
+			Namespace: "neogo",
+		},
+		[]string{"direction"},
+	)
+	p2pConnections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of established P2P connections by transport (tcp for plain, tls for encrypted)",
+			Name:      "p2p_connections",
+			Namespace: "neogo",
+		},
+		[]string{"transport"},
+	)
+	tlsHandshakeFailures = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of failed secure P2P transport handshakes",
+			Name:      "p2p_tls_handshake_failures",
+			Namespace: "neogo",
+		},
+	)
+	peerHandshakeFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of failed P2P handshakes by reason",
+			Name:      "peer_handshake_failures_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
+	natPortMapped = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Whether P2P port is mapped on the gateway via UPnP or NAT-PMP (1) or not (0)",
+			Name:      "nat_port_mapped",
+			Namespace: "neogo",
+		},
+	)
+	p2pCmdReceivedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of bytes received in P2P messages by command",
+			Name:      "p2p_cmd_received_bytes",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+	p2pCmdSentBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of bytes sent in P2P messages by command",
+			Name:      "p2p_cmd_sent_bytes",
+			Namespace: "neogo",
+		},
+		[]string{"command"},
+	)
+	p2pSendQueueLength = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Help:      "Number of messages waiting to be sent to a peer, observed for every connected peer",
+			Name:      "p2p_send_queue_length",
+			Namespace: "neogo",
+			Buckets:   []float64{0, 1, 2, 4, 8, 16, 32, 64},
+		},
+	)
+	blockProcessTime = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Help:      "Time taken to process (verify and store) a block from the block queue",
+			Name:      "block_process_time",
+			Namespace: "neogo",
+			Buckets:   blockProcessTimeBuckets,
+		},
+	)
+	blocksRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of blocks from the block queue rejected by the chain by reason",
+			Name:      "blocks_rejected_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
+	transactionsRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Help:      "Number of transactions received from peers and rejected by reason",
+			Name:      "transactions_rejected_total",
+			Namespace: "neogo",
+		},
+		[]string{"reason"},
+	)
 	p2pCmds = make(map[CommandType]prometheus.Histogram)
+
+	// blockProcessTimeBuckets are the upper bounds (in seconds) of
+	// blockProcessTime buckets, they range from 5ms to ~10s.
+	blockProcessTimeBuckets = prometheus.ExponentialBuckets(0.005, 2, 12)
+
+	// lastBlockTime is the time (in Unix nanoseconds) the last block was
+	// accepted at, it's used for secondsSinceLastBlock updates.
+	lastBlockTime int64
 )
 
 func init() {
@@ -58,7 +192,23 @@ func init() {
 		peersConnected,
 		servAndNodeVersion,
 		poolCount,
+		poolPrefixCount,
 		blockQueueLength,
+		inflightBlocks,
+		secondsSinceLastBlock,
+		peerScore,
+		p2pRawBytes,
+		p2pWireBytes,
+		p2pConnections,
+		tlsHandshakeFailures,
+		peerHandshakeFailures,
+		natPortMapped,
+		p2pCmdReceivedBytes,
+		p2pCmdSentBytes,
+		p2pSendQueueLength,
+		blockProcessTime,
+		blocksRejected,
+		transactionsRejected,
 	)
 	for _, cmd := range []CommandType{CMDVersion, CMDVerack, CMDGetAddr,
 		CMDAddr, CMDPing, CMDPong, CMDGetHeaders, CMDHeaders, CMDGetBlocks,
@@ -75,6 +225,16 @@ func init() {
 		)
 		prometheus.MustRegister(p2pCmds[cmd])
 	}
+	// Make all the reasons visible before the first failure.
+	for _, reason := range []string{handshakeVersionMismatch, handshakeBadMagic,
+		handshakeTimeout, handshakeDuplicate, handshakeBanned} {
+		peerHandshakeFailures.WithLabelValues(reason)
+	}
+	for _, reason := range []string{rejectAlreadyKnown, rejectBadSignature, rejectInvalid} {
+		blocksRejected.WithLabelValues(reason)
+		transactionsRejected.WithLabelValues(reason)
+	}
+	transactionsRejected.WithLabelValues(rejectMempoolFull)
 }
 
 func updateNetworkSizeMetric(sz int) {
@@ -85,13 +245,110 @@ func updateBlockQueueLenMetric(bqLen int) {
 	blockQueueLength.Set(float64(bqLen))
 }
 
+func observeBlockProcessTimeMetric(t time.Duration) {
+	blockProcessTime.Observe(t.Seconds())
+}
+
+func updateInflightBlocksMetric(addr string, n int) {
+	inflightBlocks.WithLabelValues(addr).Set(float64(n))
+}
+
+func removeInflightBlocksMetric(addr string) {
+	inflightBlocks.DeleteLabelValues(addr)
+}
+
 func updatePoolCountMetric(pCount int) {
 	poolCount.Set(float64(pCount))
 }
 
+func updatePoolPrefixCountMetric(prefix string, pCount int) {
+	if pCount <= 0 {
+		poolPrefixCount.DeleteLabelValues(prefix)
+		return
+	}
+	poolPrefixCount.WithLabelValues(prefix).Set(float64(pCount))
+}
+
 func updatePeersConnectedMetric(pConnected int) {
 	peersConnected.Set(float64(pConnected))
 }
+
+func updatePeerScoreMetric(addr string, score float64) {
+	peerScore.WithLabelValues(addr).Set(score)
+}
+
+func removePeerScoreMetric(addr string) {
+	peerScore.DeleteLabelValues(addr)
+}
+
+// updatePayloadSizeMetrics accounts for the encoded (out) or decoded (in)
+// message payload of the given wire and uncompressed sizes.
+func updatePayloadSizeMetrics(out bool, wire int, raw int) {
+	var direction = "in"
+	if out {
+		direction = "out"
+	}
+	p2pWireBytes.WithLabelValues(direction).Add(float64(wire))
+	p2pRawBytes.WithLabelValues(direction).Add(float64(raw))
+}
+
+func addCmdReceivedBytesMetric(cmd CommandType, n int) {
+	p2pCmdReceivedBytes.WithLabelValues(strings.ToLower(cmd.String())).Add(float64(n))
+}
+
+func addCmdSentBytesMetric(cmd CommandType, n int) {
+	p2pCmdSentBytes.WithLabelValues(strings.ToLower(cmd.String())).Add(float64(n))
+}
+
+func observeSendQueueLenMetric(n int) {
+	p2pSendQueueLength.Observe(float64(n))
+}
+
+func addConnectionMetric(transport string) {
+	p2pConnections.WithLabelValues(transport).Inc()
+}
+
+func addTLSHandshakeFailureMetric() {
+	tlsHandshakeFailures.Inc()
+}
+
+func addHandshakeFailureMetric(reason string) {
+	peerHandshakeFailures.WithLabelValues(reason).Inc()
+}
+
+func addBlockRejectedMetric(reason string) {
+	blocksRejected.WithLabelValues(reason).Inc()
+}
+
+func addTxRejectedMetric(reason string) {
+	transactionsRejected.WithLabelValues(reason).Inc()
+}
+
+func updateNATMappedMetric(mapped bool) {
+	if mapped {
+		natPortMapped.Set(1)
+	} else {
+		natPortMapped.Set(0)
+	}
+}
+
+// resetLastBlockTime should be called when a new block is accepted, it sets
+// the last block time to the current one.
+func resetLastBlockTime() {
+	atomic.StoreInt64(&lastBlockTime, time.Now().UnixNano())
+	secondsSinceLastBlock.Set(0)
+}
+
+// updateSecondsSinceLastBlockMetric republishes the time elapsed since the last
+// block was accepted.
+func updateSecondsSinceLastBlockMetric() {
+	t := atomic.LoadInt64(&lastBlockTime)
+	if t == 0 {
+		return
+	}
+	secondsSinceLastBlock.Set(time.Since(time.Unix(0, t)).Seconds())
+}
+
 func setServerAndNodeVersions(nodeVer string, serverID string) {
 	servAndNodeVersion.WithLabelValues("Node version: ", nodeVer).Add(0)
 	servAndNodeVersion.WithLabelValues("Server id: ", serverID).Add(0)
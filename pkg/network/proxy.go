@@ -0,0 +1,106 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/proxy"
+)
+
+// onionSuffix is the top-level domain of Tor onion services.
+const onionSuffix = ".onion"
+
+// dialFunc establishes an outgoing TCP connection to the given address.
+type dialFunc func(addr string, timeout time.Duration) (net.Conn, error)
+
+// proxiedAddr is the address of the node dialed via the proxy, it's kept
+// exactly as it was dialed (host names are not resolved locally).
+type proxiedAddr string
+
+// Network implements the net.Addr interface.
+func (a proxiedAddr) Network() string {
+	return "tcp"
+}
+
+// String implements the net.Addr interface.
+func (a proxiedAddr) String() string {
+	return string(a)
+}
+
+// proxiedConn is the connection established via the proxy, its remote address
+// is the address that was dialed rather than the one of the proxy.
+type proxiedConn struct {
+	net.Conn
+	addr proxiedAddr
+}
+
+// RemoteAddr implements the net.Conn interface.
+func (c *proxiedConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+// newProxyDialer returns a dialFunc connecting via the SOCKS5 proxy with the
+// given address. Host names are passed to the proxy as is, so that they're
+// resolved by it (which is required for onion addresses and prevents DNS
+// leaks).
+func newProxyDialer(proxyAddr string) (dialFunc, error) {
+	d, err := proxy.SOCKS5("tcp", proxyAddr, nil, proxy.Direct)
+	if err != nil {
+		return nil, err
+	}
+	cd, ok := d.(proxy.ContextDialer)
+	if !ok {
+		return nil, errors.New("proxy dialer doesn't support contexts")
+	}
+	return func(addr string, timeout time.Duration) (net.Conn, error) {
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		conn, err := cd.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		return &proxiedConn{Conn: conn, addr: proxiedAddr(addr)}, nil
+	}, nil
+}
+
+// isOnion checks whether the given address is a Tor onion service address.
+func isOnion(addr string) bool {
+	return strings.HasSuffix(strings.ToLower(hostOf(addr)), onionSuffix)
+}
+
+// dial establishes an outgoing TCP connection to the given address, it's
+// made via the proxy if one is configured.
+func (s *Server) dial(addr string, timeout time.Duration) (net.Conn, error) {
+	if s.proxyDial != nil {
+		return s.proxyDial(addr, timeout)
+	}
+	if isOnion(addr) {
+		return nil, errors.New("onion addresses can't be dialed without a proxy")
+	}
+	return net.DialTimeout("tcp", addr, timeout)
+}
+
+// filterOnion returns the given list of addresses without onion ones if the
+// proxy is not configured (they can't be connected to then).
+func (s *Server) filterOnion(addrs []string) []string {
+	if s.proxyDial != nil {
+		return addrs
+	}
+	res := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		if isOnion(addr) {
+			s.log.Warn("skipping onion address, no proxy configured", zap.String("addr", addr))
+			continue
+		}
+		res = append(res, addr)
+	}
+	return res
+}
@@ -0,0 +1,232 @@
+package network
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/fakechain"
+	"github.com/nspcc-dev/neo-go/pkg/network/capability"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// startSOCKS5Server starts a SOCKS5 server accepting connect requests for
+// domain names only, it sends requested addresses to the channel returned
+// and echoes all data received after that.
+func startSOCKS5Server(t *testing.T) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	ch := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				buf := make([]byte, 512)
+				// Greeting: version, number of methods and methods.
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				if _, err := conn.Write([]byte{5, 0}); err != nil {
+					return
+				}
+				// Request: version, command, reserved, address type.
+				if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+					return
+				}
+				if buf[1] != 1 || buf[3] != 3 { // Connect to domain name.
+					_, _ = conn.Write([]byte{5, 8, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+					return
+				}
+				n := int(buf[0])
+				if _, err := io.ReadFull(conn, buf[:n+2]); err != nil {
+					return
+				}
+				ch <- net.JoinHostPort(string(buf[:n]), strconv.Itoa(int(binary.BigEndian.Uint16(buf[n:]))))
+				if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+					return
+				}
+				_, _ = io.Copy(conn, conn)
+			}(conn)
+		}
+	}()
+	return l.Addr().String(), ch
+}
+
+func TestIsOnion(t *testing.T) {
+	require.True(t, isOnion("expyuzz4wqqyqhjn.onion:20333"))
+	require.True(t, isOnion("EXPYUZZ4WQQYQHJN.ONION:20333"))
+	require.True(t, isOnion("expyuzz4wqqyqhjn.onion"))
+	require.False(t, isOnion("seed1.neo.org:10333"))
+	require.False(t, isOnion("1.2.3.4:10333"))
+	require.False(t, isOnion("onion:10333"))
+}
+
+func TestProxyDialer(t *testing.T) {
+	proxyAddr, reqs := startSOCKS5Server(t)
+	dial, err := newProxyDialer(proxyAddr)
+	require.NoError(t, err)
+
+	for _, addr := range []string{"expyuzz4wqqyqhjn.onion:20333", "seed1.neo.org:10333"} {
+		conn, err := dial(addr, time.Second)
+		require.NoError(t, err)
+		// Host name is passed to the proxy unresolved.
+		require.Equal(t, addr, <-reqs)
+		require.Equal(t, addr, conn.RemoteAddr().String())
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		require.Equal(t, "ping", string(buf))
+
+		// The dialed address is used for the peer.
+		p := NewTCPPeer(conn, nil)
+		p.version = &payload.Version{Capabilities: capability.Capabilities{{
+			Type: capability.TCPServer,
+			Data: &capability.Server{Port: 1},
+		}}}
+		require.Equal(t, addr, p.PeerAddr().String())
+		require.NoError(t, conn.Close())
+	}
+
+	t.Run("unavailable", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := l.Addr().String()
+		require.NoError(t, l.Close())
+
+		dial, err := newProxyDialer(addr)
+		require.NoError(t, err)
+		_, err = dial("expyuzz4wqqyqhjn.onion:20333", time.Second)
+		require.Error(t, err)
+	})
+}
+
+func TestServerProxy(t *testing.T) {
+	var (
+		seeds = []string{"1.2.3.4:10333", "expyuzz4wqqyqhjn.onion:20333", "seed1.neo.org:10333"}
+		used  []string
+	)
+	newServer := func(t *testing.T, cfg ServerConfig) *Server {
+		cfg.Seeds = seeds
+		s, err := newServerFromConstructors(cfg, fakechain.NewFakeChain(), new(fakechain.FakeStateSync),
+			zaptest.NewLogger(t), newFakeTransp,
+			func(addrs []string, _ time.Duration, _ DiversityConfig, _ Transporter) Discoverer {
+				used = addrs
+				return new(testDiscovery)
+			})
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("no proxy", func(t *testing.T) {
+		s := newServer(t, ServerConfig{})
+		require.Equal(t, []string{"1.2.3.4:10333", "seed1.neo.org:10333"}, used)
+		_, err := s.dial("expyuzz4wqqyqhjn.onion:20333", time.Second)
+		require.Error(t, err)
+	})
+	t.Run("proxy", func(t *testing.T) {
+		proxyAddr, reqs := startSOCKS5Server(t)
+		s := newServer(t, ServerConfig{ProxyAddress: proxyAddr})
+		require.Equal(t, seeds, used)
+		conn, err := s.dial("expyuzz4wqqyqhjn.onion:20333", time.Second)
+		require.NoError(t, err)
+		require.Equal(t, "expyuzz4wqqyqhjn.onion:20333", <-reqs)
+		require.NoError(t, conn.Close())
+	})
+}
+
+func TestServerDisableInbound(t *testing.T) {
+	s := newTestServer(t, ServerConfig{DisableInbound: true, Relay: true})
+	msg, err := s.getVersionMsg()
+	require.NoError(t, err)
+	caps := msg.Payload.(*payload.Version).Capabilities
+	require.Equal(t, 1, len(caps))
+	require.Equal(t, capability.FullNode, caps[0].Type)
+}
+
+// hostAddrDiscovery is a testDiscovery having good addresses of all kinds.
+type hostAddrDiscovery struct {
+	testDiscovery
+}
+
+func (d *hostAddrDiscovery) GoodPeers() []AddressWithCapabilities {
+	caps := capability.Capabilities{{
+		Type: capability.TCPServer,
+		Data: &capability.Server{Port: 10333},
+	}}
+	return []AddressWithCapabilities{
+		{Address: "expyuzz4wqqyqhjn.onion:10333", Capabilities: withOnionMarker(caps)},
+		{Address: "1.2.3.4:10333", Capabilities: caps},
+		{Address: "seed1.neo.org:10333", Capabilities: caps},
+	}
+}
+
+func TestGetAddrSkipsHostNames(t *testing.T) {
+	s := newTestServer(t, ServerConfig{})
+	s.discovery = new(hostAddrDiscovery)
+	p := newLocalPeer(t, s)
+	p.handshaked = 1
+
+	var addrs *payload.AddressList
+	p.messageHandler = func(t *testing.T, msg *Message) {
+		if msg.Command == CMDAddr {
+			addrs = msg.Payload.(*payload.AddressList)
+		}
+	}
+	s.testHandleMessage(t, p, CMDGetAddr, payload.NewNullPayload())
+	require.NotNil(t, addrs)
+	require.Equal(t, 1, len(addrs.Addrs))
+	addr, err := addrs.Addrs[0].GetTCPAddress()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4:10333", addr)
+}
+
+func TestOnionMarker(t *testing.T) {
+	d := NewDefaultDiscovery(nil, time.Second, DiversityConfig{}, &fakeTransp{})
+	caps := capability.Capabilities{{
+		Type: capability.TCPServer,
+		Data: &capability.Server{Port: 20333},
+	}}
+	d.RegisterGoodAddr("expyuzz4wqqyqhjn.onion:20333", caps)
+	d.RegisterGoodAddr("1.2.3.4:10333", caps)
+	require.Equal(t, 1, len(caps))
+
+	for _, a := range d.GoodPeers() {
+		if a.Address == "1.2.3.4:10333" {
+			require.Equal(t, caps, a.Capabilities)
+			continue
+		}
+		require.Equal(t, 2, len(a.Capabilities))
+		require.Equal(t, capability.OnionService, a.Capabilities[1].Type)
+		require.Equal(t, a.Capabilities, withOnionMarker(a.Capabilities))
+	}
+
+	// The marker is stored along with other capabilities.
+	data, err := encodePeerAddresses(d.GoodPeers())
+	require.NoError(t, err)
+	addrs, err := decodePeerAddresses(data)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(addrs))
+	for _, a := range addrs {
+		require.Equal(t, isOnion(a.Address), len(a.Capabilities) == 2)
+	}
+}
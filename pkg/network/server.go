@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
@@ -37,8 +38,15 @@ const (
 	defaultMaxPeers           = 100
 	defaultExtensiblePoolSize = 20
 	defaultBroadcastFactor    = 0
+	defaultShutdownTimeout    = 10 * time.Second
 	maxBlockBatch             = 200
 	peerTimeFactor            = 1000
+	// lastBlockMetricPeriod is the seconds_since_last_block metric update
+	// period.
+	lastBlockMetricPeriod = time.Second
+	// shutdownPollInterval is the interval of peer send queues and message
+	// handlers checks during shutdown.
+	shutdownPollInterval = 10 * time.Millisecond
 )
 
 var (
@@ -46,6 +54,7 @@ var (
 	errIdenticalID      = errors.New("identical node id")
 	errInvalidNetwork   = errors.New("invalid network")
 	errMaxPeers         = errors.New("max peers reached")
+	errPoorScore        = errors.New("peer score is too low")
 	errServerShutdown   = errors.New("server shutdown")
 	errInvalidInvType   = errors.New("invalid inventory type")
 )
@@ -96,10 +105,17 @@ type (
 		config config.ProtocolConfiguration
 
 		transport         Transporter
+		secureTransport   Transporter
+		proxyDial         dialFunc
+		nat               *natService
 		discovery         Discoverer
 		chain             Ledger
+		peerStore         *peerFile
+		memPoolStore      MemPoolStore
 		bQueue            *blockQueue
 		bSyncQueue        *blockQueue
+		bFetcher          *blockFetcher
+		bSyncFetcher      *blockFetcher
 		mempool           *mempool.Pool
 		notaryRequestPool *mempool.Pool
 		extensiblePool    *extpool.Pool
@@ -117,9 +133,12 @@ type (
 
 		lock  sync.RWMutex
 		peers map[Peer]bool
+		// evicted contains peers being disconnected because of MaxPeers
+		// limit, they're not counted when the limit is checked.
+		evicted map[Peer]bool
+		// scores tracks peer quality.
+		scores *peerScores
 
-		// lastRequestedBlock contains a height of the last requested block.
-		lastRequestedBlock atomic.Uint32
 		// lastRequestedHeader contains a height of the last requested header.
 		lastRequestedHeader atomic.Uint32
 
@@ -127,6 +146,11 @@ type (
 		unregister chan peerDrop
 		quit       chan struct{}
 		relayFin   chan struct{}
+		// stopping is set when graceful shutdown begins, no new peers are
+		// accepted after that.
+		stopping atomic.Bool
+		// handlers is the number of message handlers being executed.
+		handlers atomic.Int32
 
 		transactions chan *transaction.Transaction
 
@@ -158,7 +182,7 @@ func NewServer(config ServerConfig, chain Ledger, stSync StateSync, log *zap.Log
 
 func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSync, log *zap.Logger,
 	newTransport func(*Server) Transporter,
-	newDiscovery func([]string, time.Duration, Transporter) Discoverer,
+	newDiscovery func([]string, time.Duration, DiversityConfig, Transporter) Discoverer,
 ) (*Server, error) {
 	if log == nil {
 		return nil, errors.New("logger is a required parameter")
@@ -181,6 +205,8 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 		unregister:     make(chan peerDrop),
 		txInMap:        make(map[util.Uint256]struct{}),
 		peers:          make(map[Peer]bool),
+		evicted:        make(map[Peer]bool),
+		scores:         newPeerScores(config.ScoreHalfLife),
 		syncReached:    atomic.NewBool(false),
 		mempool:        chain.GetMemPool(),
 		extensiblePool: extpool.New(chain, config.ExtensiblePoolSize),
@@ -205,6 +231,8 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 	})
 
 	s.bSyncQueue = newBlockQueue(maxBlockBatch, s.stateSync, log, nil)
+	s.bFetcher = newBlockFetcher(defaultBlockChunkTimeout)
+	s.bSyncFetcher = newBlockFetcher(defaultBlockChunkTimeout)
 
 	if s.MinPeers < 0 {
 		s.log.Info("bad MinPeers configured, using the default value",
@@ -234,11 +262,74 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 		s.BroadcastFactor = defaultBroadcastFactor
 	}
 
+	if s.ShutdownTimeout <= 0 {
+		s.ShutdownTimeout = defaultShutdownTimeout
+	}
+
+	if s.MaxPeersPerPrefix < 0 {
+		s.log.Info("bad MaxPeersPerPrefix configured, disabling the limit",
+			zap.Int("configured", s.MaxPeersPerPrefix))
+		s.MaxPeersPerPrefix = 0
+	}
+	trusted, err := ParseTrustedNets(s.TrustedPeers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted peers list: %w", err)
+	}
+	var asnMap *ASNMap
+	if s.ASNMapFile != "" {
+		asnMap, err = LoadASNMap(s.ASNMapFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load ASN map: %w", err)
+		}
+	}
+
+	if s.ProxyAddress != "" {
+		s.proxyDial, err = newProxyDialer(s.ProxyAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address: %w", err)
+		}
+	}
 	s.transport = newTransport(s)
+	var (
+		seeds          = s.filterOnion(s.Seeds)
+		discoTransport = s.transport
+	)
+	if s.SecureP2P.Enabled {
+		tlsCfg, err := NewSecureTLSConfig(s.SecureP2P)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure secure transport: %w", err)
+		}
+		s.secureTransport = NewTLSTransport(s, net.JoinHostPort(s.SecureP2P.Address, strconv.Itoa(int(s.SecureP2P.Port))), tlsCfg, s.log)
+		discoTransport = newRoutedTransport(s.transport, s.secureTransport, s.SecureP2P.Peers)
+		// Trusted nodes are dialed just like seeds.
+		seeds = append(append([]string{}, seeds...), s.SecureP2P.Peers...)
+	}
+	if s.NAT.Enabled && !s.DisableInbound {
+		s.nat = newNATService(s.NAT, s.log)
+	}
+	if s.PeerPersistence.Enabled {
+		if s.PeerPersistence.File == "" {
+			return nil, errors.New("peer persistence is enabled, but no file is specified")
+		}
+		s.peerStore = &peerFile{path: s.PeerPersistence.File}
+	}
+	if s.MemPoolPersistence.Enabled {
+		ms, ok := chain.(MemPoolStore)
+		if ok {
+			s.memPoolStore = ms
+		} else {
+			s.log.Warn("memory pool persistence is enabled, but the ledger doesn't support it")
+		}
+	}
 	s.discovery = newDiscovery(
-		s.Seeds,
+		seeds,
 		s.DialTimeout,
-		s.transport,
+		DiversityConfig{
+			MaxPerPrefix: s.MaxPeersPerPrefix,
+			Trusted:      trusted,
+			ASN:          asnMap,
+		},
+		discoTransport,
 	)
 
 	return s, nil
@@ -258,6 +349,12 @@ func (s *Server) Start(errChan chan error) {
 
 	s.tryStartServices()
 	s.initStaleMemPools()
+	if s.peerStore != nil {
+		s.loadPeers()
+	}
+	if s.memPoolStore != nil {
+		s.loadMemPool()
+	}
 
 	var txThreads = optimalNumOfThreads()
 	for i := 0; i < txThreads; i++ {
@@ -267,7 +364,15 @@ func (s *Server) Start(errChan chan error) {
 	go s.relayBlocksLoop()
 	go s.bQueue.run()
 	go s.bSyncQueue.run()
-	go s.transport.Accept()
+	if !s.DisableInbound {
+		go s.transport.Accept()
+		if s.secureTransport != nil {
+			go s.secureTransport.Accept()
+		}
+	}
+	if s.nat != nil {
+		s.nat.start(s.listenPort, s.AnnouncedPort)
+	}
 	setServerAndNodeVersions(s.UserAgent, strconv.FormatUint(uint64(s.id), 10))
 	s.run()
 }
@@ -276,12 +381,27 @@ func (s *Server) Start(errChan chan error) {
 // once stopped the same intance of the Server can't be started again by calling Start.
 func (s *Server) Shutdown() {
 	s.log.Info("shutting down server", zap.Int("peers", s.PeerCount()))
+	var deadline = time.Now().Add(s.ShutdownTimeout)
+
+	s.stopping.Store(true)
 	s.transport.Close()
-	for _, p := range s.getPeers(nil) {
-		p.Disconnect(errServerShutdown)
+	if s.secureTransport != nil {
+		s.secureTransport.Close()
+	}
+	if s.peerStore != nil {
+		s.savePeers()
+	}
+	// Blocks that are already downloaded are added to the chain before
+	// disconnecting, so that they're persisted on chain closing.
+	s.bQueue.drain(time.Until(deadline))
+	s.bSyncQueue.drain(time.Until(deadline))
+	s.disconnectPeers(deadline)
+	s.waitHandlers(deadline)
+	if s.memPoolStore != nil {
+		// Pools are stable at this point, no new transactions or blocks
+		// are received.
+		s.saveMemPool()
 	}
-	s.bQueue.discard()
-	s.bSyncQueue.discard()
 	s.serviceLock.RLock()
 	for _, svc := range s.services {
 		svc.Shutdown()
@@ -291,9 +411,49 @@ func (s *Server) Shutdown() {
 		s.notaryRequestPool.StopSubscriptions()
 	}
 	close(s.quit)
+	if s.nat != nil {
+		s.nat.shutdown()
+	}
 	<-s.relayFin
 }
 
+// disconnectPeers disconnects all peers. N3 protocol has no disconnection
+// message, so peers are notified by connection closing, but this is done
+// after messages queued for them are sent (or the deadline is reached).
+func (s *Server) disconnectPeers(deadline time.Time) {
+	peers := s.getPeers(nil)
+	for len(peers) != 0 && time.Now().Before(deadline) {
+		var pending = peers[:0]
+		for _, p := range peers {
+			if p.Traffic().SendQueueLen != 0 {
+				pending = append(pending, p)
+				continue
+			}
+			p.Disconnect(errServerShutdown)
+		}
+		peers = pending
+		if len(peers) != 0 {
+			time.Sleep(shutdownPollInterval)
+		}
+	}
+	for _, p := range peers {
+		p.Disconnect(errServerShutdown)
+	}
+}
+
+// waitHandlers waits for in-flight message handlers to finish, but no longer
+// than until the deadline.
+func (s *Server) waitHandlers(deadline time.Time) {
+	for s.handlers.Load() != 0 {
+		if !time.Now().Before(deadline) {
+			s.log.Warn("message handlers are still running after shutdown timeout",
+				zap.Int32("count", s.handlers.Load()))
+			return
+		}
+		time.Sleep(shutdownPollInterval)
+	}
+}
+
 // AddService allows to add a service to be started/stopped by Server.
 func (s *Server) AddService(svc Service) {
 	s.serviceLock.Lock()
@@ -391,6 +551,29 @@ func (s *Server) ConnectedPeers() []string {
 	return peers
 }
 
+// PeerScores returns scores of currently connected peers (by their
+// addresses).
+func (s *Server) PeerScores() map[string]PeerScore {
+	peers := s.getPeers(nil)
+	res := make(map[string]PeerScore, len(peers))
+	for _, p := range peers {
+		addr := p.PeerAddr().String()
+		res[addr] = s.scores.get(addr)
+	}
+	return res
+}
+
+// PeerTraffic returns traffic statistics of currently connected peers (by
+// their addresses).
+func (s *Server) PeerTraffic() map[string]PeerTraffic {
+	peers := s.getPeers(nil)
+	res := make(map[string]PeerTraffic, len(peers))
+	for _, p := range peers {
+		res[p.PeerAddr().String()] = p.Traffic()
+	}
+	return res
+}
+
 // run is a goroutine that starts another goroutine to manage protocol specifics
 // while itself dealing with peers management (handling connects/disconnects).
 func (s *Server) run() {
@@ -398,8 +581,15 @@ func (s *Server) run() {
 		peerCheckTime    = s.TimePerBlock * peerTimeFactor
 		peerCheckTimeout bool
 		timer            = time.NewTimer(peerCheckTime)
+		saveTicker       *time.Ticker
+		saveC            <-chan time.Time
 	)
 	defer timer.Stop()
+	if s.peerStore != nil {
+		saveTicker = time.NewTicker(peerSaveInterval)
+		saveC = saveTicker.C
+		defer saveTicker.Stop()
+	}
 	go s.runProto()
 	for loopCnt := 0; ; loopCnt++ {
 		var (
@@ -408,12 +598,14 @@ func (s *Server) run() {
 			optimalN = s.discovery.GetFanOut() * 2
 			// Real number of peers.
 			peerN = s.PeerCount()
+			// No new connections are needed during shutdown.
+			stopping = s.stopping.Load()
 		)
 
-		if peerN < s.MinPeers {
+		if !stopping && peerN < s.MinPeers {
 			// Starting up or going below the minimum -> quickly get many new peers.
 			s.discovery.RequestRemote(s.AttemptConnPeers)
-		} else if s.MinPeers > 0 && loopCnt%s.MinPeers == 0 && optimalN > peerN && optimalN < s.MaxPeers && optimalN < netSize {
+		} else if !stopping && s.MinPeers > 0 && loopCnt%s.MinPeers == 0 && optimalN > peerN && optimalN < s.MaxPeers && optimalN < netSize {
 			// Having some number of peers, but probably can get some more, the network is big.
 			// It also allows to start picking up new peers proactively, before we suddenly have <s.MinPeers of them.
 			var connN = s.AttemptConnPeers
@@ -423,7 +615,7 @@ func (s *Server) run() {
 			s.discovery.RequestRemote(connN)
 		}
 
-		if peerCheckTimeout || s.discovery.PoolCount() < s.AttemptConnPeers {
+		if !stopping && (peerCheckTimeout || s.discovery.PoolCount() < s.AttemptConnPeers) {
 			s.broadcastHPMessage(NewMessage(CMDGetAddr, payload.NewNullPayload()))
 			peerCheckTimeout = false
 		}
@@ -433,21 +625,28 @@ func (s *Server) run() {
 		case <-timer.C:
 			peerCheckTimeout = true
 			timer.Reset(peerCheckTime)
+		case <-saveC:
+			s.savePeers()
 		case p := <-s.register:
 			s.lock.Lock()
 			s.peers[p] = true
 			s.lock.Unlock()
 			peerCount := s.PeerCount()
 			s.log.Info("new peer connected", zap.Stringer("addr", p.RemoteAddr()), zap.Int("peerCount", peerCount))
-			if peerCount > s.MaxPeers {
-				s.lock.RLock()
-				// Pick a random peer and drop connection to it.
-				for peer := range s.peers {
-					// It will send us unregister signal.
-					go peer.Disconnect(errMaxPeers)
-					break
-				}
-				s.lock.RUnlock()
+			if s.stopping.Load() {
+				// It will send us unregister signal.
+				go p.Disconnect(errServerShutdown)
+			} else if peerCount-s.HandshakedPeersCount() > s.MaxPeers {
+				// Peer scores are only known after the handshake (they're
+				// tracked by announced addresses), so score checks are
+				// performed then (see checkHandshakedPeer). Dropping some
+				// peer here would evict a random (possibly the best) one
+				// before the newcomer's score is known, so only the number
+				// of connections waiting for the handshake is limited. It
+				// makes the total number of connections 2*MaxPeers at most,
+				// handshake timeout limits the time it can last.
+				// It will send us unregister signal.
+				go p.Disconnect(errMaxPeers)
 			}
 			updatePeersConnectedMetric(s.PeerCount())
 
@@ -455,12 +654,25 @@ func (s *Server) run() {
 			s.lock.Lock()
 			if s.peers[drop.peer] {
 				delete(s.peers, drop.peer)
+				delete(s.evicted, drop.peer)
 				s.lock.Unlock()
 				s.log.Warn("peer disconnected",
 					zap.Stringer("addr", drop.peer.RemoteAddr()),
 					zap.Error(drop.reason),
 					zap.Int("peerCount", s.PeerCount()))
 				addr := drop.peer.PeerAddr().String()
+				if !drop.peer.Handshaked() {
+					if reason := handshakeFailureReason(drop.reason); reason != "" {
+						addHandshakeFailureMetric(reason)
+					}
+				}
+				s.bFetcher.peerDropped(drop.peer)
+				s.bSyncFetcher.peerDropped(drop.peer)
+				if errors.Is(drop.reason, errPingPong) {
+					s.scores.timeout(addr)
+				}
+				removePeerScoreMetric(addr)
+				s.pruneScores()
 				if errors.Is(drop.reason, errIdenticalID) {
 					s.discovery.RegisterBadAddr(addr)
 				} else if errors.Is(drop.reason, errAlreadyConnected) {
@@ -508,11 +720,74 @@ func (s *Server) runProto() {
 			if s.chain.BlockHeight() == prevHeight {
 				s.broadcastMessage(NewMessage(CMDPing, payload.NewPing(s.chain.BlockHeight(), s.id)))
 			}
+			for addr, score := range s.PeerScores() {
+				updatePeerScoreMetric(addr, score.Score)
+			}
+			s.dropPoorPeers()
+			for _, p := range s.getPeers(nil) {
+				observeSendQueueLenMetric(p.Traffic().SendQueueLen)
+			}
 			pingTimer.Reset(s.PingInterval)
 		}
 	}
 }
 
+// checkHandshakedPeer checks the peer that has just completed the handshake
+// against DisconnectScore and MaxPeers limits, peers are identified by their
+// announced addresses at this point. When the limit is exceeded the worst
+// peer is disconnected, the new one is dropped if it's not better than
+// others. It returns false if the new peer is being disconnected.
+func (s *Server) checkHandshakedPeer(p Peer) bool {
+	if s.DisconnectScore < 0 && s.scores.get(p.PeerAddr().String()).Score < s.DisconnectScore {
+		// The peer is handshaked at this point, so the failure can't be
+		// accounted for when it's dropped.
+		addHandshakeFailureMetric(handshakeBanned)
+		// It will send us unregister signal.
+		go p.Disconnect(errPoorScore)
+		return false
+	}
+	var (
+		n          int
+		worst      Peer
+		worstScore float64
+	)
+	s.lock.Lock()
+	for peer := range s.peers {
+		if !peer.Handshaked() || s.evicted[peer] {
+			continue
+		}
+		n++
+		score := s.scores.get(peer.PeerAddr().String()).Score
+		if worst == nil || score < worstScore || (score == worstScore && peer == p) {
+			worst, worstScore = peer, score
+		}
+	}
+	if n > s.MaxPeers {
+		s.evicted[worst] = true
+	} else {
+		worst = nil
+	}
+	s.lock.Unlock()
+	if worst != nil {
+		// It will send us unregister signal.
+		go worst.Disconnect(errMaxPeers)
+	}
+	return worst != p
+}
+
+// dropPoorPeers disconnects connected peers with score below DisconnectScore.
+func (s *Server) dropPoorPeers() {
+	if s.DisconnectScore >= 0 {
+		return
+	}
+	for _, p := range s.getPeers(func(p Peer) bool { return p.Handshaked() }) {
+		if s.scores.get(p.PeerAddr().String()).Score < s.DisconnectScore {
+			// It will send us unregister signal.
+			go p.Disconnect(errPoorScore)
+		}
+	}
+}
+
 func (s *Server) tryStartServices() {
 	if s.syncReached.Load() {
 		return
@@ -598,18 +873,19 @@ func (s *Server) HandshakedPeersCount() int {
 
 // getVersionMsg returns the current version message.
 func (s *Server) getVersionMsg() (*Message, error) {
-	port, err := s.Port()
-	if err != nil {
-		return nil, err
-	}
-
-	capabilities := []capability.Capability{
-		{
+	var capabilities []capability.Capability
+	// There is no port to announce if we're not listening.
+	if !s.DisableInbound {
+		port, err := s.Port()
+		if err != nil {
+			return nil, err
+		}
+		capabilities = append(capabilities, capability.Capability{
 			Type: capability.TCPServer,
 			Data: &capability.Server{
 				Port: port,
 			},
-		},
+		})
 	}
 	if s.Relay {
 		capabilities = append(capabilities, capability.Capability{
@@ -619,6 +895,14 @@ func (s *Server) getVersionMsg() (*Message, error) {
 			},
 		})
 	}
+	if s.Compression {
+		capabilities = append(capabilities, capability.Capability{
+			Type: capability.PayloadCompression,
+			Data: &capability.Compression{
+				Algorithms: capability.Deflate,
+			},
+		})
+	}
 	payload := payload.NewVersion(
 		s.Net,
 		s.id,
@@ -628,6 +912,25 @@ func (s *Server) getVersionMsg() (*Message, error) {
 	return NewMessage(CMDVersion, payload), nil
 }
 
+// useDeflate returns true if DEFLATE payload compression can be used for
+// messages sent to the given peer, that is when it's enabled for this node
+// and the peer advertises its support in the version message.
+func (s *Server) useDeflate(p Peer) bool {
+	if !s.Compression {
+		return false
+	}
+	v := p.Version()
+	if v == nil {
+		return false
+	}
+	for _, c := range v.Capabilities {
+		if c.Type == capability.PayloadCompression {
+			return c.Data.(*capability.Compression).Algorithms&capability.Deflate != 0
+		}
+	}
+	return false
+}
+
 // IsInSync answers the question of whether the server is in sync with the
 // network or not (at least how the server itself sees it). The server operates
 // with the data that it has, the number of peers (that has to be more than
@@ -702,12 +1005,57 @@ func (s *Server) handleVersionCmd(p Peer, version *payload.Version) error {
 	return p.SendVersionAck(NewMessage(CMDVerack, payload.NewNullPayload()))
 }
 
+// Handshake failure reasons used for the peer_handshake_failures_total metric.
+const (
+	handshakeVersionMismatch = "version_mismatch"
+	handshakeBadMagic        = "bad_magic"
+	handshakeTimeout         = "timeout"
+	handshakeDuplicate       = "duplicate"
+	handshakeBanned          = "banned"
+)
+
+// handshakeFailureReason returns the metric reason for the error the peer
+// was dropped with before completing the handshake or an empty string if
+// the error is not related to the handshake itself (like server shutdown).
+func handshakeFailureReason(err error) string {
+	var ne net.Error
+	switch {
+	case errors.Is(err, errInvalidNetwork):
+		return handshakeBadMagic
+	case errors.Is(err, errIdenticalID), errors.Is(err, errAlreadyConnected):
+		return handshakeDuplicate
+	case errors.Is(err, errPoorScore):
+		return handshakeBanned
+	case errors.Is(err, errInvalidHandshake):
+		return handshakeVersionMismatch
+	case errors.As(err, &ne) && ne.Timeout():
+		return handshakeTimeout
+	}
+	return ""
+}
+
 // handleBlockCmd processes the block received from its peer.
 func (s *Server) handleBlockCmd(p Peer, block *block.Block) error {
+	var (
+		bq Blockqueuer = s.chain
+		q              = s.bQueue
+		f              = s.bFetcher
+	)
 	if s.stateSync.IsActive() {
-		return s.bSyncQueue.putBlock(block)
+		bq, q, f = s.stateSync, s.bSyncQueue, s.bSyncFetcher
+	}
+	if block.Index > bq.BlockHeight() {
+		s.scores.usefulBlock(p.PeerAddr().String())
+	}
+	err := q.putBlock(block)
+	if err != nil {
+		return err
+	}
+	if f.received(p, block.Index) {
+		// The peer is done with its chunk, give it the next one right away.
+		return s.requestBlocksOrHeaders(p)
 	}
-	return s.bQueue.putBlock(block)
+	return nil
 }
 
 // handlePing processes a ping request.
@@ -732,18 +1080,27 @@ func (s *Server) requestBlocksOrHeaders(p Peer) error {
 	}
 	var (
 		bq              Blockqueuer = s.chain
+		f                           = s.bFetcher
 		requestMPTNodes bool
 	)
 	if s.stateSync.IsActive() {
-		bq = s.stateSync
+		bq, f = s.stateSync, s.bSyncFetcher
 		requestMPTNodes = s.stateSync.NeedMPTNodes()
 	}
 	if bq.BlockHeight() >= p.LastBlockIndex() {
 		return nil
 	}
-	err := s.requestBlocks(bq, p)
-	if err != nil {
-		return err
+	// Chunks are handed out to all idle peers able to serve them in the
+	// order of their scores, so that the most urgent (lowest) ones are
+	// fetched from the best peers rather than from the one that has
+	// triggered the request. Errors of other peers are handled by their
+	// own routines.
+	height := bq.BlockHeight()
+	for _, peer := range s.peersByScore(p, func(peer Peer) bool { return peer.LastBlockIndex() > height }) {
+		err := s.requestBlocks(bq, f, peer)
+		if err != nil && peer == p {
+			return err
+		}
 	}
 	if requestMPTNodes {
 		return s.requestMPTNodes(p, s.stateSync.GetUnknownMPTNodesBatch(payload.MaxMPTHashesCount))
@@ -751,6 +1108,32 @@ func (s *Server) requestBlocksOrHeaders(p Peer) error {
 	return nil
 }
 
+// peersByScore returns the given peer along with other handshaked peers
+// satisfying isOK sorted by their scores (the best one first). Peers scoring
+// below DisconnectScore are skipped, they're to be dropped anyway.
+func (s *Server) peersByScore(p Peer, isOK func(Peer) bool) []Peer {
+	peers := s.getPeers(func(peer Peer) bool {
+		return peer != p && peer.Handshaked() && isOK(peer)
+	})
+	peers = append(peers, p)
+	scores := make(map[Peer]float64, len(peers))
+	var n int
+	for _, peer := range peers {
+		score := s.scores.get(peer.PeerAddr().String()).Score
+		if s.DisconnectScore < 0 && score < s.DisconnectScore {
+			continue
+		}
+		scores[peer] = score
+		peers[n] = peer
+		n++
+	}
+	peers = peers[:n]
+	sort.SliceStable(peers, func(i, j int) bool {
+		return scores[peers[i]] > scores[peers[j]]
+	})
+	return peers
+}
+
 // requestHeaders sends a CMDGetHeaders message to the peer to sync up in headers.
 func (s *Server) requestHeaders(p Peer) error {
 	pl := getRequestBlocksPayload(p, s.chain.HeaderHeight(), &s.lastRequestedHeader)
@@ -763,6 +1146,7 @@ func (s *Server) handlePong(p Peer, pong *payload.Ping) error {
 	if err != nil {
 		return err
 	}
+	s.scores.pongReceived(p.PeerAddr().String())
 	return s.requestBlocksOrHeaders(p)
 }
 
@@ -828,6 +1212,7 @@ func (s *Server) handleGetDataCmd(p Peer, inv *payload.Inventory) error {
 		notFound []util.Uint256
 		reply    = io.NewBufBinWriter()
 		send     = p.EnqueueP2PPacket
+		deflate  = s.useDeflate(p)
 	)
 	if inv.Type == payload.ExtensibleType {
 		send = p.EnqueueHPPacket
@@ -862,14 +1247,14 @@ func (s *Server) handleGetDataCmd(p Peer, inv *payload.Inventory) error {
 			}
 		}
 		if msg != nil {
-			err = addMessageToPacket(reply, msg, send)
+			err = addMessageToPacket(reply, msg, send, deflate)
 			if err != nil {
 				return err
 			}
 		}
 	}
 	if len(notFound) != 0 {
-		err = addMessageToPacket(reply, NewMessage(CMDNotFound, payload.NewInventory(inv.Type, notFound)), send)
+		err = addMessageToPacket(reply, NewMessage(CMDNotFound, payload.NewInventory(inv.Type, notFound)), send, deflate)
 		if err != nil {
 			return err
 		}
@@ -881,9 +1266,10 @@ func (s *Server) handleGetDataCmd(p Peer, inv *payload.Inventory) error {
 }
 
 // addMessageToPacket serializes given message into the given buffer and sends whole
-// batch if it exceeds MaxSize/2 memory limit (to prevent DoS).
-func addMessageToPacket(batch *io.BufBinWriter, msg *Message, send func([]byte) error) error {
-	err := msg.Encode(batch.BinWriter)
+// batch if it exceeds MaxSize/2 memory limit (to prevent DoS). The message payload
+// is compressed with DEFLATE if deflate is true.
+func addMessageToPacket(batch *io.BufBinWriter, msg *Message, send func([]byte) error, deflate bool) error {
+	err := msg.encode(batch.BinWriter, deflate)
 	if err != nil {
 		return err
 	}
@@ -986,7 +1372,10 @@ func (s *Server) handleGetBlocksCmd(p Peer, gb *payload.GetBlocks) error {
 
 // handleGetBlockByIndexCmd processes the getblockbyindex request.
 func (s *Server) handleGetBlockByIndexCmd(p Peer, gbd *payload.GetBlockByIndex) error {
-	var reply = io.NewBufBinWriter()
+	var (
+		reply   = io.NewBufBinWriter()
+		deflate = s.useDeflate(p)
+	)
 	count := gbd.Count
 	if gbd.Count < 0 || gbd.Count > payload.MaxHashesCount {
 		count = payload.MaxHashesCount
@@ -1000,7 +1389,7 @@ func (s *Server) handleGetBlockByIndexCmd(p Peer, gbd *payload.GetBlockByIndex)
 		if err != nil {
 			break
 		}
-		err = addMessageToPacket(reply, NewMessage(CMDBlock, b), p.EnqueueP2PPacket)
+		err = addMessageToPacket(reply, NewMessage(CMDBlock, b), p.EnqueueP2PPacket, deflate)
 		if err != nil {
 			return err
 		}
@@ -1109,8 +1498,10 @@ txloop:
 			if txCallback != nil && s.txCbEnabled.Load() {
 				txCallback(tx)
 			}
-			if s.verifyAndPoolTX(tx) == nil {
+			if err := s.verifyAndPoolTX(tx); err == nil {
 				s.broadcastTX(tx, nil)
+			} else {
+				addTxRejectedMetric(rejectReason(err))
 			}
 			s.txInLock.Lock()
 			delete(s.txInMap, tx.Hash())
@@ -1198,40 +1589,36 @@ func (s *Server) handleAddrCmd(p Peer, addrs *payload.AddressList) error {
 // handleGetAddrCmd sends to the peer some good addresses that we know of.
 func (s *Server) handleGetAddrCmd(p Peer) error {
 	addrs := s.discovery.GoodPeers()
-	if len(addrs) > payload.MaxAddrsCount {
-		addrs = addrs[:payload.MaxAddrsCount]
-	}
-	alist := payload.NewAddressList(len(addrs))
+	alist := payload.NewAddressList(0)
 	ts := time.Now()
-	for i, addr := range addrs {
+	for _, addr := range addrs {
+		if len(alist.Addrs) == payload.MaxAddrsCount {
+			break
+		}
+		// Host names (like onion ones known when the proxy is used) can't
+		// be sent and they're not resolved locally.
+		if net.ParseIP(hostOf(addr.Address)) == nil {
+			continue
+		}
 		// we know it's a good address, so it can't fail
 		netaddr, _ := net.ResolveTCPAddr("tcp", addr.Address)
-		alist.Addrs[i] = payload.NewAddressAndTime(netaddr, ts, addr.Capabilities)
+		alist.Addrs = append(alist.Addrs, payload.NewAddressAndTime(netaddr, ts, addr.Capabilities))
 	}
 	return p.EnqueueP2PMessage(NewMessage(CMDAddr, alist))
 }
 
-// requestBlocks sends a CMDGetBlockByIndex message to the peer
-// to sync up in blocks. A maximum of maxBlockBatch will be
-// sent at once. There are two things we need to take care of:
-//  1. If possible, blocks should be fetched in parallel.
-//     height..+500 to one peer, height+500..+1000 to another etc.
-//  2. Every block must eventually be fetched even if the peer sends no answer.
-//
-// Thus, the following algorithm is used:
-// 1. Block range is divided into chunks of payload.MaxHashesCount.
-// 2. Send requests for chunk in increasing order.
-// 3. After all requests have been sent, request random height.
-func (s *Server) requestBlocks(bq Blockqueuer, p Peer) error {
-	h := bq.BlockHeight()
-	pl := getRequestBlocksPayload(p, h, &s.lastRequestedBlock)
-	lq := s.bQueue.lastQueued()
-	if lq > pl.IndexStart {
-		c := int16(h + blockCacheSize - lq)
-		if c < payload.MaxHashesCount {
-			pl.Count = c
-		}
-		pl.IndexStart = lq + 1
+// requestBlocks sends a CMDGetBlockByIndex message to the peer to sync up in
+// blocks. Missing blocks are fetched in parallel: the range is divided into
+// chunks of payload.MaxHashesCount, each peer gets its own chunk
+// (height..+500 to one peer, height+500..+1000 to another etc.) and the next
+// one as soon as it delivers it. Chunks not delivered in time are reassigned
+// to other peers, so that every block is eventually fetched even if some peer
+// sends no answer. Nothing is sent if the peer is busy with its chunk or
+// there is nothing it can provide.
+func (s *Server) requestBlocks(bq Blockqueuer, f *blockFetcher, p Peer) error {
+	pl := f.next(p, bq.BlockHeight())
+	if pl == nil {
+		return nil
 	}
 	return p.EnqueueP2PMessage(NewMessage(CMDGetBlockByIndex, pl))
 }
@@ -1239,7 +1626,7 @@ func (s *Server) requestBlocks(bq Blockqueuer, p Peer) error {
 func getRequestBlocksPayload(p Peer, currHeight uint32, lastRequestedHeight *atomic.Uint32) *payload.GetBlockByIndex {
 	var peerHeight = p.LastBlockIndex()
 	var needHeight uint32
-	// lastRequestedBlock can only be increased.
+	// lastRequestedHeight can only be increased.
 	for {
 		old := lastRequestedHeight.Load()
 		if old <= currHeight {
@@ -1270,6 +1657,9 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 		zap.Stringer("addr", peer.RemoteAddr()),
 		zap.String("type", msg.Command.String()))
 
+	s.handlers.Inc()
+	defer s.handlers.Dec()
+
 	start := time.Now()
 	defer func() { addCmdTimeMetric(msg.Command, time.Since(start)) }()
 
@@ -1344,12 +1734,15 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 			if err != nil {
 				return err
 			}
+			if !s.checkHandshakedPeer(peer) {
+				return nil
+			}
 			go peer.StartProtocol()
 
 			s.tryInitStateSync()
 			s.tryStartServices()
 		default:
-			return fmt.Errorf("received '%s' during handshake", msg.Command.String())
+			return fmt.Errorf("%w: received '%s' during handshake", errInvalidHandshake, msg.Command.String())
 		}
 	}
 	return nil
@@ -1358,6 +1751,7 @@ func (s *Server) handleMessage(peer Peer, msg *Message) error {
 func (s *Server) tryInitStateSync() {
 	if !s.stateSync.IsActive() {
 		s.bSyncQueue.discard()
+		s.bSyncFetcher.reset()
 		return
 	}
 
@@ -1398,6 +1792,7 @@ func (s *Server) tryInitStateSync() {
 		// module can be inactive after init (i.e. full state is collected and ordinary block processing is needed)
 		if !s.stateSync.IsActive() {
 			s.bSyncQueue.discard()
+			s.bSyncFetcher.reset()
 		}
 	}
 }
@@ -1438,6 +1833,16 @@ func (s *Server) RequestTx(hashes ...util.Uint256) {
 	}
 }
 
+// pruneScores removes stale scores of disconnected peers.
+func (s *Server) pruneScores() {
+	peers := s.getPeers(nil)
+	connected := make(map[string]bool, len(peers))
+	for _, p := range peers {
+		connected[p.PeerAddr().String()] = true
+	}
+	s.scores.prune(connected)
+}
+
 // StopTxFlow makes the server not call previously specified consensus transaction callback.
 func (s *Server) StopTxFlow() {
 	s.txCbEnabled.Store(false)
@@ -1475,6 +1880,7 @@ func (s *Server) iteratePeersWithSendMsg(msg *Message, send func(Peer, context.C
 			}
 			if msg.Command == CMDPing {
 				p.SetPingTimer()
+				s.scores.pingSent(p.PeerAddr().String())
 			}
 			replies <- send(p, ctx, pkt)
 		}(peer, ctx, pkt)
@@ -1511,13 +1917,19 @@ func (s *Server) broadcastHPMessage(msg *Message) {
 func (s *Server) relayBlocksLoop() {
 	ch := make(chan *block.Block, 2) // Some buffering to smooth out possible egressing delays.
 	s.chain.SubscribeForBlocks(ch)
+	// Node start is the best approximation we have for the first block.
+	resetLastBlockTime()
+	metricTimer := time.NewTicker(lastBlockMetricPeriod)
 mainloop:
 	for {
 		select {
 		case <-s.quit:
 			s.chain.UnsubscribeFromBlocks(ch)
 			break mainloop
+		case <-metricTimer.C:
+			updateSecondsSinceLastBlockMetric()
 		case b := <-ch:
+			resetLastBlockTime()
 			msg := NewMessage(CMDInv, payload.NewInventory(payload.BlockType, []util.Uint256{b.Hash()}))
 			// Filter out nodes that are more current (avoid spamming the network
 			// during initial sync).
@@ -1535,6 +1947,7 @@ drainBlocksLoop:
 			break drainBlocksLoop
 		}
 	}
+	metricTimer.Stop()
 	close(ch)
 	close(s.relayFin)
 }
@@ -1544,6 +1957,30 @@ func (s *Server) verifyAndPoolTX(t *transaction.Transaction) error {
 	return s.chain.PoolTx(t)
 }
 
+// Block and transaction rejection reasons used for the blocks_rejected_total
+// and transactions_rejected_total metrics.
+const (
+	rejectAlreadyKnown = "already_known"
+	rejectBadSignature = "bad_signature"
+	rejectMempoolFull  = "mempool_full"
+	rejectInvalid      = "invalid"
+)
+
+// rejectReason returns the metric reason for the error returned by the chain
+// for a block or transaction.
+func rejectReason(err error) string {
+	switch {
+	case errors.Is(err, core.ErrAlreadyExists), errors.Is(err, mempool.ErrDup),
+		errors.Is(err, core.ErrInvalidBlockIndex):
+		return rejectAlreadyKnown
+	case errors.Is(err, core.ErrVerificationFailed), errors.Is(err, core.ErrWitnessHashMismatch):
+		return rejectBadSignature
+	case errors.Is(err, core.ErrOOM), errors.Is(err, mempool.ErrOOM):
+		return rejectMempoolFull
+	}
+	return rejectInvalid
+}
+
 // RelayTxn a new transaction to the local node and the connected peers.
 // Reference: the method OnRelay in C#: https://github.com/neo-project/neo/blob/master/neo/Network/P2P/LocalNode.cs#L159
 func (s *Server) RelayTxn(t *transaction.Transaction) error {
@@ -1644,7 +2081,14 @@ func (s *Server) broadcastTxLoop() {
 // case `AnnouncedPort` is set in the server.Config, the announced node port
 // will be returned (e.g. consider the node running behind NAT). If `AnnouncedPort`
 // isn't set, the port returned may still differs from that of server.Config.
+// The external port mapped on the gateway takes precedence over both if NAT
+// port mapping is enabled and succeeded.
 func (s *Server) Port() (uint16, error) {
+	if s.nat != nil {
+		if port, ok := s.nat.externalPort(); ok {
+			return port, nil
+		}
+	}
 	if s.AnnouncedPort != 0 {
 		return s.ServerConfig.AnnouncedPort, nil
 	}
@@ -1662,6 +2106,36 @@ func (s *Server) Port() (uint16, error) {
 	return port, nil
 }
 
+// listenPort returns the port the P2P listener is bound to, it waits for the
+// listener to be started.
+func (s *Server) listenPort() (uint16, error) {
+	for s.transport.Address() == "" {
+		select {
+		case <-s.quit:
+			return 0, errServerShutdown
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	_, portStr, err := net.SplitHostPort(s.transport.Address())
+	if err != nil {
+		return 0, err
+	}
+	p, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(p), nil
+}
+
+// NATStatus returns the state of the automatic port mapping, the second value
+// is false if it's disabled.
+func (s *Server) NATStatus() (NATStatus, bool) {
+	if s.nat == nil {
+		return NATStatus{}, false
+	}
+	return s.nat.Status(), true
+}
+
 // optimalNumOfThreads returns the optimal number of processing threads to create
 // for transaction processing.
 func optimalNumOfThreads() int {
@@ -56,6 +56,11 @@ type (
 		// When this is 0, the default interval of 5 seconds will be used.
 		ProtoTickInterval time.Duration
 
+		// ShutdownTimeout is the maximum time graceful shutdown may take
+		// draining the block queue, peer send queues and in-flight message
+		// handlers. When this is 0, the default of 10 seconds will be used.
+		ShutdownTimeout time.Duration
+
 		// Interval used in pinging mechanism for syncing blocks.
 		PingInterval time.Duration
 		// Time to wait for pong(response for sent ping request).
@@ -81,6 +86,47 @@ type (
 
 		// BroadcastFactor is the factor (0-100) for fan-out optimization.
 		BroadcastFactor int
+
+		// ScoreHalfLife is the time peer score counters are halved in.
+		ScoreHalfLife time.Duration
+		// DisconnectScore is the peer score threshold to disconnect at,
+		// zero disables disconnections based on score.
+		DisconnectScore float64
+
+		// MaxPeersPerPrefix is the maximum number of outgoing connections to
+		// nodes from the same network, zero means no limit.
+		MaxPeersPerPrefix int
+		// TrustedPeers is a list of addresses and networks not subject to
+		// MaxPeersPerPrefix limit.
+		TrustedPeers []string
+		// ASNMapFile is an optional path to the ASN map used to group
+		// peers by autonomous system.
+		ASNMapFile string
+
+		// Compression enables DEFLATE payload compression for peers
+		// supporting it (advertised via version capabilities).
+		Compression bool
+
+		// SecureP2P is the encrypted transport configuration.
+		SecureP2P config.SecureP2P
+
+		// NAT is the automatic port mapping configuration.
+		NAT config.NAT
+
+		// PeerPersistence is the known good peer addresses storage
+		// configuration.
+		PeerPersistence config.PeerPersistence
+
+		// MemPoolPersistence is the memory pool contents storage
+		// configuration.
+		MemPoolPersistence config.MemPoolPersistence
+
+		// ProxyAddress is the address of SOCKS5 proxy used for all outgoing
+		// connections, they're established directly if it's empty.
+		ProxyAddress string
+		// DisableInbound disables P2P listeners, only outgoing connections
+		// are established then.
+		DisableInbound bool
 	}
 )
 
@@ -100,6 +146,7 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 		Seeds:              protoConfig.SeedList,
 		DialTimeout:        time.Duration(appConfig.DialTimeout) * time.Second,
 		ProtoTickInterval:  time.Duration(appConfig.ProtoTickInterval) * time.Second,
+		ShutdownTimeout:    time.Duration(appConfig.ShutdownTimeout) * time.Second,
 		PingInterval:       time.Duration(appConfig.PingInterval) * time.Second,
 		PingTimeout:        time.Duration(appConfig.PingTimeout) * time.Second,
 		MaxPeers:           appConfig.MaxPeers,
@@ -111,5 +158,17 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 		StateRootCfg:       appConfig.StateRoot,
 		ExtensiblePoolSize: appConfig.ExtensiblePoolSize,
 		BroadcastFactor:    appConfig.BroadcastFactor,
+		ScoreHalfLife:      time.Duration(appConfig.PeerScoring.HalfLife) * time.Second,
+		DisconnectScore:    appConfig.PeerScoring.DisconnectScore,
+		MaxPeersPerPrefix:  appConfig.PeerDiversity.MaxPerPrefix,
+		TrustedPeers:       appConfig.PeerDiversity.Trusted,
+		ASNMapFile:         appConfig.PeerDiversity.ASNMap,
+		Compression:        appConfig.P2PCompression,
+		SecureP2P:          appConfig.SecureP2P,
+		NAT:                appConfig.NAT,
+		PeerPersistence:    appConfig.PeerPersistence,
+		MemPoolPersistence: appConfig.MemPoolPersistence,
+		ProxyAddress:       appConfig.ProxyAddress,
+		DisableInbound:     appConfig.DisableInbound,
 	}
 }
@@ -3,8 +3,10 @@ package network
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
 	"net"
+	"os"
 	"strconv"
 	"sync"
 	atomic2 "sync/atomic"
@@ -18,6 +20,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -25,6 +28,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
@@ -183,6 +187,133 @@ func TestServerRegisterPeer(t *testing.T) {
 	}, time.Second, time.Millisecond*50)
 }
 
+func TestServerPeerScoring(t *testing.T) {
+	const peerCount = 3
+
+	s := newTestServer(t, ServerConfig{MaxPeers: 2, DisconnectScore: -2 * scoreInvalidPenalty, PingInterval: time.Hour})
+	ps := make([]*localPeer, peerCount+1)
+	for i := range ps {
+		ps[i] = newLocalPeer(t, s)
+		ps[i].netaddr.Port = i + 1
+	}
+	startWithCleanup(t, s)
+	handshake := func(p *localPeer) {
+		require.NoError(t, s.handleMessage(p, NewMessage(CMDVerack, payload.NewNullPayload())))
+	}
+
+	s.scores.usefulBlock(ps[0].PeerAddr().String())
+	s.scores.invalidPayload(ps[1].PeerAddr().String())
+	for i := 0; i < 2; i++ {
+		s.register <- ps[i]
+	}
+	require.Eventually(t, func() bool { return 2 == s.PeerCount() }, time.Second, time.Millisecond*10)
+
+	handshake(ps[0])
+	handshake(ps[1])
+
+	// Over the limit, but scores are only checked after the handshake.
+	s.register <- ps[2]
+	require.Eventually(t, func() bool { return peerCount == s.PeerCount() }, time.Second, time.Millisecond*10)
+	require.Nil(t, ps[2].droppedWith.Load())
+
+	// The worst handshaked peer is dropped.
+	handshake(ps[2])
+	require.Eventually(t, func() bool { return ps[1].droppedWith.Load() != nil }, time.Second, time.Millisecond*10)
+	require.True(t, errors.Is(ps[1].droppedWith.Load().(error), errMaxPeers))
+	require.Eventually(t, func() bool { return 2 == s.PeerCount() }, time.Second, time.Millisecond*10)
+	require.Nil(t, ps[0].droppedWith.Load())
+	require.Nil(t, ps[2].droppedWith.Load())
+
+	t.Run("poor score", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			s.scores.invalidPayload(ps[peerCount].PeerAddr().String())
+		}
+		s.register <- ps[peerCount]
+		require.Eventually(t, func() bool { return 3 == s.PeerCount() }, time.Second, time.Millisecond*10)
+		require.Nil(t, ps[peerCount].droppedWith.Load()) // Unknown before the handshake.
+		banned := testutil.ToFloat64(peerHandshakeFailures.WithLabelValues(handshakeBanned))
+		handshake(ps[peerCount])
+		require.Eventually(t, func() bool { return ps[peerCount].droppedWith.Load() != nil }, time.Second, time.Millisecond*10)
+		require.True(t, errors.Is(ps[peerCount].droppedWith.Load().(error), errPoorScore))
+		require.Equal(t, banned+1, testutil.ToFloat64(peerHandshakeFailures.WithLabelValues(handshakeBanned)))
+		require.Nil(t, ps[0].droppedWith.Load())
+		require.Nil(t, ps[2].droppedWith.Load())
+	})
+	t.Run("connected peer", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			s.scores.invalidPayload(ps[2].PeerAddr().String())
+		}
+		s.dropPoorPeers()
+		require.Eventually(t, func() bool { return ps[2].droppedWith.Load() != nil }, time.Second, time.Millisecond*10)
+		require.True(t, errors.Is(ps[2].droppedWith.Load().(error), errPoorScore))
+		require.Nil(t, ps[0].droppedWith.Load())
+	})
+}
+
+func TestServerEvictionRace(t *testing.T) {
+	const (
+		maxPeers  = 3
+		peerCount = 20
+	)
+
+	s := newTestServer(t, ServerConfig{MaxPeers: maxPeers, PingInterval: time.Hour})
+	ps := make([]*localPeer, peerCount)
+	for i := range ps {
+		ps[i] = newLocalPeer(t, s)
+		ps[i].netaddr.Port = i + 1
+		ps[i].handshaked = 1
+	}
+	startWithCleanup(t, s)
+	for _, p := range ps {
+		s.register <- p
+	}
+	require.Eventually(t, func() bool { return peerCount == s.PeerCount() }, time.Second, time.Millisecond*10)
+
+	// All peers complete the handshake simultaneously, every check must
+	// evict a different peer and no more than needed.
+	var (
+		wg      sync.WaitGroup
+		start   = make(chan struct{})
+		dropped atomic.Int32
+	)
+	for _, p := range ps {
+		wg.Add(1)
+		go func(p *localPeer) {
+			defer wg.Done()
+			<-start
+			if !s.checkHandshakedPeer(p) {
+				dropped.Inc()
+			}
+		}(p)
+	}
+	close(start)
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		s.lock.RLock()
+		n := len(s.evicted)
+		s.lock.RUnlock()
+		return n == 0 && s.PeerCount() == maxPeers
+	}, time.Second, time.Millisecond*10)
+	var evicted int
+	for _, p := range ps {
+		if err := p.droppedWith.Load(); err != nil {
+			require.True(t, errors.Is(err.(error), errMaxPeers))
+			evicted++
+		}
+	}
+	require.Equal(t, peerCount-maxPeers, evicted)
+	require.True(t, int(dropped.Load()) <= evicted)
+
+	// Late checks of already connected peers don't evict anyone.
+	for _, p := range ps {
+		if p.droppedWith.Load() == nil {
+			require.True(t, s.checkHandshakedPeer(p))
+		}
+	}
+	require.Never(t, func() bool { return s.PeerCount() != maxPeers }, time.Millisecond*100, time.Millisecond*10)
+}
+
 func TestGetBlocksByIndex(t *testing.T) {
 	testGetBlocksByIndex(t, CMDGetBlockByIndex)
 }
@@ -220,6 +351,10 @@ func testGetBlocksByIndex(t *testing.T, cmd CommandType) {
 	checkPingRespond := func(t *testing.T, peerIndex int, peerHeight uint32, hs ...uint32) {
 		nonce++
 		expectedHeight[peerIndex] = hs
+		if len(hs) == 0 {
+			// Nothing is to be requested from this peer.
+			expectsCmd[peerIndex] = CMDPong
+		}
 		require.NoError(t, s.handlePing(ps[peerIndex], payload.NewPing(peerHeight, nonce)))
 	}
 
@@ -232,6 +367,21 @@ func testGetBlocksByIndex(t *testing.T, cmd CommandType) {
 	// Receive some blocks.
 	s.chain.(*fakechain.FakeChain).Blockheight = 2123
 
+	if cmd == CMDGetBlockByIndex {
+		// Chunks are assigned in order, one per peer.
+		checkPingRespond(t, 5, 5000, 2124)
+		checkPingRespond(t, 6, 5000, 2624)
+		// Peers behind can't get a chunk above their height.
+		checkPingRespond(t, 7, 3100)
+		checkPingRespond(t, 8, 5000, 3124)
+		checkPingRespond(t, 9, 5000, 3624)
+		// Peers already having a chunk are not given another one.
+		checkPingRespond(t, 5, 5000)
+		// The whole block queue range is requested.
+		checkPingRespond(t, 1, 5000)
+		return
+	}
+
 	// Minimum chunk has priority.
 	checkPingRespond(t, 5, 5000, 2124)
 	checkPingRespond(t, 6, 5000, 2624)
@@ -245,6 +395,44 @@ func testGetBlocksByIndex(t *testing.T, cmd CommandType) {
 	checkPingRespond(t, 3, 5000, 2124, 2624, 3124, 3624)
 }
 
+func TestRequestBlocksByScore(t *testing.T) {
+	s := newTestServer(t, ServerConfig{DisconnectScore: -scoreInvalidPenalty})
+	ps := make([]*localPeer, 5)
+	requested := make([]uint32, len(ps))
+	for i := range ps {
+		i := i
+		ps[i] = newLocalPeer(t, s)
+		ps[i].netaddr.Port = i + 1
+		ps[i].handshaked = 1
+		ps[i].lastBlockIndex = 5000
+		ps[i].messageHandler = func(t *testing.T, msg *Message) {
+			if msg.Command == CMDGetBlockByIndex {
+				requested[i] = msg.Payload.(*payload.GetBlockByIndex).IndexStart
+			}
+		}
+		s.peers[ps[i]] = true
+	}
+	// Below DisconnectScore.
+	s.scores.invalidPayload(ps[0].PeerAddr().String())
+	s.scores.invalidPayload(ps[0].PeerAddr().String())
+	// The best one.
+	s.scores.usefulBlock(ps[1].PeerAddr().String())
+	// ps[2] has zero score, ps[3] is worse.
+	s.scores.timeout(ps[3].PeerAddr().String())
+	// Good, but can't serve blocks we need.
+	s.scores.usefulBlock(ps[4].PeerAddr().String())
+	ps[4].lastBlockIndex = 0
+
+	require.NoError(t, s.handlePing(ps[3], payload.NewPing(5000, 1)))
+	require.Equal(t, []uint32{
+		0,
+		1,
+		1 + payload.MaxHashesCount,
+		1 + 2*payload.MaxHashesCount,
+		0,
+	}, requested)
+}
+
 func TestSendVersion(t *testing.T) {
 	var (
 		s = newTestServer(t, ServerConfig{Port: 0, UserAgent: "/test/"})
@@ -278,6 +466,47 @@ func TestSendVersion(t *testing.T) {
 	require.NoError(t, p.SendVersion())
 }
 
+func TestPayloadCompressionNegotiation(t *testing.T) {
+	compressionCap := capability.Capability{
+		Type: capability.PayloadCompression,
+		Data: &capability.Compression{Algorithms: capability.Deflate},
+	}
+	for _, enabled := range []bool{false, true} {
+		s := newTestServer(t, ServerConfig{Compression: enabled})
+		s.transport.Accept()
+		msg, err := s.getVersionMsg()
+		require.NoError(t, err)
+		require.Equal(t, enabled, len(msg.Payload.(*payload.Version).Capabilities) == 2)
+
+		p := newLocalPeer(t, s)
+		require.False(t, s.useDeflate(p)) // No version.
+		p.version = &payload.Version{}
+		require.False(t, s.useDeflate(p))
+		p.version.Capabilities = capability.Capabilities{{
+			Type: capability.PayloadCompression,
+			Data: &capability.Compression{},
+		}}
+		require.False(t, s.useDeflate(p)) // No supported algorithms.
+		p.version.Capabilities = capability.Capabilities{compressionCap}
+		require.Equal(t, enabled, s.useDeflate(p))
+	}
+	t.Run("getblockbyindex", func(t *testing.T) {
+		s, blocks := initGetBlocksTest(t)
+		s.Compression = true
+		p := newLocalPeer(t, s)
+		p.handshaked = 1
+		p.version = &payload.Version{Capabilities: capability.Capabilities{compressionCap}}
+		var actual []*block.Block
+		p.messageHandler = func(t *testing.T, msg *Message) {
+			if msg.Command == CMDBlock {
+				actual = append(actual, msg.Payload.(*block.Block))
+			}
+		}
+		s.testHandleMessage(t, p, CMDGetBlockByIndex, &payload.GetBlockByIndex{IndexStart: blocks[0].Index, Count: 2})
+		require.Equal(t, blocks[:2], actual)
+	})
+}
+
 // Server should reply with a verack after receiving a valid version.
 func TestVerackAfterHandleVersionCmd(t *testing.T) {
 	var (
@@ -375,6 +604,60 @@ func TestServerNotSendsVerack(t *testing.T) {
 	require.Equal(t, errAlreadyConnected, err)
 }
 
+func TestHandshakeFailureReason(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		reason string
+	}{
+		{errInvalidNetwork, handshakeBadMagic},
+		{errIdenticalID, handshakeDuplicate},
+		{fmt.Errorf("handling: %w", errAlreadyConnected), handshakeDuplicate},
+		{errPoorScore, handshakeBanned},
+		{fmt.Errorf("%w: already received Version", errInvalidHandshake), handshakeVersionMismatch},
+		{&net.OpError{Op: "read", Err: os.ErrDeadlineExceeded}, handshakeTimeout},
+		{errServerShutdown, ""},
+		{errMaxPeers, ""},
+		{io.EOF, ""},
+	} {
+		require.Equal(t, tc.reason, handshakeFailureReason(tc.err), tc.err.Error())
+	}
+}
+
+func TestHandshakeFailureMetric(t *testing.T) {
+	s := newTestServer(t, ServerConfig{MaxPeers: 10, Net: 56753})
+	finished := make(chan struct{})
+	go func() {
+		s.run()
+		close(finished)
+	}()
+	t.Cleanup(func() {
+		close(s.quit)
+		<-finished
+	})
+
+	var (
+		badMagic  = peerHandshakeFailures.WithLabelValues(handshakeBadMagic)
+		duplicate = peerHandshakeFailures.WithLabelValues(handshakeDuplicate)
+		before    = testutil.ToFloat64(badMagic)
+		dupBefore = testutil.ToFloat64(duplicate)
+	)
+	p := newLocalPeer(t, s)
+	s.register <- p
+	require.Eventually(t, func() bool { return 1 == s.PeerCount() }, time.Second, time.Millisecond*10)
+	p.Disconnect(errInvalidNetwork)
+	require.Eventually(t, func() bool { return 0 == s.PeerCount() }, time.Second, time.Millisecond*10)
+	require.Equal(t, before+1, testutil.ToFloat64(badMagic))
+
+	// Handshaked peers are not accounted for.
+	p = newLocalPeer(t, s)
+	p.handshaked = 1
+	s.register <- p
+	require.Eventually(t, func() bool { return 1 == s.PeerCount() }, time.Second, time.Millisecond*10)
+	p.Disconnect(errAlreadyConnected)
+	require.Eventually(t, func() bool { return 0 == s.PeerCount() }, time.Second, time.Millisecond*10)
+	require.Equal(t, dupBefore, testutil.ToFloat64(duplicate))
+}
+
 func (s *Server) testHandleMessage(t *testing.T, p Peer, cmd CommandType, pl payload.Payload) *Server {
 	if p == nil {
 		p = newLocalPeer(t, s)
@@ -385,6 +668,75 @@ func (s *Server) testHandleMessage(t *testing.T, p Peer, cmd CommandType, pl pay
 	return s
 }
 
+// slowChain is a FakeChain adding blocks with some delay.
+type slowChain struct {
+	*fakechain.FakeChain
+}
+
+func (c slowChain) AddBlock(b *block.Block) error {
+	time.Sleep(10 * time.Millisecond)
+	return c.FakeChain.AddBlock(b)
+}
+
+func TestServerGracefulShutdown(t *testing.T) {
+	newSyncingServer := func(t *testing.T, cfg ServerConfig, n int) (*Server, chan error) {
+		s := newTestServer(t, cfg)
+		chain := s.chain.(*fakechain.FakeChain)
+		s.bQueue = newBlockQueue(maxBlockBatch, slowChain{chain}, s.log, nil)
+		ch := startWithChannel(s)
+		for i := 1; i <= n; i++ {
+			require.NoError(t, s.bQueue.putBlock(&block.Block{Header: block.Header{Index: uint32(i)}}))
+		}
+		return s, ch
+	}
+	t.Run("blocks are flushed", func(t *testing.T) {
+		s, ch := newSyncingServer(t, ServerConfig{}, 30)
+		// Can't be added because of the gap.
+		require.NoError(t, s.bQueue.putBlock(&block.Block{Header: block.Header{Index: 32}}))
+		p := newLocalPeer(t, s)
+		s.register <- p
+		require.Eventually(t, func() bool { return 1 == s.PeerCount() }, time.Second, time.Millisecond*10)
+
+		s.Shutdown()
+		<-ch
+		require.Equal(t, uint32(30), s.chain.BlockHeight())
+		err, ok := p.droppedWith.Load().(error)
+		require.True(t, ok)
+		require.True(t, errors.Is(err, errServerShutdown))
+	})
+	t.Run("timeout", func(t *testing.T) {
+		s, ch := newSyncingServer(t, ServerConfig{ShutdownTimeout: 50 * time.Millisecond}, 1000)
+		s.Shutdown()
+		<-ch
+		require.Less(t, s.chain.BlockHeight(), uint32(1000))
+	})
+	t.Run("handlers", func(t *testing.T) {
+		s := newTestServer(t, ServerConfig{})
+		ch := startWithChannel(s)
+		finished := atomic.NewBool(false)
+		s.handlers.Inc()
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			finished.Store(true)
+			s.handlers.Dec()
+		}()
+		s.Shutdown()
+		<-ch
+		require.True(t, finished.Load())
+	})
+	t.Run("no new peers", func(t *testing.T) {
+		s := newTestServer(t, ServerConfig{})
+		ch := startWithChannel(s)
+		s.stopping.Store(true)
+		p := newLocalPeer(t, s)
+		s.register <- p
+		require.Eventually(t, func() bool { return p.droppedWith.Load() != nil }, time.Second, time.Millisecond*10)
+		require.True(t, errors.Is(p.droppedWith.Load().(error), errServerShutdown))
+		s.Shutdown()
+		<-ch
+	})
+}
+
 func startTestServer(t *testing.T, protocolCfg ...func(*config.ProtocolConfiguration)) *Server {
 	var s *Server
 	srvCfg := ServerConfig{Port: 0, UserAgent: "/test/"}
@@ -497,6 +849,8 @@ func TestTransaction(t *testing.T) {
 	})
 	t.Run("bad", func(t *testing.T) {
 		tx := newDummyTx()
+		rejected := transactionsRejected.WithLabelValues(rejectInvalid)
+		before := testutil.ToFloat64(rejected)
 		s.chain.(*fakechain.FakeChain).PoolTxF = func(*transaction.Transaction) error { return core.ErrInsufficientFunds }
 		s.testHandleMessage(t, nil, CMDTX, tx)
 		require.Eventually(t, func() bool {
@@ -510,9 +864,30 @@ func TestTransaction(t *testing.T) {
 			}
 			return false
 		}, 2*time.Second, time.Millisecond*500)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(rejected) == before+1
+		}, 2*time.Second, time.Millisecond*50)
 	})
 }
 
+func TestRejectReason(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		reason string
+	}{
+		{core.ErrAlreadyExists, rejectAlreadyKnown},
+		{fmt.Errorf("pool: %w", mempool.ErrDup), rejectAlreadyKnown},
+		{fmt.Errorf("expected 5, got 3: %w", core.ErrInvalidBlockIndex), rejectAlreadyKnown},
+		{core.ErrInvalidSignature, rejectBadSignature},
+		{fmt.Errorf("transaction failed to verify: %w", core.ErrWitnessHashMismatch), rejectBadSignature},
+		{core.ErrOOM, rejectMempoolFull},
+		{core.ErrInsufficientFunds, rejectInvalid},
+		{core.ErrHdrHashMismatch, rejectInvalid},
+	} {
+		require.Equal(t, tc.reason, rejectReason(tc.err), tc.err.Error())
+	}
+}
+
 func (s *Server) testHandleGetData(t *testing.T, invType payload.InventoryType, hs, notFound []util.Uint256, found payload.Payload) {
 	var recvResponse atomic.Bool
 	var recvNotFound atomic.Bool
@@ -31,10 +31,11 @@ const (
 )
 
 var (
-	errGone           = errors.New("the peer is gone already")
-	errStateMismatch  = errors.New("tried to send protocol message before handshake completed")
-	errPingPong       = errors.New("ping/pong timeout")
-	errUnexpectedPong = errors.New("pong message wasn't expected")
+	errGone             = errors.New("the peer is gone already")
+	errStateMismatch    = errors.New("tried to send protocol message before handshake completed")
+	errInvalidHandshake = errors.New("invalid handshake")
+	errPingPong         = errors.New("ping/pong timeout")
+	errUnexpectedPong   = errors.New("pong message wasn't expected")
 )
 
 // TCPPeer represents a connected remote node in the
@@ -66,6 +67,10 @@ type TCPPeer struct {
 	// number of sent pings.
 	pingSent  int
 	pingTimer *time.Timer
+
+	// traffic counters.
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
 }
 
 // NewTCPPeer returns a TCPPeer structure based on the given connection.
@@ -111,7 +116,7 @@ func (p *TCPPeer) BroadcastHPPacket(ctx context.Context, msg []byte) error {
 // putMessageIntoQueue serializes the given Message and puts it into given queue if
 // the peer has done handshaking.
 func (p *TCPPeer) putMsgIntoQueue(queue chan<- []byte, msg *Message) error {
-	b, err := msg.Bytes()
+	b, err := msg.bytes(p.server.useDeflate(p))
 	if err != nil {
 		return err
 	}
@@ -145,10 +150,28 @@ func (p *TCPPeer) writeMsg(msg *Message) error {
 	}
 
 	_, err = p.conn.Write(b)
+	if err == nil {
+		p.packetSent(b)
+	}
 
 	return err
 }
 
+// packetSent accounts for the packet written to the connection.
+func (p *TCPPeer) packetSent(b []byte) {
+	p.bytesSent.Add(uint64(len(b)))
+	splitPacket(b, addCmdSentBytesMetric)
+}
+
+// Traffic implements the Peer interface.
+func (p *TCPPeer) Traffic() PeerTraffic {
+	return PeerTraffic{
+		BytesSent:     p.bytesSent.Load(),
+		BytesReceived: p.bytesReceived.Load(),
+		SendQueueLen:  len(p.hpSendQ) + len(p.p2pSendQ) + len(p.sendQ),
+	}
+}
+
 // handleConn handles the read side of the connection, it should be started as
 // a goroutine right after a new peer setup.
 func (p *TCPPeer) handleConn() {
@@ -163,13 +186,25 @@ func (p *TCPPeer) handleConn() {
 	if err == nil {
 		r := io.NewBinReaderFromIO(p.conn)
 		for {
-			msg := &Message{StateRootInHeader: p.server.config.StateRootInHeader}
+			msg := &Message{
+				StateRootInHeader: p.server.config.StateRootInHeader,
+				deflateAllowed:    p.server.useDeflate(p),
+			}
 			err = msg.Decode(r)
+			if r.Err == nil {
+				size := msg.wireSize()
+				p.bytesReceived.Add(uint64(size))
+				addCmdReceivedBytesMetric(msg.Command, size)
+			}
 
 			if errors.Is(err, payload.ErrTooManyHeaders) {
 				p.server.log.Warn("not all headers were processed")
 				r.Err = nil
 			} else if err != nil {
+				// The message was read completely, but can't be decoded.
+				if r.Err == nil && p.Handshaked() {
+					p.server.scores.malformedMessage(p.PeerAddr().String())
+				}
 				break
 			}
 			p.incoming <- msg
@@ -185,6 +220,9 @@ func (p *TCPPeer) handleIncoming() {
 		err = p.server.handleMessage(p, msg)
 		if err != nil {
 			if p.Handshaked() {
+				if !errors.Is(err, errPingPong) { // Accounted for as a timeout.
+					p.server.scores.invalidPayload(p.PeerAddr().String())
+				}
 				err = fmt.Errorf("handling %s message: %w", msg.Command.String(), err)
 			}
 			break
@@ -245,6 +283,7 @@ func (p *TCPPeer) handleQueues() {
 		if err != nil {
 			break
 		}
+		p.packetSent(msg)
 		p2pSkipCounter++
 	}
 	p.Disconnect(err)
@@ -317,7 +356,7 @@ func (p *TCPPeer) SendVersion() error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	if p.handShake&versionSent != 0 {
-		return errors.New("invalid handshake: already sent Version")
+		return fmt.Errorf("%w: already sent Version", errInvalidHandshake)
 	}
 	err = p.writeMsg(msg)
 	if err == nil {
@@ -331,7 +370,7 @@ func (p *TCPPeer) HandleVersion(version *payload.Version) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	if p.handShake&versionReceived != 0 {
-		return errors.New("invalid handshake: already received Version")
+		return fmt.Errorf("%w: already received Version", errInvalidHandshake)
 	}
 	p.version = version
 	for _, cap := range version.Capabilities {
@@ -351,13 +390,13 @@ func (p *TCPPeer) SendVersionAck(msg *Message) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	if p.handShake&versionReceived == 0 {
-		return errors.New("invalid handshake: tried to send VersionAck, but no version received yet")
+		return fmt.Errorf("%w: tried to send VersionAck, but no version received yet", errInvalidHandshake)
 	}
 	if p.handShake&versionSent == 0 {
-		return errors.New("invalid handshake: tried to send VersionAck, but didn't send Version yet")
+		return fmt.Errorf("%w: tried to send VersionAck, but didn't send Version yet", errInvalidHandshake)
 	}
 	if p.handShake&verAckSent != 0 {
-		return errors.New("invalid handshake: already sent VersionAck")
+		return fmt.Errorf("%w: already sent VersionAck", errInvalidHandshake)
 	}
 	err := p.writeMsg(msg)
 	if err == nil {
@@ -372,13 +411,13 @@ func (p *TCPPeer) HandleVersionAck() error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	if p.handShake&versionSent == 0 {
-		return errors.New("invalid handshake: received VersionAck, but no version sent yet")
+		return fmt.Errorf("%w: received VersionAck, but no version sent yet", errInvalidHandshake)
 	}
 	if p.handShake&versionReceived == 0 {
-		return errors.New("invalid handshake: received VersionAck, but no version received yet")
+		return fmt.Errorf("%w: received VersionAck, but no version received yet", errInvalidHandshake)
 	}
 	if p.handShake&verAckReceived != 0 {
-		return errors.New("invalid handshake: already received VersionAck")
+		return fmt.Errorf("%w: already received VersionAck", errInvalidHandshake)
 	}
 	p.handShake |= verAckReceived
 	return nil
@@ -392,6 +431,11 @@ func (p *TCPPeer) RemoteAddr() net.Addr {
 // PeerAddr implements the Peer interface.
 func (p *TCPPeer) PeerAddr() net.Addr {
 	remote := p.conn.RemoteAddr()
+	// Proxied connections are outgoing ones, the address dialed is the one
+	// to use and it's not resolved locally.
+	if addr, ok := remote.(proxiedAddr); ok {
+		return addr
+	}
 	// The network can be non-tcp in unit tests.
 	if p.version == nil || remote.Network() != "tcp" {
 		return p.RemoteAddr()
@@ -428,6 +472,8 @@ func (p *TCPPeer) Disconnect(err error) {
 
 // Version implements the Peer interface.
 func (p *TCPPeer) Version() *payload.Version {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
 	return p.version
 }
 
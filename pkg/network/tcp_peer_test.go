@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -83,3 +84,32 @@ func TestPeerHandshake(t *testing.T) {
 	require.NoError(t, tcpS.EnqueueP2PMessage(&Message{}))
 	require.NoError(t, tcpC.EnqueueP2PMessage(&Message{}))
 }
+
+func TestPeerTraffic(t *testing.T) {
+	server, client := net.Pipe()
+	go connReadStub(client)
+
+	p := NewTCPPeer(server, newTestServer(t, ServerConfig{}))
+	require.Equal(t, PeerTraffic{}, p.Traffic())
+
+	ping, err := NewMessage(CMDPing, payload.NewPing(1, 2)).Bytes()
+	require.NoError(t, err)
+	getAddr, err := NewMessage(CMDGetAddr, payload.NewNullPayload()).Bytes()
+	require.NoError(t, err)
+	pkt := append(append([]byte{}, ping...), getAddr...)
+
+	var (
+		pingBytes    = p2pCmdSentBytes.WithLabelValues("cmdping")
+		getAddrBytes = p2pCmdSentBytes.WithLabelValues("cmdgetaddr")
+		pingBefore   = testutil.ToFloat64(pingBytes)
+		addrBefore   = testutil.ToFloat64(getAddrBytes)
+	)
+	p.packetSent(pkt)
+	require.Equal(t, uint64(len(pkt)), p.Traffic().BytesSent)
+	require.Equal(t, float64(len(ping)), testutil.ToFloat64(pingBytes)-pingBefore)
+	require.Equal(t, float64(len(getAddr)), testutil.ToFloat64(getAddrBytes)-addrBefore)
+
+	p.sendQ <- pkt
+	p.hpSendQ <- ping
+	require.Equal(t, 2, p.Traffic().SendQueueLen)
+}
@@ -31,10 +31,11 @@ func NewTCPTransport(s *Server, bindAddr string, log *zap.Logger) *TCPTransport
 
 // Dial implements the Transporter interface.
 func (t *TCPTransport) Dial(addr string, timeout time.Duration) error {
-	conn, err := net.DialTimeout("tcp", addr, timeout)
+	conn, err := t.server.dial(addr, timeout)
 	if err != nil {
 		return err
 	}
+	addConnectionMetric(t.Proto())
 	p := NewTCPPeer(conn, t.server)
 	go p.handleConn()
 	return nil
@@ -69,6 +70,7 @@ func (t *TCPTransport) Accept() {
 			t.log.Warn("TCP accept error", zap.Error(err))
 			continue
 		}
+		addConnectionMetric(t.Proto())
 		p := NewTCPPeer(conn, t.server)
 		go p.handleConn()
 	}
@@ -0,0 +1,252 @@
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"go.uber.org/zap"
+)
+
+// tlsHandshakeTimeout is the maximum time an incoming connection handshake
+// can take.
+const tlsHandshakeTimeout = 10 * time.Second
+
+// TLSTransport allows encrypted and mutually authenticated network
+// communication over TLS. Both sides of the connection must present a
+// certificate trusted by the other one, the regular P2P protocol is used
+// inside of the tunnel.
+type TLSTransport struct {
+	log      *zap.Logger
+	server   *Server
+	listener net.Listener
+	bindAddr string
+	config   *tls.Config
+	lock     sync.RWMutex
+	quit     bool
+}
+
+// NewTLSTransport returns a new TLSTransport that will listen for new
+// incoming peer connections using the given TLS configuration (see
+// NewSecureTLSConfig).
+func NewTLSTransport(s *Server, bindAddr string, cfg *tls.Config, log *zap.Logger) *TLSTransport {
+	return &TLSTransport{
+		log:      log,
+		server:   s,
+		bindAddr: bindAddr,
+		config:   cfg,
+	}
+}
+
+// NewSecureTLSConfig creates a TLS configuration for the secure P2P transport.
+// It requires remote nodes to present a certificate that is either listed in
+// or signed by one of the trusted certificates. Host names are not checked,
+// the set of trusted certificates is an allow-list of nodes.
+func NewSecureTLSConfig(cfg config.SecureP2P) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load node certificate: %w", err)
+	}
+	if len(cfg.TrustedCertificates) == 0 {
+		return nil, errors.New("no trusted certificates")
+	}
+	pool := x509.NewCertPool()
+	for _, f := range cfg.TrustedCertificates {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read trusted certificate: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificates in %s", f)
+		}
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAnyClientCert,
+		MinVersion:   tls.VersionTLS13,
+		// Standard verification includes host name check which doesn't make
+		// sense for P2P addresses, so it's replaced by verifyTrusted.
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verifyTrusted(pool),
+	}, nil
+}
+
+// verifyTrusted returns a function checking that the peer certificate chains
+// up to one of the trusted certificates.
+func verifyTrusted(pool *x509.CertPool) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.New("no peer certificate")
+		}
+		certs := make([]*x509.Certificate, len(rawCerts))
+		for i := range rawCerts {
+			c, err := x509.ParseCertificate(rawCerts[i])
+			if err != nil {
+				return fmt.Errorf("invalid peer certificate: %w", err)
+			}
+			certs[i] = c
+		}
+		intermediates := x509.NewCertPool()
+		for _, c := range certs[1:] {
+			intermediates.AddCert(c)
+		}
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			Roots:         pool,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return fmt.Errorf("untrusted peer certificate: %w", err)
+		}
+		return nil
+	}
+}
+
+// Dial implements the Transporter interface.
+func (t *TLSTransport) Dial(addr string, timeout time.Duration) error {
+	conn, err := t.server.dial(addr, timeout)
+	if err != nil {
+		return err
+	}
+	tlsConn := tls.Client(conn, t.config)
+	_ = tlsConn.SetDeadline(time.Now().Add(timeout))
+	err = tlsConn.Handshake()
+	if err != nil {
+		addTLSHandshakeFailureMetric()
+		_ = conn.Close()
+		return fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+	addConnectionMetric(t.Proto())
+	p := NewTCPPeer(tlsConn, t.server)
+	go p.handleConn()
+	return nil
+}
+
+// Accept implements the Transporter interface.
+func (t *TLSTransport) Accept() {
+	l, err := net.Listen("tcp", t.bindAddr)
+	if err != nil {
+		t.log.Panic("TLS listen error", zap.Error(err))
+		return
+	}
+
+	t.lock.Lock()
+	if t.quit {
+		t.lock.Unlock()
+		l.Close()
+		return
+	}
+	t.listener = l
+	t.lock.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			t.lock.Lock()
+			quit := t.quit
+			t.lock.Unlock()
+			if errors.Is(err, net.ErrClosed) && quit {
+				break
+			}
+			t.log.Warn("TLS accept error", zap.Error(err))
+			continue
+		}
+		// Handshake is performed in a separate goroutine so that slow or
+		// misbehaving clients can't block the acceptor.
+		go t.handshake(conn)
+	}
+}
+
+// handshake performs TLS handshake for the incoming connection and starts
+// the peer if it succeeds.
+func (t *TLSTransport) handshake(conn net.Conn) {
+	tlsConn := tls.Server(conn, t.config)
+	_ = tlsConn.SetDeadline(time.Now().Add(tlsHandshakeTimeout))
+	err := tlsConn.Handshake()
+	if err != nil {
+		addTLSHandshakeFailureMetric()
+		t.log.Info("TLS handshake failed",
+			zap.Stringer("addr", conn.RemoteAddr()), zap.Error(err))
+		_ = conn.Close()
+		return
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+	addConnectionMetric(t.Proto())
+	p := NewTCPPeer(tlsConn, t.server)
+	p.handleConn()
+}
+
+// Close implements the Transporter interface.
+func (t *TLSTransport) Close() {
+	t.lock.Lock()
+	if t.listener != nil {
+		t.listener.Close()
+	}
+	t.quit = true
+	t.lock.Unlock()
+}
+
+// Proto implements the Transporter interface.
+func (t *TLSTransport) Proto() string {
+	return "tls"
+}
+
+// Address implements the Transporter interface.
+func (t *TLSTransport) Address() string {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	if t.listener != nil {
+		return t.listener.Addr().String()
+	}
+	return ""
+}
+
+// routedTransport is a Transporter used by discovery when the secure
+// transport is enabled. Any address of the trusted node host (including the
+// plain one announced by the node itself or learned from other peers) is
+// dialed via the secure transport using the configured secure address, all
+// other addresses are dialed via the plain one.
+type routedTransport struct {
+	Transporter
+	secure      Transporter
+	secureAddrs map[string]string
+}
+
+// newRoutedTransport creates a routedTransport for the given list of trusted
+// nodes secure addresses.
+func newRoutedTransport(plain, secure Transporter, trusted []string) *routedTransport {
+	r := &routedTransport{
+		Transporter: plain,
+		secure:      secure,
+		secureAddrs: make(map[string]string, len(trusted)),
+	}
+	for _, addr := range trusted {
+		r.secureAddrs[hostOf(addr)] = addr
+	}
+	return r
+}
+
+// Dial implements the Transporter interface.
+func (r *routedTransport) Dial(addr string, timeout time.Duration) error {
+	if secAddr, ok := r.secureAddrs[hostOf(addr)]; ok {
+		return r.secure.Dial(secAddr, timeout)
+	}
+	return r.Transporter.Dial(addr, timeout)
+}
+
+// hostOf returns the host part of the given address (or the address itself
+// if it has no port).
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
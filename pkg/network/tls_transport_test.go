@@ -0,0 +1,219 @@
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/fakechain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// genTestCert generates a self-signed certificate and a key for it and
+// stores them in the given directory returning file paths.
+func genTestCert(t *testing.T, dir string, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
+
+// knownAddrDiscovery is a testDiscovery always having some good address.
+type knownAddrDiscovery struct {
+	testDiscovery
+}
+
+func (d *knownAddrDiscovery) GoodPeers() []AddressWithCapabilities {
+	return []AddressWithCapabilities{{Address: "1.2.3.4:10333"}}
+}
+
+func TestNewSecureTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := genTestCert(t, dir, "node")
+	cfg := config.SecureP2P{
+		CertFile:            cert,
+		KeyFile:             key,
+		TrustedCertificates: []string{cert},
+	}
+	_, err := NewSecureTLSConfig(cfg)
+	require.NoError(t, err)
+
+	t.Run("bad key", func(t *testing.T) {
+		cfg := cfg
+		cfg.KeyFile = filepath.Join(dir, "unknown.key")
+		_, err := NewSecureTLSConfig(cfg)
+		require.Error(t, err)
+	})
+	t.Run("no trusted", func(t *testing.T) {
+		cfg := cfg
+		cfg.TrustedCertificates = nil
+		_, err := NewSecureTLSConfig(cfg)
+		require.Error(t, err)
+	})
+	t.Run("missing trusted", func(t *testing.T) {
+		cfg := cfg
+		cfg.TrustedCertificates = []string{filepath.Join(dir, "unknown.crt")}
+		_, err := NewSecureTLSConfig(cfg)
+		require.Error(t, err)
+	})
+	t.Run("invalid trusted", func(t *testing.T) {
+		cfg := cfg
+		cfg.TrustedCertificates = []string{key}
+		_, err := NewSecureTLSConfig(cfg)
+		require.Error(t, err)
+	})
+	t.Run("server", func(t *testing.T) {
+		cfg := cfg
+		cfg.Enabled = true
+		_, err := newServerFromConstructors(ServerConfig{SecureP2P: cfg}, fakechain.NewFakeChain(), new(fakechain.FakeStateSync),
+			zaptest.NewLogger(t), newFakeTransp, newTestDiscovery)
+		require.NoError(t, err)
+
+		cfg.TrustedCertificates = nil
+		_, err = newServerFromConstructors(ServerConfig{SecureP2P: cfg}, fakechain.NewFakeChain(), new(fakechain.FakeStateSync),
+			zaptest.NewLogger(t), newFakeTransp, newTestDiscovery)
+		require.Error(t, err)
+	})
+}
+
+func TestTLSTransport(t *testing.T) {
+	dir := t.TempDir()
+	certA, keyA := genTestCert(t, dir, "a")
+	certB, keyB := genTestCert(t, dir, "b")
+	certC, keyC := genTestCert(t, dir, "c")
+
+	newSecureServer := func(t *testing.T, cert, key string, trusted ...string) *Server {
+		s, err := newServerFromConstructors(ServerConfig{
+			Relay:        true,
+			PingInterval: time.Minute,
+			PingTimeout:  time.Minute,
+			SecureP2P: config.SecureP2P{
+				Enabled:             true,
+				Address:             "127.0.0.1",
+				CertFile:            cert,
+				KeyFile:             key,
+				TrustedCertificates: trusted,
+			},
+		}, fakechain.NewFakeChainWithCustomCfg(func(c *config.ProtocolConfiguration) {
+			c.SecondsPerBlock = 1 // Used as a write timeout.
+		}), new(fakechain.FakeStateSync), zaptest.NewLogger(t), newFakeTransp,
+			func([]string, time.Duration, DiversityConfig, Transporter) Discoverer {
+				// Empty address list is a reason for disconnection.
+				return new(knownAddrDiscovery)
+			})
+		require.NoError(t, err)
+		ch := startWithChannel(s)
+		t.Cleanup(func() {
+			s.Shutdown()
+			<-ch
+		})
+		require.Eventually(t, func() bool { return s.secureTransport.Address() != "" }, time.Second, 10*time.Millisecond)
+		return s
+	}
+
+	// A has some blocks and trusts B only, C trusts A, but isn't trusted by it.
+	srvA := newSecureServer(t, certA, keyA, certB)
+	blocks := make([]uint32, 0, 5)
+	for i := uint32(1); i <= 5; i++ {
+		srvA.chain.(*fakechain.FakeChain).PutBlock(newDummyBlock(i, 1))
+		blocks = append(blocks, i)
+	}
+	addrA := srvA.secureTransport.Address()
+
+	t.Run("plain connection", func(t *testing.T) {
+		conn, err := net.Dial("tcp", addrA)
+		require.NoError(t, err)
+		_, err = conn.Write([]byte("not a TLS handshake"))
+		require.NoError(t, err)
+		_, err = conn.Read(make([]byte, 1024))
+		for err == nil {
+			_, err = conn.Read(make([]byte, 1024))
+		}
+		conn.Close()
+		require.Equal(t, 0, srvA.PeerCount())
+	})
+	t.Run("untrusted", func(t *testing.T) {
+		srvC := newSecureServer(t, certC, keyC, certA)
+		err := srvC.secureTransport.Dial(addrA, time.Second)
+		if err == nil {
+			// TLS 1.3 client can finish the handshake before the server
+			// checks its certificate, but the connection is dropped then.
+			require.Never(t, func() bool { return srvA.PeerCount() > 0 }, 500*time.Millisecond, 10*time.Millisecond)
+		}
+		require.Equal(t, 0, srvA.PeerCount())
+	})
+	t.Run("wrong server", func(t *testing.T) {
+		// B trusts C, but not A.
+		srvB := newSecureServer(t, certB, keyB, certC)
+		require.Error(t, srvB.secureTransport.Dial(addrA, time.Second))
+		require.Equal(t, 0, srvB.PeerCount())
+	})
+	t.Run("good", func(t *testing.T) {
+		srvB := newSecureServer(t, certB, keyB, certA)
+		require.NoError(t, srvB.secureTransport.Dial(addrA, time.Second))
+		require.Eventually(t, func() bool {
+			return srvA.HandshakedPeersCount() == 1 && srvB.HandshakedPeersCount() == 1
+		}, 2*time.Second, 10*time.Millisecond)
+
+		// Blocks are synchronized via the encrypted connection.
+		require.Eventually(t, func() bool {
+			return srvB.chain.BlockHeight() == blocks[len(blocks)-1]
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestRoutedTransport(t *testing.T) {
+	plain := &fakeTransp{dialCh: make(chan string, 1)}
+	secure := &fakeTransp{dialCh: make(chan string, 1)}
+	r := newRoutedTransport(plain, secure, []string{"1.1.1.1:20333", "node.example.com:20333"})
+
+	for addr, expected := range map[string]*fakeTransp{
+		"2.2.2.2:10333":          plain,
+		"1.1.1.1:10333":          secure,
+		"1.1.1.1:20333":          secure,
+		"node.example.com:10333": secure,
+		"node.example.org:10333": plain,
+	} {
+		require.NoError(t, r.Dial(addr, time.Second))
+		select {
+		case a := <-expected.dialCh:
+			if expected == secure {
+				require.Contains(t, []string{"1.1.1.1:20333", "node.example.com:20333"}, a)
+			} else {
+				require.Equal(t, addr, a)
+			}
+		default:
+			t.Fatalf("%s was dialed via the wrong transport", addr)
+		}
+	}
+	require.Equal(t, "", r.Proto()) // Plain transport methods are used.
+}
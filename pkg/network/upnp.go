@@ -0,0 +1,303 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpAddress = "239.255.255.250:1900"
+	// upnpDiscoveryTimeout is the time to wait for SSDP responses.
+	upnpDiscoveryTimeout = 2 * time.Second
+	// upnpRequestTimeout limits HTTP requests to the gateway.
+	upnpRequestTimeout = 5 * time.Second
+	// upnpMappingDescription is the port mapping description shown by routers.
+	upnpMappingDescription = "neo-go"
+	// upnpMaxResponseSize limits device description and SOAP responses.
+	upnpMaxResponseSize = 1 << 20
+)
+
+// upnpIGDs are the device types searched for.
+var upnpIGDs = []string{
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:1",
+	"urn:schemas-upnp-org:device:InternetGatewayDevice:2",
+}
+
+type (
+	// upnpIGD is a client of UPnP Internet Gateway Device WAN connection
+	// service.
+	upnpIGD struct {
+		client      *http.Client
+		controlURL  string
+		serviceType string
+		// localIP is the address of the node in the gateway's network.
+		localIP net.IP
+	}
+
+	// upnpDevice is an UPnP device description.
+	upnpDevice struct {
+		DeviceType string        `xml:"deviceType"`
+		Services   []upnpService `xml:"serviceList>service"`
+		Devices    []upnpDevice  `xml:"deviceList>device"`
+	}
+
+	upnpService struct {
+		ServiceType string `xml:"serviceType"`
+		ControlURL  string `xml:"controlURL"`
+	}
+
+	upnpRoot struct {
+		URLBase string     `xml:"URLBase"`
+		Device  upnpDevice `xml:"device"`
+	}
+)
+
+// discoverUPnP searches for the Internet Gateway Device in the local network
+// with SSDP.
+func discoverUPnP() (*upnpIGD, error) {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range upnpIGDs {
+		req := "M-SEARCH * HTTP/1.1\r\n" +
+			"HOST: " + ssdpAddress + "\r\n" +
+			"ST: " + st + "\r\n" +
+			"MAN: \"ssdp:discover\"\r\n" +
+			"MX: 2\r\n\r\n"
+		if _, err := conn.WriteTo([]byte(req), dst); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		buf      = make([]byte, 2048)
+		deadline = time.Now().Add(upnpDiscoveryTimeout)
+		lastErr  = errors.New("no UPnP gateway found")
+	)
+	_ = conn.SetReadDeadline(deadline)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			return nil, lastErr
+		}
+		loc, err := parseSSDPResponse(buf[:n], from)
+		if err != nil {
+			continue
+		}
+		igd, err := newUPnPIGD(loc)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return igd, nil
+	}
+}
+
+// parseSSDPResponse returns the device description URL from the SSDP search
+// response received from the given address. The description is only accepted
+// from the device that has sent the response.
+func parseSSDPResponse(data []byte, from net.Addr) (string, error) {
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		return "", fmt.Errorf("bad SSDP response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad SSDP response status: %d", resp.StatusCode)
+	}
+	loc := resp.Header.Get("Location")
+	if loc == "" {
+		return "", errors.New("no location in SSDP response")
+	}
+	u, err := url.Parse(loc)
+	if err != nil {
+		return "", fmt.Errorf("bad SSDP location: %w", err)
+	}
+	if u.Scheme != "http" {
+		return "", fmt.Errorf("bad SSDP location scheme: %q", u.Scheme)
+	}
+	udpAddr, ok := from.(*net.UDPAddr)
+	if !ok || !udpAddr.IP.Equal(net.ParseIP(u.Hostname())) {
+		return "", fmt.Errorf("SSDP location %s doesn't match the sender %s", loc, from)
+	}
+	return loc, nil
+}
+
+// newUPnPIGD creates a client for the device with the given description URL.
+func newUPnPIGD(location string) (*upnpIGD, error) {
+	var client = &http.Client{Timeout: upnpRequestTimeout}
+
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get UPnP device description: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get UPnP device description: HTTP %d", resp.StatusCode)
+	}
+	var root upnpRoot
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, upnpMaxResponseSize)).Decode(&root); err != nil {
+		return nil, fmt.Errorf("bad UPnP device description: %w", err)
+	}
+	svc := root.Device.wanConnection()
+	if svc == nil {
+		return nil, errors.New("no WAN connection service found in UPnP device description")
+	}
+
+	base := root.URLBase
+	if base == "" {
+		base = location
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("bad UPnP device URL: %w", err)
+	}
+	ctrl, err := baseURL.Parse(svc.ControlURL)
+	if err != nil {
+		return nil, fmt.Errorf("bad UPnP control URL: %w", err)
+	}
+	port := ctrl.Port()
+	if port == "" {
+		port = "80"
+	}
+	localIP, err := outboundIP(net.JoinHostPort(ctrl.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	return &upnpIGD{
+		client:      client,
+		controlURL:  ctrl.String(),
+		serviceType: svc.ServiceType,
+		localIP:     localIP,
+	}, nil
+}
+
+// wanConnection returns WANIPConnection or WANPPPConnection service of the
+// device or its embedded devices.
+func (d *upnpDevice) wanConnection() *upnpService {
+	for i := range d.Services {
+		t := d.Services[i].ServiceType
+		if strings.HasPrefix(t, "urn:schemas-upnp-org:service:WANIPConnection:") ||
+			strings.HasPrefix(t, "urn:schemas-upnp-org:service:WANPPPConnection:") {
+			return &d.Services[i]
+		}
+	}
+	for i := range d.Devices {
+		if s := d.Devices[i].wanConnection(); s != nil {
+			return s
+		}
+	}
+	return nil
+}
+
+// String implements the natMapper interface.
+func (u *upnpIGD) String() string {
+	return "upnp"
+}
+
+// soapArg is a named SOAP action argument, the order matters.
+type soapArg struct {
+	name, value string
+}
+
+// call performs SOAP action and returns the response body.
+func (u *upnpIGD) call(action string, args ...soapArg) ([]byte, error) {
+	var body bytes.Buffer
+
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body><u:` + action + ` xmlns:u="` + u.serviceType + `">`)
+	for _, a := range args {
+		body.WriteString("<" + a.name + ">")
+		_ = xml.EscapeText(&body, []byte(a.value))
+		body.WriteString("</" + a.name + ">")
+	}
+	body.WriteString(`</u:` + action + `></s:Body></s:Envelope>`)
+
+	req, err := http.NewRequest("POST", u.controlURL, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+u.serviceType+"#"+action+`"`)
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("UPnP %s failed: HTTP %d", action, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, upnpMaxResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > upnpMaxResponseSize {
+		return nil, fmt.Errorf("UPnP %s response is too big", action)
+	}
+	return data, nil
+}
+
+// ExternalIP implements the natMapper interface.
+func (u *upnpIGD) ExternalIP() (net.IP, error) {
+	data, err := u.call("GetExternalIPAddress")
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		IP string `xml:"Body>GetExternalIPAddressResponse>NewExternalIPAddress"`
+	}
+	if err := xml.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("bad UPnP GetExternalIPAddress response: %w", err)
+	}
+	ip := net.ParseIP(resp.IP)
+	if ip == nil || ip.IsUnspecified() {
+		return nil, fmt.Errorf("bad external IP address: %q", resp.IP)
+	}
+	return ip, nil
+}
+
+// AddMapping implements the natMapper interface.
+func (u *upnpIGD) AddMapping(internal, external uint16, lifetime time.Duration) (uint16, error) {
+	_, err := u.call("AddPortMapping",
+		soapArg{"NewRemoteHost", ""},
+		soapArg{"NewExternalPort", strconv.FormatUint(uint64(external), 10)},
+		soapArg{"NewProtocol", "TCP"},
+		soapArg{"NewInternalPort", strconv.FormatUint(uint64(internal), 10)},
+		soapArg{"NewInternalClient", u.localIP.String()},
+		soapArg{"NewEnabled", "1"},
+		soapArg{"NewPortMappingDescription", upnpMappingDescription},
+		soapArg{"NewLeaseDuration", strconv.FormatInt(int64(lifetime/time.Second), 10)},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return external, nil
+}
+
+// DeleteMapping implements the natMapper interface.
+func (u *upnpIGD) DeleteMapping(_, external uint16) error {
+	_, err := u.call("DeletePortMapping",
+		soapArg{"NewRemoteHost", ""},
+		soapArg{"NewExternalPort", strconv.FormatUint(uint64(external), 10)},
+		soapArg{"NewProtocol", "TCP"},
+	)
+	return err
+}
@@ -109,6 +109,9 @@ func New(ra RPCActor, signers []SignerAccount) (*Actor, error) {
 		if signers[i].Account.Contract == nil {
 			return nil, fmt.Errorf("empty contract for account %s", signers[i].Account.Address)
 		}
+		if !signers[i].Account.Contract.Deployed && len(signers[i].Account.Contract.Script) == 0 {
+			return nil, fmt.Errorf("no verification script for account %s", signers[i].Account.Address)
+		}
 		if !signers[i].Account.Contract.Deployed && signers[i].Account.Contract.ScriptHash() != signers[i].Signer.Account {
 			return nil, fmt.Errorf("signer account doesn't match script hash for signer %s", signers[i].Account.Address)
 		}
@@ -137,7 +140,7 @@ func New(ra RPCActor, signers []SignerAccount) (*Actor, error) {
 func NewSimple(ra RPCActor, acc *wallet.Account) (*Actor, error) {
 	return New(ra, []SignerAccount{{
 		Signer: transaction.Signer{
-			Account: acc.Contract.ScriptHash(),
+			Account: acc.ScriptHash(),
 			Scopes:  transaction.CalledByEntry,
 		},
 		Account: acc,
@@ -190,6 +193,12 @@ func (a *Actor) GetNetwork() netmode.Magic {
 	return a.version.Protocol.Network
 }
 
+// GetSigners returns a copy of the list of signers (with their accounts)
+// used by this Actor.
+func (a *Actor) GetSigners() []SignerAccount {
+	return append([]SignerAccount(nil), a.signers...)
+}
+
 // GetVersion returns version data from the RPC endpoint.
 func (a *Actor) GetVersion() result.Version {
 	return *a.version
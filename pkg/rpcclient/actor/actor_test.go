@@ -103,9 +103,19 @@ func TestNew(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, len(a.signers))
 	require.Equal(t, 1, len(a.txSigners))
+	require.Equal(t, a.signers, a.GetSigners())
 	require.Equal(t, transaction.CalledByEntry, a.signers[0].Signer.Scopes)
 	require.Equal(t, transaction.CalledByEntry, a.txSigners[0].Scopes)
 
+	// Watch-only accounts.
+	watchOnly, err := wallet.NewWatchOnlyAccount(acc.Address)
+	require.NoError(t, err)
+	_, err = NewSimple(client, watchOnly)
+	require.Error(t, err)
+	a, err = NewSimple(client, wallet.NewWatchOnlyAccountFromPublicKey(acc.PublicKey()))
+	require.NoError(t, err)
+	require.Equal(t, acc.ScriptHash(), a.signers[0].Signer.Account)
+
 	// Contractless account.
 	badAcc, err := wallet.NewAccount()
 	require.NoError(t, err)
@@ -189,8 +189,12 @@ func (a *Actor) MakeUnsignedUncheckedRun(script []byte, sysFee int64, attrs []tr
 
 	tx.Scripts = make([]transaction.Witness, len(a.signers))
 	for i := range a.signers {
-		if !a.signers[i].Account.Contract.Deployed {
-			tx.Scripts[i].VerificationScript = a.signers[i].Account.Contract.Script
+		acc := a.signers[i].Account
+		if !acc.Contract.Deployed {
+			tx.Scripts[i].VerificationScript = acc.Contract.Script
+		} else if inv, err := acc.DummyInvocationScript(); err == nil {
+			// Real values can make verification more expensive than default ones.
+			tx.Scripts[i].InvocationScript = inv
 		}
 	}
 	// CalculateNetworkFee doesn't call Hash or Size, only serializes the
@@ -0,0 +1,201 @@
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// errBatchNotSupported is returned by batch request functions when the
+// server doesn't reply to a batch request with an array of responses.
+var errBatchNotSupported = errors.New("batch requests are not supported")
+
+// Batch accumulates RPC calls to be sent in a single JSON-RPC 2.0 batch
+// request. It's created with Client.Batch, calls are added with Add (or
+// helper methods like InvokeFunction) and sent with Send. If the server
+// doesn't support batch requests (or the client is a WSClient), calls are
+// performed one by one. Batch is not thread-safe.
+type Batch struct {
+	c     *Client
+	calls []batchCall
+}
+
+type batchCall struct {
+	method string
+	params []interface{}
+	result interface{}
+}
+
+// BatchResult is a result of a single batched call.
+type BatchResult struct {
+	// Result is the value passed to Add (*result.Invoke for invocations)
+	// filled with the call result. It's nil if Error is not nil.
+	Result interface{}
+	// Error is the call error (usually *neorpc.Error returned by server).
+	Error error
+}
+
+// Batch returns a new empty batch of calls.
+func (c *Client) Batch() *Batch {
+	return &Batch{c: c}
+}
+
+// Len returns the number of calls in the batch.
+func (b *Batch) Len() int {
+	return len(b.calls)
+}
+
+// Add adds a call of the given method with the given parameters to the batch,
+// the result is to be unmarshaled into v (which should be a pointer).
+func (b *Batch) Add(method string, params []interface{}, v interface{}) *Batch {
+	if params == nil {
+		params = []interface{}{} // neo-project/neo-modules#742
+	}
+	b.calls = append(b.calls, batchCall{method: method, params: params, result: v})
+	return b
+}
+
+// InvokeFunction adds invokefunction call to the batch, see
+// Client.InvokeFunction. The result is *result.Invoke.
+func (b *Batch) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) *Batch {
+	var p = []interface{}{contract.StringLE(), operation, params}
+	if signers != nil {
+		p = append(p, signers)
+	}
+	return b.Add("invokefunction", p, new(result.Invoke))
+}
+
+// InvokeScript adds invokescript call to the batch, see Client.InvokeScript.
+// The result is *result.Invoke.
+func (b *Batch) InvokeScript(script []byte, signers []transaction.Signer) *Batch {
+	var p = []interface{}{script}
+	if signers != nil {
+		p = append(p, signers)
+	}
+	return b.Add("invokescript", p, new(result.Invoke))
+}
+
+// Send is the same as SendContext with the background context.
+func (b *Batch) Send() ([]BatchResult, error) {
+	return b.SendContext(context.Background())
+}
+
+// SendContext executes all calls of the batch and returns their results in
+// the same order. An error is only returned if the request can't be
+// performed at all, errors of particular calls are returned in their
+// results. The batch is emptied after sending.
+func (b *Batch) SendContext(ctx context.Context) ([]BatchResult, error) {
+	var calls = b.calls
+	b.calls = nil
+	if len(calls) == 0 {
+		return nil, nil
+	}
+	var reqs = make([]neorpc.Request, len(calls))
+	for i := range calls {
+		reqs[i] = neorpc.Request{
+			JSONRPC: neorpc.JSONRPCVersion,
+			Method:  calls[i].method,
+			Params:  calls[i].params,
+			ID:      b.c.getNextRequestID(),
+		}
+	}
+
+	var (
+		resps []*neorpc.Response
+		err   = errBatchNotSupported
+	)
+	if b.c.batchF != nil && !b.c.noBatch.Load() {
+		resps, err = b.c.batchF(ctx, reqs)
+		if errors.Is(err, errBatchNotSupported) {
+			b.c.noBatch.Store(true)
+		}
+	}
+	if errors.Is(err, errBatchNotSupported) {
+		resps = make([]*neorpc.Response, len(reqs))
+		for i := range reqs {
+			resps[i], err = b.c.requestF(ctx, &reqs[i])
+			if err != nil && (resps[i] == nil || resps[i].Error == nil) {
+				return nil, err
+			}
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	var res = make([]BatchResult, len(calls))
+	for i, raw := range resps {
+		switch {
+		case raw == nil:
+			res[i].Error = errors.New("no response returned")
+		case raw.Error != nil:
+			res[i].Error = raw.Error
+		case raw.Result == nil:
+			res[i].Error = errors.New("no result returned")
+		default:
+			res[i].Error = json.Unmarshal(raw.Result, calls[i].result)
+			if res[i].Error == nil {
+				res[i].Result = calls[i].result
+			}
+		}
+	}
+	return res, nil
+}
+
+// makeHTTPBatchRequest sends the given requests as a single batch, responses
+// are returned in the same order as requests (nil for missing ones).
+func (c *Client) makeHTTPBatchRequest(ctx context.Context, reqs []neorpc.Request) ([]*neorpc.Response, error) {
+	var buf = new(bytes.Buffer)
+
+	if err := json.NewEncoder(buf).Encode(reqs); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint.String(), buf)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw json.RawMessage
+	err = json.NewDecoder(resp.Body).Decode(&raw)
+	if err != nil || len(raw) == 0 || raw[0] != '[' {
+		// Either a single error response or something that is not
+		// JSON-RPC at all (like HTTP 404 page), batch requests are not
+		// supported by this server anyway.
+		if err != nil && resp.StatusCode == http.StatusOK {
+			return nil, fmt.Errorf("JSON decoding: %w", err)
+		}
+		return nil, errBatchNotSupported
+	}
+	var batch []neorpc.Response
+	if err := json.Unmarshal(raw, &batch); err != nil {
+		return nil, fmt.Errorf("JSON decoding: %w", err)
+	}
+
+	// Responses can be returned in any order, so they're matched by ID.
+	var (
+		res = make([]*neorpc.Response, len(reqs))
+		ids = make(map[string]int, len(reqs))
+	)
+	for i := range reqs {
+		ids[fmt.Sprint(reqs[i].ID)] = i
+	}
+	for i := range batch {
+		if j, ok := ids[string(batch[i].ID)]; ok {
+			res[j] = &batch[i]
+		}
+	}
+	return res, nil
+}
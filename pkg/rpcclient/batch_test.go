@@ -0,0 +1,136 @@
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func batchTestResponse(r neorpc.Request) string {
+	switch r.Method {
+	case "getblockcount":
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":42}`, r.ID)
+	case "invokescript":
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":{"state":"HALT","gasconsumed":"10","script":"EQ==","stack":[{"type":"Integer","value":"1"}]}}`, r.ID)
+	default:
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"Method not found"}}`, r.ID)
+	}
+}
+
+func newBatchTestServer(t *testing.T, supportBatch bool, requests *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Inc()
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if body[0] == '[' {
+			if !supportBatch {
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request"}}`))
+				return
+			}
+			var reqs []neorpc.Request
+			require.NoError(t, json.Unmarshal(body, &reqs))
+			// Responses are returned in reverse order to check matching.
+			resp := "["
+			for i := len(reqs) - 1; i >= 0; i-- {
+				resp += batchTestResponse(reqs[i])
+				if i != 0 {
+					resp += ","
+				}
+			}
+			_, _ = w.Write([]byte(resp + "]"))
+			return
+		}
+		var r neorpc.Request
+		require.NoError(t, json.Unmarshal(body, &r))
+		_, _ = w.Write([]byte(batchTestResponse(r)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestBatch(t *testing.T) {
+	check := func(t *testing.T, res []BatchResult) {
+		require.Equal(t, 3, len(res))
+
+		require.NoError(t, res[0].Error)
+		require.Equal(t, uint32(42), *res[0].Result.(*uint32))
+
+		var rpcErr *neorpc.Error
+		require.ErrorAs(t, res[1].Error, &rpcErr)
+		require.Equal(t, int64(-32601), rpcErr.Code)
+		require.Nil(t, res[1].Result)
+
+		require.NoError(t, res[2].Error)
+		inv := res[2].Result.(*result.Invoke)
+		require.Equal(t, "HALT", inv.State)
+		require.Equal(t, 1, len(inv.Stack))
+		require.Equal(t, big.NewInt(1), inv.Stack[0].Value())
+	}
+	fill := func(b *Batch) {
+		b.Add("getblockcount", nil, new(uint32)).
+			InvokeFunction(util.Uint160{1, 2, 3}, "method", nil, nil).
+			InvokeScript([]byte{0x11}, nil)
+	}
+
+	t.Run("supported", func(t *testing.T) {
+		var requests = atomic.NewInt32(0)
+		srv := newBatchTestServer(t, true, requests)
+		c, err := New(context.Background(), srv.URL, Options{})
+		require.NoError(t, err)
+
+		b := c.Batch()
+		res, err := b.Send()
+		require.NoError(t, err)
+		require.Nil(t, res)
+		require.Equal(t, int32(0), requests.Load())
+
+		fill(b)
+		require.Equal(t, 3, b.Len())
+		res, err = b.Send()
+		require.NoError(t, err)
+		check(t, res)
+		require.Equal(t, int32(1), requests.Load())
+		require.Equal(t, 0, b.Len())
+	})
+	t.Run("not supported", func(t *testing.T) {
+		var requests = atomic.NewInt32(0)
+		srv := newBatchTestServer(t, false, requests)
+		c, err := New(context.Background(), srv.URL, Options{})
+		require.NoError(t, err)
+
+		b := c.Batch()
+		fill(b)
+		res, err := b.Send()
+		require.NoError(t, err)
+		check(t, res)
+		require.Equal(t, int32(4), requests.Load()) // Failed batch and 3 single requests.
+
+		// No batch requests are made after the first failure.
+		fill(b)
+		res, err = b.Send()
+		require.NoError(t, err)
+		check(t, res)
+		require.Equal(t, int32(7), requests.Load())
+	})
+	t.Run("connection error", func(t *testing.T) {
+		var requests = atomic.NewInt32(0)
+		srv := newBatchTestServer(t, true, requests)
+		c, err := New(context.Background(), srv.URL, Options{})
+		require.NoError(t, err)
+		srv.Close()
+
+		_, err = c.Batch().Add("getblockcount", nil, new(uint32)).Send()
+		require.Error(t, err)
+	})
+}
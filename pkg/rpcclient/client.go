@@ -37,7 +37,12 @@ type Client struct {
 	// ctx.
 	ctxCancel func()
 	opts      Options
-	requestF  func(*neorpc.Request) (*neorpc.Response, error)
+	requestF  func(context.Context, *neorpc.Request) (*neorpc.Response, error)
+	// batchF sends batch requests, it's nil if they're not supported by
+	// the transport.
+	batchF func(context.Context, []neorpc.Request) ([]*neorpc.Response, error)
+	// noBatch is set when the server doesn't support batch requests.
+	noBatch atomic.Bool
 
 	// reader is an Invoker that has no signers and uses current state,
 	// it's used to implement various getters. It'll be removed eventually,
@@ -140,6 +145,7 @@ func initClient(ctx context.Context, cl *Client, endpoint string, opts Options)
 	cl.getNextRequestID = (cl).getRequestID
 	cl.opts = opts
 	cl.requestF = cl.makeHTTPRequest
+	cl.batchF = cl.makeHTTPBatchRequest
 	cl.reader = invoker.New(cl, nil)
 	return nil
 }
@@ -186,6 +192,12 @@ func (c *Client) Close() {
 }
 
 func (c *Client) performRequest(method string, p []interface{}, v interface{}) error {
+	return c.performRequestContext(context.Background(), method, p, v)
+}
+
+// performRequestContext is the same as performRequest, but allows to cancel
+// the request via the given context.
+func (c *Client) performRequestContext(ctx context.Context, method string, p []interface{}, v interface{}) error {
 	if p == nil {
 		p = []interface{}{} // neo-project/neo-modules#742
 	}
@@ -196,7 +208,7 @@ func (c *Client) performRequest(method string, p []interface{}, v interface{}) e
 		ID:      c.getNextRequestID(),
 	}
 
-	raw, err := c.requestF(&r)
+	raw, err := c.requestF(ctx, &r)
 
 	if raw != nil && raw.Error != nil {
 		return raw.Error
@@ -208,7 +220,7 @@ func (c *Client) performRequest(method string, p []interface{}, v interface{}) e
 	return json.Unmarshal(raw.Result, v)
 }
 
-func (c *Client) makeHTTPRequest(r *neorpc.Request) (*neorpc.Response, error) {
+func (c *Client) makeHTTPRequest(ctx context.Context, r *neorpc.Request) (*neorpc.Response, error) {
 	var (
 		buf = new(bytes.Buffer)
 		raw = new(neorpc.Response)
@@ -218,7 +230,7 @@ func (c *Client) makeHTTPRequest(r *neorpc.Request) (*neorpc.Response, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.endpoint.String(), buf)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint.String(), buf)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +47,9 @@ These layers are:
     example of how contract-specific wrappers can be built for other dApps
     (reusing invoker/actor layers it's pretty easy).
 
+Runnable examples of using these layers together can be found in the examples
+subpackage.
+
 # Client
 
 After creating a client instance with or without a ClientConfig
@@ -112,5 +115,12 @@ Unsupported methods
 	sendfrom
 	sendmany
 	sendtoaddress
+
+# Batch requests
+
+Multiple calls can be sent in a single JSON-RPC 2.0 batch request using Batch,
+results are returned in the same order calls were added with an error for each
+of them. If the server doesn't support batch requests (and for WSClient), calls
+are performed one by one transparently.
 */
 package rpcclient
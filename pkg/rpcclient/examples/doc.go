@@ -0,0 +1,8 @@
+/*
+Package examples contains runnable examples of rpcclient package and its
+subpackages usage (transfers, multisignature transactions, contract deployment,
+historic invocations and WebSocket subscriptions). They're run as tests against
+an in-process chain with an RPC server, so they're kept separate from other
+rpcclient tests. This package has no code of its own.
+*/
+package examples
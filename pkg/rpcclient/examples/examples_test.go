@@ -0,0 +1,425 @@
+package examples_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/internal/testchain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/network"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/actor"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/gas"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/management"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/neo"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/services/rpcsrv"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	sccontext "github.com/nspcc-dev/neo-go/pkg/smartcontract/context"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"go.uber.org/zap"
+)
+
+// Examples below are run against the in-process unit test chain with an RPC
+// server serving it (see TestMain), blocks are produced as soon as there are
+// transactions in the mempool. In real code endpoint is the node's RPC
+// address, keys are taken from wallets.
+var (
+	// endpoint is the HTTP RPC server address.
+	endpoint string
+	// wsEndpoint is the WebSocket RPC server address.
+	wsEndpoint string
+	// senderKey is the key of some account with GAS.
+	senderKey *keys.PrivateKey
+	// validatorKeys are the keys of the standby validators that own all NEO
+	// and GAS at the genesis via their 3-of-4 multisignature account.
+	validatorKeys []*keys.PrivateKey
+)
+
+func Example_gasTransfer() {
+	ctx := context.Background()
+	c, err := rpcclient.New(ctx, endpoint, rpcclient.Options{})
+	if err != nil {
+		panic(err)
+	}
+	// Actor needs network magic and other parameters from the node, it
+	// gets them itself, so no Init call is needed for this client. The
+	// account given is the sender (the first signer paying fees), use
+	// actor.New for more signers.
+	a, err := actor.NewSimple(c, wallet.NewAccountFromPrivateKey(senderKey))
+	if err != nil {
+		panic(err)
+	}
+
+	receiver := util.Uint160{1, 2, 3}
+	gasToken := gas.New(a)
+	// Transfer returns right after the transaction is sent, Wait polls the
+	// node until the transaction is accepted (or its ValidUntilBlock
+	// passes).
+	res, err := a.Wait(gasToken.Transfer(a.Sender(), receiver, big.NewInt(1_0000_0000), nil))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.VMState)
+
+	balance, err := gasToken.BalanceOf(receiver)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(balance)
+	// Output:
+	// HALT
+	// 100000000
+}
+
+func Example_multisigTransfer() {
+	ctx := context.Background()
+	c, err := rpcclient.New(ctx, endpoint, rpcclient.Options{})
+	if err != nil {
+		panic(err)
+	}
+
+	// Every party has its own key, but all of them use the same multisig
+	// account (3 signatures out of 4 keys are needed).
+	pubs := make(keys.PublicKeys, 0, len(validatorKeys))
+	for _, k := range validatorKeys {
+		pubs = append(pubs, k.PublicKey())
+	}
+	multisigAccount := func(k *keys.PrivateKey) *wallet.Account {
+		acc := wallet.NewAccountFromPrivateKey(k)
+		if err := acc.ConvertMultisig(3, pubs); err != nil {
+			panic(err)
+		}
+		return acc
+	}
+
+	// The first party creates an unsigned transaction. Actor calculates
+	// the network fee properly for multisig accounts, but can't sign it
+	// with a single key.
+	a, err := actor.NewSimple(c, multisigAccount(validatorKeys[0]))
+	if err != nil {
+		panic(err)
+	}
+	receiver := util.Uint160{3, 2, 1}
+	tx, err := neo.New(a).TransferUnsigned(a.Sender(), receiver, big.NewInt(10), nil)
+	if err != nil {
+		panic(err)
+	}
+	// Parameter context is a JSON-serializable container used to collect
+	// signatures, it's the same format as used by the CLI.
+	pc := sccontext.NewParameterContext(sccontext.TransactionType, a.GetNetwork(), tx)
+	data, err := json.Marshal(pc)
+	if err != nil {
+		panic(err)
+	}
+
+	// Every party gets the context, checks the transaction inside
+	// (not shown here), adds its signature and passes it further.
+	for _, k := range validatorKeys[:3] {
+		pc := new(sccontext.ParameterContext)
+		if err := json.Unmarshal(data, pc); err != nil {
+			panic(err)
+		}
+		acc := multisigAccount(k)
+		tx := pc.Verifiable.(*transaction.Transaction)
+		sig := acc.SignHashable(pc.Network, tx)
+		if err := pc.AddSignature(acc.ScriptHash(), acc.Contract, k.PublicKey(), sig); err != nil {
+			panic(err)
+		}
+		data, err = json.Marshal(pc)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// The last one (or anyone else) gets the complete transaction from it
+	// and sends it.
+	pc = new(sccontext.ParameterContext)
+	if err := json.Unmarshal(data, pc); err != nil {
+		panic(err)
+	}
+	tx, err = pc.GetCompleteTransaction()
+	if err != nil {
+		panic(err)
+	}
+	res, err := a.Wait(a.Send(tx))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.VMState)
+
+	balance, err := neo.NewReader(a).BalanceOf(receiver)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(balance)
+	// Output:
+	// HALT
+	// 10
+}
+
+// NumberContract is a typed wrapper for the contract deployed in
+// Example_deployment, it's the kind of code generated by the
+// `contract generate-rpcwrapper` CLI command.
+type NumberContract struct {
+	invoker invoker.RPCInvoke
+	hash    util.Uint160
+}
+
+// GetNumber invokes `getNumber` method of the contract.
+func (c *NumberContract) GetNumber() (*big.Int, error) {
+	return unwrap.BigInt(invoker.New(c.invoker, nil).Call(c.hash, "getNumber"))
+}
+
+func Example_deployment() {
+	ctx := context.Background()
+	c, err := rpcclient.New(ctx, endpoint, rpcclient.Options{})
+	if err != nil {
+		panic(err)
+	}
+	a, err := actor.NewSimple(c, wallet.NewAccountFromPrivateKey(senderKey))
+	if err != nil {
+		panic(err)
+	}
+
+	// NEF and manifest are usually produced by the compiler, this contract
+	// is just a "return 42" script.
+	w := io.NewBufBinWriter()
+	emit.Int(w.BinWriter, 42)
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	exe, err := nef.NewFile(w.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	manif := manifest.NewManifest("Number")
+	manif.ABI.Methods = []manifest.Method{{
+		Name:       "getNumber",
+		Parameters: []manifest.Parameter{},
+		ReturnType: smartcontract.IntegerType,
+		Safe:       true,
+	}}
+
+	res, err := a.Wait(management.New(a).Deploy(exe, manif, nil))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(res.VMState)
+
+	// The contract hash depends on the sender, so it's known in advance.
+	h := state.CreateContractHash(a.Sender(), exe.Checksum, manif.Name)
+	number, err := (&NumberContract{invoker: c, hash: h}).GetNumber()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(number)
+	// Output:
+	// HALT
+	// 42
+}
+
+func Example_historicInvocation() {
+	ctx := context.Background()
+	c, err := rpcclient.New(ctx, endpoint, rpcclient.Options{})
+	if err != nil {
+		panic(err)
+	}
+	a, err := actor.NewSimple(c, wallet.NewAccountFromPrivateKey(senderKey))
+	if err != nil {
+		panic(err)
+	}
+
+	// Remember the height before the transfer.
+	count, err := c.GetBlockCount()
+	if err != nil {
+		panic(err)
+	}
+	receiver := util.Uint160{4, 5, 6}
+	_, err = a.Wait(gas.New(a).Transfer(a.Sender(), receiver, big.NewInt(5), nil))
+	if err != nil {
+		panic(err)
+	}
+
+	// Historic invoker performs all calls against the state at the given
+	// height (the node must not be configured to keep only the latest state).
+	past := gas.NewReader(invoker.NewHistoricAtHeight(count-1, c, nil))
+	before, err := past.BalanceOf(receiver)
+	if err != nil {
+		panic(err)
+	}
+	now, err := gas.NewReader(a).BalanceOf(receiver)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(before, now)
+	// Output:
+	// 0 5
+}
+
+func Example_wsExecutions() {
+	ctx := context.Background()
+	c, err := rpcclient.NewWS(ctx, wsEndpoint, rpcclient.Options{})
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	// WSClient is a regular Client as well, so it can be used for Actor
+	// with EventWaiter used in this case instead of a polling one.
+	a, err := actor.NewSimple(c, wallet.NewAccountFromPrivateKey(senderKey))
+	if err != nil {
+		panic(err)
+	}
+
+	// Events are delivered to the channel that must be read from,
+	// otherwise the client is blocked. It's closed when the connection is
+	// lost.
+	halt := "HALT"
+	execs := make(chan *state.AppExecResult, 10)
+	id, err := c.ReceiveExecutions(&neorpc.ExecutionFilter{State: &halt}, execs)
+	if err != nil {
+		panic(err)
+	}
+
+	txid, _, err := gas.New(a).Transfer(a.Sender(), util.Uint160{7, 8, 9}, big.NewInt(1), nil)
+	if err != nil {
+		panic(err)
+	}
+	for exec := range execs {
+		// Block executions are sent too, only our transaction is needed.
+		if exec.Container.Equals(txid) {
+			fmt.Println(exec.VMState)
+			break
+		}
+	}
+
+	if err := c.Unsubscribe(id); err != nil {
+		panic(err)
+	}
+	// Output:
+	// HALT
+}
+
+func TestMain(m *testing.M) {
+	cfg, err := config.Load("../../../config", netmode.UnitTestNet)
+	if err != nil {
+		panic(err)
+	}
+	// Blocks are produced on demand, but waiters poll the node depending
+	// on the block time.
+	cfg.ProtocolConfiguration.SecondsPerBlock = 1
+	log := zap.NewNop()
+	chain, err := core.NewBlockchain(storage.NewMemoryStore(), cfg.ProtocolConfiguration, log)
+	if err != nil {
+		panic(err)
+	}
+	go chain.Run()
+
+	netSrv, err := network.NewServer(network.NewServerConfig(cfg), chain, chain.GetStateSyncModule(), log)
+	if err != nil {
+		panic(err)
+	}
+	rpcSrv := rpcsrv.New(chain, cfg.ApplicationConfiguration.RPC, netSrv, nil, log, make(chan error, 2))
+	rpcSrv.Start()
+	endpoint = "http://" + rpcSrv.Addr
+	wsEndpoint = "ws://" + rpcSrv.Addr + "/ws"
+
+	for i := 0; i < testchain.ValidatorsCount; i++ {
+		validatorKeys = append(validatorKeys, testchain.PrivateKey(i))
+	}
+	senderKey, err = keys.NewPrivateKey()
+	if err != nil {
+		panic(err)
+	}
+	fundSender(chain)
+
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go produceBlocks(chain, quit, done)
+
+	code := m.Run()
+
+	close(quit)
+	<-done
+	rpcSrv.Shutdown()
+	chain.Close()
+	os.Exit(code)
+}
+
+// fundSender transfers some GAS from the validators to the senderKey account.
+func fundSender(chain *core.Blockchain) {
+	tx, err := testchain.NewTransferFromOwner(chain, chain.UtilityTokenHash(),
+		senderKey.GetScriptHash(), 1000_0000_0000, 0, chain.BlockHeight()+100)
+	if err != nil {
+		panic(err)
+	}
+	if err := chain.AddBlock(newBlock(chain, tx)); err != nil {
+		panic(err)
+	}
+}
+
+// produceBlocks adds a block with all mempooled transactions as soon as
+// there are any of them.
+func produceBlocks(chain *core.Blockchain, quit <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+		txs := chain.GetMemPool().GetVerifiedTransactions()
+		if len(txs) == 0 {
+			continue
+		}
+		if err := chain.AddBlock(newBlock(chain, txs...)); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// newBlock creates the next block signed by the validators.
+func newBlock(chain *core.Blockchain, txs ...*transaction.Transaction) *block.Block {
+	witness := transaction.Witness{VerificationScript: testchain.MultisigVerificationScript()}
+	prev, err := chain.GetHeader(chain.CurrentBlockHash())
+	if err != nil {
+		panic(err)
+	}
+	ts := uint64(time.Now().UnixMilli())
+	if ts <= prev.Timestamp {
+		ts = prev.Timestamp + 1
+	}
+	b := &block.Block{
+		Header: block.Header{
+			PrevHash:      prev.Hash(),
+			Timestamp:     ts,
+			Index:         prev.Index + 1,
+			NextConsensus: witness.ScriptHash(),
+			Script:        witness,
+		},
+		Transactions: txs,
+	}
+	b.RebuildMerkleRoot()
+	b.Script.InvocationScript = testchain.Sign(b)
+	return b
+}
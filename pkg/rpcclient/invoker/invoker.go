@@ -15,10 +15,12 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 )
 
 // DefaultIteratorResultItems is the default number of results to
@@ -27,6 +29,22 @@ import (
 // settings.
 const DefaultIteratorResultItems = 100
 
+// ErrSessionsDisabled is returned from CallAndIterate when the RPC server
+// doesn't support sessions and the iterator it returned was truncated, so not
+// all of its values can be retrieved.
+var ErrSessionsDisabled = errors.New("iterator is truncated and RPC server has sessions disabled")
+
+// HistoricStateError is returned from historic calls when the RPC server
+// doesn't have the requested state (it can be pruned or not yet known to the
+// server), it contains the nearest height the state is available for that
+// can be used with NewHistoricAtHeight.
+type HistoricStateError struct {
+	// Nearest is the nearest height the state is available for.
+	Nearest uint32
+	// Err is the original error returned from the server.
+	Err error
+}
+
 // RPCSessions is a set of RPC methods needed to retrieve values from the
 // session-based iterators.
 type RPCSessions interface {
@@ -99,32 +117,51 @@ func NewHistoricWithState(rootOrBlock util.Uint256, client RPCInvokeHistoric, si
 	}, signers)
 }
 
+// Error implements the error interface.
+func (e *HistoricStateError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error returned from the server.
+func (e *HistoricStateError) Unwrap() error {
+	return e.Err
+}
+
+// convertErr converts historic call errors for unavailable states into
+// HistoricStateError.
+func (h *historicConverter) convertErr(res *result.Invoke, err error) (*result.Invoke, error) {
+	if nearest, ok := neorpc.NearestStateHeight(err); ok {
+		return nil, &HistoricStateError{Nearest: nearest, Err: err}
+	}
+	return res, err
+}
+
 func (h *historicConverter) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	if h.height != nil {
-		return h.client.InvokeScriptAtHeight(*h.height, script, signers)
+		return h.convertErr(h.client.InvokeScriptAtHeight(*h.height, script, signers))
 	}
 	if h.root != nil {
-		return h.client.InvokeScriptWithState(*h.root, script, signers)
+		return h.convertErr(h.client.InvokeScriptWithState(*h.root, script, signers))
 	}
 	panic("uninitialized historicConverter")
 }
 
 func (h *historicConverter) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
 	if h.height != nil {
-		return h.client.InvokeFunctionAtHeight(*h.height, contract, operation, params, signers)
+		return h.convertErr(h.client.InvokeFunctionAtHeight(*h.height, contract, operation, params, signers))
 	}
 	if h.root != nil {
-		return h.client.InvokeFunctionWithState(*h.root, contract, operation, params, signers)
+		return h.convertErr(h.client.InvokeFunctionWithState(*h.root, contract, operation, params, signers))
 	}
 	panic("uninitialized historicConverter")
 }
 
 func (h *historicConverter) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	if h.height != nil {
-		return h.client.InvokeContractVerifyAtHeight(*h.height, contract, params, signers, witnesses...)
+		return h.convertErr(h.client.InvokeContractVerifyAtHeight(*h.height, contract, params, signers, witnesses...))
 	}
 	if h.root != nil {
-		return h.client.InvokeContractVerifyWithState(*h.root, contract, params, signers, witnesses...)
+		return h.convertErr(h.client.InvokeContractVerifyWithState(*h.root, contract, params, signers, witnesses...))
 	}
 	panic("uninitialized historicConverter")
 }
@@ -223,3 +260,59 @@ func iterateNext(rpc RPCSessions, sessionID uuid.UUID, iterator *result.Iterator
 
 	return items, nil
 }
+
+// CallAndIterate invokes a method of the contract with the given parameters
+// (similar to Call) expecting it to return an iterator and then retrieves all
+// values of this iterator requesting them in batches of pageSize elements (if
+// pageSize <= 0 then DefaultIteratorResultItems is used). The session opened
+// by the invocation is always terminated before return. If the RPC server has
+// sessions disabled, values expanded by the server are returned if they're
+// complete and ErrSessionsDisabled is returned if they're truncated. Be
+// careful with iterators holding lots of elements, all of them are kept in
+// memory, use Call with TraverseIterator if you need to process them one batch
+// at a time.
+func (v *Invoker) CallAndIterate(contract util.Uint160, method string, pageSize int, params ...interface{}) ([]stackitem.Item, error) {
+	r, err := v.Call(contract, method, params...)
+	if err != nil {
+		return nil, err
+	}
+	if (r.Session != uuid.UUID{}) {
+		defer func() {
+			_ = v.TerminateSession(r.Session)
+		}()
+	}
+	if r.State != vmstate.Halt.String() {
+		return nil, fmt.Errorf("invocation failed: %s", r.FaultException)
+	}
+	if len(r.Stack) != 1 {
+		return nil, fmt.Errorf("expected 1 result item, got %d", len(r.Stack))
+	}
+	iter, ok := r.Stack[0].Value().(result.Iterator)
+	if !ok || r.Stack[0].Type() != stackitem.InteropT {
+		return nil, fmt.Errorf("expected iterator, got %s", r.Stack[0].Type())
+	}
+	if iter.ID == nil {
+		if iter.Truncated {
+			return nil, ErrSessionsDisabled
+		}
+		return iter.Values, nil
+	}
+	if (r.Session == uuid.UUID{}) {
+		return nil, errors.New("server returned iterator ID, but no session ID")
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultIteratorResultItems
+	}
+	var res []stackitem.Item
+	for {
+		items, err := v.TraverseIterator(r.Session, &iter, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to traverse iterator: %w", err)
+		}
+		res = append(res, items...)
+		if len(items) < pageSize {
+			break
+		}
+	}
+	return res, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -18,6 +19,8 @@ type rpcInv struct {
 	resTrm bool
 	resItm []stackitem.Item
 	err    error
+	// terminated contains IDs of terminated sessions.
+	terminated []uuid.UUID
 }
 
 func (r *rpcInv) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
@@ -48,6 +51,7 @@ func (r *rpcInv) InvokeScriptWithState(stateroot util.Uint256, script []byte, si
 	return r.resInv, r.err
 }
 func (r *rpcInv) TerminateSession(sessionID uuid.UUID) (bool, error) {
+	r.terminated = append(r.terminated, sessionID)
 	return r.resTrm, r.err
 }
 func (r *rpcInv) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsCount int) ([]stackitem.Item, error) {
@@ -56,7 +60,7 @@ func (r *rpcInv) TraverseIterator(sessionID, iteratorID uuid.UUID, maxItemsCount
 
 func TestInvoker(t *testing.T) {
 	resExp := &result.Invoke{State: "HALT"}
-	ri := &rpcInv{resExp, true, nil, nil}
+	ri := &rpcInv{resExp, true, nil, nil, nil}
 
 	testInv := func(t *testing.T, inv *Invoker) {
 		res, err := inv.Call(util.Uint160{}, "method")
@@ -105,6 +109,29 @@ func TestInvoker(t *testing.T) {
 	t.Run("historic, state", func(t *testing.T) {
 		testInv(t, NewHistoricWithState(util.Uint256{}, ri, nil))
 	})
+	t.Run("historic, unavailable state", func(t *testing.T) {
+		ri := &rpcInv{err: neorpc.NewUnsupportedStateError(5, 10)}
+		for _, inv := range []*Invoker{
+			NewHistoricAtHeight(5, ri, nil),
+			NewHistoricWithState(util.Uint256{}, ri, nil),
+		} {
+			var stErr *HistoricStateError
+			_, err := inv.Call(util.Uint160{}, "method")
+			require.ErrorAs(t, err, &stErr)
+			require.Equal(t, uint32(10), stErr.Nearest)
+			require.ErrorIs(t, err, neorpc.ErrUnsupportedState)
+
+			_, err = inv.Verify(util.Uint160{}, nil)
+			require.ErrorAs(t, err, &stErr)
+			_, err = inv.Run([]byte{1})
+			require.ErrorAs(t, err, &stErr)
+		}
+
+		ri.err = errors.New("some error")
+		_, err := NewHistoricAtHeight(5, ri, nil).Call(util.Uint160{}, "method")
+		require.Error(t, err)
+		require.False(t, errors.As(err, new(*HistoricStateError)))
+	})
 	t.Run("broken historic", func(t *testing.T) {
 		inv := New(&historicConverter{client: ri}, nil) // It's not possible to do this from outside.
 		require.Panics(t, func() { _, _ = inv.Call(util.Uint160{}, "method") })
@@ -157,4 +184,64 @@ func TestInvoker(t *testing.T) {
 			require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res)
 		}
 	})
+	t.Run("call and iterate", func(t *testing.T) {
+		inv := New(ri, nil)
+		iid := uuid.New()
+		sid := uuid.New()
+
+		ri.err = errors.New("")
+		_, err := inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.Error(t, err)
+		ri.err = nil
+
+		ri.resInv = &result.Invoke{State: "FAULT", FaultException: "oops"}
+		_, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.Error(t, err)
+
+		// Session is terminated even if the result can't be used.
+		ri.terminated = nil
+		ri.resInv = &result.Invoke{State: "FAULT", FaultException: "oops", Session: sid}
+		_, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.Error(t, err)
+		ri.resInv = &result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42)}, Session: sid}
+		_, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.Error(t, err)
+		require.Equal(t, []uuid.UUID{sid, sid}, ri.terminated)
+
+		ri.resInv = &result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42)}}
+		_, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.Error(t, err)
+
+		ri.resInv = &result.Invoke{State: "HALT", Stack: []stackitem.Item{
+			stackitem.NewInterop(result.Iterator{
+				Values: []stackitem.Item{stackitem.Make(42)},
+			}),
+		}}
+		res, err := inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res)
+
+		ri.resInv = &result.Invoke{State: "HALT", Stack: []stackitem.Item{
+			stackitem.NewInterop(result.Iterator{
+				Values:    []stackitem.Item{stackitem.Make(42)},
+				Truncated: true,
+			}),
+		}}
+		_, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.ErrorIs(t, err, ErrSessionsDisabled)
+
+		ri.resInv = &result.Invoke{State: "HALT", Stack: []stackitem.Item{
+			stackitem.NewInterop(result.Iterator{ID: &iid}),
+		}}
+		_, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.Error(t, err) // No session ID.
+
+		ri.resInv.Session = sid
+		ri.resItm = []stackitem.Item{stackitem.Make(42)}
+		ri.terminated = nil
+		res, err = inv.CallAndIterate(util.Uint160{}, "method", 2)
+		require.NoError(t, err)
+		require.Equal(t, []stackitem.Item{stackitem.Make(42)}, res)
+		require.Equal(t, []uuid.UUID{sid}, ri.terminated)
+	})
 }
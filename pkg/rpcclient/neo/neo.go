@@ -248,7 +248,10 @@ func (c *ContractReader) GetRegisterPrice() (int64, error) {
 // UnclaimedGas allows to calculate the amount of GAS that will be generated if
 // any NEO state change ("claim") is to happen for the given account at the given
 // block number. This method is mostly useful for historic invocations because
-// the RPC protocol provides direct getunclaimedgas method that works faster.
+// the RPC protocol provides direct getunclaimedgas method that works faster
+// (see rpcclient.Client.GetUnclaimedGas and GetUnclaimedGasVerbose, the latter
+// also returns a breakdown of the amount into the NEO holder and voter
+// rewards).
 func (c *ContractReader) UnclaimedGas(account util.Uint160, end uint32) (*big.Int, error) {
 	return unwrap.BigInt(c.invoker.Call(Hash, "unclaimedGas", account, end))
 }
@@ -42,6 +42,8 @@ type Actor struct {
 	reader   *ContractReader
 	sender   *wallet.Account
 	rpc      RPCActor
+	depOpts  *DepositOptions
+	depActor *actor.Actor
 }
 
 // ActorOptions are used to influence main and fallback actors as well as the
@@ -76,6 +78,9 @@ type ActorOptions struct {
 	// ValidUntilBlock transaction's field. Only override it if you know
 	// what you're doing.
 	MainModifier actor.TransactionModifier
+	// Deposit enables automatic deposit top-up before sending notary
+	// requests (see DepositOptions for details), it's disabled by default.
+	Deposit *DepositOptions
 }
 
 // RPCActor is a set of methods required from RPC client to create Actor.
@@ -92,7 +97,7 @@ func NewDefaultActorOptions(reader *ContractReader, acc *wallet.Account) ActorOp
 		FbScript: []byte{byte(opcode.RET)},
 		FbSigner: actor.SignerAccount{
 			Signer: transaction.Signer{
-				Account: acc.Contract.ScriptHash(),
+				Account: acc.ScriptHash(),
 				Scopes:  transaction.None,
 			},
 			Account: acc,
@@ -222,7 +227,14 @@ func newTunedActor(c RPCActor, signers []actor.SignerAccount, simpleAcc *wallet.
 	if err != nil {
 		return nil, err
 	}
-	return &Actor{*mainActor, *fbActor, opts.FbScript, reader, simpleAcc, c}, nil
+	var depActor *actor.Actor
+	if opts.Deposit != nil {
+		depActor, err = actor.NewSimple(c, simpleAcc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Actor{*mainActor, *fbActor, opts.FbScript, reader, simpleAcc, c, opts.Deposit, depActor}, nil
 }
 
 // Notarize is a simple wrapper for transaction-creating functions that allows to
@@ -289,21 +301,29 @@ func (a *Actor) SendRequest(mainTx *transaction.Transaction, fbTx *transaction.T
 // transactions, creates a P2P notary request containing them, signs and sends
 // it to the network. Caller takes full responsibility for transaction
 // correctness in this case, use this method only if you know exactly that you
-// need to override some of the other method's behavior and you can do it. The
-// values returned are main and fallback transaction hashes, ValidUntilBlock
-// and error if any.
+// need to override some of the other method's behavior and you can do it. If
+// deposit top-up is enabled (see ActorOptions.Deposit), it's performed before
+// sending the request. The values returned are main and fallback transaction
+// hashes, ValidUntilBlock and error if any.
 func (a *Actor) SendRequestExactly(mainTx *transaction.Transaction, fbTx *transaction.Transaction) (util.Uint256, util.Uint256, uint32, error) {
 	var (
 		fbHash   = fbTx.Hash()
 		mainHash = mainTx.Hash()
 		vub      = mainTx.ValidUntilBlock
 	)
+	if err := a.ensureDeposit(fbTx); err != nil {
+		return mainHash, fbHash, vub, err
+	}
 	req := &payload.P2PNotaryRequest{
 		MainTransaction:     mainTx,
 		FallbackTransaction: fbTx,
 	}
+	sig, err := a.sender.SignHashableContext(context.Background(), a.GetNetwork(), req)
+	if err != nil {
+		return mainHash, fbHash, vub, fmt.Errorf("failed to sign notary request: %w", err)
+	}
 	req.Witness = transaction.Witness{
-		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, a.sender.SignHashable(a.GetNetwork(), req)...),
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
 		VerificationScript: a.sender.GetVerificationScript(),
 	}
 	actualHash, err := a.rpc.SubmitP2PNotaryRequest(req)
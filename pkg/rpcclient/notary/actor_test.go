@@ -127,6 +127,18 @@ func TestNewActor(t *testing.T) {
 	}}, accs[0])
 	require.Error(t, err)
 
+	// Watch-only account (empty script).
+	watchOnly, err := wallet.NewWatchOnlyAccount(accs[0].Address)
+	require.NoError(t, err)
+	_, err = NewActor(rc, []actor.SignerAccount{{
+		Signer: transaction.Signer{
+			Account: accs[0].ScriptHash(),
+			Scopes:  transaction.CalledByEntry,
+		},
+		Account: watchOnly,
+	}}, watchOnly)
+	require.Error(t, err)
+
 	// Non-standard script.
 	badMultiAcc0.Contract = &wallet.Contract{}
 	*badMultiAcc0.Contract = *multiAccs[0].Contract
@@ -584,3 +596,95 @@ func TestWait(t *testing.T) {
 		Execution: ex,
 	}, res)
 }
+
+func TestDepositTopUp(t *testing.T) {
+	rc := &RPCClient{
+		version: &result.Version{
+			Protocol: result.Protocol{
+				Network:              netmode.UnitTestNet,
+				MillisecondsPerBlock: 1,
+				ValidatorsCount:      7,
+			},
+		},
+		invRes: &result.Invoke{
+			State:       "HALT",
+			GasConsumed: 3,
+			Script:      []byte{byte(opcode.RET)},
+			Stack:       []stackitem.Item{stackitem.Make(100)}, // Both balance and expiration.
+		},
+		mirror: true,
+	}
+
+	key0, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	acc0 := wallet.NewAccountFromPrivateKey(key0)
+	var confirmed *transaction.Transaction
+	opts := NewDefaultActorOptions(NewReader(invoker.New(rc, nil)), acc0)
+	opts.Deposit = &DepositOptions{
+		Threshold: 50,
+		Amount:    1000,
+		TillDelta: 10,
+		Confirm: func(tx *transaction.Transaction) error {
+			confirmed = tx
+			return nil
+		},
+	}
+	act, err := NewTunedActor(rc, []actor.SignerAccount{{
+		Signer: transaction.Signer{
+			Account: acc0.Contract.ScriptHash(),
+			Scopes:  transaction.None,
+		},
+		Account: acc0,
+	}}, opts)
+	require.NoError(t, err)
+
+	mainTx := transaction.New([]byte{byte(opcode.RET)}, 1)
+	fbTx, err := act.FbActor.MakeUnsignedRun([]byte{byte(opcode.RET)}, nil)
+	require.NoError(t, err)
+
+	// Deposit is fine.
+	fbTx.ValidUntilBlock = 99
+	_, _, _, err = act.SendRequestExactly(mainTx, fbTx)
+	require.NoError(t, err)
+	require.Nil(t, confirmed)
+
+	// Deposit expires too early, top-up is cancelled.
+	fbTx.ValidUntilBlock = 100
+	opts.Deposit.Confirm = func(tx *transaction.Transaction) error {
+		confirmed = tx
+		return errors.New("no")
+	}
+	_, _, _, err = act.SendRequestExactly(mainTx, fbTx)
+	require.Error(t, err)
+	require.NotNil(t, confirmed)
+	require.Equal(t, acc0.ScriptHash(), confirmed.Sender())
+
+	// Top-up confirmed, but failed.
+	confirmed = nil
+	opts.Deposit.Confirm = nil
+	rc.applog = &result.ApplicationLog{
+		IsTransaction: true,
+		Executions: []state.Execution{{
+			Trigger: trigger.Application,
+			VMState: vmstate.Fault,
+		}},
+	}
+	_, _, _, err = act.SendRequestExactly(mainTx, fbTx)
+	require.Error(t, err)
+
+	// Top-up OK.
+	rc.applog.Executions[0].VMState = vmstate.Halt
+	_, _, _, err = act.SendRequestExactly(mainTx, fbTx)
+	require.NoError(t, err)
+
+	// Low balance.
+	fbTx.ValidUntilBlock = 10
+	opts.Deposit.Threshold = 200
+	opts.Deposit.Confirm = func(tx *transaction.Transaction) error {
+		confirmed = tx
+		return nil
+	}
+	_, _, _, err = act.SendRequestExactly(mainTx, fbTx)
+	require.NoError(t, err)
+	require.NotNil(t, confirmed)
+}
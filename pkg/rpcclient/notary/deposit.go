@@ -0,0 +1,109 @@
+package notary
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/gas"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/nep17"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+// DepositOptions configures automatic deposit top-up performed by Actor before
+// sending notary requests. Fallback transactions are paid for from the deposit
+// of their second signer, so if it's too low (or expires too early) requests
+// are rejected by the network. With these options set Actor checks the deposit
+// of this signer before every request and transfers some GAS from the sender
+// account to the Notary contract if needed.
+type DepositOptions struct {
+	// Threshold is the minimum deposit balance (in GAS fractions), top-up
+	// is made if the current one is lower than that.
+	Threshold int64
+	// Amount is the amount of GAS (in fractions) transferred to the Notary
+	// contract for top-up.
+	Amount int64
+	// TillDelta is the number of blocks (counting from the fallback
+	// transaction's ValidUntilBlock) the deposit is to be locked for after
+	// top-up. Top-up is also made when the deposit expires before the
+	// fallback transaction does, in this case the lock is extended
+	// irrespective of the balance.
+	TillDelta uint32
+	// Confirm, if set, is called with the (unsigned) top-up transaction
+	// before signing and sending it. A non-nil error returned from it
+	// cancels the top-up and the notary request. It can also be used to
+	// adjust the transaction (fees for example).
+	Confirm func(tx *transaction.Transaction) error
+}
+
+// DepositTransferUnsigned creates a GAS transfer transaction from the given
+// account to the Notary contract that adds the specified amount to the deposit
+// of the "to" account (or "from" if it's nil) and locks the deposit until the
+// "till" block. Notice that only the deposit owner can change its lock height,
+// transfers made for other accounts use the current (or default for new
+// deposits) one. The transaction is not signed and just returned to the
+// caller.
+func DepositTransferUnsigned(act nep17.Actor, from util.Uint160, to *util.Uint160, amount *big.Int, till uint32) (*transaction.Transaction, error) {
+	return gas.New(act).TransferUnsigned(from, Hash, amount, depositData(to, till))
+}
+
+// depositData returns onNEP17Payment data for the Notary contract, it can't be
+// OnNEP17PaymentData because transfer parameters are to be emittable.
+func depositData(to *util.Uint160, till uint32) []interface{} {
+	var acc interface{}
+	if to != nil {
+		acc = *to
+	}
+	return []interface{}{acc, int64(till)}
+}
+
+// ensureDeposit checks the deposit of the fallback transaction payer and tops
+// it up if needed according to DepositOptions. It waits for the top-up
+// transaction to be accepted, because notary requests are checked against the
+// current chain state.
+func (a *Actor) ensureDeposit(fbTx *transaction.Transaction) error {
+	if a.depOpts == nil {
+		return nil
+	}
+	if len(fbTx.Signers) < 2 {
+		return errors.New("invalid fallback: missing payer signer")
+	}
+	payer := fbTx.Signers[1].Account
+	balance, err := a.reader.BalanceOf(payer)
+	if err != nil {
+		return fmt.Errorf("failed to get deposit balance: %w", err)
+	}
+	expiration, err := a.reader.ExpirationOf(payer)
+	if err != nil {
+		return fmt.Errorf("failed to get deposit expiration: %w", err)
+	}
+	if balance.Cmp(big.NewInt(a.depOpts.Threshold)) >= 0 && expiration > fbTx.ValidUntilBlock {
+		return nil
+	}
+	till := fbTx.ValidUntilBlock + a.depOpts.TillDelta
+	if till <= fbTx.ValidUntilBlock {
+		till = fbTx.ValidUntilBlock + 1
+	}
+	if till < expiration {
+		till = expiration
+	}
+	tx, err := DepositTransferUnsigned(a.depActor, a.sender.ScriptHash(), &payer, big.NewInt(a.depOpts.Amount), till)
+	if err != nil {
+		return fmt.Errorf("failed to create deposit top-up transaction: %w", err)
+	}
+	if a.depOpts.Confirm != nil {
+		if err := a.depOpts.Confirm(tx); err != nil {
+			return fmt.Errorf("deposit top-up cancelled: %w", err)
+		}
+	}
+	aer, err := a.depActor.Wait(a.depActor.SignAndSend(tx))
+	if err != nil {
+		return fmt.Errorf("deposit top-up transaction failed: %w", err)
+	}
+	if aer.VMState != vmstate.Halt {
+		return fmt.Errorf("deposit top-up transaction failed: %s", aer.FaultException)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package rpcclient
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -50,6 +51,12 @@ func (c *Client) CalculateNetworkFee(tx *transaction.Transaction) (int64, error)
 
 // GetApplicationLog returns a contract log based on the specified txid.
 func (c *Client) GetApplicationLog(hash util.Uint256, trig *trigger.Type) (*result.ApplicationLog, error) {
+	return c.GetApplicationLogContext(context.Background(), hash, trig)
+}
+
+// GetApplicationLogContext is the same as GetApplicationLog,
+// but allows to cancel the request via the given context.
+func (c *Client) GetApplicationLogContext(ctx context.Context, hash util.Uint256, trig *trigger.Type) (*result.ApplicationLog, error) {
 	var (
 		params = []interface{}{hash.StringLE()}
 		resp   = new(result.ApplicationLog)
@@ -57,7 +64,7 @@ func (c *Client) GetApplicationLog(hash util.Uint256, trig *trigger.Type) (*resu
 	if trig != nil {
 		params = append(params, trig.String())
 	}
-	if err := c.performRequest("getapplicationlog", params, resp); err != nil {
+	if err := c.performRequestContext(ctx, "getapplicationlog", params, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -74,8 +81,14 @@ func (c *Client) GetBestBlockHash() (util.Uint256, error) {
 
 // GetBlockCount returns the number of blocks in the blockchain.
 func (c *Client) GetBlockCount() (uint32, error) {
+	return c.GetBlockCountContext(context.Background())
+}
+
+// GetBlockCountContext is the same as GetBlockCount,
+// but allows to cancel the request via the given context.
+func (c *Client) GetBlockCountContext(ctx context.Context) (uint32, error) {
 	var resp uint32
-	if err := c.performRequest("getblockcount", nil, &resp); err != nil {
+	if err := c.performRequestContext(ctx, "getblockcount", nil, &resp); err != nil {
 		return resp, err
 	}
 	return resp, nil
@@ -243,30 +256,48 @@ func (c *Client) GetCommittee() (keys.PublicKeys, error) {
 
 // GetContractStateByHash queries contract information according to the contract script hash.
 func (c *Client) GetContractStateByHash(hash util.Uint160) (*state.Contract, error) {
-	return c.getContractState(hash.StringLE())
+	return c.GetContractStateByHashContext(context.Background(), hash)
+}
+
+// GetContractStateByHashContext is the same as GetContractStateByHash,
+// but allows to cancel the request via the given context.
+func (c *Client) GetContractStateByHashContext(ctx context.Context, hash util.Uint160) (*state.Contract, error) {
+	return c.getContractState(ctx, hash.StringLE())
 }
 
 // GetContractStateByAddressOrName queries contract information using the contract
 // address or name. Notice that name-based queries work only for native contracts,
 // non-native ones can't be requested this way.
 func (c *Client) GetContractStateByAddressOrName(addressOrName string) (*state.Contract, error) {
-	return c.getContractState(addressOrName)
+	return c.GetContractStateByAddressOrNameContext(context.Background(), addressOrName)
+}
+
+// GetContractStateByAddressOrNameContext is the same as GetContractStateByAddressOrName,
+// but allows to cancel the request via the given context.
+func (c *Client) GetContractStateByAddressOrNameContext(ctx context.Context, addressOrName string) (*state.Contract, error) {
+	return c.getContractState(ctx, addressOrName)
 }
 
 // GetContractStateByID queries contract information according to the contract ID.
 // Notice that this is supported by all servers only for native contracts,
 // non-native ones can be requested only from NeoGo servers.
 func (c *Client) GetContractStateByID(id int32) (*state.Contract, error) {
-	return c.getContractState(id)
+	return c.GetContractStateByIDContext(context.Background(), id)
+}
+
+// GetContractStateByIDContext is the same as GetContractStateByID,
+// but allows to cancel the request via the given context.
+func (c *Client) GetContractStateByIDContext(ctx context.Context, id int32) (*state.Contract, error) {
+	return c.getContractState(ctx, id)
 }
 
 // getContractState is an internal representation of GetContractStateBy* methods.
-func (c *Client) getContractState(param interface{}) (*state.Contract, error) {
+func (c *Client) getContractState(ctx context.Context, param interface{}) (*state.Contract, error) {
 	var (
 		params = []interface{}{param}
 		resp   = &state.Contract{}
 	)
-	if err := c.performRequest("getcontractstate", params, resp); err != nil {
+	if err := c.performRequestContext(ctx, "getcontractstate", params, resp); err != nil {
 		return resp, err
 	}
 	return resp, nil
@@ -395,6 +426,32 @@ func (c *Client) GetNEP17Transfers(address util.Uint160, start, stop *uint64, li
 	return resp, nil
 }
 
+// GetNotaryPool returns P2PNotaryRequests pooled by the node grouped by main
+// transactions (only hashes are returned, see GetNotaryPoolVerbose for
+// complete transactions). It's a NeoGo-specific method that only works with
+// nodes having P2PSigExtensions enabled.
+func (c *Client) GetNotaryPool() ([]result.NotaryPoolRequest, error) {
+	var resp []result.NotaryPoolRequest
+
+	if err := c.performRequest("getnotarypool", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetNotaryPoolVerbose is the same as GetNotaryPool, but it also returns main
+// and fallback transactions.
+func (c *Client) GetNotaryPoolVerbose() ([]result.NotaryPoolRequest, error) {
+	var (
+		params = []interface{}{1} // 1 for verbose.
+		resp   []result.NotaryPoolRequest
+	)
+	if err := c.performRequest("getnotarypool", params, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // GetPeers returns a list of the nodes that the node is currently connected to/disconnected from.
 func (c *Client) GetPeers() (*result.GetPeers, error) {
 	var resp = &result.GetPeers{}
@@ -405,6 +462,19 @@ func (c *Client) GetPeers() (*result.GetPeers, error) {
 	return resp, nil
 }
 
+// GetPeersVerbose is the same as GetPeers, but it also returns quality scores
+// of connected peers. It's a NeoGo-specific extension.
+func (c *Client) GetPeersVerbose() (*result.GetPeers, error) {
+	var (
+		params = []interface{}{true}
+		resp   = &result.GetPeers{}
+	)
+	if err := c.performRequest("getpeers", params, resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
 // GetRawMemPool returns a list of unconfirmed transactions in the memory.
 func (c *Client) GetRawMemPool() ([]util.Uint256, error) {
 	var resp = new([]util.Uint256)
@@ -488,6 +558,34 @@ func (c *Client) FindStates(stateroot util.Uint256, historicalContractHash util.
 	return resp, nil
 }
 
+// GetProof returns existence proof of storage item state by the given stateroot,
+// historical contract hash and historical item key. The proof can be checked
+// locally with result.ProofWithKey.Verify against a trusted state root.
+func (c *Client) GetProof(stateroot util.Uint256, historicalContractHash util.Uint160, historicalKey []byte) (*result.ProofWithKey, error) {
+	var (
+		params = []interface{}{stateroot.StringLE(), historicalContractHash.StringLE(), historicalKey}
+		resp   = new(result.ProofWithKey)
+	)
+	if err := c.performRequest("getproof", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// VerifyProof returns the value of storage item proven by the given proof
+// against the given stateroot. The verification is performed by the RPC
+// server, nil value is returned if the proof is invalid.
+func (c *Client) VerifyProof(stateroot util.Uint256, proof *result.ProofWithKey) ([]byte, error) {
+	var (
+		params = []interface{}{stateroot.StringLE(), proof.String()}
+		resp   = new(result.VerifyProof)
+	)
+	if err := c.performRequest("verifyproof", params, resp); err != nil {
+		return nil, err
+	}
+	return resp.Value, nil
+}
+
 // GetStateRootByHeight returns the state root for the specified height.
 func (c *Client) GetStateRootByHeight(height uint32) (*state.MPTRoot, error) {
 	return c.getStateRoot(height)
@@ -558,6 +656,21 @@ func (c *Client) GetUnclaimedGas(address string) (result.UnclaimedGas, error) {
 	return resp, nil
 }
 
+// GetUnclaimedGasVerbose is the same as GetUnclaimedGas, but it also returns
+// a breakdown of the amount into the NEO holder reward for every range of
+// blocks with the same GAS per block value and the voter reward. It's
+// a NeoGo-specific extension.
+func (c *Client) GetUnclaimedGasVerbose(address string) (result.UnclaimedGas, error) {
+	var (
+		params = []interface{}{address, true}
+		resp   result.UnclaimedGas
+	)
+	if err := c.performRequest("getunclaimedgas", params, &resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
 // GetCandidates returns the current list of NEO candidate node with voting data and
 // validator status.
 func (c *Client) GetCandidates() ([]result.Candidate, error) {
@@ -592,8 +705,44 @@ func (c *Client) GetVersion() (*result.Version, error) {
 // InvokeScript returns the result of the given script after running it true the VM.
 // NOTE: This is a test invoke and will not affect the blockchain.
 func (c *Client) InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeScriptContext(context.Background(), script, signers)
+}
+
+// InvokeScriptContext is the same as InvokeScript,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeScriptContext(ctx context.Context, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = []interface{}{script}
+	return c.invokeSomething(ctx, "invokescript", p, signers)
+}
+
+// InvokeScriptDiagnostics is the same as InvokeScript, but also requests
+// diagnostic data (Diagnostics field of the result): invocation tree, storage
+// changes and GAS consumption breakdown.
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptDiagnostics(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeScriptDiagnosticsContext(context.Background(), script, signers)
+}
+
+// InvokeScriptDiagnosticsContext is the same as InvokeScriptDiagnostics,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeScriptDiagnosticsContext(ctx context.Context, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = []interface{}{script}
+	return c.invokeDiagnostics(ctx, "invokescript", p, signers)
+}
+
+// InvokeScriptTrace is the same as InvokeScript, but also returns VM execution
+// trace (Trace field of the result). It requires the node to have tracing
+// enabled.
+// NOTE: This is a test invoke and will not affect the blockchain.
+func (c *Client) InvokeScriptTrace(script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeScriptTraceContext(context.Background(), script, signers)
+}
+
+// InvokeScriptTraceContext is the same as InvokeScriptTrace,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeScriptTraceContext(ctx context.Context, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	var p = []interface{}{script}
-	return c.invokeSomething("invokescript", p, signers)
+	return c.invokeSomething(ctx, "invokescripttrace", p, signers)
 }
 
 // InvokeScriptAtHeight returns the result of the given script after running it
@@ -601,8 +750,14 @@ func (c *Client) InvokeScript(script []byte, signers []transaction.Signer) (*res
 // height.
 // NOTE: This is a test invoke and will not affect the blockchain.
 func (c *Client) InvokeScriptAtHeight(height uint32, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeScriptAtHeightContext(context.Background(), height, script, signers)
+}
+
+// InvokeScriptAtHeightContext is the same as InvokeScriptAtHeight,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeScriptAtHeightContext(ctx context.Context, height uint32, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	var p = []interface{}{height, script}
-	return c.invokeSomething("invokescripthistoric", p, signers)
+	return c.invokeSomething(ctx, "invokescripthistoric", p, signers)
 }
 
 // InvokeScriptWithState returns the result of the given script after running it
@@ -610,16 +765,46 @@ func (c *Client) InvokeScriptAtHeight(height uint32, script []byte, signers []tr
 // state root or block hash.
 // NOTE: This is a test invoke and will not affect the blockchain.
 func (c *Client) InvokeScriptWithState(stateOrBlock util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeScriptWithStateContext(context.Background(), stateOrBlock, script, signers)
+}
+
+// InvokeScriptWithStateContext is the same as InvokeScriptWithState,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeScriptWithStateContext(ctx context.Context, stateOrBlock util.Uint256, script []byte, signers []transaction.Signer) (*result.Invoke, error) {
 	var p = []interface{}{stateOrBlock.StringLE(), script}
-	return c.invokeSomething("invokescripthistoric", p, signers)
+	return c.invokeSomething(ctx, "invokescripthistoric", p, signers)
 }
 
 // InvokeFunction returns the results after calling the smart contract scripthash
 // with the given operation and parameters.
 // NOTE: this is test invoke and will not affect the blockchain.
 func (c *Client) InvokeFunction(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeFunctionContext(context.Background(), contract, operation, params, signers)
+}
+
+// InvokeFunctionContext is the same as InvokeFunction,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeFunctionContext(ctx context.Context, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	var p = []interface{}{contract.StringLE(), operation, params}
+	return c.invokeSomething(ctx, "invokefunction", p, signers)
+}
+
+// InvokeFunctionDiagnostics is the same as InvokeFunction, but also requests
+// diagnostic data (Diagnostics field of the result): invocation tree, storage
+// changes and GAS consumption breakdown.
+// NOTE: this is test invoke and will not affect the blockchain.
+func (c *Client) InvokeFunctionDiagnostics(contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeFunctionDiagnosticsContext(context.Background(), contract, operation, params, signers)
+}
+
+// InvokeFunctionDiagnosticsContext is the same as InvokeFunctionDiagnostics,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeFunctionDiagnosticsContext(ctx context.Context, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	if params == nil {
+		params = []smartcontract.Parameter{}
+	}
 	var p = []interface{}{contract.StringLE(), operation, params}
-	return c.invokeSomething("invokefunction", p, signers)
+	return c.invokeDiagnostics(ctx, "invokefunction", p, signers)
 }
 
 // InvokeFunctionAtHeight returns the results after calling the smart contract
@@ -627,8 +812,14 @@ func (c *Client) InvokeFunction(contract util.Uint160, operation string, params
 // specified by the blockchain height.
 // NOTE: this is test invoke and will not affect the blockchain.
 func (c *Client) InvokeFunctionAtHeight(height uint32, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeFunctionAtHeightContext(context.Background(), height, contract, operation, params, signers)
+}
+
+// InvokeFunctionAtHeightContext is the same as InvokeFunctionAtHeight,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeFunctionAtHeightContext(ctx context.Context, height uint32, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
 	var p = []interface{}{height, contract.StringLE(), operation, params}
-	return c.invokeSomething("invokefunctionhistoric", p, signers)
+	return c.invokeSomething(ctx, "invokefunctionhistoric", p, signers)
 }
 
 // InvokeFunctionWithState returns the results after calling the smart contract
@@ -636,16 +827,28 @@ func (c *Client) InvokeFunctionAtHeight(height uint32, contract util.Uint160, op
 // by the specified state root or block hash.
 // NOTE: this is test invoke and will not affect the blockchain.
 func (c *Client) InvokeFunctionWithState(stateOrBlock util.Uint256, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
+	return c.InvokeFunctionWithStateContext(context.Background(), stateOrBlock, contract, operation, params, signers)
+}
+
+// InvokeFunctionWithStateContext is the same as InvokeFunctionWithState,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeFunctionWithStateContext(ctx context.Context, stateOrBlock util.Uint256, contract util.Uint160, operation string, params []smartcontract.Parameter, signers []transaction.Signer) (*result.Invoke, error) {
 	var p = []interface{}{stateOrBlock.StringLE(), contract.StringLE(), operation, params}
-	return c.invokeSomething("invokefunctionhistoric", p, signers)
+	return c.invokeSomething(ctx, "invokefunctionhistoric", p, signers)
 }
 
 // InvokeContractVerify returns the results after calling `verify` method of the smart contract
 // with the given parameters under verification trigger type.
 // NOTE: this is test invoke and will not affect the blockchain.
 func (c *Client) InvokeContractVerify(contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return c.InvokeContractVerifyContext(context.Background(), contract, params, signers, witnesses...)
+}
+
+// InvokeContractVerifyContext is the same as InvokeContractVerify,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeContractVerifyContext(ctx context.Context, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var p = []interface{}{contract.StringLE(), params}
-	return c.invokeSomething("invokecontractverify", p, signers, witnesses...)
+	return c.invokeSomething(ctx, "invokecontractverify", p, signers, witnesses...)
 }
 
 // InvokeContractVerifyAtHeight returns the results after calling `verify` method
@@ -653,8 +856,14 @@ func (c *Client) InvokeContractVerify(contract util.Uint160, params []smartcontr
 // at the blockchain state specified by the blockchain height.
 // NOTE: this is test invoke and will not affect the blockchain.
 func (c *Client) InvokeContractVerifyAtHeight(height uint32, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return c.InvokeContractVerifyAtHeightContext(context.Background(), height, contract, params, signers, witnesses...)
+}
+
+// InvokeContractVerifyAtHeightContext is the same as InvokeContractVerifyAtHeight,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeContractVerifyAtHeightContext(ctx context.Context, height uint32, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var p = []interface{}{height, contract.StringLE(), params}
-	return c.invokeSomething("invokecontractverifyhistoric", p, signers, witnesses...)
+	return c.invokeSomething(ctx, "invokecontractverifyhistoric", p, signers, witnesses...)
 }
 
 // InvokeContractVerifyWithState returns the results after calling `verify` method
@@ -662,12 +871,18 @@ func (c *Client) InvokeContractVerifyAtHeight(height uint32, contract util.Uint1
 // at the blockchain state specified by the state root or block hash.
 // NOTE: this is test invoke and will not affect the blockchain.
 func (c *Client) InvokeContractVerifyWithState(stateOrBlock util.Uint256, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+	return c.InvokeContractVerifyWithStateContext(context.Background(), stateOrBlock, contract, params, signers, witnesses...)
+}
+
+// InvokeContractVerifyWithStateContext is the same as InvokeContractVerifyWithState,
+// but allows to cancel the request via the given context.
+func (c *Client) InvokeContractVerifyWithStateContext(ctx context.Context, stateOrBlock util.Uint256, contract util.Uint160, params []smartcontract.Parameter, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var p = []interface{}{stateOrBlock.StringLE(), contract.StringLE(), params}
-	return c.invokeSomething("invokecontractverifyhistoric", p, signers, witnesses...)
+	return c.invokeSomething(ctx, "invokecontractverifyhistoric", p, signers, witnesses...)
 }
 
 // invokeSomething is an inner wrapper for Invoke* functions.
-func (c *Client) invokeSomething(method string, p []interface{}, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
+func (c *Client) invokeSomething(ctx context.Context, method string, p []interface{}, signers []transaction.Signer, witnesses ...transaction.Witness) (*result.Invoke, error) {
 	var resp = new(result.Invoke)
 	if signers != nil {
 		if witnesses == nil {
@@ -686,7 +901,21 @@ func (c *Client) invokeSomething(method string, p []interface{}, signers []trans
 			p = append(p, signersWithWitnesses)
 		}
 	}
-	if err := c.performRequest(method, p, resp); err != nil {
+	if err := c.performRequestContext(ctx, method, p, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// invokeDiagnostics is the same as invokeSomething, but it always passes
+// signers (they precede the diagnostics flag) and sets the flag.
+func (c *Client) invokeDiagnostics(ctx context.Context, method string, p []interface{}, signers []transaction.Signer) (*result.Invoke, error) {
+	var resp = new(result.Invoke)
+	if signers == nil {
+		signers = []transaction.Signer{}
+	}
+	p = append(p, signers, true)
+	if err := c.performRequestContext(ctx, method, p, resp); err != nil {
 		return nil, err
 	}
 	return resp, nil
@@ -697,11 +926,17 @@ func (c *Client) invokeSomething(method string, p []interface{}, signers []trans
 // When the result of the response object is true, the TX has successfully
 // been broadcasted to the network.
 func (c *Client) SendRawTransaction(rawTX *transaction.Transaction) (util.Uint256, error) {
+	return c.SendRawTransactionContext(context.Background(), rawTX)
+}
+
+// SendRawTransactionContext is the same as SendRawTransaction,
+// but allows to cancel the request via the given context.
+func (c *Client) SendRawTransactionContext(ctx context.Context, rawTX *transaction.Transaction) (util.Uint256, error) {
 	var (
 		params = []interface{}{rawTX.Bytes()}
 		resp   = new(result.RelayResult)
 	)
-	if err := c.performRequest("sendrawtransaction", params, resp); err != nil {
+	if err := c.performRequestContext(ctx, "sendrawtransaction", params, resp); err != nil {
 		return util.Uint256{}, err
 	}
 	return resp.Hash, nil
@@ -709,6 +944,12 @@ func (c *Client) SendRawTransaction(rawTX *transaction.Transaction) (util.Uint25
 
 // SubmitBlock broadcasts a raw block over the NEO network.
 func (c *Client) SubmitBlock(b block.Block) (util.Uint256, error) {
+	return c.SubmitBlockContext(context.Background(), b)
+}
+
+// SubmitBlockContext is the same as SubmitBlock,
+// but allows to cancel the request via the given context.
+func (c *Client) SubmitBlockContext(ctx context.Context, b block.Block) (util.Uint256, error) {
 	var (
 		params []interface{}
 		resp   = new(result.RelayResult)
@@ -720,7 +961,7 @@ func (c *Client) SubmitBlock(b block.Block) (util.Uint256, error) {
 	}
 	params = []interface{}{buf.Bytes()}
 
-	if err := c.performRequest("submitblock", params, resp); err != nil {
+	if err := c.performRequestContext(ctx, "submitblock", params, resp); err != nil {
 		return util.Uint256{}, err
 	}
 	return resp.Hash, nil
@@ -918,8 +1159,12 @@ func (c *Client) SignAndPushP2PNotaryRequest(mainTx *transaction.Transaction, fa
 		MainTransaction:     mainTx,
 		FallbackTransaction: fallbackTx,
 	}
+	sig, err := acc.SignHashableContext(c.ctx, m, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign notary request: %w", err)
+	}
 	req.Witness = transaction.Witness{
-		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, acc.SignHashable(m, req)...),
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
 		VerificationScript: acc.GetVerificationScript(),
 	}
 	actualHash, err := c.SubmitP2PNotaryRequest(req)
@@ -36,6 +36,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/trace"
 	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -693,6 +694,23 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getnotarypool": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetNotaryPool()
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":[{"hash":"0x9786cce0dddb524c40ddbdd5e31a41ed1f6b5c8a683c122f627ca4a007a7cf4e","nkeys":3,"signatures":1,"fallbacks":["0xb4b5cfb23bfd2a2b1a9b5a3b3d8ab4e4b43ebc2d19a0a9b42dd2a8ad3ec2dfc8"]}]}`,
+			result: func(c *Client) interface{} {
+				return []result.NotaryPoolRequest{{
+					Hash:       util.Uint256{0x4e, 0xcf, 0xa7, 0x07, 0xa0, 0xa4, 0x7c, 0x62, 0x2f, 0x12, 0x3c, 0x68, 0x8a, 0x5c, 0x6b, 0x1f, 0xed, 0x41, 0x1a, 0xe3, 0xd5, 0xbd, 0xdd, 0x40, 0x4c, 0x52, 0xdb, 0xdd, 0xe0, 0xcc, 0x86, 0x97},
+					NKeys:      3,
+					Signatures: 1,
+					Fallbacks:  []util.Uint256{{0xc8, 0xdf, 0xc2, 0x3e, 0xad, 0xa8, 0xd2, 0x2d, 0xb4, 0xa9, 0xa0, 0x19, 0x2d, 0xbc, 0x3e, 0xb4, 0xe4, 0xb4, 0x8a, 0x3d, 0x3b, 0x5a, 0x9b, 0x1a, 0x2b, 0x2a, 0xfd, 0x3b, 0xb2, 0xcf, 0xb5, 0xb4}},
+				}}
+			},
+		},
+	},
 	"getpeers": {
 		{
 			name: "positive",
@@ -723,6 +741,31 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 				}
 			},
 		},
+		{
+			name: "verbose",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetPeersVerbose()
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"unconnected":[],"connected":[{"address":"127.0.0.1","port":"20335","score":{"score":-0.5,"invalidpayloads":0,"timeouts":0.25,"usefulblocks":100,"latency":250}}],"bad":[]}}`,
+			result: func(c *Client) interface{} {
+				return &result.GetPeers{
+					Unconnected: result.Peers{},
+					Connected: result.Peers{
+						{
+							Address: "127.0.0.1",
+							Port:    "20335",
+							Score: &result.PeerScore{
+								Score:        -0.5,
+								Timeouts:     0.25,
+								UsefulBlocks: 100,
+								Latency:      250,
+							},
+						},
+					},
+					Bad: result.Peers{},
+				}
+			},
+		},
 	},
 	"getrawmempool": {
 		{
@@ -817,6 +860,23 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getproof": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				cHash, _ := util.Uint160DecodeStringLE("5c9e40a12055c6b9e3f72271c9779958c842135d")
+				return c.GetProof(root, cHash, []byte("testkey"))
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":"AgECAQED"}`,
+			result: func(c *Client) interface{} {
+				return &result.ProofWithKey{
+					Key:   []byte{1, 2},
+					Proof: [][]byte{{3}},
+				}
+			},
+		},
+	},
 	"getstate": {
 		{
 			name: "positive",
@@ -945,6 +1005,27 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 				}
 			},
 		},
+		{
+			name: "verbose",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetUnclaimedGasVerbose("NMipL5VsNoLUBUJKPKLhxaEbPQVCZnyJyB")
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"address":"NMipL5VsNoLUBUJKPKLhxaEbPQVCZnyJyB","unclaimed":"1200","periods":[{"start":10,"end":20,"gasperblock":"500000000","amount":"1000"},{"start":20,"end":30,"gasperblock":"100000000","amount":"200"}]}}`,
+			result: func(c *Client) interface{} {
+				addr, err := address.StringToUint160("NMipL5VsNoLUBUJKPKLhxaEbPQVCZnyJyB")
+				if err != nil {
+					panic(fmt.Errorf("failed to parse UnclaimedGas address: %w", err))
+				}
+				return result.UnclaimedGas{
+					Address:   addr,
+					Unclaimed: *big.NewInt(1200),
+					Periods: []result.UnclaimedGasPeriod{
+						{Start: 10, End: 20, GASPerBlock: *big.NewInt(500000000), Amount: *big.NewInt(1000)},
+						{Start: 20, End: 30, GASPerBlock: *big.NewInt(100000000), Amount: *big.NewInt(200)},
+					},
+				}
+			},
+		},
 	},
 	"getcandidates": {
 		{
@@ -1111,6 +1192,32 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"invokescripttrace": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.InvokeScriptTrace([]byte{byte(opcode.PUSH1)}, nil)
+			},
+			serverResponse: `{"jsonrpc":"2.0","id":1,"result":{"script":"EQ==","state":"HALT","gasconsumed":"1","stack":[{"type":"Integer","value":"1"}],"trace":{"steps":[{"contract":"0x1d3e6f2b1a1c6d0a2e3d4a5b6c7d8e9f0a1b2c3d","ip":0,"opcode":"PUSH1","stackdepth":0,"gasleft":"2000000000"}],"truncated":false}}}`,
+			result: func(c *Client) interface{} {
+				h, err := util.Uint160DecodeStringLE("1d3e6f2b1a1c6d0a2e3d4a5b6c7d8e9f0a1b2c3d")
+				if err != nil {
+					panic(err)
+				}
+				return &result.Invoke{
+					State:       "HALT",
+					GasConsumed: 1,
+					Script:      []byte{byte(opcode.PUSH1)},
+					Stack:       []stackitem.Item{stackitem.NewBigInteger(big.NewInt(1))},
+					Trace: &trace.Trace{Steps: []trace.Step{{
+						ScriptHash: h,
+						Opcode:     opcode.PUSH1,
+						GasLeft:    2000000000,
+					}}},
+				}
+			},
+		},
+	},
 	"invokecontractverify": {
 		{
 			name: "positive",
@@ -1200,6 +1307,30 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"verifyproof": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				return c.VerifyProof(root, &result.ProofWithKey{Key: []byte{1, 2}, Proof: [][]byte{{3}}})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":"dGVzdHZhbHVl"}`,
+			result: func(c *Client) interface{} {
+				return []byte("testvalue")
+			},
+		},
+		{
+			name: "invalid proof",
+			invoke: func(c *Client) (interface{}, error) {
+				root, _ := util.Uint256DecodeStringLE("252e9d73d49c95c7618d40650da504e05183a1b2eed0685e42c360413c329170")
+				return c.VerifyProof(root, &result.ProofWithKey{Key: []byte{1, 2}, Proof: [][]byte{{3}}})
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":"invalid"}`,
+			result: func(c *Client) interface{} {
+				return []byte(nil)
+			},
+		},
+	},
 }
 
 type rpcClientErrorCase struct {
@@ -2010,3 +2141,44 @@ func newTestNEF(script []byte) nef.File {
 func getTestRequestID() uint64 {
 	return 1
 }
+
+func TestClientContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r := params.NewRequest()
+		err := r.DecodeData(req.Body)
+		require.NoErrorf(t, err, "Cannot decode request body: %s", req.Body)
+		if r.In.Method == "getblockcount" {
+			select {
+			case <-release:
+			case <-req.Context().Done():
+				return
+			}
+		}
+		requestHandler(t, r.In, w, `{"jsonrpc": "2.0", "id": 1, "result": 123}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(context.TODO(), srv.URL, Options{})
+	require.NoError(t, err)
+	c.getNextRequestID = getTestRequestID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = c.GetBlockCountContext(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = c.InvokeScriptContext(ctx, []byte{byte(opcode.PUSH1)}, nil)
+	require.True(t, errors.Is(err, context.Canceled), err)
+	_, err = c.GetContractStateByHashContext(ctx, util.Uint160{})
+	require.True(t, errors.Is(err, context.Canceled), err)
+	_, err = c.SendRawTransactionContext(ctx, transaction.New([]byte{byte(opcode.PUSH1)}, 0))
+	require.True(t, errors.Is(err, context.Canceled), err)
+
+	close(release)
+	count, err := c.GetBlockCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(123), count)
+}
@@ -0,0 +1,112 @@
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/vmstate"
+)
+
+var (
+	// ErrTxNotConfirmed is returned from WaitForTransaction when the
+	// transaction is not accepted to the chain within the given timeout.
+	ErrTxNotConfirmed = errors.New("transaction is not confirmed")
+	// ErrTxFaulted is returned from WaitForTransaction when the transaction
+	// is accepted to the chain, but its execution has faulted. Execution
+	// result is returned along with this error.
+	ErrTxFaulted = errors.New("transaction execution faulted")
+)
+
+// WaitForTransaction waits for the transaction with the given hash to be
+// accepted to the chain for at most the given timeout polling its application
+// log. It returns the execution result for HALTed transactions, the
+// execution result along with wrapped ErrTxFaulted for FAULTed ones and
+// wrapped ErrTxNotConfirmed if the transaction isn't accepted in time. Unlike
+// actor.Waiter it doesn't track ValidUntilBlock, so it can't tell if the
+// transaction can still be accepted or not.
+func (c *Client) WaitForTransaction(h util.Uint256, timeout time.Duration) (*state.AppExecResult, error) {
+	ctx, cancel := context.WithTimeout(c.Context(), timeout)
+	defer cancel()
+	return c.pollTransaction(ctx, h)
+}
+
+// pollTransaction polls the transaction application log every half of the
+// block interval until it's available or the given context is done.
+func (c *Client) pollTransaction(ctx context.Context, h util.Uint256) (*state.AppExecResult, error) {
+	var pollTime = time.Second
+	if v, err := c.GetVersion(); err == nil && v.Protocol.MillisecondsPerBlock != 0 {
+		pollTime = time.Millisecond * time.Duration(v.Protocol.MillisecondsPerBlock) / 2
+	}
+	ticker := time.NewTicker(pollTime)
+	defer ticker.Stop()
+	for {
+		if res := c.getTxExecution(ctx, h); res != nil {
+			return checkTxExecution(res)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", ErrTxNotConfirmed, ctx.Err())
+		}
+	}
+}
+
+// getTxExecution returns the transaction execution result or nil if it's not
+// available (yet).
+func (c *Client) getTxExecution(ctx context.Context, h util.Uint256) *state.AppExecResult {
+	t := trigger.Application
+	res, err := c.GetApplicationLogContext(ctx, h, &t)
+	if err != nil || len(res.Executions) == 0 {
+		return nil
+	}
+	return &state.AppExecResult{
+		Container: res.Container,
+		Execution: res.Executions[0],
+	}
+}
+
+// checkTxExecution returns ErrTxFaulted along with the result if the
+// transaction execution has faulted.
+func checkTxExecution(res *state.AppExecResult) (*state.AppExecResult, error) {
+	if res.VMState != vmstate.Halt {
+		return res, fmt.Errorf("%w: %s", ErrTxFaulted, res.FaultException)
+	}
+	return res, nil
+}
+
+// WaitForTransaction is the same as Client.WaitForTransaction, but it uses
+// execution notifications instead of polling. It falls back to polling if
+// subscription fails or some event is missed.
+func (c *WSClient) WaitForTransaction(h util.Uint256, timeout time.Duration) (*state.AppExecResult, error) {
+	ctx, cancel := context.WithTimeout(c.Context(), timeout)
+	defer cancel()
+
+	// There can be only one execution for the transaction, so the buffer
+	// ensures other receivers are never blocked by this one.
+	rcvr := make(chan *state.AppExecResult, 1)
+	id, err := c.ReceiveExecutions(&neorpc.ExecutionFilter{Container: &h}, rcvr)
+	if err != nil {
+		return c.pollTransaction(ctx, h)
+	}
+	defer func() { _ = c.Unsubscribe(id) }()
+
+	// The transaction could've been accepted before the subscription.
+	if res := c.getTxExecution(ctx, h); res != nil {
+		return checkTxExecution(res)
+	}
+	select {
+	case res, ok := <-rcvr:
+		if !ok {
+			return c.pollTransaction(ctx, h)
+		}
+		return checkTxExecution(res)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %v", ErrTxNotConfirmed, ctx.Err())
+	}
+}
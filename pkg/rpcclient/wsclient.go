@@ -390,6 +390,7 @@ func NewWS(ctx context.Context, endpoint string, opts Options) (*WSClient, error
 	go wsc.wsReader()
 	go wsc.wsWriter()
 	wsc.requestF = wsc.makeWsRequest
+	wsc.batchF = nil
 	return wsc, nil
 }
 
@@ -584,7 +585,7 @@ func (c *WSClient) getResponseChannel(id uint64) chan *neorpc.Response {
 	return c.respChannels[id]
 }
 
-func (c *WSClient) makeWsRequest(r *neorpc.Request) (*neorpc.Response, error) {
+func (c *WSClient) makeWsRequest(ctx context.Context, r *neorpc.Request) (*neorpc.Response, error) {
 	ch := make(chan *neorpc.Response)
 	c.respLock.Lock()
 	select {
@@ -598,11 +599,25 @@ func (c *WSClient) makeWsRequest(r *neorpc.Request) (*neorpc.Response, error) {
 	select {
 	case <-c.done:
 		return nil, errors.New("connection lost before sending the request")
+	case <-ctx.Done():
+		c.unregisterRespChannel(r.ID)
+		return nil, ctx.Err()
 	case c.requests <- r:
 	}
 	select {
 	case <-c.done:
 		return nil, errors.New("connection lost while waiting for the response")
+	case <-ctx.Done():
+		// The request is already sent, so the response is still to be
+		// consumed to keep the reader going.
+		go func() {
+			select {
+			case <-c.done:
+			case <-ch:
+				c.unregisterRespChannel(r.ID)
+			}
+		}()
+		return nil, ctx.Err()
 	case resp := <-ch:
 		c.unregisterRespChannel(r.ID)
 		return resp, nil
@@ -3,6 +3,7 @@ package rpcclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -706,3 +707,42 @@ func TestWSClient_ConnClosedError(t *testing.T) {
 		require.True(t, strings.Contains(err.Error(), "failed to read JSON response (timeout/connection loss/malformed response)"), err.Error())
 	})
 }
+
+func TestWSClientContextCancellation(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var upgrader = websocket.Upgrader{}
+		ws, err := upgrader.Upgrade(w, req, nil)
+		require.NoError(t, err)
+		defer ws.Close()
+		for {
+			_, p, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			r := params.NewIn()
+			require.NoError(t, json.Unmarshal(p, r))
+			time.Sleep(delay) // Slow node.
+			resp := fmt.Sprintf(`{"jsonrpc": "2.0", "id": %s, "result": 123}`, r.RawID)
+			if ws.WriteMessage(websocket.TextMessage, []byte(resp)) != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewWS(context.TODO(), httpURLtoWS(srv.URL), Options{})
+	require.NoError(t, err)
+	t.Cleanup(c.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), delay/4)
+	defer cancel()
+	_, err = c.GetBlockCountContext(ctx)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), err)
+
+	// Late response to the cancelled request doesn't break the connection.
+	count, err := c.GetBlockCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(123), count)
+	require.NoError(t, c.GetError())
+}
@@ -142,11 +142,16 @@ func TestNotary(t *testing.T) {
 	randomAcc, err := keys.NewPrivateKey()
 	require.NoError(t, err)
 
-	bc.SetNotary(ntr1)
 	bc.RegisterPostBlock(func(f func(*transaction.Transaction, *mempool.Pool, bool) bool, pool *mempool.Pool, b *block.Block) {
 		ntr1.PostPersist()
 	})
 
+	// Notary nodes are designated before the service is started, so that
+	// they're fetched synchronously on start.
+	notaryNodes := []interface{}{acc1.PublicKey().Bytes(), acc2.PrivateKey().PublicKey().Bytes()}
+	designationSuperInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(noderoles.P2PNotary), notaryNodes)
+
 	mp1.RunSubscriptions()
 	ntr1.Start()
 	t.Cleanup(func() {
@@ -154,10 +159,6 @@ func TestNotary(t *testing.T) {
 		mp1.StopSubscriptions()
 	})
 
-	notaryNodes := []interface{}{acc1.PublicKey().Bytes(), acc2.PrivateKey().PublicKey().Bytes()}
-	designationSuperInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
-		int64(noderoles.P2PNotary), notaryNodes)
-
 	type requester struct {
 		accounts []*wallet.Account
 		m        int
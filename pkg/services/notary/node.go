@@ -8,7 +8,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// UpdateNotaryNodes implements Notary interface and updates current notary account.
+// UpdateNotaryNodes updates current notary account using the given list of
+// designated notary nodes.
 func (n *Notary) UpdateNotaryNodes(notaryNodes keys.PublicKeys) {
 	n.accMtx.Lock()
 	defer n.accMtx.Unlock()
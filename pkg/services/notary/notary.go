@@ -2,21 +2,27 @@ package notary
 
 import (
 	"bytes"
+	"context"
 	"crypto/elliptic"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
@@ -30,10 +36,13 @@ type (
 	// Ledger is the interface to Blockchain sufficient for Notary.
 	Ledger interface {
 		BlockHeight() uint32
+		GetDesignatedByRole(r noderoles.Role, index uint32) (keys.PublicKeys, uint32, error)
 		GetMaxVerificationGAS() int64
 		GetNotaryContractScriptHash() util.Uint160
 		SubscribeForBlocks(ch chan *block.Block)
+		SubscribeForRoleChanges(ch chan *state.RoleChangeEvent)
 		UnsubscribeFromBlocks(ch chan *block.Block)
+		UnsubscribeFromRoleChanges(ch chan *state.RoleChangeEvent)
 		VerifyWitness(util.Uint160, hash.Hashable, *transaction.Witness, int64) (int64, error)
 	}
 
@@ -62,12 +71,24 @@ type (
 		currAccount *wallet.Account
 		wallet      *wallet.Wallet
 
+		// completed, fallbacksSent and expired are the numbers of sent main
+		// transactions, sent fallbacks and requests dropped without main
+		// transaction being sent.
+		completed     *atomic.Uint64
+		fallbacksSent *atomic.Uint64
+		expired       *atomic.Uint64
+
 		mp *mempool.Pool
 		// requests channel
 		reqCh    chan mempoolevent.Event
 		blocksCh chan *block.Block
+		roleCh   chan *state.RoleChangeEvent
 		stopCh   chan struct{}
 		done     chan struct{}
+		// signCtx is used for remote signer requests, it's cancelled on
+		// shutdown.
+		signCtx    context.Context
+		signCancel context.CancelFunc
 	}
 
 	// Config represents external configuration for Notary module.
@@ -86,6 +107,14 @@ type (
 		// isSent indicates whether the main transaction was successfully sent to the network.
 		isSent bool
 		main   *transaction.Transaction
+		// received is the time the first request for the main transaction
+		// was received at, it's used for completion time metric.
+		received time.Time
+		// completed indicates whether all main transaction signatures were
+		// collected (and completion time was accounted for).
+		completed bool
+		// fallbackSent indicates whether any of fallbacks was sent.
+		fallbackSent bool
 		// minNotValidBefore is the minimum NVB value among fallbacks transactions.
 		// We stop trying to send the mainTx to the network if the chain reaches the minNotValidBefore height.
 		minNotValidBefore uint32
@@ -126,10 +155,14 @@ func (r request) isMainCompleted() bool {
 // NewNotary returns a new Notary module.
 func NewNotary(cfg Config, net netmode.Magic, mp *mempool.Pool, onTransaction func(tx *transaction.Transaction) error) (*Notary, error) {
 	w := cfg.MainCfg.UnlockWallet
+	permErr := wallet.CheckPermissions(w.Path)
 	wallet, err := wallet.NewWalletFromFile(w.Path)
 	if err != nil {
 		return nil, err
 	}
+	if permErr != nil {
+		cfg.Log.Warn("insecure wallet permissions", zap.Error(permErr))
+	}
 
 	haveAccount := false
 	for _, acc := range wallet.Accounts {
@@ -142,19 +175,26 @@ func NewNotary(cfg Config, net netmode.Magic, mp *mempool.Pool, onTransaction fu
 		return nil, errors.New("no wallet account could be unlocked")
 	}
 
+	signCtx, signCancel := context.WithCancel(context.Background())
 	return &Notary{
 		requests:      make(map[util.Uint256]*request),
 		Config:        cfg,
 		Network:       net,
 		started:       atomic.NewBool(false),
+		completed:     atomic.NewUint64(0),
+		fallbacksSent: atomic.NewUint64(0),
+		expired:       atomic.NewUint64(0),
 		wallet:        wallet,
 		onTransaction: onTransaction,
 		newTxs:        make(chan txHashPair, defaultTxChannelCapacity),
 		mp:            mp,
 		reqCh:         make(chan mempoolevent.Event),
 		blocksCh:      make(chan *block.Block),
+		roleCh:        make(chan *state.RoleChangeEvent),
 		stopCh:        make(chan struct{}),
 		done:          make(chan struct{}),
+		signCtx:       signCtx,
+		signCancel:    signCancel,
 	}, nil
 }
 
@@ -170,8 +210,17 @@ func (n *Notary) Start() {
 		return
 	}
 	n.Config.Log.Info("starting notary service")
+	n.Config.Chain.SubscribeForRoleChanges(n.roleCh)
 	n.Config.Chain.SubscribeForBlocks(n.blocksCh)
 	n.mp.SubscribeForTransactions(n.reqCh)
+	// Notary nodes for the next block, subsequent changes are delivered
+	// via role change events.
+	nodes, _, err := n.Config.Chain.GetDesignatedByRole(noderoles.P2PNotary, n.Config.Chain.BlockHeight()+1)
+	if err != nil {
+		n.Config.Log.Error("failed to get notary nodes", zap.Error(err))
+	} else {
+		n.UpdateNotaryNodes(nodes)
+	}
 	go n.newTxCallbackLoop()
 	go n.mainLoop()
 }
@@ -181,9 +230,11 @@ mainloop:
 	for {
 		select {
 		case <-n.stopCh:
-			n.mp.UnsubscribeFromTransactions(n.reqCh)
-			n.Config.Chain.UnsubscribeFromBlocks(n.blocksCh)
 			break mainloop
+		case ev := <-n.roleCh:
+			if ev.Role == noderoles.P2PNotary {
+				n.UpdateNotaryNodes(ev.Nodes)
+			}
 		case event := <-n.reqCh:
 			if req, ok := event.Data.(*payload.P2PNotaryRequest); ok {
 				switch event.Type {
@@ -198,16 +249,27 @@ mainloop:
 			n.PostPersist()
 		}
 	}
+	// Blockchain can be blocked sending to any of its channels, so they're
+	// drained until all unsubscriptions are completed.
+	unsubbed := make(chan struct{})
+	go func() {
+		n.mp.UnsubscribeFromTransactions(n.reqCh)
+		n.Config.Chain.UnsubscribeFromBlocks(n.blocksCh)
+		n.Config.Chain.UnsubscribeFromRoleChanges(n.roleCh)
+		close(unsubbed)
+	}()
 drainLoop:
 	for {
 		select {
 		case <-n.blocksCh:
 		case <-n.reqCh:
-		default:
+		case <-n.roleCh:
+		case <-unsubbed:
 			break drainLoop
 		}
 	}
 	close(n.blocksCh)
+	close(n.roleCh)
 	close(n.reqCh)
 	close(n.done)
 }
@@ -220,6 +282,7 @@ func (n *Notary) Shutdown() {
 		return
 	}
 	n.Config.Log.Info("stopping notary service")
+	n.signCancel()
 	close(n.stopCh)
 	<-n.done
 	n.wallet.Close()
@@ -265,8 +328,10 @@ func (n *Notary) OnNewRequest(payload *payload.P2PNotaryRequest) {
 		r = &request{
 			main:              &cp,
 			minNotValidBefore: nvbFallback,
+			received:          time.Now(),
 		}
 		n.requests[payload.MainTransaction.Hash()] = r
+		updatePendingRequestsMetric(len(n.requests))
 	}
 	if r.witnessInfo == nil && validationErr == nil {
 		r.witnessInfo = newInfo
@@ -324,6 +389,10 @@ func (n *Notary) OnNewRequest(payload *payload.P2PNotaryRequest) {
 			// been added - we're OK with that, let the fallback TX to be added
 		}
 	}
+	if !r.completed && r.isMainCompleted() {
+		r.completed = true
+		addCompletionTimeMetric(time.Since(r.received))
+	}
 	if r.isMainCompleted() && r.minNotValidBefore > n.Config.Chain.BlockHeight() {
 		if err := n.finalize(acc, r.main, payload.MainTransaction.Hash()); err != nil {
 			n.Config.Log.Error("failed to finalize main transaction",
@@ -353,10 +422,21 @@ func (n *Notary) OnRequestRemoval(pld *payload.P2PNotaryRequest) {
 		}
 	}
 	if len(r.fallbacks) == 0 {
-		delete(n.requests, r.main.Hash())
+		n.removeRequest(pld.MainTransaction.Hash(), !r.isSent && !r.fallbackSent)
 	}
 }
 
+// removeRequest removes the request for the given main transaction and updates
+// metrics. It must be called with reqMtx held.
+func (n *Notary) removeRequest(h util.Uint256, expired bool) {
+	delete(n.requests, h)
+	if expired {
+		n.expired.Inc()
+		expiredRequests.Inc()
+	}
+	updatePendingRequestsMetric(len(n.requests))
+}
+
 // PostPersist is a callback which is called after a new block event is received.
 // PostPersist must not be called under the blockchain lock, because it uses finalization function.
 func (n *Notary) PostPersist() {
@@ -391,8 +471,12 @@ func (n *Notary) PostPersist() {
 
 // finalize adds missing Notary witnesses to the transaction (main or fallback) and pushes it to the network.
 func (n *Notary) finalize(acc *wallet.Account, tx *transaction.Transaction, h util.Uint256) error {
+	sig, err := acc.SignHashableContext(n.signCtx, n.Network, tx)
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
 	notaryWitness := transaction.Witness{
-		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, acc.SignHashable(n.Network, tx)...),
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...),
 		VerificationScript: []byte{},
 	}
 	for i, signer := range tx.Signers {
@@ -460,7 +544,12 @@ func (n *Notary) newTxCallbackLoop() {
 			n.reqMtx.Lock()
 			if isMain {
 				r.isSent = true
+				n.completed.Inc()
+				completedRequests.Inc()
 			} else {
+				r.fallbackSent = true
+				n.fallbacksSent.Inc()
+				sentFallbacks.Inc()
 				for i := range r.fallbacks {
 					if r.fallbacks[i].Hash() == tx.tx.Hash() {
 						r.fallbacks = append(r.fallbacks[:i], r.fallbacks[i+1:]...)
@@ -468,7 +557,7 @@ func (n *Notary) newTxCallbackLoop() {
 					}
 				}
 				if len(r.fallbacks) == 0 {
-					delete(n.requests, tx.mainHash)
+					n.removeRequest(tx.mainHash, false)
 				}
 			}
 			n.reqMtx.Unlock()
@@ -478,6 +567,35 @@ func (n *Notary) newTxCallbackLoop() {
 	}
 }
 
+// State returns a snapshot of the current Notary module state.
+func (n *Notary) State() result.NotaryState {
+	var st = result.NotaryState{
+		Started:       n.started.Load(),
+		Completed:     n.completed.Load(),
+		FallbacksSent: n.fallbacksSent.Load(),
+		Expired:       n.expired.Load(),
+	}
+	if acc := n.getAccount(); acc != nil {
+		st.Account = acc.Address
+	}
+	n.reqMtx.RLock()
+	st.Requests = make([]result.NotaryRequestState, 0, len(n.requests))
+	for h, r := range n.requests {
+		st.Requests = append(st.Requests, result.NotaryRequestState{
+			Main:              h,
+			Fallbacks:         len(r.fallbacks),
+			MinNotValidBefore: r.minNotValidBefore,
+			Completed:         r.isMainCompleted(),
+			Sent:              r.isSent,
+		})
+	}
+	n.reqMtx.RUnlock()
+	sort.Slice(st.Requests, func(i, j int) bool {
+		return st.Requests[i].Main.CompareTo(st.Requests[j].Main) < 0
+	})
+	return st
+}
+
 // updateTxSize returns a transaction with re-calculated size and an error.
 func updateTxSize(tx *transaction.Transaction) (*transaction.Transaction, error) {
 	bw := io.NewBufBinWriter()
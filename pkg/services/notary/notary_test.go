@@ -10,6 +10,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
@@ -489,3 +491,36 @@ func TestVerifyIncompleteRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestState(t *testing.T) {
+	bc := fakechain.NewFakeChain()
+	acc, ntr, _ := getTestNotary(t, bc, "./testdata/notary1.json", "one")
+	require.Equal(t, result.NotaryState{Requests: []result.NotaryRequestState{}}, ntr.State())
+
+	ntr.started.Store(true)
+	ntr.UpdateNotaryNodes(keys.PublicKeys{acc.PublicKey()})
+	mainTx := transaction.New([]byte{byte(opcode.RET)}, 1)
+	fbTx := transaction.New([]byte{byte(opcode.PUSH1)}, 1)
+	ntr.requests[mainTx.Hash()] = &request{
+		main:              mainTx,
+		minNotValidBefore: 10,
+		fallbacks:         []*transaction.Transaction{fbTx},
+	}
+	require.Equal(t, result.NotaryState{
+		Started: true,
+		Account: acc.Address,
+		Requests: []result.NotaryRequestState{{
+			Main:              mainTx.Hash(),
+			Fallbacks:         1,
+			MinNotValidBefore: 10,
+		}},
+	}, ntr.State())
+
+	ntr.OnRequestRemoval(&payload.P2PNotaryRequest{MainTransaction: mainTx, FallbackTransaction: fbTx})
+	require.Equal(t, result.NotaryState{
+		Started:  true,
+		Account:  acc.Address,
+		Expired:  1,
+		Requests: []result.NotaryRequestState{},
+	}, ntr.State())
+}
@@ -0,0 +1,64 @@
+package notary
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics used in monitoring service.
+var (
+	pendingRequests = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of main transactions tracked by the Notary service",
+			Name:      "notary_pending_requests",
+			Namespace: "neogo",
+		},
+	)
+	completedRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of completed main transactions sent by the Notary service",
+			Name:      "notary_completed_requests_total",
+			Namespace: "neogo",
+		},
+	)
+	sentFallbacks = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of fallback transactions sent by the Notary service",
+			Name:      "notary_fallbacks_sent_total",
+			Namespace: "neogo",
+		},
+	)
+	expiredRequests = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Help:      "Number of requests removed without main transaction being completed",
+			Name:      "notary_expired_requests_total",
+			Namespace: "neogo",
+		},
+	)
+	completionTime = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Help:      "Time (in seconds) needed to collect all signatures for the main transaction",
+			Name:      "notary_completion_time",
+			Namespace: "neogo",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		pendingRequests,
+		completedRequests,
+		sentFallbacks,
+		expiredRequests,
+		completionTime,
+	)
+}
+
+func updatePendingRequestsMetric(n int) {
+	pendingRequests.Set(float64(n))
+}
+
+func addCompletionTimeMetric(t time.Duration) {
+	completionTime.Observe(t.Seconds())
+}
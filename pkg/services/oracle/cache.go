@@ -0,0 +1,255 @@
+package oracle
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+)
+
+// cacheCompactThreshold is the number of stale records in the cache file
+// that triggers its rewrite.
+const cacheCompactThreshold = 64
+
+type (
+	// responseCache is an on-disk cache of response transactions signed by
+	// this node. It allows to resend signatures after restart without
+	// fetching request data again. nil cache is a valid no-op one.
+	//
+	// The file is an append-only log of JSON records, every change adds
+	// records to it and the file is only rewritten when the number of
+	// stale records reaches cacheCompactThreshold (and on load).
+	responseCache struct {
+		lock    sync.Mutex
+		path    string
+		entries map[uint64]*cacheEntry
+		// records is the number of records in the file.
+		records int
+	}
+
+	// cacheRecord is a single change of the cache, nil Entry means that
+	// the response is removed.
+	cacheRecord struct {
+		ID    uint64      `json:"id"`
+		Entry *cacheEntry `json:"entry,omitempty"`
+	}
+
+	// cacheEntry is a serialized cached response.
+	cacheEntry struct {
+		Tx        []byte `json:"tx"`
+		TxSig     []byte `json:"txsig"`
+		BackupTx  []byte `json:"backuptx"`
+		BackupSig []byte `json:"backupsig"`
+	}
+
+	// cachedResponse is a response restored from the cache.
+	cachedResponse struct {
+		tx        *transaction.Transaction
+		txSig     []byte
+		backupTx  *transaction.Transaction
+		backupSig []byte
+	}
+)
+
+// newResponseCache loads the cache from the given file, it returns nil if
+// the path is empty.
+func newResponseCache(path string) (*responseCache, error) {
+	if path == "" {
+		return nil, nil
+	}
+	c := &responseCache{
+		path:    path,
+		entries: make(map[uint64]*cacheEntry),
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("can't read oracle response cache: %w", err)
+	}
+	defer f.Close()
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var r cacheRecord
+		err := dec.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid oracle response cache %s: %w", path, err)
+		}
+		c.records++
+		if r.Entry == nil {
+			delete(c.entries, r.ID)
+		} else {
+			c.entries[r.ID] = r.Entry
+		}
+	}
+	if c.records != len(c.entries) {
+		if err := c.compact(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// get returns the cached response for the given request if it's present.
+func (c *responseCache) get(id uint64) *cachedResponse {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	e, ok := c.entries[id]
+	c.lock.Unlock()
+	if !ok {
+		return nil
+	}
+	tx, err := transaction.NewTransactionFromBytes(e.Tx)
+	if err != nil {
+		return nil
+	}
+	backupTx, err := transaction.NewTransactionFromBytes(e.BackupTx)
+	if err != nil {
+		return nil
+	}
+	return &cachedResponse{
+		tx:        tx,
+		txSig:     e.TxSig,
+		backupTx:  backupTx,
+		backupSig: e.BackupSig,
+	}
+}
+
+// put stores the response for the given request.
+func (c *responseCache) put(id uint64, r *cachedResponse) error {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e := &cacheEntry{
+		Tx:        r.tx.Bytes(),
+		TxSig:     r.txSig,
+		BackupTx:  r.backupTx.Bytes(),
+		BackupSig: r.backupSig,
+	}
+	c.entries[id] = e
+	return c.write([]cacheRecord{{ID: id, Entry: e}})
+}
+
+// remove drops responses for the given (resolved) requests.
+func (c *responseCache) remove(ids []uint64) error {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var records []cacheRecord
+	for _, id := range ids {
+		if _, ok := c.entries[id]; ok {
+			delete(c.entries, id)
+			records = append(records, cacheRecord{ID: id})
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return c.write(records)
+}
+
+// clear drops all cached responses.
+func (c *responseCache) clear() error {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.records == 0 {
+		return nil
+	}
+	c.entries = make(map[uint64]*cacheEntry)
+	return c.compact()
+}
+
+// removeExpired drops responses which main transactions are not valid at the
+// given height anymore.
+func (c *responseCache) removeExpired(height uint32) error {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	var expired []uint64
+	for id, e := range c.entries {
+		tx, err := transaction.NewTransactionFromBytes(e.Tx)
+		if err != nil || tx.ValidUntilBlock <= height {
+			expired = append(expired, id)
+		}
+	}
+	c.lock.Unlock()
+	return c.remove(expired)
+}
+
+// write appends the given records to the cache file (compacting it if there
+// are too many stale records), it must be called with the lock held.
+func (c *responseCache) write(records []cacheRecord) error {
+	if c.records+len(records)-len(c.entries) >= cacheCompactThreshold {
+		return c.compact()
+	}
+	f, err := os.OpenFile(c.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("can't write oracle response cache: %w", err)
+	}
+	err = encodeRecords(f, records)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("can't write oracle response cache: %w", err)
+	}
+	c.records += len(records)
+	return nil
+}
+
+// compact rewrites the cache file with the current entries only, it must be
+// called with the lock held.
+func (c *responseCache) compact() error {
+	records := make([]cacheRecord, 0, len(c.entries))
+	for id, e := range c.entries {
+		records = append(records, cacheRecord{ID: id, Entry: e})
+	}
+	tmp := c.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("can't write oracle response cache: %w", err)
+	}
+	err = encodeRecords(f, records)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, c.path)
+	}
+	if err != nil {
+		return fmt.Errorf("can't write oracle response cache: %w", err)
+	}
+	c.records = len(records)
+	return nil
+}
+
+// encodeRecords writes records to w, one JSON object per line.
+func encodeRecords(w io.Writer, records []cacheRecord) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
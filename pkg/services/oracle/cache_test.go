@@ -0,0 +1,114 @@
+package oracle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newCacheTestResponse(id uint64, vub uint32) *cachedResponse {
+	newTx := func(code transaction.OracleResponseCode, result []byte) *transaction.Transaction {
+		tx := transaction.New([]byte{1, 2, 3}, 0)
+		tx.Nonce = uint32(id)
+		tx.ValidUntilBlock = vub
+		tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		tx.Scripts = []transaction.Witness{{}}
+		tx.Attributes = []transaction.Attribute{{
+			Type:  transaction.OracleResponseT,
+			Value: &transaction.OracleResponse{ID: id, Code: code, Result: result},
+		}}
+		return tx
+	}
+	return &cachedResponse{
+		tx:        newTx(transaction.Success, []byte{4, 5}),
+		txSig:     []byte{byte(id), 1},
+		backupTx:  newTx(transaction.ConsensusUnreachable, nil),
+		backupSig: []byte{byte(id), 2},
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		c, err := newResponseCache("")
+		require.NoError(t, err)
+		require.Nil(t, c)
+		require.NoError(t, c.put(1, newCacheTestResponse(1, 10)))
+		require.Nil(t, c.get(1))
+		require.NoError(t, c.remove([]uint64{1}))
+		require.NoError(t, c.removeExpired(100))
+		require.NoError(t, c.clear())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.json")
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+		_, err := newResponseCache(path)
+		require.Error(t, err)
+	})
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c, err := newResponseCache(path)
+	require.NoError(t, err)
+	require.Nil(t, c.get(1))
+
+	for id, vub := range map[uint64]uint32{1: 10, 2: 20, 3: 30} {
+		require.NoError(t, c.put(id, newCacheTestResponse(id, vub)))
+	}
+
+	// Restart.
+	c, err = newResponseCache(path)
+	require.NoError(t, err)
+	expected := newCacheTestResponse(1, 10)
+	actual := c.get(1)
+	require.NotNil(t, actual)
+	require.Equal(t, expected.tx.Hash(), actual.tx.Hash())
+	require.Equal(t, expected.backupTx.Hash(), actual.backupTx.Hash())
+	require.Equal(t, expected.txSig, actual.txSig)
+	require.Equal(t, expected.backupSig, actual.backupSig)
+
+	require.NoError(t, c.remove([]uint64{1, 4}))
+	require.Nil(t, c.get(1))
+	require.NoError(t, c.removeExpired(20))
+	require.Nil(t, c.get(2))
+	require.NotNil(t, c.get(3))
+
+	c, err = newResponseCache(path)
+	require.NoError(t, err)
+	require.Nil(t, c.get(1))
+	require.Nil(t, c.get(2))
+	require.NotNil(t, c.get(3))
+	require.Equal(t, 1, c.records) // Compacted on load.
+
+	require.NoError(t, c.clear())
+	require.Nil(t, c.get(3))
+	c, err = newResponseCache(path)
+	require.NoError(t, err)
+	require.Nil(t, c.get(3))
+	require.Equal(t, 0, c.records)
+}
+
+func TestResponseCacheCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c, err := newResponseCache(path)
+	require.NoError(t, err)
+
+	require.NoError(t, c.put(0, newCacheTestResponse(0, 10)))
+	for i := 1; i < cacheCompactThreshold/2; i++ {
+		id := uint64(i)
+		require.NoError(t, c.put(id, newCacheTestResponse(id, 10)))
+		require.NoError(t, c.remove([]uint64{id}))
+		// Records are appended until there are too many stale ones.
+		require.Equal(t, 2*i+1, c.records)
+	}
+	require.NoError(t, c.put(100, newCacheTestResponse(100, 10)))
+	require.NoError(t, c.remove([]uint64{100}))
+	require.Equal(t, 1, c.records)
+
+	c, err = newResponseCache(path)
+	require.NoError(t, err)
+	require.NotNil(t, c.get(0))
+	require.Equal(t, 1, len(c.entries))
+}
@@ -0,0 +1,82 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+)
+
+// fileScheme is the URI scheme of local file requests.
+const fileScheme = "file"
+
+// ErrFileForbidden is returned when a file:// request points outside of the
+// allowed root directory.
+var ErrFileForbidden = errors.New("file is outside of the allowed directory")
+
+// readFile reads the file specified by the file:// URI u if it's located in
+// the root directory (symbolic links are resolved for both). The file size is
+// limited by transaction.MaxOracleResultSize.
+func readFile(root string, u *url.URL) ([]byte, error) {
+	if u.Host != "" && u.Host != "localhost" {
+		return nil, fmt.Errorf("%w: remote host %s", ErrFileForbidden, u.Host)
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Clean(filepath.FromSlash(u.Path))
+	// Check the path before touching the file system not to reveal anything
+	// about files outside of the root.
+	if !filepath.IsAbs(path) || !isInDir(root, path) {
+		return nil, fmt.Errorf("%w: %s", ErrFileForbidden, u.Path)
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return nil, err
+	}
+	if path, err = filepath.EvalSymlinks(path); err != nil {
+		return nil, err
+	}
+	if !isInDir(root, path) {
+		return nil, fmt.Errorf("%w: %s", ErrFileForbidden, u.Path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if !st.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", u.Path)
+	}
+	return readResponse(f, transaction.MaxOracleResultSize)
+}
+
+// isInDir checks whether the path is located inside the dir (both are to be
+// absolute and cleaned).
+func isInDir(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// fileErrorCode returns the oracle response code for the readFile error.
+func fileErrorCode(err error) transaction.OracleResponseCode {
+	switch {
+	case errors.Is(err, ErrFileForbidden):
+		return transaction.Forbidden
+	case errors.Is(err, fs.ErrNotExist):
+		return transaction.NotFound
+	case errors.Is(err, ErrResponseTooLarge):
+		return transaction.ResponseTooLarge
+	default:
+		return transaction.Error
+	}
+}
@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "data.json"), []byte(`{"a":1}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "empty"), nil, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "large"), make([]byte, transaction.MaxOracleResultSize+1), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(root, "dir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644))
+
+	read := func(path string, host string) ([]byte, error) {
+		return readFile(root, &url.URL{Scheme: fileScheme, Host: host, Path: filepath.ToSlash(path)})
+	}
+
+	res, err := read(filepath.Join(root, "data.json"), "")
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"a":1}`), res)
+
+	res, err = read(filepath.Join(root, "dir", "..", "data.json"), "localhost")
+	require.NoError(t, err)
+	require.Equal(t, []byte(`{"a":1}`), res)
+
+	testCases := map[string]struct {
+		path string
+		host string
+		code transaction.OracleResponseCode
+	}{
+		"remote host":     {filepath.Join(root, "data.json"), "example.com", transaction.Forbidden},
+		"outside of root": {filepath.Join(outside, "secret"), "", transaction.Forbidden},
+		"traversal":       {root + "/../" + filepath.Base(outside) + "/secret", "", transaction.Forbidden},
+		"root prefix":     {root + "x", "", transaction.Forbidden},
+		"missing":         {filepath.Join(root, "missing"), "", transaction.NotFound},
+		"directory":       {filepath.Join(root, "dir"), "", transaction.Error},
+		"empty":           {filepath.Join(root, "empty"), "", transaction.Error},
+		"too large":       {filepath.Join(root, "large"), "", transaction.ResponseTooLarge},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := read(tc.path, tc.host)
+			require.Error(t, err)
+			require.Equal(t, tc.code, fileErrorCode(err))
+		})
+	}
+
+	t.Run("symlink", func(t *testing.T) {
+		link := filepath.Join(root, "link")
+		if err := os.Symlink(filepath.Join(outside, "secret"), link); err != nil {
+			t.Skipf("can't create symlink: %s", err)
+		}
+		_, err := read(link, "")
+		require.ErrorIs(t, err, ErrFileForbidden)
+	})
+}
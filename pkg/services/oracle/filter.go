@@ -3,10 +3,12 @@ package oracle
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"unicode/utf8"
 
 	json "github.com/nspcc-dev/go-ordered-json"
-	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle/jsonpath"
 )
 
@@ -31,9 +33,76 @@ func filter(value []byte, path string) ([]byte, error) {
 	return json.Marshal(result)
 }
 
-func filterRequest(result []byte, req *state.OracleRequest) ([]byte, error) {
-	if req.Filter != nil {
-		return filter(result, *req.Filter)
+// maxRequestBodySize is the maximum size of HTTP request body that can be
+// specified in the request filter.
+const maxRequestBodySize = 1024
+
+// requestFilter is a parsed oracle request filter.
+type requestFilter struct {
+	// Method is an HTTP method to use, GET by default.
+	Method string
+	// Path is a JSONPath expression to apply to the response, nil if the
+	// response is to be returned as is.
+	Path *string
+	// Body is an HTTP request body, it's only allowed for POST requests.
+	Body []byte
+}
+
+// parseFilter parses the oracle request filter. It's a JSONPath expression by
+// default (and GET request is made then), but it can also start with an HTTP
+// method name (GET or POST) followed by an optional space-separated JSONPath
+// expression and an optional newline-separated POST request body, like:
+//
+//	POST $.result
+//	{"jsonrpc": "2.0", "method": "getblockcount", "params": [], "id": 1}
+//
+// JSONPath expressions always start with '$', so there is no ambiguity here.
+func parseFilter(flt *string) (requestFilter, error) {
+	var res = requestFilter{Method: http.MethodGet}
+	if flt == nil {
+		return res, nil
+	}
+	if *flt == "" || strings.HasPrefix(*flt, "$") {
+		res.Path = flt
+		return res, nil
+	}
+	var (
+		head    = *flt
+		body    string
+		hasBody bool
+		path    string
+	)
+	if i := strings.IndexByte(head, '\n'); i >= 0 {
+		head, body, hasBody = head[:i], head[i+1:], true
+	}
+	method := head
+	if i := strings.IndexByte(head, ' '); i >= 0 {
+		method, path = head[:i], head[i+1:]
+	}
+	switch method {
+	case http.MethodGet:
+		if hasBody {
+			return res, errors.New("GET request can't have a body")
+		}
+	case http.MethodPost:
+		if len(body) > maxRequestBodySize {
+			return res, fmt.Errorf("request body is too big: %d > %d", len(body), maxRequestBodySize)
+		}
+		res.Method = method
+		res.Body = []byte(body)
+	default:
+		return res, fmt.Errorf("unsupported HTTP method %q", method)
+	}
+	if path != "" {
+		res.Path = &path
+	}
+	return res, nil
+}
+
+// apply filters the response according to the JSONPath expression.
+func (f requestFilter) apply(result []byte) ([]byte, error) {
+	if f.Path != nil {
+		return filter(result, *f.Path)
 	}
 	return result, nil
 }
@@ -1,6 +1,7 @@
 package oracle
 
 import (
+	"net/http"
 	"strings"
 	"testing"
 
@@ -81,3 +82,38 @@ func TestFilterOOM(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestParseFilter(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	testCases := []struct {
+		filter   *string
+		expected requestFilter
+	}{
+		{nil, requestFilter{Method: http.MethodGet}},
+		{str(""), requestFilter{Method: http.MethodGet, Path: str("")}},
+		{str("$.a"), requestFilter{Method: http.MethodGet, Path: str("$.a")}},
+		{str("GET"), requestFilter{Method: http.MethodGet}},
+		{str("GET $.a"), requestFilter{Method: http.MethodGet, Path: str("$.a")}},
+		{str("POST"), requestFilter{Method: http.MethodPost, Body: []byte{}}},
+		{str("POST $.a"), requestFilter{Method: http.MethodPost, Path: str("$.a"), Body: []byte{}}},
+		{str("POST\n{}"), requestFilter{Method: http.MethodPost, Body: []byte("{}")}},
+		{str("POST $['a b']\n{\"a\": 1}"), requestFilter{Method: http.MethodPost, Path: str("$['a b']"), Body: []byte(`{"a": 1}`)}},
+	}
+	for _, tc := range testCases {
+		actual, err := parseFilter(tc.filter)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, actual)
+	}
+
+	for _, flt := range []string{
+		"PUT $.a",
+		"post $.a",
+		" $.a",
+		"GET $.a\n{}",
+		"POST $.a\n" + strings.Repeat("a", maxRequestBodySize+1),
+	} {
+		_, err := parseFilter(&flt)
+		require.Error(t, err, flt)
+	}
+}
@@ -0,0 +1,70 @@
+package oracle
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// errRateLimitTimeout is returned when a request can't be started before
+	// its deadline because of the outbound request rate limit.
+	errRateLimitTimeout = errors.New("request rate limit exceeded")
+	// errStopped is returned when the service is stopped while a request is
+	// waiting for its turn.
+	errStopped = errors.New("oracle service is stopped")
+)
+
+// rateLimiter is a token bucket limiting the overall rate of outbound
+// requests. Tokens are reserved in order, so waiting requests are served
+// FIFO and the bucket never allows more than one request above the rate.
+type rateLimiter struct {
+	lock sync.Mutex
+	// interval is the time needed for one token to be refilled.
+	interval time.Duration
+	// next is the time the next token is available at.
+	next time.Time
+}
+
+// newRateLimiter creates a limiter for the given number of requests per
+// second, it returns nil (no limit) if it's not positive.
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// wait blocks until the request can be made. It returns errRateLimitTimeout
+// without waiting if the request can't be started before the deadline and
+// errStopped if stop channel is closed while waiting. It's a no-op for nil
+// limiter.
+func (l *rateLimiter) wait(deadline time.Time, stop <-chan struct{}) error {
+	if l == nil {
+		return nil
+	}
+	now := time.Now()
+
+	l.lock.Lock()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	if at.After(deadline) {
+		l.lock.Unlock()
+		return errRateLimitTimeout
+	}
+	l.next = at.Add(l.interval)
+	l.lock.Unlock()
+
+	if d := at.Sub(now); d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-stop:
+			return errStopped
+		}
+	}
+	return nil
+}
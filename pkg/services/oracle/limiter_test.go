@@ -0,0 +1,75 @@
+package oracle
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("unlimited", func(t *testing.T) {
+		l := newRateLimiter(0)
+		require.Nil(t, l)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, l.wait(time.Now(), nil))
+		}
+	})
+	t.Run("aggregate rate", func(t *testing.T) {
+		const (
+			rate = 50
+			n    = 60
+		)
+		var (
+			l     = newRateLimiter(rate)
+			wg    sync.WaitGroup
+			lock  sync.Mutex
+			times = make([]time.Time, 0, n)
+			start = time.Now()
+		)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, l.wait(time.Now().Add(time.Minute), nil))
+				lock.Lock()
+				times = append(times, time.Now())
+				lock.Unlock()
+			}()
+		}
+		wg.Wait()
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+		// n requests can't be done faster than (n-1)/rate.
+		require.True(t, times[n-1].Sub(start) >= (n-1)*time.Second/rate, times[n-1].Sub(start))
+		// No more than rate requests (plus the first one) in any second.
+		for i := range times {
+			j := i
+			for j < n && times[j].Sub(times[i]) < time.Second {
+				j++
+			}
+			require.LessOrEqual(t, j-i, rate+1)
+		}
+	})
+	t.Run("deadline", func(t *testing.T) {
+		l := newRateLimiter(1)
+		require.NoError(t, l.wait(time.Now().Add(time.Second), nil))
+
+		// The next slot is a second later, so it fails right away.
+		start := time.Now()
+		require.ErrorIs(t, l.wait(time.Now().Add(100*time.Millisecond), nil), errRateLimitTimeout)
+		require.True(t, time.Since(start) < 100*time.Millisecond)
+
+		// Failed request doesn't take the slot.
+		require.NoError(t, l.wait(time.Now().Add(2*time.Second), nil))
+	})
+	t.Run("stop", func(t *testing.T) {
+		l := newRateLimiter(0.1)
+		stop := make(chan struct{})
+		require.NoError(t, l.wait(time.Now().Add(time.Second), stop))
+		close(stop)
+		require.ErrorIs(t, l.wait(time.Now().Add(time.Minute), stop), errStopped)
+	})
+}
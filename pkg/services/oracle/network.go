@@ -1,9 +1,14 @@
 package oracle
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"syscall"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
@@ -47,21 +52,116 @@ func isReserved(ip net.IP) bool {
 	return false
 }
 
-func getDefaultClient(cfg config.OracleConfiguration) *http.Client {
+// tlsVersions maps configuration values to TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// newTLSConfig creates a TLS client configuration from the given one, nil is
+// returned for an empty configuration, so that standard defaults are used.
+func newTLSConfig(cfg config.OracleTLS) (*tls.Config, error) {
+	if cfg == (config.OracleTLS{}) {
+		return nil, nil
+	}
+	var res = &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // It's explicitly requested by the user.
+	}
+	if cfg.MinVersion != "" {
+		v, ok := tlsVersions[cfg.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum TLS version %q", cfg.MinVersion)
+		}
+		res.MinVersion = v
+	}
+	if cfg.RootCAs != "" {
+		data, err := os.ReadFile(cfg.RootCAs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read root CA certificates: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid certificates in %s", cfg.RootCAs)
+		}
+		res.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, errors.New("both client certificate and key must be specified")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		res.Certificates = []tls.Certificate{cert}
+	}
+	return res, nil
+}
+
+// newResolver returns a resolver using the DNS server with the given address
+// or the default one if it's empty.
+func newResolver(server string) *net.Resolver {
+	if server == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// newPinningDialer returns a DialContext function that resolves the host
+// once, checks resolved addresses (if private hosts are not allowed) and
+// connects to exactly the address checked, so that DNS response can't be
+// changed between the check and connection (DNS rebinding).
+func newPinningDialer(d *net.Dialer, r *net.Resolver, allowPrivate bool) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := r.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var firstErr error
+		for _, ip := range ips {
+			var conn net.Conn
+			if !allowPrivate && isReserved(ip.IP) {
+				err = fmt.Errorf("%w: IP is not global unicast", ErrRestrictedRedirect)
+			} else {
+				conn, err = d.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+				if err == nil {
+					return conn, nil
+				}
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("no addresses found for %s", host)
+		}
+		return nil, firstErr
+	}
+}
+
+func getDefaultClient(cfg config.OracleConfiguration) (*http.Client, error) {
+	tlsCfg, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
 	d := &net.Dialer{}
 	if !cfg.AllowPrivateHost {
-		// Control is used after request URI is resolved and network connection (network
-		// file descriptor) is created, but right before listening/dialing
-		// is started.
-		// `address` represents a resolved IP address in the format of ip:port. `address`
-		// is presented in its final (resolved) form that was used directly for network
-		// connection establishing.
-		// Control is called for each item in the set of IP addresses got from request
-		// URI resolving. The first network connection with address that passes Control
-		// function will be used for further request processing. Network connection
-		// with address that failed Control will be ignored. If all the connections
-		// fail Control, the most relevant error (the one from the first address)
-		// will be returned after `Client.Do`.
+		// Control is called right before dialing with the IP address
+		// being connected to. Addresses are already checked by the pinning
+		// dialer, so it's just an additional safety net for connections
+		// made with this dialer.
 		d.Control = func(network, address string, c syscall.RawConn) error {
 			host, _, err := net.SplitHostPort(address)
 			if err != nil {
@@ -83,7 +183,8 @@ func getDefaultClient(cfg config.OracleConfiguration) *http.Client {
 		// Do not set DialTLSContext, so that DialContext will be used to establish the
 		// connection. After that, TLS connection will be added to a persistent connection
 		// by standard library code and handshaking will be performed.
-		DialContext: d.DialContext,
+		DialContext:     newPinningDialer(d, newResolver(cfg.Resolver), cfg.AllowPrivateHost),
+		TLSClientConfig: tlsCfg,
 	}
 	client.Timeout = cfg.RequestTimeout
 	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
@@ -96,5 +197,5 @@ func getDefaultClient(cfg config.OracleConfiguration) *http.Client {
 		}
 		return nil
 	}
-	return &client
+	return &client, nil
 }
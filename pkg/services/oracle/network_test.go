@@ -1,14 +1,27 @@
 package oracle
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
+	"math/big"
 	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 func TestIsReserved(t *testing.T) {
@@ -26,7 +39,8 @@ func TestDefaultClient_RestrictedRedirectErr(t *testing.T) {
 		AllowPrivateHost: false,
 		RequestTimeout:   time.Second,
 	}
-	cl := getDefaultClient(cfg)
+	cl, err := getDefaultClient(cfg)
+	require.NoError(t, err)
 
 	testCases := []string{
 		"http://localhost:8080",
@@ -47,3 +61,179 @@ func TestDefaultClient_RestrictedRedirectErr(t *testing.T) {
 		})
 	}
 }
+
+// runTestDNS starts a DNS server answering all A queries with the given IP
+// and returns its address.
+func runTestDNS(t *testing.T, ip net.IP) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var msg dnsmessage.Message
+			if err := msg.Unpack(buf[:n]); err != nil || len(msg.Questions) == 0 {
+				continue
+			}
+			msg.Header.Response = true
+			msg.Header.Authoritative = true
+			q := msg.Questions[0]
+			if q.Type == dnsmessage.TypeA {
+				var a [4]byte
+				copy(a[:], ip.To4())
+				msg.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+					Body:   &dnsmessage.AResource{A: a},
+				}}
+			}
+			resp, err := msg.Pack()
+			if err == nil {
+				_, _ = pc.WriteTo(resp, addr)
+			}
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestDefaultClient_Resolver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	url := "http://rebind.example.com:" + port
+
+	cfg := config.OracleConfiguration{
+		RequestTimeout: time.Second,
+		Resolver:       runTestDNS(t, net.IPv4(127, 0, 0, 1)),
+	}
+	t.Run("loopback", func(t *testing.T) {
+		cl, err := getDefaultClient(cfg)
+		require.NoError(t, err)
+		_, err = cl.Get(url) //nolint:bodyclose // It errors out and it's a test.
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrRestrictedRedirect), err)
+	})
+	t.Run("private allowed", func(t *testing.T) {
+		cfg := cfg
+		cfg.AllowPrivateHost = true
+		cl, err := getDefaultClient(cfg)
+		require.NoError(t, err)
+		resp, err := cl.Get(url)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+// genClientCert generates a self-signed client certificate and a key for it
+// and stores them in the given directory returning file paths.
+func genClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "oracle"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return cert, certFile, keyFile
+}
+
+func TestDefaultClient_TLS(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := genClientCert(t, dir)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0600))
+	badFile := filepath.Join(dir, "bad.crt")
+	require.NoError(t, os.WriteFile(badFile, []byte("not a certificate"), 0600))
+
+	get := func(t *testing.T, tlsCfg config.OracleTLS) error {
+		cl, err := getDefaultClient(config.OracleConfiguration{
+			AllowPrivateHost: true,
+			RequestTimeout:   time.Second,
+			TLS:              tlsCfg,
+		})
+		require.NoError(t, err)
+		resp, err := cl.Get(srv.URL)
+		if err == nil {
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.NoError(t, resp.Body.Close())
+		}
+		return err
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		cl, err := getDefaultClient(config.OracleConfiguration{})
+		require.NoError(t, err)
+		require.Nil(t, cl.Transport.(*http.Transport).TLSClientConfig)
+		require.Error(t, get(t, config.OracleTLS{})) // Unknown authority.
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for name, cfg := range map[string]config.OracleTLS{
+			"min version":     {MinVersion: "2.0"},
+			"missing CA":      {RootCAs: filepath.Join(dir, "missing.crt")},
+			"bad CA":          {RootCAs: badFile},
+			"missing key":     {CertFile: certFile},
+			"bad certificate": {CertFile: badFile, KeyFile: keyFile},
+		} {
+			_, err := getDefaultClient(config.OracleConfiguration{TLS: cfg})
+			require.Error(t, err, name)
+		}
+	})
+	t.Run("no client certificate", func(t *testing.T) {
+		require.Error(t, get(t, config.OracleTLS{RootCAs: caFile}))
+	})
+	t.Run("insecure", func(t *testing.T) {
+		require.NoError(t, get(t, config.OracleTLS{
+			CertFile:           certFile,
+			KeyFile:            keyFile,
+			InsecureSkipVerify: true,
+		}))
+	})
+	t.Run("good", func(t *testing.T) {
+		cfg := config.OracleTLS{
+			MinVersion: "1.3",
+			RootCAs:    caFile,
+			CertFile:   certFile,
+			KeyFile:    keyFile,
+		}
+		tlsCfg, err := newTLSConfig(cfg)
+		require.NoError(t, err)
+		require.Equal(t, uint16(tls.VersionTLS13), tlsCfg.MinVersion)
+		require.NoError(t, get(t, cfg))
+	})
+}
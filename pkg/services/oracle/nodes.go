@@ -29,6 +29,12 @@ func (o *Oracle) UpdateOracleNodes(oracleNodes keys.PublicKeys) {
 	var acc *wallet.Account
 	for i := range oracleNodes {
 		acc = o.wallet.GetAccount(oracleNodes[i].GetScriptHash())
+		if acc != nil && acc.IsRemote() {
+			o.Log.Error("remote signer accounts can't be used for oracle",
+				zap.String("address", acc.Address))
+			acc = nil
+			continue
+		}
 		if acc != nil {
 			if acc.CanSign() {
 				break
@@ -48,6 +54,13 @@ func (o *Oracle) UpdateOracleNodes(oracleNodes keys.PublicKeys) {
 	o.currAccount = acc
 	o.oracleSignContract, _ = smartcontract.CreateDefaultMultiSigRedeemScript(oracleNodes)
 	o.oracleNodes = oracleNodes
+	// Cached transactions are signed by the old oracle nodes' multisig
+	// account and can't be used anymore.
+	if old != nil {
+		if err := o.cache.clear(); err != nil {
+			o.Log.Warn("failed to update oracle response cache", zap.Error(err))
+		}
+	}
 }
 
 func (o *Oracle) getAccount() *wallet.Account {
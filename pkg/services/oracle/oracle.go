@@ -2,6 +2,7 @@ package oracle
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -28,9 +30,12 @@ type (
 		FeePerByte() int64
 		GetBaseExecFee() int64
 		GetConfig() config.ProtocolConfiguration
+		GetDesignatedByRole(r noderoles.Role, index uint32) (keys.PublicKeys, uint32, error)
 		GetMaxVerificationGAS() int64
 		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
 		GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
+		SubscribeForRoleChanges(ch chan *state.RoleChangeEvent)
+		UnsubscribeFromRoleChanges(ch chan *state.RoleChangeEvent)
 	}
 
 	// Oracle represents an oracle module capable of talking
@@ -54,6 +59,7 @@ type (
 		done       chan struct{}
 		requestCh  chan request
 		requestMap chan map[uint64]*state.OracleRequest
+		roleCh     chan *state.RoleChangeEvent
 
 		// respMtx protects responses and pending maps.
 		respMtx sync.RWMutex
@@ -65,6 +71,10 @@ type (
 		responses map[uint64]*incompleteTx
 		// removed contains ids of requests which won't be processed further due to expiration.
 		removed map[uint64]bool
+		// cache persists signed responses between restarts, it's nil if disabled.
+		cache *responseCache
+		// limiter limits outbound requests rate, it's nil if disabled.
+		limiter *rateLimiter
 
 		wallet *wallet.Wallet
 	}
@@ -78,6 +88,14 @@ type (
 		Chain           Ledger
 		ResponseHandler Broadcaster
 		OnTransaction   TxCallback
+		// PostProcessor is an optional response transformation applied after
+		// the request filter, nil by default.
+		PostProcessor ResponsePostProcessor
+		// AllowFileScheme enables file:// requests reading local files
+		// located in the FileSchemeRoot directory. It's intended for testing
+		// and trusted on-host data only and is disabled by default.
+		AllowFileScheme bool
+		FileSchemeRoot  string
 	}
 
 	// HTTPClient is an interface capable of doing oracle requests.
@@ -94,6 +112,11 @@ type (
 
 	// TxCallback executes on new transactions when they are ready to be pooled.
 	TxCallback = func(tx *transaction.Transaction) error
+
+	// ResponsePostProcessor transforms successful (and filtered) response
+	// data for the given request URL. An error makes the response fail
+	// with transaction.Error code.
+	ResponsePostProcessor = func(url string, body []byte) ([]byte, error)
 )
 
 const (
@@ -122,6 +145,7 @@ func NewOracle(cfg Config) (*Oracle, error) {
 		close:      make(chan struct{}),
 		done:       make(chan struct{}),
 		requestMap: make(chan map[uint64]*state.OracleRequest, 1),
+		roleCh:     make(chan *state.RoleChangeEvent),
 		pending:    make(map[uint64]*state.OracleRequest),
 		responses:  make(map[uint64]*incompleteTx),
 		removed:    make(map[uint64]bool),
@@ -142,15 +166,30 @@ func NewOracle(cfg Config) (*Oracle, error) {
 	if o.MainCfg.RefreshInterval == 0 {
 		o.MainCfg.RefreshInterval = defaultRefreshInterval
 	}
+	o.limiter = newRateLimiter(o.MainCfg.MaxRequestsPerSecond)
+	if o.AllowFileScheme && o.FileSchemeRoot == "" {
+		return nil, errors.New("file scheme is allowed, but no root directory is specified")
+	}
 
 	var err error
+	if o.cache, err = newResponseCache(o.MainCfg.CachePath); err != nil {
+		return nil, err
+	}
 	w := cfg.MainCfg.UnlockWallet
 	if o.wallet, err = wallet.NewWalletFromFile(w.Path); err != nil {
 		return nil, err
 	}
+	if err := wallet.CheckPermissions(w.Path); err != nil {
+		o.Log.Warn("insecure wallet permissions", zap.Error(err))
+	}
 
+	// Remote signer accounts are not supported, oracle response messages and
+	// NeoFS requests are signed with the local key.
 	haveAccount := false
 	for _, acc := range o.wallet.Accounts {
+		if acc.IsRemote() {
+			continue
+		}
 		if err := acc.Decrypt(w.Password, o.wallet.Scrypt); err == nil {
 			haveAccount = true
 			break
@@ -167,7 +206,10 @@ func NewOracle(cfg Config) (*Oracle, error) {
 		o.OnTransaction = func(*transaction.Transaction) error { return nil }
 	}
 	if o.Client == nil {
-		o.Client = getDefaultClient(o.MainCfg)
+		o.Client, err = getDefaultClient(o.MainCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure oracle TLS: %w", err)
+		}
 	}
 	return o, nil
 }
@@ -212,6 +254,16 @@ func (o *Oracle) start() {
 	o.running = true
 	o.respMtx.Unlock()
 
+	o.Chain.SubscribeForRoleChanges(o.roleCh)
+	// Oracle nodes for the next block, subsequent changes are delivered
+	// via role change events.
+	nodes, _, err := o.Chain.GetDesignatedByRole(noderoles.Oracle, o.Chain.BlockHeight()+1)
+	if err != nil {
+		o.Log.Error("failed to get oracle nodes", zap.Error(err))
+	} else {
+		o.UpdateOracleNodes(nodes)
+	}
+
 	for i := 0; i < o.MainCfg.MaxConcurrentRequests; i++ {
 		go o.runRequestWorker()
 	}
@@ -223,6 +275,10 @@ main:
 		select {
 		case <-o.close:
 			break main
+		case ev := <-o.roleCh:
+			if ev.Role == noderoles.Oracle {
+				o.UpdateOracleNodes(ev.Nodes)
+			}
 		case <-tick.C:
 			var reprocess []uint64
 			o.respMtx.Lock()
@@ -242,6 +298,10 @@ main:
 			}
 			o.respMtx.Unlock()
 
+			if err := o.cache.removeExpired(o.Chain.BlockHeight()); err != nil {
+				o.Log.Warn("failed to update oracle response cache", zap.Error(err))
+			}
+
 			for _, id := range reprocess {
 				o.requestCh <- request{ID: id}
 			}
@@ -255,6 +315,7 @@ main:
 		}
 	}
 	tick.Stop()
+	o.Chain.UnsubscribeFromRoleChanges(o.roleCh)
 drain:
 	for {
 		select {
@@ -264,6 +325,7 @@ drain:
 		}
 	}
 	close(o.requestMap)
+	close(o.roleCh)
 	close(o.done)
 }
 
@@ -2,12 +2,16 @@ package oracle_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	gio "io"
 	"net/http"
+	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -128,6 +132,27 @@ func TestCreateResponseTx(t *testing.T) {
 	assert.Equal(t, 166, tx.Size())
 	assert.Equal(t, int64(2198650), tx.NetworkFee)
 	assert.Equal(t, int64(97801350), tx.SystemFee)
+
+	t.Run("check", func(t *testing.T) {
+		nodes := keys.PublicKeys{acc.PublicKey()}
+		require.Empty(t, oracle.CheckResponseTx(tx, req, nodes, bc.GetBaseExecFee(), bc.FeePerByte()))
+
+		cp := *tx
+		cp.SystemFee++
+		require.Equal(t, 1, len(oracle.CheckResponseTx(&cp, req, nodes, bc.GetBaseExecFee(), bc.FeePerByte())))
+
+		cp = *tx
+		cp.Nonce++
+		require.NotEmpty(t, oracle.CheckResponseTx(&cp, req, nodes, bc.GetBaseExecFee(), bc.FeePerByte()))
+
+		k, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		require.NotEmpty(t, oracle.CheckResponseTx(tx, req, keys.PublicKeys{k.PublicKey()}, bc.GetBaseExecFee(), bc.FeePerByte()))
+
+		cp = *tx
+		cp.Attributes = nil
+		require.NotEmpty(t, oracle.CheckResponseTx(&cp, req, nodes, bc.GetBaseExecFee(), bc.FeePerByte()))
+	})
 }
 
 func TestOracle_InvalidWallet(t *testing.T) {
@@ -191,6 +216,21 @@ func TestOracle(t *testing.T) {
 
 	putOracleRequest(t, cInvoker, "https://get.invalidcontent", nil, "handle", []byte{}, 10_000_000)
 
+	putOracleRequest(t, cInvoker, "https://get.gzip", &flt, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, "https://get.deflate", &flt, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, "https://get.gzipbomb", nil, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, "https://get.gzipinv", nil, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, "https://get.brotli", nil, "handle", []byte{}, 10_000_000)
+
+	postFlt := "POST $.id\n{\"id\":42}"
+	putOracleRequest(t, cInvoker, "https://post.echo", &postFlt, "handle", []byte{}, 10_000_000)
+	getFlt := "GET $.id"
+	putOracleRequest(t, cInvoker, "https://post.echo", &getFlt, "handle", []byte{}, 10_000_000)
+	postPrivFlt := "POST\n{}"
+	putOracleRequest(t, cInvoker, "https://private.url", &postPrivFlt, "handle", []byte{}, 10_000_000)
+	putFlt := "PUT\n{}"
+	putOracleRequest(t, cInvoker, "https://get.1234", &putFlt, "handle", []byte{}, 10_000_000)
+
 	checkResp := func(t *testing.T, id uint64, resp *transaction.OracleResponse) *state.OracleRequest {
 		// Use a hack to get request from Oracle contract, because we can't use GetRequestInternal directly.
 		requestKey := make([]byte, 9)
@@ -337,6 +377,232 @@ func TestOracle(t *testing.T) {
 			Code: transaction.ContentTypeNotSupported,
 		})
 	})
+	t.Run("Compressed", func(t *testing.T) {
+		t.Run("gzip", func(t *testing.T) {
+			checkResp(t, 12, &transaction.OracleResponse{
+				ID:     12,
+				Code:   transaction.Success,
+				Result: []byte(`[2]`),
+			})
+		})
+		t.Run("deflate", func(t *testing.T) {
+			checkResp(t, 13, &transaction.OracleResponse{
+				ID:     13,
+				Code:   transaction.Success,
+				Result: []byte(`[2]`),
+			})
+		})
+		t.Run("too big", func(t *testing.T) {
+			checkResp(t, 14, &transaction.OracleResponse{
+				ID:   14,
+				Code: transaction.ResponseTooLarge,
+			})
+		})
+		t.Run("corrupted", func(t *testing.T) {
+			checkResp(t, 15, &transaction.OracleResponse{
+				ID:   15,
+				Code: transaction.Error,
+			})
+		})
+		t.Run("unsupported encoding", func(t *testing.T) {
+			checkResp(t, 16, &transaction.OracleResponse{
+				ID:   16,
+				Code: transaction.Error,
+			})
+		})
+	})
+	t.Run("POST", func(t *testing.T) {
+		t.Run("good", func(t *testing.T) {
+			checkResp(t, 17, &transaction.OracleResponse{
+				ID:     17,
+				Code:   transaction.Success,
+				Result: []byte(`[42]`),
+			})
+		})
+		t.Run("GET to POST-only endpoint", func(t *testing.T) {
+			checkResp(t, 18, &transaction.OracleResponse{
+				ID:   18,
+				Code: transaction.Error,
+			})
+		})
+		t.Run("private network", func(t *testing.T) {
+			checkResp(t, 19, &transaction.OracleResponse{
+				ID:   19,
+				Code: transaction.Forbidden,
+			})
+		})
+		t.Run("unsupported method", func(t *testing.T) {
+			checkResp(t, 20, &transaction.OracleResponse{
+				ID:   20,
+				Code: transaction.Error,
+			})
+		})
+	})
+}
+
+func TestOracle_PostProcessor(t *testing.T) {
+	bc, validator, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validator, committee)
+	designationSuperInvoker := e.NewInvoker(e.NativeHash(t, nativenames.Designation), validator, committee)
+	nativeOracleH := e.NativeHash(t, nativenames.Oracle)
+	nativeOracleID := e.NativeID(t, nativenames.Oracle)
+
+	acc, orc, m, _ := getTestOracle(t, bc, "./testdata/oracle1.json", "one")
+	orc.PostProcessor = func(url string, body []byte) ([]byte, error) {
+		switch url {
+		case "https://get.1234":
+			return nil, errors.New("can't process")
+		case "https://get.maxallowed":
+			return append(body, 1), nil
+		}
+		return bytes.ToUpper(body), nil
+	}
+	oracleNodes := keys.PublicKeys{acc.PublicKey()}
+	designationSuperInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(roles.Oracle), []interface{}{oracleNodes[0].Bytes()})
+	orc.UpdateOracleNodes(oracleNodes.Copy())
+
+	nativeOracleState := bc.GetContractState(nativeOracleH)
+	require.NotNil(t, nativeOracleState)
+	md := nativeOracleState.Manifest.ABI.GetMethod(manifest.MethodVerify, -1)
+	require.NotNil(t, md)
+	orc.UpdateNativeContract(nativeOracleState.NEF.Script, native.CreateOracleResponseScript(nativeOracleH), nativeOracleH, md.Offset)
+
+	cs := contracts.GetOracleContractState(t, pathToInternalContracts, validator.ScriptHash(), 0)
+	e.DeployContract(t, &neotest.Contract{
+		Hash:     cs.Hash,
+		NEF:      &cs.NEF,
+		Manifest: &cs.Manifest,
+	}, nil)
+	cInvoker := e.ValidatorInvoker(cs.Hash)
+
+	flt := "$.Values[0]"
+	putOracleRequest(t, cInvoker, "https://get.filter", &flt, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, "https://get.1234", nil, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, "https://get.maxallowed", nil, "handle", []byte{}, 100_000_000)
+	putOracleRequest(t, cInvoker, "https://get.notfound", nil, "handle", []byte{}, 10_000_000)
+
+	checkResp := func(t *testing.T, id uint64, resp *transaction.OracleResponse) {
+		requestKey := make([]byte, 9)
+		requestKey[0] = 7 // prefixRequest from native Oracle contract
+		binary.BigEndian.PutUint64(requestKey[1:], id)
+		si := bc.GetStorageItem(nativeOracleID, requestKey)
+		require.NotNil(t, si)
+		req := new(state.OracleRequest)
+		require.NoError(t, stackitem.DeserializeConvertible(si, req))
+
+		orc.ProcessRequestsInternal(map[uint64]*state.OracleRequest{id: req})
+		require.NotNil(t, m[id])
+		require.Equal(t, resp, m[id].resp)
+	}
+	t.Run("filtered and processed", func(t *testing.T) {
+		checkResp(t, 0, &transaction.OracleResponse{
+			ID:     0,
+			Code:   transaction.Success,
+			Result: []byte(`["ONE"]`),
+		})
+	})
+	t.Run("error", func(t *testing.T) {
+		checkResp(t, 1, &transaction.OracleResponse{
+			ID:   1,
+			Code: transaction.Error,
+		})
+	})
+	t.Run("too large", func(t *testing.T) {
+		checkResp(t, 2, &transaction.OracleResponse{
+			ID:   2,
+			Code: transaction.ResponseTooLarge,
+		})
+	})
+	t.Run("not applied to failed requests", func(t *testing.T) {
+		checkResp(t, 3, &transaction.OracleResponse{
+			ID:   3,
+			Code: transaction.NotFound,
+		})
+	})
+}
+
+func TestOracle_FileScheme(t *testing.T) {
+	bc, validator, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validator, committee)
+	designationSuperInvoker := e.NewInvoker(e.NativeHash(t, nativenames.Designation), validator, committee)
+	nativeOracleH := e.NativeHash(t, nativenames.Oracle)
+	nativeOracleID := e.NativeID(t, nativenames.Oracle)
+
+	root := t.TempDir()
+	feed := filepath.Join(root, "feed.json")
+	require.NoError(t, os.WriteFile(feed, []byte(`{"Values":["one", 2]}`), 0o644))
+	outside := filepath.Join(t.TempDir(), "secret.json")
+	require.NoError(t, os.WriteFile(outside, []byte(`{}`), 0o644))
+
+	acc, orc, m, _ := getTestOracle(t, bc, "./testdata/oracle1.json", "one")
+	orc.AllowFileScheme = true
+	orc.FileSchemeRoot = root
+	oracleNodes := keys.PublicKeys{acc.PublicKey()}
+	designationSuperInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(roles.Oracle), []interface{}{oracleNodes[0].Bytes()})
+	orc.UpdateOracleNodes(oracleNodes.Copy())
+
+	nativeOracleState := bc.GetContractState(nativeOracleH)
+	require.NotNil(t, nativeOracleState)
+	md := nativeOracleState.Manifest.ABI.GetMethod(manifest.MethodVerify, -1)
+	require.NotNil(t, md)
+	orc.UpdateNativeContract(nativeOracleState.NEF.Script, native.CreateOracleResponseScript(nativeOracleH), nativeOracleH, md.Offset)
+
+	cs := contracts.GetOracleContractState(t, pathToInternalContracts, validator.ScriptHash(), 0)
+	e.DeployContract(t, &neotest.Contract{
+		Hash:     cs.Hash,
+		NEF:      &cs.NEF,
+		Manifest: &cs.Manifest,
+	}, nil)
+	cInvoker := e.ValidatorInvoker(cs.Hash)
+
+	feedURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(feed)}).String()
+	flt := "$.Values[0]"
+	putOracleRequest(t, cInvoker, feedURL, &flt, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, (&url.URL{Scheme: "file", Path: filepath.ToSlash(outside)}).String(), nil, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, (&url.URL{Scheme: "file", Path: filepath.ToSlash(filepath.Join(root, "missing.json"))}).String(), nil, "handle", []byte{}, 10_000_000)
+	putOracleRequest(t, cInvoker, feedURL, nil, "handle", []byte{}, 10_000_000)
+
+	checkResp := func(t *testing.T, id uint64, resp *transaction.OracleResponse) {
+		requestKey := make([]byte, 9)
+		requestKey[0] = 7 // prefixRequest from native Oracle contract
+		binary.BigEndian.PutUint64(requestKey[1:], id)
+		si := bc.GetStorageItem(nativeOracleID, requestKey)
+		require.NotNil(t, si)
+		req := new(state.OracleRequest)
+		require.NoError(t, stackitem.DeserializeConvertible(si, req))
+
+		orc.ProcessRequestsInternal(map[uint64]*state.OracleRequest{id: req})
+		require.NotNil(t, m[id])
+		require.Equal(t, resp, m[id].resp)
+	}
+	t.Run("filtered", func(t *testing.T) {
+		checkResp(t, 0, &transaction.OracleResponse{
+			ID:     0,
+			Code:   transaction.Success,
+			Result: []byte(`["one"]`),
+		})
+	})
+	t.Run("outside of root", func(t *testing.T) {
+		checkResp(t, 1, &transaction.OracleResponse{
+			ID:   1,
+			Code: transaction.Forbidden,
+		})
+	})
+	t.Run("not found", func(t *testing.T) {
+		checkResp(t, 2, &transaction.OracleResponse{
+			ID:   2,
+			Code: transaction.NotFound,
+		})
+	})
+	t.Run("disabled", func(t *testing.T) {
+		orc.AllowFileScheme = false
+		checkResp(t, 3, &transaction.OracleResponse{
+			ID:   3,
+			Code: transaction.ProtocolNotSupported,
+		})
+	})
 }
 
 func TestOracleFull(t *testing.T) {
@@ -472,6 +738,7 @@ type (
 	testResponse struct {
 		code int
 		ct   string
+		ce   string
 		body []byte
 	}
 )
@@ -481,19 +748,51 @@ func (c *httpClient) Do(req *http.Request) (*http.Response, error) {
 	if c.returnOracleRedirectionErrOn != nil && c.returnOracleRedirectionErrOn(req.URL.String()) {
 		return nil, fmt.Errorf("%w: private network", oracle.ErrRestrictedRedirect)
 	}
+	if req.URL.String() == "https://post.echo" {
+		return echoPOST(req)
+	}
 	resp, ok := c.responses[req.URL.String()]
 	if ok {
-		return &http.Response{
+		r := &http.Response{
 			StatusCode: resp.code,
 			Header: http.Header{
 				"Content-Type": {resp.ct},
 			},
 			Body: newResponseBody(resp.body),
-		}, nil
+		}
+		if resp.ce != "" {
+			r.Header.Set("Content-Encoding", resp.ce)
+		}
+		return r, nil
 	}
 	return nil, errors.New("request failed")
 }
 
+// echoPOST returns POST request body as a response.
+func echoPOST(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return &http.Response{
+			StatusCode: http.StatusMethodNotAllowed,
+			Body:       newResponseBody(nil),
+		}, nil
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = gio.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: newResponseBody(body),
+	}, nil
+}
+
 func newDefaultHTTPClient(returnOracleRedirectionErrOn func(address string) bool) oracle.HTTPClient {
 	return &httpClient{
 		returnOracleRedirectionErrOn: returnOracleRedirectionErrOn,
@@ -553,10 +852,63 @@ func newDefaultHTTPClient(returnOracleRedirectionErrOn func(address string) bool
 				ct:   "image/gif",
 				body: []byte{1, 2, 3},
 			},
+			"https://get.gzip": {
+				code: http.StatusOK,
+				ct:   "application/json",
+				ce:   "gzip",
+				body: compress("gzip", []byte(`{"Values":["one", 2, 3],"Another":null}`)),
+			},
+			"https://get.deflate": {
+				code: http.StatusOK,
+				ct:   "application/json",
+				ce:   "deflate",
+				body: compress("deflate", []byte(`{"Values":["one", 2, 3],"Another":null}`)),
+			},
+			"https://get.gzipbomb": {
+				code: http.StatusOK,
+				ct:   "application/json",
+				ce:   "gzip",
+				body: compress("gzip", make([]byte, transaction.MaxOracleResultSize+1)),
+			},
+			"https://get.gzipinv": {
+				code: http.StatusOK,
+				ct:   "application/json",
+				ce:   "gzip",
+				body: compress("gzip", []byte(`{"Values":["one", 2, 3],"Another":null}`))[:20],
+			},
+			"https://get.brotli": {
+				code: http.StatusOK,
+				ct:   "application/json",
+				ce:   "br",
+				body: []byte{1, 2, 3},
+			},
 		},
 	}
 }
 
+// compress compresses data using the given HTTP content encoding.
+func compress(encoding string, data []byte) []byte {
+	var (
+		buf bytes.Buffer
+		w   gio.WriteCloser
+	)
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	default:
+		panic(encoding)
+	}
+	if _, err := w.Write(data); err != nil {
+		panic(err)
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
 func newResponseBody(resp []byte) gio.ReadCloser {
 	return gio.NopCloser(bytes.NewReader(resp))
 }
@@ -1,8 +1,10 @@
 package oracle
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"mime"
 	"net/http"
 	"net/url"
@@ -11,8 +13,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
-	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/services/oracle/neofs"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap"
 )
 
@@ -33,7 +35,7 @@ func (o *Oracle) runRequestWorker() {
 			if acc == nil {
 				continue
 			}
-			err := o.processRequest(acc.PrivateKey(), req)
+			err := o.processRequest(acc, req)
 			if err != nil {
 				o.Log.Debug("can't process request", zap.Uint64("id", req.ID), zap.Error(err))
 			}
@@ -44,6 +46,9 @@ func (o *Oracle) runRequestWorker() {
 // RemoveRequests removes all data associated with requests
 // which have been processed by oracle contract.
 func (o *Oracle) RemoveRequests(ids []uint64) {
+	if err := o.cache.remove(ids); err != nil {
+		o.Log.Warn("failed to update oracle response cache", zap.Error(err))
+	}
 	o.respMtx.Lock()
 	defer o.respMtx.Unlock()
 	if !o.running {
@@ -96,15 +101,15 @@ func (o *Oracle) ProcessRequestsInternal(reqs map[uint64]*state.OracleRequest) {
 
 	// Process actual requests.
 	for id, req := range reqs {
-		if err := o.processRequest(acc.PrivateKey(), request{ID: id, Req: req}); err != nil {
+		if err := o.processRequest(acc, request{ID: id, Req: req}); err != nil {
 			o.Log.Debug("can't process request", zap.Error(err))
 		}
 	}
 }
 
-func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
+func (o *Oracle) processRequest(acc *wallet.Account, req request) error {
 	if req.Req == nil {
-		o.processFailedRequest(priv, req)
+		o.processFailedRequest(acc, req)
 		return nil
 	}
 
@@ -112,15 +117,36 @@ func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
 	if incTx == nil {
 		return nil
 	}
+	if cached := o.getCachedResponse(acc, req.ID); cached != nil {
+		o.Log.Debug("using cached oracle response", zap.Uint64("request", req.ID))
+		o.sendSignedResponse(acc, req.Req, incTx, responseFromTx(cached.tx), cached)
+		return nil
+	}
 	resp := &transaction.OracleResponse{ID: req.ID, Code: transaction.Success}
+	flt, fltErr := parseFilter(req.Req.Filter)
 	u, err := url.ParseRequestURI(req.Req.URL)
 	if err != nil {
 		o.Log.Warn("malformed oracle request", zap.String("url", req.Req.URL), zap.Error(err))
 		resp.Code = transaction.ProtocolNotSupported
+	} else if fltErr != nil {
+		o.Log.Warn("invalid oracle request filter", zap.Uint64("request", req.ID), zap.Error(fltErr))
+		resp.Code = transaction.Error
 	} else {
 		switch u.Scheme {
 		case "https":
-			httpReq, err := http.NewRequest("GET", req.Req.URL, nil)
+			if err := o.waitForRateLimit(o.MainCfg.RequestTimeout); err != nil {
+				if !errors.Is(err, errRateLimitTimeout) {
+					return err
+				}
+				o.Log.Warn("oracle request is throttled", zap.String("url", req.Req.URL), zap.Error(err))
+				resp.Code = transaction.Timeout
+				break
+			}
+			var body io.Reader
+			if len(flt.Body) != 0 {
+				body = bytes.NewReader(flt.Body)
+			}
+			httpReq, err := http.NewRequest(flt.Method, req.Req.URL, body)
 			if err != nil {
 				o.Log.Warn("failed to create http request", zap.String("url", req.Req.URL), zap.Error(err))
 				resp.Code = transaction.Error
@@ -128,6 +154,7 @@ func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
 			}
 			httpReq.Header.Set("User-Agent", "NeoOracleService/3.0")
 			httpReq.Header.Set("Content-Type", "application/json")
+			httpReq.Header.Set("Accept-Encoding", "gzip, deflate")
 			r, err := o.Client.Do(httpReq)
 			if err != nil {
 				if errors.Is(err, ErrRestrictedRedirect) {
@@ -146,7 +173,14 @@ func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
 					break
 				}
 
-				resp.Result, err = readResponse(r.Body, transaction.MaxOracleResultSize)
+				body, err := decodeResponse(r.Body, r.Header.Get("Content-Encoding"))
+				if err != nil {
+					resp.Code = transaction.Error
+					o.Log.Warn("failed to decode data for oracle request", zap.String("url", req.Req.URL), zap.Error(err))
+					break
+				}
+				resp.Result, err = readResponse(body, transaction.MaxOracleResultSize)
+				body.Close()
 				if err != nil {
 					if errors.Is(err, ErrResponseTooLarge) {
 						resp.Code = transaction.ResponseTooLarge
@@ -166,26 +200,67 @@ func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
 				resp.Code = transaction.Error
 			}
 		case neofs.URIScheme:
+			if flt.Method != http.MethodGet {
+				o.Log.Warn("unsupported method for NeoFS oracle request", zap.String("url", req.Req.URL), zap.String("method", flt.Method))
+				resp.Code = transaction.ProtocolNotSupported
+				break
+			}
+			if err := o.waitForRateLimit(o.MainCfg.NeoFS.Timeout); err != nil {
+				if !errors.Is(err, errRateLimitTimeout) {
+					return err
+				}
+				o.Log.Warn("oracle request is throttled", zap.String("url", req.Req.URL), zap.Error(err))
+				resp.Code = transaction.Timeout
+				break
+			}
 			ctx, cancel := context.WithTimeout(context.Background(), o.MainCfg.NeoFS.Timeout)
 			defer cancel()
 			index := (int(req.ID) + incTx.attempts) % len(o.MainCfg.NeoFS.Nodes)
-			resp.Result, err = neofs.Get(ctx, priv, u, o.MainCfg.NeoFS.Nodes[index])
+			resp.Result, err = neofs.Get(ctx, acc.PrivateKey(), u, o.MainCfg.NeoFS.Nodes[index])
 			if err != nil {
 				o.Log.Warn("oracle request failed", zap.String("url", req.Req.URL), zap.Error(err))
 				resp.Code = transaction.Error
 			}
+		case fileScheme:
+			if !o.AllowFileScheme {
+				resp.Code = transaction.ProtocolNotSupported
+				o.Log.Warn("file scheme is not allowed", zap.String("url", req.Req.URL))
+				break
+			}
+			if flt.Method != http.MethodGet {
+				o.Log.Warn("unsupported method for file oracle request", zap.String("url", req.Req.URL), zap.String("method", flt.Method))
+				resp.Code = transaction.ProtocolNotSupported
+				break
+			}
+			resp.Result, err = readFile(o.FileSchemeRoot, u)
+			if err != nil {
+				resp.Code = fileErrorCode(err)
+				o.Log.Warn("oracle request failed", zap.String("url", req.Req.URL), zap.Error(err), zap.Stringer("code", resp.Code))
+			}
 		default:
 			resp.Code = transaction.ProtocolNotSupported
 			o.Log.Warn("unknown oracle request scheme", zap.String("url", req.Req.URL))
 		}
 	}
 	if resp.Code == transaction.Success {
-		resp.Result, err = filterRequest(resp.Result, req.Req)
+		resp.Result, err = flt.apply(resp.Result)
 		if err != nil {
 			o.Log.Warn("oracle filter failed", zap.Uint64("request", req.ID), zap.Error(err))
 			resp.Code = transaction.Error
 		}
 	}
+	if resp.Code == transaction.Success && o.PostProcessor != nil {
+		resp.Result, err = o.PostProcessor(req.Req.URL, resp.Result)
+		if err != nil {
+			o.Log.Warn("oracle response post-processing failed", zap.Uint64("request", req.ID), zap.Error(err))
+			resp.Code = transaction.Error
+			resp.Result = nil
+		} else if len(resp.Result) > transaction.MaxOracleResultSize {
+			o.Log.Warn("post-processed oracle response is too large", zap.Uint64("request", req.ID), zap.Int("size", len(resp.Result)))
+			resp.Code = transaction.ResponseTooLarge
+			resp.Result = nil
+		}
+	}
 	o.Log.Debug("oracle request processed", zap.String("url", req.Req.URL), zap.Int("code", int(resp.Code)), zap.String("result", string(resp.Result)))
 
 	currentHeight := o.Chain.BlockHeight()
@@ -214,17 +289,59 @@ func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
 		return err
 	}
 
-	incTx.Lock()
-	incTx.request = req.Req
-	incTx.tx = tx
-	incTx.backupTx = backupTx
-	incTx.reverifyTx(o.Network)
+	signed := &cachedResponse{
+		tx:        tx,
+		txSig:     acc.SignHashable(o.Network, tx),
+		backupTx:  backupTx,
+		backupSig: acc.SignHashable(o.Network, backupTx),
+	}
+	if err := o.cache.put(req.ID, signed); err != nil {
+		o.Log.Warn("failed to update oracle response cache", zap.Uint64("request", req.ID), zap.Error(err))
+	}
+	o.sendSignedResponse(acc, req.Req, incTx, resp, signed)
+	return nil
+}
 
-	txSig := priv.SignHashable(uint32(o.Network), tx)
-	incTx.addResponse(priv.PublicKey(), txSig, false)
+// waitForRateLimit waits for the outbound request to be allowed by the rate
+// limiter, the request is not started if it has to wait for more than the
+// given timeout.
+func (o *Oracle) waitForRateLimit(timeout time.Duration) error {
+	return o.limiter.wait(time.Now().Add(timeout), o.close)
+}
 
-	backupSig := priv.SignHashable(uint32(o.Network), backupTx)
-	incTx.addResponse(priv.PublicKey(), backupSig, true)
+// getCachedResponse returns the response signed by the given account before
+// restart if it's still valid (including the case of oracle nodes changed
+// while the node was down).
+func (o *Oracle) getCachedResponse(acc *wallet.Account, id uint64) *cachedResponse {
+	cached := o.cache.get(id)
+	if cached == nil || cached.tx.ValidUntilBlock <= o.Chain.BlockHeight() || responseFromTx(cached.tx) == nil {
+		return nil
+	}
+	signContract := o.getOracleSignContract()
+	if len(cached.tx.Scripts) != 2 || len(cached.backupTx.Scripts) != 2 ||
+		!bytes.Equal(cached.tx.Scripts[1].VerificationScript, signContract) ||
+		!bytes.Equal(cached.backupTx.Scripts[1].VerificationScript, signContract) {
+		return nil
+	}
+	pub := acc.PublicKey()
+	if !pub.VerifyHashable(cached.txSig, uint32(o.Network), cached.tx) ||
+		!pub.VerifyHashable(cached.backupSig, uint32(o.Network), cached.backupTx) {
+		return nil
+	}
+	return cached
+}
+
+// sendSignedResponse adds our signatures to the incomplete transaction,
+// broadcasts them and sends the transaction if it's complete.
+func (o *Oracle) sendSignedResponse(acc *wallet.Account, req *state.OracleRequest, incTx *incompleteTx,
+	resp *transaction.OracleResponse, signed *cachedResponse) {
+	incTx.Lock()
+	incTx.request = req
+	incTx.tx = signed.tx
+	incTx.backupTx = signed.backupTx
+	incTx.reverifyTx(o.Network)
+	incTx.addResponse(acc.PublicKey(), signed.txSig, false)
+	incTx.addResponse(acc.PublicKey(), signed.backupSig, true)
 
 	readyTx, ready := incTx.finalize(o.getOracleNodes(), false)
 	if ready {
@@ -235,14 +352,13 @@ func (o *Oracle) processRequest(priv *keys.PrivateKey, req request) error {
 	incTx.attempts++
 	incTx.Unlock()
 
-	o.ResponseHandler.SendResponse(priv, resp, txSig)
+	o.ResponseHandler.SendResponse(acc.PrivateKey(), resp, signed.txSig)
 	if ready {
 		o.sendTx(readyTx)
 	}
-	return nil
 }
 
-func (o *Oracle) processFailedRequest(priv *keys.PrivateKey, req request) {
+func (o *Oracle) processFailedRequest(acc *wallet.Account, req request) {
 	// Request is being processed again.
 	incTx := o.getResponse(req.ID, false)
 	if incTx == nil {
@@ -263,10 +379,10 @@ func (o *Oracle) processFailedRequest(priv *keys.PrivateKey, req request) {
 	}
 	incTx.time = time.Now()
 	incTx.attempts++
-	txSig := incTx.backupSigs[string(priv.PublicKey().Bytes())].sig
+	txSig := incTx.backupSigs[string(acc.PublicKey().Bytes())].sig
 	incTx.Unlock()
 
-	o.ResponseHandler.SendResponse(priv, getFailedResponse(req.ID), txSig)
+	o.ResponseHandler.SendResponse(acc.PrivateKey(), getFailedResponse(req.ID), txSig)
 	if ready {
 		o.sendTx(readyTx)
 	}
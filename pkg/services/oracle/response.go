@@ -1,19 +1,30 @@
 package oracle
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	gio "io"
+	"strings"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/zap"
 )
 
@@ -68,31 +79,77 @@ func (o *Oracle) AddResponse(pub *keys.PublicKey, reqID uint64, txSig []byte) {
 var ErrResponseTooLarge = errors.New("too big response")
 
 func readResponse(rc gio.ReadCloser, limit int) ([]byte, error) {
-	buf := make([]byte, limit+1)
-	n, err := gio.ReadFull(rc, buf)
-	if errors.Is(err, gio.ErrUnexpectedEOF) && n <= limit {
-		return buf[:n], nil
+	// Decompressing readers can return io.ErrUnexpectedEOF for truncated
+	// streams, so it can't be used as a successful read indicator here.
+	buf, err := gio.ReadAll(gio.LimitReader(rc, int64(limit)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) == 0 {
+		return nil, gio.EOF // Empty response is treated as an error.
 	}
-	if err == nil || n > limit {
+	if len(buf) > limit {
 		return nil, ErrResponseTooLarge
 	}
-	return nil, err
+	return buf, nil
+}
+
+// decodeResponse returns a reader decompressing the response body according to
+// the given Content-Encoding header value. Only gzip and deflate encodings are
+// supported. The result should still be read with readResponse to limit the
+// decompressed data size.
+func decodeResponse(body gio.Reader, encoding string) (gio.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return gio.NopCloser(body), nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "deflate":
+		// Deflate is supposed to be zlib-wrapped, but some servers send raw
+		// deflate streams.
+		br := bufio.NewReader(body)
+		hdr, err := br.Peek(2)
+		if err == nil && hdr[0]&0x0f == 8 && (uint16(hdr[0])<<8|uint16(hdr[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
 }
 
 // CreateResponseTx creates an unsigned oracle response transaction.
 func (o *Oracle) CreateResponseTx(gasForResponse int64, vub uint32, resp *transaction.OracleResponse) (*transaction.Transaction, error) {
-	tx := transaction.New(o.oracleResponse, 0)
+	tx := NewResponseTx(o.oracleResponse, o.oracleHash, o.getOracleSignContract(), vub, resp)
+
+	gasConsumed, ok, err := o.testVerify(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare `verify` invocation: %w", err)
+	}
+	if !ok {
+		return nil, errors.New("can't verify transaction")
+	}
+	CalculateResponseFees(tx, gasForResponse, gasConsumed, o.Chain.GetBaseExecFee(), o.Chain.FeePerByte())
+	return tx, nil
+}
+
+// NewResponseTx creates an unsigned oracle response transaction with the given
+// script (that is expected to call native Oracle contract's `finish` method),
+// response attribute and signers, but without fees set. The first witness of
+// this transaction is empty (it's the native contract one) and the second one
+// has the oracleSignContract verification script, but no invocation script.
+// Fees can be calculated via CalculateResponseFees after that.
+func NewResponseTx(script []byte, oracleHash util.Uint160, oracleSignContract []byte, vub uint32, resp *transaction.OracleResponse) *transaction.Transaction {
+	tx := transaction.New(script, 0)
 	tx.Nonce = uint32(resp.ID)
 	tx.ValidUntilBlock = vub
 	tx.Attributes = []transaction.Attribute{{
 		Type:  transaction.OracleResponseT,
 		Value: resp,
 	}}
-
-	oracleSignContract := o.getOracleSignContract()
 	tx.Signers = []transaction.Signer{
 		{
-			Account: o.oracleHash,
+			Account: oracleHash,
 			Scopes:  transaction.None,
 		},
 		{
@@ -102,37 +159,123 @@ func (o *Oracle) CreateResponseTx(gasForResponse int64, vub uint32, resp *transa
 	}
 	tx.Scripts = []transaction.Witness{
 		{}, // native contract witness is fixed, second witness is set later.
+		{VerificationScript: oracleSignContract},
 	}
+	return tx
+}
 
-	// Calculate network fee.
+// CalculateResponseFees sets network and system fees of the transaction
+// created by NewResponseTx. verifyGas is the amount of GAS consumed by the
+// native Oracle contract `verify` method for this transaction, baseExecFee and
+// feePerByte are current Policy contract settings. If gasForResponse is not
+// enough to pay the network fee, the response code is changed to
+// InsufficientFunds and its result is dropped. The rest of gasForResponse is
+// used as a system fee.
+func CalculateResponseFees(tx *transaction.Transaction, gasForResponse int64, verifyGas int64, baseExecFee int64, feePerByte int64) {
+	scripts := tx.Scripts
+	tx.Scripts = scripts[:1]
 	size := io.GetVarSize(tx)
-	tx.Scripts = append(tx.Scripts, transaction.Witness{VerificationScript: oracleSignContract})
-
-	gasConsumed, ok, err := o.testVerify(tx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to prepare `verify` invocation: %w", err)
-	}
-	if !ok {
-		return nil, errors.New("can't verify transaction")
-	}
-	tx.NetworkFee += gasConsumed
+	tx.Scripts = scripts
 
-	netFee, sizeDelta := fee.Calculate(o.Chain.GetBaseExecFee(), tx.Scripts[1].VerificationScript)
-	tx.NetworkFee += netFee
+	netFee, sizeDelta := fee.Calculate(baseExecFee, tx.Scripts[1].VerificationScript)
+	tx.NetworkFee = verifyGas + netFee
 	size += sizeDelta
 
-	currNetFee := tx.NetworkFee + int64(size)*o.Chain.FeePerByte()
+	currNetFee := tx.NetworkFee + int64(size)*feePerByte
 	if currNetFee > gasForResponse {
 		attrSize := io.GetVarSize(tx.Attributes)
+		resp := responseFromTx(tx)
 		resp.Code = transaction.InsufficientFunds
 		resp.Result = nil
 		size = size - attrSize + io.GetVarSize(tx.Attributes)
 	}
-	tx.NetworkFee += int64(size) * o.Chain.FeePerByte() // 233
+	tx.NetworkFee += int64(size) * feePerByte // 233
 
 	// Calculate system fee.
 	tx.SystemFee = gasForResponse - tx.NetworkFee
-	return tx, nil
+}
+
+// CheckResponseTx checks that the given oracle response transaction is the one
+// expected for the given request. oracleNodes are the nodes designated for
+// the Oracle role at the moment of response creation, baseExecFee and
+// feePerByte are Policy contract settings at the same moment. Response itself
+// and ValidUntilBlock are taken from tx and can't be checked. Verification
+// GAS can't be calculated without the chain state either, so it's derived
+// from the network fee of the transaction (and has to be non-negative). A list
+// of mismatches found is returned, it's empty for a correct transaction.
+func CheckResponseTx(tx *transaction.Transaction, req *state.OracleRequest, oracleNodes keys.PublicKeys,
+	baseExecFee int64, feePerByte int64) []string {
+	var mismatches []string
+	actual := responseFromTx(tx)
+	if actual == nil {
+		return []string{"no oracle response attribute"}
+	}
+	signContract, err := smartcontract.CreateDefaultMultiSigRedeemScript(oracleNodes)
+	if err != nil {
+		return []string{fmt.Sprintf("can't create oracle nodes contract: %s", err)}
+	}
+	if len(tx.Scripts) != 2 {
+		return []string{fmt.Sprintf("wrong number of witnesses: %d", len(tx.Scripts))}
+	}
+
+	oracleHash := state.CreateNativeContractHash(nativenames.Oracle)
+	resp := *actual
+	expected := NewResponseTx(native.CreateOracleResponseScript(oracleHash), oracleHash, signContract, tx.ValidUntilBlock, &resp)
+	netFee, sizeDelta := fee.Calculate(baseExecFee, signContract)
+	expected.Scripts = expected.Scripts[:1]
+	size := io.GetVarSize(expected) + sizeDelta
+	expected.Scripts = append(expected.Scripts, transaction.Witness{VerificationScript: signContract})
+	verifyGas := tx.NetworkFee - netFee - int64(size)*feePerByte
+	if verifyGas < 0 {
+		mismatches = append(mismatches, fmt.Sprintf("network fee %d is too low", tx.NetworkFee))
+	}
+	CalculateResponseFees(expected, int64(req.GasForResponse), verifyGas, baseExecFee, feePerByte)
+
+	if !bytes.Equal(tx.Script, expected.Script) {
+		mismatches = append(mismatches, "wrong script")
+	}
+	if tx.Nonce != expected.Nonce {
+		mismatches = append(mismatches, fmt.Sprintf("nonce: expected %d, got %d", expected.Nonce, tx.Nonce))
+	}
+	if tx.SystemFee != expected.SystemFee {
+		mismatches = append(mismatches, fmt.Sprintf("system fee: expected %d, got %d", expected.SystemFee, tx.SystemFee))
+	}
+	if tx.NetworkFee != expected.NetworkFee {
+		mismatches = append(mismatches, fmt.Sprintf("network fee: expected %d, got %d", expected.NetworkFee, tx.NetworkFee))
+	}
+	if len(tx.Attributes) != 1 {
+		mismatches = append(mismatches, fmt.Sprintf("wrong number of attributes: %d", len(tx.Attributes)))
+	}
+	if resp.Code != actual.Code {
+		mismatches = append(mismatches, fmt.Sprintf("response code: expected %s, got %s", resp.Code, actual.Code))
+	}
+	if len(tx.Signers) != len(expected.Signers) {
+		mismatches = append(mismatches, fmt.Sprintf("wrong number of signers: %d", len(tx.Signers)))
+	} else {
+		for i := range tx.Signers {
+			if tx.Signers[i].Account != expected.Signers[i].Account || tx.Signers[i].Scopes != expected.Signers[i].Scopes {
+				mismatches = append(mismatches, fmt.Sprintf("signer #%d: expected %s (%s), got %s (%s)", i,
+					expected.Signers[i].Account.StringLE(), expected.Signers[i].Scopes,
+					tx.Signers[i].Account.StringLE(), tx.Signers[i].Scopes))
+			}
+		}
+	}
+	if len(tx.Scripts[0].InvocationScript) != 0 || len(tx.Scripts[0].VerificationScript) != 0 {
+		mismatches = append(mismatches, "native contract witness is not empty")
+	}
+	if !bytes.Equal(tx.Scripts[1].VerificationScript, signContract) {
+		mismatches = append(mismatches, "wrong oracle nodes verification script")
+	}
+	return mismatches
+}
+
+func responseFromTx(tx *transaction.Transaction) *transaction.OracleResponse {
+	for i := range tx.Attributes {
+		if tx.Attributes[i].Type == transaction.OracleResponseT {
+			return tx.Attributes[i].Value.(*transaction.OracleResponse)
+		}
+	}
+	return nil
 }
 
 func (o *Oracle) testVerify(tx *transaction.Transaction) (int64, bool, error) {
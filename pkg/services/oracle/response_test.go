@@ -0,0 +1,69 @@
+package oracle
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	gio "io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	data := []byte(`{"Values":["one", 2, 3],"Another":null}`)
+	encode := func(t *testing.T, newWriter func(w gio.Writer) gio.WriteCloser) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		_, err := w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	check := func(t *testing.T, encoding string, body []byte) {
+		r, err := decodeResponse(bytes.NewReader(body), encoding)
+		require.NoError(t, err)
+		actual, err := readResponse(r, len(data))
+		require.NoError(t, err)
+		require.Equal(t, data, actual)
+	}
+
+	t.Run("identity", func(t *testing.T) {
+		check(t, "", data)
+		check(t, "identity", data)
+	})
+	t.Run("gzip", func(t *testing.T) {
+		body := encode(t, func(w gio.Writer) gio.WriteCloser { return gzip.NewWriter(w) })
+		check(t, "gzip", body)
+		check(t, " GZIP", body)
+		check(t, "x-gzip", body)
+	})
+	t.Run("deflate", func(t *testing.T) {
+		check(t, "deflate", encode(t, func(w gio.Writer) gio.WriteCloser { return zlib.NewWriter(w) }))
+		check(t, "deflate", encode(t, func(w gio.Writer) gio.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		}))
+	})
+	t.Run("too big", func(t *testing.T) {
+		body := encode(t, func(w gio.Writer) gio.WriteCloser { return gzip.NewWriter(w) })
+		r, err := decodeResponse(bytes.NewReader(body), "gzip")
+		require.NoError(t, err)
+		_, err = readResponse(r, len(data)-1)
+		require.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		body := encode(t, func(w gio.Writer) gio.WriteCloser { return gzip.NewWriter(w) })
+		r, err := decodeResponse(bytes.NewReader(body[:len(body)-4]), "gzip")
+		require.NoError(t, err)
+		_, err = readResponse(r, len(data))
+		require.Error(t, err)
+	})
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := decodeResponse(bytes.NewReader(data), "br")
+		require.Error(t, err)
+		_, err = decodeResponse(bytes.NewReader(data), "gzip, deflate")
+		require.Error(t, err)
+	})
+}
@@ -20,12 +20,15 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
 	"github.com/nspcc-dev/neo-go/pkg/network"
 	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
@@ -1237,6 +1240,82 @@ func TestCreateNEP17TransferTx(t *testing.T) {
 	})
 }
 
+func TestInvokeHistoricUnavailableState(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	h := chain.BlockHeight()
+	require.NoError(t, chain.PruneStates(h-2))
+	sh, err := c.GetStateHeight()
+	require.NoError(t, err)
+	require.Equal(t, &result.StateArchive{Start: h - 2, End: h}, sh.Archive)
+
+	neoHash, err := chain.GetNativeContractScriptHash(nativenames.Neo)
+	require.NoError(t, err)
+	_, err = c.InvokeFunctionAtHeight(1, neoHash, "symbol", []smartcontract.Parameter{}, nil)
+	require.ErrorIs(t, err, neorpc.ErrUnsupportedState)
+	nearest, ok := neorpc.NearestStateHeight(err)
+	require.True(t, ok)
+	require.Equal(t, h-2, nearest)
+
+	var stErr *invoker.HistoricStateError
+	_, err = invoker.NewHistoricWithState(chain.GetHeaderHash(2), c, nil).Call(neoHash, "symbol")
+	require.ErrorAs(t, err, &stErr)
+	require.Equal(t, h-2, stErr.Nearest)
+
+	res, err := invoker.NewHistoricAtHeight(stErr.Nearest, c, nil).Call(neoHash, "symbol")
+	require.NoError(t, err)
+	require.Equal(t, "HALT", res.State)
+}
+
+func TestInvokeDiagnostics(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	neoHash, err := chain.GetNativeContractScriptHash(nativenames.Neo)
+	require.NoError(t, err)
+
+	checkGasUsage := func(t *testing.T, res *result.Invoke) {
+		require.Equal(t, "HALT", res.State)
+		require.NotNil(t, res.Diagnostics)
+		require.NotNil(t, res.Diagnostics.GasUsage)
+		var gas int64
+		for _, v := range res.Diagnostics.GasUsage.Opcodes {
+			gas += v
+		}
+		for _, v := range res.Diagnostics.GasUsage.Syscalls {
+			gas += v
+		}
+		require.Equal(t, res.GasConsumed, gas)
+	}
+	t.Run("function", func(t *testing.T) {
+		res, err := c.InvokeFunctionDiagnostics(neoHash, "symbol", nil, nil)
+		require.NoError(t, err)
+		checkGasUsage(t, res)
+		require.Contains(t, res.Diagnostics.GasUsage.Syscalls, "System.Contract.CallNative")
+
+		res, err = c.InvokeFunction(neoHash, "symbol", []smartcontract.Parameter{}, nil)
+		require.NoError(t, err)
+		require.Nil(t, res.Diagnostics)
+	})
+	t.Run("script", func(t *testing.T) {
+		res, err := c.InvokeScriptDiagnostics([]byte{byte(opcode.PUSH1)}, nil)
+		require.NoError(t, err)
+		checkGasUsage(t, res)
+		require.Empty(t, res.Diagnostics.GasUsage.Syscalls)
+	})
+}
+
 func TestInvokeVerify(t *testing.T) {
 	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
 	defer chain.Close()
@@ -1329,6 +1408,62 @@ func TestInvokeVerify(t *testing.T) {
 	})
 }
 
+func TestClient_GetProof(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	h, err := util.Uint160DecodeStringLE(testContractHash)
+	require.NoError(t, err)
+	sr, err := c.GetStateRootByHeight(3)
+	require.NoError(t, err)
+	expected, err := chain.GetStateModule().GetStateRoot(3)
+	require.NoError(t, err)
+	require.Equal(t, expected.Root, sr.Root)
+
+	t.Run("positive", func(t *testing.T) {
+		proof, err := c.GetProof(sr.Root, h, []byte("testkey"))
+		require.NoError(t, err)
+		require.Equal(t, makeStorageKey(chain.GetContractState(h).ID, []byte("testkey")), proof.Key)
+
+		v, ok := proof.Verify(sr.Root)
+		require.True(t, ok)
+		require.Equal(t, []byte("testvalue"), v)
+
+		v, err = c.VerifyProof(sr.Root, proof)
+		require.NoError(t, err)
+		require.Equal(t, []byte("testvalue"), v)
+	})
+	t.Run("invalid proof", func(t *testing.T) {
+		proof, err := c.GetProof(sr.Root, h, []byte("testkey"))
+		require.NoError(t, err)
+		other, err := c.GetStateRootByHeight(2)
+		require.NoError(t, err)
+
+		_, ok := proof.Verify(other.Root)
+		require.False(t, ok)
+		v, err := c.VerifyProof(other.Root, proof)
+		require.NoError(t, err)
+		require.Nil(t, v)
+	})
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := c.GetProof(sr.Root, h, []byte("unknownkey"))
+		require.Error(t, err)
+	})
+	t.Run("unknown contract", func(t *testing.T) {
+		_, err := c.GetProof(sr.Root, util.Uint160{1, 2, 3}, []byte("testkey"))
+		require.Error(t, err)
+	})
+	t.Run("unknown state root", func(t *testing.T) {
+		_, err := c.GetStateRootByHeight(chain.BlockHeight() + 10)
+		require.Error(t, err)
+	})
+}
+
 func TestClient_GetNativeContracts(t *testing.T) {
 	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
 	defer chain.Close()
@@ -2001,3 +2136,89 @@ func TestClient_Wait(t *testing.T) {
 	// Wait for transaction that hasn't been persisted and VUB block has been persisted.
 	check(t, util.Uint256{1, 2, 3}, chain.BlockHeight()-1, true)
 }
+
+func TestClient_WaitForTransaction(t *testing.T) {
+	chain, rpcSrv, httpSrv := initServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	c, err := rpcclient.New(context.Background(), httpSrv.URL, rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+	wsc, err := rpcclient.NewWS(context.Background(), "ws"+strings.TrimPrefix(httpSrv.URL, "http")+"/ws", rpcclient.Options{})
+	require.NoError(t, err)
+	require.NoError(t, wsc.Init())
+	t.Cleanup(wsc.Close)
+
+	act, err := actor.New(c, []actor.SignerAccount{{
+		Signer: transaction.Signer{
+			Account: testchain.CommitteeScriptHash(),
+			Scopes:  transaction.CalledByEntry,
+		},
+		Account: &wallet.Account{
+			Address: testchain.CommitteeAddress(),
+			Contract: &wallet.Contract{
+				Script: testchain.CommitteeVerificationScript(),
+			},
+		},
+	}})
+	require.NoError(t, err)
+	newTx := func(t *testing.T, script []byte) *transaction.Transaction {
+		tx, err := act.MakeUnsignedUncheckedRun(script, 1_0000_0000, nil)
+		require.NoError(t, err)
+		tx.Scripts[0].InvocationScript = testchain.SignCommittee(tx)
+		return tx
+	}
+	submit := func(t *testing.T, tx *transaction.Transaction) {
+		_, err := c.SubmitBlock(*testchain.NewBlock(t, chain, 1, 0, tx))
+		require.NoError(t, err)
+	}
+
+	b, err := chain.GetBlock(chain.GetHeaderHash(1))
+	require.NoError(t, err)
+	require.True(t, len(b.Transactions) > 0)
+	persisted := b.Transactions[0].Hash()
+
+	for name, wait := range map[string]func(util.Uint256, time.Duration) (*state.AppExecResult, error){
+		"http": c.WaitForTransaction,
+		"ws":   wsc.WaitForTransaction,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Run("persisted", func(t *testing.T) {
+				res, err := wait(persisted, time.Second)
+				require.NoError(t, err)
+				require.Equal(t, persisted, res.Container)
+				require.Equal(t, vmstate.Halt, res.VMState)
+			})
+			t.Run("not confirmed", func(t *testing.T) {
+				_, err := wait(util.Uint256{1, 2, 3}, 100*time.Millisecond)
+				require.ErrorIs(t, err, rpcclient.ErrTxNotConfirmed)
+			})
+			t.Run("new", func(t *testing.T) {
+				if name == "http" {
+					t.Skip("polling interval is half of the block time which is too long for this test")
+				}
+				tx := newTx(t, []byte{byte(opcode.PUSH1)})
+				errCh := make(chan error, 1)
+				go func() {
+					res, err := wait(tx.Hash(), 5*time.Second)
+					if err == nil && res.Container != tx.Hash() {
+						err = fmt.Errorf("unexpected container %s", res.Container.StringLE())
+					}
+					errCh <- err
+				}()
+				time.Sleep(100 * time.Millisecond)
+				submit(t, tx)
+				require.NoError(t, <-errCh)
+			})
+			t.Run("faulted", func(t *testing.T) {
+				tx := newTx(t, []byte{byte(opcode.ABORT)})
+				submit(t, tx)
+				res, err := wait(tx.Hash(), time.Second)
+				require.ErrorIs(t, err, rpcclient.ErrTxFaulted)
+				require.NotNil(t, res)
+				require.Equal(t, vmstate.Fault, res.VMState)
+			})
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package params
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -87,6 +88,15 @@ func ExpandArrayIntoScript(script *io.BinWriter, slice []Param) error {
 			if err != nil {
 				return err
 			}
+		case smartcontract.MapType:
+			val, err := fp.Value.GetArray()
+			if err != nil {
+				return err
+			}
+			err = expandMapIntoScript(script, val)
+			if err != nil {
+				return err
+			}
 		case smartcontract.AnyType:
 			if fp.Value.IsNull() {
 				emit.Opcodes(script, opcode.PUSHNULL)
@@ -114,6 +124,28 @@ func ExpandArrayIntoScriptAndPack(script *io.BinWriter, slice []Param) error {
 	return script.Err
 }
 
+// expandMapIntoScript creates a map from the given array of key-value pairs
+// (each being a JSON object with "key" and "value" FuncParam fields).
+func expandMapIntoScript(script *io.BinWriter, pairs []Param) error {
+	emit.Opcodes(script, opcode.NEWMAP)
+	for i := range pairs {
+		var kv struct {
+			Key   Param `json:"key"`
+			Value Param `json:"value"`
+		}
+		if err := json.Unmarshal(pairs[i].RawMessage, &kv); err != nil {
+			return fmt.Errorf("map element #%d: %w", i, err)
+		}
+		emit.Opcodes(script, opcode.DUP)
+		// Key is pushed first, value is on top of the stack for SETITEM.
+		if err := ExpandArrayIntoScript(script, []Param{kv.Value, kv.Key}); err != nil {
+			return fmt.Errorf("map element #%d: %w", i, err)
+		}
+		emit.Opcodes(script, opcode.SETITEM)
+	}
+	return script.Err
+}
+
 // CreateFunctionInvocationScript creates a script to invoke the given contract with
 // the given parameters.
 func CreateFunctionInvocationScript(contract util.Uint160, method string, param *Param) ([]byte, error) {
@@ -124,6 +124,17 @@ func TestExpandArrayIntoScript(t *testing.T) {
 			Input:    []Param{{RawMessage: []byte(`{"type": "Integer", "value": "` + bi.String() + `"}`)}},
 			Expected: append([]byte{byte(opcode.PUSHINT256)}, rawInt...),
 		},
+		{
+			Input:    []Param{{RawMessage: []byte(`{"type": "Map", "value": []}`)}},
+			Expected: []byte{byte(opcode.NEWMAP)},
+		},
+		{
+			Input: []Param{{RawMessage: []byte(`{"type": "Map", "value": [{"key": {"type": "String", "value": "a"}, "value": {"type": "Array", "value": [{"type": "Integer", "value": 1}]}}]}`)}},
+			Expected: []byte{byte(opcode.NEWMAP), byte(opcode.DUP),
+				byte(opcode.PUSHDATA1), 1, byte('a'),
+				byte(opcode.PUSH1), byte(opcode.PUSH1), byte(opcode.PACK),
+				byte(opcode.SETITEM)},
+		},
 	}
 	for _, c := range testCases {
 		script := io.NewBufBinWriter()
@@ -138,6 +149,15 @@ func TestExpandArrayIntoScript(t *testing.T) {
 		{
 			{RawMessage: []byte(`{"type": "Array", "value": null}`)},
 		},
+		{
+			{RawMessage: []byte(`{"type": "Map", "value": "a"}`)},
+		},
+		{
+			{RawMessage: []byte(`{"type": "Map", "value": [1]}`)},
+		},
+		{
+			{RawMessage: []byte(`{"type": "Map", "value": [{"key": {"type": "String", "value": "a"}}]}`)},
+		},
 		{
 			{RawMessage: []byte(`{"type": "Integer", "value": "` +
 				new(big.Int).Lsh(big.NewInt(1), 255).String() + `"}`)},
@@ -25,7 +25,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/iterator"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
 	"github.com/nspcc-dev/neo-go/pkg/core/mempoolevent"
@@ -54,6 +56,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/vm/trace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 )
@@ -63,7 +66,7 @@ type (
 	Ledger interface {
 		AddBlock(block *block.Block) error
 		BlockHeight() uint32
-		CalculateClaimable(h util.Uint160, endHeight uint32) (*big.Int, error)
+		CalculateClaimableDetails(h util.Uint160, endHeight uint32) (*state.GASBonus, error)
 		CurrentBlockHash() util.Uint256
 		FeePerByte() int64
 		ForEachNEP11Transfer(acc util.Uint160, newestTimestamp uint64, f func(*state.NEP11Transfer) (bool, error)) error
@@ -88,10 +91,12 @@ type (
 		GetNextBlockValidators() ([]*keys.PublicKey, error)
 		GetNotaryContractScriptHash() util.Uint160
 		GetNotaryServiceFeePerKey() int64
+		GetStateArchive() (core.StateArchive, bool)
 		GetStateModule() core.StateRoot
 		GetStorageItem(id int32, key []byte) state.StorageItem
 		GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, nextBlockHeight uint32) (*interop.Context, error)
 		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error)
+		GetTestVMSnapshot(t trigger.Type, tx *transaction.Transaction) (*interop.Context, error)
 		GetTokenLastUpdated(acc util.Uint160) (map[int32]uint32, error)
 		GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
 		GetValidators() ([]*keys.PublicKey, error)
@@ -115,6 +120,11 @@ type (
 		AddResponse(pub *keys.PublicKey, reqID uint64, txSig []byte)
 	}
 
+	// NotaryHandler is the interface notary service needs to provide for the Server.
+	NotaryHandler interface {
+		State() result.NotaryState
+	}
+
 	// Server represents the JSON-RPC 2.0 server.
 	Server struct {
 		*http.Server
@@ -126,6 +136,7 @@ type (
 		stateRootEnabled bool
 		coreServer       *network.Server
 		oracle           *atomic.Value
+		notary           *atomic.Value
 		log              *zap.Logger
 		https            *http.Server
 		shutdown         chan struct{}
@@ -187,8 +198,16 @@ const (
 	// Maximum number of elements for get*transfers requests.
 	maxTransfersLimit = 1000
 
+	// healthHeightHeader is the HTTP header containing current chain height
+	// in replies to HEAD /health requests.
+	healthHeightHeader = "X-Neo-Block-Height"
+
 	// defaultSessionPoolSize is the number of concurrently running iterator sessions.
 	defaultSessionPoolSize = 20
+
+	// defaultTraceMaxSteps is the default number of VM instructions traced
+	// by invokescripttrace.
+	defaultTraceMaxSteps = 10000
 )
 
 var rpcHandlers = map[string]func(*Server, params.Params) (interface{}, *neorpc.Error){
@@ -212,6 +231,8 @@ var rpcHandlers = map[string]func(*Server, params.Params) (interface{}, *neorpc.
 	"getnep11transfers":            (*Server).getNEP11Transfers,
 	"getnep17balances":             (*Server).getNEP17Balances,
 	"getnep17transfers":            (*Server).getNEP17Transfers,
+	"getnotarypool":                (*Server).getNotaryPool,
+	"getnotaryservicestate":        (*Server).getNotaryServiceState,
 	"getpeers":                     (*Server).getPeers,
 	"getproof":                     (*Server).getProof,
 	"getrawmempool":                (*Server).getRawMempool,
@@ -228,6 +249,7 @@ var rpcHandlers = map[string]func(*Server, params.Params) (interface{}, *neorpc.
 	"invokefunctionhistoric":       (*Server).invokeFunctionHistoric,
 	"invokescript":                 (*Server).invokescript,
 	"invokescripthistoric":         (*Server).invokescripthistoric,
+	"invokescripttrace":            (*Server).invokescripttrace,
 	"invokecontractverify":         (*Server).invokeContractVerify,
 	"invokecontractverifyhistoric": (*Server).invokeContractVerifyHistoric,
 	"sendrawtransaction":           (*Server).sendrawtransaction,
@@ -282,6 +304,10 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 			log.Info("SessionPoolSize is not set or wrong, setting default value", zap.Int("SessionPoolSize", defaultSessionPoolSize))
 		}
 	}
+	if conf.TraceEnabled && conf.TraceMaxSteps <= 0 {
+		conf.TraceMaxSteps = defaultTraceMaxSteps
+		log.Info("TraceMaxSteps is not set or wrong, setting default value", zap.Int("TraceMaxSteps", defaultTraceMaxSteps))
+	}
 	var oracleWrapped = new(atomic.Value)
 	if orc != nil {
 		oracleWrapped.Store(&orc)
@@ -296,6 +322,7 @@ func New(chain Ledger, conf config.RPC, coreServer *network.Server,
 		coreServer:       coreServer,
 		log:              log,
 		oracle:           oracleWrapped,
+		notary:           new(atomic.Value),
 		https:            tlsServer,
 		shutdown:         make(chan struct{}),
 		started:          atomic.NewBool(false),
@@ -416,6 +443,11 @@ func (s *Server) SetOracleHandler(orc OracleHandler) {
 	s.oracle.Store(&orc)
 }
 
+// SetNotaryHandler allows to update notary handler used by the Server.
+func (s *Server) SetNotaryHandler(ntr NotaryHandler) {
+	s.notary.Store(&ntr)
+}
+
 func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Request) {
 	req := params.NewRequest()
 
@@ -451,6 +483,12 @@ func (s *Server) handleHTTPRequest(w http.ResponseWriter, httpRequest *http.Requ
 		return
 	}
 
+	if httpRequest.URL.Path == "/health" && httpRequest.Method == "HEAD" {
+		w.Header().Set(healthHeightHeader, strconv.FormatUint(uint64(s.chain.BlockHeight()), 10))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	if httpRequest.Method == "OPTIONS" && s.config.EnableCORSWorkaround { // Preflight CORS.
 		setCORSOriginHeaders(w.Header())
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST") // GET for websockets.
@@ -717,11 +755,47 @@ func (s *Server) getVersion(_ params.Params) (interface{}, *neorpc.Error) {
 	}, nil
 }
 
-func (s *Server) getPeers(_ params.Params) (interface{}, *neorpc.Error) {
+func (s *Server) getPeers(reqParams params.Params) (interface{}, *neorpc.Error) {
+	verbose, _ := reqParams.Value(0).GetBoolean()
 	peers := result.NewGetPeers()
 	peers.AddUnconnected(s.coreServer.UnconnectedPeers())
 	peers.AddConnected(s.coreServer.ConnectedPeers())
 	peers.AddBad(s.coreServer.BadPeers())
+	if st, ok := s.coreServer.NATStatus(); ok {
+		peers.NAT = &result.NATStatus{
+			Method: st.Method,
+			Mapped: st.Mapped,
+		}
+		if st.Mapped {
+			peers.NAT.ExternalAddress = net.JoinHostPort(st.ExternalIP.String(), strconv.FormatUint(uint64(st.ExternalPort), 10))
+		}
+	}
+	if verbose {
+		scores := s.coreServer.PeerScores()
+		traffic := s.coreServer.PeerTraffic()
+		for i := range peers.Connected {
+			p := &peers.Connected[i]
+			if tr, ok := traffic[p.Address+":"+p.Port]; ok {
+				p.Traffic = &result.PeerTraffic{
+					BytesSent:     tr.BytesSent,
+					BytesReceived: tr.BytesReceived,
+					SendQueue:     tr.SendQueueLen,
+				}
+			}
+			sc, ok := scores[p.Address+":"+p.Port]
+			if !ok {
+				continue
+			}
+			p.Score = &result.PeerScore{
+				Score:             sc.Score,
+				InvalidPayloads:   sc.InvalidPayloads,
+				MalformedMessages: sc.MalformedMessages,
+				Timeouts:          sc.Timeouts,
+				UsefulBlocks:      sc.UsefulBlocks,
+				Latency:           sc.Latency.Milliseconds(),
+			}
+		}
+	}
 	return peers, nil
 }
 
@@ -1569,10 +1643,17 @@ func (s *Server) getStateHeight(_ params.Params) (interface{}, *neorpc.Error) {
 	if s.chain.GetConfig().StateRootInHeader {
 		stateHeight = height - 1
 	}
-	return &result.StateHeight{
+	var res = &result.StateHeight{
 		Local:     height,
 		Validated: stateHeight,
-	}, nil
+	}
+	if archive, ok := s.chain.GetStateArchive(); ok {
+		res.Archive = &result.StateArchive{
+			Start: archive.Start,
+			End:   archive.End,
+		}
+	}
+	return res, nil
 }
 
 func (s *Server) getStateRoot(ps params.Params) (interface{}, *neorpc.Error) {
@@ -1758,14 +1839,29 @@ func (s *Server) getUnclaimedGas(ps params.Params) (interface{}, *neorpc.Error)
 			Address: u,
 		}, nil
 	}
-	gas, err := s.chain.CalculateClaimable(u, s.chain.BlockHeight()+1) // +1 as in C#, for the next block.
+	bonus, err := s.chain.CalculateClaimableDetails(u, s.chain.BlockHeight()+1) // +1 as in C#, for the next block.
 	if err != nil {
 		return nil, neorpc.NewRPCError("Can't calculate claimable", err.Error())
 	}
-	return result.UnclaimedGas{
+	res := result.UnclaimedGas{
 		Address:   u,
-		Unclaimed: *gas,
-	}, nil
+		Unclaimed: bonus.Total,
+	}
+	if verbose, _ := ps.Value(1).GetBoolean(); verbose {
+		res.VoteTo = bonus.VoteTo
+		if bonus.VoteTo != nil {
+			res.VoterReward = &bonus.VoterReward
+		}
+		for _, p := range bonus.Periods {
+			res.Periods = append(res.Periods, result.UnclaimedGasPeriod{
+				Start:       p.Start,
+				End:         p.End,
+				GASPerBlock: p.GASPerBlock,
+				Amount:      p.Amount,
+			})
+		}
+	}
+	return res, nil
 }
 
 // getCandidates returns the current list of candidates with their active/inactive voting status.
@@ -1831,7 +1927,7 @@ func (s *Server) invokeFunction(reqParams params.Params) (interface{}, *neorpc.E
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, false)
 }
 
 // invokeFunctionHistoric implements the `invokeFunctionHistoric` RPC call.
@@ -1847,7 +1943,7 @@ func (s *Server) invokeFunctionHistoric(reqParams params.Params) (interface{}, *
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose, false)
 }
 
 func (s *Server) getInvokeFunctionParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
@@ -1898,7 +1994,7 @@ func (s *Server) invokescript(reqParams params.Params) (interface{}, *neorpc.Err
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, false)
 }
 
 // invokescripthistoric implements the `invokescripthistoric` RPC call.
@@ -1914,7 +2010,20 @@ func (s *Server) invokescripthistoric(reqParams params.Params) (interface{}, *ne
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose)
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, &nextH, verbose, false)
+}
+
+// invokescripttrace implements the `invokescripttrace` RPC call. It's the same
+// as invokescript, but also returns (a limited) VM execution trace.
+func (s *Server) invokescripttrace(reqParams params.Params) (interface{}, *neorpc.Error) {
+	if !s.config.TraceEnabled {
+		return nil, neorpc.NewInvalidRequestError("execution tracing is disabled")
+	}
+	tx, verbose, respErr := s.getInvokeScriptParams(reqParams)
+	if respErr != nil {
+		return nil, respErr
+	}
+	return s.runScriptInVM(trigger.Application, tx.Script, util.Uint160{}, tx, nil, verbose, true)
 }
 
 func (s *Server) getInvokeScriptParams(reqParams params.Params) (*transaction.Transaction, bool, *neorpc.Error) {
@@ -1952,7 +2061,7 @@ func (s *Server) invokeContractVerify(reqParams params.Params) (interface{}, *ne
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, nil, false, false)
 }
 
 // invokeContractVerifyHistoric implements the `invokecontractverifyhistoric` RPC call.
@@ -1968,7 +2077,7 @@ func (s *Server) invokeContractVerifyHistoric(reqParams params.Params) (interfac
 	if respErr != nil {
 		return nil, respErr
 	}
-	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH, false)
+	return s.runScriptInVM(trigger.Verification, invocationScript, scriptHash, tx, &nextH, false, false)
 }
 
 func (s *Server) getInvokeContractVerifyParams(reqParams params.Params) (util.Uint160, *transaction.Transaction, []byte, *neorpc.Error) {
@@ -2047,18 +2156,32 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 		ic  *interop.Context
 	)
 	if nextH == nil {
-		ic, err = s.chain.GetTestVM(t, tx, nil)
+		if s.config.SessionEnabled && !s.config.SessionBackedByMPT {
+			// Iterators may outlive the invocation, so they need a
+			// state that is not affected by new blocks.
+			ic, err = s.chain.GetTestVMSnapshot(t, tx)
+			if errors.Is(err, storage.ErrSnapshotsNotSupported) {
+				ic, err = s.chain.GetTestVM(t, tx, nil)
+			}
+		} else {
+			ic, err = s.chain.GetTestVM(t, tx, nil)
+		}
 		if err != nil {
 			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create test VM: %s", err))
 		}
 	} else {
 		ic, err = s.chain.GetTestHistoricVM(t, tx, *nextH)
 		if err != nil {
+			var stErr *core.StateUnavailableError
+			if errors.As(err, &stErr) {
+				return nil, neorpc.NewUnsupportedStateError(stErr.Height, stErr.Nearest)
+			}
 			return nil, neorpc.NewInternalServerError(fmt.Sprintf("failed to create historic VM: %s", err))
 		}
 	}
 	if verbose {
 		ic.VM.EnableInvocationTree()
+		ic.VM.EnableGasStats()
 	}
 	ic.VM.GasLimit = int64(s.config.MaxGasInvoke)
 	if t == trigger.Verification {
@@ -2083,18 +2206,35 @@ func (s *Server) prepareInvocationContext(t trigger.Type, script []byte, contrac
 // result. The script is either a simple script in case of `application` trigger,
 // witness invocation script in case of `verification` trigger (it pushes `verify`
 // arguments on stack before verification). In case of contract verification
-// contractScriptHash should be specified.
-func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose bool) (*result.Invoke, *neorpc.Error) {
+// contractScriptHash should be specified. If traced is set, execution trace is
+// collected and returned along with the result.
+func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash util.Uint160, tx *transaction.Transaction, nextH *uint32, verbose, traced bool) (*result.Invoke, *neorpc.Error) {
 	ic, respErr := s.prepareInvocationContext(t, script, contractScriptHash, tx, nextH, verbose)
 	if respErr != nil {
 		return nil, respErr
 	}
+	var tracer *trace.Collector
+	if traced {
+		tracer = trace.NewCollector(s.config.TraceMaxSteps)
+		ic.VM.SetTracer(tracer)
+	}
 	err := ic.VM.Run()
 	var faultException string
 	if err != nil {
 		faultException = err.Error()
 	}
 	items := ic.VM.Estack().ToArray()
+	// Storage changes must be collected before the context is finalized,
+	// its storage snapshot (if any) is released then.
+	var diag *result.InvokeDiag
+	tree := ic.VM.GetInvocationTree()
+	if tree != nil {
+		diag = &result.InvokeDiag{
+			Invocations: tree.Calls,
+			Changes:     storage.BatchToOperations(ic.DAO.GetBatch()),
+			GasUsage:    newGasUsage(ic.VM.GetGasStats()),
+		}
+	}
 	sess := s.postProcessExecStack(items)
 	var id uuid.UUID
 
@@ -2104,7 +2244,7 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 		if s.config.SessionBackedByMPT && nextH == nil {
 			ic.Finalize()
 			// Rerun with MPT-backed storage.
-			return s.runScriptInVM(t, script, contractScriptHash, tx, &ic.Block.Index, verbose)
+			return s.runScriptInVM(t, script, contractScriptHash, tx, &ic.Block.Index, verbose, traced)
 		}
 		id = uuid.New()
 		sessionID := id.String()
@@ -2135,14 +2275,6 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 	} else {
 		ic.Finalize()
 	}
-	var diag *result.InvokeDiag
-	tree := ic.VM.GetInvocationTree()
-	if tree != nil {
-		diag = &result.InvokeDiag{
-			Invocations: tree.Calls,
-			Changes:     storage.BatchToOperations(ic.DAO.GetBatch()),
-		}
-	}
 	notifications := ic.Notifications
 	if notifications == nil {
 		notifications = make([]state.NotificationEvent, 0)
@@ -2157,10 +2289,38 @@ func (s *Server) runScriptInVM(t trigger.Type, script []byte, contractScriptHash
 		Diagnostics:    diag,
 		Session:        id,
 	}
+	if tracer != nil {
+		res.Trace = tracer.Trace()
+	}
 
 	return res, nil
 }
 
+// newGasUsage converts VM GAS consumption breakdown into the RPC result
+// grouping opcodes by their price class.
+func newGasUsage(stats *vm.GasStats) *result.GasUsage {
+	if stats == nil {
+		return nil
+	}
+	res := &result.GasUsage{
+		Opcodes:  make(map[int64]int64),
+		Syscalls: make(map[string]int64, len(stats.Syscalls)),
+	}
+	for op, gas := range stats.Opcodes {
+		if gas != 0 {
+			res.Opcodes[fee.Opcode(1, opcode.Opcode(op))] += gas
+		}
+	}
+	for id, gas := range stats.Syscalls {
+		name, err := interopnames.FromID(id)
+		if err != nil {
+			name = fmt.Sprintf("0x%08x", id)
+		}
+		res.Syscalls[name] += gas
+	}
+	return res
+}
+
 // postProcessExecStack changes iterator interop items according to the server configuration.
 // It does modifications in-place, but it returns a session if any iterator was registered.
 func (s *Server) postProcessExecStack(stack []stackitem.Item) *session {
@@ -2312,6 +2472,46 @@ func (s *Server) submitBlock(reqParams params.Params) (interface{}, *neorpc.Erro
 	}, nil
 }
 
+// getNotaryPool returns P2PNotaryRequests pooled by the node grouped by main
+// transactions.
+func (s *Server) getNotaryPool(reqParams params.Params) (interface{}, *neorpc.Error) {
+	if !s.chain.P2PSigExtensionsEnabled() {
+		return nil, neorpc.NewRPCError("P2PSignatureExtensions are disabled", "")
+	}
+	verbose, _ := reqParams.Value(0).GetBoolean()
+
+	var (
+		res   = make([]*result.NotaryPoolRequest, 0)
+		index = make(map[util.Uint256]*result.NotaryPoolRequest)
+	)
+	s.coreServer.GetNotaryPool().IterateVerifiedTransactions(func(fb *transaction.Transaction, data interface{}) bool {
+		r, ok := data.(*payload.P2PNotaryRequest)
+		if !ok {
+			return true
+		}
+		h := r.MainTransaction.Hash()
+		entry, ok := index[h]
+		if !ok {
+			entry = &result.NotaryPoolRequest{Hash: h}
+			if attrs := r.MainTransaction.GetAttributes(transaction.NotaryAssistedT); len(attrs) != 0 {
+				entry.NKeys = attrs[0].Value.(*transaction.NotaryAssisted).NKeys
+			}
+			if verbose {
+				entry.MainTransaction = r.MainTransaction
+			}
+			index[h] = entry
+			res = append(res, entry)
+		}
+		entry.Signatures++
+		entry.Fallbacks = append(entry.Fallbacks, fb.Hash())
+		if verbose {
+			entry.FallbackTransactions = append(entry.FallbackTransactions, fb)
+		}
+		return true
+	})
+	return res, nil
+}
+
 // submitNotaryRequest broadcasts P2PNotaryRequest over the NEO network.
 func (s *Server) submitNotaryRequest(ps params.Params) (interface{}, *neorpc.Error) {
 	if !s.chain.P2PSigExtensionsEnabled() {
@@ -2347,6 +2547,19 @@ func getRelayResult(err error, hash util.Uint256) (interface{}, *neorpc.Error) {
 	}
 }
 
+// getNotaryServiceState returns the state of the Notary service, it's only
+// available if enabled in the configuration.
+func (s *Server) getNotaryServiceState(_ params.Params) (interface{}, *neorpc.Error) {
+	if !s.config.NotaryStateEnabled {
+		return nil, neorpc.NewInvalidRequestError("notary service state is disabled")
+	}
+	ntr, ok := s.notary.Load().(*NotaryHandler)
+	if !ok || *ntr == nil {
+		return nil, neorpc.NewRPCError("Notary service is not enabled", "")
+	}
+	return (*ntr).State(), nil
+}
+
 func (s *Server) submitOracleResponse(ps params.Params) (interface{}, *neorpc.Error) {
 	oracle := s.oracle.Load().(*OracleHandler)
 	if oracle == nil || *oracle == nil {
@@ -2430,7 +2643,7 @@ func (s *Server) subscribe(reqParams params.Params, sub *subscriber) (interface{
 		case neorpc.ExecutionEventID:
 			flt := new(neorpc.ExecutionFilter)
 			err = jd.Decode(flt)
-			if err == nil && (flt.State != nil && (*flt.State == "HALT" || *flt.State == "FAULT")) {
+			if err == nil && (flt.State == nil || *flt.State == "HALT" || *flt.State == "FAULT") {
 				filter = *flt
 			} else if err == nil {
 				err = errors.New("invalid state")
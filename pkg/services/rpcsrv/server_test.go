@@ -25,6 +25,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dboper"
@@ -499,6 +500,7 @@ var rpcTestCases = map[string][]rpcTestCase{
 
 				require.Equal(t, e.chain.BlockHeight(), sh.Local)
 				require.Equal(t, uint32(0), sh.Validated)
+				require.Equal(t, &result.StateArchive{Start: 0, End: e.chain.BlockHeight()}, sh.Archive)
 			},
 		},
 	},
@@ -747,6 +749,17 @@ var rpcTestCases = map[string][]rpcTestCase{
 				}
 			},
 		},
+		{
+			name:   "verbose",
+			params: "[true]",
+			result: func(*executor) interface{} {
+				return &result.GetPeers{
+					Unconnected: []result.Peer{},
+					Connected:   []result.Peer{},
+					Bad:         []result.Peer{},
+				}
+			},
+		},
 	},
 	"getrawtransaction": {
 		{
@@ -822,6 +835,33 @@ var rpcTestCases = map[string][]rpcTestCase{
 				assert.Equal(t, expected, *actual)
 			},
 		},
+		{
+			name:   "verbose",
+			params: `["` + testchain.MultisigAddress() + `", true]`,
+			result: func(*executor) interface{} {
+				return &result.UnclaimedGas{}
+			},
+			check: func(t *testing.T, e *executor, resp interface{}) {
+				actual, ok := resp.(*result.UnclaimedGas)
+				require.True(t, ok)
+				require.Equal(t, testchain.MultisigScriptHash(), actual.Address)
+				require.Equal(t, big.NewInt(11000), &actual.Unclaimed)
+				require.NotEqual(t, 0, len(actual.Periods))
+				var sum = new(big.Int)
+				for i, p := range actual.Periods {
+					require.True(t, p.Start < p.End)
+					if i > 0 {
+						require.Equal(t, actual.Periods[i-1].End, p.Start)
+					}
+					sum.Add(sum, &p.Amount)
+				}
+				require.Equal(t, e.chain.BlockHeight()+1, actual.Periods[len(actual.Periods)-1].End)
+				if actual.VoteTo != nil {
+					sum.Add(sum, actual.VoterReward)
+				}
+				require.True(t, sum.Cmp(&actual.Unclaimed) <= 0)
+			},
+		},
 	},
 	"getcandidates": {
 		{
@@ -951,6 +991,15 @@ var rpcTestCases = map[string][]rpcTestCase{
 				}}
 				// Can be returned in any order.
 				assert.ElementsMatch(t, chg, res.Diagnostics.Changes)
+				// GAS consumption breakdown covers all consumed GAS.
+				var gas int64
+				for _, v := range res.Diagnostics.GasUsage.Opcodes {
+					gas += v
+				}
+				for _, v := range res.Diagnostics.GasUsage.Syscalls {
+					gas += v
+				}
+				assert.Equal(t, res.GasConsumed, gas)
 			},
 		},
 		{
@@ -968,7 +1017,8 @@ var rpcTestCases = map[string][]rpcTestCase{
 					Stack:         []stackitem.Item{stackitem.Make("1.2.3.4")},
 					Notifications: []state.NotificationEvent{},
 					Diagnostics: &result.InvokeDiag{
-						Changes: []dboper.Operation{},
+						Changes:  []dboper.Operation{},
+						GasUsage: nnsResolveGasUsage,
 						Invocations: []*invocations.Tree{{
 							Current: hash.Hash160(script),
 							Calls: []*invocations.Tree{
@@ -1083,7 +1133,8 @@ var rpcTestCases = map[string][]rpcTestCase{
 					Stack:         []stackitem.Item{stackitem.Make("1.2.3.4")},
 					Notifications: []state.NotificationEvent{},
 					Diagnostics: &result.InvokeDiag{
-						Changes: []dboper.Operation{},
+						Changes:  []dboper.Operation{},
+						GasUsage: nnsResolveGasUsage,
 						Invocations: []*invocations.Tree{{
 							Current: hash.Hash160(script),
 							Calls: []*invocations.Tree{
@@ -1175,7 +1226,8 @@ var rpcTestCases = map[string][]rpcTestCase{
 					FaultException: "at instruction 0 (ROT): too big index",
 					Notifications:  []state.NotificationEvent{},
 					Diagnostics: &result.InvokeDiag{
-						Changes: []dboper.Operation{},
+						Changes:  []dboper.Operation{},
+						GasUsage: &result.GasUsage{Opcodes: map[int64]int64{2: 60}, Syscalls: map[string]int64{}},
 						Invocations: []*invocations.Tree{{
 							Current: hash.Hash160(script),
 						}},
@@ -1286,7 +1338,8 @@ var rpcTestCases = map[string][]rpcTestCase{
 					FaultException: "at instruction 0 (ROT): too big index",
 					Notifications:  []state.NotificationEvent{},
 					Diagnostics: &result.InvokeDiag{
-						Changes: []dboper.Operation{},
+						Changes:  []dboper.Operation{},
+						GasUsage: &result.GasUsage{Opcodes: map[int64]int64{2: 60}, Syscalls: map[string]int64{}},
 						Invocations: []*invocations.Tree{{
 							Current: hash.Hash160(script),
 						}},
@@ -1691,6 +1744,32 @@ var rpcTestCases = map[string][]rpcTestCase{
 	},
 }
 
+// nnsResolveGasUsage is GAS consumption breakdown of NNS "resolve" method
+// invocation used in verbose invokefunction tests.
+var nnsResolveGasUsage = &result.GasUsage{
+	Opcodes: map[int64]int64{
+		1:     1680,
+		2:     19200,
+		4:     3840,
+		8:     6720,
+		64:    67200,
+		512:   276480,
+		2048:  307200,
+		8192:  2949120,
+		32768: 4915200,
+	},
+	Syscalls: map[string]int64{
+		"System.Contract.Call":              983040,
+		"System.Contract.CallNative":        3448320,
+		"System.Iterator.Next":              983040,
+		"System.Iterator.Value":             480,
+		"System.Runtime.GetTime":            240,
+		"System.Storage.Find":               983040,
+		"System.Storage.Get":                983040,
+		"System.Storage.GetReadOnlyContext": 480,
+	},
+}
+
 func TestRPC(t *testing.T) {
 	t.Run("http", func(t *testing.T) {
 		testRPCProtocol(t, doRPCCallOverHTTP)
@@ -3250,6 +3329,107 @@ func TestEscapeForLog(t *testing.T) {
 	require.Equal(t, "bad", escapeForLog(in))
 }
 
+func TestChainTip(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	check := func(t *testing.T) {
+		body := doRPCCallOverHTTP(`{"jsonrpc": "2.0", "id": 1, "method": "getblockcount", "params": []}`, httpSrv.URL, t)
+		var count uint32
+		require.NoError(t, json.Unmarshal(checkErrGetResult(t, body, false), &count))
+		require.Equal(t, chain.BlockHeight()+1, count)
+
+		body = doRPCCallOverHTTP(`{"jsonrpc": "2.0", "id": 1, "method": "getbestblockhash", "params": []}`, httpSrv.URL, t)
+		var hash string
+		require.NoError(t, json.Unmarshal(checkErrGetResult(t, body, false), &hash))
+		require.Equal(t, "0x"+chain.CurrentBlockHash().StringLE(), hash)
+
+		req, err := http.NewRequest(http.MethodHead, httpSrv.URL+"/health", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, strconv.FormatUint(uint64(chain.BlockHeight()), 10), resp.Header.Get(healthHeightHeader))
+	}
+	check(t)
+	for _, b := range getTestBlocks(t)[:3] {
+		require.NoError(t, chain.AddBlock(b))
+		check(t)
+	}
+
+	t.Run("GET /health", func(t *testing.T) {
+		resp, err := http.Get(httpSrv.URL + "/health")
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.NotEqual(t, http.StatusOK, resp.StatusCode) // Only HEAD is supported.
+	})
+}
+
+type fakeNotary result.NotaryState
+
+func (f fakeNotary) State() result.NotaryState {
+	return result.NotaryState(f)
+}
+
+func TestGetNotaryServiceState(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	req := `{"jsonrpc": "2.0", "id": 1, "method": "getnotaryservicestate", "params": []}`
+	t.Run("disabled in config", func(t *testing.T) {
+		body := doRPCCallOverHTTP(req, httpSrv.URL, t)
+		checkErrGetResult(t, body, true, "notary service state is disabled")
+	})
+	rpcSrv.config.NotaryStateEnabled = true
+	t.Run("notary disabled", func(t *testing.T) {
+		body := doRPCCallOverHTTP(req, httpSrv.URL, t)
+		checkErrGetResult(t, body, true, "Notary service is not enabled")
+	})
+
+	expected := result.NotaryState{
+		Started:       true,
+		Account:       "NfgHwwTi3wHAS8aFAN243C5vGbkYDpqLHP",
+		Completed:     1,
+		FallbacksSent: 2,
+		Expired:       3,
+		Requests: []result.NotaryRequestState{{
+			Main:              util.Uint256{1, 2, 3},
+			Fallbacks:         2,
+			MinNotValidBefore: 42,
+		}},
+	}
+	rpcSrv.SetNotaryHandler(fakeNotary(expected))
+	body := doRPCCallOverHTTP(req, httpSrv.URL, t)
+	var actual result.NotaryState
+	require.NoError(t, json.Unmarshal(checkErrGetResult(t, body, false), &actual))
+	require.Equal(t, expected, actual)
+}
+
+func BenchmarkChainTip(b *testing.B) {
+	chain, orc, cfg, logger := getUnitTestChain(b, false, false, false)
+	defer chain.Close()
+	rpcServer := New(chain, cfg.ApplicationConfiguration.RPC, nil, orc, logger, make(chan error))
+
+	for _, m := range []string{"getblockcount", "getbestblockhash"} {
+		b.Run(m, func(b *testing.B) {
+			req := []byte(`{"jsonrpc":"2.0", "method":"` + m + `","params":[]}`)
+			in := new(params.In)
+			require.NoError(b, json.Unmarshal(req, in))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				res := rpcServer.handleIn(in, nil)
+				if res.Error != nil {
+					b.FailNow()
+				}
+			}
+		})
+	}
+}
+
 func BenchmarkHandleIn(b *testing.B) {
 	chain, orc, cfg, logger := getUnitTestChain(b, false, false, false)
 
@@ -3292,3 +3472,65 @@ func BenchmarkHandleIn(b *testing.B) {
 {"type": "Integer", "value": "42"}, {"type": "Boolean", "value": false}]]}`))
 	})
 }
+
+func TestInvokeScriptTrace(t *testing.T) {
+	chain, rpcSrv, httpSrv := initClearServerWithInMemoryChain(t)
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1)
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGetTrigger)
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	script := w.Bytes()
+	req := fmt.Sprintf(`{"jsonrpc": "2.0", "id": 1, "method": "invokescripttrace", "params": ["%s"]}`, base64.StdEncoding.EncodeToString(script))
+
+	t.Run("disabled", func(t *testing.T) {
+		body := doRPCCallOverHTTP(req, httpSrv.URL, t)
+		checkErrGetResult(t, body, true, "execution tracing is disabled")
+	})
+
+	rpcSrv.config.TraceEnabled = true
+	rpcSrv.config.TraceMaxSteps = 3
+	getTrace := func(t *testing.T) *result.Invoke {
+		body := doRPCCallOverHTTP(req, httpSrv.URL, t)
+		raw := checkErrGetResult(t, body, false)
+		res := new(result.Invoke)
+		require.NoError(t, json.Unmarshal(raw, res))
+		require.Equal(t, "HALT", res.State)
+		require.NotNil(t, res.Trace)
+		return res
+	}
+	t.Run("full", func(t *testing.T) {
+		res := getTrace(t)
+		require.False(t, res.Trace.Truncated)
+		require.Equal(t, 3, len(res.Trace.Steps))
+		expected := []struct {
+			ip      int
+			op      opcode.Opcode
+			depth   int
+			syscall string
+		}{
+			{0, opcode.PUSH1, 0, ""},
+			{1, opcode.SYSCALL, 1, interopnames.SystemRuntimeGetTrigger},
+			{6, opcode.RET, 2, ""},
+		}
+		for i, e := range expected {
+			s := res.Trace.Steps[i]
+			require.Equal(t, hash.Hash160(script), s.ScriptHash)
+			require.Equal(t, e.ip, s.IP)
+			require.Equal(t, e.op, s.Opcode)
+			require.Equal(t, e.depth, s.StackDepth)
+			require.Equal(t, e.syscall, s.Syscall)
+			require.Equal(t, "", s.Error)
+		}
+		require.Equal(t, int64(rpcSrv.config.MaxGasInvoke), res.Trace.Steps[0].GasLeft)
+		require.True(t, res.Trace.Steps[1].GasLeft < res.Trace.Steps[0].GasLeft)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		rpcSrv.config.TraceMaxSteps = 1
+		res := getTrace(t)
+		require.True(t, res.Trace.Truncated)
+		require.Equal(t, 1, len(res.Trace.Steps))
+	})
+}
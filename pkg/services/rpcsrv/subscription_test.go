@@ -12,6 +12,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/neorpc"
+	"github.com/nspcc-dev/neo-go/pkg/neorpc/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
@@ -255,6 +257,12 @@ func TestFilteredSubscriptions(t *testing.T) {
 				t.Fatal("unexpected match for faulted execution")
 			},
 		},
+		"execution non-matching container": {
+			params: `["transaction_executed", {"container":"0x0000000000000000000000000000000000000000000000000000000000030201"}]`,
+			check: func(t *testing.T, _ *neorpc.Notification) {
+				t.Fatal("unexpected match for unknown container")
+			},
+		},
 	}
 
 	for name, this := range cases {
@@ -389,6 +397,65 @@ func TestFilteredNotaryRequestSubscriptions(t *testing.T) {
 	c.Close()
 }
 
+func TestNotaryPool(t *testing.T) {
+	priv0 := testchain.PrivateKeyByID(0)
+	chain, rpcSrv, c, respMsgs, finishedFlag := initCleanServerAndWSClient(t)
+	go rpcSrv.coreServer.Start(make(chan error, 1))
+
+	defer chain.Close()
+	defer rpcSrv.Shutdown()
+
+	// blocks are needed to make GAS deposit for priv0
+	for _, b := range getTestBlocks(t) {
+		require.NoError(t, chain.AddBlock(b))
+	}
+
+	getPool := func(t *testing.T, verbose bool) []result.NotaryPoolRequest {
+		resp := callWSGetRaw(t, c, fmt.Sprintf(`{"jsonrpc": "2.0","method": "getnotarypool","params": [%t],"id": 1}`, verbose), respMsgs)
+		require.Nil(t, resp.Error)
+		var res []result.NotaryPoolRequest
+		require.NoError(t, json.Unmarshal(resp.Result, &res))
+		return res
+	}
+	require.Equal(t, 0, len(getPool(t, false)))
+
+	subID := callSubscribe(t, c, respMsgs, `["notary_request_event", {"signer":"`+priv0.GetScriptHash().StringLE()+`"}]`)
+	req := createValidNotaryRequest(chain, priv0, 1)
+	require.NoError(t, rpcSrv.coreServer.RelayP2PNotaryRequest(req))
+
+	var resp = new(neorpc.Notification)
+	select {
+	case body := <-respMsgs:
+		require.NoError(t, json.Unmarshal(body, resp))
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+	require.Equal(t, neorpc.NotaryRequestEventID, resp.Event)
+	rmap := resp.Payload[0].(map[string]interface{})
+	require.Equal(t, "added", rmap["type"].(string))
+	callUnsubscribe(t, c, respMsgs, subID)
+
+	mainHash := req.MainTransaction.Hash()
+	fbHash := req.FallbackTransaction.Hash()
+	require.Equal(t, []result.NotaryPoolRequest{{
+		Hash:       mainHash,
+		NKeys:      1,
+		Signatures: 1,
+		Fallbacks:  []util.Uint256{fbHash},
+	}}, getPool(t, false))
+
+	res := getPool(t, true)
+	require.Equal(t, 1, len(res))
+	require.Equal(t, mainHash, res[0].Hash)
+	require.Equal(t, 1, res[0].Signatures)
+	require.Equal(t, mainHash, res[0].MainTransaction.Hash())
+	require.Equal(t, 1, len(res[0].FallbackTransactions))
+	require.Equal(t, fbHash, res[0].FallbackTransactions[0].Hash())
+
+	finishedFlag.CAS(false, true)
+	c.Close()
+}
+
 func TestFilteredBlockSubscriptions(t *testing.T) {
 	// We can't fit this into TestFilteredSubscriptions, because it uses
 	// blocks as EOF events to wait for.
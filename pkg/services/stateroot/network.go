@@ -103,7 +103,11 @@ func (s *service) sendValidatedRoot(r *state.MPTRoot, acc *wallet.Account) {
 			VerificationScript: acc.GetVerificationScript(),
 		},
 	}
-	sig := acc.SignHashable(s.Network, ep)
+	sig, err := acc.SignHashableContext(s.signCtx, s.Network, ep)
+	if err != nil {
+		s.log.Error("can't sign validated state root", zap.Error(err))
+		return
+	}
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
 	ep.Witness.InvocationScript = buf.Bytes()
@@ -1,6 +1,7 @@
 package stateroot
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/stateroot"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -21,10 +23,14 @@ import (
 type (
 	// Ledger is an interface to Blockchain sufficient for Service.
 	Ledger interface {
+		BlockHeight() uint32
 		GetConfig() config.ProtocolConfiguration
+		GetDesignatedByRole(r noderoles.Role, index uint32) (keys.PublicKeys, uint32, error)
 		HeaderHeight() uint32
 		SubscribeForBlocks(ch chan *block.Block)
+		SubscribeForRoleChanges(ch chan *state.RoleChangeEvent)
 		UnsubscribeFromBlocks(ch chan *block.Block)
+		UnsubscribeFromRoleChanges(ch chan *state.RoleChangeEvent)
 	}
 
 	// Service represents a state root service.
@@ -64,8 +70,13 @@ type (
 		maxRetries      int
 		relayExtensible RelayCallback
 		blockCh         chan *block.Block
+		roleCh          chan *state.RoleChangeEvent
 		stopCh          chan struct{}
 		done            chan struct{}
+		// signCtx is used for remote signer requests, it's cancelled on
+		// shutdown.
+		signCtx    context.Context
+		signCancel context.CancelFunc
 	}
 )
 
@@ -85,12 +96,14 @@ func New(cfg config.StateRoot, sm *stateroot.Module, log *zap.Logger, bc Ledger,
 		log:             log,
 		incompleteRoots: make(map[uint32]*incompleteRoot),
 		blockCh:         make(chan *block.Block),
+		roleCh:          make(chan *state.RoleChangeEvent),
 		stopCh:          make(chan struct{}),
 		done:            make(chan struct{}),
 		timePerBlock:    time.Duration(bcConf.SecondsPerBlock) * time.Second,
 		maxRetries:      voteValidEndInc,
 		relayExtensible: cb,
 	}
+	s.signCtx, s.signCancel = context.WithCancel(context.Background())
 
 	s.MainCfg = cfg
 	if cfg.Enabled {
@@ -102,6 +115,9 @@ func New(cfg config.StateRoot, sm *stateroot.Module, log *zap.Logger, bc Ledger,
 		if s.wallet, err = wallet.NewWalletFromFile(w.Path); err != nil {
 			return nil, err
 		}
+		if err := wallet.CheckPermissions(w.Path); err != nil {
+			s.log.Warn("insecure wallet permissions", zap.Error(err))
+		}
 
 		haveAccount := false
 		for _, acc := range s.wallet.Accounts {
@@ -113,8 +129,6 @@ func New(cfg config.StateRoot, sm *stateroot.Module, log *zap.Logger, bc Ledger,
 		if !haveAccount {
 			return nil, errors.New("no wallet account could be unlocked")
 		}
-
-		s.SetUpdateValidatorsCallback(s.updateValidators)
 	}
 	return s, nil
 }
@@ -136,6 +150,7 @@ func (s *service) OnPayload(ep *payload.Extensible) error {
 		err := s.AddStateRoot(sr)
 		if errors.Is(err, stateroot.ErrStateMismatch) {
 			s.log.Error("can't add SV-signed state root", zap.Error(err))
+			s.handleMismatch(sr)
 			return nil
 		}
 		s.srMtx.Lock()
@@ -154,7 +169,32 @@ func (s *service) OnPayload(ep *payload.Extensible) error {
 	return nil
 }
 
+// handleMismatch halts block processing if it's configured to do so on local
+// state root mismatching the given validated one.
+func (s *service) handleMismatch(sr *state.MPTRoot) {
+	if !s.MainCfg.HaltOnMismatch || s.Halted() != nil {
+		return
+	}
+	local, err := s.GetStateRoot(sr.Index)
+	if err != nil {
+		s.log.Error("can't get local state root", zap.Uint32("height", sr.Index), zap.Error(err))
+		return
+	}
+	s.Halt(&stateroot.Mismatch{
+		Height:    sr.Index,
+		Local:     local.Root,
+		Validated: sr.Root,
+	})
+	s.log.Error("state root mismatch, block processing is halted",
+		zap.Uint32("height", sr.Index),
+		zap.Stringer("local", local.Root),
+		zap.Stringer("validated", sr.Root))
+}
+
 func (s *service) updateValidators(height uint32, pubs keys.PublicKeys) {
+	if s.wallet == nil {
+		return
+	}
 	s.accMtx.Lock()
 	defer s.accMtx.Unlock()
 
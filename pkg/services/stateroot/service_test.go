@@ -2,6 +2,7 @@ package stateroot_test
 
 import (
 	"crypto/elliptic"
+	"encoding/binary"
 	"errors"
 	"path/filepath"
 	"sort"
@@ -149,6 +150,66 @@ func TestStateRoot(t *testing.T) {
 	require.Equal(t, h, r.Witness[0].ScriptHash())
 }
 
+func TestStateRootMismatch(t *testing.T) {
+	bc, validator, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validator, committee)
+	designationSuperInvoker := e.NewInvoker(e.NativeHash(t, nativenames.Designation), validator, committee)
+	gasValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+
+	h, pubs, accs := newMajorityMultisigWithGAS(t, 2)
+	validatorNodes := []interface{}{pubs[0].Bytes(), pubs[1].Bytes()}
+	designationSuperInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(roles.StateValidator), validatorNodes)
+	gasValidatorInvoker.Invoke(t, true, "transfer", validator.ScriptHash(), h, 1_0000_0000, nil)
+
+	tmpDir := t.TempDir()
+	w := createAndWriteWallet(t, accs[0], filepath.Join(tmpDir, "w"), "pass")
+	cfg := createStateRootConfig(w.Path(), "pass")
+	srMod := bc.GetStateModule().(*corestate.Module) // Take full responsibility here.
+
+	// Corrupt local state root stored in the DAO.
+	height := bc.BlockHeight()
+	validated, err := srMod.GetStateRoot(height)
+	require.NoError(t, err)
+	local := *validated
+	local.Root = hash.Sha256([]byte("corrupted"))
+	key := make([]byte, 5)
+	key[0] = byte(storage.DataMPTAux)
+	binary.BigEndian.PutUint32(key[1:], height)
+	corrupted, err := testserdes.EncodeBinary(&local)
+	require.NoError(t, err)
+	srMod.Store.Put(key, corrupted)
+
+	data := testSignStateRoot(t, validated, pubs, accs...)
+
+	t.Run("disabled", func(t *testing.T) {
+		srv, err := stateroot.New(cfg, srMod, zaptest.NewLogger(t), bc, nil)
+		require.NoError(t, err)
+		require.NoError(t, srv.OnPayload(&payload.Extensible{Data: data}))
+		require.Nil(t, srMod.Halted())
+		require.EqualValues(t, 0, srMod.CurrentValidatedHeight())
+	})
+
+	cfg.HaltOnMismatch = true
+	srv, err := stateroot.New(cfg, srMod, zaptest.NewLogger(t), bc, nil)
+	require.NoError(t, err)
+	require.NoError(t, srv.OnPayload(&payload.Extensible{Data: data}))
+	expected := &corestate.Mismatch{
+		Height:    height,
+		Local:     local.Root,
+		Validated: validated.Root,
+	}
+	require.Equal(t, expected, srMod.Halted())
+	require.EqualValues(t, 0, srMod.CurrentValidatedHeight())
+
+	srMod.Halt(&corestate.Mismatch{Height: height + 1})
+	require.Equal(t, expected, srMod.Halted()) // Only the first mismatch is kept.
+
+	b := e.SignBlock(e.NewUnsignedBlock(t))
+	require.ErrorIs(t, bc.AddBlock(b), corestate.ErrStateMismatch)
+	require.Equal(t, height, bc.BlockHeight())
+}
+
 type memoryStore struct {
 	*storage.MemoryStore
 }
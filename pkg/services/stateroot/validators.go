@@ -1,8 +1,10 @@
 package stateroot
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/io"
@@ -29,7 +31,16 @@ func (s *service) Start() {
 		return
 	}
 	s.log.Info("starting state validation service")
+	s.chain.SubscribeForRoleChanges(s.roleCh)
 	s.chain.SubscribeForBlocks(s.blockCh)
+	// Validators for the next block, subsequent changes are delivered
+	// via role change events.
+	pubs, h, err := s.chain.GetDesignatedByRole(noderoles.StateValidator, s.chain.BlockHeight()+1)
+	if err != nil {
+		s.log.Error("failed to get state validators", zap.Error(err))
+	} else {
+		s.updateValidators(h, pubs)
+	}
 	go s.run()
 }
 
@@ -37,6 +48,10 @@ func (s *service) run() {
 runloop:
 	for {
 		select {
+		case ev := <-s.roleCh:
+			if ev.Role == noderoles.StateValidator {
+				s.updateValidators(ev.Height, ev.Nodes)
+			}
 		case b := <-s.blockCh:
 			r, err := s.GetStateRoot(b.Index)
 			if err != nil {
@@ -51,16 +66,25 @@ runloop:
 			break runloop
 		}
 	}
-	s.chain.UnsubscribeFromBlocks(s.blockCh)
+	// Blockchain can be blocked sending to any of the channels, so they're
+	// drained until both unsubscriptions are completed.
+	unsubbed := make(chan struct{})
+	go func() {
+		s.chain.UnsubscribeFromBlocks(s.blockCh)
+		s.chain.UnsubscribeFromRoleChanges(s.roleCh)
+		close(unsubbed)
+	}()
 drainloop:
 	for {
 		select {
 		case <-s.blockCh:
-		default:
+		case <-s.roleCh:
+		case <-unsubbed:
 			break drainloop
 		}
 	}
 	close(s.blockCh)
+	close(s.roleCh)
 	close(s.done)
 }
 
@@ -72,6 +96,7 @@ func (s *service) Shutdown() {
 		return
 	}
 	s.log.Info("stopping state validation service")
+	s.signCancel()
 	close(s.stopCh)
 	<-s.done
 	if s.wallet != nil {
@@ -89,7 +114,10 @@ func (s *service) signAndSend(r *state.MPTRoot) error {
 		return nil
 	}
 
-	sig := acc.SignHashable(s.Network, r)
+	sig, err := acc.SignHashableContext(s.signCtx, s.Network, r)
+	if err != nil {
+		return fmt.Errorf("can't sign state root: %w", err)
+	}
 	incRoot := s.getIncompleteRoot(r.Index, myIndex)
 	incRoot.Lock()
 	defer incRoot.Unlock()
@@ -119,7 +147,10 @@ func (s *service) signAndSend(r *state.MPTRoot) error {
 			VerificationScript: acc.GetVerificationScript(),
 		},
 	}
-	sig = acc.SignHashable(s.Network, e)
+	sig, err = acc.SignHashableContext(s.signCtx, s.Network, e)
+	if err != nil {
+		return fmt.Errorf("can't sign vote: %w", err)
+	}
 	buf := io.NewBufBinWriter()
 	emit.Bytes(buf.BinWriter, sig)
 	e.Witness.InvocationScript = buf.Bytes()
@@ -0,0 +1,460 @@
+/*
+Package analysis implements static checks of the compiled contract bytecode.
+
+It doesn't execute anything, instead it decodes the script contained in the
+NEF file and looks for things that are likely to be mistakes (like unreachable
+instructions) or that will surely fail at runtime (like stack underflows or
+syscalls requiring call flags not available to safe methods). Every problem
+found is reported as a Finding with the script offset it relates to.
+*/
+package analysis
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/limits"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Severity is a level of the problem found.
+type Severity byte
+
+// Severity levels, ordered from the least to the most important.
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+// Check names used in findings.
+const (
+	CheckUnreachable = "unreachable"
+	CheckStack       = "stack"
+	CheckCallFlags   = "callflags"
+	CheckCallT       = "callt"
+	CheckPush        = "push"
+	CheckSyscall     = "syscall"
+)
+
+// String implements the fmt.Stringer interface.
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(s))
+	}
+}
+
+// ParseSeverity parses severity from its string representation.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(s) {
+	case "info":
+		return Info, nil
+	case "warning":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	default:
+		return 0, fmt.Errorf("unknown severity: %s", s)
+	}
+}
+
+// Finding is a single problem found in the script.
+type Finding struct {
+	// Offset is an offset of the instruction the problem relates to.
+	Offset int
+	// Severity is the level of the problem.
+	Severity Severity
+	// Check is the name of the check that produced this finding.
+	Check string
+	// Message describes the problem.
+	Message string
+}
+
+// String implements the fmt.Stringer interface.
+func (f Finding) String() string {
+	return fmt.Sprintf("%d: %s: %s: %s", f.Offset, f.Severity, f.Check, f.Message)
+}
+
+// SyscallInfo is a syscall description needed for analysis.
+type SyscallInfo struct {
+	Name          string
+	RequiredFlags callflag.CallFlag
+	ParamCount    int
+}
+
+// Options contains additional data used for analysis, all of it is optional.
+type Options struct {
+	// Manifest is used to get method entry points, their parameters and
+	// safety flags. If it's nil, the script is analyzed starting from
+	// offset 0 and method-specific checks are not performed.
+	Manifest *manifest.Manifest
+	// Syscalls is used to resolve syscall IDs. If it's nil, syscall-related
+	// checks are not performed.
+	Syscalls func(id uint32) (SyscallInfo, bool)
+}
+
+type instr struct {
+	offset int
+	op     opcode.Opcode
+	param  []byte
+	next   int
+}
+
+type analyzer struct {
+	nef     *nef.File
+	opts    Options
+	instrs  []instr
+	index   map[int]int
+	results []Finding
+}
+
+// Analyze performs all checks on the given NEF file and returns findings
+// sorted by offset. An error is returned if the script can't be decoded.
+func Analyze(n *nef.File, opts Options) ([]Finding, error) {
+	if err := vm.IsScriptCorrect(n.Script, nil); err != nil {
+		return nil, fmt.Errorf("invalid script: %w", err)
+	}
+	a := &analyzer{
+		nef:   n,
+		opts:  opts,
+		index: make(map[int]int),
+	}
+	ctx := vm.NewContext(n.Script)
+	for ctx.NextIP() < len(n.Script) {
+		op, param, err := ctx.Next()
+		if err != nil {
+			return nil, err
+		}
+		a.index[ctx.IP()] = len(a.instrs)
+		a.instrs = append(a.instrs, instr{
+			offset: ctx.IP(),
+			op:     op,
+			param:  param,
+			next:   ctx.NextIP(),
+		})
+	}
+	var entries []int
+	if opts.Manifest != nil {
+		for _, m := range opts.Manifest.ABI.Methods {
+			if _, ok := a.index[m.Offset]; !ok {
+				return nil, fmt.Errorf("method %s points to a wrong offset %d", m.Name, m.Offset)
+			}
+			entries = append(entries, m.Offset)
+		}
+	}
+	if len(entries) == 0 && len(a.instrs) != 0 {
+		entries = append(entries, 0)
+	}
+
+	a.checkUnreachable(entries)
+	a.checkInstructions()
+	if opts.Manifest != nil {
+		for i := range opts.Manifest.ABI.Methods {
+			m := &opts.Manifest.ABI.Methods[i]
+			a.checkStack(m)
+			if m.Safe {
+				a.checkSafeMethod(m)
+			}
+		}
+	}
+	sort.SliceStable(a.results, func(i, j int) bool {
+		return a.results[i].Offset < a.results[j].Offset
+	})
+	return a.results, nil
+}
+
+func (a *analyzer) report(offset int, sev Severity, check string, format string, args ...interface{}) {
+	a.results = append(a.results, Finding{
+		Offset:   offset,
+		Severity: sev,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// jumpTarget returns an absolute offset for the given jump parameter of the
+// instruction at the given offset.
+func jumpTarget(offset int, param []byte) int {
+	if len(param) == 1 {
+		return offset + int(int8(param[0]))
+	}
+	return offset + int(int32(binary.LittleEndian.Uint32(param)))
+}
+
+// successors returns a list of instructions that can be executed after the
+// given one in the same context.
+func (a *analyzer) successors(in instr) []int {
+	var res []int
+	switch in.op {
+	case opcode.RET, opcode.THROW, opcode.ABORT, opcode.ENDFINALLY:
+	case opcode.JMP, opcode.JMPL, opcode.ENDTRY, opcode.ENDTRYL:
+		res = append(res, jumpTarget(in.offset, in.param))
+	case opcode.JMPIF, opcode.JMPIFL, opcode.JMPIFNOT, opcode.JMPIFNOTL,
+		opcode.JMPEQ, opcode.JMPEQL, opcode.JMPNE, opcode.JMPNEL,
+		opcode.JMPGT, opcode.JMPGTL, opcode.JMPGE, opcode.JMPGEL,
+		opcode.JMPLT, opcode.JMPLTL, opcode.JMPLE, opcode.JMPLEL,
+		opcode.CALL, opcode.CALLL, opcode.PUSHA:
+		res = append(res, jumpTarget(in.offset, in.param), in.next)
+	case opcode.TRY, opcode.TRYL:
+		l := len(in.param) / 2
+		catchP, finallyP := in.param[:l], in.param[l:]
+		if t := jumpTarget(in.offset, catchP); t != in.offset {
+			res = append(res, t)
+		}
+		if t := jumpTarget(in.offset, finallyP); t != in.offset {
+			res = append(res, t)
+		}
+		res = append(res, in.next)
+	default:
+		res = append(res, in.next)
+	}
+	return res
+}
+
+// reachable returns a set of instruction indexes reachable from the given
+// entry points.
+func (a *analyzer) reachable(entries []int) []bool {
+	var (
+		seen  = make([]bool, len(a.instrs))
+		queue = make([]int, 0, len(entries))
+	)
+	queue = append(queue, entries...)
+	for len(queue) != 0 {
+		off := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		i, ok := a.index[off]
+		if !ok || seen[i] {
+			continue
+		}
+		seen[i] = true
+		queue = append(queue, a.successors(a.instrs[i])...)
+	}
+	return seen
+}
+
+func (a *analyzer) checkUnreachable(entries []int) {
+	seen := a.reachable(entries)
+	for i := 0; i < len(a.instrs); i++ {
+		if seen[i] {
+			continue
+		}
+		start := i
+		for i+1 < len(a.instrs) && !seen[i+1] {
+			i++
+		}
+		a.report(a.instrs[start].offset, Warning, CheckUnreachable,
+			"unreachable code (%d instructions)", i-start+1)
+	}
+}
+
+// checkInstructions performs checks that don't depend on control flow.
+func (a *analyzer) checkInstructions() {
+	for _, in := range a.instrs {
+		switch in.op {
+		case opcode.PUSHDATA1, opcode.PUSHDATA2, opcode.PUSHDATA4:
+			// Items bigger than stackitem.MaxSize can't be decoded at all,
+			// but anything that can't be stored is suspicious too.
+			if len(in.param) > limits.MaxStorageValueLen {
+				a.report(in.offset, Warning, CheckPush, "pushed item is too big to be stored (%d bytes, max %d)",
+					len(in.param), limits.MaxStorageValueLen)
+			}
+		case opcode.CALLT:
+			id := int(binary.LittleEndian.Uint16(in.param))
+			if id >= len(a.nef.Tokens) {
+				a.report(in.offset, Error, CheckCallT, "missing method token #%d (%d tokens defined)",
+					id, len(a.nef.Tokens))
+			}
+		case opcode.SYSCALL:
+			if a.opts.Syscalls == nil {
+				continue
+			}
+			id := binary.LittleEndian.Uint32(in.param)
+			if _, ok := a.opts.Syscalls(id); !ok {
+				a.report(in.offset, Error, CheckSyscall, "unknown syscall 0x%08x", id)
+			}
+		}
+	}
+}
+
+// checkSafeMethod checks that the code reachable from safe method doesn't
+// need call flags besides ReadOnly.
+func (a *analyzer) checkSafeMethod(m *manifest.Method) {
+	seen := a.reachable([]int{m.Offset})
+	for i, in := range a.instrs {
+		if !seen[i] {
+			continue
+		}
+		switch in.op {
+		case opcode.SYSCALL:
+			if a.opts.Syscalls == nil {
+				continue
+			}
+			info, ok := a.opts.Syscalls(binary.LittleEndian.Uint32(in.param))
+			if ok && !callflag.ReadOnly.Has(info.RequiredFlags) {
+				a.report(in.offset, Error, CheckCallFlags, "safe method %s uses %s requiring %s flags",
+					m.Name, info.Name, info.RequiredFlags)
+			}
+		case opcode.CALLT:
+			id := int(binary.LittleEndian.Uint16(in.param))
+			if id < len(a.nef.Tokens) && !callflag.ReadOnly.Has(a.nef.Tokens[id].CallFlag) {
+				t := a.nef.Tokens[id]
+				a.report(in.offset, Error, CheckCallFlags, "safe method %s calls %s.%s with %s flags",
+					m.Name, t.Hash.StringLE(), t.Method, t.CallFlag)
+			}
+		}
+	}
+}
+
+// errStop is returned from stackEffect for instructions with effects that
+// can't be determined statically.
+var errStop = errors.New("unknown stack effect")
+
+// checkStack follows the linear path starting at the method entry point and
+// checks that stack never underflows and has exactly the number of items
+// expected at RET. If the path contains an instruction with unknown stack
+// effect (like CALL or SYSCALL), the check stops there and this is reported
+// as an Info finding, so that "no findings" always means the whole path is
+// checked.
+func (a *analyzer) checkStack(m *manifest.Method) {
+	depth := len(m.Parameters)
+	for i := a.index[m.Offset]; i < len(a.instrs); i++ {
+		in := a.instrs[i]
+		if in.op == opcode.RET {
+			expected := 1
+			if m.ReturnType == smartcontract.VoidType {
+				expected = 0
+			}
+			if depth != expected {
+				a.report(in.offset, Error, CheckStack, "method %s returns with %d stack items instead of %d",
+					m.Name, depth, expected)
+			}
+			return
+		}
+		pop, push, err := a.stackEffect(in, depth)
+		if depth < pop {
+			if in.op == opcode.CALLT {
+				id := binary.LittleEndian.Uint16(in.param)
+				a.report(in.offset, Error, CheckCallT, "method token #%d expects %d parameters, but only %d stack items are available",
+					id, pop, depth)
+			} else {
+				a.report(in.offset, Error, CheckStack, "stack underflow in method %s: %s needs %d items, %d available",
+					m.Name, in.op, pop, depth)
+			}
+			return
+		}
+		if err != nil {
+			a.report(in.offset, Info, CheckStack, "stack check of method %s stopped at %s: %v",
+				m.Name, in.op, err)
+			return
+		}
+		depth += push - pop
+		switch in.op {
+		case opcode.JMP, opcode.JMPL, opcode.THROW, opcode.ABORT:
+			return
+		}
+	}
+}
+
+// stackEffect returns the number of items the instruction takes from the
+// stack and the number of items it puts there. Instructions with dynamic
+// effects (that depend on the stack contents) are approximated with their
+// minimal requirements, errStop is returned when it's not possible (the
+// number of items taken is still the minimal requirement in this case).
+func (a *analyzer) stackEffect(in instr, depth int) (int, int, error) {
+	op := in.op
+	switch {
+	case op <= opcode.PUSH16 && op != opcode.PUSHA:
+		return 0, 1, nil
+	case op >= opcode.LDSFLD0 && op <= opcode.LDSFLD, op >= opcode.LDLOC0 && op <= opcode.LDLOC,
+		op >= opcode.LDARG0 && op <= opcode.LDARG:
+		return 0, 1, nil
+	case op >= opcode.STSFLD0 && op <= opcode.STSFLD, op >= opcode.STLOC0 && op <= opcode.STLOC,
+		op >= opcode.STARG0 && op <= opcode.STARG:
+		return 1, 0, nil
+	}
+	switch op {
+	case opcode.PUSHA, opcode.DEPTH, opcode.NEWARRAY0, opcode.NEWSTRUCT0, opcode.NEWMAP:
+		return 0, 1, nil
+	case opcode.NOP, opcode.JMP, opcode.JMPL, opcode.INITSSLOT:
+		return 0, 0, nil
+	case opcode.JMPIF, opcode.JMPIFL, opcode.JMPIFNOT, opcode.JMPIFNOTL, opcode.ASSERT, opcode.THROW,
+		opcode.DROP, opcode.ROLL, opcode.REVERSEN, opcode.REVERSEITEMS, opcode.CLEARITEMS:
+		return 1, 0, nil
+	case opcode.JMPEQ, opcode.JMPEQL, opcode.JMPNE, opcode.JMPNEL, opcode.JMPGT, opcode.JMPGTL,
+		opcode.JMPGE, opcode.JMPGEL, opcode.JMPLT, opcode.JMPLTL, opcode.JMPLE, opcode.JMPLEL,
+		opcode.APPEND, opcode.REMOVE, opcode.XDROP:
+		return 2, 0, nil
+	case opcode.SETITEM:
+		return 3, 0, nil
+	case opcode.MEMCPY:
+		return 5, 0, nil
+	case opcode.CLEAR:
+		return depth, 0, nil
+	case opcode.INITSLOT:
+		return int(in.param[1]), 0, nil
+	case opcode.ABORT:
+		return 0, 0, nil
+	case opcode.DUP:
+		return 1, 2, nil
+	case opcode.OVER, opcode.TUCK:
+		return 2, 3, nil
+	case opcode.SWAP:
+		return 2, 2, nil
+	case opcode.ROT, opcode.REVERSE3:
+		return 3, 3, nil
+	case opcode.REVERSE4:
+		return 4, 4, nil
+	case opcode.PICK, opcode.NEWBUFFER, opcode.INVERT, opcode.SIGN, opcode.ABS, opcode.NEGATE,
+		opcode.INC, opcode.DEC, opcode.SQRT, opcode.NOT, opcode.NZ, opcode.NEWARRAY, opcode.NEWARRAYT,
+		opcode.NEWSTRUCT, opcode.SIZE, opcode.KEYS, opcode.VALUES, opcode.POPITEM, opcode.ISNULL,
+		opcode.ISTYPE, opcode.CONVERT:
+		return 1, 1, nil
+	case opcode.NIP, opcode.CAT, opcode.LEFT, opcode.RIGHT, opcode.AND, opcode.OR, opcode.XOR,
+		opcode.EQUAL, opcode.NOTEQUAL, opcode.ADD, opcode.SUB, opcode.MUL, opcode.DIV, opcode.MOD,
+		opcode.POW, opcode.SHL, opcode.SHR, opcode.BOOLAND, opcode.BOOLOR, opcode.NUMEQUAL,
+		opcode.NUMNOTEQUAL, opcode.LT, opcode.LE, opcode.GT, opcode.GE, opcode.MIN, opcode.MAX,
+		opcode.HASKEY, opcode.PICKITEM:
+		return 2, 1, nil
+	case opcode.SUBSTR, opcode.MODMUL, opcode.MODPOW, opcode.WITHIN:
+		return 3, 1, nil
+	case opcode.CALLT:
+		id := int(binary.LittleEndian.Uint16(in.param))
+		if id >= len(a.nef.Tokens) {
+			return 0, 0, errStop
+		}
+		t := a.nef.Tokens[id]
+		var push int
+		if t.HasReturn {
+			push = 1
+		}
+		return int(t.ParamCount), push, nil
+	case opcode.SYSCALL:
+		// Syscalls can return nothing or a single item, there is no way
+		// to know it from the table.
+		if a.opts.Syscalls == nil {
+			return 0, 0, errStop
+		}
+		info, ok := a.opts.Syscalls(binary.LittleEndian.Uint32(in.param))
+		if !ok {
+			return 0, 0, errStop
+		}
+		return info.ParamCount, 0, errStop
+	default:
+		return 0, 0, errStop
+	}
+}
@@ -0,0 +1,322 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/limits"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func testSyscalls(id uint32) (SyscallInfo, bool) {
+	switch id {
+	case interopnames.ToID([]byte(interopnames.SystemStoragePut)):
+		return SyscallInfo{Name: interopnames.SystemStoragePut, RequiredFlags: callflag.WriteStates, ParamCount: 3}, true
+	case interopnames.ToID([]byte(interopnames.SystemStorageGetContext)):
+		return SyscallInfo{Name: interopnames.SystemStorageGetContext, RequiredFlags: callflag.ReadStates}, true
+	}
+	return SyscallInfo{}, false
+}
+
+func newManifest(methods ...manifest.Method) *manifest.Manifest {
+	m := manifest.NewManifest("Test")
+	m.ABI.Methods = methods
+	return m
+}
+
+func analyze(t *testing.T, script []byte, tokens []nef.MethodToken, opts Options) []Finding {
+	n := &nef.File{Script: script, Tokens: tokens}
+	res, err := Analyze(n, opts)
+	require.NoError(t, err)
+	return res
+}
+
+// filterChecks returns only findings of the given check.
+func filterChecks(res []Finding, check string) []Finding {
+	var filtered []Finding
+	for _, f := range res {
+		if f.Check == check {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+func TestAnalyzeInvalidScript(t *testing.T) {
+	_, err := Analyze(&nef.File{Script: []byte{byte(opcode.JMP), 0x7f}}, Options{})
+	require.Error(t, err)
+
+	_, err = Analyze(&nef.File{Script: []byte{byte(opcode.RET)}}, Options{
+		Manifest: newManifest(manifest.Method{Name: "main", Offset: 1, ReturnType: smartcontract.VoidType}),
+	})
+	require.Error(t, err)
+}
+
+func TestUnreachable(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.RET, opcode.PUSH1, opcode.DROP, opcode.RET)
+	res := analyze(t, w.Bytes(), nil, Options{})
+	require.Equal(t, 1, len(res))
+	require.Equal(t, 1, res[0].Offset)
+	require.Equal(t, CheckUnreachable, res[0].Check)
+	require.Equal(t, Warning, res[0].Severity)
+
+	t.Run("after jump", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.JMP, []byte{3})
+		emit.Opcodes(w.BinWriter, opcode.NOP, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, 2, res[0].Offset)
+	})
+	t.Run("jump target is reachable", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.JMP, []byte{2})
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{})
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("all methods are entry points", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.RET, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "a", Offset: 0, ReturnType: smartcontract.VoidType},
+			manifest.Method{Name: "b", Offset: 1, ReturnType: smartcontract.VoidType},
+		)})
+		require.Equal(t, 0, len(res))
+	})
+}
+
+func TestStackImbalance(t *testing.T) {
+	t.Run("underflow", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.ADD, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckStack, res[0].Check)
+		require.Equal(t, 1, res[0].Offset)
+		require.Equal(t, Error, res[0].Severity)
+	})
+	t.Run("parameters", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.ADD, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{
+				Name:       "main",
+				Parameters: []manifest.Parameter{manifest.NewParameter("a", smartcontract.IntegerType)},
+				ReturnType: smartcontract.IntegerType,
+			},
+		)})
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("extra items at RET", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckStack, res[0].Check)
+		require.Equal(t, 2, res[0].Offset)
+	})
+	t.Run("void", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.INITSLOT, []byte{0, 1})
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{
+				Name:       "main",
+				Parameters: []manifest.Parameter{manifest.NewParameter("a", smartcontract.IntegerType)},
+				ReturnType: smartcontract.VoidType,
+			},
+		)})
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("XDROP", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.PUSH0, opcode.XDROP, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 0, len(res))
+
+		w = io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH0, opcode.XDROP, opcode.RET)
+		res = analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckStack, res[0].Check)
+		require.Equal(t, 3, res[0].Offset) // No items left for RET.
+	})
+	t.Run("unknown effect stops analysis", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH0, opcode.PACK, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.VoidType},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckStack, res[0].Check)
+		require.Equal(t, Info, res[0].Severity)
+		require.Equal(t, 1, res[0].Offset)
+	})
+	t.Run("CALL", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.CALL, []byte{3})
+		emit.Opcodes(w.BinWriter, opcode.RET, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, Info, res[0].Severity)
+		require.Equal(t, 0, res[0].Offset)
+	})
+	t.Run("SYSCALL", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2)
+		emit.Syscall(w.BinWriter, interopnames.SystemStoragePut)
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		script := w.Bytes()
+
+		res := analyze(t, script, nil, Options{
+			Manifest: newManifest(manifest.Method{Name: "main", ReturnType: smartcontract.VoidType}),
+			Syscalls: testSyscalls,
+		})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, Error, res[0].Severity)
+		require.Equal(t, 2, res[0].Offset) // Put needs 3 items.
+
+		res = analyze(t, script, nil, Options{
+			Manifest: newManifest(manifest.Method{
+				Name:       "main",
+				Parameters: []manifest.Parameter{manifest.NewParameter("a", smartcontract.IntegerType)},
+				ReturnType: smartcontract.VoidType,
+			}),
+			Syscalls: testSyscalls,
+		})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, Info, res[0].Severity)
+		require.Equal(t, 2, res[0].Offset)
+	})
+}
+
+func TestSafeMethodFlags(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Syscall(w.BinWriter, interopnames.SystemStorageGetContext)
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	emit.Syscall(w.BinWriter, interopnames.SystemStoragePut)
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	script := w.Bytes()
+
+	t.Run("read only", func(t *testing.T) {
+		res := analyze(t, script, nil, Options{
+			Manifest: newManifest(
+				manifest.Method{Name: "get", Offset: 0, ReturnType: smartcontract.InteropInterfaceType, Safe: true},
+				manifest.Method{Name: "put", Offset: 6, ReturnType: smartcontract.VoidType},
+			),
+			Syscalls: testSyscalls,
+		})
+		require.Equal(t, 0, len(filterChecks(res, CheckCallFlags)))
+	})
+	t.Run("write in safe method", func(t *testing.T) {
+		res := analyze(t, script, nil, Options{
+			Manifest: newManifest(
+				manifest.Method{Name: "get", Offset: 0, ReturnType: smartcontract.InteropInterfaceType},
+				manifest.Method{Name: "put", Offset: 6, ReturnType: smartcontract.VoidType, Safe: true},
+			),
+			Syscalls: testSyscalls,
+		})
+		res = filterChecks(res, CheckCallFlags)
+		require.Equal(t, 1, len(res))
+		require.Equal(t, 6, res[0].Offset)
+	})
+	t.Run("unknown syscall", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.SYSCALL, []byte{1, 2, 3, 4})
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		res := analyze(t, w.Bytes(), nil, Options{Syscalls: testSyscalls})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckSyscall, res[0].Check)
+	})
+	t.Run("CALLT in safe method", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.CALLT, []byte{0, 0})
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		res := analyze(t, w.Bytes(), []nef.MethodToken{{
+			Hash:     util.Uint160{1, 2, 3},
+			Method:   "update",
+			CallFlag: callflag.All,
+		}}, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.VoidType, Safe: true},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckCallFlags, res[0].Check)
+	})
+}
+
+func TestCallT(t *testing.T) {
+	t.Run("missing token", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Instruction(w.BinWriter, opcode.CALLT, []byte{1, 0})
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		res := analyze(t, w.Bytes(), []nef.MethodToken{{Method: "m", CallFlag: callflag.All}}, Options{})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckCallT, res[0].Check)
+		require.Equal(t, Error, res[0].Severity)
+	})
+	t.Run("parameter count", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.PUSH1)
+		emit.Instruction(w.BinWriter, opcode.CALLT, []byte{0, 0})
+		emit.Opcodes(w.BinWriter, opcode.RET)
+		script := w.Bytes()
+		tokens := []nef.MethodToken{{Method: "m", ParamCount: 2, HasReturn: true, CallFlag: callflag.All}}
+		res := analyze(t, script, tokens, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 1, len(res))
+		require.Equal(t, CheckCallT, res[0].Check)
+		require.Equal(t, 1, res[0].Offset)
+
+		tokens[0].ParamCount = 1
+		res = analyze(t, script, tokens, Options{Manifest: newManifest(
+			manifest.Method{Name: "main", ReturnType: smartcontract.IntegerType},
+		)})
+		require.Equal(t, 0, len(res))
+	})
+}
+
+func TestOversizedPush(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, make([]byte, limits.MaxStorageValueLen+1))
+	emit.Opcodes(w.BinWriter, opcode.DROP, opcode.RET)
+	res := analyze(t, w.Bytes(), nil, Options{})
+	require.Equal(t, 1, len(res))
+	require.Equal(t, CheckPush, res[0].Check)
+	require.Equal(t, 0, res[0].Offset)
+	require.Equal(t, Warning, res[0].Severity)
+
+	_, err := Analyze(&nef.File{Script: append([]byte{byte(opcode.PUSHDATA4), 0, 0, 0x20, 0}, make([]byte, stackitem.MaxSize+1)...)}, Options{})
+	require.Error(t, err)
+}
+
+func TestSeverity(t *testing.T) {
+	for _, s := range []Severity{Info, Warning, Error} {
+		p, err := ParseSeverity(s.String())
+		require.NoError(t, err)
+		require.Equal(t, s, p)
+	}
+	_, err := ParseSeverity("fatal")
+	require.Error(t, err)
+}
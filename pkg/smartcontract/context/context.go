@@ -2,6 +2,7 @@ package context
 
 import (
 	"bytes"
+	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -39,6 +40,10 @@ type ParameterContext struct {
 	Items map[util.Uint160]*Item
 }
 
+// ErrNetworkMismatch is returned when the context is used with a network it
+// doesn't belong to.
+var ErrNetworkMismatch = errors.New("context network mismatch")
+
 type paramContext struct {
 	Type  string                     `json:"type"`
 	Net   uint32                     `json:"network"`
@@ -89,13 +94,28 @@ func (c *ParameterContext) GetWitness(h util.Uint160) (*transaction.Witness, err
 		return nil, errors.New("witness not found")
 	}
 	bw := io.NewBufBinWriter()
-	for i := range item.Parameters {
-		if item.Parameters[i].Type != smartcontract.SignatureType {
-			return nil, fmt.Errorf("unsupported %s parameter #%d", item.Parameters[i].Type.String(), i)
-		} else if item.Parameters[i].Value == nil {
-			return nil, fmt.Errorf("no value for parameter #%d (not signed yet?)", i)
+	n := len(item.Parameters)
+	for j := 0; j < n; j++ {
+		i := j
+		if item.Script == nil { // Deployed contract, verify arguments are pushed in reverse order.
+			i = n - 1 - j
+		}
+		p := item.Parameters[i]
+		if p.Value == nil {
+			if p.Type == smartcontract.SignatureType {
+				return nil, fmt.Errorf("no value for parameter #%d (not signed yet?)", i)
+			} else if p.Type != smartcontract.AnyType {
+				return nil, fmt.Errorf("no value for %s parameter #%d", p.Type.String(), i)
+			}
+		}
+		v, err := smartcontract.ExpandParameterToEmitable(p)
+		if err != nil {
+			return nil, fmt.Errorf("parameter #%d: %w", i, err)
 		}
-		emit.Bytes(bw.BinWriter, item.Parameters[i].Value.([]byte))
+		emit.Any(bw.BinWriter, v)
+	}
+	if bw.Err != nil {
+		return nil, bw.Err
 	}
 	return &transaction.Witness{
 		InvocationScript:   bw.Bytes(),
@@ -103,6 +123,67 @@ func (c *ParameterContext) GetWitness(h util.Uint160) (*transaction.Witness, err
 	}, nil
 }
 
+// CheckNetwork returns ErrNetworkMismatch (with both networks specified) if
+// the context belongs to a network other than the given one.
+func (c *ParameterContext) CheckNetwork(net netmode.Magic) error {
+	if c.Network != net {
+		return fmt.Errorf("%w: context is made for %s (%d), but %s (%d) is used",
+			ErrNetworkMismatch, c.Network, uint32(c.Network), net, uint32(net))
+	}
+	return nil
+}
+
+// SetNetwork moves the context to the given network. Signatures are made for
+// the network-specific hash of the verifiable item, so the ones that are not
+// valid for the new network are removed. Signature parameter values that
+// can't be checked (of deployed contracts) are removed as well and so are all
+// multisignature parameter values if any of the signatures is removed (they're
+// refilled by AddSignature when there are enough signatures). It returns the
+// number of signatures removed.
+func (c *ParameterContext) SetNetwork(net netmode.Magic) int {
+	var removed int
+	if c.Network == net {
+		return 0
+	}
+	c.Network = net
+	for _, item := range c.Items {
+		var itemRemoved int
+		for pubHex, sig := range item.Signatures {
+			pub, err := keys.NewPublicKeyFromString(pubHex)
+			if err != nil || !pub.VerifyHashable(sig, uint32(net), c.Verifiable) {
+				delete(item.Signatures, pubHex)
+				itemRemoved++
+			}
+		}
+		var (
+			_, _, multi = vm.ParseMultiSigContract(item.Script)
+			pub         *keys.PublicKey
+		)
+		if pubBytes, ok := vm.ParseSignatureContract(item.Script); ok {
+			pub, _ = keys.NewPublicKeyFromBytes(pubBytes, elliptic.P256())
+		}
+		for i := range item.Parameters {
+			p := &item.Parameters[i]
+			if p.Type != smartcontract.SignatureType || p.Value == nil {
+				continue
+			}
+			if multi {
+				if itemRemoved != 0 {
+					p.Value = nil
+				}
+				continue
+			}
+			sig, ok := p.Value.([]byte)
+			if !ok || pub == nil || !pub.VerifyHashable(sig, uint32(net), c.Verifiable) {
+				p.Value = nil
+				itemRemoved++
+			}
+		}
+		removed += itemRemoved
+	}
+	return removed
+}
+
 // AddSignature adds a signature for the specified contract and public key.
 func (c *ParameterContext) AddSignature(h util.Uint160, ctr *wallet.Contract, pub *keys.PublicKey, sig []byte) error {
 	item := c.getItemForContract(h, ctr)
@@ -166,8 +247,33 @@ func (c *ParameterContext) AddSignature(h util.Uint160, ctr *wallet.Contract, pu
 	return nil
 }
 
+// AddParameters sets values of non-signature verification parameters for the
+// specified contract (creating a context item for it if needed). It's intended
+// for deployed contracts which verify methods accept arbitrary arguments.
+// Values are given in the contract parameters order, Signature ones are
+// ignored (use AddSignature for them). If params is nil only the item is
+// created, so that its parameter types are available to other signers.
+func (c *ParameterContext) AddParameters(h util.Uint160, ctr *wallet.Contract, params []smartcontract.Parameter) error {
+	if params != nil && len(params) != len(ctr.Parameters) {
+		return fmt.Errorf("expected %d parameters, got %d", len(ctr.Parameters), len(params))
+	}
+	for i := range params {
+		typ := ctr.Parameters[i].Type
+		if typ != smartcontract.SignatureType && typ != smartcontract.AnyType && params[i].Type != typ {
+			return fmt.Errorf("parameter #%d has %s type, %s expected", i, params[i].Type, typ)
+		}
+	}
+	item := c.getItemForContract(h, ctr)
+	for i := range params {
+		if ctr.Parameters[i].Type != smartcontract.SignatureType {
+			item.Parameters[i] = params[i]
+		}
+	}
+	return nil
+}
+
 func (c *ParameterContext) getItemForContract(h util.Uint160, ctr *wallet.Contract) *Item {
-	item, ok := c.Items[ctr.ScriptHash()]
+	item, ok := c.Items[h]
 	if ok {
 		return item
 	}
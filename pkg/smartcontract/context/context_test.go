@@ -3,6 +3,7 @@ package context
 import (
 	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/testserdes"
@@ -158,6 +159,175 @@ func TestParameterContext_AddSignatureMultisig(t *testing.T) {
 	})
 }
 
+func TestParameterContext_AddParameters(t *testing.T) {
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	h := util.Uint160{1, 2, 3}
+	ctr := &wallet.Contract{
+		Script: []byte{byte(opcode.PUSHT)},
+		Parameters: []wallet.ContractParam{
+			newParam(smartcontract.StringType, "password"),
+			newParam(smartcontract.SignatureType, "sig"),
+		},
+		Deployed: true,
+	}
+	tx := getContractTx(h)
+	c := NewParameterContext(TransactionType, netmode.UnitTestNet, tx)
+
+	require.Error(t, c.AddParameters(h, ctr, []smartcontract.Parameter{{Type: smartcontract.StringType}}))
+	require.Error(t, c.AddParameters(h, ctr, []smartcontract.Parameter{
+		{Type: smartcontract.IntegerType},
+		{Type: smartcontract.SignatureType},
+	}))
+	require.Equal(t, 0, len(c.Items))
+
+	require.NoError(t, c.AddParameters(h, ctr, nil)) // Parameter specification only.
+	item := c.Items[h]
+	require.NotNil(t, item)
+	require.Nil(t, item.Script)
+	require.Equal(t, []smartcontract.Parameter{{Type: smartcontract.StringType}, {Type: smartcontract.SignatureType}}, item.Parameters)
+	_, err = c.GetWitness(h)
+	require.Error(t, err)
+
+	sig := priv.SignHashable(uint32(c.Network), tx)
+	require.NoError(t, c.AddSignature(h, ctr, priv.PublicKey(), sig))
+	_, err = c.GetWitness(h)
+	require.Error(t, err) // No password.
+
+	require.NoError(t, c.AddParameters(h, ctr, []smartcontract.Parameter{
+		{Type: smartcontract.StringType, Value: "pass"},
+		{Type: smartcontract.SignatureType, Value: []byte{1, 2, 3}}, // Ignored.
+	}))
+	w, err := c.GetWitness(h)
+	require.NoError(t, err)
+	require.Nil(t, w.VerificationScript)
+
+	// Arguments are pushed in reverse order.
+	expected := append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sig...)
+	expected = append(expected, byte(opcode.PUSHDATA1), 4)
+	require.Equal(t, append(expected, "pass"...), w.InvocationScript)
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(c)
+		require.NoError(t, err)
+		actual := new(ParameterContext)
+		require.NoError(t, json.Unmarshal(data, actual))
+		w2, err := actual.GetWitness(h)
+		require.NoError(t, err)
+		require.Equal(t, w, w2)
+	})
+}
+
+func TestParameterContext_GetWitnessOrder(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+	c := NewParameterContext(TransactionType, netmode.UnitTestNet, getContractTx(h))
+	params := []smartcontract.Parameter{
+		{Type: smartcontract.SignatureType, Value: []byte{1}},
+		{Type: smartcontract.SignatureType, Value: []byte{2}},
+	}
+	pushes := func(vals ...byte) []byte {
+		var res []byte
+		for _, v := range vals {
+			res = append(res, byte(opcode.PUSHDATA1), 1, v)
+		}
+		return res
+	}
+
+	t.Run("script", func(t *testing.T) {
+		// Verification script consumes the stack as is, so the order is kept.
+		c.Items[h] = &Item{Script: []byte{byte(opcode.PUSHT)}, Parameters: params}
+		w, err := c.GetWitness(h)
+		require.NoError(t, err)
+		require.Equal(t, pushes(1, 2), w.InvocationScript)
+	})
+	t.Run("deployed", func(t *testing.T) {
+		// Verify method arguments are taken from the stack top (the first
+		// argument is pushed last), the same way they're passed to any
+		// other contract method.
+		c.Items[h] = &Item{Parameters: params}
+		w, err := c.GetWitness(h)
+		require.NoError(t, err)
+		require.Equal(t, pushes(2, 1), w.InvocationScript)
+	})
+}
+
+func TestParameterContext_CheckNetwork(t *testing.T) {
+	c := NewParameterContext(TransactionType, netmode.TestNet, getContractTx(util.Uint160{1}))
+	require.NoError(t, c.CheckNetwork(netmode.TestNet))
+	err := c.CheckNetwork(netmode.UnitTestNet)
+	require.ErrorIs(t, err, ErrNetworkMismatch)
+	require.Contains(t, err.Error(), "testnet (894710606)")
+	require.Contains(t, err.Error(), "unit_testnet (42)")
+}
+
+func TestParameterContext_SetNetwork(t *testing.T) {
+	privs, pubs := getPrivateKeys(t, 4)
+	multiScript, err := smartcontract.CreateMultiSigRedeemScript(2, keys.PublicKeys(pubs[:3]).Copy())
+	require.NoError(t, err)
+	var (
+		simpleCtr = &wallet.Contract{
+			Script:     pubs[3].GetVerificationScript(),
+			Parameters: []wallet.ContractParam{newParam(smartcontract.SignatureType, "parameter0")},
+		}
+		multiCtr = &wallet.Contract{
+			Script: multiScript,
+			Parameters: []wallet.ContractParam{
+				newParam(smartcontract.SignatureType, "parameter0"),
+				newParam(smartcontract.SignatureType, "parameter1"),
+			},
+		}
+		deployedCtr = &wallet.Contract{
+			Parameters: []wallet.ContractParam{
+				newParam(smartcontract.IntegerType, "n"),
+				newParam(smartcontract.SignatureType, "sig"),
+			},
+			Deployed: true,
+		}
+		deployedHash = util.Uint160{1, 2, 3}
+	)
+	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+	tx.Signers = []transaction.Signer{{Account: simpleCtr.ScriptHash()}, {Account: multiCtr.ScriptHash()}, {Account: deployedHash}}
+	tx.Scripts = []transaction.Witness{}
+
+	c := NewParameterContext(TransactionType, netmode.TestNet, tx)
+	require.NoError(t, c.AddSignature(simpleCtr.ScriptHash(), simpleCtr, pubs[3], privs[3].SignHashable(uint32(netmode.TestNet), tx)))
+	// Partially signed multisig.
+	require.NoError(t, c.AddSignature(multiCtr.ScriptHash(), multiCtr, pubs[0], privs[0].SignHashable(uint32(netmode.TestNet), tx)))
+	require.NoError(t, c.AddParameters(deployedHash, deployedCtr, []smartcontract.Parameter{{Type: smartcontract.IntegerType, Value: big.NewInt(42)}, {}}))
+	require.NoError(t, c.AddSignature(deployedHash, deployedCtr, pubs[3], []byte{1, 2, 3}))
+
+	require.Equal(t, 0, c.SetNetwork(netmode.TestNet))
+	require.Equal(t, 1, len(c.Items[multiCtr.ScriptHash()].Signatures))
+
+	require.Equal(t, 3, c.SetNetwork(netmode.UnitTestNet))
+	require.Equal(t, netmode.UnitTestNet, c.Network)
+	require.Nil(t, c.Items[simpleCtr.ScriptHash()].Parameters[0].Value)
+	require.Equal(t, 0, len(c.Items[multiCtr.ScriptHash()].Signatures))
+	require.Equal(t, big.NewInt(42), c.Items[deployedHash].Parameters[0].Value)
+	require.Nil(t, c.Items[deployedHash].Parameters[1].Value)
+
+	// Converted context can be signed for the new network.
+	require.NoError(t, c.AddSignature(simpleCtr.ScriptHash(), simpleCtr, pubs[3], privs[3].SignHashable(uint32(netmode.UnitTestNet), tx)))
+	for _, i := range []int{2, 0} {
+		require.NoError(t, c.AddSignature(multiCtr.ScriptHash(), multiCtr, pubs[i], privs[i].SignHashable(uint32(netmode.UnitTestNet), tx)))
+	}
+	for _, h := range []util.Uint160{simpleCtr.ScriptHash(), multiCtr.ScriptHash()} {
+		w, err := c.GetWitness(h)
+		require.NoError(t, err)
+		v := newTestVM(w, tx)
+		require.NoError(t, v.Run())
+		require.Equal(t, true, v.Estack().Pop().Value())
+	}
+
+	t.Run("full multisig", func(t *testing.T) {
+		require.Equal(t, 0, c.SetNetwork(netmode.UnitTestNet))
+		require.Equal(t, 3, c.SetNetwork(netmode.TestNet))
+		for i := range c.Items[multiCtr.ScriptHash()].Parameters {
+			require.Nil(t, c.Items[multiCtr.ScriptHash()].Parameters[i].Value)
+		}
+	})
+}
+
 func newTestVM(w *transaction.Witness, tx *transaction.Transaction) *vm.VM {
 	ic := &interop.Context{Network: uint32(netmode.UnitTestNet), Container: tx, Functions: crypto.Interops}
 	v := ic.SpawnVM()
@@ -0,0 +1,331 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ChangeType is a type of the manifest change.
+type ChangeType byte
+
+const (
+	// Added means that the element is present in the new manifest only.
+	Added ChangeType = iota
+	// Removed means that the element is present in the old manifest only.
+	Removed
+	// Modified means that the element is present in both manifests, but it
+	// differs.
+	Modified
+)
+
+// Manifest elements that can be changed.
+const (
+	ElementName       = "name"
+	ElementMethod     = "method"
+	ElementEvent      = "event"
+	ElementPermission = "permission"
+	ElementStandard   = "standard"
+)
+
+// Change describes a single difference between two manifests.
+type Change struct {
+	Type ChangeType
+	// Element is the kind of the changed manifest element, one of Element*
+	// constants.
+	Element string
+	// Name identifies the element: method name with the number of parameters
+	// (like "transfer/4"), event name, permission contract descriptor (as in
+	// JSON) or standard name.
+	Name string
+	// Details is a human-readable description of the modification, it's
+	// empty for added and removed elements.
+	Details string
+	// Breaking is true if the change can break existing callers (like
+	// method removal or parameter type change) or contract behavior (like
+	// permission narrowing).
+	Breaking bool
+}
+
+// DiffReport is a result of manifest comparison.
+type DiffReport struct {
+	Changes []Change
+}
+
+// String implements the fmt.Stringer interface.
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return fmt.Sprintf("unknown (%d)", byte(t))
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (c Change) String() string {
+	var s = c.Type.String() + " " + c.Element + " " + c.Name
+	if c.Details != "" {
+		s += ": " + c.Details
+	}
+	if c.Breaking {
+		s = "[breaking] " + s
+	}
+	return s
+}
+
+// IsBreaking returns true if the report contains at least one breaking change.
+func (r *DiffReport) IsBreaking() bool {
+	for i := range r.Changes {
+		if r.Changes[i].Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff compares manifests of the same contract before (from) and after (to)
+// the update and returns the list of changes in name, methods, events,
+// permissions and supported standards. Method offsets, groups, trusts and
+// extra data are not compared.
+func Diff(from, to *Manifest) *DiffReport {
+	var r = new(DiffReport)
+
+	if from.Name != to.Name {
+		r.add(Modified, ElementName, from.Name, fmt.Sprintf("renamed to %s", to.Name), true)
+	}
+	r.diffMethods(from.ABI.Methods, to.ABI.Methods)
+	r.diffEvents(from.ABI.Events, to.ABI.Events)
+	r.diffPermissions(from.Permissions, to.Permissions)
+	for _, s := range from.SupportedStandards {
+		if !to.IsStandardSupported(s) {
+			r.add(Removed, ElementStandard, s, "", true)
+		}
+	}
+	for _, s := range to.SupportedStandards {
+		if !from.IsStandardSupported(s) {
+			r.add(Added, ElementStandard, s, "", false)
+		}
+	}
+	return r
+}
+
+func (r *DiffReport) add(typ ChangeType, element string, name string, details string, breaking bool) {
+	r.Changes = append(r.Changes, Change{
+		Type:     typ,
+		Element:  element,
+		Name:     name,
+		Details:  details,
+		Breaking: breaking,
+	})
+}
+
+func (r *DiffReport) diffMethods(from, to []Method) {
+	var (
+		fromABI = ABI{Methods: from}
+		toABI   = ABI{Methods: to}
+	)
+	for i := range from {
+		var (
+			o    = &from[i]
+			name = fmt.Sprintf("%s/%d", o.Name, len(o.Parameters))
+			n    = toABI.GetMethod(o.Name, len(o.Parameters))
+		)
+		if n == nil {
+			r.add(Removed, ElementMethod, name, "", true)
+			continue
+		}
+		var (
+			details  []string
+			breaking bool
+		)
+		if !equalParamTypes(o.Parameters, n.Parameters) {
+			details = append(details, fmt.Sprintf("parameter types (%s) -> (%s)",
+				paramTypes(o.Parameters), paramTypes(n.Parameters)))
+			breaking = true
+		} else if !equalParamNames(o.Parameters, n.Parameters) {
+			details = append(details, "parameter names changed")
+		}
+		if o.ReturnType != n.ReturnType {
+			details = append(details, fmt.Sprintf("return type %s -> %s", o.ReturnType, n.ReturnType))
+			breaking = true
+		}
+		if o.Safe != n.Safe {
+			if o.Safe {
+				// Callers using read-only call flags can't call it anymore.
+				details = append(details, "no longer safe")
+				breaking = true
+			} else {
+				details = append(details, "became safe")
+			}
+		}
+		if len(details) != 0 {
+			r.add(Modified, ElementMethod, name, strings.Join(details, ", "), breaking)
+		}
+	}
+	for i := range to {
+		if fromABI.GetMethod(to[i].Name, len(to[i].Parameters)) == nil {
+			r.add(Added, ElementMethod, fmt.Sprintf("%s/%d", to[i].Name, len(to[i].Parameters)), "", false)
+		}
+	}
+}
+
+func (r *DiffReport) diffEvents(from, to []Event) {
+	var (
+		fromABI = ABI{Events: from}
+		toABI   = ABI{Events: to}
+	)
+	for i := range from {
+		var (
+			o = &from[i]
+			n = toABI.GetEvent(o.Name)
+		)
+		if n == nil {
+			r.add(Removed, ElementEvent, o.Name, "", true)
+			continue
+		}
+		if !equalParamTypes(o.Parameters, n.Parameters) {
+			r.add(Modified, ElementEvent, o.Name, fmt.Sprintf("parameter types (%s) -> (%s)",
+				paramTypes(o.Parameters), paramTypes(n.Parameters)), true)
+		} else if !equalParamNames(o.Parameters, n.Parameters) {
+			r.add(Modified, ElementEvent, o.Name, "parameter names changed", false)
+		}
+	}
+	for i := range to {
+		if fromABI.GetEvent(to[i].Name) == nil {
+			r.add(Added, ElementEvent, to[i].Name, "", false)
+		}
+	}
+}
+
+// diffPermissions reports permissions with contract descriptors present in
+// one manifest only as added or removed and permissions with different
+// method sets as modified. Changes are breaking if some call allowed by the
+// old permissions is not allowed by the new ones.
+func (r *DiffReport) diffPermissions(from, to []Permission) {
+	for i := range from {
+		var (
+			o        = &from[i]
+			name     = permissionDescString(&o.Contract)
+			n        = findPermission(to, o.Contract)
+			breaking = !permissionsAllow(to, o)
+		)
+		switch {
+		case n == nil:
+			r.add(Removed, ElementPermission, name, "", breaking)
+		case !equalWildStrings(o.Methods, n.Methods):
+			r.add(Modified, ElementPermission, name, fmt.Sprintf("methods %s -> %s",
+				wildStringsString(o.Methods), wildStringsString(n.Methods)), breaking)
+		}
+	}
+	for i := range to {
+		if findPermission(from, to[i].Contract) == nil {
+			r.add(Added, ElementPermission, permissionDescString(&to[i].Contract), "", false)
+		}
+	}
+}
+
+// findPermission returns permission for the given contract descriptor or nil
+// if there is none.
+func findPermission(ps []Permission, d PermissionDesc) *Permission {
+	for i := range ps {
+		if samePermissionDesc(ps[i].Contract, d) {
+			return &ps[i]
+		}
+	}
+	return nil
+}
+
+// permissionsAllow checks whether all calls allowed by p are allowed by ps.
+// Hash and group descriptors can't be compared without the contract state,
+// so only the same or wildcard descriptors are taken into account.
+func permissionsAllow(ps []Permission, p *Permission) bool {
+	allowed := func(method string) bool {
+		for i := range ps {
+			if ps[i].Contract.Type != PermissionWildcard && !samePermissionDesc(ps[i].Contract, p.Contract) {
+				continue
+			}
+			if ps[i].Methods.IsWildcard() || method != "" && ps[i].Methods.Contains(method) {
+				return true
+			}
+		}
+		return false
+	}
+	if p.Methods.IsWildcard() {
+		return allowed("")
+	}
+	for _, m := range p.Methods.Value {
+		if !allowed(m) {
+			return false
+		}
+	}
+	return true
+}
+
+// samePermissionDesc is similar to PermissionDesc.Equals, but it also
+// treats wildcard descriptors as equal.
+func samePermissionDesc(a, b PermissionDesc) bool {
+	return a.Type == b.Type && (a.Type == PermissionWildcard || a.Equals(b))
+}
+
+func permissionDescString(d *PermissionDesc) string {
+	data, err := d.MarshalJSON()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.Trim(string(data), `"`)
+}
+
+func equalWildStrings(a, b WildStrings) bool {
+	if a.IsWildcard() || b.IsWildcard() {
+		return a.IsWildcard() == b.IsWildcard()
+	}
+	if len(a.Value) != len(b.Value) {
+		return false
+	}
+	for _, s := range a.Value {
+		if !b.Contains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func wildStringsString(c WildStrings) string {
+	if c.IsWildcard() {
+		return "*"
+	}
+	return "[" + strings.Join(c.Value, ", ") + "]"
+}
+
+func equalParamTypes(a, b []Parameter) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type {
+			return false
+		}
+	}
+	return true
+}
+
+func equalParamNames(a, b []Parameter) bool {
+	for i := range a {
+		if a[i].Name != b[i].Name {
+			return false
+		}
+	}
+	return true
+}
+
+func paramTypes(ps []Parameter) string {
+	var types = make([]string, len(ps))
+	for i := range ps {
+		types[i] = ps[i].Type.String()
+	}
+	return strings.Join(types, ", ")
+}
@@ -0,0 +1,153 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newDiffTestManifest() *Manifest {
+	m := NewManifest("Test")
+	m.ABI.Methods = []Method{{
+		Name:       "transfer",
+		Offset:     0,
+		Parameters: []Parameter{NewParameter("from", smartcontract.Hash160Type), NewParameter("amount", smartcontract.IntegerType)},
+		ReturnType: smartcontract.BoolType,
+	}, {
+		Name:       "balanceOf",
+		Offset:     10,
+		Parameters: []Parameter{NewParameter("account", smartcontract.Hash160Type)},
+		ReturnType: smartcontract.IntegerType,
+		Safe:       true,
+	}}
+	m.ABI.Events = []Event{{
+		Name:       "Transfer",
+		Parameters: []Parameter{NewParameter("from", smartcontract.Hash160Type)},
+	}}
+	m.Permissions = []Permission{*NewPermission(PermissionHash, util.Uint160{1, 2, 3})}
+	m.Permissions[0].Methods.Add("onNEP17Payment")
+	m.SupportedStandards = []string{"NEP-17"}
+	return m
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("same", func(t *testing.T) {
+		m := newDiffTestManifest()
+		r := Diff(m, newDiffTestManifest())
+		require.Equal(t, 0, len(r.Changes))
+		require.False(t, r.IsBreaking())
+	})
+	t.Run("offsets", func(t *testing.T) {
+		m := newDiffTestManifest()
+		m.ABI.Methods[1].Offset = 20
+		require.Equal(t, 0, len(Diff(newDiffTestManifest(), m).Changes))
+	})
+	t.Run("wildcard permission", func(t *testing.T) {
+		require.Equal(t, 0, len(Diff(DefaultManifest("Test"), DefaultManifest("Test")).Changes))
+	})
+	testCases := map[string]struct {
+		modify   func(m *Manifest)
+		expected []Change
+	}{
+		"added method": {
+			modify: func(m *Manifest) {
+				m.ABI.Methods = append(m.ABI.Methods, Method{Name: "transfer", ReturnType: smartcontract.VoidType})
+			},
+			expected: []Change{{Type: Added, Element: ElementMethod, Name: "transfer/0"}},
+		},
+		"removed method": {
+			modify:   func(m *Manifest) { m.ABI.Methods = m.ABI.Methods[1:] },
+			expected: []Change{{Type: Removed, Element: ElementMethod, Name: "transfer/2", Breaking: true}},
+		},
+		"parameter types": {
+			modify: func(m *Manifest) { m.ABI.Methods[0].Parameters[1].Type = smartcontract.StringType },
+			expected: []Change{{Type: Modified, Element: ElementMethod, Name: "transfer/2",
+				Details: "parameter types (Hash160, Integer) -> (Hash160, String)", Breaking: true}},
+		},
+		"parameter names": {
+			modify: func(m *Manifest) { m.ABI.Methods[0].Parameters[1].Name = "value" },
+			expected: []Change{{Type: Modified, Element: ElementMethod, Name: "transfer/2",
+				Details: "parameter names changed"}},
+		},
+		"return type and safe flag": {
+			modify: func(m *Manifest) {
+				m.ABI.Methods[0].ReturnType = smartcontract.VoidType
+				m.ABI.Methods[0].Safe = true
+			},
+			expected: []Change{{Type: Modified, Element: ElementMethod, Name: "transfer/2",
+				Details: "return type Boolean -> Void, became safe", Breaking: true}},
+		},
+		"no longer safe": {
+			modify: func(m *Manifest) { m.ABI.Methods[1].Safe = false },
+			expected: []Change{{Type: Modified, Element: ElementMethod, Name: "balanceOf/1",
+				Details: "no longer safe", Breaking: true}},
+		},
+		"events": {
+			modify: func(m *Manifest) {
+				m.ABI.Events[0].Parameters[0].Type = smartcontract.ByteArrayType
+				m.ABI.Events = append(m.ABI.Events, Event{Name: "Mint"})
+			},
+			expected: []Change{
+				{Type: Modified, Element: ElementEvent, Name: "Transfer",
+					Details: "parameter types (Hash160) -> (ByteArray)", Breaking: true},
+				{Type: Added, Element: ElementEvent, Name: "Mint"},
+			},
+		},
+		"removed event": {
+			modify:   func(m *Manifest) { m.ABI.Events = nil },
+			expected: []Change{{Type: Removed, Element: ElementEvent, Name: "Transfer", Breaking: true}},
+		},
+		"widened permission": {
+			modify: func(m *Manifest) { m.Permissions[0].Methods.Add("transfer") },
+			expected: []Change{{Type: Modified, Element: ElementPermission, Name: "0x0000000000000000000000000000000000030201",
+				Details: "methods [onNEP17Payment] -> [onNEP17Payment, transfer]"}},
+		},
+		"narrowed permission": {
+			modify: func(m *Manifest) { m.Permissions[0].Methods.Restrict() },
+			expected: []Change{{Type: Modified, Element: ElementPermission, Name: "0x0000000000000000000000000000000000030201",
+				Details: "methods [onNEP17Payment] -> []", Breaking: true}},
+		},
+		"replaced permission with wildcard": {
+			modify: func(m *Manifest) { m.Permissions = []Permission{*NewPermission(PermissionWildcard)} },
+			expected: []Change{
+				{Type: Removed, Element: ElementPermission, Name: "0x0000000000000000000000000000000000030201"},
+				{Type: Added, Element: ElementPermission, Name: "*"},
+			},
+		},
+		"name and standards": {
+			modify: func(m *Manifest) {
+				m.Name = "Other"
+				m.SupportedStandards = []string{"NEP-11"}
+			},
+			expected: []Change{
+				{Type: Modified, Element: ElementName, Name: "Test", Details: "renamed to Other", Breaking: true},
+				{Type: Removed, Element: ElementStandard, Name: "NEP-17", Breaking: true},
+				{Type: Added, Element: ElementStandard, Name: "NEP-11"},
+			},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			m := newDiffTestManifest()
+			tc.modify(m)
+			r := Diff(newDiffTestManifest(), m)
+			require.Equal(t, tc.expected, r.Changes)
+			var breaking bool
+			for _, c := range tc.expected {
+				breaking = breaking || c.Breaking
+			}
+			require.Equal(t, breaking, r.IsBreaking())
+		})
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	require.Equal(t, "added method transfer/0", Change{Type: Added, Element: ElementMethod, Name: "transfer/0"}.String())
+	require.Equal(t, "[breaking] modified event Transfer: parameter types () -> (Integer)", Change{
+		Type: Modified, Element: ElementEvent, Name: "Transfer",
+		Details: "parameter types () -> (Integer)", Breaking: true,
+	}.String())
+	require.Equal(t, "unknown (42)", ChangeType(42).String())
+}
@@ -0,0 +1,222 @@
+package smartcontract
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// jsonParamType is a string representation of `json` parameter type used in
+// cli, its value is a JSON document parsed with NewParameterFromJSON.
+const jsonParamType = "json"
+
+// Prefixes of JSON strings that are converted to ByteArray parameters by
+// NewParameterFromJSON.
+const (
+	jsonHexPrefix    = "hex:"
+	jsonBase64Prefix = "base64:"
+)
+
+// NewParameterFromJSON returns a new Parameter parsed from the given JSON
+// document. Unlike Parameter.UnmarshalJSON it accepts both explicitly typed
+// nodes and a convenience form that can be nested arbitrarily:
+//   - objects with "type" key are explicitly typed parameters in the regular
+//     {"type": ..., "value": ...} format, but Array elements and Map keys and
+//     values can use the convenience form as well;
+//   - other objects are converted to Map parameters with String keys (in the
+//     order they're specified in);
+//   - arrays are converted to Array parameters;
+//   - strings are converted to String parameters unless they have "hex:" or
+//     "base64:" prefix, in which case they're decoded to ByteArray;
+//   - numbers are converted to Integer parameters (only integers are allowed);
+//   - booleans are converted to Boolean parameters;
+//   - null is converted to Any parameter with nil value.
+//
+// Maps with "type" key and strings starting with "hex:" or "base64:" can be
+// specified explicitly if needed.
+func NewParameterFromJSON(data []byte) (Parameter, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Parameter{}, errors.New("empty JSON")
+	}
+	switch data[0] {
+	case '{':
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return Parameter{}, err
+		}
+		if _, ok := fields["type"]; ok {
+			return newTypedParameterFromJSON(data, fields)
+		}
+		keys, err := jsonObjectKeys(data)
+		if err != nil {
+			return Parameter{}, err
+		}
+		var pairs = make([]ParameterPair, 0, len(keys))
+		for _, k := range keys {
+			v, err := NewParameterFromJSON(fields[k])
+			if err != nil {
+				return Parameter{}, fmt.Errorf("key %q: %w", k, err)
+			}
+			pairs = append(pairs, ParameterPair{
+				Key:   Parameter{Type: StringType, Value: k},
+				Value: v,
+			})
+		}
+		return Parameter{Type: MapType, Value: pairs}, nil
+	case '[':
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return Parameter{}, err
+		}
+		arr, err := newParametersFromJSON(elems)
+		if err != nil {
+			return Parameter{}, err
+		}
+		return Parameter{Type: ArrayType, Value: arr}, nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return Parameter{}, err
+		}
+		var (
+			b   []byte
+			err error
+		)
+		switch {
+		case strings.HasPrefix(s, jsonHexPrefix):
+			b, err = hex.DecodeString(strings.TrimPrefix(s, jsonHexPrefix))
+		case strings.HasPrefix(s, jsonBase64Prefix):
+			b, err = base64.StdEncoding.DecodeString(strings.TrimPrefix(s, jsonBase64Prefix))
+		default:
+			return Parameter{Type: StringType, Value: s}, nil
+		}
+		if err != nil {
+			return Parameter{}, fmt.Errorf("bad byte array %q: %w", s, err)
+		}
+		return Parameter{Type: ByteArrayType, Value: b}, nil
+	case 't', 'f':
+		var b bool
+		if err := json.Unmarshal(data, &b); err != nil {
+			return Parameter{}, err
+		}
+		return Parameter{Type: BoolType, Value: b}, nil
+	case 'n':
+		if !bytes.Equal(data, []byte("null")) {
+			return Parameter{}, fmt.Errorf("invalid JSON value %s", data)
+		}
+		return Parameter{Type: AnyType}, nil
+	default:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return Parameter{}, err
+		}
+		bi, ok := new(big.Int).SetString(n.String(), 10)
+		if !ok {
+			return Parameter{}, fmt.Errorf("%s is not an integer", n)
+		}
+		if err := stackitem.CheckIntegerSize(bi); err != nil {
+			return Parameter{}, err
+		}
+		return Parameter{Type: IntegerType, Value: bi}, nil
+	}
+}
+
+// newTypedParameterFromJSON parses explicitly typed parameter node.
+func newTypedParameterFromJSON(data []byte, fields map[string]json.RawMessage) (Parameter, error) {
+	var (
+		typ ParamType
+		raw = fields["value"]
+	)
+	if err := json.Unmarshal(fields["type"], &typ); err != nil {
+		return Parameter{}, err
+	}
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) || (typ != ArrayType && typ != MapType) {
+		var p Parameter
+		err := p.UnmarshalJSON(data)
+		return p, err
+	}
+	if typ == ArrayType {
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return Parameter{}, err
+		}
+		arr, err := newParametersFromJSON(elems)
+		if err != nil {
+			return Parameter{}, err
+		}
+		return Parameter{Type: ArrayType, Value: arr}, nil
+	}
+	var rawPairs []struct {
+		Key   json.RawMessage `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &rawPairs); err != nil {
+		return Parameter{}, err
+	}
+	var pairs = make([]ParameterPair, len(rawPairs))
+	for i := range rawPairs {
+		if len(rawPairs[i].Key) == 0 || len(rawPairs[i].Value) == 0 {
+			return Parameter{}, fmt.Errorf("map element #%d: missing key or value", i)
+		}
+		k, err := NewParameterFromJSON(rawPairs[i].Key)
+		if err != nil {
+			return Parameter{}, fmt.Errorf("map key #%d: %w", i, err)
+		}
+		v, err := NewParameterFromJSON(rawPairs[i].Value)
+		if err != nil {
+			return Parameter{}, fmt.Errorf("map value #%d: %w", i, err)
+		}
+		pairs[i] = ParameterPair{Key: k, Value: v}
+	}
+	return Parameter{Type: MapType, Value: pairs}, nil
+}
+
+func newParametersFromJSON(elems []json.RawMessage) ([]Parameter, error) {
+	var res = make([]Parameter, len(elems))
+	for i := range elems {
+		var err error
+		res[i], err = NewParameterFromJSON(elems[i])
+		if err != nil {
+			return nil, fmt.Errorf("element #%d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// jsonObjectKeys returns keys of the JSON object in the order they're
+// specified in, duplicate keys are not allowed.
+func jsonObjectKeys(data []byte) ([]string, error) {
+	var (
+		dec  = json.NewDecoder(bytes.NewReader(data))
+		keys []string
+		seen = make(map[string]bool)
+	)
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		k := t.(string)
+		if seen[k] {
+			return nil, fmt.Errorf("duplicate key %q", k)
+		}
+		seen[k] = true
+		keys = append(keys, k)
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
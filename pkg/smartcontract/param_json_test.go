@@ -0,0 +1,238 @@
+package smartcontract
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParameterFromJSON(t *testing.T) {
+	testCases := []struct {
+		in       string
+		param    *Parameter
+		expected stackitem.Item
+	}{
+		{
+			in:       `42`,
+			param:    &Parameter{Type: IntegerType, Value: big.NewInt(42)},
+			expected: stackitem.Make(42),
+		},
+		{
+			in:       `-100500100500100500100500`,
+			expected: stackitem.NewBigInteger(func() *big.Int { b, _ := new(big.Int).SetString("-100500100500100500100500", 10); return b }()),
+		},
+		{
+			in:       `true`,
+			param:    &Parameter{Type: BoolType, Value: true},
+			expected: stackitem.NewBool(true),
+		},
+		{
+			in:       `null`,
+			param:    &Parameter{Type: AnyType},
+			expected: stackitem.Null{},
+		},
+		{
+			in:       `"str"`,
+			param:    &Parameter{Type: StringType, Value: "str"},
+			expected: stackitem.Make("str"),
+		},
+		{
+			in:       `"hex:0102ff"`,
+			param:    &Parameter{Type: ByteArrayType, Value: []byte{1, 2, 0xff}},
+			expected: stackitem.NewByteArray([]byte{1, 2, 0xff}),
+		},
+		{
+			in:       `"base64:AQL/"`,
+			param:    &Parameter{Type: ByteArrayType, Value: []byte{1, 2, 0xff}},
+			expected: stackitem.NewByteArray([]byte{1, 2, 0xff}),
+		},
+		{
+			in:       ` [ ] `,
+			param:    &Parameter{Type: ArrayType, Value: []Parameter{}},
+			expected: stackitem.NewArray([]stackitem.Item{}),
+		},
+		{
+			in: `[1, "a", [true, null]]`,
+			expected: stackitem.NewArray([]stackitem.Item{
+				stackitem.Make(1),
+				stackitem.Make("a"),
+				stackitem.NewArray([]stackitem.Item{stackitem.NewBool(true), stackitem.Null{}}),
+			}),
+		},
+		{
+			in: `{"b": 1, "a": {"c": [2]}}`,
+			param: &Parameter{Type: MapType, Value: []ParameterPair{
+				{Key: Parameter{Type: StringType, Value: "b"}, Value: Parameter{Type: IntegerType, Value: big.NewInt(1)}},
+				{Key: Parameter{Type: StringType, Value: "a"}, Value: Parameter{Type: MapType, Value: []ParameterPair{
+					{Key: Parameter{Type: StringType, Value: "c"}, Value: Parameter{Type: ArrayType, Value: []Parameter{
+						{Type: IntegerType, Value: big.NewInt(2)},
+					}}},
+				}}},
+			}},
+			expected: stackitem.NewMapWithValue([]stackitem.MapElement{
+				{Key: stackitem.Make("b"), Value: stackitem.Make(1)},
+				{Key: stackitem.Make("a"), Value: stackitem.NewMapWithValue([]stackitem.MapElement{
+					{Key: stackitem.Make("c"), Value: stackitem.NewArray([]stackitem.Item{stackitem.Make(2)})},
+				})},
+			}),
+		},
+		{
+			in:       `{}`,
+			param:    &Parameter{Type: MapType, Value: []ParameterPair{}},
+			expected: stackitem.NewMap(),
+		},
+		{
+			in:       `{"type": "Hash160", "value": "0x0000000000000000000000000000000000000001"}`,
+			param:    &Parameter{Type: Hash160Type, Value: util.Uint160{1}},
+			expected: stackitem.NewByteArray(util.Uint160{1}.BytesBE()),
+		},
+		{
+			in:       `{"type": "ByteArray", "value": "AQI="}`,
+			param:    &Parameter{Type: ByteArrayType, Value: []byte{1, 2}},
+			expected: stackitem.NewByteArray([]byte{1, 2}),
+		},
+		{
+			in:       `{"type": "String", "value": "hex:01"}`,
+			param:    &Parameter{Type: StringType, Value: "hex:01"},
+			expected: stackitem.Make("hex:01"),
+		},
+		{
+			in:       `{"type": "Any"}`,
+			param:    &Parameter{Type: AnyType},
+			expected: stackitem.Null{},
+		},
+		{
+			in: `{"type": "Array", "value": [{"type": "Integer", "value": "7"}, "hex:01", {"a": false}]}`,
+			expected: stackitem.NewArray([]stackitem.Item{
+				stackitem.Make(7),
+				stackitem.NewByteArray([]byte{1}),
+				stackitem.NewMapWithValue([]stackitem.MapElement{{Key: stackitem.Make("a"), Value: stackitem.NewBool(false)}}),
+			}),
+		},
+		{
+			in: `{"type": "Map", "value": [{"key": 1, "value": {"type": "Boolean", "value": true}}, {"key": {"type": "ByteArray", "value": "AQ=="}, "value": ["x"]}]}`,
+			expected: stackitem.NewMapWithValue([]stackitem.MapElement{
+				{Key: stackitem.Make(1), Value: stackitem.NewBool(true)},
+				{Key: stackitem.NewByteArray([]byte{1}), Value: stackitem.NewArray([]stackitem.Item{stackitem.Make("x")})},
+			}),
+		},
+		{
+			in: `{"type": "Map", "value": [{"key": "type", "value": "Map"}]}`,
+			expected: stackitem.NewMapWithValue([]stackitem.MapElement{
+				{Key: stackitem.Make("type"), Value: stackitem.Make("Map")},
+			}),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			p, err := NewParameterFromJSON([]byte(tc.in))
+			require.NoError(t, err)
+			if tc.param != nil {
+				require.Equal(t, *tc.param, p)
+			}
+			actual, err := p.ToStackItem()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+
+			// Parameter marshaled to the explicit form is parsed back.
+			data, err := p.MarshalJSON()
+			require.NoError(t, err)
+			p2, err := NewParameterFromJSON(data)
+			require.NoError(t, err)
+			actual, err = p2.ToStackItem()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	errCases := []string{
+		``,
+		`   `,
+		`1.5`,
+		`1e3`,
+		`nul`,
+		`"hex:xyz"`,
+		`"base64:!!!"`,
+		`{"a": 1, "a": 2}`,
+		`{"a": }`,
+		`[1, 2`,
+		`[1, "hex:zz"]`,
+		`{"a": [1.1]}`,
+		`{"type": "Unknown", "value": 1}`,
+		`{"type": "Integer", "value": "abc"}`,
+		`{"type": "Array", "value": 1}`,
+		`{"type": "Array", "value": ["hex:z"]}`,
+		`{"type": "Map", "value": {}}`,
+		`{"type": "Map", "value": [{"key": 1}]}`,
+		`{"type": "Map", "value": [{"key": "hex:z", "value": 1}]}`,
+		`{"type": "Map", "value": [{"key": 1, "value": 1.5}]}`,
+		`1` + strings.Repeat("0", 100),
+	}
+	for _, in := range errCases {
+		t.Run("bad "+in, func(t *testing.T) {
+			_, err := NewParameterFromJSON([]byte(in))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNewParameterFromJSONRoundTrip(t *testing.T) {
+	for _, tc := range marshalJSONTestCases {
+		if tc.input.Type == InteropInterfaceType {
+			continue
+		}
+		data, err := tc.input.MarshalJSON()
+		require.NoError(t, err)
+		p, err := NewParameterFromJSON(data)
+		require.NoError(t, err, string(data))
+
+		expected, err := tc.input.ToStackItem()
+		require.NoError(t, err)
+		actual, err := p.ToStackItem()
+		require.NoError(t, err)
+		require.Equal(t, expected, actual, string(data))
+	}
+}
+
+func TestParameterToStackItemMap(t *testing.T) {
+	t.Run("bad key", func(t *testing.T) {
+		p := Parameter{Type: MapType, Value: []ParameterPair{{
+			Key:   Parameter{Type: ArrayType, Value: []Parameter{}},
+			Value: Parameter{Type: IntegerType, Value: big.NewInt(1)},
+		}}}
+		_, err := p.ToStackItem()
+		require.Error(t, err)
+	})
+	t.Run("bad value", func(t *testing.T) {
+		p := Parameter{Type: MapType, Value: []ParameterPair{{
+			Key:   Parameter{Type: IntegerType, Value: big.NewInt(1)},
+			Value: Parameter{Type: InteropInterfaceType},
+		}}}
+		_, err := p.ToStackItem()
+		require.Error(t, err)
+	})
+	t.Run("nested in array", func(t *testing.T) {
+		p := Parameter{Type: ArrayType, Value: []Parameter{{Type: MapType}}}
+		item, err := p.ToStackItem()
+		require.NoError(t, err)
+		require.Equal(t, stackitem.NewArray([]stackitem.Item{stackitem.NewMap()}), item)
+	})
+}
+
+func TestNewParameterFromStringJSON(t *testing.T) {
+	p, err := NewParameterFromString(`json:{"a\\b": ["hex:01", 2]}`)
+	require.NoError(t, err)
+	item, err := p.ToStackItem()
+	require.NoError(t, err)
+	require.Equal(t, stackitem.NewMapWithValue([]stackitem.MapElement{{
+		Key:   stackitem.Make(`a\b`),
+		Value: stackitem.NewArray([]stackitem.Item{stackitem.NewByteArray([]byte{1}), stackitem.Make(2)}),
+	}}), item)
+
+	_, err = NewParameterFromString(`json:{`)
+	require.Error(t, err)
+}
@@ -207,6 +207,13 @@ func NewParameterFromString(in string) (*Parameter, error) {
 		res     = &Parameter{}
 		typStr  string
 	)
+	if strings.HasPrefix(in, jsonParamType+":") {
+		p, err := NewParameterFromJSON([]byte(in[len(jsonParamType)+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse '%s' parameter: %w", jsonParamType, err)
+		}
+		return &p, nil
+	}
 	r = strings.NewReader(in)
 	for char, _, err = r.ReadRune(); err == nil && char != utf8.RuneError; char, _, err = r.ReadRune() {
 		if char == '\\' && !escaped {
@@ -404,8 +411,40 @@ func ExpandParameterToEmitable(param Parameter) (interface{}, error) {
 	}
 }
 
-// ToStackItem converts smartcontract parameter to stackitem.Item.
+// ToStackItem converts smartcontract parameter to stackitem.Item. Unlike
+// ExpandParameterToEmitable it supports Map parameters (including nested ones).
 func (p *Parameter) ToStackItem() (stackitem.Item, error) {
+	switch p.Type {
+	case ArrayType:
+		arr, _ := p.Value.([]Parameter)
+		items := make([]stackitem.Item, len(arr))
+		for i := range arr {
+			var err error
+			items[i], err = arr[i].ToStackItem()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return stackitem.NewArray(items), nil
+	case MapType:
+		pairs, _ := p.Value.([]ParameterPair)
+		m := stackitem.NewMap()
+		for i := range pairs {
+			k, err := pairs[i].Key.ToStackItem()
+			if err != nil {
+				return nil, err
+			}
+			if err := stackitem.IsValidMapKey(k); err != nil {
+				return nil, fmt.Errorf("map key #%d: %w", i, err)
+			}
+			v, err := pairs[i].Value.ToStackItem()
+			if err != nil {
+				return nil, err
+			}
+			m.Add(k, v)
+		}
+		return m, nil
+	}
 	e, err := ExpandParameterToEmitable(*p)
 	if err != nil {
 		return nil, err
@@ -49,6 +49,32 @@ func BenchmarkScriptPushPop(t *testing.B) {
 	}
 }
 
+func BenchmarkGasStats(t *testing.B) {
+	const n = 1024
+	var script = make([]byte, n*2)
+	for p := 0; p < n; p++ {
+		script[p] = byte(opcode.PUSH1)
+		script[n+p] = byte(opcode.DROP)
+	}
+	for _, enabled := range []bool{false, true} {
+		t.Run("enabled="+strconv.FormatBool(enabled), func(t *testing.B) {
+			for i := 0; i < t.N; i++ {
+				t.StopTimer()
+				vm := load(script)
+				vm.SetPriceGetter(func(opcode.Opcode, []byte) int64 { return 1 })
+				if enabled {
+					vm.EnableGasStats()
+				}
+				t.StartTimer()
+				err := vm.Run()
+				t.StopTimer()
+				require.NoError(t, err)
+				t.StartTimer()
+			}
+		})
+	}
+}
+
 func BenchmarkIsSignatureContract(t *testing.B) {
 	b64script := "DCED2eixa9myLTNF1tTN4xvhw+HRYVMuPQzOy5Xs4utYM25BVuezJw=="
 	script, err := base64.StdEncoding.DecodeString(b64script)
@@ -23,6 +23,19 @@ func TestVM_Debug(t *testing.T) {
 		require.Equal(t, 1, v.estack.Len())
 		require.Equal(t, big.NewInt(5), v.estack.Top().Value())
 	})
+	t.Run("RemoveBreakPoint", func(t *testing.T) {
+		v := load(prog)
+		v.AddBreakPoint(3)
+		v.AddBreakPoint(5)
+		v.AddBreakPoint(5)
+		v.RemoveBreakPoint(3)
+		v.RemoveBreakPoint(5)
+		v.RemoveBreakPoint(6) // No-op.
+		require.NoError(t, v.Run())
+		require.Equal(t, 5, v.Context().NextIP())
+		require.NoError(t, v.Run())
+		require.True(t, v.HasHalted())
+	})
 	t.Run("StepInto", func(t *testing.T) {
 		v := load(prog)
 		require.NoError(t, v.StepInto())
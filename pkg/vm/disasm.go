@@ -0,0 +1,181 @@
+package vm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Instruction is a single disassembled VM instruction.
+type Instruction struct {
+	// Offset is the instruction offset in the script.
+	Offset int
+	// Opcode is the instruction opcode.
+	Opcode opcode.Opcode
+	// Operand is the raw instruction operand (without the length prefix
+	// for PUSHDATA*), it's nil if there is none.
+	Operand []byte
+	// Value is the decoded operand (if it can be decoded):
+	//   - *big.Int for PUSHINT*
+	//   - util.Uint160 for 20-byte PUSHDATA* (which are usually hashes)
+	//   - string for other PUSHDATA* with valid UTF-8 data
+	//   - uint32 interop ID for SYSCALL
+	//   - stackitem.Type for CONVERT, ISTYPE and NEWARRAY_T
+	//   - int slot or method token index for INITSSLOT, LD*/ST* and CALLT
+	Value interface{}
+	// Targets contains absolute offsets of jump, call and PUSHA targets.
+	// It has two elements for TRY (catch and finally blocks, an offset
+	// equal to the instruction one means there is no such block).
+	Targets []int
+}
+
+// nativeContracts contains names of native contracts by their hashes.
+var nativeContracts = func() map[util.Uint160]string {
+	names := []string{
+		nativenames.Management, nativenames.Ledger, nativenames.Neo,
+		nativenames.Gas, nativenames.Policy, nativenames.Oracle,
+		nativenames.Designation, nativenames.Notary, nativenames.CryptoLib,
+		nativenames.StdLib,
+	}
+	m := make(map[util.Uint160]string, len(names))
+	for _, name := range names {
+		m[state.CreateNativeContractHash(name)] = name
+	}
+	return m
+}()
+
+// InteropName returns the name of the interop with the given ID or its
+// hex-encoded ID if it's unknown.
+func InteropName(id uint32) string {
+	name, err := interopnames.FromID(id)
+	if err != nil {
+		return fmt.Sprintf("%08x", id)
+	}
+	return name
+}
+
+// Disassemble parses the given script into a list of instructions. If the
+// script is invalid, instructions parsed so far are returned along with an
+// error.
+func Disassemble(script []byte) ([]Instruction, error) {
+	var (
+		res []Instruction
+		ctx = NewContext(script)
+	)
+	for ctx.nextip < len(script) {
+		op, param, err := ctx.Next()
+		if err != nil {
+			return res, newError(ctx.ip, op, err)
+		}
+		instr := Instruction{
+			Offset:  ctx.ip,
+			Opcode:  op,
+			Operand: param,
+		}
+		if err := instr.decode(ctx); err != nil {
+			return res, newError(ctx.ip, op, err)
+		}
+		res = append(res, instr)
+	}
+	return res, nil
+}
+
+// decode fills instruction value and targets.
+func (i *Instruction) decode(ctx *Context) error {
+	p := i.Operand
+	switch i.Opcode {
+	case opcode.JMP, opcode.JMPIF, opcode.JMPIFNOT, opcode.CALL,
+		opcode.JMPEQ, opcode.JMPNE,
+		opcode.JMPGT, opcode.JMPGE, opcode.JMPLE, opcode.JMPLT,
+		opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.CALLL,
+		opcode.JMPEQL, opcode.JMPNEL,
+		opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL,
+		opcode.PUSHA, opcode.ENDTRY, opcode.ENDTRYL:
+		offset, _, err := calcJumpOffset(ctx, p)
+		if err != nil {
+			return err
+		}
+		i.Targets = []int{offset}
+	case opcode.TRY, opcode.TRYL:
+		catchP, finallyP := getTryParams(i.Opcode, p)
+		for _, tp := range [][]byte{catchP, finallyP} {
+			offset, _, err := calcJumpOffset(ctx, tp)
+			if err != nil {
+				return err
+			}
+			i.Targets = append(i.Targets, offset)
+		}
+	case opcode.PUSHINT8, opcode.PUSHINT16, opcode.PUSHINT32,
+		opcode.PUSHINT64, opcode.PUSHINT128, opcode.PUSHINT256:
+		i.Value = bigint.FromBytes(p)
+	case opcode.PUSHDATA1, opcode.PUSHDATA2, opcode.PUSHDATA4:
+		if len(p) == util.Uint160Size {
+			i.Value, _ = util.Uint160DecodeBytesBE(p)
+		} else if utf8.Valid(p) {
+			i.Value = string(p)
+		}
+	case opcode.SYSCALL:
+		i.Value = GetInteropID(p)
+	case opcode.CONVERT, opcode.ISTYPE, opcode.NEWARRAYT:
+		i.Value = stackitem.Type(p[0])
+	case opcode.INITSSLOT, opcode.LDLOC, opcode.STLOC, opcode.LDARG, opcode.STARG, opcode.LDSFLD, opcode.STSFLD:
+		i.Value = int(p[0])
+	case opcode.CALLT:
+		i.Value = int(binary.LittleEndian.Uint16(p))
+	}
+	return nil
+}
+
+// Description returns a human-readable description of the instruction
+// operand: decoded values, jump targets, syscall names and native contract
+// names for their hashes. It's empty for instructions without operands.
+func (i Instruction) Description() string {
+	if i.Operand == nil {
+		return ""
+	}
+	p := i.Operand
+	switch i.Opcode {
+	case opcode.TRY, opcode.TRYL:
+		catchP, finallyP := getTryParams(i.Opcode, p)
+		return fmt.Sprintf("catch %s, finally %s",
+			offsetDesc(i.Offset, i.Targets[0], catchP), offsetDesc(i.Offset, i.Targets[1], finallyP))
+	case opcode.INITSSLOT:
+		return fmt.Sprint(p[0])
+	case opcode.CONVERT, opcode.ISTYPE, opcode.NEWARRAYT:
+		return fmt.Sprintf("%s (%x)", i.Value, p)
+	case opcode.INITSLOT:
+		return fmt.Sprintf("%d local, %d arg", p[0], p[1])
+	case opcode.SYSCALL:
+		return fmt.Sprintf("%s (%x)", InteropName(i.Value.(uint32)), p)
+	case opcode.PUSHINT8, opcode.PUSHINT16, opcode.PUSHINT32,
+		opcode.PUSHINT64, opcode.PUSHINT128, opcode.PUSHINT256:
+		return fmt.Sprintf("%d (%x)", i.Value.(*big.Int), p)
+	case opcode.LDLOC, opcode.STLOC, opcode.LDARG, opcode.STARG, opcode.LDSFLD, opcode.STSFLD, opcode.CALLT:
+		return fmt.Sprintf("%d (%x)", i.Value, p)
+	}
+	if len(i.Targets) == 1 {
+		return offsetDesc(i.Offset, i.Targets[0], p)
+	}
+	if h, ok := i.Value.(util.Uint160); ok {
+		if name, ok := nativeContracts[h]; ok {
+			return fmt.Sprintf("%x (%s)", p, name)
+		}
+	}
+	if utf8.Valid(p) {
+		return fmt.Sprintf("%x (%q)", p, p)
+	}
+	return fmt.Sprintf("%x", p)
+}
+
+func offsetDesc(offset int, target int, parameter []byte) string {
+	return fmt.Sprintf("%d (%d/%x)", target, target-offset, parameter)
+}
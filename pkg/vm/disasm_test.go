@@ -0,0 +1,87 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisassemble(t *testing.T) {
+	neoHash := state.CreateNativeContractHash(nativenames.Neo)
+	otherHash := util.Uint160{0xff, 1, 2}
+
+	w := io.NewBufBinWriter()
+	emit.Int(w.BinWriter, 1000)                                                     // 0
+	emit.String(w.BinWriter, "abc")                                                 // 3
+	emit.Bytes(w.BinWriter, neoHash.BytesBE())                                      // 8
+	emit.Bytes(w.BinWriter, otherHash.BytesBE())                                    // 30
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLog)                        // 52
+	emit.Instruction(w.BinWriter, opcode.JMP, []byte{4})                            // 57
+	emit.Instruction(w.BinWriter, opcode.TRY, []byte{3, 0})                         // 59
+	emit.Instruction(w.BinWriter, opcode.CONVERT, []byte{byte(stackitem.BooleanT)}) // 62
+	emit.Instruction(w.BinWriter, opcode.LDLOC, []byte{2})                          // 64
+	emit.Instruction(w.BinWriter, opcode.CALLT, []byte{1, 0})                       // 66
+	emit.Opcodes(w.BinWriter, opcode.RET)                                           // 69
+	require.NoError(t, w.Err)
+
+	instrs, err := Disassemble(w.Bytes())
+	require.NoError(t, err)
+
+	expected := []struct {
+		offset  int
+		op      opcode.Opcode
+		value   interface{}
+		targets []int
+		desc    string
+	}{
+		{0, opcode.PUSHINT16, big.NewInt(1000), nil, "1000 (e803)"},
+		{3, opcode.PUSHDATA1, "abc", nil, `616263 ("abc")`},
+		{8, opcode.PUSHDATA1, neoHash, nil, neoHash.StringBE() + " (" + nativenames.Neo + ")"},
+		{30, opcode.PUSHDATA1, otherHash, nil, otherHash.StringBE()},
+		{52, opcode.SYSCALL, interopnames.ToID([]byte(interopnames.SystemRuntimeLog)), nil, interopnames.SystemRuntimeLog + " (cfe74796)"},
+		{57, opcode.JMP, nil, []int{61}, "61 (4/04)"},
+		{59, opcode.TRY, nil, []int{62, 59}, "catch 62 (3/03), finally 59 (0/00)"},
+		{62, opcode.CONVERT, stackitem.BooleanT, nil, "Boolean (20)"},
+		{64, opcode.LDLOC, 2, nil, "2 (02)"},
+		{66, opcode.CALLT, 1, nil, "1 (0100)"},
+		{69, opcode.RET, nil, nil, ""},
+	}
+	require.Equal(t, len(expected), len(instrs))
+	for i, e := range expected {
+		require.Equal(t, e.offset, instrs[i].Offset, i)
+		require.Equal(t, e.op, instrs[i].Opcode, i)
+		require.Equal(t, e.value, instrs[i].Value, i)
+		require.Equal(t, e.targets, instrs[i].Targets, i)
+		require.Equal(t, e.desc, instrs[i].Description(), i)
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		testCases := map[string][]byte{
+			"truncated operand": {byte(opcode.PUSH1), byte(opcode.PUSHDATA1), 10, 1},
+			"bad opcode":        {byte(opcode.PUSH1), 0xFF},
+			"bad jump target":   {byte(opcode.PUSH1), byte(opcode.JMP), 100},
+		}
+		for name, script := range testCases {
+			t.Run(name, func(t *testing.T) {
+				instrs, err := Disassemble(script)
+				require.Error(t, err)
+				require.Equal(t, 1, len(instrs))
+				require.Equal(t, opcode.PUSH1, instrs[0].Opcode)
+			})
+		}
+	})
+}
+
+func TestInteropName(t *testing.T) {
+	require.Equal(t, interopnames.SystemRuntimeLog, InteropName(interopnames.ToID([]byte(interopnames.SystemRuntimeLog))))
+	require.Equal(t, "01020304", InteropName(0x01020304))
+}
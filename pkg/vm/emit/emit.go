@@ -105,61 +105,70 @@ func Array(w *io.BinWriter, es ...interface{}) {
 		return
 	}
 	for i := len(es) - 1; i >= 0; i-- {
-		switch e := es[i].(type) {
-		case []interface{}:
-			Array(w, e...)
-		case int64:
-			Int(w, e)
-		case int32:
-			Int(w, int64(e))
-		case uint32:
-			Int(w, int64(e))
-		case int16:
-			Int(w, int64(e))
-		case uint16:
-			Int(w, int64(e))
-		case int8:
-			Int(w, int64(e))
-		case uint8:
-			Int(w, int64(e))
-		case int:
-			Int(w, int64(e))
-		case *big.Int:
-			BigInt(w, e)
-		case string:
-			String(w, e)
-		case util.Uint160:
-			Bytes(w, e.BytesBE())
-		case util.Uint256:
-			Bytes(w, e.BytesBE())
-		case *util.Uint160:
-			if e == nil {
-				Opcodes(w, opcode.PUSHNULL)
-			} else {
-				Bytes(w, e.BytesBE())
-			}
-		case *util.Uint256:
-			if e == nil {
-				Opcodes(w, opcode.PUSHNULL)
-			} else {
-				Bytes(w, e.BytesBE())
-			}
-		case []byte:
-			Bytes(w, e)
-		case bool:
-			Bool(w, e)
-		default:
-			if es[i] != nil {
-				w.Err = fmt.Errorf("unsupported type: %T", e)
-				return
-			}
-			Opcodes(w, opcode.PUSHNULL)
+		Any(w, es[i])
+		if w.Err != nil {
+			return
 		}
 	}
 	Int(w, int64(len(es)))
 	Opcodes(w, opcode.PACK)
 }
 
+// Any emits a single element of any type supported by Array to the given
+// buffer, an error is set on w for unsupported types.
+func Any(w *io.BinWriter, e interface{}) {
+	switch e := e.(type) {
+	case []interface{}:
+		Array(w, e...)
+	case int64:
+		Int(w, e)
+	case int32:
+		Int(w, int64(e))
+	case uint32:
+		Int(w, int64(e))
+	case int16:
+		Int(w, int64(e))
+	case uint16:
+		Int(w, int64(e))
+	case int8:
+		Int(w, int64(e))
+	case uint8:
+		Int(w, int64(e))
+	case int:
+		Int(w, int64(e))
+	case *big.Int:
+		BigInt(w, e)
+	case string:
+		String(w, e)
+	case util.Uint160:
+		Bytes(w, e.BytesBE())
+	case util.Uint256:
+		Bytes(w, e.BytesBE())
+	case *util.Uint160:
+		if e == nil {
+			Opcodes(w, opcode.PUSHNULL)
+		} else {
+			Bytes(w, e.BytesBE())
+		}
+	case *util.Uint256:
+		if e == nil {
+			Opcodes(w, opcode.PUSHNULL)
+		} else {
+			Bytes(w, e.BytesBE())
+		}
+	case []byte:
+		Bytes(w, e)
+	case bool:
+		Bool(w, e)
+	default:
+		if e != nil {
+			w.Err = fmt.Errorf("unsupported type: %T", e)
+			return
+		}
+		Opcodes(w, opcode.PUSHNULL)
+	}
+}
+
 // String emits a string to the given buffer.
 func String(w *io.BinWriter, s string) {
 	Bytes(w, []byte(s))
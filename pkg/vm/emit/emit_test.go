@@ -1,6 +1,7 @@
 package emit
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"math"
@@ -273,6 +274,42 @@ func TestEmitArray(t *testing.T) {
 		Array(buf.BinWriter, struct{}{})
 		require.Error(t, buf.Err)
 	})
+
+	t.Run("invalid nested type", func(t *testing.T) {
+		var b bytes.Buffer
+		w := io.NewBinWriterFromIO(&b)
+		Array(w, int64(1), []interface{}{struct{}{}}, int64(2))
+		require.Error(t, w.Err)
+		// Elements are emitted in reverse order, nothing is written after the error.
+		require.Equal(t, []byte{byte(opcode.PUSH2)}, b.Bytes())
+	})
+}
+
+func TestEmitAny(t *testing.T) {
+	for _, e := range []interface{}{
+		nil, int64(1), int32(-1), uint32(1), int16(1), uint16(1), int8(1), uint8(1), 1,
+		big.NewInt(1), "str", util.Uint160{1}, util.Uint256{1}, (*util.Uint160)(nil),
+		(*util.Uint256)(nil), &util.Uint160{1}, &util.Uint256{1}, []byte{1}, true,
+		[]interface{}{int64(1), "str"},
+	} {
+		expected := io.NewBufBinWriter()
+		Array(expected.BinWriter, e)
+		require.NoError(t, expected.Err)
+
+		buf := io.NewBufBinWriter()
+		Any(buf.BinWriter, e)
+		require.NoError(t, buf.Err)
+		// Array(e) is Any(e) followed by PUSH1 PACK.
+		require.Equal(t, expected.Bytes(), append(buf.Bytes(), byte(opcode.PUSH1), byte(opcode.PACK)), "%T", e)
+	}
+
+	t.Run("invalid type", func(t *testing.T) {
+		var b bytes.Buffer
+		w := io.NewBinWriterFromIO(&b)
+		Any(w, struct{}{})
+		require.Error(t, w.Err)
+		require.Equal(t, 0, b.Len())
+	})
 }
 
 func TestEmitBool(t *testing.T) {
@@ -0,0 +1,67 @@
+package vm
+
+import (
+	"errors"
+
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+var (
+	// ErrStackOverflow is returned when the number of items on all stacks
+	// exceeds Limits.MaxStackSize.
+	ErrStackOverflow = errors.New("stack is too big")
+	// ErrItemTooBig is returned when an item exceeds Limits.MaxItemSize or
+	// other size constraints. It's the same as stackitem.ErrTooBig, so
+	// errors returned from stack item conversions and serialization match
+	// it as well.
+	ErrItemTooBig = stackitem.ErrTooBig
+	// ErrTooDeep is returned when an item exceeds Limits.MaxNestingDepth.
+	// It's the same as stackitem.ErrTooDeep.
+	ErrTooDeep = stackitem.ErrTooDeep
+)
+
+// Limits are VM execution limits. Default (protocol) limits are used by the
+// chain and can't be changed there without breaking compatibility with other
+// nodes, custom ones are intended for VM embedders.
+type Limits struct {
+	// MaxStackSize is the maximum number of items allowed to be on all
+	// stacks at once (including items nested into compound ones).
+	MaxStackSize int
+	// MaxItemSize is the maximum size of a single byte string or buffer
+	// item (including serialized ones) in bytes. It can't be bigger than
+	// stackitem.MaxSize.
+	MaxItemSize int
+	// MaxNestingDepth is the maximum nesting level of arrays and maps
+	// deserialized from JSON.
+	MaxNestingDepth int
+}
+
+// DefaultLimits returns protocol VM limits.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxStackSize:    MaxStackSize,
+		MaxItemSize:     stackitem.MaxSize,
+		MaxNestingDepth: stackitem.MaxJSONDepth,
+	}
+}
+
+// SetLimits sets VM execution limits, zero (or negative) values are replaced
+// with the default ones and MaxItemSize is capped at stackitem.MaxSize.
+func (v *VM) SetLimits(l Limits) {
+	def := DefaultLimits()
+	if l.MaxStackSize <= 0 {
+		l.MaxStackSize = def.MaxStackSize
+	}
+	if l.MaxItemSize <= 0 || l.MaxItemSize > def.MaxItemSize {
+		l.MaxItemSize = def.MaxItemSize
+	}
+	if l.MaxNestingDepth <= 0 {
+		l.MaxNestingDepth = def.MaxNestingDepth
+	}
+	v.limits = l
+}
+
+// Limits returns VM execution limits.
+func (v *VM) Limits() Limits {
+	return v.limits
+}
@@ -0,0 +1,156 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVM_SetLimits(t *testing.T) {
+	v := New()
+	require.Equal(t, DefaultLimits(), v.Limits())
+
+	v.SetLimits(Limits{MaxStackSize: 10, MaxItemSize: stackitem.MaxSize + 1})
+	require.Equal(t, Limits{
+		MaxStackSize:    10,
+		MaxItemSize:     stackitem.MaxSize,
+		MaxNestingDepth: stackitem.MaxJSONDepth,
+	}, v.Limits())
+
+	v.Reset(trigger.Application)
+	require.Equal(t, DefaultLimits(), v.Limits())
+}
+
+func TestVM_Limits(t *testing.T) {
+	const (
+		maxStackSize = 16
+		maxItemSize  = 32
+	)
+	testCases := []struct {
+		name string
+		// limit is the maximum script parameter that doesn't exceed the
+		// limits, limit+1 is expected to fail with err.
+		limit  int
+		err    error
+		script func(w *io.BinWriter, n int)
+		// prepare is used to push items that can't be created by the
+		// script itself.
+		prepare func(v *VM, n int)
+	}{
+		{
+			name:  "PUSHDATA",
+			limit: maxItemSize,
+			err:   ErrItemTooBig,
+			script: func(w *io.BinWriter, n int) {
+				emit.Bytes(w, make([]byte, n))
+			},
+		},
+		{
+			name:  "NEWBUFFER",
+			limit: maxItemSize,
+			err:   ErrItemTooBig,
+			script: func(w *io.BinWriter, n int) {
+				emit.Int(w, int64(n))
+				emit.Opcodes(w, opcode.NEWBUFFER)
+			},
+		},
+		{
+			name:  "CAT",
+			limit: maxItemSize,
+			err:   ErrItemTooBig,
+			script: func(w *io.BinWriter, n int) {
+				emit.Bytes(w, make([]byte, n/2))
+				emit.Bytes(w, make([]byte, n-n/2))
+				emit.Opcodes(w, opcode.CAT)
+			},
+		},
+		{
+			name:  "CONVERT",
+			limit: maxItemSize,
+			err:   ErrItemTooBig,
+			script: func(w *io.BinWriter, _ int) {
+				emit.Instruction(w, opcode.CONVERT, []byte{byte(stackitem.ByteArrayT)})
+			},
+			prepare: func(v *VM, n int) {
+				v.Estack().PushVal(stackitem.NewBuffer(make([]byte, n)))
+			},
+		},
+		{
+			// Array with all of its elements.
+			name:  "NEWARRAY",
+			limit: maxStackSize - 1,
+			err:   ErrStackOverflow,
+			script: func(w *io.BinWriter, n int) {
+				emit.Int(w, int64(n))
+				emit.Opcodes(w, opcode.NEWARRAY)
+			},
+		},
+		{
+			// Elements and the number of them.
+			name:  "PACK",
+			limit: maxStackSize - 1,
+			err:   ErrStackOverflow,
+			script: func(w *io.BinWriter, n int) {
+				for i := 0; i < n; i++ {
+					emit.Opcodes(w, opcode.PUSH1)
+				}
+				emit.Int(w, int64(n))
+				emit.Opcodes(w, opcode.PACK)
+			},
+		},
+		{
+			// Array with elements, elements and the number of them.
+			name:  "UNPACK",
+			limit: maxStackSize/2 - 1,
+			err:   ErrStackOverflow,
+			script: func(w *io.BinWriter, n int) {
+				emit.Int(w, int64(n))
+				emit.Opcodes(w, opcode.NEWARRAY, opcode.DUP, opcode.UNPACK)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		run := func(t *testing.T, n int) error {
+			w := io.NewBufBinWriter()
+			tc.script(w.BinWriter, n)
+			require.NoError(t, w.Err)
+			v := load(w.Bytes())
+			v.SetLimits(Limits{MaxStackSize: maxStackSize, MaxItemSize: maxItemSize})
+			if tc.prepare != nil {
+				tc.prepare(v, n)
+			}
+			return v.Run()
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("at limit", func(t *testing.T) {
+				require.NoError(t, run(t, tc.limit))
+			})
+			t.Run("over limit", func(t *testing.T) {
+				require.ErrorIs(t, run(t, tc.limit+1), tc.err)
+			})
+		})
+	}
+}
+
+func TestVM_DefaultLimitsErrors(t *testing.T) {
+	t.Run("stack", func(t *testing.T) {
+		prog := make([]byte, MaxStackSize+1)
+		for i := range prog {
+			prog[i] = byte(opcode.PUSH1)
+		}
+		v := load(prog)
+		require.ErrorIs(t, v.Run(), ErrStackOverflow)
+	})
+	t.Run("item", func(t *testing.T) {
+		w := io.NewBufBinWriter()
+		emit.Int(w.BinWriter, stackitem.MaxSize+1)
+		emit.Opcodes(w.BinWriter, opcode.NEWBUFFER)
+		v := load(w.Bytes())
+		require.ErrorIs(t, v.Run(), ErrItemTooBig)
+	})
+}
@@ -16,8 +16,9 @@ import (
 type decoder struct {
 	json.Decoder
 
-	count int
-	depth int
+	count    int
+	depth    int
+	maxDepth int
 }
 
 // MaxAllowedInteger is the maximum integer allowed to be encoded.
@@ -30,8 +31,8 @@ const MaxJSONDepth = 10
 // during serialization or deserialization.
 var ErrInvalidValue = errors.New("invalid value")
 
-// ErrTooDeep is returned when JSON encoder/decoder goes beyond MaxJSONDepth in
-// its processing.
+// ErrTooDeep is returned when JSON decoder goes beyond MaxJSONDepth (or the
+// depth limit given to FromJSONWithLimits) in its processing.
 var ErrTooDeep = errors.New("too deep")
 
 // ToJSON encodes Item to JSON.
@@ -44,8 +45,18 @@ var ErrTooDeep = errors.New("too deep")
 //	Array, Struct -> array
 //	Map -> map with keys as UTF-8 bytes
 func ToJSON(item Item) ([]byte, error) {
+	return ToJSONWithLimit(item, MaxSize)
+}
+
+// ToJSONWithLimit is the same as ToJSON, but allows to set the maximum size of
+// the resulting JSON (ErrTooBig is returned if it's exceeded). The limit can't
+// be bigger than MaxSize.
+func ToJSONWithLimit(item Item, maxSize int) ([]byte, error) {
+	if maxSize > MaxSize {
+		maxSize = MaxSize
+	}
 	seen := make(map[Item]sliceNoPointer, typicalNumOfItems)
-	return toJSON(nil, seen, item)
+	return toJSON(nil, seen, item, maxSize)
 }
 
 // sliceNoPointer represents a sub-slice of a known slice.
@@ -54,13 +65,13 @@ type sliceNoPointer struct {
 	start, end int
 }
 
-func toJSON(data []byte, seen map[Item]sliceNoPointer, item Item) ([]byte, error) {
-	if len(data) > MaxSize {
+func toJSON(data []byte, seen map[Item]sliceNoPointer, item Item, maxSize int) ([]byte, error) {
+	if len(data) > maxSize {
 		return nil, errTooBigSize
 	}
 
 	if old, ok := seen[item]; ok {
-		if len(data)+old.end-old.start > MaxSize {
+		if len(data)+old.end-old.start > maxSize {
 			return nil, errTooBigSize
 		}
 		return append(data, data[old.start:old.end]...), nil
@@ -80,7 +91,7 @@ func toJSON(data []byte, seen map[Item]sliceNoPointer, item Item) ([]byte, error
 
 		data = append(data, '[')
 		for i, v := range items {
-			data, err = toJSON(data, seen, v)
+			data, err = toJSON(data, seen, v, maxSize)
 			if err != nil {
 				return nil, err
 			}
@@ -101,7 +112,7 @@ func toJSON(data []byte, seen map[Item]sliceNoPointer, item Item) ([]byte, error
 			}
 			data = append(data, raw...)
 			data = append(data, ':')
-			data, err = toJSON(data, seen, it.value[i].Value)
+			data, err = toJSON(data, seen, it.value[i].Value, maxSize)
 			if err != nil {
 				return nil, err
 			}
@@ -133,7 +144,7 @@ func toJSON(data []byte, seen map[Item]sliceNoPointer, item Item) ([]byte, error
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnserializable, it.String())
 	}
-	if len(data) > MaxSize {
+	if len(data) > maxSize {
 		return nil, errTooBigSize
 	}
 	return data, nil
@@ -162,9 +173,16 @@ func itemToJSONString(it Item) ([]byte, error) {
 //	array -> Array
 //	map -> Map, keys are UTF-8
 func FromJSON(data []byte, maxCount int) (Item, error) {
+	return FromJSONWithLimits(data, maxCount, MaxJSONDepth)
+}
+
+// FromJSONWithLimits is the same as FromJSON, but allows to set the maximum
+// nesting level of arrays and maps (ErrTooDeep is returned if it's exceeded).
+func FromJSONWithLimits(data []byte, maxCount int, maxDepth int) (Item, error) {
 	d := decoder{
-		Decoder: *json.NewDecoder(bytes.NewReader(data)),
-		count:   maxCount,
+		Decoder:  *json.NewDecoder(bytes.NewReader(data)),
+		count:    maxCount,
+		maxDepth: maxDepth,
 	}
 	d.UseNumber()
 	if item, err := d.decode(); err != nil {
@@ -191,7 +209,7 @@ func (d *decoder) decode() (Item, error) {
 	case json.Delim:
 		switch t {
 		case json.Delim('{'), json.Delim('['):
-			if d.depth == MaxJSONDepth {
+			if d.depth >= d.maxDepth {
 				return nil, ErrTooDeep
 			}
 			d.depth++
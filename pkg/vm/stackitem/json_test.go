@@ -159,6 +159,21 @@ func TestToJSONCornerCases(t *testing.T) {
 	})
 }
 
+func TestToJSONWithLimit(t *testing.T) {
+	item := NewArray([]Item{Make(1), Make(2)}) // [1,2]
+	_, err := ToJSONWithLimit(item, 5)
+	require.NoError(t, err)
+	_, err = ToJSONWithLimit(item, 4)
+	require.ErrorIs(t, err, ErrTooBig)
+}
+
+func TestFromJSONWithLimits(t *testing.T) {
+	_, err := FromJSONWithLimits([]byte("[[1]]"), 10, 2)
+	require.NoError(t, err)
+	_, err = FromJSONWithLimits([]byte("[{\"a\":[1]}]"), 10, 2)
+	require.ErrorIs(t, err, ErrTooDeep)
+}
+
 // getBigArray returns array takes up a lot of storage when serialized.
 func getBigArray(depth int) *Array {
 	arr := NewArray([]Item{})
@@ -0,0 +1,147 @@
+/*
+Package trace provides VM execution tracing structures that can be used to
+find out what exactly happened during script execution.
+*/
+package trace
+
+import (
+	"encoding/json"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// Step is a single executed VM instruction.
+type Step struct {
+	// ScriptHash is the hash of the script being executed.
+	ScriptHash util.Uint160
+	// IP is the instruction offset in this script.
+	IP int
+	// Opcode is the instruction opcode.
+	Opcode opcode.Opcode
+	// StackDepth is the evaluation stack depth before instruction execution.
+	StackDepth int
+	// GasLeft is the amount of GAS left before instruction execution, it's
+	// -1 if there is no limit.
+	GasLeft int64
+	// Syscall is the name of the interop invoked by SYSCALL instruction.
+	Syscall string
+	// Error is the error returned from the interop (if any).
+	Error string
+}
+
+// Trace is a VM execution trace.
+type Trace struct {
+	Steps []Step `json:"steps"`
+	// Truncated is set if the number of executed instructions exceeds
+	// the trace limit, only the first ones are traced then.
+	Truncated bool `json:"truncated"`
+}
+
+type stepAux struct {
+	ScriptHash util.Uint160 `json:"contract"`
+	IP         int          `json:"ip"`
+	Opcode     string       `json:"opcode"`
+	StackDepth int          `json:"stackdepth"`
+	GasLeft    int64        `json:"gasleft,string"`
+	Syscall    string       `json:"syscall,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s Step) MarshalJSON() ([]byte, error) {
+	return json.Marshal(stepAux{
+		ScriptHash: s.ScriptHash,
+		IP:         s.IP,
+		Opcode:     s.Opcode.String(),
+		StackDepth: s.StackDepth,
+		GasLeft:    s.GasLeft,
+		Syscall:    s.Syscall,
+		Error:      s.Error,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *Step) UnmarshalJSON(data []byte) error {
+	var aux stepAux
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	op, err := opcode.FromString(aux.Opcode)
+	if err != nil {
+		return err
+	}
+	*s = Step{
+		ScriptHash: aux.ScriptHash,
+		IP:         aux.IP,
+		Opcode:     op,
+		StackDepth: aux.StackDepth,
+		GasLeft:    aux.GasLeft,
+		Syscall:    aux.Syscall,
+		Error:      aux.Error,
+	}
+	return nil
+}
+
+// Collector is a VM tracer that collects a limited number of execution
+// steps.
+type Collector struct {
+	trace Trace
+	max   int
+	// syscalls contains indexes of steps with syscalls being executed
+	// (-1 for those not traced), they can be nested for native contracts
+	// calling other contracts.
+	syscalls []int
+}
+
+// NewCollector returns a new Collector that traces at most max instructions.
+func NewCollector(max int) *Collector {
+	return &Collector{
+		trace: Trace{Steps: make([]Step, 0)},
+		max:   max,
+	}
+}
+
+// Trace returns collected execution trace.
+func (c *Collector) Trace() *Trace {
+	return &c.trace
+}
+
+// OnStep implements the vm.Tracer interface.
+func (c *Collector) OnStep(scriptHash util.Uint160, ip int, op opcode.Opcode, stackDepth int, gasLeft int64) {
+	if len(c.trace.Steps) >= c.max {
+		c.trace.Truncated = true
+		return
+	}
+	c.trace.Steps = append(c.trace.Steps, Step{
+		ScriptHash: scriptHash,
+		IP:         ip,
+		Opcode:     op,
+		StackDepth: stackDepth,
+		GasLeft:    gasLeft,
+	})
+}
+
+// OnSyscallEnter implements the vm.Tracer interface.
+func (c *Collector) OnSyscallEnter(id uint32) {
+	if c.trace.Truncated || len(c.trace.Steps) == 0 {
+		c.syscalls = append(c.syscalls, -1)
+		return
+	}
+	i := len(c.trace.Steps) - 1
+	c.trace.Steps[i].Syscall = vm.InteropName(id)
+	c.syscalls = append(c.syscalls, i)
+}
+
+// OnSyscallExit implements the vm.Tracer interface.
+func (c *Collector) OnSyscallExit(id uint32, err error) {
+	if len(c.syscalls) == 0 {
+		return
+	}
+	i := c.syscalls[len(c.syscalls)-1]
+	c.syscalls = c.syscalls[:len(c.syscalls)-1]
+	if err != nil && i >= 0 {
+		c.trace.Steps[i].Error = err.Error()
+	}
+}
@@ -0,0 +1,53 @@
+package trace
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+	getTimeID := interopnames.ToID([]byte(interopnames.SystemRuntimeGetTime))
+	c := NewCollector(3)
+	c.OnStep(h, 0, opcode.PUSH1, 0, 100)
+	c.OnStep(h, 1, opcode.SYSCALL, 1, 90)
+	c.OnSyscallEnter(getTimeID)
+	c.OnSyscallExit(getTimeID, errors.New("bad"))
+	c.OnStep(h, 6, opcode.SYSCALL, 2, 80)
+	c.OnSyscallEnter(0x01020304)
+	c.OnStep(h, 11, opcode.RET, 3, 70) // Truncated.
+	c.OnSyscallEnter(getTimeID)        // Not traced.
+	c.OnSyscallExit(getTimeID, errors.New("ignored"))
+	c.OnSyscallExit(0x01020304, nil)
+	c.OnSyscallExit(0x01020304, nil) // Unbalanced.
+
+	require.Equal(t, &Trace{
+		Steps: []Step{
+			{ScriptHash: h, IP: 0, Opcode: opcode.PUSH1, StackDepth: 0, GasLeft: 100},
+			{ScriptHash: h, IP: 1, Opcode: opcode.SYSCALL, StackDepth: 1, GasLeft: 90, Syscall: interopnames.SystemRuntimeGetTime, Error: "bad"},
+			{ScriptHash: h, IP: 6, Opcode: opcode.SYSCALL, StackDepth: 2, GasLeft: 80, Syscall: "01020304"},
+		},
+		Truncated: true,
+	}, c.Trace())
+}
+
+func TestTraceSerialization(t *testing.T) {
+	tr := &Trace{
+		Steps: []Step{
+			{ScriptHash: util.Uint160{1, 2, 3}, IP: 0, Opcode: opcode.PUSH1, StackDepth: 0, GasLeft: -1},
+			{ScriptHash: util.Uint160{1, 2, 3}, IP: 1, Opcode: opcode.SYSCALL, StackDepth: 1, GasLeft: -1, Syscall: "System.Runtime.GetTime", Error: "bad"},
+		},
+		Truncated: true,
+	}
+	testserdes.MarshalUnmarshalJSON(t, tr, new(Trace))
+
+	var s Step
+	require.Error(t, s.UnmarshalJSON([]byte(`{"opcode":"BAD"}`)))
+	require.Error(t, s.UnmarshalJSON([]byte(`[]`)))
+}
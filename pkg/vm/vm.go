@@ -11,9 +11,8 @@ import (
 	"math/big"
 	"os"
 	"text/tabwriter"
-	"unicode/utf8"
 
-	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
@@ -37,6 +36,13 @@ func (e *errorAtInstruct) Error() string {
 	return fmt.Sprintf("at instruction %d (%s): %s", e.ip, e.op, e.err)
 }
 
+// Unwrap returns the underlying error (if it's an error), so that errors.Is
+// and errors.As can be used with VM errors.
+func (e *errorAtInstruct) Unwrap() error {
+	err, _ := e.err.(error)
+	return err
+}
+
 func newError(ip int, op opcode.Opcode, err interface{}) *errorAtInstruct {
 	return &errorAtInstruct{ip: ip, op: op, err: err}
 }
@@ -62,6 +68,37 @@ const (
 // SyscallHandler is a type for syscall handler.
 type SyscallHandler = func(*VM, uint32) error
 
+// Tracer receives VM execution events, it can be set with SetTracer. Tracing
+// slows execution down, so it's intended to be used for debugging.
+type Tracer interface {
+	// OnStep is called before every instruction execution with the hash of
+	// the script being executed, instruction pointer, opcode, evaluation
+	// stack depth and the amount of GAS left (-1 if there is no limit).
+	OnStep(scriptHash util.Uint160, ip int, op opcode.Opcode, stackDepth int, gasLeft int64)
+	// OnSyscallEnter is called before syscall handler invocation.
+	OnSyscallEnter(id uint32)
+	// OnSyscallExit is called after syscall handler invocation with the
+	// error returned from it (if any).
+	OnSyscallExit(id uint32, err error)
+}
+
+// OnExecHook is a function that is called before every instruction execution
+// with the hash of the script being executed, instruction offset and opcode.
+// It's a lightweight alternative to Tracer intended for coverage collection.
+type OnExecHook func(scriptHash util.Uint160, offset int, op opcode.Opcode)
+
+// GasStats is a breakdown of GAS consumed by the VM, it's collected if
+// enabled with EnableGasStats.
+type GasStats struct {
+	// Opcodes contains GAS consumed by opcodes (indexed by opcode), it
+	// only includes opcode prices.
+	Opcodes [256]int64
+	// Syscalls contains GAS consumed by syscall handlers (by syscall ID),
+	// it includes interop prices as well as everything charged by the
+	// handler itself (like native contract method prices or storage fees).
+	Syscalls map[uint32]int64
+}
+
 // VM represents the virtual machine.
 type VM struct {
 	state vmstate.State
@@ -89,6 +126,18 @@ type VM struct {
 
 	// invTree is a top-level invocation tree (if enabled).
 	invTree *invocations.Tree
+
+	// tracer receives execution events (if set).
+	tracer Tracer
+
+	// onExecHook is called before every instruction (if set).
+	onExecHook OnExecHook
+
+	// gasStats is GAS consumption breakdown (if enabled).
+	gasStats *GasStats
+
+	// limits are execution limits.
+	limits Limits
 }
 
 var (
@@ -108,6 +157,7 @@ func NewWithTrigger(t trigger.Type) *VM {
 	vm := &VM{
 		state:   vmstate.None,
 		trigger: t,
+		limits:  DefaultLimits(),
 	}
 
 	initStack(&vm.istack, "invocation", nil)
@@ -138,6 +188,10 @@ func (v *VM) Reset(t trigger.Type) {
 	v.LoadToken = nil
 	v.trigger = t
 	v.invTree = nil
+	v.tracer = nil
+	v.onExecHook = nil
+	v.gasStats = nil
+	v.limits = DefaultLimits()
 }
 
 // GasConsumed returns the amount of GAS consumed during execution.
@@ -169,74 +223,21 @@ func (v *VM) PrintOps(out io.Writer) {
 	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
 	fmt.Fprintln(w, "INDEX\tOPCODE\tPARAMETER")
 	realctx := v.Context()
-	ctx := &Context{sc: realctx.sc}
-	for {
-		cursor := ""
-		instr, parameter, err := ctx.Next()
-		if ctx.ip == realctx.ip {
-			cursor = "\t<<"
-		}
-		if err != nil {
-			fmt.Fprintf(w, "%d\t%s\tERROR: %s%s\n", ctx.ip, instr, err, cursor)
-			break
-		}
-		var desc = ""
-		if parameter != nil {
-			switch instr {
-			case opcode.JMP, opcode.JMPIF, opcode.JMPIFNOT, opcode.CALL,
-				opcode.JMPEQ, opcode.JMPNE,
-				opcode.JMPGT, opcode.JMPGE, opcode.JMPLE, opcode.JMPLT,
-				opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.CALLL,
-				opcode.JMPEQL, opcode.JMPNEL,
-				opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL,
-				opcode.PUSHA, opcode.ENDTRY, opcode.ENDTRYL:
-				desc = getOffsetDesc(ctx, parameter)
-			case opcode.TRY, opcode.TRYL:
-				catchP, finallyP := getTryParams(instr, parameter)
-				desc = fmt.Sprintf("catch %s, finally %s",
-					getOffsetDesc(ctx, catchP), getOffsetDesc(ctx, finallyP))
-			case opcode.INITSSLOT:
-				desc = fmt.Sprint(parameter[0])
-			case opcode.CONVERT, opcode.ISTYPE:
-				typ := stackitem.Type(parameter[0])
-				desc = fmt.Sprintf("%s (%x)", typ, parameter[0])
-			case opcode.INITSLOT:
-				desc = fmt.Sprintf("%d local, %d arg", parameter[0], parameter[1])
-			case opcode.SYSCALL:
-				name, err := interopnames.FromID(GetInteropID(parameter))
-				if err != nil {
-					name = "not found"
-				}
-				desc = fmt.Sprintf("%s (%x)", name, parameter)
-			case opcode.PUSHINT8, opcode.PUSHINT16, opcode.PUSHINT32,
-				opcode.PUSHINT64, opcode.PUSHINT128, opcode.PUSHINT256:
-				val := bigint.FromBytes(parameter)
-				desc = fmt.Sprintf("%d (%x)", val, parameter)
-			case opcode.LDLOC, opcode.STLOC, opcode.LDARG, opcode.STARG, opcode.LDSFLD, opcode.STSFLD:
-				desc = fmt.Sprintf("%d (%x)", parameter[0], parameter)
-			default:
-				if utf8.Valid(parameter) {
-					desc = fmt.Sprintf("%x (%q)", parameter, parameter)
-				} else {
-					desc = fmt.Sprintf("%x", parameter)
-				}
-			}
-		}
-
-		fmt.Fprintf(w, "%d\t%s\t%s%s\n", ctx.ip, instr, desc, cursor)
-		if ctx.nextip >= len(ctx.sc.prog) {
-			break
+	cursor := func(ip int) string {
+		if ip == realctx.ip {
+			return "\t<<"
 		}
+		return ""
 	}
-	w.Flush()
-}
-
-func getOffsetDesc(ctx *Context, parameter []byte) string {
-	offset, rOffset, err := calcJumpOffset(ctx, parameter)
-	if err != nil {
-		return fmt.Sprintf("ERROR: %v", err)
+	instrs, err := Disassemble(realctx.sc.prog)
+	for _, instr := range instrs {
+		fmt.Fprintf(w, "%d\t%s\t%s%s\n", instr.Offset, instr.Opcode, instr.Description(), cursor(instr.Offset))
 	}
-	return fmt.Sprintf("%d (%d/%x)", offset, rOffset, parameter)
+	var e *errorAtInstruct
+	if errors.As(err, &e) {
+		fmt.Fprintf(w, "%d\t%s\tERROR: %s%s\n", e.ip, e.op, e.err, cursor(e.ip))
+	}
+	w.Flush()
 }
 
 // AddBreakPoint adds a breakpoint to the current context.
@@ -245,6 +246,18 @@ func (v *VM) AddBreakPoint(n int) {
 	ctx.sc.breakPoints = append(ctx.sc.breakPoints, n)
 }
 
+// RemoveBreakPoint removes a single breakpoint at the given instruction from
+// the current context, it does nothing if there is no such breakpoint.
+func (v *VM) RemoveBreakPoint(n int) {
+	ctx := v.Context()
+	for i := len(ctx.sc.breakPoints) - 1; i >= 0; i-- {
+		if ctx.sc.breakPoints[i] == n {
+			ctx.sc.breakPoints = append(ctx.sc.breakPoints[:i], ctx.sc.breakPoints[i+1:]...)
+			return
+		}
+	}
+}
+
 // AddBreakPointRel adds a breakpoint relative to the current
 // instruction pointer.
 func (v *VM) AddBreakPointRel(n int) {
@@ -276,6 +289,30 @@ func (v *VM) GetInvocationTree() *invocations.Tree {
 	return v.invTree
 }
 
+// EnableGasStats enables GAS consumption breakdown collection for the VM, it
+// can be retrieved with GetGasStats.
+func (v *VM) EnableGasStats() {
+	v.gasStats = &GasStats{Syscalls: make(map[uint32]int64)}
+}
+
+// GetGasStats returns GAS consumption breakdown collected so far (nil if
+// not enabled).
+func (v *VM) GetGasStats() *GasStats {
+	return v.gasStats
+}
+
+// SetTracer sets the given Tracer to receive execution events, nil disables
+// tracing.
+func (v *VM) SetTracer(t Tracer) {
+	v.tracer = t
+}
+
+// SetOnExecHook sets the given OnExecHook to be called before every
+// instruction execution, nil disables it.
+func (v *VM) SetOnExecHook(h OnExecHook) {
+	v.onExecHook = h
+}
+
 // Load initializes the VM with the program given.
 func (v *VM) Load(prog []byte) {
 	v.LoadWithFlags(prog, callflag.NoneFlag)
@@ -314,6 +351,24 @@ func (v *VM) LoadScriptWithHash(b []byte, hash util.Uint160, f callflag.CallFlag
 	v.loadScriptWithCallingHash(b, nil, v.GetCurrentScriptHash(), hash, f, 1, 0, nil)
 }
 
+// LoadDynamicScript loads the given script with the given flags. The script is
+// executed in a separate context with its own evaluation stack and must return
+// at most one value, Null is returned to the caller if it returns nothing. The
+// hash of the script is used as the executing script hash and the current
+// script hash becomes the calling one.
+func (v *VM) LoadDynamicScript(b []byte, f callflag.CallFlag) {
+	v.loadScriptWithCallingHash(b, nil, v.GetCurrentScriptHash(), hash.Hash160(b), f, 1, 0, dynamicOnUnload)
+}
+
+// dynamicOnUnload is a context unload callback for dynamic scripts, it ensures
+// there is a value to return.
+func dynamicOnUnload(ctx *Context, commit bool) error {
+	if commit && ctx.Estack().Len() == 0 {
+		ctx.Estack().PushItem(stackitem.Null{})
+	}
+	return nil
+}
+
 // LoadNEFMethod allows to create a context to execute a method from the NEF
 // file with the specified caller and executing hash, call flags, return value,
 // method and _initialize offsets.
@@ -574,14 +629,28 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		if errRecover := recover(); errRecover != nil {
 			v.state = vmstate.Fault
 			err = newError(ctx.ip, op, errRecover)
-		} else if v.refs > MaxStackSize {
+		} else if int(v.refs) > v.limits.MaxStackSize {
 			v.state = vmstate.Fault
-			err = newError(ctx.ip, op, "stack is too big")
+			err = newError(ctx.ip, op, ErrStackOverflow)
 		}
 	}()
 
+	if v.tracer != nil && ctx != nil {
+		gasLeft := int64(-1)
+		if v.GasLimit >= 0 {
+			gasLeft = v.GasLimit - v.gasConsumed
+		}
+		v.tracer.OnStep(ctx.ScriptHash(), ctx.ip, op, v.estack.Len(), gasLeft)
+	}
+	if v.onExecHook != nil && ctx != nil {
+		v.onExecHook(ctx.ScriptHash(), ctx.ip, op)
+	}
 	if v.getPrice != nil && ctx.ip < len(ctx.sc.prog) {
-		v.gasConsumed += v.getPrice(op, parameter)
+		price := v.getPrice(op, parameter)
+		v.gasConsumed += price
+		if v.gasStats != nil {
+			v.gasStats.Opcodes[op] += price
+		}
 		if v.GasLimit >= 0 && v.gasConsumed > v.GasLimit {
 			panic("gas limit is exceeded")
 		}
@@ -602,6 +671,9 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		v.estack.PushItem(stackitem.NewBigInteger(big.NewInt(int64(val))))
 
 	case opcode.PUSHDATA1, opcode.PUSHDATA2, opcode.PUSHDATA4:
+		if len(parameter) > v.limits.MaxItemSize {
+			panic(fmt.Errorf("%w: %d bytes", ErrItemTooBig, len(parameter)))
+		}
 		v.estack.PushItem(stackitem.NewByteArray(parameter))
 
 	case opcode.PUSHA:
@@ -627,6 +699,9 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		if err != nil {
 			panic(err)
 		}
+		if b, ok := result.Value().([]byte); ok && len(b) > v.limits.MaxItemSize {
+			panic(fmt.Errorf("%w: %d bytes", ErrItemTooBig, len(b)))
+		}
 		v.estack.PushItem(result)
 
 	case opcode.INITSSLOT:
@@ -703,9 +778,12 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 
 	case opcode.NEWBUFFER:
 		n := toInt(v.estack.Pop().BigInt())
-		if n < 0 || n > stackitem.MaxSize {
+		if n < 0 {
 			panic("invalid size")
 		}
+		if n > v.limits.MaxItemSize {
+			panic(fmt.Errorf("%w: %d bytes", ErrItemTooBig, n))
+		}
 		v.estack.PushItem(stackitem.NewBuffer(make([]byte, n)))
 
 	case opcode.MEMCPY:
@@ -735,8 +813,8 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		b := v.estack.Pop().Bytes()
 		a := v.estack.Pop().Bytes()
 		l := len(a) + len(b)
-		if l > stackitem.MaxSize {
-			panic(fmt.Sprintf("too big item: %d", l))
+		if l > v.limits.MaxItemSize {
+			panic(fmt.Errorf("%w: %d bytes", ErrItemTooBig, l))
 		}
 		ab := make([]byte, l)
 		copy(ab, a)
@@ -1108,9 +1186,12 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 
 	case opcode.NEWARRAY, opcode.NEWARRAYT, opcode.NEWSTRUCT:
 		n := toInt(v.estack.Pop().BigInt())
-		if n < 0 || n > MaxStackSize {
+		if n < 0 {
 			panic("wrong number of elements")
 		}
+		if n > v.limits.MaxStackSize {
+			panic(fmt.Errorf("%w: %d elements", ErrStackOverflow, n))
+		}
 		typ := stackitem.AnyT
 		if op == opcode.NEWARRAYT {
 			typ = stackitem.Type(parameter[0])
@@ -1459,7 +1540,7 @@ func (v *VM) execute(ctx *Context, op opcode.Opcode, parameter []byte) (err erro
 		if v.SyscallHandler == nil {
 			panic("vm's SyscallHandler is not initialized")
 		}
-		err := v.SyscallHandler(v, interopID)
+		err := v.callSyscall(interopID)
 		if err != nil {
 			panic(fmt.Sprintf("failed to invoke syscall %d: %s", interopID, err))
 		}
@@ -1647,6 +1728,29 @@ func (v *VM) unloadContext(ctx *Context) {
 	}
 }
 
+// callSyscall invokes the syscall handler notifying the tracer (if any) and
+// accounting the GAS it consumes (if enabled).
+func (v *VM) callSyscall(id uint32) (err error) {
+	if v.gasStats != nil {
+		start := v.gasConsumed
+		defer func() {
+			v.gasStats.Syscalls[id] += v.gasConsumed - start
+		}()
+	}
+	if v.tracer == nil {
+		return v.SyscallHandler(v, id)
+	}
+	v.tracer.OnSyscallEnter(id)
+	defer func() {
+		if r := recover(); r != nil {
+			v.tracer.OnSyscallExit(id, fmt.Errorf("%v", r))
+			panic(r)
+		}
+		v.tracer.OnSyscallExit(id, err)
+	}()
+	return v.SyscallHandler(v, id)
+}
+
 // getTryParams splits TRY(L) instruction parameter into offsets for catch and finally blocks.
 func getTryParams(op opcode.Opcode, p []byte) ([]byte, []byte) {
 	i := 1
@@ -14,10 +14,12 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/random"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -49,6 +51,124 @@ func TestInteropHook(t *testing.T) {
 	assert.Equal(t, big.NewInt(1), v.estack.Pop().value.Value())
 }
 
+type testTracer struct {
+	events []string
+}
+
+func (t *testTracer) OnStep(_ util.Uint160, ip int, op opcode.Opcode, stackDepth int, gasLeft int64) {
+	t.events = append(t.events, fmt.Sprintf("%d %s %d %d", ip, op, stackDepth, gasLeft))
+}
+
+func (t *testTracer) OnSyscallEnter(id uint32) {
+	t.events = append(t.events, fmt.Sprintf("enter %d", id))
+}
+
+func (t *testTracer) OnSyscallExit(id uint32, err error) {
+	t.events = append(t.events, fmt.Sprintf("exit %d %v", id, err))
+}
+
+func TestVM_SetTracer(t *testing.T) {
+	fooID := interopnames.ToID([]byte("foo"))
+	barID := interopnames.ToID([]byte("bar"))
+	buf := io.NewBufBinWriter()
+	emit.Syscall(buf.BinWriter, "foo")
+	emit.Opcodes(buf.BinWriter, opcode.DROP)
+	emit.Syscall(buf.BinWriter, "bar")
+	emit.Opcodes(buf.BinWriter, opcode.RET)
+	prog := buf.Bytes()
+
+	v := newTestVM()
+	v.SyscallHandler = fooInteropHandler
+	v.GasLimit = 10
+	tr := new(testTracer)
+	v.SetTracer(tr)
+	v.Load(prog)
+	checkVMFailed(t, v)
+	require.Equal(t, []string{
+		"0 SYSCALL 0 10",
+		fmt.Sprintf("enter %d", fooID),
+		fmt.Sprintf("exit %d <nil>", fooID),
+		"5 DROP 1 9",
+		"6 SYSCALL 0 9",
+		fmt.Sprintf("enter %d", barID),
+		fmt.Sprintf("exit %d syscall not found", barID),
+	}, tr.events)
+
+	t.Run("no limit", func(t *testing.T) {
+		tr := new(testTracer)
+		v.SetTracer(tr)
+		v.GasLimit = -1
+		v.Load(makeProgram(opcode.PUSH1))
+		runVM(t, v)
+		require.Equal(t, []string{"0 PUSH1 0 -1", "1 RET 1 -1"}, tr.events)
+	})
+	t.Run("reset", func(t *testing.T) {
+		v.Reset(trigger.Application)
+		require.Nil(t, v.tracer)
+	})
+}
+
+func TestVM_SetOnExecHook(t *testing.T) {
+	prog := makeProgram(opcode.PUSH1, opcode.PUSH2, opcode.ADD)
+	var (
+		v       = newTestVM()
+		h       = hash.Hash160(prog)
+		offsets []int
+		ops     []opcode.Opcode
+	)
+	v.SetOnExecHook(func(scriptHash util.Uint160, offset int, op opcode.Opcode) {
+		require.Equal(t, h, scriptHash)
+		offsets = append(offsets, offset)
+		ops = append(ops, op)
+	})
+	v.Load(prog)
+	runVM(t, v)
+	require.Equal(t, []int{0, 1, 2, 3}, offsets)
+	require.Equal(t, []opcode.Opcode{opcode.PUSH1, opcode.PUSH2, opcode.ADD, opcode.RET}, ops)
+
+	t.Run("reset", func(t *testing.T) {
+		v.Reset(trigger.Application)
+		require.Nil(t, v.onExecHook)
+	})
+}
+
+func TestVM_GasStats(t *testing.T) {
+	buf := io.NewBufBinWriter()
+	emit.Opcodes(buf.BinWriter, opcode.PUSH1, opcode.PUSH2)
+	emit.Syscall(buf.BinWriter, "foo")
+	emit.Opcodes(buf.BinWriter, opcode.DROP, opcode.DROP, opcode.RET)
+	prog := buf.Bytes()
+	fooID := interopnames.ToID([]byte("foo"))
+
+	v := newTestVM()
+	v.SetPriceGetter(func(op opcode.Opcode, _ []byte) int64 {
+		if op == opcode.SYSCALL {
+			return 0
+		}
+		return 2
+	})
+	v.SyscallHandler = fooInteropHandler
+	require.Nil(t, v.GetGasStats())
+	v.EnableGasStats()
+	v.Load(prog)
+	runVM(t, v)
+
+	stats := v.GetGasStats()
+	require.NotNil(t, stats)
+	require.EqualValues(t, 2, stats.Opcodes[opcode.PUSH1])
+	require.EqualValues(t, 2, stats.Opcodes[opcode.PUSH2])
+	require.EqualValues(t, 4, stats.Opcodes[opcode.DROP])
+	require.EqualValues(t, 2, stats.Opcodes[opcode.RET])
+	require.EqualValues(t, 0, stats.Opcodes[opcode.SYSCALL])
+	require.Equal(t, map[uint32]int64{fooID: 1}, stats.Syscalls)
+	require.EqualValues(t, 11, v.GasConsumed())
+
+	t.Run("reset", func(t *testing.T) {
+		v.Reset(trigger.Application)
+		require.Nil(t, v.GetGasStats())
+	})
+}
+
 func TestVM_SetPriceGetter(t *testing.T) {
 	v := newTestVM()
 	prog := []byte{
@@ -2761,6 +2881,39 @@ func TestUninitializedSyscallHandler(t *testing.T) {
 	assert.Equal(t, true, v.HasFailed())
 }
 
+func TestLoadDynamicScript(t *testing.T) {
+	newVM := func(dynamic []byte) *VM {
+		v := load(makeProgram(opcode.PUSH7))
+		v.LoadDynamicScript(dynamic, callflag.ReadOnly)
+		return v
+	}
+	t.Run("single value", func(t *testing.T) {
+		v := newVM(makeProgram(opcode.PUSH2, opcode.PUSH3, opcode.ADD))
+		require.Equal(t, callflag.ReadOnly, v.Context().GetCallFlags())
+		runVM(t, v)
+		require.Equal(t, 2, v.estack.Len())
+		require.Equal(t, big.NewInt(7), v.estack.Pop().Value())
+		require.Equal(t, big.NewInt(5), v.estack.Pop().Value())
+	})
+	t.Run("no value", func(t *testing.T) {
+		v := newVM(makeProgram(opcode.NOP))
+		runVM(t, v)
+		require.Equal(t, 2, v.estack.Len())
+		require.Equal(t, big.NewInt(7), v.estack.Pop().Value())
+		require.Equal(t, stackitem.Null{}, v.estack.Pop().Item())
+	})
+	t.Run("multiple values", func(t *testing.T) {
+		v := newVM(makeProgram(opcode.PUSH2, opcode.PUSH3))
+		checkVMFailed(t, v)
+	})
+	t.Run("script hashes", func(t *testing.T) {
+		dynamic := makeProgram(opcode.NOP)
+		v := newVM(dynamic)
+		require.Equal(t, hash.Hash160(dynamic), v.GetCurrentScriptHash())
+		require.Equal(t, hash.Hash160(makeProgram(opcode.PUSH7)), v.GetCallingScriptHash())
+	})
+}
+
 func makeProgram(opcodes ...opcode.Opcode) []byte {
 	prog := make([]byte, len(opcodes)+1) // RET
 	for i := 0; i < len(opcodes); i++ {
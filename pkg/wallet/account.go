@@ -1,6 +1,8 @@
 package wallet
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -9,17 +11,37 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/remote"
 )
 
+// defaultRemoteRetries is the number of retries for remote signer requests
+// used by Decrypt.
+const defaultRemoteRetries = 2
+
+// ErrWatchOnly is returned when watch-only account is used for signing.
+var ErrWatchOnly = errors.New("watch-only account can't sign")
+
 // Account represents a NEO account. It holds the private and the public key
 // along with some metadata.
 type Account struct {
 	// NEO private key.
 	privateKey *keys.PrivateKey
 
+	// Remote signer used instead of the private key for remote accounts and
+	// the context its requests are made with (cancelled by Close).
+	signer     remote.Signer
+	signCtx    context.Context
+	signCancel context.CancelFunc
+
+	// Verification parameter values for deployed contract-based accounts.
+	verifyParams []smartcontract.Parameter
+
 	// Script hash corresponding to the Address.
 	scriptHash util.Uint160
 
@@ -42,6 +64,28 @@ type Account struct {
 
 	// Indicates whether the account is the default change account.
 	Default bool `json:"isDefault"`
+
+	// Extra is arbitrary account metadata, NEP-6 doesn't define its format,
+	// so it's kept as is. Remote signer accounts store their key reference
+	// there (see RemoteKey).
+	Extra json.RawMessage `json:"extra,omitempty"`
+}
+
+// accountExtra is the Extra data format used by remote signer and watch-only
+// accounts.
+type accountExtra struct {
+	RemoteSigner *RemoteKey `json:"remoteSigner,omitempty"`
+	WatchOnly    bool       `json:"watchOnly,omitempty"`
+}
+
+// RemoteKey references a key stored in a remote signer.
+type RemoteKey struct {
+	// URL is the signer endpoint.
+	URL string `json:"url"`
+	// KeyID is the key identifier used by the signer.
+	KeyID string `json:"keyid"`
+	// PublicKey is the public key corresponding to KeyID.
+	PublicKey *keys.PublicKey `json:"publickey"`
 }
 
 // Contract represents a subset of the smartcontract to embed in the
@@ -87,6 +131,9 @@ func (a *Account) SignTx(net netmode.Magic, t *transaction.Transaction) error {
 	if a.Locked {
 		return errors.New("account is locked")
 	}
+	if a.IsWatchOnly() {
+		return ErrWatchOnly
+	}
 	if a.Contract == nil {
 		return errors.New("account has no contract")
 	}
@@ -104,17 +151,35 @@ func (a *Account) SignTx(net netmode.Magic, t *transaction.Transaction) error {
 		return errors.New("transaction is not yet signed by the previous signer")
 	}
 	if len(t.Scripts) == pos {
-		t.Scripts = append(t.Scripts, transaction.Witness{
-			VerificationScript: a.Contract.Script, // Can be nil for deployed contract.
-		})
+		var w transaction.Witness
+		if !a.Contract.Deployed { // Deployed contracts are verified by hash.
+			w.VerificationScript = a.Contract.Script
+		}
+		t.Scripts = append(t.Scripts, w)
 	}
 	if len(a.Contract.Parameters) == 0 {
 		return nil
 	}
-	if a.privateKey == nil {
+	if a.Contract.Deployed {
+		invoc, err := a.deployedInvocation(func() ([]byte, error) {
+			if !a.CanSign() {
+				return nil, errors.New("account key is not available (need to decrypt?)")
+			}
+			return a.signHash(a.signCtx, hash.NetSha256(uint32(net), t))
+		})
+		if err != nil {
+			return err
+		}
+		t.Scripts[pos].InvocationScript = invoc
+		return nil
+	}
+	if !a.CanSign() {
 		return errors.New("account key is not available (need to decrypt?)")
 	}
-	sign := a.privateKey.SignHashable(uint32(net), t)
+	sign, err := a.signHash(a.signCtx, hash.NetSha256(uint32(net), t))
+	if err != nil {
+		return err
+	}
 
 	invoc := append([]byte{byte(opcode.PUSHDATA1), keys.SignatureLen}, sign...)
 	if len(a.Contract.Parameters) == 1 {
@@ -126,19 +191,199 @@ func (a *Account) SignTx(net netmode.Magic, t *transaction.Transaction) error {
 	return nil
 }
 
+// DummyInvocationScript returns an invocation script for the deployed
+// contract's verify method with values set by SetVerifyParameters and zero
+// signatures. It's only useful for network fee calculation.
+func (a *Account) DummyInvocationScript() ([]byte, error) {
+	if a.Contract == nil || !a.Contract.Deployed {
+		return nil, errors.New("not a deployed contract-based account")
+	}
+	return a.deployedInvocation(func() ([]byte, error) {
+		return make([]byte, keys.SignatureLen), nil
+	})
+}
+
+// deployedInvocation creates an invocation script for the deployed contract's
+// verify method. Signature parameters are created with sign, values for all
+// other ones must be set with SetVerifyParameters. Arguments are pushed in
+// reverse order, so that the first one ends up on top of the stack.
+func (a *Account) deployedInvocation(sign func() ([]byte, error)) ([]byte, error) {
+	bw := io.NewBufBinWriter()
+	for i := len(a.Contract.Parameters) - 1; i >= 0; i-- {
+		p := a.Contract.Parameters[i]
+		if p.Type == smartcontract.SignatureType {
+			sig, err := sign()
+			if err != nil {
+				return nil, err
+			}
+			emit.Bytes(bw.BinWriter, sig)
+			continue
+		}
+		if len(a.verifyParams) == 0 || (a.verifyParams[i].Value == nil && p.Type != smartcontract.AnyType) {
+			return nil, fmt.Errorf("no value for verification parameter #%d (%s)", i, p.Name)
+		}
+		v, err := smartcontract.ExpandParameterToEmitable(a.verifyParams[i])
+		if err != nil {
+			return nil, fmt.Errorf("verification parameter #%d (%s): %w", i, p.Name, err)
+		}
+		emit.Any(bw.BinWriter, v)
+	}
+	if bw.Err != nil {
+		return nil, bw.Err
+	}
+	return bw.Bytes(), nil
+}
+
+// SetVerifyParameters sets values of deployed contract's verification
+// parameters that are used by SignTx to construct the invocation script.
+// Values are given in the order of contract parameters, the number of values
+// and their types must match it, Signature values are ignored (they're
+// created by SignTx). These values are not stored in the wallet, nil resets
+// them.
+func (a *Account) SetVerifyParameters(params []smartcontract.Parameter) error {
+	if params == nil {
+		a.verifyParams = nil
+		return nil
+	}
+	if a.Contract == nil || !a.Contract.Deployed {
+		return errors.New("not a deployed contract-based account")
+	}
+	if len(params) != len(a.Contract.Parameters) {
+		return fmt.Errorf("expected %d parameters, got %d", len(a.Contract.Parameters), len(params))
+	}
+	for i, p := range a.Contract.Parameters {
+		if p.Type != smartcontract.SignatureType && p.Type != smartcontract.AnyType && params[i].Type != p.Type {
+			return fmt.Errorf("parameter #%d (%s) has %s type, %s expected", i, p.Name, params[i].Type, p.Type)
+		}
+	}
+	a.verifyParams = params
+	return nil
+}
+
+// VerifyParameters returns verification parameter values set with
+// SetVerifyParameters.
+func (a *Account) VerifyParameters() []smartcontract.Parameter {
+	return a.verifyParams
+}
+
 // SignHashable signs the given Hashable item and returns the signature. If this
-// account can't sign (CanSign() returns false) nil is returned.
+// account can't sign (CanSign() returns false) or its key is stored in a remote
+// signer nil is returned, use SignHashableContext for remote accounts.
 func (a *Account) SignHashable(net netmode.Magic, item hash.Hashable) []byte {
-	if !a.CanSign() {
+	if !a.CanSign() || a.privateKey == nil {
 		return nil
 	}
 	return a.privateKey.SignHashable(uint32(net), item)
 }
 
+// SignHashableContext signs the given Hashable item and returns the signature.
+// It works for both local and remote keys, remote signer request is made with
+// the given context. An error is returned if this account can't sign
+// (CanSign() returns false) or remote signer fails.
+func (a *Account) SignHashableContext(ctx context.Context, net netmode.Magic, item hash.Hashable) ([]byte, error) {
+	return a.signHash(ctx, hash.NetSha256(uint32(net), item))
+}
+
+func (a *Account) signHash(ctx context.Context, digest util.Uint256) ([]byte, error) {
+	if !a.CanSign() {
+		return nil, errors.New("account can't sign")
+	}
+	if a.privateKey != nil {
+		return a.privateKey.SignHash(digest), nil
+	}
+	rk := a.RemoteKey()
+	sig, err := a.signer.SignDigest(ctx, rk.KeyID, digest)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer: %w", err)
+	}
+	if !rk.PublicKey.Verify(sig, digest[:]) {
+		return nil, errors.New("remote signer returned invalid signature")
+	}
+	return sig, nil
+}
+
 // CanSign returns true when account is not locked and has a decrypted private
-// key inside, so it's ready to create real signatures.
+// key inside or a connected remote signer, so it's ready to create real
+// signatures.
 func (a *Account) CanSign() bool {
-	return !a.Locked && a.privateKey != nil
+	return !a.Locked && (a.privateKey != nil || a.signer != nil)
+}
+
+// IsWatchOnly returns true for watch-only accounts (see NewWatchOnlyAccount),
+// such accounts can't sign anything.
+func (a *Account) IsWatchOnly() bool {
+	e, _ := a.getExtra()
+	return e.WatchOnly
+}
+
+// getExtra parses the account's Extra data, false is returned if it's not in
+// the accountExtra format.
+func (a *Account) getExtra() (accountExtra, bool) {
+	var e accountExtra
+	if len(a.Extra) == 0 || json.Unmarshal(a.Extra, &e) != nil {
+		return accountExtra{}, false
+	}
+	return e, true
+}
+
+// IsRemote returns true if the account key is stored in a remote signer.
+func (a *Account) IsRemote() bool {
+	return a.RemoteKey() != nil
+}
+
+// RemoteKey returns the remote signer key reference stored in the account's
+// Extra data or nil if it's not a remote account.
+func (a *Account) RemoteKey() *RemoteKey {
+	e, ok := a.getExtra()
+	if !ok || e.RemoteSigner == nil || e.RemoteSigner.PublicKey == nil {
+		return nil
+	}
+	return e.RemoteSigner
+}
+
+// Connect creates a remote signer client with the given options and makes the
+// account use it for signing (see SetRemoteSigner). It can only be used for
+// remote accounts.
+func (a *Account) Connect(ctx context.Context, opts remote.Options) error {
+	rk := a.RemoteKey()
+	if rk == nil {
+		return errors.New("not a remote account")
+	}
+	c, err := remote.New(rk.URL, opts)
+	if err != nil {
+		return err
+	}
+	return a.SetRemoteSigner(ctx, c)
+}
+
+// SetRemoteSigner makes remote account use the given signer. The signer is
+// checked to have the key with the ID and public key specified in the
+// account. The context given is used for this check and for signing requests
+// made by SignTx, cancelling it (or calling Close) aborts them.
+func (a *Account) SetRemoteSigner(ctx context.Context, s remote.Signer) error {
+	rk := a.RemoteKey()
+	if rk == nil {
+		return errors.New("not a remote account")
+	}
+	ks, err := s.ListKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list remote keys: %w", err)
+	}
+	for i := range ks {
+		if ks[i].ID != rk.KeyID {
+			continue
+		}
+		if !ks[i].PublicKey.Equal(rk.PublicKey) {
+			return fmt.Errorf("remote key %q has different public key", rk.KeyID)
+		}
+		if a.signCancel != nil {
+			a.signCancel() // Previous signer is no longer used.
+		}
+		a.signer = s
+		a.signCtx, a.signCancel = context.WithCancel(ctx)
+		return nil
+	}
+	return fmt.Errorf("remote key %q not found", rk.KeyID)
 }
 
 // GetVerificationScript returns account's verification script.
@@ -146,6 +391,9 @@ func (a *Account) GetVerificationScript() []byte {
 	if a.Contract != nil {
 		return a.Contract.Script
 	}
+	if a.privateKey == nil {
+		return nil
+	}
 	return a.privateKey.PublicKey().GetVerificationScript()
 }
 
@@ -153,9 +401,18 @@ func (a *Account) GetVerificationScript() []byte {
 // if anything goes wrong. After the decryption Account can be used to sign
 // things unless it's locked. Don't decrypt the key unless you want to sign
 // something and don't forget to call Close after use for maximum safety.
+// Remote accounts are connected to their signers with default timeouts and
+// a couple of retries instead (passphrase is not used then), pending requests
+// can be aborted with Close.
 func (a *Account) Decrypt(passphrase string, scrypt keys.ScryptParams) error {
 	var err error
 
+	if a.EncryptedWIF == "" && a.IsRemote() {
+		return a.Connect(context.Background(), remote.Options{Retries: defaultRemoteRetries})
+	}
+	if a.IsWatchOnly() {
+		return ErrWatchOnly
+	}
 	if a.EncryptedWIF == "" {
 		return errors.New("no encrypted wif in the account")
 	}
@@ -193,6 +450,9 @@ func (a *Account) PublicKey() *keys.PublicKey {
 	if !a.CanSign() {
 		return nil
 	}
+	if a.privateKey == nil {
+		return a.RemoteKey().PublicKey
+	}
 	return a.privateKey.PublicKey()
 }
 
@@ -207,9 +467,15 @@ func (a *Account) ScriptHash() util.Uint160 {
 }
 
 // Close cleans up the private key used by Account and disassociates it from
-// Account. The Account can no longer sign anything after this call, but Decrypt
-// can make it usable again.
+// Account (remote signer is disconnected too and its pending requests are
+// cancelled). The Account can no longer sign anything after this call, but
+// Decrypt can make it usable again.
 func (a *Account) Close() {
+	if a.signCancel != nil {
+		a.signCancel()
+		a.signCtx, a.signCancel = nil, nil
+	}
+	a.signer = nil
 	if a.privateKey == nil {
 		return
 	}
@@ -217,6 +483,66 @@ func (a *Account) Close() {
 	a.privateKey = nil
 }
 
+// NewWatchOnlyAccount creates a new watch-only Account for the given address.
+// It has no key and a best-effort signature contract with an empty script (it's
+// not known), so it can be used for balance queries, but not for signing or
+// transaction creation.
+func NewWatchOnlyAccount(addr string) (*Account, error) {
+	if _, err := address.StringToUint160(addr); err != nil {
+		return nil, err
+	}
+	return &Account{
+		Address:  addr,
+		Contract: &Contract{Parameters: getContractParams(1)},
+		Extra:    watchOnlyExtra(),
+	}, nil
+}
+
+// NewWatchOnlyAccountFromPublicKey creates a new watch-only Account for the
+// given public key. It has no private key, but has a standard signature
+// contract, so transactions can be created for it (to be signed elsewhere).
+func NewWatchOnlyAccountFromPublicKey(pub *keys.PublicKey) *Account {
+	return &Account{
+		scriptHash: pub.GetScriptHash(),
+		Address:    pub.Address(),
+		Contract: &Contract{
+			Script:     pub.GetVerificationScript(),
+			Parameters: getContractParams(1),
+		},
+		Extra: watchOnlyExtra(),
+	}
+}
+
+// WatchOnlyCopy returns a watch-only copy of the account that only contains
+// its address, label and contract (script and parameters), so it can be
+// shared with other parties to create transactions or verify signatures
+// without exposing the key.
+func (a *Account) WatchOnlyCopy() *Account {
+	res := &Account{
+		scriptHash: a.scriptHash,
+		Address:    a.Address,
+		Label:      a.Label,
+		Extra:      watchOnlyExtra(),
+	}
+	if a.Contract != nil {
+		c := *a.Contract
+		if c.Script != nil {
+			c.Script = slice.Copy(c.Script)
+		}
+		if c.Parameters != nil {
+			c.Parameters = append([]ContractParam{}, c.Parameters...)
+		}
+		res.Contract = &c
+	}
+	return res
+}
+
+func watchOnlyExtra() json.RawMessage {
+	// Can't fail, it's a fixed structure.
+	extra, _ := json.Marshal(accountExtra{WatchOnly: true})
+	return extra
+}
+
 // NewAccountFromWIF creates a new Account from the given WIF.
 func NewAccountFromWIF(wif string) (*Account, error) {
 	privKey, err := keys.NewPrivateKeyFromWIF(wif)
@@ -244,11 +570,11 @@ func (a *Account) ConvertMultisig(m int, pubs []*keys.PublicKey) error {
 	if a.Locked {
 		return errors.New("account is locked")
 	}
-	if a.privateKey == nil {
+	if !a.CanSign() {
 		return errors.New("account key is not available (need to decrypt?)")
 	}
 	var found bool
-	accKey := a.privateKey.PublicKey()
+	accKey := a.PublicKey()
 	for i := range pubs {
 		if accKey.Equal(pubs[i]) {
 			found = true
@@ -292,6 +618,28 @@ func NewAccountFromPrivateKey(p *keys.PrivateKey) *Account {
 	return a
 }
 
+// NewRemoteAccount creates an account referencing the key with the given ID
+// and public key stored in the remote signer available at url. It has a
+// standard signature contract and can sign after connecting to the signer
+// (see Connect and SetRemoteSigner).
+func NewRemoteAccount(url string, keyID string, pub *keys.PublicKey) *Account {
+	// Can't fail, it's a fixed structure with a valid key.
+	extra, _ := json.Marshal(accountExtra{RemoteSigner: &RemoteKey{
+		URL:       url,
+		KeyID:     keyID,
+		PublicKey: pub,
+	}})
+	return &Account{
+		scriptHash: pub.GetScriptHash(),
+		Address:    pub.Address(),
+		Contract: &Contract{
+			Script:     pub.GetVerificationScript(),
+			Parameters: getContractParams(1),
+		},
+		Extra: extra,
+	}
+}
+
 func getContractParams(n int) []ContractParam {
 	params := make([]ContractParam, n)
 	for i := range params {
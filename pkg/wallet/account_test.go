@@ -1,8 +1,11 @@
 package wallet
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"math/big"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/keytestcases"
@@ -11,6 +14,9 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/remote"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -167,6 +173,315 @@ func TestContractSignTx(t *testing.T) {
 	require.Equal(t, 132, len(tx.Scripts[2].InvocationScript))
 }
 
+func TestDeployedContractSignTx(t *testing.T) {
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	h := util.Uint160{1, 2, 3}
+	acc := &Account{
+		privateKey: priv,
+		Address:    address.Uint160ToString(h),
+		Contract: &Contract{
+			Script: []byte{byte(opcode.PUSHT)}, // Contract's NEF script, not a verification one.
+			Parameters: []ContractParam{
+				{Name: "sig", Type: smartcontract.SignatureType},
+				{Name: "password", Type: smartcontract.StringType},
+			},
+			Deployed: true,
+		},
+	}
+	tx := &transaction.Transaction{
+		Script:  []byte{1, 2, 3},
+		Signers: []transaction.Signer{{Account: h}},
+	}
+	require.Error(t, acc.SignTx(0, tx)) // No password.
+	require.Equal(t, 1, len(tx.Scripts))
+	require.Nil(t, tx.Scripts[0].VerificationScript)
+	_, err = acc.DummyInvocationScript()
+	require.Error(t, err)
+
+	require.Error(t, acc.SetVerifyParameters([]smartcontract.Parameter{{Type: smartcontract.StringType, Value: "pass"}}))
+	require.Error(t, acc.SetVerifyParameters([]smartcontract.Parameter{
+		{Type: smartcontract.SignatureType},
+		{Type: smartcontract.IntegerType, Value: big.NewInt(1)},
+	}))
+	params := []smartcontract.Parameter{
+		{Type: smartcontract.SignatureType},
+		{Type: smartcontract.StringType}, // Not set.
+	}
+	require.NoError(t, acc.SetVerifyParameters(params))
+	require.Error(t, acc.SignTx(0, tx))
+
+	params[1].Value = "pass"
+	require.NoError(t, acc.SetVerifyParameters(params))
+	require.Equal(t, params, acc.VerifyParameters())
+	require.NoError(t, acc.SignTx(0, tx))
+
+	// Arguments are pushed in reverse order.
+	sig := acc.SignHashable(0, tx)
+	expected := append([]byte{byte(opcode.PUSHDATA1), 4}, "pass"...)
+	expected = append(expected, byte(opcode.PUSHDATA1), keys.SignatureLen)
+	require.Equal(t, append(expected, sig...), tx.Scripts[0].InvocationScript)
+
+	dummy, err := acc.DummyInvocationScript()
+	require.NoError(t, err)
+	require.Equal(t, len(tx.Scripts[0].InvocationScript), len(dummy))
+
+	acc.Close()
+	require.Error(t, acc.SignTx(0, tx)) // Signature is required.
+
+	acc.Contract.Parameters = acc.Contract.Parameters[1:]
+	require.NoError(t, acc.SetVerifyParameters(params[1:]))
+	require.NoError(t, acc.SignTx(0, tx)) // No key needed.
+	require.Equal(t, append([]byte{byte(opcode.PUSHDATA1), 4}, "pass"...), tx.Scripts[0].InvocationScript)
+
+	require.NoError(t, acc.SetVerifyParameters(nil))
+	require.Nil(t, acc.VerifyParameters())
+	acc, err = NewAccount()
+	require.NoError(t, err)
+	require.Error(t, acc.SetVerifyParameters(params)) // Not deployed.
+}
+
+type fakeSigner struct {
+	keys   map[string]*keys.PrivateKey
+	err    error
+	badSig bool
+	hang   bool
+}
+
+func (f *fakeSigner) ListKeys(context.Context) ([]remote.Key, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	var res []remote.Key
+	for id, k := range f.keys {
+		res = append(res, remote.Key{ID: id, PublicKey: k.PublicKey()})
+	}
+	return res, nil
+}
+
+func (f *fakeSigner) SignDigest(ctx context.Context, keyID string, digest util.Uint256) ([]byte, error) {
+	if f.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	k, ok := f.keys[keyID]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	if f.badSig {
+		digest[0]++
+	}
+	return k.SignHash(digest), nil
+}
+
+func TestRemoteAccount(t *testing.T) {
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	other, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	acc := NewRemoteAccount("http://localhost:8080", "key1", priv.PublicKey())
+	require.True(t, acc.IsRemote())
+	require.False(t, acc.CanSign())
+	require.Equal(t, priv.Address(), acc.Address)
+	require.Equal(t, priv.GetScriptHash(), acc.ScriptHash())
+	require.Error(t, acc.Connect(context.Background(), remote.Options{})) // No signer there.
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(acc)
+		require.NoError(t, err)
+		actual := new(Account)
+		require.NoError(t, json.Unmarshal(data, actual))
+		require.Equal(t, acc.Extra, actual.Extra)
+		require.True(t, actual.IsRemote())
+
+		data, err = json.Marshal(NewAccountFromPrivateKey(priv))
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "extra")
+
+		// Arbitrary extra data is preserved and doesn't make account remote.
+		for _, extra := range []string{`"some data"`, `{"tokens":[1,2]}`, `{"remoteSigner":null}`, `null`} {
+			data = []byte(`{"address":"` + priv.Address() + `","key":"","label":"","contract":null,"lock":false,"isDefault":false,"extra":` + extra + `}`)
+			actual = new(Account)
+			require.NoError(t, json.Unmarshal(data, actual))
+			require.False(t, actual.IsRemote(), extra)
+			out, err := json.Marshal(actual)
+			require.NoError(t, err)
+			require.JSONEq(t, string(data), string(out))
+		}
+	})
+
+	ctx := context.Background()
+	require.Error(t, acc.SetRemoteSigner(ctx, &fakeSigner{err: errors.New("down")}))
+	require.Error(t, acc.SetRemoteSigner(ctx, &fakeSigner{keys: map[string]*keys.PrivateKey{"key2": priv}}))
+	require.Error(t, acc.SetRemoteSigner(ctx, &fakeSigner{keys: map[string]*keys.PrivateKey{"key1": other}}))
+	require.False(t, acc.CanSign())
+
+	s := &fakeSigner{keys: map[string]*keys.PrivateKey{"key1": priv}}
+	require.NoError(t, acc.SetRemoteSigner(ctx, s))
+	require.True(t, acc.CanSign())
+	require.True(t, priv.PublicKey().Equal(acc.PublicKey()))
+	require.Nil(t, acc.PrivateKey())
+
+	tx := &transaction.Transaction{
+		Script: []byte{1, 2, 3},
+		Signers: []transaction.Signer{{
+			Account: acc.ScriptHash(),
+			Scopes:  transaction.CalledByEntry,
+		}},
+	}
+	require.NoError(t, acc.SignTx(42, tx))
+	require.Equal(t, 1, len(tx.Scripts))
+	require.Equal(t, 66, len(tx.Scripts[0].InvocationScript))
+	require.True(t, priv.PublicKey().VerifyHashable(tx.Scripts[0].InvocationScript[2:], 42, tx))
+	sig, err := acc.SignHashableContext(ctx, 42, tx)
+	require.NoError(t, err)
+	require.True(t, priv.PublicKey().VerifyHashable(sig, 42, tx))
+	require.Nil(t, acc.SignHashable(42, tx)) // Remote key is not used by it.
+
+	s.badSig = true
+	require.Error(t, acc.SignTx(42, tx))
+	_, err = acc.SignHashableContext(ctx, 42, tx)
+	require.Error(t, err)
+	s.badSig = false
+
+	s.err = errors.New("down")
+	require.Error(t, acc.SignTx(42, tx))
+	_, err = acc.SignHashableContext(ctx, 42, tx)
+	require.Error(t, err)
+	s.err = nil
+
+	s.hang = true
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = acc.SignHashableContext(cctx, 42, tx)
+	require.ErrorIs(t, err, context.Canceled)
+	s.hang = false
+
+	acc.Locked = true
+	require.False(t, acc.CanSign())
+	_, err = acc.SignHashableContext(ctx, 42, tx)
+	require.Error(t, err)
+	acc.Locked = false
+
+	t.Run("multisig", func(t *testing.T) {
+		pubs := keys.PublicKeys{priv.PublicKey(), other.PublicKey()}
+		multiAcc := NewRemoteAccount("http://localhost:8080", "key1", priv.PublicKey())
+		require.NoError(t, multiAcc.SetRemoteSigner(ctx, s))
+		require.NoError(t, multiAcc.ConvertMultisig(1, pubs))
+		require.True(t, multiAcc.IsRemote())
+	})
+
+	t.Run("cancelled connection", func(t *testing.T) {
+		cctx, cancel := context.WithCancel(ctx)
+		hanging := &fakeSigner{keys: map[string]*keys.PrivateKey{"key1": priv}}
+		hAcc := NewRemoteAccount("http://localhost:8080", "key1", priv.PublicKey())
+		require.NoError(t, hAcc.SetRemoteSigner(cctx, hanging))
+		hanging.hang = true
+		cancel()
+		require.ErrorIs(t, hAcc.SignTx(42, tx), context.Canceled)
+	})
+
+	acc.Close()
+	require.False(t, acc.CanSign())
+	_, err = acc.SignHashableContext(ctx, 42, tx)
+	require.Error(t, err)
+}
+
+func TestWatchOnlyAccount(t *testing.T) {
+	_, err := NewWatchOnlyAccount("not an address")
+	require.Error(t, err)
+
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	acc, err := NewWatchOnlyAccount(priv.Address())
+	require.NoError(t, err)
+	require.True(t, acc.IsWatchOnly())
+	require.False(t, acc.CanSign())
+	require.Equal(t, priv.GetScriptHash(), acc.ScriptHash())
+	require.ErrorIs(t, acc.Decrypt("pass", keys.NEP2ScryptParams()), ErrWatchOnly)
+
+	tx := &transaction.Transaction{
+		Script: []byte{1, 2, 3},
+		Signers: []transaction.Signer{{
+			Account: acc.ScriptHash(),
+			Scopes:  transaction.CalledByEntry,
+		}},
+	}
+	require.ErrorIs(t, acc.SignTx(42, tx), ErrWatchOnly)
+
+	data, err := json.Marshal(acc)
+	require.NoError(t, err)
+	actual := new(Account)
+	require.NoError(t, json.Unmarshal(data, actual))
+	require.True(t, actual.IsWatchOnly())
+
+	require.False(t, NewAccountFromPrivateKey(priv).IsWatchOnly())
+	require.False(t, NewRemoteAccount("http://localhost:8080", "key1", priv.PublicKey()).IsWatchOnly())
+
+	require.NotNil(t, acc.Contract)
+	require.Nil(t, acc.GetVerificationScript())
+	require.Nil(t, acc.PublicKey())
+
+	// Address-only accounts without a key are not watch-only unless marked so.
+	plain := &Account{Address: priv.Address()}
+	require.False(t, plain.IsWatchOnly())
+	require.Nil(t, plain.GetVerificationScript())
+
+	t.Run("public key", func(t *testing.T) {
+		acc := NewWatchOnlyAccountFromPublicKey(priv.PublicKey())
+		require.True(t, acc.IsWatchOnly())
+		require.False(t, acc.CanSign())
+		require.Equal(t, priv.Address(), acc.Address)
+		require.Equal(t, priv.GetScriptHash(), acc.Contract.ScriptHash())
+		require.Equal(t, priv.PublicKey().GetVerificationScript(), acc.GetVerificationScript())
+		require.ErrorIs(t, acc.SignTx(42, tx), ErrWatchOnly)
+	})
+}
+
+func TestAccount_WatchOnlyCopy(t *testing.T) {
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	acc := NewAccountFromPrivateKey(priv)
+	acc.Label = "label"
+	acc.Default = true
+	require.NoError(t, acc.Encrypt("pass", keys.NEP2ScryptParams()))
+
+	cp := acc.WatchOnlyCopy()
+	require.True(t, cp.IsWatchOnly())
+	require.False(t, cp.CanSign())
+	require.Equal(t, "", cp.EncryptedWIF)
+	require.False(t, cp.Default)
+	require.Equal(t, acc.Address, cp.Address)
+	require.Equal(t, acc.Label, cp.Label)
+	require.Equal(t, acc.ScriptHash(), cp.ScriptHash())
+	require.Equal(t, acc.Contract, cp.Contract)
+	require.Equal(t, priv.PublicKey().GetVerificationScript(), cp.GetVerificationScript())
+
+	// Contract is copied.
+	cp.Contract.Script[0] ^= 0xff
+	cp.Contract.Parameters[0].Name = "changed"
+	require.Equal(t, priv.PublicKey().GetVerificationScript(), acc.Contract.Script)
+	require.Equal(t, "parameter0", acc.Contract.Parameters[0].Name)
+
+	t.Run("remote", func(t *testing.T) {
+		acc := NewRemoteAccount("http://localhost:8080", "key1", priv.PublicKey())
+		cp := acc.WatchOnlyCopy()
+		require.True(t, cp.IsWatchOnly())
+		require.False(t, cp.IsRemote())
+		require.Equal(t, acc.Contract, cp.Contract)
+	})
+	t.Run("no contract", func(t *testing.T) {
+		acc := &Account{Address: priv.Address()}
+		cp := acc.WatchOnlyCopy()
+		require.True(t, cp.IsWatchOnly())
+		require.Nil(t, cp.Contract)
+	})
+}
+
 func TestContract_ScriptHash(t *testing.T) {
 	script := []byte{0, 1, 2, 3}
 	c := &Contract{Script: script}
@@ -0,0 +1,247 @@
+/*
+Package remote implements a client for external signing services.
+
+Remote signer keeps private keys (usually in some HSM) and provides two
+operations: key listing and digest signing. The reference protocol is a simple
+HTTP/JSON one:
+
+	GET <endpoint>/keys
+	  -> {"keys": [{"id": "<key ID>", "publickey": "<hex-encoded compressed key>"}]}
+
+	POST <endpoint>/sign {"id": "<key ID>", "digest": "<hex-encoded 32-byte digest>"}
+	  -> {"signature": "<hex-encoded 64-byte signature>"}
+
+Signatures are the regular secp256r1 ECDSA ones (r || s, 32 bytes each) made
+for the digest given (it's not hashed again by the signer). Any non-200 reply
+is an error, it can have {"error": "<description>"} body with the details.
+*/
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+const (
+	// DefaultTimeout is the default timeout of a single request to the signer.
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetryDelay is the default delay between request attempts.
+	DefaultRetryDelay = time.Second
+
+	// maxReplySize is the maximum size of the signer reply.
+	maxReplySize = 1024 * 1024
+)
+
+// ErrCancelled is returned from Client.SignDigest when signing is not
+// confirmed.
+var ErrCancelled = errors.New("signing cancelled")
+
+// Signer is a remote signing service.
+type Signer interface {
+	// ListKeys returns all keys available in the signer.
+	ListKeys(ctx context.Context) ([]Key, error)
+	// SignDigest signs the given digest with the key specified and returns
+	// a 64-byte signature.
+	SignDigest(ctx context.Context, keyID string, digest util.Uint256) ([]byte, error)
+}
+
+// Key is a key stored in the remote signer.
+type Key struct {
+	ID        string          `json:"id"`
+	PublicKey *keys.PublicKey `json:"publickey"`
+}
+
+// Options contains Client parameters.
+type Options struct {
+	// Timeout is a timeout for a single request, DefaultTimeout is used if
+	// it's zero.
+	Timeout time.Duration
+	// Retries is the number of additional attempts made for requests failed
+	// because of network or signer-side (5xx) errors.
+	Retries int
+	// RetryDelay is the delay between attempts, DefaultRetryDelay is used if
+	// it's zero.
+	RetryDelay time.Duration
+	// Confirm, if set, is called before every signing request, a non-nil
+	// error returned from it cancels signing (and it's returned from
+	// SignDigest). It's intended for interactive confirmations.
+	Confirm func(keyID string, digest util.Uint256) error
+	// HTTPClient is the HTTP client to use, http.DefaultClient is used if
+	// it's nil.
+	HTTPClient *http.Client
+}
+
+// Client is an HTTP/JSON remote signer client implementing Signer.
+type Client struct {
+	endpoint string
+	opts     Options
+}
+
+type (
+	keysReply struct {
+		Keys []Key `json:"keys"`
+	}
+	signRequest struct {
+		ID     string `json:"id"`
+		Digest string `json:"digest"`
+	}
+	signReply struct {
+		Signature string `json:"signature"`
+	}
+	errorReply struct {
+		Error string `json:"error"`
+	}
+)
+
+// statusError is returned for non-200 replies.
+type statusError struct {
+	code int
+	msg  string
+}
+
+var _ Signer = (*Client)(nil)
+
+// New creates a new Client for the given endpoint.
+func New(endpoint string, opts Options) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer endpoint: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported signer endpoint scheme: %q", u.Scheme)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.RetryDelay == 0 {
+		opts.RetryDelay = DefaultRetryDelay
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = http.DefaultClient
+	}
+	return &Client{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		opts:     opts,
+	}, nil
+}
+
+// ListKeys implements the Signer interface.
+func (c *Client) ListKeys(ctx context.Context) ([]Key, error) {
+	var res keysReply
+	if err := c.do(ctx, http.MethodGet, "/keys", nil, &res); err != nil {
+		return nil, err
+	}
+	for i := range res.Keys {
+		if res.Keys[i].PublicKey == nil {
+			return nil, fmt.Errorf("no public key for %q", res.Keys[i].ID)
+		}
+	}
+	return res.Keys, nil
+}
+
+// SignDigest implements the Signer interface. Options.Confirm is called
+// before making a request if it's set.
+func (c *Client) SignDigest(ctx context.Context, keyID string, digest util.Uint256) ([]byte, error) {
+	if c.opts.Confirm != nil {
+		if err := c.opts.Confirm(keyID, digest); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCancelled, err)
+		}
+	}
+	req, err := json.Marshal(signRequest{
+		ID:     keyID,
+		Digest: hex.EncodeToString(digest[:]),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var res signReply
+	if err := c.do(ctx, http.MethodPost, "/sign", req, &res); err != nil {
+		return nil, err
+	}
+	sig, err := hex.DecodeString(res.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(sig) != keys.SignatureLen {
+		return nil, fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+	return sig, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body []byte, res interface{}) error {
+	var err error
+	for i := 0; i <= c.opts.Retries; i++ {
+		if i != 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w (last error: %s)", ctx.Err(), err)
+			case <-time.After(c.opts.RetryDelay):
+			}
+		}
+		err = c.doOnce(ctx, method, path, body, res)
+		var se *statusError
+		if err == nil || (errors.As(err, &se) && se.code < http.StatusInternalServerError) {
+			break
+		}
+	}
+	return err
+}
+
+func (c *Client) doOnce(ctx context.Context, method string, path string, body []byte, res interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+	defer cancel()
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxReplySize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e errorReply
+		_ = json.Unmarshal(data, &e)
+		return &statusError{code: resp.StatusCode, msg: e.Error}
+	}
+	if err := json.Unmarshal(data, res); err != nil {
+		return fmt.Errorf("invalid signer reply: %w", err)
+	}
+	return nil
+}
+
+// Error implements the error interface.
+func (e *statusError) Error() string {
+	if e.msg == "" {
+		return fmt.Sprintf("signer returned %d status", e.code)
+	}
+	return fmt.Sprintf("signer returned %d status: %s", e.code, e.msg)
+}
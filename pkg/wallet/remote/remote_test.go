@@ -0,0 +1,204 @@
+package remote_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"github.com/nspcc-dev/neo-go/pkg/wallet/remote"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSigner is an in-process HTTP signer with failure injection.
+type fakeSigner struct {
+	keys map[string]*keys.PrivateKey
+	// failures is the number of requests to fail with failStatus.
+	failures   int32
+	failStatus int32
+	// delay (in milliseconds) is applied to every request.
+	delay int32
+	// badSig makes signer return short signatures if non-zero.
+	badSig   int32
+	requests int32
+}
+
+func newFakeSigner(t *testing.T) (*fakeSigner, *httptest.Server) {
+	priv, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	f := &fakeSigner{
+		keys:       map[string]*keys.PrivateKey{"key1": priv},
+		failStatus: http.StatusInternalServerError,
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeSigner) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&f.requests, 1)
+	time.Sleep(time.Duration(atomic.LoadInt32(&f.delay)) * time.Millisecond)
+	if atomic.AddInt32(&f.failures, -1) >= 0 {
+		w.WriteHeader(int(atomic.LoadInt32(&f.failStatus)))
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "injected failure"})
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/keys":
+		var res []map[string]string
+		for id, k := range f.keys {
+			res = append(res, map[string]string{"id": id, "publickey": hex.EncodeToString(k.PublicKey().Bytes())})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": res})
+	case r.Method == http.MethodPost && r.URL.Path == "/sign":
+		var req struct {
+			ID     string `json:"id"`
+			Digest string `json:"digest"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		k, ok := f.keys[req.ID]
+		digest, err := hex.DecodeString(req.Digest)
+		if !ok || err != nil || len(digest) != 32 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "bad request"})
+			return
+		}
+		var d util.Uint256
+		copy(d[:], digest)
+		sig := k.SignHash(d)
+		if atomic.LoadInt32(&f.badSig) != 0 {
+			sig = sig[1:]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"signature": hex.EncodeToString(sig)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := remote.New("ftp://localhost", remote.Options{})
+	require.Error(t, err)
+	_, err = remote.New(":invalid", remote.Options{})
+	require.Error(t, err)
+	_, err = remote.New("http://localhost/", remote.Options{})
+	require.NoError(t, err)
+}
+
+func TestClient(t *testing.T) {
+	f, srv := newFakeSigner(t)
+	pub := f.keys["key1"].PublicKey()
+	c, err := remote.New(srv.URL+"/", remote.Options{Retries: 2, RetryDelay: time.Millisecond})
+	require.NoError(t, err)
+
+	ks, err := c.ListKeys(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []remote.Key{{ID: "key1", PublicKey: pub}}, ks)
+
+	digest := hash.Sha256([]byte("data"))
+	sig, err := c.SignDigest(context.Background(), "key1", digest)
+	require.NoError(t, err)
+	require.True(t, pub.Verify(sig, digest[:]))
+
+	t.Run("unknown key", func(t *testing.T) {
+		atomic.StoreInt32(&f.requests, 0)
+		_, err := c.SignDigest(context.Background(), "key2", digest)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bad request")
+		require.Equal(t, int32(1), atomic.LoadInt32(&f.requests)) // Not retried.
+	})
+	t.Run("retries", func(t *testing.T) {
+		atomic.StoreInt32(&f.requests, 0)
+		atomic.StoreInt32(&f.failures, 2)
+		sig, err := c.SignDigest(context.Background(), "key1", digest)
+		require.NoError(t, err)
+		require.True(t, pub.Verify(sig, digest[:]))
+		require.Equal(t, int32(3), atomic.LoadInt32(&f.requests))
+
+		atomic.StoreInt32(&f.requests, 0)
+		atomic.StoreInt32(&f.failures, 3)
+		_, err = c.ListKeys(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "injected failure")
+		require.Equal(t, int32(3), atomic.LoadInt32(&f.requests))
+		atomic.StoreInt32(&f.failures, 0)
+	})
+	t.Run("client error", func(t *testing.T) {
+		atomic.StoreInt32(&f.requests, 0)
+		atomic.StoreInt32(&f.failures, 1)
+		atomic.StoreInt32(&f.failStatus, http.StatusForbidden)
+		_, err := c.ListKeys(context.Background())
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&f.requests))
+		atomic.StoreInt32(&f.failStatus, http.StatusInternalServerError)
+		atomic.StoreInt32(&f.failures, 0)
+	})
+	t.Run("bad signature", func(t *testing.T) {
+		atomic.StoreInt32(&f.badSig, 1)
+		_, err := c.SignDigest(context.Background(), "key1", digest)
+		require.Error(t, err)
+		atomic.StoreInt32(&f.badSig, 0)
+	})
+	t.Run("timeout", func(t *testing.T) {
+		c, err := remote.New(srv.URL, remote.Options{Timeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+		atomic.StoreInt32(&f.delay, 100)
+		_, err = c.ListKeys(context.Background())
+		require.Error(t, err)
+		atomic.StoreInt32(&f.delay, 0)
+	})
+	t.Run("confirmation", func(t *testing.T) {
+		var confirm bool
+		c, err := remote.New(srv.URL, remote.Options{Confirm: func(keyID string, d util.Uint256) error {
+			require.Equal(t, "key1", keyID)
+			require.Equal(t, digest, d)
+			if !confirm {
+				return errors.New("no")
+			}
+			return nil
+		}})
+		require.NoError(t, err)
+		atomic.StoreInt32(&f.requests, 0)
+		_, err = c.SignDigest(context.Background(), "key1", digest)
+		require.ErrorIs(t, err, remote.ErrCancelled)
+		require.Equal(t, int32(0), atomic.LoadInt32(&f.requests))
+
+		confirm = true
+		_, err = c.SignDigest(context.Background(), "key1", digest)
+		require.NoError(t, err)
+	})
+}
+
+func TestAccount(t *testing.T) {
+	f, srv := newFakeSigner(t)
+	pub := f.keys["key1"].PublicKey()
+	acc := wallet.NewRemoteAccount(srv.URL, "key1", pub)
+	require.NoError(t, acc.Decrypt("", keys.NEP2ScryptParams()))
+	require.True(t, acc.CanSign())
+
+	tx := &transaction.Transaction{
+		Script:  []byte{1, 2, 3},
+		Signers: []transaction.Signer{{Account: acc.ScriptHash()}},
+	}
+	require.NoError(t, acc.SignTx(42, tx))
+	require.True(t, pub.VerifyHashable(tx.Scripts[0].InvocationScript[2:], 42, tx))
+
+	atomic.StoreInt32(&f.failures, 10)
+	require.Error(t, acc.SignTx(42, tx))
+	atomic.StoreInt32(&f.failures, 0)
+
+	acc = wallet.NewRemoteAccount(srv.URL, "key2", pub)
+	require.Error(t, acc.Decrypt("", keys.NEP2ScryptParams()))
+}
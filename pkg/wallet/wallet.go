@@ -3,8 +3,11 @@ package wallet
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
@@ -15,6 +18,11 @@ import (
 const (
 	// The current version of neo-go wallet implementations.
 	walletVersion = "1.0"
+
+	// FileMode is the permission mode of wallet files created by this
+	// package, wallets contain (encrypted) private keys, so they should
+	// only be accessible by their owner.
+	FileMode os.FileMode = 0600
 )
 
 // Wallet represents a NEO (NEP-2, NEP-6) compliant wallet.
@@ -42,13 +50,18 @@ type Extra struct {
 	Tokens []*Token
 }
 
-// NewWallet creates a new NEO wallet at the given location.
+// NewWallet creates a new NEO wallet at the given location. The file is
+// created (or truncated if it exists) with FileMode permissions.
 func NewWallet(location string) (*Wallet, error) {
-	file, err := os.Create(location)
+	file, err := os.OpenFile(location, os.O_RDWR|os.O_CREATE|os.O_TRUNC, FileMode)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
+	// Existing file retains its mode on open.
+	if err := file.Chmod(FileMode); err != nil {
+		return nil, err
+	}
 	return newWallet(file), nil
 }
 
@@ -160,7 +173,35 @@ func (w *Wallet) SavePretty() error {
 }
 
 func (w *Wallet) writeRaw(data []byte) error {
-	return os.WriteFile(w.path, data, 0644)
+	return os.WriteFile(w.path, data, FileMode)
+}
+
+// CheckPermissions checks the wallet file at the given path and its directory
+// permissions. It returns an error if the file is accessible by anyone except
+// its owner or the directory is writable by group or others (so the file can
+// be replaced). Such wallets can still be used, so the error is intended to
+// be shown as a warning. Permissions are not checked on Windows.
+func CheckPermissions(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if mode := fi.Mode().Perm(); mode&^FileMode != 0 {
+		return fmt.Errorf("wallet file %s is accessible by others (mode %#o), it should be %#o", path, mode, FileMode)
+	}
+	dir := filepath.Dir(path)
+	di, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	// Sticky directories (like /tmp) don't allow to replace others' files.
+	if mode := di.Mode(); mode&0022 != 0 && mode&os.ModeSticky == 0 {
+		return fmt.Errorf("wallet directory %s is writable by group or others (mode %#o)", dir, mode.Perm())
+	}
+	return nil
 }
 
 // JSON outputs a pretty JSON representation of the wallet.
@@ -2,8 +2,10 @@ package wallet
 
 import (
 	"encoding/json"
+	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
@@ -22,6 +24,35 @@ func TestNewWallet(t *testing.T) {
 	require.NotNil(t, wallet)
 }
 
+func TestCheckPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not checked on Windows")
+	}
+	dir := filepath.Join(t.TempDir(), "wallets")
+	require.NoError(t, os.Mkdir(dir, 0700))
+	file := filepath.Join(dir, walletTemplate)
+
+	require.NoError(t, os.WriteFile(file, []byte("{}"), 0644))
+	w, err := NewWallet(file)
+	require.NoError(t, err)
+	require.NoError(t, w.Save())
+	fi, err := os.Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, FileMode, fi.Mode().Perm())
+	require.NoError(t, CheckPermissions(file))
+
+	require.NoError(t, os.Chmod(file, 0640))
+	require.Error(t, CheckPermissions(file))
+	require.NoError(t, os.Chmod(file, FileMode))
+
+	require.NoError(t, os.Chmod(dir, 0777))
+	require.Error(t, CheckPermissions(file))
+	require.NoError(t, os.Chmod(dir, 0777|os.ModeSticky))
+	require.NoError(t, CheckPermissions(file))
+
+	require.Error(t, CheckPermissions(filepath.Join(dir, "unknown")))
+}
+
 func TestNewWalletFromFile_Negative_EmptyFile(t *testing.T) {
 	_ = checkWalletConstructor(t)
 	walletFromFile, err2 := NewWalletFromFile(walletTemplate)